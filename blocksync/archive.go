@@ -0,0 +1,259 @@
+package blocksync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cosmos/gogoproto/proto"
+
+	"github.com/cometbft/cometbft/p2p"
+	bcproto "github.com/cometbft/cometbft/proto/tendermint/blocksync"
+	rpchttp "github.com/cometbft/cometbft/rpc/client/http"
+	"github.com/cometbft/cometbft/types"
+)
+
+const (
+	// archivePeerID identifies the archive in the pool, as if it were a peer.
+	archivePeerID p2p.ID = "archive"
+
+	// archiveCheckInterval is how often to check whether the pool needs blocks
+	// which no peer has.
+	archiveCheckInterval = time.Second
+
+	// archiveRequestTimeout is the maximum time to load a block from the archive.
+	archiveRequestTimeout = 30 * time.Second
+
+	// Sources of archived blocks.
+	ArchiveSourceRPC         = "rpc"
+	ArchiveSourceObjectStore = "object_store"
+)
+
+// errBlockNotArchived is returned when the archive does not have a block.
+var errBlockNotArchived = errors.New("block not archived")
+
+// BlockArchive is a source of blocks outside of the p2p network, used when no
+// peer has the blocks the node needs, e.g. because they pruned them. The
+// blocks are untrusted, and verified like blocks received from peers.
+type BlockArchive interface {
+	// LoadBlock returns the block at the given height, and its extended commit
+	// if vote extensions were enabled at that height.
+	LoadBlock(ctx context.Context, height int64) (*types.Block, *types.ExtendedCommit, error)
+	// Height returns the height of the latest archived block.
+	Height(ctx context.Context) (int64, error)
+}
+
+// NewBlockArchive creates a block archive of the given source, at the given
+// URL. The source is either ArchiveSourceRPC, the RPC endpoint of an archival
+// node, or ArchiveSourceObjectStore, an HTTP object store holding the block of
+// each height as a protobuf-encoded BlockResponse under <url>/<height>, and the
+// height of the latest archived block, in decimal, under <url>/latest.
+func NewBlockArchive(source, url string) (BlockArchive, error) {
+	switch source {
+	case ArchiveSourceRPC:
+		client, err := rpchttp.New(url, "/websocket")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create RPC client: %w", err)
+		}
+		return &rpcArchive{client: client}, nil
+	case ArchiveSourceObjectStore:
+		return &objectStoreArchive{url: strings.TrimSuffix(url, "/"), client: &http.Client{}}, nil
+	default:
+		return nil, fmt.Errorf("unknown archive source %q", source)
+	}
+}
+
+// rpcArchive loads blocks from the RPC endpoint of an archival node. The RPC
+// does not serve extended commits, so it can only provide the blocks of the
+// heights at which vote extensions were disabled: the node refuses to use it
+// on chains which enable vote extensions.
+type rpcArchive struct {
+	client *rpchttp.HTTP
+}
+
+// LoadBlock implements BlockArchive.
+func (a *rpcArchive) LoadBlock(ctx context.Context, height int64) (*types.Block, *types.ExtendedCommit, error) {
+	res, err := a.client.Block(ctx, &height)
+	if err != nil {
+		return nil, nil, err
+	}
+	if res.Block == nil {
+		return nil, nil, errBlockNotArchived
+	}
+	return res.Block, nil, nil
+}
+
+// Height implements BlockArchive.
+func (a *rpcArchive) Height(ctx context.Context) (int64, error) {
+	res, err := a.client.Status(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return res.SyncInfo.LatestBlockHeight, nil
+}
+
+// objectStoreArchive loads blocks from an HTTP object store.
+type objectStoreArchive struct {
+	url    string
+	client *http.Client
+}
+
+// get returns the object at the given path, of up to maxSize bytes.
+func (a *objectStoreArchive) get(ctx context.Context, path string, maxSize int) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, a.url+"/"+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, errBlockNotArchived
+	default:
+		return nil, fmt.Errorf("unexpected status %q", resp.Status)
+	}
+
+	bz, err := io.ReadAll(io.LimitReader(resp.Body, int64(maxSize)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(bz) > maxSize {
+		return nil, fmt.Errorf("%s exceeds %d bytes", path, maxSize)
+	}
+	return bz, nil
+}
+
+// Height implements BlockArchive.
+func (a *objectStoreArchive) Height(ctx context.Context) (int64, error) {
+	bz, err := a.get(ctx, "latest", 20)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(strings.TrimSpace(string(bz)), 10, 64)
+}
+
+// LoadBlock implements BlockArchive.
+func (a *objectStoreArchive) LoadBlock(ctx context.Context, height int64) (*types.Block, *types.ExtendedCommit, error) {
+	bz, err := a.get(ctx, strconv.FormatInt(height, 10), MaxMsgSize)
+	if err != nil {
+		return nil, nil, err
+	}
+	var msg bcproto.BlockResponse
+	if err := proto.Unmarshal(bz, &msg); err != nil {
+		return nil, nil, err
+	}
+	block, err := types.BlockFromProto(msg.Block)
+	if err != nil {
+		return nil, nil, err
+	}
+	var extCommit *types.ExtendedCommit
+	if msg.ExtCommit != nil {
+		if extCommit, err = types.ExtendedCommitFromProto(msg.ExtCommit); err != nil {
+			return nil, nil, err
+		}
+	}
+	return block, extCommit, nil
+}
+
+// SetBlockArchive sets the archive to load blocks from, when no peer has had
+// the blocks at the pool's height for the given delay. It must be called
+// before the reactor is started.
+func (bcR *Reactor) SetBlockArchive(archive BlockArchive, delay time.Duration) {
+	bcR.archive = archive
+	bcR.archiveDelay = delay
+}
+
+// archiveRoutine adds the archive to the pool, as a peer with the blocks
+// between the pool's height and the lowest base of the peers, when no peer
+// has the block at the pool's height. If no peer has the blocks above the
+// pool's height, e.g. because every peer left, the archive is used up to its
+// own height. The archive is removed from the pool on the first block it
+// fails to provide, and added again when needed.
+func (bcR *Reactor) archiveRoutine() {
+	ticker := time.NewTicker(archiveCheckInterval)
+	defer ticker.Stop()
+
+	var stalledSince time.Time
+	for {
+		select {
+		case <-bcR.Quit():
+			return
+		case <-bcR.pool.Quit():
+			return
+		case <-ticker.C:
+		}
+
+		// The pool is caught up if the peers have no block above its height,
+		// and is stalled if there are no peers, once it had the time to
+		// connect to some.
+		height := bcR.pool.Height()
+		maxPeerHeight := bcR.pool.MaxPeerHeight()
+		if bcR.pool.hasPeer(archivePeerID) || len(bcR.pool.peersWithHeight(height)) > 0 ||
+			(maxPeerHeight > 0 && maxPeerHeight <= height) ||
+			(maxPeerHeight == 0 && time.Since(bcR.pool.startTime) < peerConnWait) {
+			stalledSince = time.Time{}
+			continue
+		}
+		if stalledSince.IsZero() {
+			stalledSince = time.Now()
+		}
+		if time.Since(stalledSince) < bcR.archiveDelay {
+			continue
+		}
+
+		// The peers may have left since the checks above, in which case there
+		// is no upper bound other than the archive's own height.
+		top := bcR.pool.nextPeerBase(height) - 1
+		if top < height {
+			var err error
+			if top, err = bcR.archiveHeight(); err != nil {
+				bcR.Logger.Error("Failed to get the height of the archive", "err", err)
+				continue
+			}
+			if top < height {
+				bcR.Logger.Debug("The archive has no blocks above the pool's height",
+					"height", height, "archiveHeight", top)
+				continue
+			}
+		}
+		bcR.Logger.Info("No peer has the blocks, loading them from the archive",
+			"from", height, "to", top)
+		bcR.pool.SetPeerRange(archivePeerID, height, top)
+		stalledSince = time.Time{}
+	}
+}
+
+// archiveHeight returns the height of the latest block in the archive.
+func (bcR *Reactor) archiveHeight() (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), archiveRequestTimeout)
+	defer cancel()
+	return bcR.archive.Height(ctx)
+}
+
+// loadArchivedBlock loads the block at the given height from the archive,
+// and adds it to the pool.
+func (bcR *Reactor) loadArchivedBlock(height int64) {
+	ctx, cancel := context.WithTimeout(context.Background(), archiveRequestTimeout)
+	defer cancel()
+
+	block, extCommit, err := bcR.archive.LoadBlock(ctx, height)
+	if err == nil && block.Height != height {
+		err = fmt.Errorf("got block #%d", block.Height)
+	}
+	if err == nil {
+		err = bcR.pool.AddBlock(archivePeerID, block, extCommit, block.Size())
+	}
+	if err != nil {
+		bcR.Logger.Error("Failed to load block from the archive", "height", height, "err", err)
+		bcR.pool.RemovePeer(archivePeerID)
+	}
+}
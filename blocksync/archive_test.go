@@ -0,0 +1,205 @@
+package blocksync
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cosmos/gogoproto/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cometbft/cometbft/internal/test"
+	cmtbytes "github.com/cometbft/cometbft/libs/bytes"
+	"github.com/cometbft/cometbft/libs/log"
+	"github.com/cometbft/cometbft/p2p"
+	bcproto "github.com/cometbft/cometbft/proto/tendermint/blocksync"
+	sm "github.com/cometbft/cometbft/state"
+	"github.com/cometbft/cometbft/types"
+)
+
+// testObjectStore serves the blocks of a block store, as an object store.
+type testObjectStore struct {
+	mtx       sync.Mutex
+	height    int64
+	responses map[int64][]byte
+	requested map[int64]bool
+}
+
+func newTestObjectStore(t *testing.T, blockStore sm.BlockStore) *testObjectStore {
+	s := &testObjectStore{
+		height:    blockStore.Height(),
+		responses: make(map[int64][]byte),
+		requested: make(map[int64]bool),
+	}
+	for h := blockStore.Base(); h <= blockStore.Height(); h++ {
+		block, err := blockStore.LoadBlock(h).ToProto()
+		require.NoError(t, err)
+		msg := &bcproto.BlockResponse{Block: block}
+		if extCommit := blockStore.LoadBlockExtendedCommit(h); extCommit != nil {
+			msg.ExtCommit = extCommit.ToProto()
+		}
+		s.responses[h], err = proto.Marshal(msg)
+		require.NoError(t, err)
+	}
+	return s
+}
+
+func (s *testObjectStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/blocks/latest" {
+		_, _ = w.Write([]byte(strconv.FormatInt(s.height, 10)))
+		return
+	}
+	height, err := strconv.ParseInt(strings.TrimPrefix(r.URL.Path, "/blocks/"), 10, 64)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.requested[height] = true
+	bz, ok := s.responses[height]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	_, _ = w.Write(bz)
+}
+
+func TestObjectStoreArchive(t *testing.T) {
+	config = test.ResetTestRoot("blocksync_reactor_test")
+	defer os.RemoveAll(config.RootDir)
+	genDoc, privVals := randGenesisDoc(1, false, 30)
+
+	source := newReactor(t, log.TestingLogger(), genDoc, privVals, 3)
+	defer func() {
+		require.NoError(t, source.app.Stop())
+	}()
+	server := httptest.NewServer(newTestObjectStore(t, source.reactor.store))
+	defer server.Close()
+
+	archive, err := NewBlockArchive(ArchiveSourceObjectStore, server.URL+"/blocks/")
+	require.NoError(t, err)
+
+	block, extCommit, err := archive.LoadBlock(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, source.reactor.store.LoadBlock(2).Hash(), block.Hash())
+	require.NotNil(t, extCommit)
+	assert.Equal(t, source.reactor.store.LoadBlockExtendedCommit(2).BlockID, extCommit.BlockID)
+
+	_, _, err = archive.LoadBlock(context.Background(), 4)
+	assert.ErrorIs(t, err, errBlockNotArchived)
+
+	height, err := archive.Height(context.Background())
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, height)
+
+	_, err = NewBlockArchive("invalid", server.URL)
+	assert.Error(t, err)
+}
+
+func TestArchiveFallback(t *testing.T) {
+	config = test.ResetTestRoot("blocksync_reactor_test")
+	defer os.RemoveAll(config.RootDir)
+	genDoc, privVals := randGenesisDoc(1, false, 30)
+
+	maxBlockHeight := int64(20)
+	prunedHeight := int64(10)
+
+	// The only peer pruned the blocks below prunedHeight, which are archived.
+	source := newReactor(t, log.TestingLogger(), genDoc, privVals, maxBlockHeight)
+	sourceStore := source.reactor.store
+	objectStore := newTestObjectStore(t, sourceStore)
+	hashes := make(map[int64]cmtbytes.HexBytes)
+	for h := int64(1); h <= maxBlockHeight; h++ {
+		hashes[h] = sourceStore.LoadBlock(h).Hash()
+	}
+	server := httptest.NewServer(objectStore)
+	defer server.Close()
+	_, _, err := sourceStore.PruneBlocks(prunedHeight, sm.State{})
+	require.NoError(t, err)
+	require.Equal(t, prunedHeight, sourceStore.Base())
+
+	syncing := newReactor(t, log.TestingLogger(), genDoc, privVals, 0)
+	archive, err := NewBlockArchive(ArchiveSourceObjectStore, server.URL+"/blocks")
+	require.NoError(t, err)
+	syncing.reactor.SetBlockArchive(archive, 0)
+
+	reactorPairs := []ReactorPair{source, syncing}
+	p2p.MakeConnectedSwitches(config.P2P, 2, func(i int, s *p2p.Switch) *p2p.Switch {
+		s.AddReactor("BLOCKSYNC", reactorPairs[i].reactor)
+		return s
+	}, p2p.Connect2Switches)
+	defer func() {
+		for _, r := range reactorPairs {
+			require.NoError(t, r.reactor.Stop())
+			require.NoError(t, r.app.Stop())
+		}
+	}()
+
+	require.Eventually(t, func() bool {
+		return syncing.reactor.pool.IsCaughtUp()
+	}, 30*time.Second, 10*time.Millisecond)
+
+	for h := int64(1); h < maxBlockHeight; h++ {
+		block := syncing.reactor.store.LoadBlock(h)
+		require.NotNil(t, block, h)
+		assert.Equal(t, hashes[h], block.Hash(), h)
+	}
+	objectStore.mtx.Lock()
+	defer objectStore.mtx.Unlock()
+	for h := int64(1); h < prunedHeight; h++ {
+		assert.True(t, objectStore.requested[h], h)
+	}
+	for h := prunedHeight; h <= maxBlockHeight; h++ {
+		assert.False(t, objectStore.requested[h], h)
+	}
+}
+
+// testArchive is an archive of the given height, which fails to load blocks.
+type testArchive struct {
+	height int64
+}
+
+func (a testArchive) LoadBlock(context.Context, int64) (*types.Block, *types.ExtendedCommit, error) {
+	return nil, nil, errBlockNotArchived
+}
+
+func (a testArchive) Height(context.Context) (int64, error) {
+	return a.height, nil
+}
+
+func TestArchiveRoutine_noPeers(t *testing.T) {
+	pool := NewBlockPool(5, make(chan BlockRequest, 1000), make(chan peerError, 1000))
+	pool.SetLogger(log.TestingLogger())
+	require.NoError(t, pool.Start())
+	t.Cleanup(func() {
+		if err := pool.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	// Every peer left, so no peer bounds the blocks to load from the archive.
+	pool.SetPeerRange("a", 1, 20)
+	pool.RemovePeer("a")
+
+	bcR := &Reactor{pool: pool}
+	bcR.BaseReactor = *p2p.NewBaseReactor("Reactor", bcR)
+	bcR.SetLogger(log.TestingLogger())
+	bcR.SetBlockArchive(testArchive{height: 15}, 0)
+	go bcR.archiveRoutine()
+
+	require.Eventually(t, func() bool {
+		return pool.hasPeer(archivePeerID)
+	}, peerConnWait+5*time.Second, 10*time.Millisecond)
+	pool.mtx.Lock()
+	defer pool.mtx.Unlock()
+	assert.EqualValues(t, 5, pool.peers[archivePeerID].base)
+	assert.EqualValues(t, 15, pool.peers[archivePeerID].height)
+}
@@ -0,0 +1,212 @@
+package blocksync
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cometbft/cometbft/p2p"
+	bcproto "github.com/cometbft/cometbft/proto/tendermint/blocksync"
+	sm "github.com/cometbft/cometbft/state"
+	"github.com/cometbft/cometbft/types"
+)
+
+const (
+	// backfillRequestTimeout is how long to wait for a peer to respond to a
+	// backfill block request, before requesting the block from another peer.
+	backfillRequestTimeout = 15 * time.Second
+	// backfillRetryInterval is how long to wait for peer status updates when
+	// no peer has the block to backfill.
+	backfillRetryInterval = time.Second
+	// backfillStallTimeout is how long the backfill may go without fetching a
+	// block before giving up.
+	backfillStallTimeout = time.Minute
+)
+
+var errBackfillStalled = errors.New("no peer provided the block to backfill")
+
+// backfillStore is implemented by block stores which can save blocks below
+// their base.
+type backfillStore interface {
+	SaveBackfilledBlock(block *types.Block, blockParts *types.PartSet, seenCommit *types.Commit) error
+}
+
+// backfillResponse is the response of a peer to a backfill block request.
+type backfillResponse struct {
+	peerID p2p.ID
+	height int64
+	block  *types.Block // nil if the peer does not have the block
+}
+
+// Backfill fetches the blocks below the height of the given state, restored
+// by state sync, from the peers and saves them in the block store, down to
+// depth blocks, so that the store has no gap below the snapshot height. Each
+// block is verified against the hash of the block ID of the block above it,
+// starting from the last block ID of the state, which was verified by the
+// light client. Extended commits are not backfilled.
+//
+// It must be called before SwitchToBlockSync.
+func (bcR *Reactor) Backfill(state sm.State, depth int64) error {
+	store, ok := bcR.store.(backfillStore)
+	if !ok {
+		return errors.New("block store does not support backfilling")
+	}
+	if depth <= 0 || state.LastBlockHeight == 0 {
+		return nil
+	}
+	seenCommit := bcR.store.LoadSeenCommit(state.LastBlockHeight)
+	if seenCommit == nil {
+		return fmt.Errorf("no seen commit for height %d", state.LastBlockHeight)
+	}
+
+	stopHeight := state.LastBlockHeight - depth + 1
+	if stopHeight < state.InitialHeight {
+		stopHeight = state.InitialHeight
+	}
+	bcR.Logger.Info("Backfilling blocks", "height", state.LastBlockHeight, "stop_height", stopHeight)
+
+	responses := make(chan backfillResponse, ReactorIncomingMessageQueueSize)
+	bcR.backfillMtx.Lock()
+	bcR.backfillCh = responses
+	bcR.backfillHeight = state.LastBlockHeight
+	bcR.backfillMtx.Unlock()
+	defer func() {
+		bcR.backfillMtx.Lock()
+		bcR.backfillCh = nil
+		bcR.backfillMtx.Unlock()
+	}()
+
+	blockID := state.LastBlockID
+	for height := state.LastBlockHeight; height >= stopHeight; height-- {
+		block, parts, err := bcR.backfillBlock(height, blockID, responses)
+		if err != nil {
+			return fmt.Errorf("failed to backfill block %d: %w", height, err)
+		}
+		if err := store.SaveBackfilledBlock(block, parts, seenCommit); err != nil {
+			return fmt.Errorf("failed to save backfilled block %d: %w", height, err)
+		}
+		seenCommit = block.LastCommit
+		blockID = block.LastBlockID
+	}
+	bcR.Logger.Info("Backfilled blocks", "base", stopHeight, "height", state.LastBlockHeight)
+	return nil
+}
+
+// backfillBlock requests the block at the given height, which must have the
+// given block ID, from each peer which has it in turn, until one provides it.
+func (bcR *Reactor) backfillBlock(
+	height int64,
+	blockID types.BlockID,
+	responses <-chan backfillResponse,
+) (*types.Block, *types.PartSet, error) {
+	deadline := time.Now().Add(backfillStallTimeout)
+	tried := make(map[p2p.ID]bool)
+	for time.Now().Before(deadline) {
+		var peer p2p.Peer
+		for _, peerID := range bcR.pool.peersWithHeight(height) {
+			if p := bcR.Switch.Peers().Get(peerID); p != nil && !tried[peerID] {
+				peer = p
+				break
+			}
+		}
+		if peer == nil {
+			// All peers with the block were tried, ask for status updates and
+			// try them again.
+			tried = make(map[p2p.ID]bool)
+			bcR.BroadcastStatusRequest()
+			select {
+			case <-time.After(backfillRetryInterval):
+				continue
+			case <-bcR.Quit():
+				return nil, nil, errors.New("reactor stopped")
+			}
+		}
+		tried[peer.ID()] = true
+
+		queued := peer.TrySend(p2p.Envelope{
+			ChannelID: BlocksyncChannel,
+			Message:   &bcproto.BlockRequest{Height: height},
+		})
+		if !queued {
+			continue
+		}
+
+		block, parts, err := bcR.awaitBackfillResponse(peer, height, blockID, responses)
+		if err != nil {
+			return nil, nil, err
+		}
+		if block != nil {
+			return block, parts, nil
+		}
+	}
+	return nil, nil, errBackfillStalled
+}
+
+// awaitBackfillResponse waits for the peer to respond to the request for the
+// block at the given height. It returns a nil block if the peer does not have
+// the block, sent an invalid block, or did not respond in time.
+func (bcR *Reactor) awaitBackfillResponse(
+	peer p2p.Peer,
+	height int64,
+	blockID types.BlockID,
+	responses <-chan backfillResponse,
+) (*types.Block, *types.PartSet, error) {
+	timer := time.NewTimer(backfillRequestTimeout)
+	defer timer.Stop()
+	for {
+		select {
+		case resp := <-responses:
+			if resp.peerID != peer.ID() || resp.height != height {
+				// A late response to a previous request.
+				continue
+			}
+			if resp.block == nil {
+				return nil, nil, nil
+			}
+			parts, err := verifyBackfilledBlock(resp.block, blockID)
+			if err != nil {
+				bcR.Logger.Error("Peer sent us invalid backfilled block", "peer", peer, "height", height, "err", err)
+				bcR.Switch.StopPeerForError(peer, ErrReactorValidation{Err: err}, bcR.String())
+				return nil, nil, nil
+			}
+			return resp.block, parts, nil
+		case <-timer.C:
+			bcR.Logger.Debug("Peer did not send backfilled block in time", "peer", peer, "height", height)
+			return nil, nil, nil
+		case <-bcR.Quit():
+			return nil, nil, errors.New("reactor stopped")
+		}
+	}
+}
+
+// verifyBackfilledBlock checks that the block is valid and has the given
+// block ID, returning its parts.
+func verifyBackfilledBlock(block *types.Block, blockID types.BlockID) (*types.PartSet, error) {
+	if err := block.ValidateBasic(); err != nil {
+		return nil, err
+	}
+	parts, err := block.MakePartSet(types.BlockPartSizeBytes)
+	if err != nil {
+		return nil, err
+	}
+	if id := (types.BlockID{Hash: block.Hash(), PartSetHeader: parts.Header()}); !id.Equals(blockID) {
+		return nil, fmt.Errorf("expected block %v, got %v", blockID, id)
+	}
+	return parts, nil
+}
+
+// routeBackfillResponse passes the response of a peer to the running
+// backfill, returning false if it is not a response to a backfill request.
+func (bcR *Reactor) routeBackfillResponse(resp backfillResponse) bool {
+	bcR.backfillMtx.Lock()
+	defer bcR.backfillMtx.Unlock()
+	if bcR.backfillCh == nil || resp.height > bcR.backfillHeight {
+		return false
+	}
+	select {
+	case bcR.backfillCh <- resp:
+	default:
+		bcR.Logger.Debug("Dropping backfill response", "peer", resp.peerID, "height", resp.height)
+	}
+	return true
+}
@@ -0,0 +1,102 @@
+package blocksync
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbm "github.com/cometbft/cometbft-db"
+
+	"github.com/cometbft/cometbft/internal/test"
+	"github.com/cometbft/cometbft/libs/log"
+	"github.com/cometbft/cometbft/p2p"
+	sm "github.com/cometbft/cometbft/state"
+	"github.com/cometbft/cometbft/store"
+	"github.com/cometbft/cometbft/types"
+)
+
+func TestBackfill(t *testing.T) {
+	config = test.ResetTestRoot("blocksync_reactor_test")
+	defer os.RemoveAll(config.RootDir)
+	genDoc, privVals := randGenesisDoc(1, false, 30)
+
+	maxBlockHeight := int64(20)
+	testcases := map[string]struct {
+		depth      int64
+		expectBase int64
+	}{
+		"partial": {10, maxBlockHeight - 9},
+		"genesis": {100, 1},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			source := newReactor(t, log.TestingLogger(), genDoc, privVals, maxBlockHeight)
+			defer func() {
+				require.NoError(t, source.app.Stop())
+			}()
+
+			// The state restored by state sync, with the commit of its height.
+			state := sm.State{
+				ChainID:         genDoc.ChainID,
+				InitialHeight:   genDoc.InitialHeight,
+				LastBlockHeight: maxBlockHeight,
+				LastBlockID:     source.reactor.store.LoadBlockMeta(maxBlockHeight).BlockID,
+			}
+			blockStore := store.NewBlockStore(dbm.NewMemDB())
+			require.NoError(t, blockStore.SaveSeenCommit(maxBlockHeight, source.reactor.store.LoadSeenCommit(maxBlockHeight)))
+
+			genState, err := sm.MakeGenesisState(genDoc)
+			require.NoError(t, err)
+			reactor := NewReactor(genState, nil, blockStore, false, NopMetrics(), 0)
+			reactor.SetLogger(log.TestingLogger())
+
+			switches := p2p.MakeConnectedSwitches(config.P2P, 2, func(i int, s *p2p.Switch) *p2p.Switch {
+				if i == 0 {
+					s.AddReactor("BLOCKSYNC", source.reactor)
+				} else {
+					s.AddReactor("BLOCKSYNC", reactor)
+				}
+				return s
+			}, p2p.Connect2Switches)
+			defer func() {
+				for _, s := range switches {
+					require.NoError(t, s.Stop())
+				}
+			}()
+
+			require.NoError(t, reactor.Backfill(state, tc.depth))
+			assert.Equal(t, tc.expectBase, blockStore.Base())
+			assert.Equal(t, maxBlockHeight, blockStore.Height())
+			for h := tc.expectBase; h <= maxBlockHeight; h++ {
+				block := blockStore.LoadBlock(h)
+				require.NotNil(t, block)
+				assert.Equal(t, source.reactor.store.LoadBlock(h).Hash(), block.Hash())
+				assert.NotNil(t, blockStore.LoadSeenCommit(h))
+			}
+			assert.Nil(t, blockStore.LoadBlock(tc.expectBase-1))
+		})
+	}
+}
+
+func TestVerifyBackfilledBlock(t *testing.T) {
+	state, err := sm.MakeGenesisState(&types.GenesisDoc{
+		ChainID:    test.DefaultTestChainID,
+		Validators: []types.GenesisValidator{{PubKey: types.NewMockPV().PrivKey.PubKey(), Power: 10}},
+	})
+	require.NoError(t, err)
+	block, parts, err := state.MakeBlock(1, types.MakeData(nil), new(types.Commit), nil, state.Validators.GetProposer().Address)
+	require.NoError(t, err)
+	blockID := types.BlockID{Hash: block.Hash(), PartSetHeader: parts.Header()}
+
+	_, err = verifyBackfilledBlock(block, blockID)
+	require.NoError(t, err)
+
+	_, err = verifyBackfilledBlock(block, types.BlockID{Hash: block.Hash()})
+	require.Error(t, err)
+
+	block.Txs = types.Txs{types.Tx("tx")}
+	_, err = verifyBackfilledBlock(block, blockID)
+	require.Error(t, err)
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 
 	"github.com/cosmos/gogoproto/proto"
+
+	cmtbytes "github.com/cometbft/cometbft/libs/bytes"
 )
 
 var (
@@ -51,3 +53,18 @@ func (e ErrReactorValidation) Error() string {
 func (e ErrReactorValidation) Unwrap() error {
 	return e.Err
 }
+
+// ErrConflictingHeader is returned when a quorum of witnesses has a different
+// header than the synced block at the same height, which indicates that either
+// the peers or the witnesses are on a fork, e.g. following a long-range attack.
+type ErrConflictingHeader struct {
+	Height      int64
+	Hash        cmtbytes.HexBytes
+	Witnesses   []string
+	WitnessHash cmtbytes.HexBytes
+}
+
+func (e ErrConflictingHeader) Error() string {
+	return fmt.Sprintf("witnesses %v have header %v at height %d, but the synced block is %v",
+		e.Witnesses, e.WitnessHash, e.Height, e.Hash)
+}
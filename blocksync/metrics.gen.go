@@ -44,6 +44,18 @@ func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
 			Name:      "latest_block_height",
 			Help:      "The height of the latest block.",
 		}, labels).With(labelsAndValues...),
+		RequestWindow: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "request_window",
+			Help:      "Number of blocks requested ahead of the latest block, as adapted to the throughput of peers and the block verification latency.",
+		}, labels).With(labelsAndValues...),
+		WitnessConflicts: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "witness_conflicts",
+			Help:      "Number of synced blocks whose header a quorum of witnesses contradicted, which halts block sync.",
+		}, labels).With(labelsAndValues...),
 	}
 }
 
@@ -54,5 +66,7 @@ func NopMetrics() *Metrics {
 		TotalTxs:          discard.NewGauge(),
 		BlockSizeBytes:    discard.NewGauge(),
 		LatestBlockHeight: discard.NewGauge(),
+		RequestWindow:     discard.NewGauge(),
+		WitnessConflicts:  discard.NewCounter(),
 	}
 }
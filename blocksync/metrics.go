@@ -26,11 +26,17 @@ type Metrics struct {
 	BlockSizeBytes metrics.Gauge
 	// The height of the latest block.
 	LatestBlockHeight metrics.Gauge
+	// Number of blocks requested ahead of the latest block, as adapted to the
+	// throughput of peers and the block verification latency.
+	RequestWindow metrics.Gauge
+	// Number of synced blocks whose header a quorum of witnesses contradicted,
+	// which halts block sync.
+	WitnessConflicts metrics.Counter
 }
 
 func (m *Metrics) recordBlockMetrics(block *types.Block) {
 	m.NumTxs.Set(float64(len(block.Data.Txs)))   //nolint:staticcheck
 	m.TotalTxs.Add(float64(len(block.Data.Txs))) //nolint:staticcheck
-	m.BlockSizeBytes.Set(float64(block.Size()))
+	m.BlockSizeBytes.Set(float64(block.ByteSize()))
 	m.LatestBlockHeight.Set(float64(block.Height))
 }
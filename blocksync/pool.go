@@ -30,9 +30,8 @@ eg, L = latency = 0.1s
 */
 
 const (
-	requestIntervalMS         = 2
-	maxPendingRequestsPerPeer = 20
-	requestRetrySeconds       = 45
+	requestIntervalMS   = 2
+	requestRetrySeconds = 45
 
 	// Minimum recv rate to ensure we're receiving blocks from a peer fast
 	// enough. If a peer is not sending us data at at least that rate, we
@@ -63,7 +62,7 @@ var peerTimeout = 120 * time.Second // not const so we can override with tests
 	Every so often we ask peers what height they're on so we can keep going.
 
 	Requests are continuously made for blocks of higher heights until
-	the limit is reached. If most of the requests have no available peers, and we
+	the request window is full (see window.go). If most of the requests have no available peers, and we
 	are not at peer limits, we can probably switch to consensus reactor
 */
 
@@ -82,6 +81,9 @@ type BlockPool struct {
 	bannedPeers   map[p2p.ID]time.Time
 	sortedPeers   []*bpPeer // sorted by curRate, highest first
 	maxPeerHeight int64     // the biggest reported height
+	// window bounds the number of requesters, and the pending requests of
+	// each peer.
+	window *requestWindow
 
 	// atomic
 	numPending int32 // number of requests pending assignment or block response
@@ -100,6 +102,7 @@ func NewBlockPool(start int64, requestsCh chan<- BlockRequest, errorsCh chan<- p
 		height:      start,
 		startHeight: start,
 		numPending:  0,
+		window:      newRequestWindow(),
 
 		requestsCh: requestsCh,
 		errorsCh:   errorsCh,
@@ -133,7 +136,7 @@ func (pool *BlockPool) makeRequestersRoutine() {
 
 		pool.mtx.Lock()
 		var (
-			maxRequestersCreated = len(pool.requesters) >= len(pool.peers)*maxPendingRequestsPerPeer
+			maxRequestersCreated = len(pool.requesters) >= pool.window.size
 
 			nextHeight           = pool.height + int64(len(pool.requesters))
 			maxPeerHeightReached = nextHeight > pool.maxPeerHeight
@@ -188,6 +191,27 @@ func (pool *BlockPool) removeTimedoutPeers() {
 	}
 
 	pool.sortPeers()
+	pool.window.update(pool.sortedPeers)
+}
+
+// recordVerification records the time taken by the reactor to verify and
+// apply a block, which bounds the request window, and resizes the window.
+// The window is thus re-evaluated for each block processed, instead of only
+// when the peers are checked for timeouts, which happens once the window is
+// full.
+func (pool *BlockPool) recordVerification(d time.Duration) {
+	pool.mtx.Lock()
+	defer pool.mtx.Unlock()
+	pool.window.addVerification(d)
+	pool.window.update(pool.sortedPeers)
+}
+
+// windowSize returns the number of blocks the pool may request ahead of its
+// height.
+func (pool *BlockPool) windowSize() int {
+	pool.mtx.Lock()
+	defer pool.mtx.Unlock()
+	return pool.window.size
 }
 
 // GetStatus returns pool's height, numPending requests and the number of
@@ -349,6 +373,7 @@ func (pool *BlockPool) AddBlock(peerID p2p.ID, block *types.Block, extCommit *ty
 	}
 
 	atomic.AddInt32(&pool.numPending, -1)
+	pool.window.addBlock(blockSize)
 	peer := pool.peers[peerID]
 	if peer != nil {
 		peer.decrPending(blockSize)
@@ -371,6 +396,44 @@ func (pool *BlockPool) MaxPeerHeight() int64 {
 	return pool.maxPeerHeight
 }
 
+// peersWithHeight returns the peers which are not banned and allegedly have the block at the
+// given height.
+func (pool *BlockPool) peersWithHeight(height int64) []p2p.ID {
+	pool.mtx.Lock()
+	defer pool.mtx.Unlock()
+
+	var peers []p2p.ID
+	for _, peer := range pool.sortedPeers {
+		if peer.base <= height && height <= peer.height && !pool.isPeerBanned(peer.id) {
+			peers = append(peers, peer.id)
+		}
+	}
+	return peers
+}
+
+// nextPeerBase returns the lowest base of the peers which allegedly have the
+// blocks above the given height, 0 if there are none.
+func (pool *BlockPool) nextPeerBase(height int64) int64 {
+	pool.mtx.Lock()
+	defer pool.mtx.Unlock()
+
+	var base int64
+	for _, peer := range pool.peers {
+		if peer.height > height && (base == 0 || peer.base < base) {
+			base = peer.base
+		}
+	}
+	return base
+}
+
+// hasPeer returns true if the peer is in the pool.
+func (pool *BlockPool) hasPeer(peerID p2p.ID) bool {
+	pool.mtx.Lock()
+	defer pool.mtx.Unlock()
+	_, ok := pool.peers[peerID]
+	return ok
+}
+
 // SetPeerRange sets the peer's alleged blockchain base and height.
 func (pool *BlockPool) SetPeerRange(peerID p2p.ID, base int64, height int64) {
 	pool.mtx.Lock()
@@ -401,6 +464,7 @@ func (pool *BlockPool) SetPeerRange(peerID p2p.ID, base int64, height int64) {
 		// no need to sort because curRate is 0 at start.
 		// just add to the beginning so it's picked first by pickIncrAvailablePeer.
 		pool.sortedPeers = append([]*bpPeer{peer}, pool.sortedPeers...)
+		pool.window.update(pool.sortedPeers)
 	}
 
 	if height > pool.maxPeerHeight {
@@ -491,7 +555,7 @@ func (pool *BlockPool) pickIncrAvailablePeer(height int64, excludePeerID p2p.ID)
 			pool.removePeer(peer.id)
 			continue
 		}
-		if peer.numPending >= maxPendingRequestsPerPeer {
+		if peer.numPending >= peer.maxPending {
 			continue
 		}
 		if height < peer.base || height > peer.height {
@@ -568,6 +632,7 @@ type bpPeer struct {
 	didTimeout  bool
 	curRate     int64
 	numPending  int32
+	maxPending  int32 // set by the request window
 	height      int64
 	base        int64
 	pool        *BlockPool
@@ -586,6 +651,7 @@ func newBPPeer(pool *BlockPool, peerID p2p.ID, base int64, height int64) *bpPeer
 		base:       base,
 		height:     height,
 		numPending: 0,
+		maxPending: minPendingRequestsPerPeer,
 		logger:     log.NewNopLogger(),
 	}
 	return peer
@@ -595,6 +661,15 @@ func (peer *bpPeer) setLogger(l log.Logger) {
 	peer.logger = l
 }
 
+// throughput returns the measured recv rate of the peer, in bytes/s. Peers
+// which were not measured yet are assumed to send at the minimum rate.
+func (peer *bpPeer) throughput() float64 {
+	if peer.curRate < minRecvRate {
+		return minRecvRate
+	}
+	return float64(peer.curRate)
+}
+
 func (peer *bpPeer) resetMonitor() {
 	peer.recvMonitor = flow.New(time.Second, time.Second*40)
 	initialValue := float64(minRecvRate) * math.E
@@ -4,10 +4,13 @@ import (
 	"fmt"
 	"reflect"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/cometbft/cometbft/crypto"
 	"github.com/cometbft/cometbft/libs/log"
+	cmtsync "github.com/cometbft/cometbft/libs/sync"
+	"github.com/cometbft/cometbft/light/provider"
 	"github.com/cometbft/cometbft/p2p"
 	bcproto "github.com/cometbft/cometbft/proto/tendermint/blocksync"
 	sm "github.com/cometbft/cometbft/state"
@@ -40,6 +43,12 @@ type consensusReactor interface {
 	SwitchToConsensus(state sm.State, skipWAL bool)
 }
 
+type mempoolReactor interface {
+	// for when we switch from block sync to the consensus machine, once the
+	// node caught up
+	SwitchToConsensus()
+}
+
 type peerError struct {
 	err    error
 	peerID p2p.ID
@@ -68,6 +77,22 @@ type Reactor struct {
 
 	switchToConsensusMs int
 
+	// the running backfill, if any, see Backfill
+	backfillMtx    cmtsync.Mutex
+	backfillCh     chan backfillResponse
+	backfillHeight int64
+
+	// the source of the blocks which no peer has, if any, see SetBlockArchive
+	archive      BlockArchive
+	archiveDelay time.Duration
+
+	// the light client witnesses to cross-check synced blocks, see SetWitnesses
+	witnesses         []provider.Provider
+	witnessSampleRate float64
+	witnessChecks     chan *types.Block // blocks to check, see witnessRoutine
+	witnessPending    atomic.Int64      // blocks queued or being checked
+	witnessConflicts  chan error        // conflict halting block sync
+
 	metrics *Metrics
 }
 
@@ -285,6 +310,9 @@ func (bcR *Reactor) Receive(e p2p.Envelope) {
 			}
 		}
 
+		if bcR.routeBackfillResponse(backfillResponse{peerID: e.Src.ID(), height: bi.Height, block: bi}) {
+			return
+		}
 		if err := bcR.pool.AddBlock(e.Src.ID(), bi, extCommit, msg.Block.Size()); err != nil {
 			bcR.Logger.Error("failed to add block", "peer", e.Src, "err", err)
 		}
@@ -302,6 +330,9 @@ func (bcR *Reactor) Receive(e p2p.Envelope) {
 		bcR.pool.SetPeerRange(e.Src.ID(), msg.Base, msg.Height)
 	case *bcproto.NoBlockResponse:
 		bcR.Logger.Debug("Peer does not have requested block", "peer", e.Src, "height", msg.Height)
+		if bcR.routeBackfillResponse(backfillResponse{peerID: e.Src.ID(), height: msg.Height}) {
+			return
+		}
 		bcR.pool.RedoRequestFrom(msg.Height, e.Src.ID())
 	default:
 		bcR.Logger.Error(fmt.Sprintf("Unknown message type %v", reflect.TypeOf(msg)))
@@ -347,6 +378,13 @@ func (bcR *Reactor) poolRoutine(stateSynced bool) {
 
 	initialCommitHasExtensions := (bcR.initialState.LastBlockHeight > 0 && bcR.store.LoadBlockExtendedCommit(bcR.initialState.LastBlockHeight) != nil)
 
+	if bcR.archive != nil {
+		go bcR.archiveRoutine()
+	}
+	if len(bcR.witnesses) > 0 {
+		go bcR.witnessRoutine()
+	}
+
 	go func() {
 		for {
 			select {
@@ -355,6 +393,10 @@ func (bcR *Reactor) poolRoutine(stateSynced bool) {
 			case <-bcR.pool.Quit():
 				return
 			case request := <-bcR.requestsCh:
+				if request.PeerID == archivePeerID {
+					go bcR.loadArchivedBlock(request.Height)
+					continue
+				}
 				peer := bcR.Switch.Peers().Get(request.PeerID)
 				if peer == nil {
 					continue
@@ -425,6 +467,12 @@ FOR_LOOP:
 				)
 				continue FOR_LOOP
 			}
+			// Don't switch to consensus until the synced blocks are cross-checked.
+			if pending := bcR.witnessPending.Load(); pending > 0 {
+				bcR.Logger.Info("Waiting for the witness checks before switching to consensus",
+					"height", height, "pending", pending)
+				continue FOR_LOOP
+			}
 			if bcR.pool.IsCaughtUp() || bcR.localNodeBlocksTheChain(state) {
 				bcR.Logger.Info("Time to switch to consensus reactor!", "height", height)
 				if err := bcR.pool.Stop(); err != nil {
@@ -434,6 +482,9 @@ FOR_LOOP:
 				if ok {
 					conR.SwitchToConsensus(state, blocksSynced > 0 || stateSynced)
 				}
+				if memR, ok := bcR.Switch.Reactor("MEMPOOL").(mempoolReactor); ok {
+					memR.SwitchToConsensus()
+				}
 				// else {
 				// should only happen during testing
 				// }
@@ -483,6 +534,7 @@ FOR_LOOP:
 			// Try again quickly next loop.
 			didProcessCh <- struct{}{}
 
+			verifyStart := time.Now()
 			firstParts, err := first.MakePartSet(types.BlockPartSizeBytes)
 			if err != nil {
 				bcR.Logger.Error("failed to make ",
@@ -551,6 +603,13 @@ FOR_LOOP:
 				continue FOR_LOOP
 			}
 
+			// Cross-check a sample of the blocks against the witnesses, to
+			// detect that the peers follow a fork, e.g. in a long-range attack.
+			// The checks run in the background, see witnessRoutine.
+			if bcR.sampleWitnessCheck() {
+				bcR.queueWitnessCheck(first)
+			}
+
 			bcR.pool.PopRequest()
 
 			// TODO: batch saves so we dont persist to disk every block
@@ -571,7 +630,10 @@ FOR_LOOP:
 				// TODO This is bad, are we zombie?
 				panic(fmt.Sprintf("Failed to process committed block (%d:%X): %v", first.Height, first.Hash(), err))
 			}
+			// The verification latency bounds how far ahead the pool requests blocks.
+			bcR.pool.recordVerification(time.Since(verifyStart))
 			bcR.metrics.recordBlockMetrics(first)
+			bcR.metrics.RequestWindow.Set(float64(bcR.pool.windowSize()))
 			blocksSynced++
 
 			if blocksSynced%100 == 0 {
@@ -583,6 +645,17 @@ FOR_LOOP:
 
 			continue FOR_LOOP
 
+		case err := <-bcR.witnessConflicts:
+			// The node must not join consensus on a fork, so block sync halts
+			// for good, until the operator investigates.
+			bcR.Logger.Error("Witnesses have a conflicting header, halting block sync without "+
+				"switching to consensus", "err", err)
+			bcR.metrics.WitnessConflicts.Add(1)
+			if err := bcR.pool.Stop(); err != nil {
+				bcR.Logger.Error("Error stopping pool", "err", err)
+			}
+			break FOR_LOOP
+
 		case <-bcR.Quit():
 			break FOR_LOOP
 		case <-bcR.pool.Quit():
@@ -554,6 +554,9 @@ func (bcR *ByzantineReactor) Receive(e p2p.Envelope) {
 			}
 		}
 
+		if bcR.routeBackfillResponse(backfillResponse{peerID: e.Src.ID(), height: bi.Height, block: bi}) {
+			return
+		}
 		if err := bcR.pool.AddBlock(e.Src.ID(), bi, extCommit, msg.Block.Size()); err != nil {
 			bcR.Logger.Error("failed to add block", "peer", e.Src, "err", err)
 		}
@@ -571,6 +574,9 @@ func (bcR *ByzantineReactor) Receive(e p2p.Envelope) {
 		bcR.pool.SetPeerRange(e.Src.ID(), msg.Base, msg.Height)
 	case *bcproto.NoBlockResponse:
 		bcR.Logger.Debug("Peer does not have requested block", "peer", e.Src, "height", msg.Height)
+		if bcR.routeBackfillResponse(backfillResponse{peerID: e.Src.ID(), height: msg.Height}) {
+			return
+		}
 		bcR.pool.RedoRequestFrom(msg.Height, e.Src.ID())
 	default:
 		bcR.Logger.Error(fmt.Sprintf("Unknown message type %v", reflect.TypeOf(msg)))
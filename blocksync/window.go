@@ -0,0 +1,116 @@
+package blocksync
+
+import (
+	"math"
+	"time"
+)
+
+/*
+The pool requests blocks ahead of its height within a window, whose size
+adapts to how fast blocks are downloaded and verified:
+
+	R  = throughput of the peers, in bytes/s
+	BS = average block size, in bytes
+	V  = average verification latency, in s
+	H  = windowHorizon
+
+	window = min(R/BS, 1/V) * H blocks
+
+A window larger than the blocks in flight lets the peers send faster, which
+raises their measured throughput and in turn the window, until either the
+link or the verification is saturated. The window also bounds the memory
+taken by the blocks received but not yet verified to maxWindowBytes.
+
+Each peer is assigned a share of the window proportional to its throughput.
+*/
+
+const (
+	// windowHorizon is how long the blocks in the window should last, at the
+	// rate they are downloaded and verified.
+	windowHorizon = 10 * time.Second
+
+	// Bounds of the window, in blocks.
+	minWindowSize = 10
+	maxWindowSize = 1000
+
+	// maxWindowBytes bounds the memory taken by the blocks in the window.
+	maxWindowBytes = 1 << 30 // 1 GB
+
+	// Bounds of the requests pending for a single peer.
+	minPendingRequestsPerPeer = 2
+	maxPendingRequestsPerPeer = 100
+
+	// initialBlockSize is the assumed block size until a block is received.
+	initialBlockSize = 1 << 20 // 1 MB
+
+	// windowSmoothing is the weight of a new sample in the moving averages of
+	// the block size and verification latency.
+	windowSmoothing = 0.1
+)
+
+// requestWindow tracks the average block size and verification latency to
+// size the window of blocks requested ahead of the pool's height.
+type requestWindow struct {
+	blockSize  float64 // bytes, 0 until a block is received
+	verifyTime float64 // seconds, 0 until a block is verified
+	size       int
+}
+
+func newRequestWindow() *requestWindow {
+	return &requestWindow{size: minWindowSize}
+}
+
+// addBlock records the size of a received block.
+func (w *requestWindow) addBlock(size int) {
+	w.blockSize = movingAverage(w.blockSize, float64(size))
+}
+
+// addVerification records the time taken to verify a block.
+func (w *requestWindow) addVerification(d time.Duration) {
+	w.verifyTime = movingAverage(w.verifyTime, d.Seconds())
+}
+
+// update resizes the window for the throughput of the given peers, and sets
+// the maximum number of requests pending for each of them.
+func (w *requestWindow) update(peers []*bpPeer) {
+	blockSize := w.blockSize
+	if blockSize == 0 {
+		blockSize = initialBlockSize
+	}
+
+	var throughput float64
+	for _, peer := range peers {
+		throughput += peer.throughput()
+	}
+
+	rate := throughput / blockSize // blocks/s
+	if w.verifyTime > 0 {
+		rate = math.Min(rate, 1/w.verifyTime)
+	}
+	size := rate * windowHorizon.Seconds()
+	size = math.Min(size, maxWindowBytes/blockSize)
+	w.size = clampInt(int(math.Ceil(size)), minWindowSize, maxWindowSize)
+
+	for _, peer := range peers {
+		share := float64(w.size) * peer.throughput() / throughput
+		peer.maxPending = int32(clampInt(int(math.Ceil(share)),
+			minPendingRequestsPerPeer, maxPendingRequestsPerPeer))
+	}
+}
+
+func movingAverage(avg, sample float64) float64 {
+	if avg == 0 {
+		return sample
+	}
+	return (1-windowSmoothing)*avg + windowSmoothing*sample
+}
+
+func clampInt(v, lower, upper int) int {
+	if v < lower {
+		return lower
+	}
+	if v > upper {
+		return upper
+	}
+	return v
+}
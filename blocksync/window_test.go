@@ -0,0 +1,105 @@
+package blocksync
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cometbft/cometbft/p2p"
+)
+
+func TestRequestWindow(t *testing.T) {
+	const mb = 1 << 20
+	fast := &bpPeer{id: "fast", curRate: 30 * mb}
+	slow := &bpPeer{id: "slow", curRate: 10 * mb}
+	idle := &bpPeer{id: "idle"}
+	link := &bpPeer{id: "link", curRate: 1000 * mb}
+
+	testCases := []struct {
+		name       string
+		blockSize  int
+		verifyTime time.Duration
+		peers      []*bpPeer
+		size       int
+		maxPending []int32
+	}{
+		{"no peers", 0, 0, nil, minWindowSize, nil},
+		// 40 MB/s of 8 MB blocks is 5 blocks/s, i.e. 50 blocks in 10s.
+		{"download bound", 8 * mb, 0, []*bpPeer{fast, slow}, 50, []int32{38, 13}},
+		// Verifying a block takes 0.5s, i.e. 20 blocks in 10s.
+		{"verification bound", 8 * mb, 500 * time.Millisecond, []*bpPeer{fast, slow}, 20, []int32{15, 5}},
+		// 1 GB of 64 MB blocks.
+		{"memory bound", 64 * mb, 0, []*bpPeer{link}, 16, []int32{16}},
+		{"small blocks", 1024, 0, []*bpPeer{fast, slow}, maxWindowSize, []int32{maxPendingRequestsPerPeer, maxPendingRequestsPerPeer}},
+		{"unmeasured peer", 1024, time.Second, []*bpPeer{idle}, minWindowSize, []int32{minWindowSize}},
+		{"initial block size", 0, 0, []*bpPeer{fast, slow}, 400, []int32{maxPendingRequestsPerPeer, maxPendingRequestsPerPeer}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := newRequestWindow()
+			if tc.blockSize > 0 {
+				w.addBlock(tc.blockSize)
+			}
+			if tc.verifyTime > 0 {
+				w.addVerification(tc.verifyTime)
+			}
+			w.update(tc.peers)
+			assert.Equal(t, tc.size, w.size)
+			for i, peer := range tc.peers {
+				assert.Equal(t, tc.maxPending[i], peer.maxPending, peer.id)
+			}
+		})
+	}
+}
+
+func TestRequestWindow_movingAverage(t *testing.T) {
+	w := newRequestWindow()
+	w.addBlock(100)
+	assert.EqualValues(t, 100, w.blockSize)
+	w.addBlock(200)
+	assert.InDelta(t, 110, w.blockSize, 1e-9)
+
+	w.addVerification(time.Second)
+	w.addVerification(2 * time.Second)
+	assert.InDelta(t, 1.1, w.verifyTime, 1e-9)
+}
+
+func TestBlockPool_recordVerification(t *testing.T) {
+	const mb = 1 << 20
+	pool := NewBlockPool(1, make(chan BlockRequest), make(chan peerError))
+	pool.SetPeerRange("a", 1, 1000)
+	pool.SetPeerRange("b", 1, 1000)
+	pool.mtx.Lock()
+	pool.peers["a"].curRate = 30 * mb
+	pool.peers["b"].curRate = 10 * mb
+	pool.window.addBlock(8 * mb)
+	pool.mtx.Unlock()
+
+	// The window follows the verification latency as each block is processed,
+	// without waiting for the peers to be checked for timeouts.
+	pool.recordVerification(500 * time.Millisecond)
+	assert.Equal(t, 20, pool.windowSize())
+	for i := 0; i < 100; i++ {
+		pool.recordVerification(10 * time.Millisecond)
+	}
+	// 40 MB/s of 8 MB blocks is 5 blocks/s, i.e. 50 blocks in 10s.
+	assert.Equal(t, 50, pool.windowSize())
+}
+
+func BenchmarkBlockPool_recordVerification(b *testing.B) {
+	pool := NewBlockPool(1, make(chan BlockRequest), make(chan peerError))
+	for i := 0; i < 100; i++ {
+		peerID := p2p.ID(fmt.Sprintf("peer%d", i))
+		pool.SetPeerRange(peerID, 1, 1000)
+		pool.peers[peerID].curRate = int64(i+1) << 20
+	}
+	pool.window.addBlock(1 << 20)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		pool.recordVerification(time.Millisecond)
+	}
+}
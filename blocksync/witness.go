@@ -0,0 +1,137 @@
+package blocksync
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	cmtbytes "github.com/cometbft/cometbft/libs/bytes"
+	cmtrand "github.com/cometbft/cometbft/libs/rand"
+	"github.com/cometbft/cometbft/light/provider"
+	"github.com/cometbft/cometbft/types"
+)
+
+const (
+	// witnessTimeout is the maximum time to fetch a header from a witness.
+	witnessTimeout = 10 * time.Second
+
+	// witnessQueueSize is the number of synced blocks waiting to be
+	// cross-checked against the witnesses, beyond which the checks are
+	// skipped rather than slowing down block sync.
+	witnessQueueSize = 100
+)
+
+// SetWitnesses sets the light client witnesses to cross-check the headers of
+// the synced blocks against, for the given fraction of the blocks. It must be
+// called before the reactor is started.
+func (bcR *Reactor) SetWitnesses(witnesses []provider.Provider, sampleRate float64) {
+	bcR.witnesses = witnesses
+	bcR.witnessSampleRate = sampleRate
+	bcR.witnessChecks = make(chan *types.Block, witnessQueueSize)
+	bcR.witnessConflicts = make(chan error, 1)
+}
+
+// sampleWitnessCheck returns true if the next synced block should be
+// cross-checked against the witnesses.
+func (bcR *Reactor) sampleWitnessCheck() bool {
+	return len(bcR.witnesses) > 0 && cmtrand.Float64() < bcR.witnessSampleRate
+}
+
+// queueWitnessCheck queues the block to be cross-checked against the
+// witnesses by witnessRoutine. The check is skipped if the queue is full.
+func (bcR *Reactor) queueWitnessCheck(block *types.Block) {
+	bcR.witnessPending.Add(1)
+	select {
+	case bcR.witnessChecks <- block:
+	default:
+		bcR.witnessPending.Add(-1)
+		bcR.Logger.Info("Witness check queue is full, skipping the check", "height", block.Height)
+	}
+}
+
+// witnessRoutine cross-checks the queued blocks against the witnesses, until
+// the reactor or the pool is stopped. On the first conflicting header, it
+// reports the conflict to the pool routine, which halts block sync.
+func (bcR *Reactor) witnessRoutine() {
+	for {
+		select {
+		case <-bcR.Quit():
+			return
+		case <-bcR.pool.Quit():
+			return
+		case block := <-bcR.witnessChecks:
+			err := bcR.checkWitnesses(block)
+			bcR.witnessPending.Add(-1)
+			if err != nil {
+				bcR.witnessConflicts <- err
+				return
+			}
+		}
+	}
+}
+
+// witnessQuorum returns the number of witnesses which must agree on a
+// conflicting header to halt block sync: more than half of them, so that a
+// single faulty witness can't halt it.
+func (bcR *Reactor) witnessQuorum() int {
+	return len(bcR.witnesses)/2 + 1
+}
+
+// witnessResponse is the hash of the header of a witness, or the error
+// fetching it.
+type witnessResponse struct {
+	witness provider.Provider
+	hash    cmtbytes.HexBytes
+	err     error
+}
+
+// checkWitnesses cross-checks the header of the block against the witnesses,
+// which are queried concurrently. It returns ErrConflictingHeader if a quorum
+// of witnesses has the same header at that height, different from the block's
+// one. Conflicting headers without a quorum are only logged, as are the
+// witnesses which fail to respond.
+func (bcR *Reactor) checkWitnesses(block *types.Block) error {
+	hash := block.Hash()
+	responses := make(chan witnessResponse, len(bcR.witnesses))
+	for _, witness := range bcR.witnesses {
+		go func(witness provider.Provider) {
+			ctx, cancel := context.WithTimeout(context.Background(), witnessTimeout)
+			defer cancel()
+			lb, err := witness.LightBlock(ctx, block.Height)
+			if err != nil {
+				responses <- witnessResponse{witness: witness, err: err}
+				return
+			}
+			responses <- witnessResponse{witness: witness, hash: lb.Hash()}
+		}(witness)
+	}
+
+	// the witnesses with each conflicting header, by hash
+	conflicts := make(map[string][]string)
+	for range bcR.witnesses {
+		resp := <-responses
+		switch {
+		case resp.err != nil:
+			bcR.Logger.Info("Failed to fetch header from witness", "witness", resp.witness,
+				"height", block.Height, "err", resp.err)
+		case !bytes.Equal(resp.hash, hash):
+			bcR.Logger.Error("Witness has a conflicting header", "witness", resp.witness,
+				"height", block.Height, "hash", hash, "witnessHash", resp.hash)
+			key := string(resp.hash)
+			conflicts[key] = append(conflicts[key], fmt.Sprint(resp.witness))
+		}
+	}
+
+	for witnessHash, witnesses := range conflicts {
+		if len(witnesses) >= bcR.witnessQuorum() {
+			return ErrConflictingHeader{
+				Height:      block.Height,
+				Hash:        hash,
+				Witnesses:   witnesses,
+				WitnessHash: []byte(witnessHash),
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package blocksync
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cometbft/cometbft/internal/test"
+	"github.com/cometbft/cometbft/libs/log"
+	"github.com/cometbft/cometbft/light/provider"
+	"github.com/cometbft/cometbft/p2p"
+	sm "github.com/cometbft/cometbft/state"
+	"github.com/cometbft/cometbft/types"
+)
+
+// testWitness serves the headers of a block store, with a conflicting header
+// at the given height, if any.
+type testWitness struct {
+	store          sm.BlockStore
+	conflictHeight int64
+	err            error
+}
+
+func (w *testWitness) ChainID() string { return test.DefaultTestChainID }
+
+func (w *testWitness) LightBlock(_ context.Context, height int64) (*types.LightBlock, error) {
+	if w.err != nil {
+		return nil, w.err
+	}
+	block := w.store.LoadBlock(height)
+	if block == nil {
+		return nil, provider.ErrLightBlockNotFound
+	}
+	header := block.Header
+	if height == w.conflictHeight {
+		header.AppHash = []byte("fork")
+	}
+	return &types.LightBlock{SignedHeader: &types.SignedHeader{Header: &header}}, nil
+}
+
+func (w *testWitness) ReportEvidence(context.Context, types.Evidence) error { return nil }
+
+func TestCheckWitnesses(t *testing.T) {
+	config = test.ResetTestRoot("blocksync_reactor_test")
+	defer os.RemoveAll(config.RootDir)
+	genDoc, privVals := randGenesisDoc(1, false, 30)
+
+	source := newReactor(t, log.TestingLogger(), genDoc, privVals, 3)
+	defer func() {
+		require.NoError(t, source.app.Stop())
+	}()
+	block := source.reactor.store.LoadBlock(2)
+
+	testcases := map[string]struct {
+		witnesses []provider.Provider
+		expectErr bool
+	}{
+		"same header": {[]provider.Provider{&testWitness{store: source.reactor.store}}, false},
+		"conflicting header": {[]provider.Provider{
+			&testWitness{store: source.reactor.store, conflictHeight: 2},
+		}, true},
+		"conflicting header without quorum": {[]provider.Provider{
+			&testWitness{store: source.reactor.store},
+			&testWitness{store: source.reactor.store, conflictHeight: 2},
+		}, false},
+		"conflicting header with quorum": {[]provider.Provider{
+			&testWitness{store: source.reactor.store},
+			&testWitness{store: source.reactor.store, conflictHeight: 2},
+			&testWitness{store: source.reactor.store, conflictHeight: 2},
+		}, true},
+		"unavailable witnesses without quorum": {[]provider.Provider{
+			&testWitness{err: errors.New("unavailable")},
+			&testWitness{err: errors.New("unavailable")},
+			&testWitness{store: source.reactor.store, conflictHeight: 2},
+		}, false},
+		"unavailable witness": {[]provider.Provider{
+			&testWitness{err: errors.New("unavailable")},
+			&testWitness{store: source.reactor.store},
+		}, false},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			source.reactor.SetWitnesses(tc.witnesses, 1)
+			err := source.reactor.checkWitnesses(block)
+			if tc.expectErr {
+				var conflict ErrConflictingHeader
+				require.ErrorAs(t, err, &conflict)
+				assert.EqualValues(t, 2, conflict.Height)
+				assert.Equal(t, block.Hash(), conflict.Hash)
+				assert.GreaterOrEqual(t, len(conflict.Witnesses), source.reactor.witnessQuorum())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestWitnessConflictHaltsBlockSync(t *testing.T) {
+	config = test.ResetTestRoot("blocksync_reactor_test")
+	defer os.RemoveAll(config.RootDir)
+	genDoc, privVals := randGenesisDoc(1, false, 30)
+
+	maxBlockHeight := int64(20)
+	conflictHeight := int64(5)
+
+	source := newReactor(t, log.TestingLogger(), genDoc, privVals, maxBlockHeight)
+	syncing := newReactor(t, log.TestingLogger(), genDoc, privVals, 0)
+	syncing.reactor.SetWitnesses([]provider.Provider{
+		&testWitness{store: source.reactor.store, conflictHeight: conflictHeight},
+	}, 1)
+
+	reactorPairs := []ReactorPair{source, syncing}
+	p2p.MakeConnectedSwitches(config.P2P, 2, func(i int, s *p2p.Switch) *p2p.Switch {
+		s.AddReactor("BLOCKSYNC", reactorPairs[i].reactor)
+		return s
+	}, p2p.Connect2Switches)
+	defer func() {
+		for _, r := range reactorPairs {
+			require.NoError(t, r.reactor.Stop())
+			require.NoError(t, r.app.Stop())
+		}
+	}()
+
+	// Block sync halts once the block with the conflicting header is checked,
+	// which happens in the background, without switching to consensus.
+	require.Eventually(t, func() bool {
+		return !syncing.reactor.pool.IsRunning()
+	}, 30*time.Second, 10*time.Millisecond)
+	assert.GreaterOrEqual(t, syncing.reactor.store.Height(), conflictHeight)
+	assert.Empty(t, syncing.reactor.witnessConflicts)
+}
+
+func TestQueueWitnessCheck(t *testing.T) {
+	bcR := &Reactor{pool: NewBlockPool(1, nil, nil)}
+	bcR.BaseReactor = *p2p.NewBaseReactor("Reactor", bcR)
+	bcR.SetLogger(log.TestingLogger())
+	bcR.SetWitnesses([]provider.Provider{&testWitness{}}, 1)
+
+	// The checks beyond the queue size are skipped, rather than blocking.
+	for i := 0; i < 2*witnessQueueSize; i++ {
+		bcR.queueWitnessCheck(&types.Block{Header: types.Header{Height: int64(i + 1)}})
+	}
+	assert.Len(t, bcR.witnessChecks, witnessQueueSize)
+	assert.EqualValues(t, witnessQueueSize, bcR.witnessPending.Load())
+}
@@ -10,6 +10,7 @@ import (
 
 	cfg "github.com/cometbft/cometbft/config"
 	"github.com/cometbft/cometbft/inspect"
+	"github.com/cometbft/cometbft/mempool"
 	"github.com/cometbft/cometbft/state"
 	"github.com/cometbft/cometbft/state/indexer/block"
 	"github.com/cometbft/cometbft/store"
@@ -77,7 +78,16 @@ func runInspect(cmd *cobra.Command, _ []string) error {
 	if err != nil {
 		return err
 	}
-	ins := inspect.New(config.RPC, blockStore, stateStore, txIndexer, blockIndexer)
+	var options []inspect.Option
+	if config.Mempool.DumpEnabled() {
+		txs, err := mempool.ReadDumpFile(config.Mempool.DumpFile())
+		if err != nil {
+			return err
+		}
+		logger.Info("loaded mempool dump", "path", config.Mempool.DumpFile(), "txs", len(txs))
+		options = append(options, inspect.WithMempoolDump(txs))
+	}
+	ins := inspect.New(config.RPC, blockStore, stateStore, txIndexer, blockIndexer, options...)
 
 	logger.Info("starting inspect server")
 	return ins.Run(ctx)
@@ -5,11 +5,15 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
+	cmtstrings "github.com/cometbft/cometbft/libs/strings"
 	"github.com/cometbft/cometbft/version"
 )
 
@@ -47,6 +51,36 @@ const (
 	LegacyMempoolTypeFlood    = "v0"
 	LegacyMempoolTypePriority = "v1"
 	LegacyMempoolTypeCAT      = "v2"
+
+	// The mempool compresses batches of transactions with the same
+	// algorithms as p2p packets.
+	MempoolCompressionZstd   = P2PCompressionZstd
+	MempoolCompressionSnappy = P2PCompressionSnappy
+
+	// MaxMempoolBatchBytes is the protocol-level maximum size of a batch of
+	// transactions: peers accept batches up to this size, whatever their own
+	// max_batch_bytes.
+	MaxMempoolBatchBytes = 1048576 // 1 MiB
+
+	RecheckPolicyAlways       = "always"
+	RecheckPolicyEveryNBlocks = "every_n_blocks"
+	RecheckPolicyNever        = "never"
+	RecheckPolicyOnDemand     = "on_demand"
+
+	MempoolGossipFlood    = "flood"
+	MempoolGossipHaveWant = "have_want"
+
+	P2PCompressionZstd   = "zstd"
+	P2PCompressionSnappy = "snappy"
+
+	P2PRoleFull      = "full"
+	P2PRoleValidator = "validator"
+	P2PRoleSentry    = "sentry"
+	P2PRoleSeed      = "seed"
+
+	P2PNATUPnP   = "upnp"
+	P2PNATNATPMP = "natpmp"
+	P2PNATAny    = "any"
 )
 
 // NOTE: Most of the structs & relevant comments + the
@@ -419,6 +453,10 @@ type RPCConfig struct {
 	// https://www.jsonrpc.org/specification#batch
 	MaxRequestBatchSize int `mapstructure:"max_request_batch_size"`
 
+	// Maximum number of blocks that can be requested in a single call to the
+	// inspect /blocks endpoint.
+	MaxBlocksPerRequest int `mapstructure:"max_blocks_per_request"`
+
 	// Maximum size of request body, in bytes
 	MaxBodyBytes int64 `mapstructure:"max_body_bytes"`
 
@@ -468,6 +506,7 @@ func DefaultRPCConfig() *RPCConfig {
 		WebSocketWriteBufferSize:  defaultSubscriptionBufferSize,
 
 		MaxRequestBatchSize: 10,             // maximum requests in a JSON-RPC batch request
+		MaxBlocksPerRequest: 20,             // maximum blocks returned by the inspect /blocks endpoint
 		MaxBodyBytes:        int64(1000000), // 1MB
 		MaxHeaderBytes:      1 << 20,        // same as the net/http default
 
@@ -518,6 +557,9 @@ func (cfg *RPCConfig) ValidateBasic() error {
 	if cfg.MaxRequestBatchSize < 0 {
 		return errors.New("max_request_batch_size can't be negative")
 	}
+	if cfg.MaxBlocksPerRequest < 0 {
+		return errors.New("max_blocks_per_request can't be negative")
+	}
 	if cfg.MaxBodyBytes < 0 {
 		return errors.New("max_body_bytes can't be negative")
 	}
@@ -569,6 +611,20 @@ type P2PConfig struct {
 	// Address to advertise to peers for them to dial
 	ExternalAddress string `mapstructure:"external_address"`
 
+	// Protocol with which to map the listen port on the gateway of a node
+	// behind a NAT, and advertise the external address of the mapping if
+	// external_address is empty: "upnp", "natpmp" (Linux only), or "any" to
+	// use the first of them the gateway supports. Empty disables port mapping.
+	// The external address is only advertised once it is verified to reach
+	// the node, which requires the gateway to support hairpinning.
+	NAT string `mapstructure:"nat"`
+
+	// Set true to advertise, if external_address is empty, the IP that
+	// outbound peers observe the node connecting from, with the port of the
+	// listen address (or of the mapping on the gateway), once it is verified
+	// to reach the node.
+	DetectExternalAddress bool `mapstructure:"detect_external_address"`
+
 	// Comma separated list of seed nodes to connect to
 	// We only use these if we can’t connect to peers in the addrbook
 	Seeds string `mapstructure:"seeds"`
@@ -607,6 +663,20 @@ type P2PConfig struct {
 	// Rate at which packets can be received, in bytes/second
 	RecvRate int64 `mapstructure:"recv_rate"`
 
+	// Comma separated list of channel:rate pairs limiting the rate at which
+	// packets can be sent on given channels, in bytes/second, on top of
+	// send_rate, e.g. "0x40:1024000,0x61:512000"
+	ChannelSendRates string `mapstructure:"channel_send_rates"`
+
+	// Comma separated list of the algorithms ("zstd", "snappy") with which
+	// messages may be compressed, in order of preference. The first one also
+	// supported by a peer, as advertised during the handshake, is used to
+	// compress the messages sent to it. Empty disables compression.
+	Compression string `mapstructure:"compression"`
+
+	// Minimum size of the messages compressed, in bytes
+	CompressionMinSize int `mapstructure:"compression_min_size"`
+
 	// Set true to enable the peer-exchange reactor
 	PexReactor bool `mapstructure:"pex"`
 
@@ -616,6 +686,27 @@ type P2PConfig struct {
 	// Does not work if the peer-exchange reactor is disabled.
 	SeedMode bool `mapstructure:"seed_mode"`
 
+	// Set true to dial back the listen address claimed by an inbound peer,
+	// and only add it to the address book, and so advertise it to other
+	// peers, if the node listening there authenticates as the peer.
+	DialBackInbound bool `mapstructure:"dial_back_inbound"`
+
+	// Role of the node in the network, advertised to its peers: "full",
+	// "validator" (behind sentries), "sentry" (shielding validators) or "seed".
+	// A validator doesn't run the peer-exchange reactor. A sentry keeps the
+	// addresses of its persistent validator peers private and always accepts
+	// them.
+	// Defaults to "seed" in seed mode, and "full" otherwise.
+	Role string `mapstructure:"role"`
+
+	// Semicolon separated list of role:reactors entries restricting the
+	// reactors that peers of a role may use, reactors being comma separated
+	// and "*" meaning all of them, e.g. "seed:PEX;full:PEX,MEMPOOL". Peers of
+	// roles that are not listed may use all the reactors. Roles are only
+	// trusted from persistent and unconditional peers: other peers may not use
+	// the reactors full nodes may not use, whatever role they advertise.
+	RoleReactors string `mapstructure:"role_reactors"`
+
 	// Comma separated list of peer IDs to keep private (will not be gossiped to
 	// other peers)
 	PrivatePeerIDs string `mapstructure:"private_peer_ids"`
@@ -623,7 +714,15 @@ type P2PConfig struct {
 	// Toggle to disable guard against peers connecting from the same ip.
 	AllowDuplicateIP bool `mapstructure:"allow_duplicate_ip"`
 
+	// Path to a JSON file listing the CIDR ranges, IP addresses and peer IDs
+	// allowed and denied to connect (see p2p/filter.AccessList). The file is
+	// reloaded on SIGHUP and with the unsafe_reload_p2p_access_list RPC call.
+	// Empty disables the access list.
+	AccessList string `mapstructure:"access_list_file"`
+
 	// Peer connection configuration.
+	// The handshake timeout applies to each stage of the handshake: the
+	// secret connection and the node info exchange.
 	HandshakeTimeout time.Duration `mapstructure:"handshake_timeout"`
 	DialTimeout      time.Duration `mapstructure:"dial_timeout"`
 
@@ -640,6 +739,8 @@ func DefaultP2PConfig() *P2PConfig {
 	return &P2PConfig{
 		ListenAddress:                "tcp://0.0.0.0:26656",
 		ExternalAddress:              "",
+		NAT:                          "",
+		DetectExternalAddress:        false,
 		AddrBook:                     defaultAddrBookPath,
 		AddrBookStrict:               true,
 		MaxNumInboundPeers:           40,
@@ -649,8 +750,11 @@ func DefaultP2PConfig() *P2PConfig {
 		MaxPacketMsgPayloadSize:      1024,    // 1 kB
 		SendRate:                     5120000, // 5 mB/s
 		RecvRate:                     5120000, // 5 mB/s
+		CompressionMinSize:           4096,    // 4 kB
 		PexReactor:                   true,
 		SeedMode:                     false,
+		DialBackInbound:              false,
+		RoleReactors:                 P2PRoleSeed + ":PEX",
 		AllowDuplicateIP:             false,
 		HandshakeTimeout:             20 * time.Second,
 		DialTimeout:                  3 * time.Second,
@@ -674,6 +778,15 @@ func (cfg *P2PConfig) AddrBookFile() string {
 	return rootify(cfg.AddrBook, cfg.RootDir)
 }
 
+// AccessListFile returns the full path to the access list, or an empty string
+// if there is none.
+func (cfg *P2PConfig) AccessListFile() string {
+	if cfg.AccessList == "" {
+		return ""
+	}
+	return rootify(cfg.AccessList, cfg.RootDir)
+}
+
 // ValidateBasic performs basic validation (checking param bounds, etc.) and
 // returns an error if any check fails.
 func (cfg *P2PConfig) ValidateBasic() error {
@@ -698,9 +811,164 @@ func (cfg *P2PConfig) ValidateBasic() error {
 	if cfg.RecvRate < 0 {
 		return errors.New("recv_rate can't be negative")
 	}
+	if _, err := ParseChannelRates(cfg.ChannelSendRates); err != nil {
+		return fmt.Errorf("invalid channel_send_rates: %w", err)
+	}
+	for _, algo := range cmtstrings.SplitAndTrimEmpty(cfg.Compression, ",", " ") {
+		switch algo {
+		case P2PCompressionZstd, P2PCompressionSnappy:
+		default:
+			return fmt.Errorf("unsupported p2p compression %q: must be %q or %q",
+				algo, P2PCompressionZstd, P2PCompressionSnappy)
+		}
+	}
+	if cfg.HandshakeTimeout < 0 {
+		return errors.New("handshake_timeout can't be negative")
+	}
+	if cfg.CompressionMinSize < 0 {
+		return errors.New("compression_min_size can't be negative")
+	}
+	switch cfg.Role {
+	case "", P2PRoleFull, P2PRoleValidator, P2PRoleSentry, P2PRoleSeed:
+	default:
+		return fmt.Errorf("unknown p2p role %q", cfg.Role)
+	}
+	if cfg.SeedMode && cfg.NodeRole() != P2PRoleSeed {
+		return fmt.Errorf("the role of a node in seed mode can't be %q", cfg.Role)
+	}
+	if _, err := ParseRoleReactors(cfg.RoleReactors); err != nil {
+		return fmt.Errorf("invalid role_reactors: %w", err)
+	}
+	switch cfg.NAT {
+	case "", P2PNATUPnP, P2PNATNATPMP, P2PNATAny:
+	default:
+		return fmt.Errorf("unsupported nat %q: must be %q, %q or %q",
+			cfg.NAT, P2PNATUPnP, P2PNATNATPMP, P2PNATAny)
+	}
 	return nil
 }
 
+// NodeRole returns the role of the node in the network.
+func (cfg *P2PConfig) NodeRole() string {
+	switch {
+	case cfg.Role != "":
+		return cfg.Role
+	case cfg.SeedMode:
+		return P2PRoleSeed
+	default:
+		return P2PRoleFull
+	}
+}
+
+// PexEnabled returns true if the node runs the peer-exchange reactor, which
+// validators behind sentries don't.
+func (cfg *P2PConfig) PexEnabled() bool {
+	return cfg.PexReactor && cfg.NodeRole() != P2PRoleValidator
+}
+
+// ParseRoleReactors parses a semicolon separated list of role:reactors
+// entries, such as "seed:PEX;full:PEX,MEMPOOL", into the reactors that peers
+// of each role may use. A "*" reactor allows all of them, in which case the
+// role maps to nil.
+func ParseRoleReactors(s string) (map[string][]string, error) {
+	roles := make(map[string][]string)
+	for _, entry := range cmtstrings.SplitAndTrimEmpty(s, ";", " ") {
+		role, list, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, fmt.Errorf("%q is not a role:reactors entry", entry)
+		}
+		role = strings.TrimSpace(role)
+		switch role {
+		case P2PRoleFull, P2PRoleValidator, P2PRoleSentry, P2PRoleSeed:
+		default:
+			return nil, fmt.Errorf("unknown role %q", role)
+		}
+		if _, ok := roles[role]; ok {
+			return nil, fmt.Errorf("duplicate role %q", role)
+		}
+		reactors := cmtstrings.SplitAndTrimEmpty(list, ",", " ")
+		if cmtstrings.StringInSlice("*", reactors) {
+			reactors = nil
+		} else if reactors == nil {
+			reactors = []string{}
+		}
+		roles[role] = reactors
+	}
+	return roles, nil
+}
+
+// ParseChannelRates parses a comma separated list of channel:rate pairs, such
+// as "0x40:1024000,0x61:512000", into the rates of the channels, in
+// bytes/second. Channel ids may be decimal or hexadecimal with a 0x prefix.
+func ParseChannelRates(s string) (map[byte]int64, error) {
+	rates := make(map[byte]int64)
+	for _, pair := range cmtstrings.SplitAndTrimEmpty(s, ",", " ") {
+		id, rate, ok := strings.Cut(pair, ":")
+		if !ok {
+			return nil, fmt.Errorf("%q is not a channel:rate pair", pair)
+		}
+		chID, err := strconv.ParseUint(strings.TrimSpace(id), 0, 8)
+		if err != nil {
+			return nil, fmt.Errorf("invalid channel %q: %w", id, err)
+		}
+		r, err := strconv.ParseInt(strings.TrimSpace(rate), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate %q: %w", rate, err)
+		}
+		if r < 0 {
+			return nil, fmt.Errorf("rate of channel %q can't be negative", id)
+		}
+		if _, ok := rates[byte(chID)]; ok {
+			return nil, fmt.Errorf("duplicate channel %q", id)
+		}
+		rates[byte(chID)] = r
+	}
+	return rates, nil
+}
+
+// MempoolLane is a lane of the priority mempool, as parsed from the lanes
+// setting of the mempool configuration.
+type MempoolLane struct {
+	Name        string
+	MaxTxs      int
+	MaxTxsBytes int64
+}
+
+// ParseMempoolLanes parses a comma separated list of
+// name:max_txs:max_txs_bytes triples, such as
+// "blob:1000:8000000,transfer:5000:0", into mempool lanes, in the order given.
+func ParseMempoolLanes(s string) ([]MempoolLane, error) {
+	var lanes []MempoolLane
+	names := make(map[string]struct{})
+	for _, triple := range cmtstrings.SplitAndTrimEmpty(s, ",", " ") {
+		parts := strings.Split(triple, ":")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("%q is not a name:max_txs:max_txs_bytes triple", triple)
+		}
+		name := strings.TrimSpace(parts[0])
+		if name == "" {
+			return nil, fmt.Errorf("lane %q has no name", triple)
+		}
+		if _, ok := names[name]; ok {
+			return nil, fmt.Errorf("duplicate lane %q", name)
+		}
+		maxTxs, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid max_txs of lane %q: %w", name, err)
+		}
+		maxTxsBytes, err := strconv.ParseInt(strings.TrimSpace(parts[2]), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid max_txs_bytes of lane %q: %w", name, err)
+		}
+		if maxTxs < 0 || maxTxsBytes < 0 {
+			return nil, fmt.Errorf("quotas of lane %q can't be negative", name)
+		}
+		names[name] = struct{}{}
+		lanes = append(lanes, MempoolLane{Name: name, MaxTxs: maxTxs, MaxTxsBytes: maxTxsBytes})
+	}
+	return lanes, nil
+}
+
 // FuzzConnConfig is a FuzzedConnection configuration.
 type FuzzConnConfig struct {
 	Mode         int
@@ -760,6 +1028,22 @@ type MempoolConfig struct {
 	// transaction. We consider that the ABCI application runs in the same location as the CometBFT binary
 	// so that the recheck duration is not affected by network delays when making requests and receiving responses.
 	RecheckTimeout time.Duration `mapstructure:"recheck_timeout"`
+	// RecheckPolicy (default: "always") defines when the priority mempool
+	// rechecks its transactions: "always" after every block, "every_n_blocks"
+	// every RecheckInterval blocks, "never", or "on_demand", only when
+	// requested through the unsafe_recheck_mempool RPC endpoint. Setting
+	// Recheck to false is the same as "never". Only applies to the priority
+	// mempool.
+	RecheckPolicy string `mapstructure:"recheck_policy"`
+	// RecheckInterval is the number of blocks between two rechecks with the
+	// "every_n_blocks" recheck policy.
+	RecheckInterval int64 `mapstructure:"recheck_interval"`
+	// RecheckAsync (default: false) defines whether the priority mempool
+	// rechecks its transactions in the background instead of while committing
+	// a block. Transactions not rechecked yet may then be reaped for the next
+	// block. A recheck still in progress when the next one starts is
+	// abandoned.
+	RecheckAsync bool `mapstructure:"recheck_async"`
 	// Broadcast (default: true) defines whether the mempool should relay
 	// transactions to other peers. Setting this to false will stop the mempool
 	// from relaying transactions to other peers until they are included in a
@@ -771,6 +1055,18 @@ type MempoolConfig struct {
 	// WalPath to where you want the WAL to be written (e.g.
 	// "data/mempool.wal").
 	WalPath string `mapstructure:"wal_dir"`
+	// PersistToDisk (default: false) journals the transactions accepted into
+	// the mempool to a write-ahead log in WalPath, or in data/mempool.wal if
+	// WalPath is unset, and replays them through CheckTx on startup, or once
+	// the node caught up if it state or block syncs, so that transactions are
+	// not dropped when the node restarts. Only applies to the priority mempool.
+	PersistToDisk bool `mapstructure:"persist_to_disk"`
+	// DumpPath (default: "") configures the location of a mempool dump. When
+	// set, the priority mempool writes its transactions to the dump every
+	// minute and when the node stops, and the inspect command loads the dump
+	// and serves the transactions it contains through the mempool_dump
+	// endpoint, to analyze what was pending when the node crashed.
+	DumpPath string `mapstructure:"dump_path"`
 	// Maximum number of transactions in the mempool
 	Size int `mapstructure:"size"`
 	// Limit the total size of all txs in the mempool.
@@ -785,11 +1081,62 @@ type MempoolConfig struct {
 	KeepInvalidTxsInCache bool `mapstructure:"keep-invalid-txs-in-cache"`
 	// Maximum size of a single transaction
 	// NOTE: the max size of a tx transmitted over the network is {max_tx_bytes}.
+	// It is independent of the maximum block size: larger transactions are
+	// neither accepted nor gossiped, and the broadcast_tx RPC endpoints
+	// reject them with code 1 of the "mempool" codespace.
 	MaxTxBytes int `mapstructure:"max_tx_bytes"`
 	// Maximum size of a batch of transactions to send to a peer
 	// Including space needed by encoding (one varint per transaction).
-	// XXX: Unused due to https://github.com/tendermint/tendermint/issues/5796
+	// Only applies to the flood and priority mempools. 0 means that
+	// transactions are sent one per message. Can't be greater than 1 MiB.
 	MaxBatchBytes int `mapstructure:"max_batch_bytes"`
+	// Compression of the batches of transactions sent to peers: "zstd",
+	// "snappy" or "" for none. Batches are only sent compressed when it makes
+	// them smaller, and to the peers supporting the algorithm, as advertised
+	// in their node info. Only applies to the flood and priority mempools.
+	GossipCompression string `mapstructure:"gossip_compression"`
+	// GossipProtocol (default: "flood") defines how the priority mempool
+	// gossips transactions: "flood" sends them to every peer, while
+	// "have_want" only announces their hashes with SeenTx messages and sends
+	// them to the peers asking for them with WantTx messages, which saves
+	// bandwidth when peers get transactions from several others. Peers must
+	// handle SeenTx and WantTx messages, as the priority and CAT mempools do.
+	// Only applies to the priority mempool; the CAT mempool always uses
+	// have/want.
+	GossipProtocol string `mapstructure:"gossip_protocol"`
+	// Maximum number of distinct senders with transactions in the mempool.
+	// Transactions from new senders are rejected once the limit is reached,
+	// while senders that already have transactions in the mempool are not
+	// affected. Only applies to the priority mempool. 0 means unlimited.
+	MaxSenders int `mapstructure:"max_senders"`
+	// Minimum priority a transaction must be assigned by the application in
+	// CheckTx to be admitted. Transactions below the floor are rejected.
+	// Only applies to the priority mempool. 0 disables the floor.
+	MinPriority int64 `mapstructure:"min_priority"`
+	// Comma separated list of the lanes of the mempool, as
+	// name:max_txs:max_txs_bytes triples, such as
+	// "blob:1000:8000000,transfer:5000:0". Transactions are assigned to a lane
+	// by the "lane" attribute of the "mempool" event of their CheckTx
+	// response, and reaped lane by lane in the order given. A quota of 0 means
+	// that only the mempool size limits the lane. Only applies to the priority
+	// mempool. Empty disables lanes.
+	Lanes string `mapstructure:"lanes"`
+	// If true, the priority mempool orders the transactions of an account by
+	// nonce, taking the account and nonce of a transaction from the address
+	// and sequence of its CheckTx response. An account may then have several
+	// transactions in the mempool, as long as their nonces differ, and they
+	// are reaped in nonce order. Only applies to the priority mempool.
+	NonceOrdering bool `mapstructure:"nonce_ordering"`
+	// Maximum number of transactions per second, and of bytes of transactions
+	// per second, that a peer may gossip to the mempool.
+	// Transactions in excess are dropped. 0 means unlimited.
+	PeerMaxTxsPerSecond   int   `mapstructure:"peer_max_txs_per_second"`
+	PeerMaxBytesPerSecond int64 `mapstructure:"peer_max_bytes_per_second"`
+	// Score at which a peer exceeding the rate limits above is disconnected.
+	// The score of a peer is the number of its transactions dropped for
+	// exceeding the limits, halved every 10 seconds, so that only sustained
+	// abuse is punished. 0 means peers are never disconnected.
+	PeerMaxRateViolations int `mapstructure:"peer_max_rate_violations"`
 	// Experimental parameters to limit gossiping txs to up to the specified number of peers.
 	// We use two independent upper values for persistent and non-persistent peers.
 	// Unconditional peers are not affected by this feature.
@@ -807,7 +1154,8 @@ type MempoolConfig struct {
 
 	// MaxGossipDelay is the maximum allotted time that the reactor expects a transaction to
 	// arrive before issuing a new request to a different peer
-	// Only applicable to the v2 / CAT mempool
+	// Only applicable to the v2 / CAT mempool, and to the priority mempool
+	// with the have_want gossip protocol
 	// Default is 200ms
 	MaxGossipDelay time.Duration `mapstructure:"max-gossip-delay"`
 
@@ -834,6 +1182,7 @@ func DefaultMempoolConfig() *MempoolConfig {
 		Type:           MempoolTypeCAT,
 		Recheck:        true,
 		RecheckTimeout: 1000 * time.Millisecond,
+		RecheckPolicy:  RecheckPolicyAlways,
 		Broadcast:      true,
 		WalPath:        "",
 		// Each signature verification takes .5ms, Size reduced until we implement
@@ -861,11 +1210,42 @@ func (cfg *MempoolConfig) WalDir() string {
 	return rootify(cfg.WalPath, cfg.RootDir)
 }
 
+// PersistDir returns the full path to the directory of the write-ahead log
+// of a mempool persisted to disk, which defaults to data/mempool.wal.
+func (cfg *MempoolConfig) PersistDir() string {
+	if cfg.WalPath == "" {
+		return rootify(filepath.Join(DefaultDataDir, "mempool.wal"), cfg.RootDir)
+	}
+	return cfg.WalDir()
+}
+
+// GetRecheckPolicy returns the recheck policy, taking Recheck into account.
+func (cfg *MempoolConfig) GetRecheckPolicy() string {
+	switch {
+	case !cfg.Recheck:
+		return RecheckPolicyNever
+	case cfg.RecheckPolicy == "":
+		return RecheckPolicyAlways
+	default:
+		return cfg.RecheckPolicy
+	}
+}
+
 // WalEnabled returns true if the WAL is enabled.
 func (cfg *MempoolConfig) WalEnabled() bool {
 	return cfg.WalPath != ""
 }
 
+// DumpFile returns the full path to the mempool dump.
+func (cfg *MempoolConfig) DumpFile() string {
+	return rootify(cfg.DumpPath, cfg.RootDir)
+}
+
+// DumpEnabled returns true if a mempool dump is configured.
+func (cfg *MempoolConfig) DumpEnabled() bool {
+	return cfg.DumpPath != ""
+}
+
 // ValidateBasic performs basic validation (checking param bounds, etc.) and
 // returns an error if any check fails.
 func (cfg *MempoolConfig) ValidateBasic() error {
@@ -887,6 +1267,52 @@ func (cfg *MempoolConfig) ValidateBasic() error {
 	if cfg.MaxTxBytes < 0 {
 		return errors.New("max_tx_bytes can't be negative")
 	}
+	switch cfg.RecheckPolicy {
+	case "", RecheckPolicyAlways, RecheckPolicyNever, RecheckPolicyOnDemand:
+	case RecheckPolicyEveryNBlocks:
+		if cfg.RecheckInterval <= 0 {
+			return errors.New("recheck_interval must be positive with the every_n_blocks recheck policy")
+		}
+	default:
+		return fmt.Errorf("unknown recheck_policy: %q", cfg.RecheckPolicy)
+	}
+	if cfg.RecheckInterval < 0 {
+		return errors.New("recheck_interval can't be negative")
+	}
+	if cfg.MaxBatchBytes < 0 {
+		return errors.New("max_batch_bytes can't be negative")
+	}
+	if cfg.MaxBatchBytes > MaxMempoolBatchBytes {
+		return fmt.Errorf("max_batch_bytes can't be greater than %d", MaxMempoolBatchBytes)
+	}
+	switch cfg.GossipProtocol {
+	case "", MempoolGossipFlood, MempoolGossipHaveWant:
+	default:
+		return fmt.Errorf("unknown gossip_protocol: %q", cfg.GossipProtocol)
+	}
+	switch cfg.GossipCompression {
+	case "", MempoolCompressionZstd, MempoolCompressionSnappy:
+	default:
+		return fmt.Errorf("unknown gossip_compression: %q", cfg.GossipCompression)
+	}
+	if cfg.MaxSenders < 0 {
+		return errors.New("max_senders can't be negative")
+	}
+	if cfg.MinPriority < 0 {
+		return errors.New("min_priority can't be negative")
+	}
+	if _, err := ParseMempoolLanes(cfg.Lanes); err != nil {
+		return fmt.Errorf("invalid lanes: %w", err)
+	}
+	if cfg.PeerMaxTxsPerSecond < 0 {
+		return errors.New("peer_max_txs_per_second can't be negative")
+	}
+	if cfg.PeerMaxBytesPerSecond < 0 {
+		return errors.New("peer_max_bytes_per_second can't be negative")
+	}
+	if cfg.PeerMaxRateViolations < 0 {
+		return errors.New("peer_max_rate_violations can't be negative")
+	}
 	if cfg.ExperimentalMaxGossipConnectionsToPersistentPeers < 0 {
 		return errors.New("experimental_max_gossip_connections_to_persistent_peers can't be negative")
 	}
@@ -910,6 +1336,43 @@ type StateSyncConfig struct {
 	DiscoveryTime       time.Duration `mapstructure:"discovery_time"`
 	ChunkRequestTimeout time.Duration `mapstructure:"chunk_request_timeout"`
 	ChunkFetchers       int32         `mapstructure:"chunk_fetchers"`
+
+	// ChunkRequestsPerPeer limits the chunk requests outstanding at each peer,
+	// so that the chunk fetchers spread their requests across peers.
+	ChunkRequestsPerPeer int32 `mapstructure:"chunk_requests_per_peer"`
+	// ChunkRetryBudget is the number of chunk requests which may be retried,
+	// once they timed out, when restoring a snapshot, before rejecting it. 0
+	// means no limit.
+	ChunkRetryBudget int32 `mapstructure:"chunk_retry_budget"`
+
+	// If TrustedSnapshotHeight is set, any snapshot at that height whose hash
+	// differs from TrustedSnapshotHash is rejected, regardless of how many
+	// peers advertise it.
+	TrustedSnapshotHeight uint64 `mapstructure:"trusted_snapshot_height"`
+	TrustedSnapshotHash   string `mapstructure:"trusted_snapshot_hash"`
+
+	// If LocalPath is set, the snapshot exported to this directory or http(s)
+	// URL is restored instead of the snapshots of peers. It must hold the
+	// snapshot, as the JSON encoding of an abci.Snapshot, in snapshot.json,
+	// and its chunks in chunks/<index>.
+	LocalPath string `mapstructure:"local_path"`
+
+	// BackfillBlocks is the number of blocks, up to the snapshot height, to
+	// fetch from peers after restoring a snapshot, before switching to block
+	// sync, e.g. to cover the evidence window. 0 disables the backfill.
+	BackfillBlocks int64 `mapstructure:"backfill_blocks"`
+
+	// ChunkHashes enables computing the hashes of the chunks of the local
+	// snapshots, to advertise them to peers along the snapshots, so that
+	// peers can verify each chunk before applying it. Each snapshot is loaded
+	// from the app once, in the background.
+	ChunkHashes bool `mapstructure:"chunk_hashes"`
+
+	// SnapshotUsageInterval is the interval at which the requests of peers
+	// for the local snapshots are reported to the snapshot usage hook, if any,
+	// so that the app can prune the snapshots which are not used. 0 disables
+	// the reports.
+	SnapshotUsageInterval time.Duration `mapstructure:"snapshot_usage_interval"`
 }
 
 func (cfg *StateSyncConfig) TrustHashBytes() []byte {
@@ -921,13 +1384,24 @@ func (cfg *StateSyncConfig) TrustHashBytes() []byte {
 	return bytes
 }
 
+func (cfg *StateSyncConfig) TrustedSnapshotHashBytes() []byte {
+	// validated in ValidateBasic, so we can safely panic here
+	bytes, err := hex.DecodeString(cfg.TrustedSnapshotHash)
+	if err != nil {
+		panic(err)
+	}
+	return bytes
+}
+
 // DefaultStateSyncConfig returns a default configuration for the state sync service
 func DefaultStateSyncConfig() *StateSyncConfig {
 	return &StateSyncConfig{
-		TrustPeriod:         168 * time.Hour,
-		DiscoveryTime:       15 * time.Second,
-		ChunkRequestTimeout: 10 * time.Second,
-		ChunkFetchers:       4,
+		TrustPeriod:           168 * time.Hour,
+		DiscoveryTime:         15 * time.Second,
+		ChunkRequestTimeout:   10 * time.Second,
+		ChunkFetchers:         4,
+		ChunkRequestsPerPeer:  4,
+		SnapshotUsageInterval: 10 * time.Minute,
 	}
 }
 
@@ -981,6 +1455,41 @@ func (cfg *StateSyncConfig) ValidateBasic() error {
 		if cfg.ChunkFetchers <= 0 {
 			return errors.New("chunk_fetchers is required")
 		}
+
+		if cfg.ChunkRequestsPerPeer <= 0 {
+			return errors.New("chunk_requests_per_peer is required")
+		}
+
+		if cfg.ChunkRetryBudget < 0 {
+			return errors.New("chunk_retry_budget can't be negative")
+		}
+
+		if cfg.TrustedSnapshotHeight > 0 {
+			if len(cfg.TrustedSnapshotHash) == 0 {
+				return errors.New("trusted_snapshot_hash is required when trusted_snapshot_height is set")
+			}
+			if _, err := hex.DecodeString(cfg.TrustedSnapshotHash); err != nil {
+				return fmt.Errorf("invalid trusted_snapshot_hash: %w", err)
+			}
+		}
+
+		if strings.Contains(cfg.LocalPath, "://") {
+			u, err := url.Parse(cfg.LocalPath)
+			if err != nil {
+				return fmt.Errorf("invalid local_path: %w", err)
+			}
+			if u.Scheme != "http" && u.Scheme != "https" {
+				return fmt.Errorf("invalid local_path: unsupported scheme %q", u.Scheme)
+			}
+		}
+
+		if cfg.BackfillBlocks < 0 {
+			return errors.New("backfill_blocks can't be negative")
+		}
+	}
+
+	if cfg.SnapshotUsageInterval < 0 {
+		return errors.New("snapshot_usage_interval can't be negative")
 	}
 
 	return nil
@@ -992,12 +1501,34 @@ func (cfg *StateSyncConfig) ValidateBasic() error {
 // BlockSyncConfig (formerly known as FastSync) defines the configuration for the CometBFT block sync service
 type BlockSyncConfig struct {
 	Version string `mapstructure:"version"`
+
+	// ArchiveSource is the source of the blocks which no peer has, e.g.
+	// because they are below the base height of every peer: "rpc" for the RPC
+	// endpoint of an archival node, "object_store" for an HTTP object store.
+	// Empty to disable.
+	ArchiveSource string `mapstructure:"archive_source"`
+	// ArchiveURL is the URL of the archive.
+	ArchiveURL string `mapstructure:"archive_url"`
+	// ArchiveDelay is how long to wait for a peer with the needed blocks,
+	// before loading them from the archive.
+	ArchiveDelay time.Duration `mapstructure:"archive_delay"`
+
+	// Witnesses are the RPC servers of light client witnesses, to cross-check
+	// the headers of a sample of the synced blocks against, in order to detect
+	// that the peers follow a fork, e.g. in a long-range attack. Block sync
+	// halts if a majority of them contradict a synced block.
+	Witnesses []string `mapstructure:"witnesses"`
+	// WitnessSampleRate is the fraction of the synced blocks to cross-check,
+	// between 0 and 1.
+	WitnessSampleRate float64 `mapstructure:"witness_sample_rate"`
 }
 
 // DefaultBlockSyncConfig returns a default configuration for the block sync service
 func DefaultBlockSyncConfig() *BlockSyncConfig {
 	return &BlockSyncConfig{
-		Version: "v0",
+		Version:           "v0",
+		ArchiveDelay:      30 * time.Second,
+		WitnessSampleRate: 0.01,
 	}
 }
 
@@ -1010,12 +1541,35 @@ func TestBlockSyncConfig() *BlockSyncConfig {
 func (cfg *BlockSyncConfig) ValidateBasic() error {
 	switch cfg.Version {
 	case "v0":
-		return nil
 	case "v1", "v2":
 		return fmt.Errorf("blocksync version %s has been deprecated. Please use v0 instead", cfg.Version)
 	default:
 		return fmt.Errorf("unknown blocksync version %s", cfg.Version)
 	}
+
+	if cfg.WitnessSampleRate < 0 || cfg.WitnessSampleRate > 1 {
+		return errors.New("witness_sample_rate must be between 0 and 1")
+	}
+	for _, witness := range cfg.Witnesses {
+		if witness == "" {
+			return errors.New("found empty witnesses entry")
+		}
+	}
+
+	switch cfg.ArchiveSource {
+	case "":
+		return nil
+	case "rpc", "object_store":
+	default:
+		return fmt.Errorf("unknown archive_source %q", cfg.ArchiveSource)
+	}
+	if cfg.ArchiveURL == "" {
+		return errors.New("archive_url is required when archive_source is set")
+	}
+	if cfg.ArchiveDelay < 0 {
+		return errors.New("archive_delay can't be negative")
+	}
+	return nil
 }
 
 //-----------------------------------------------------------------------------
@@ -85,6 +85,7 @@ func TestRPCConfigValidateBasic(t *testing.T) {
 		"MaxBodyBytes",
 		"MaxHeaderBytes",
 		"MaxRequestBatchSize",
+		"MaxBlocksPerRequest",
 	}
 
 	for _, fieldName := range fieldsToTest {
@@ -105,6 +106,8 @@ func TestP2PConfigValidateBasic(t *testing.T) {
 		"MaxPacketMsgPayloadSize",
 		"SendRate",
 		"RecvRate",
+		"CompressionMinSize",
+		"HandshakeTimeout",
 	}
 
 	for _, fieldName := range fieldsToTest {
@@ -112,6 +115,91 @@ func TestP2PConfigValidateBasic(t *testing.T) {
 		assert.Error(t, cfg.ValidateBasic())
 		reflect.ValueOf(cfg).Elem().FieldByName(fieldName).SetInt(0)
 	}
+
+	cfg.NAT = "pcp"
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.NAT = config.P2PNATAny
+	assert.NoError(t, cfg.ValidateBasic())
+
+	for _, rates := range []string{"0x40", "0x40:-1", "256:1", "0x40:1,64:2", "vote:1"} {
+		cfg.ChannelSendRates = rates
+		assert.Error(t, cfg.ValidateBasic(), rates)
+	}
+	cfg.ChannelSendRates = "0x40:1024000, 97:512000"
+	assert.NoError(t, cfg.ValidateBasic())
+
+	cfg.Compression = "zstd,gzip"
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.Compression = "zstd, snappy"
+	assert.NoError(t, cfg.ValidateBasic())
+
+	cfg.Role = "archive"
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.Role = config.P2PRoleSentry
+	cfg.SeedMode = true
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.SeedMode = false
+	assert.NoError(t, cfg.ValidateBasic())
+
+	for _, roles := range []string{"seed", "archive:PEX", "seed:PEX;seed:*"} {
+		cfg.RoleReactors = roles
+		assert.Error(t, cfg.ValidateBasic(), roles)
+	}
+}
+
+func TestP2PConfigRoles(t *testing.T) {
+	cfg := config.DefaultP2PConfig()
+	assert.Equal(t, config.P2PRoleFull, cfg.NodeRole())
+	assert.True(t, cfg.PexEnabled())
+
+	cfg.SeedMode = true
+	assert.Equal(t, config.P2PRoleSeed, cfg.NodeRole())
+
+	cfg.SeedMode = false
+	cfg.Role = config.P2PRoleValidator
+	assert.Equal(t, config.P2PRoleValidator, cfg.NodeRole())
+	assert.False(t, cfg.PexEnabled())
+
+	roles, err := config.ParseRoleReactors(cfg.RoleReactors)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{config.P2PRoleSeed: {"PEX"}}, roles)
+
+	roles, err = config.ParseRoleReactors("seed:PEX; full: PEX,MEMPOOL ;sentry:*;validator:")
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		config.P2PRoleSeed:      {"PEX"},
+		config.P2PRoleFull:      {"PEX", "MEMPOOL"},
+		config.P2PRoleSentry:    nil,
+		config.P2PRoleValidator: {},
+	}, roles)
+}
+
+func TestParseChannelRates(t *testing.T) {
+	rates, err := config.ParseChannelRates("")
+	require.NoError(t, err)
+	assert.Empty(t, rates)
+
+	rates, err = config.ParseChannelRates("0x40:1024000, 97:512000,0x20:0")
+	require.NoError(t, err)
+	assert.Equal(t, map[byte]int64{0x40: 1024000, 0x61: 512000, 0x20: 0}, rates)
+}
+
+func TestParseMempoolLanes(t *testing.T) {
+	lanes, err := config.ParseMempoolLanes("")
+	require.NoError(t, err)
+	assert.Empty(t, lanes)
+
+	lanes, err = config.ParseMempoolLanes("blob:1000:8000000, transfer:5000:0")
+	require.NoError(t, err)
+	assert.Equal(t, []config.MempoolLane{
+		{Name: "blob", MaxTxs: 1000, MaxTxsBytes: 8000000},
+		{Name: "transfer", MaxTxs: 5000},
+	}, lanes)
+
+	for _, s := range []string{"blob:1000", ":1:1", "blob:1:1,blob:2:2", "blob:-1:0", "blob:x:0"} {
+		_, err := config.ParseMempoolLanes(s)
+		assert.Error(t, err, s)
+	}
 }
 
 func TestMempoolConfigValidateBasic(t *testing.T) {
@@ -123,6 +211,10 @@ func TestMempoolConfigValidateBasic(t *testing.T) {
 		"MaxTxsBytes",
 		"CacheSize",
 		"MaxTxBytes",
+		"MaxBatchBytes",
+		"RecheckInterval",
+		"MaxSenders",
+		"MinPriority",
 	}
 
 	for _, fieldName := range fieldsToTest {
@@ -133,11 +225,62 @@ func TestMempoolConfigValidateBasic(t *testing.T) {
 
 	reflect.ValueOf(cfg).Elem().FieldByName("Type").SetString("invalid")
 	assert.Error(t, cfg.ValidateBasic())
+	reflect.ValueOf(cfg).Elem().FieldByName("Type").SetString("")
+
+	cfg.MaxBatchBytes = config.MaxMempoolBatchBytes + 1
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.MaxBatchBytes = 0
+
+	cfg.GossipCompression = "gzip"
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.GossipCompression = ""
+
+	cfg.GossipProtocol = "gossipsub"
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.GossipProtocol = ""
+
+	cfg.RecheckPolicy = "sometimes"
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.RecheckPolicy = config.RecheckPolicyEveryNBlocks
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.RecheckInterval = 10
+	assert.NoError(t, cfg.ValidateBasic())
 }
 
 func TestStateSyncConfigValidateBasic(t *testing.T) {
 	cfg := config.TestStateSyncConfig()
 	require.NoError(t, cfg.ValidateBasic())
+
+	cfg.Enable = true
+	cfg.RPCServers = []string{"localhost:26657", "localhost:26658"}
+	cfg.TrustHeight = 1
+	cfg.TrustHash = "0102"
+	require.NoError(t, cfg.ValidateBasic())
+
+	cfg.ChunkRequestsPerPeer = 0
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.ChunkRequestsPerPeer = 1
+	cfg.ChunkRetryBudget = -1
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.ChunkRetryBudget = 0
+
+	cfg.LocalPath = "/var/snapshots/100"
+	assert.NoError(t, cfg.ValidateBasic())
+	cfg.LocalPath = "https://snapshots.example.com/100"
+	assert.NoError(t, cfg.ValidateBasic())
+	cfg.LocalPath = "ftp://snapshots.example.com/100"
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.LocalPath = ""
+
+	cfg.BackfillBlocks = 100
+	assert.NoError(t, cfg.ValidateBasic())
+	cfg.BackfillBlocks = -1
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.BackfillBlocks = 0
+
+	cfg.Enable = false
+	cfg.SnapshotUsageInterval = -time.Second
+	assert.Error(t, cfg.ValidateBasic())
 }
 
 func TestBlockSyncConfigValidateBasic(t *testing.T) {
@@ -150,6 +293,36 @@ func TestBlockSyncConfigValidateBasic(t *testing.T) {
 
 	cfg.Version = "invalid"
 	assert.Error(t, cfg.ValidateBasic())
+
+	// tamper with the archive
+	cfg.Version = "v0"
+	cfg.ArchiveSource = "rpc"
+	assert.Error(t, cfg.ValidateBasic())
+
+	cfg.ArchiveURL = "http://localhost:26657"
+	assert.NoError(t, cfg.ValidateBasic())
+
+	cfg.ArchiveDelay = -1
+	assert.Error(t, cfg.ValidateBasic())
+
+	cfg.ArchiveDelay = time.Second
+	cfg.ArchiveSource = "invalid"
+	assert.Error(t, cfg.ValidateBasic())
+
+	// tamper with the witnesses
+	cfg.ArchiveSource = ""
+	cfg.Witnesses = []string{"localhost:26657"}
+	assert.NoError(t, cfg.ValidateBasic())
+
+	cfg.Witnesses = []string{""}
+	assert.Error(t, cfg.ValidateBasic())
+
+	cfg.Witnesses = []string{"localhost:26657"}
+	cfg.WitnessSampleRate = 1.5
+	assert.Error(t, cfg.ValidateBasic())
+
+	cfg.WitnessSampleRate = -0.5
+	assert.Error(t, cfg.ValidateBasic())
 }
 
 func TestConsensusConfig_ValidateBasic(t *testing.T) {
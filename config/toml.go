@@ -244,6 +244,10 @@ timeout_broadcast_tx_commit = "{{ .RPC.TimeoutBroadcastTxCommit }}"
 # enforced for a JSON-RPC batch request.
 max_request_batch_size = {{ .RPC.MaxRequestBatchSize }}
 
+# Maximum number of blocks that can be requested in a single call to the
+# /blocks endpoint of the inspect server.
+max_blocks_per_request = {{ .RPC.MaxBlocksPerRequest }}
+
 # Maximum size of request body, in bytes
 max_body_bytes = {{ .RPC.MaxBodyBytes }}
 
@@ -281,6 +285,19 @@ laddr = "{{ .P2P.ListenAddress }}"
 # address. IP and port are required. Example: 159.89.10.97:26656
 external_address = "{{ .P2P.ExternalAddress }}"
 
+# Protocol with which to map the listen port on the gateway of a node behind a
+# NAT (e.g. a home router), and advertise the external address of the mapping
+# if external_address is empty: "upnp", "natpmp" (Linux only), or "any" to use
+# the first of them the gateway supports. Empty disables port mapping. The
+# external address is only advertised once dialing it reaches the node, which
+# requires the gateway to support hairpinning (NAT loopback).
+nat = "{{ .P2P.NAT }}"
+
+# Set true to advertise, if external_address is empty, the IP that outbound
+# peers observe the node connecting from, with the port of the listen address
+# (or of the mapping on the gateway), once dialing it reaches the node.
+detect_external_address = {{ .P2P.DetectExternalAddress }}
+
 # Comma separated list of seed nodes to connect to
 seeds = "{{ .P2P.Seeds }}"
 
@@ -318,6 +335,24 @@ send_rate = {{ .P2P.SendRate }}
 # Rate at which packets can be received, in bytes/second
 recv_rate = {{ .P2P.RecvRate }}
 
+# Comma separated list of channel:rate pairs limiting the rate at which packets
+# can be sent on given channels, in bytes/second, on top of send_rate, e.g.
+# "0x40:1024000,0x61:512000" to keep blocksync (0x40) and statesync chunks
+# (0x61) from starving consensus votes. Channels are otherwise scheduled by
+# priority. The rates can be changed at runtime with the
+# unsafe_set_channel_send_rates RPC endpoint.
+channel_send_rates = "{{ .P2P.ChannelSendRates }}"
+
+# Comma separated list of the algorithms ("zstd", "snappy") with which messages
+# may be compressed, in order of preference. The first one also supported by a
+# peer, as advertised during the handshake, is used to compress the messages
+# sent to it, which saves bandwidth on large block parts and snapshot chunks at
+# the cost of CPU. Empty disables compression.
+compression = "{{ .P2P.Compression }}"
+
+# Minimum size of the messages compressed, in bytes
+compression_min_size = {{ .P2P.CompressionMinSize }}
+
 # Set true to enable the peer-exchange reactor
 pex = {{ .P2P.PexReactor }}
 
@@ -327,13 +362,50 @@ pex = {{ .P2P.PexReactor }}
 # Does not work if the peer-exchange reactor is disabled.
 seed_mode = {{ .P2P.SeedMode }}
 
+# Set true to dial back the listen address claimed by an inbound peer, and only
+# add it to the address book, and so advertise it to other peers, if the node
+# listening there authenticates as the peer. This keeps the addresses of peers
+# behind a NAT, which can't be dialed, out of the address books of the network.
+# At most 10 addresses are verified per second.
+dial_back_inbound = {{ .P2P.DialBackInbound }}
+
+# Role of the node in the network, advertised to its peers: "full",
+# "validator" (behind sentries), "sentry" (shielding validators) or "seed".
+# A validator doesn't run the peer-exchange reactor, and so only dials its
+# persistent peers (its sentries). A sentry keeps the addresses of its
+# persistent validator peers private, and always accepts them whatever its peer
+# limits, as if they were private and unconditional peers.
+# Defaults to "seed" in seed mode, and "full" otherwise.
+role = "{{ .P2P.Role }}"
+
+# Semicolon separated list of role:reactors entries restricting the reactors
+# that peers of a role may use, reactors being comma separated and "*" meaning
+# all of them, e.g. "seed:PEX;full:PEX,MEMPOOL". Peers without any reactor they
+# may use are disconnected, as are peers sending messages to other reactors.
+# Peers of roles that are not listed may use all the reactors. Roles are only
+# trusted from persistent and unconditional peers: other peers may not use the
+# reactors full nodes may not use, whatever role they advertise.
+role_reactors = "{{ .P2P.RoleReactors }}"
+
 # Comma separated list of peer IDs to keep private (will not be gossiped to other peers)
 private_peer_ids = "{{ .P2P.PrivatePeerIDs }}"
 
 # Toggle to disable guard against peers connecting from the same ip.
 allow_duplicate_ip = {{ .P2P.AllowDuplicateIP }}
 
+# Path to a JSON file listing the CIDR ranges, IP addresses and peer IDs
+# allowed and denied to connect, relative to the home directory, e.g.
+#   {"allow": ["10.0.0.0/8", "<peer ID>"], "deny": ["192.0.2.1"]}
+# A peer is rejected if its IP or its ID is denied and, if any entry is
+# allowed, unless its IP or its ID is allowed. The file is reloaded, and the
+# peers it now rejects disconnected, on SIGHUP and with the
+# unsafe_reload_p2p_access_list RPC call. Empty disables the access list.
+access_list_file = "{{ .P2P.AccessList }}"
+
 # Peer connection configuration.
+# The handshake timeout applies to each stage of the handshake: the secret
+# connection and the node info exchange. The stages are traced in the
+# "handshakes" table.
 handshake_timeout = "{{ .P2P.HandshakeTimeout }}"
 dial_timeout = "{{ .P2P.DialTimeout }}"
 
@@ -371,6 +443,26 @@ recheck = {{ .Mempool.Recheck }}
 # so that the recheck duration is not affected by network delays when making requests and receiving responses.
 recheck_timeout = "{{ .Mempool.RecheckTimeout }}"
 
+# recheck_policy (default: "always") defines when the priority mempool
+# rechecks its transactions:
+#  - "always"         : after every block
+#  - "every_n_blocks" : every recheck_interval blocks
+#  - "never"          : never, same as recheck = false
+#  - "on_demand"      : only when requested through the unsafe_recheck_mempool
+#                       RPC endpoint
+# Only applies to the priority mempool.
+recheck_policy = "{{ .Mempool.RecheckPolicy }}"
+
+# recheck_interval is the number of blocks between two rechecks with the
+# "every_n_blocks" recheck policy.
+recheck_interval = {{ .Mempool.RecheckInterval }}
+
+# recheck_async (default: false) defines whether the priority mempool rechecks
+# its transactions in the background instead of while committing a block.
+# Transactions not rechecked yet may then be reaped for the next block. A
+# recheck still in progress when the next one starts is abandoned.
+recheck_async = {{ .Mempool.RecheckAsync }}
+
 # Broadcast (default: true) defines whether the mempool should relay
 # transactions to other peers. Setting this to false will stop the mempool
 # from relaying transactions to other peers until they are included in a
@@ -384,6 +476,20 @@ broadcast = {{ .Mempool.Broadcast }}
 # "data/mempool.wal").
 wal_dir = "{{ js .Mempool.WalPath }}"
 
+# persist_to_disk (default: false) journals the transactions accepted into
+# the mempool to a write-ahead log in wal_dir, or in data/mempool.wal if
+# wal_dir is unset, and replays them through CheckTx on startup, or once the
+# node caught up if it state or block syncs, so that transactions are not
+# dropped when the node restarts. Only applies to the priority mempool.
+persist_to_disk = {{ .Mempool.PersistToDisk }}
+
+# dump_path (default: "") configures the location of a mempool dump. When
+# set, the priority mempool writes its transactions to the dump every
+# minute and when the node stops, and the inspect command loads the dump
+# and serves its transactions through the mempool_dump endpoint, to
+# analyze what was pending when the node crashed.
+dump_path = "{{ js .Mempool.DumpPath }}"
+
 # Maximum number of transactions in the mempool
 size = {{ .Mempool.Size }}
 
@@ -402,13 +508,73 @@ keep-invalid-txs-in-cache = {{ .Mempool.KeepInvalidTxsInCache }}
 
 # Maximum size of a single transaction.
 # NOTE: the max size of a tx transmitted over the network is {max_tx_bytes}.
+# It is independent of the maximum block size: larger transactions are neither
+# accepted nor gossiped, and the broadcast_tx RPC endpoints reject them with
+# code 1 of the "mempool" codespace.
 max_tx_bytes = {{ .Mempool.MaxTxBytes }}
 
 # Maximum size of a batch of transactions to send to a peer
 # Including space needed by encoding (one varint per transaction).
-# XXX: Unused due to https://github.com/tendermint/tendermint/issues/5796
+# Only applies to the flood and priority mempools. 0 means that transactions
+# are sent one per message. Can't be greater than 1 MiB.
 max_batch_bytes = {{ .Mempool.MaxBatchBytes }}
 
+# Compression of the batches of transactions sent to peers: "zstd", "snappy"
+# or "" for none. Batches are only sent compressed when it makes them smaller,
+# and to the peers supporting the algorithm, as advertised in their node info.
+# Only applies to the flood and priority mempools.
+gossip_compression = "{{ .Mempool.GossipCompression }}"
+
+# gossip_protocol (default: "flood") defines how the priority mempool gossips
+# transactions:
+#  - "flood"     : send them to every peer
+#  - "have_want" : only announce their hashes with SeenTx messages, and send
+#                  them to the peers asking for them with WantTx messages
+# have_want saves bandwidth when peers get transactions from several others.
+# Peers must handle SeenTx and WantTx messages, as the priority and cat mempools
+# do. Only applies to the priority mempool; the cat mempool always uses
+# have/want.
+gossip_protocol = "{{ .Mempool.GossipProtocol }}"
+
+# Maximum number of distinct senders with transactions in the mempool.
+# Transactions from new senders are rejected once the limit is reached,
+# while senders that already have transactions in the mempool are not
+# affected. Only applies to the priority mempool. 0 means unlimited.
+max_senders = {{ .Mempool.MaxSenders }}
+
+# Minimum priority a transaction must be assigned by the application in
+# CheckTx to be admitted. Transactions below the floor are rejected.
+# Only applies to the priority mempool. 0 disables the floor.
+min_priority = {{ .Mempool.MinPriority }}
+
+# Comma separated list of the lanes of the mempool, as
+# name:max_txs:max_txs_bytes triples, such as
+# "blob:1000:8000000,transfer:5000:0". Transactions are assigned to a lane
+# by the "lane" attribute of the "mempool" event of their CheckTx
+# response, and reaped lane by lane in the order given. A quota of 0 means
+# that only the mempool size limits the lane. Only applies to the priority
+# mempool. Empty disables lanes.
+lanes = "{{ .Mempool.Lanes }}"
+
+# If true, the priority mempool orders the transactions of an account by
+# nonce, taking the account and nonce of a transaction from the address and
+# sequence of its CheckTx response. An account may then have several
+# transactions in the mempool, as long as their nonces differ, and they are
+# reaped in nonce order. Only applies to the priority mempool.
+nonce_ordering = {{ .Mempool.NonceOrdering }}
+
+# Maximum number of transactions per second, and of bytes of transactions per
+# second, that a peer may gossip to the mempool. Transactions in
+# excess are dropped. 0 means unlimited.
+peer_max_txs_per_second = {{ .Mempool.PeerMaxTxsPerSecond }}
+peer_max_bytes_per_second = {{ .Mempool.PeerMaxBytesPerSecond }}
+
+# Score at which a peer exceeding the rate limits above is disconnected. The
+# score of a peer is the number of its transactions dropped for exceeding the
+# limits, halved every 10 seconds, so that only sustained abuse is punished.
+# 0 means peers are never disconnected.
+peer_max_rate_violations = {{ .Mempool.PeerMaxRateViolations }}
+
 # ttl-duration, if non-zero, defines the maximum amount of time a transaction
 # can exist for in the mempool.
 #
@@ -442,7 +608,8 @@ experimental_max_gossip_connections_to_non_persistent_peers = {{ .Mempool.Experi
 
 # max-gossip-delay is the maximum allotted time that the reactor expects a transaction to
 # arrive before issuing a new request to a different peer
-# Only applicable to the v2 / CAT mempool
+# Only applicable to the v2 / CAT mempool, and to the priority mempool with the
+# have_want gossip protocol
 # Default is 200ms
 max-gossip-delay = "{{ .Mempool.MaxGossipDelay }}"
 
@@ -472,7 +639,8 @@ trust_period = "{{ .StateSync.TrustPeriod }}"
 discovery_time = "{{ .StateSync.DiscoveryTime }}"
 
 # Temporary directory for state sync snapshot chunks, defaults to the OS tempdir (typically /tmp).
-# Will create a new, randomly named directory within, and remove it when done.
+# Will create a directory named after the snapshot being restored within, and remove it when done.
+# If the node restarts before, the restore resumes from the chunks already fetched in it.
 temp_dir = "{{ .StateSync.TempDir }}"
 
 # The timeout duration before re-requesting a chunk, possibly from a different
@@ -482,6 +650,44 @@ chunk_request_timeout = "{{ .StateSync.ChunkRequestTimeout }}"
 # The number of concurrent chunk fetchers to run (default: 1).
 chunk_fetchers = "{{ .StateSync.ChunkFetchers }}"
 
+# The maximum number of chunk requests outstanding at each peer, so that chunks are fetched
+# from several peers in parallel.
+chunk_requests_per_peer = {{ .StateSync.ChunkRequestsPerPeer }}
+
+# The number of chunk requests which may be retried, with an exponential backoff, once they
+# timed out, before rejecting the snapshot being restored. 0 means no limit.
+chunk_retry_budget = {{ .StateSync.ChunkRetryBudget }}
+
+# Optionally pin the hash of the snapshot at a given height, obtained from a trusted source.
+# Snapshots at trusted_snapshot_height whose hash differs from trusted_snapshot_hash are
+# rejected, regardless of how many peers advertise them. Disabled if the height is 0.
+trusted_snapshot_height = {{ .StateSync.TrustedSnapshotHeight }}
+trusted_snapshot_hash = "{{ .StateSync.TrustedSnapshotHash }}"
+
+# Optionally restore the snapshot exported to a local directory or an http(s) URL, instead of
+# fetching snapshots from peers, e.g. for air-gapped or CDN-based restores. The location must
+# hold the snapshot, as the JSON encoding of an ABCI snapshot, in snapshot.json and its chunks
+# in chunks/<index>. The app hash of the restored app is still verified with the light client.
+local_path = "{{ .StateSync.LocalPath }}"
+
+# The number of blocks, up to the snapshot height, to fetch from peers over block sync after
+# restoring a snapshot, before switching to block sync, instead of leaving a gap below the
+# snapshot height. Should cover the evidence window (evidence.max_age_num_blocks) and the
+# history served over RPC. 0 disables the backfill.
+backfill_blocks = {{ .StateSync.BackfillBlocks }}
+
+# Compute the hashes of the chunks of the local snapshots, loading each snapshot from the app
+# once in the background, and advertise them to peers along the snapshots. Peers restoring the
+# snapshot then verify each chunk against its hash before applying it, and refetch corrupt chunks
+# from other peers instead of having the app reject the whole snapshot. Chunk hashes advertised by
+# peers are always verified, regardless of this setting.
+chunk_hashes = {{ .StateSync.ChunkHashes }}
+
+# The interval at which the requests of peers for the local snapshots, by height and format, are
+# reported to the snapshot usage hook of the application, if it registered one, so that it can
+# prune the snapshots which peers do not use. 0 disables the reports.
+snapshot_usage_interval = "{{ .StateSync.SnapshotUsageInterval }}"
+
 #######################################################
 ###       Block Sync Configuration Options          ###
 #######################################################
@@ -495,6 +701,37 @@ chunk_fetchers = "{{ .StateSync.ChunkFetchers }}"
 #   1) "v0" - the default block sync implementation
 version = "{{ .BlockSync.Version }}"
 
+# Optional source of the blocks which no peer has, e.g. because they are below
+# the base height of every peer after pruning. The blocks are verified against
+# the commit of the next block, like the blocks received from peers.
+#
+#   1) "" - disabled
+#   2) "rpc" - the RPC endpoint of an archival node, e.g. "http://host:26657".
+#      It does not serve extended commits, so it can only provide the blocks
+#      of the heights at which vote extensions were disabled, and the node
+#      refuses to use it on a chain which enables vote extensions.
+#   3) "object_store" - an HTTP object store, holding the block of each height
+#      as a protobuf-encoded tendermint.blocksync.BlockResponse under
+#      <archive_url>/<height>, and the height of the latest block, in decimal,
+#      under <archive_url>/latest.
+archive_source = "{{ .BlockSync.ArchiveSource }}"
+archive_url = "{{ .BlockSync.ArchiveURL }}"
+
+# How long to wait for a peer with the needed blocks, before loading them from
+# the archive.
+archive_delay = "{{ .BlockSync.ArchiveDelay }}"
+
+# RPC servers (comma-separated) of light client witnesses, to cross-check the
+# headers of a sample of the synced blocks against, in the background. If a
+# majority of the witnesses have the same header, different from a synced
+# block, the peers or the witnesses follow a fork, e.g. in a long-range attack,
+# and block sync halts without switching to consensus. Empty to disable.
+witnesses = "{{ StringsJoin .BlockSync.Witnesses "," }}"
+
+# Fraction of the synced blocks to cross-check against the witnesses, between
+# 0 and 1.
+witness_sample_rate = {{ .BlockSync.WitnessSampleRate }}
+
 #######################################################
 ###         Consensus Configuration Options         ###
 #######################################################
@@ -3,6 +3,7 @@ package propagation
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/cosmos/gogoproto/proto"
 
@@ -169,17 +170,23 @@ func (blockProp *Reactor) handleCompactBlock(cb *proptypes.CompactBlock, peer p2
 			return
 		case blockProp.proposalChan <- cb.Proposal:
 		}
-		// check if we have any transactions that are in the compact block
-		go blockProp.recoverPartsFromMempool(cb)
+		// check if we have any transactions that are in the compact block,
+		// and fetch the others from peers
+		go func() {
+			missing := blockProp.recoverPartsFromMempool(cb)
+			blockProp.fetchMissingTxs(cb, missing, peer)
+		}()
 	}
 
 	blockProp.broadcastCompactBlock(cb, peer)
 }
 
 // recoverPartsFromMempool queries the mempool to see if we can recover any block parts locally.
-func (blockProp *Reactor) recoverPartsFromMempool(cb *proptypes.CompactBlock) {
+// It returns the keys of the compact block transactions missing from the mempool.
+func (blockProp *Reactor) recoverPartsFromMempool(cb *proptypes.CompactBlock) []types.TxKey {
 	// find the compact block transactions that exist in our mempool
 	txsFound := make([]proptypes.UnmarshalledTx, 0)
+	var missing []types.TxKey
 	for _, txMetaData := range cb.Blobs {
 		txKey, err := types.TxKeyFromBytes(txMetaData.Hash)
 		if err != nil {
@@ -189,6 +196,7 @@ func (blockProp *Reactor) recoverPartsFromMempool(cb *proptypes.CompactBlock) {
 
 		tx, has := blockProp.mempool.GetTxByKey(txKey)
 		if !has {
+			missing = append(missing, txKey)
 			continue
 		}
 
@@ -203,13 +211,13 @@ func (blockProp *Reactor) recoverPartsFromMempool(cb *proptypes.CompactBlock) {
 	}
 
 	if len(txsFound) == 0 {
-		return
+		return missing
 	}
 
 	parts, err := proptypes.TxsToParts(txsFound, cb.Proposal.BlockID.PartSetHeader.Total, types.BlockPartSizeBytes, cb.LastLen)
 	if err != nil {
 		blockProp.Logger.Error("invalid compact block", "err", err)
-		return
+		return nil
 	}
 
 	_, partSet, _, found := blockProp.getAllState(cb.Proposal.Height, cb.Proposal.Round, false)
@@ -220,13 +228,13 @@ func (blockProp *Reactor) recoverPartsFromMempool(cb *proptypes.CompactBlock) {
 	proofs, err := cb.Proofs()
 	if err != nil {
 		blockProp.Logger.Error("failed to get proofs from compact block", "err", err)
-		return
+		return nil
 	}
 
 	// todo: investigate why this could get hit, it shouldn't ever get hit
 	if partSet == nil {
 		blockProp.Logger.Error("unexpected nil partset while attempting to reuse transactions from the mempool")
-		return
+		return nil
 	}
 
 	originalParts := partSet.Original()
@@ -263,6 +271,35 @@ func (blockProp *Reactor) recoverPartsFromMempool(cb *proptypes.CompactBlock) {
 	if len(haves.Parts) > 0 {
 		blockProp.broadcastHaves(&haves, blockProp.self, int(partSet.Total()))
 	}
+	return missing
+}
+
+// fetchMissingTxs asks the mempool reactor, if it can, to request the
+// transactions of the compact block missing from the mempool from its peers,
+// then tries again to recover block parts from the mempool once they had time
+// to arrive. This saves waiting for the block parts holding them.
+func (blockProp *Reactor) fetchMissingTxs(cb *proptypes.CompactBlock, missing []types.TxKey, peer p2p.ID) {
+	if blockProp.txFetcher == nil || len(missing) == 0 {
+		return
+	}
+	requested := blockProp.txFetcher.FetchTxs(missing, peer)
+	if requested == 0 {
+		return
+	}
+	blockProp.Logger.Debug("fetching proposal transactions missing from the mempool",
+		"height", cb.Proposal.Height, "round", cb.Proposal.Round, "missing", len(missing), "requested", requested)
+
+	select {
+	case <-blockProp.ctx.Done():
+		return
+	case <-time.After(TxFetchDelay):
+	}
+
+	_, partSet, _, found := blockProp.getAllState(cb.Proposal.Height, cb.Proposal.Round, false)
+	if !found || partSet == nil || partSet.IsComplete() {
+		return
+	}
+	blockProp.recoverPartsFromMempool(cb)
 }
 
 // broadcastProposal gossips the provided proposal to all peers. This should
@@ -14,6 +14,7 @@ import (
 	cfg "github.com/cometbft/cometbft/config"
 	proptypes "github.com/cometbft/cometbft/consensus/propagation/types"
 	cmtrand "github.com/cometbft/cometbft/libs/rand"
+	"github.com/cometbft/cometbft/p2p"
 	"github.com/cometbft/cometbft/state"
 	"github.com/cometbft/cometbft/types"
 )
@@ -170,6 +171,98 @@ func TestRecoverPartsLocally(t *testing.T) {
 	}
 }
 
+// TestFetchMissingTxs checks that the transactions of a proposal missing from
+// the mempool are fetched, and block parts recovered from them.
+func TestFetchMissingTxs(t *testing.T) {
+	cleanup, _, sm := state.SetupTestCase(t)
+	t.Cleanup(func() {
+		cleanup(t)
+	})
+	defer func(delay time.Duration) { TxFetchDelay = delay }(TxFetchDelay)
+	TxFetchDelay = time.Millisecond
+
+	numberOfTxs := 10
+	txs := make([]*types.CachedTx, numberOfTxs)
+	fetcher := &mockTxFetcher{
+		mempool: &mockMempool{txs: make(map[types.TxKey]*types.CachedTx)},
+		txs:     make(map[types.TxKey]*types.CachedTx),
+	}
+	for i := 0; i < numberOfTxs; i++ {
+		txs[i] = &types.CachedTx{Tx: cmtrand.Bytes(int(types.BlockPartSizeBytes / 3))}
+		fetcher.txs[txs[i].Key()] = txs[i]
+	}
+
+	blockPropR := NewReactor(
+		"",
+		Config{
+			Store:         store.NewBlockStore(dbm.NewMemDB()),
+			Mempool:       fetcher.mempool,
+			TxFetcher:     fetcher,
+			Privval:       mockPrivVal,
+			ChainID:       sm.ChainID,
+			BlockMaxBytes: sm.ConsensusParams.Block.MaxBytes,
+			PartChan:      make(chan types.PartInfo, 1000),
+			ProposalChan:  make(chan types.Proposal, 100),
+		},
+	)
+
+	data := types.Data{Txs: types.TxsFromCachedTxs(txs)}
+	block, partSet, err := sm.MakeBlock(1, data, types.RandCommit(time.Now()), []types.Evidence{}, cmtrand.Bytes(20))
+	require.NoError(t, err)
+	prop := types.NewProposal(block.Height, 0, -1, types.BlockID{Hash: block.Hash(), PartSetHeader: partSet.Header()})
+	parityBlock, lastLen, err := types.Encode(partSet, types.BlockPartSizeBytes)
+	require.NoError(t, err)
+	metaData := make([]proptypes.TxMetaData, len(partSet.TxPos))
+	for i, pos := range partSet.TxPos {
+		metaData[i] = proptypes.TxMetaData{
+			Start: pos.Start,
+			End:   pos.End,
+			Hash:  block.Txs[i].Hash(),
+		}
+	}
+	cb := &proptypes.CompactBlock{
+		Proposal:    *prop,
+		LastLen:     uint32(lastLen),
+		BpHash:      parityBlock.Hash(),
+		Blobs:       metaData,
+		PartsHashes: extractHashes(partSet, parityBlock),
+	}
+	cb.SetProofCache(extractProofs(partSet, parityBlock))
+	require.True(t, blockPropR.AddProposal(cb))
+
+	// None of the transactions are in the mempool at first.
+	missing := blockPropR.recoverPartsFromMempool(cb)
+	require.Len(t, missing, numberOfTxs)
+
+	blockPropR.fetchMissingTxs(cb, missing, "peer")
+	require.ElementsMatch(t, missing, fetcher.requested)
+
+	_, actualParts, _ := blockPropR.GetProposal(prop.Height, prop.Round)
+	startingPartIndex := metaData[0].Start/types.BlockPartSizeBytes + 1
+	for i := startingPartIndex; i < partSet.Total()-1; i++ {
+		apart := actualParts.GetPart(int(i))
+		require.NotNil(t, apart)
+		assert.Equal(t, partSet.GetPart(int(i)).Bytes, apart.Bytes)
+	}
+}
+
+// mockTxFetcher fetches transactions into a mempool.
+type mockTxFetcher struct {
+	mempool   *mockMempool
+	txs       map[types.TxKey]*types.CachedTx
+	requested []types.TxKey
+}
+
+func (f *mockTxFetcher) FetchTxs(keys []types.TxKey, _ p2p.ID) int {
+	for _, key := range keys {
+		if tx, ok := f.txs[key]; ok {
+			f.mempool.txs[key] = tx
+			f.requested = append(f.requested, key)
+		}
+	}
+	return len(f.requested)
+}
+
 var _ Mempool = &mockMempool{}
 
 type mockMempool struct {
@@ -1,7 +1,19 @@
 package propagation
 
-import "github.com/cometbft/cometbft/types"
+import (
+	"github.com/cometbft/cometbft/p2p"
+	"github.com/cometbft/cometbft/types"
+)
 
 type Mempool interface {
 	GetTxByKey(key types.TxKey) (*types.CachedTx, bool)
 }
+
+// TxFetcher is implemented by the mempool reactors able to request
+// transactions from their peers by hash, such as the CAT mempool reactor.
+type TxFetcher interface {
+	// FetchTxs requests the transactions with the given keys missing from
+	// the mempool from peers, preferably ones that have seen them and
+	// otherwise src, and returns the number of transactions requested.
+	FetchTxs(keys []types.TxKey, src p2p.ID) int
+}
@@ -37,6 +37,10 @@ const (
 
 var RetryTime = 6 * time.Second
 
+// TxFetchDelay is how long to wait for the transactions of a proposal fetched
+// from peers before recovering block parts from the mempool again.
+var TxFetchDelay = 500 * time.Millisecond
+
 type Reactor struct {
 	p2p.BaseReactor // BaseService + p2p.Switch
 
@@ -55,6 +59,10 @@ type Reactor struct {
 	// and eventually remove it.
 	mempool Mempool
 
+	// txFetcher, if set, requests the transactions of proposals missing from
+	// the mempool from peers.
+	txFetcher TxFetcher
+
 	partChan     chan<- types.PartInfo
 	proposalChan chan<- types.Proposal
 
@@ -70,6 +78,7 @@ type Reactor struct {
 type Config struct {
 	Store         *store.BlockStore
 	Mempool       Mempool
+	TxFetcher     TxFetcher // optional
 	Privval       types.PrivValidator
 	ChainID       string
 	BlockMaxBytes int64
@@ -90,6 +99,7 @@ func NewReactor(
 		mtx:           &sync.Mutex{},
 		ProposalCache: NewProposalCache(config.Store),
 		mempool:       config.Mempool,
+		txFetcher:     config.TxFetcher,
 		started:       atomic.Bool{},
 		ctx:           ctx,
 		cancel:        cancel,
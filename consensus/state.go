@@ -1299,11 +1299,11 @@ func (cs *State) defaultDecideProposal(height int64, round int32) {
 
 		metaData := make([]proptypes.TxMetaData, len(block.Txs))
 		hashes := block.CachedHashes()
-		for i, pos := range blockParts.TxPos {
-			metaData[i] = proptypes.TxMetaData{
-				Start: pos.Start,
-				End:   pos.End,
-				Hash:  hashes[i],
+		for ptx := range block.Data.Iter(blockParts.TxPos) {
+			metaData[ptx.Index] = proptypes.TxMetaData{
+				Start: ptx.Position.Start,
+				End:   ptx.Position.End,
+				Hash:  hashes[ptx.Index],
 			}
 		}
 
@@ -1988,15 +1988,15 @@ func (cs *State) recordMetrics(height int64, block *types.Block) {
 		}
 	}
 
-	blockSize := block.Size()
+	blockSize := block.ByteSize()
 
 	// trace some metadata about the block
 	schema.WriteBlockSummary(cs.traceClient, block, blockSize)
 
 	cs.metrics.NumTxs.Set(float64(len(block.Data.Txs)))   //nolint:staticcheck
 	cs.metrics.TotalTxs.Add(float64(len(block.Data.Txs))) //nolint:staticcheck
-	cs.metrics.BlockSizeBytes.Set(float64(block.Size()))
-	cs.metrics.ChainSizeBytes.Add(float64(block.Size()))
+	cs.metrics.BlockSizeBytes.Set(float64(blockSize))
+	cs.metrics.ChainSizeBytes.Add(float64(blockSize))
 	cs.metrics.CommittedHeight.Set(float64(block.Height))
 }
 
@@ -11,6 +11,8 @@ inconsistent state so the node will not be able to start up again.
 
 The RPC endpoints provided by the Inspector type allow for a node operator to inspect
 the block store and state store to better understand what may have caused the inconsistent state.
+If the mempool configuration has a dump_path, the transactions of the mempool dump found there,
+as written by the priority mempool while the node runs, are served by the mempool_dump endpoint.
 
 The Inspector type's lifecycle is controlled by a context.Context
 
@@ -12,6 +12,7 @@ import (
 	"github.com/cometbft/cometbft/inspect/rpc"
 	"github.com/cometbft/cometbft/libs/log"
 	cmtstrings "github.com/cometbft/cometbft/libs/strings"
+	"github.com/cometbft/cometbft/mempool"
 	rpccore "github.com/cometbft/cometbft/rpc/core"
 	"github.com/cometbft/cometbft/state"
 	"github.com/cometbft/cometbft/state/indexer"
@@ -40,6 +41,17 @@ type Inspector struct {
 	// the Inspector to safely close them on shutdown.
 	ss state.Store
 	bs state.BlockStore
+
+	mempoolDump []mempool.DumpedTx
+}
+
+// Option sets an optional parameter on the Inspector.
+type Option func(*Inspector)
+
+// WithMempoolDump sets the transactions of a mempool dump, as written by the
+// priority mempool's DumpToFile, to be served by the mempool_dump endpoint.
+func WithMempoolDump(txs []mempool.DumpedTx) Option {
+	return func(ins *Inspector) { ins.mempoolDump = txs }
 }
 
 // New returns an Inspector that serves RPC on the specified BlockStore and StateStore.
@@ -54,20 +66,25 @@ func New(
 	ss state.Store,
 	txidx txindex.TxIndexer,
 	blkidx indexer.BlockIndexer,
+	options ...Option,
 ) *Inspector {
-	routes := rpc.Routes(*cfg, ss, bs, txidx, blkidx, logger)
-	eb := types.NewEventBus()
-	eb.SetLogger(logger.With("module", "events"))
-	return &Inspector{
-		routes: routes,
+	ins := &Inspector{
 		config: cfg,
 		logger: logger,
 		ss:     ss,
 		bs:     bs,
 	}
+	for _, option := range options {
+		option(ins)
+	}
+	ins.routes = rpc.Routes(*cfg, ss, bs, txidx, blkidx, ins.mempoolDump, logger)
+	eb := types.NewEventBus()
+	eb.SetLogger(logger.With("module", "events"))
+	return ins
 }
 
 // NewFromConfig constructs an Inspector using the values defined in the passed in config.
+// If a mempool dump is configured, it is loaded and served by the mempool_dump endpoint.
 func NewFromConfig(cfg *config.Config) (*Inspector, error) {
 	bsDB, err := config.DefaultDBProvider(&config.DBContext{ID: "blockstore", Config: cfg})
 	if err != nil {
@@ -86,8 +103,16 @@ func NewFromConfig(cfg *config.Config) (*Inspector, error) {
 	if err != nil {
 		return nil, err
 	}
+	var options []Option
+	if cfg.Mempool.DumpEnabled() {
+		txs, err := mempool.ReadDumpFile(cfg.Mempool.DumpFile())
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, WithMempoolDump(txs))
+	}
 	ss := state.NewStore(sDB, state.StoreOptions{})
-	return New(cfg.RPC, bs, ss, txidx, blkidx), nil
+	return New(cfg.RPC, bs, ss, txidx, blkidx, options...), nil
 }
 
 // Run starts the Inspector servers and blocks until the servers shut down. The passed
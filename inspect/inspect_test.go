@@ -19,7 +19,10 @@ import (
 	"github.com/cometbft/cometbft/inspect"
 	"github.com/cometbft/cometbft/internal/test"
 	"github.com/cometbft/cometbft/libs/pubsub/query"
+	"github.com/cometbft/cometbft/mempool"
 	httpclient "github.com/cometbft/cometbft/rpc/client/http"
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	jsonrpcclient "github.com/cometbft/cometbft/rpc/jsonrpc/client"
 	indexermocks "github.com/cometbft/cometbft/state/indexer/mocks"
 	statemocks "github.com/cometbft/cometbft/state/mocks"
 	txindexmocks "github.com/cometbft/cometbft/state/txindex/mocks"
@@ -590,3 +593,61 @@ func requireConnect(t testing.TB, addr string, retries int) {
 	}
 	t.Fatalf("unable to connect to server %s after %d tries: %s", addr, retries, err)
 }
+
+func TestMempoolDump(t *testing.T) {
+	cfg := test.ResetTestRoot("test")
+	defer func() { _ = os.RemoveAll(cfg.RootDir) }()
+
+	dumped := []mempool.DumpedTx{
+		{Tx: types.Tx("tx1"), Priority: 10, GasWanted: 1, Sender: "alice", Height: 3, Timestamp: time.Unix(1, 0).UTC()},
+		{Tx: types.Tx("tx2"), Priority: 5, GasWanted: 2, Height: 4, Timestamp: time.Unix(2, 0).UTC()},
+	}
+	cfg.Mempool.DumpPath = "data/mempool.dump"
+	require.NoError(t, mempool.WriteDumpFile(cfg.Mempool.DumpFile(), dumped))
+
+	d, err := inspect.NewFromConfig(cfg)
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		require.NoError(t, d.Run(ctx))
+	}()
+	requireConnect(t, cfg.RPC.ListenAddress, 20)
+	cli, err := jsonrpcclient.New(cfg.RPC.ListenAddress)
+	require.NoError(t, err)
+	result := new(ctypes.ResultMempoolDump)
+	_, err = cli.Call(context.Background(), "mempool_dump", map[string]interface{}{}, result)
+	require.NoError(t, err)
+	require.Equal(t, len(dumped), result.Count)
+	require.Equal(t, dumped, result.Txs)
+	cancel()
+	wg.Wait()
+}
+
+func TestMempoolDumpNotLoaded(t *testing.T) {
+	stateStoreMock := &statemocks.Store{}
+	stateStoreMock.On("Close").Return(nil)
+	blockStoreMock := &statemocks.BlockStore{}
+	blockStoreMock.On("Close").Return(nil)
+	txIndexerMock := &txindexmocks.TxIndexer{}
+	blkIdxMock := &indexermocks.BlockIndexer{}
+
+	rpcConfig := config.TestRPCConfig()
+	d := inspect.New(rpcConfig, blockStoreMock, stateStoreMock, txIndexerMock, blkIdxMock)
+	ctx, cancel := context.WithCancel(context.Background())
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		require.NoError(t, d.Run(ctx))
+	}()
+	requireConnect(t, rpcConfig.ListenAddress, 20)
+	cli, err := jsonrpcclient.New(rpcConfig.ListenAddress)
+	require.NoError(t, err)
+	_, err = cli.Call(context.Background(), "mempool_dump", map[string]interface{}{}, new(ctypes.ResultMempoolDump))
+	require.Error(t, err)
+	cancel()
+	wg.Wait()
+}
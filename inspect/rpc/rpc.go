@@ -2,6 +2,7 @@ package rpc
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"time"
 
@@ -9,8 +10,11 @@ import (
 
 	"github.com/cometbft/cometbft/config"
 	"github.com/cometbft/cometbft/libs/log"
+	"github.com/cometbft/cometbft/mempool"
 	"github.com/cometbft/cometbft/rpc/core"
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
 	"github.com/cometbft/cometbft/rpc/jsonrpc/server"
+	rpctypes "github.com/cometbft/cometbft/rpc/jsonrpc/types"
 	"github.com/cometbft/cometbft/state"
 	"github.com/cometbft/cometbft/state/indexer"
 	"github.com/cometbft/cometbft/state/txindex"
@@ -24,8 +28,10 @@ type Server struct {
 	Config  *config.RPCConfig
 }
 
-// Routes returns the set of routes used by the Inspector server.
-func Routes(cfg config.RPCConfig, s state.Store, bs state.BlockStore, txidx txindex.TxIndexer, blkidx indexer.BlockIndexer, logger log.Logger) core.RoutesMap { //nolint: lll
+// Routes returns the set of routes used by the Inspector server. The
+// transactions of the mempool dump, if any, are served by the mempool_dump
+// route.
+func Routes(cfg config.RPCConfig, s state.Store, bs state.BlockStore, txidx txindex.TxIndexer, blkidx indexer.BlockIndexer, dump []mempool.DumpedTx, logger log.Logger) core.RoutesMap { //nolint: lll
 	env := &core.Environment{
 		Config:           cfg,
 		BlockIndexer:     blkidx,
@@ -36,21 +42,42 @@ func Routes(cfg config.RPCConfig, s state.Store, bs state.BlockStore, txidx txin
 		Logger:           logger,
 	}
 	return core.RoutesMap{
-		"blockchain":       server.NewRPCFunc(env.BlockchainInfo, "minHeight,maxHeight"),
-		"consensus_params": server.NewRPCFunc(env.ConsensusParams, "height"),
-		"block":            server.NewRPCFunc(env.Block, "height"),
-		"block_by_hash":    server.NewRPCFunc(env.BlockByHash, "hash"),
-		"block_results":    server.NewRPCFunc(env.BlockResults, "height"),
-		"commit":           server.NewRPCFunc(env.Commit, "height"),
-		"header":           server.NewRPCFunc(env.Header, "height"),
-		"header_by_hash":   server.NewRPCFunc(env.HeaderByHash, "hash"),
-		"validators":       server.NewRPCFunc(env.Validators, "height,page,per_page"),
-		"tx":               server.NewRPCFunc(env.Tx, "hash,prove"),
-		"tx_search":        server.NewRPCFunc(env.TxSearch, "query,prove,page,per_page,order_by"),
-		"block_search":     server.NewRPCFunc(env.BlockSearch, "query,page,per_page,order_by"),
+		"blockchain":        server.NewRPCFunc(env.BlockchainInfo, "minHeight,maxHeight"),
+		"consensus_params":  server.NewRPCFunc(env.ConsensusParams, "height"),
+		"block":             server.NewRPCFunc(env.Block, "height"),
+		"block_by_hash":     server.NewRPCFunc(env.BlockByHash, "hash"),
+		"block_results":     server.NewRPCFunc(env.BlockResults, "height"),
+		"commit":            server.NewRPCFunc(env.Commit, "height"),
+		"header":            server.NewRPCFunc(env.Header, "height"),
+		"header_by_hash":    server.NewRPCFunc(env.HeaderByHash, "hash"),
+		"validators":        server.NewRPCFunc(env.Validators, "height,page,per_page"),
+		"tx":                server.NewRPCFunc(env.Tx, "hash,prove"),
+		"tx_search":         server.NewRPCFunc(env.TxSearch, "query,prove,page,per_page,order_by"),
+		"block_search":      server.NewRPCFunc(env.BlockSearch, "query,page,per_page,order_by"),
+		"validator_changes": server.NewRPCFunc(env.ValidatorChanges, "from,to"),
+		"finalize_results":  server.NewRPCFunc(env.FinalizeResults, "height"),
+		"blocks":            server.NewRPCFunc(env.Blocks, "heights"),
+		"block_part":        server.NewRPCFunc(env.BlockPart, "height,index"),
+		"mempool_dump":      server.NewRPCFunc(mempoolDump{txs: dump}.MempoolDump, ""),
 	}
 }
 
+type mempoolDump struct {
+	txs []mempool.DumpedTx
+}
+
+// MempoolDump returns the transactions of the mempool dump loaded by the
+// Inspector, along with their priorities, in the order they were dumped.
+func (d mempoolDump) MempoolDump(*rpctypes.Context) (*ctypes.ResultMempoolDump, error) {
+	if d.txs == nil {
+		return nil, errors.New("no mempool dump loaded")
+	}
+	return &ctypes.ResultMempoolDump{
+		Count: len(d.txs),
+		Txs:   d.txs,
+	}, nil
+}
+
 // Handler returns the http.Handler configured for use with an Inspector server. Handler
 // registers the routes on the http.Handler and also registers the websocket handler
 // and the CORS handler if specified by the configuration options.
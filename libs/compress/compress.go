@@ -0,0 +1,126 @@
+// Package compress compresses the data sent to peers. It is shared by the p2p
+// connections, compressing packets, and the mempool, compressing batches of
+// transactions, so that both use the same algorithms and the same
+// tmp2p.Compression values on the wire.
+package compress
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/klauspost/compress/s2"
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/cometbft/cometbft/config"
+	tmp2p "github.com/cometbft/cometbft/proto/tendermint/p2p"
+)
+
+var (
+	zstdEncoderOnce sync.Once
+	zstdEncoder     *zstd.Encoder
+
+	zstdDecodersMtx sync.Mutex
+	zstdDecoders    = make(map[int]*zstd.Decoder) // by maximum decoded size
+)
+
+// getZstdEncoder returns the encoder shared by all callers. EncodeAll may be
+// called concurrently.
+func getZstdEncoder() *zstd.Encoder {
+	zstdEncoderOnce.Do(func() {
+		var err error
+		zstdEncoder, err = zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedFastest))
+		if err != nil {
+			panic(err)
+		}
+	})
+	return zstdEncoder
+}
+
+// getZstdDecoder returns the decoder shared by all callers decompressing data
+// to at most maxSize bytes. Its window and the memory it uses are limited to
+// maxSize, so that frames declaring huge windows or contents are rejected
+// before anything is allocated for them. DecodeAll may be called concurrently.
+func getZstdDecoder(maxSize int) *zstd.Decoder {
+	zstdDecodersMtx.Lock()
+	defer zstdDecodersMtx.Unlock()
+	if dec, ok := zstdDecoders[maxSize]; ok {
+		return dec
+	}
+	window := uint64(maxSize)
+	if window < zstd.MinWindowSize {
+		window = zstd.MinWindowSize
+	}
+	dec, err := zstd.NewReader(nil,
+		zstd.WithDecoderMaxWindow(window),
+		zstd.WithDecoderMaxMemory(window),
+	)
+	if err != nil {
+		panic(err)
+	}
+	zstdDecoders[maxSize] = dec
+	return dec
+}
+
+// FromConfig returns the compression matching algo, one of the
+// config.P2PCompression values, or COMPRESSION_NONE.
+func FromConfig(algo string) tmp2p.Compression {
+	switch algo {
+	case config.P2PCompressionZstd:
+		return tmp2p.Compression_COMPRESSION_ZSTD
+	case config.P2PCompressionSnappy:
+		return tmp2p.Compression_COMPRESSION_SNAPPY
+	default:
+		return tmp2p.Compression_COMPRESSION_NONE
+	}
+}
+
+// Compress returns data compressed with algo, and algo, or data and
+// COMPRESSION_NONE if compression doesn't make it smaller.
+func Compress(algo tmp2p.Compression, data []byte) ([]byte, tmp2p.Compression) {
+	var compressed []byte
+	switch algo {
+	case tmp2p.Compression_COMPRESSION_ZSTD:
+		compressed = getZstdEncoder().EncodeAll(data, nil)
+	case tmp2p.Compression_COMPRESSION_SNAPPY:
+		compressed = s2.EncodeSnappy(nil, data)
+	default:
+		return data, tmp2p.Compression_COMPRESSION_NONE
+	}
+	if len(compressed) >= len(data) {
+		return data, tmp2p.Compression_COMPRESSION_NONE
+	}
+	return compressed, algo
+}
+
+// Decompress returns data decompressed with algo. It fails if the result takes
+// more than maxSize bytes, to protect against data decompressing to huge
+// sizes.
+func Decompress(algo tmp2p.Compression, data []byte, maxSize int) ([]byte, error) {
+	var out []byte
+	switch algo {
+	case tmp2p.Compression_COMPRESSION_NONE:
+		out = data
+	case tmp2p.Compression_COMPRESSION_ZSTD:
+		var err error
+		if out, err = getZstdDecoder(maxSize).DecodeAll(data, nil); err != nil {
+			return nil, fmt.Errorf("invalid zstd data: %w", err)
+		}
+	case tmp2p.Compression_COMPRESSION_SNAPPY:
+		n, err := s2.DecodedLen(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid snappy data: %w", err)
+		}
+		if n > maxSize {
+			return nil, fmt.Errorf("compressed data of %d bytes exceeds the maximum of %d", n, maxSize)
+		}
+		if out, err = s2.Decode(nil, data); err != nil {
+			return nil, fmt.Errorf("invalid snappy data: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown compression %v", algo)
+	}
+	if len(out) > maxSize {
+		return nil, fmt.Errorf("compressed data exceeds the maximum of %d bytes", maxSize)
+	}
+	return out, nil
+}
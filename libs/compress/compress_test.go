@@ -0,0 +1,62 @@
+package compress
+
+import (
+	"bytes"
+	"crypto/rand"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cometbft/cometbft/config"
+	tmp2p "github.com/cometbft/cometbft/proto/tendermint/p2p"
+)
+
+func TestCompress(t *testing.T) {
+	msg := bytes.Repeat([]byte("block part "), 1000)
+	random := make([]byte, 1000)
+	_, err := rand.Read(random)
+	require.NoError(t, err)
+
+	for _, algo := range []string{config.P2PCompressionZstd, config.P2PCompressionSnappy} {
+		t.Run(algo, func(t *testing.T) {
+			compression := FromConfig(algo)
+			require.NotEqual(t, tmp2p.Compression_COMPRESSION_NONE, compression)
+
+			compressed, used := Compress(compression, msg)
+			require.Equal(t, compression, used)
+			require.Less(t, len(compressed), len(msg))
+
+			decompressed, err := Decompress(used, compressed, len(msg))
+			require.NoError(t, err)
+			require.Equal(t, msg, decompressed)
+
+			// Data decompressing to more than the maximum is rejected.
+			_, err = Decompress(used, compressed, len(msg)-1)
+			require.Error(t, err)
+
+			// Data that doesn't compress is returned as it is.
+			data, used := Compress(compression, random)
+			require.Equal(t, tmp2p.Compression_COMPRESSION_NONE, used)
+			require.Equal(t, random, data)
+		})
+	}
+
+	_, err = Decompress(tmp2p.Compression_COMPRESSION_NONE, msg, len(msg)-1)
+	require.Error(t, err)
+	_, err = Decompress(tmp2p.Compression(42), msg, len(msg))
+	require.Error(t, err)
+}
+
+func TestDecompressHostileZstdFrame(t *testing.T) {
+	// A frame declaring a 512 MiB window, without any content size.
+	frame := []byte{0x28, 0xb5, 0x2f, 0xfd, 0x00, 0x98, 0x09, 0x00, 0x00, 0x61}
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	_, err := Decompress(tmp2p.Compression_COMPRESSION_ZSTD, frame, 1024)
+	runtime.ReadMemStats(&after)
+	require.Error(t, err)
+	// The frame is rejected before a window is allocated for it.
+	require.Less(t, after.TotalAlloc-before.TotalAlloc, uint64(1<<20))
+}
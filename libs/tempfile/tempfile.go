@@ -33,6 +33,13 @@ const (
 var (
 	atomicWriteFileRand   uint64
 	atomicWriteFileRandMu cmtsync.Mutex
+
+	// appendFileMu serializes AppendToFileSync calls within the process.
+	appendFileMu cmtsync.Mutex
+
+	// syncFile flushes f to stable storage. It is a variable so tests can
+	// observe when data is synced.
+	syncFile = func(f *os.File) error { return f.Sync() }
 )
 
 func writeFileRandReseed() uint64 {
@@ -127,3 +134,85 @@ func WriteFileAtomic(filename string, data []byte, perm os.FileMode) (err error)
 
 	return os.Rename(f.Name(), filename)
 }
+
+// AppendToFileSync appends data to filename, creating it with 0600 permissions
+// if it does not exist, and fsyncs the file before returning. Unlike
+// WriteFileAtomic, the file is not replaced. Concurrent calls within the
+// process are serialized.
+func AppendToFileSync(filename string, data []byte) (err error) {
+	appendFileMu.Lock()
+	defer appendFileMu.Unlock()
+
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	if n, err := f.Write(data); err != nil {
+		return err
+	} else if n < len(data) {
+		return io.ErrShortWrite
+	}
+	return syncFile(f)
+}
+
+// ReplaceFileContents atomically replaces the contents of filename with data
+// and returns a rollback function restoring the previous contents. The
+// previous contents are saved to a temporary backup file next to filename,
+// which is removed by rollback. If filename did not exist, rollback removes
+// it.
+func ReplaceFileContents(filename string, data []byte, perm os.FileMode) (rollback func() error, err error) {
+	var backup string
+	stat, err := os.Stat(filename)
+	switch {
+	case os.IsNotExist(err):
+		// nothing to back up
+	case err != nil:
+		return nil, err
+	default:
+		old, err := os.ReadFile(filename)
+		if err != nil {
+			return nil, err
+		}
+		f, err := os.CreateTemp(filepath.Dir(filename), filepath.Base(filename)+".bak-")
+		if err != nil {
+			return nil, err
+		}
+		backup = f.Name()
+		f.Close()
+		if err := WriteFileAtomic(backup, old, stat.Mode().Perm()); err != nil {
+			os.Remove(backup)
+			return nil, err
+		}
+	}
+
+	if err := WriteFileAtomic(filename, data, perm); err != nil {
+		if backup != "" {
+			os.Remove(backup)
+		}
+		return nil, err
+	}
+
+	rollback = func() error {
+		if backup == "" {
+			if err := os.Remove(filename); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			return nil
+		}
+		old, err := os.ReadFile(backup)
+		if err != nil {
+			return err
+		}
+		if err := WriteFileAtomic(filename, old, stat.Mode().Perm()); err != nil {
+			return err
+		}
+		return os.Remove(backup)
+	}
+	return rollback, nil
+}
@@ -6,6 +6,7 @@ import (
 	"bytes"
 	"fmt"
 	"os"
+	"path/filepath"
 	testing "testing"
 
 	"github.com/stretchr/testify/require"
@@ -141,3 +142,64 @@ func TestWriteFileAtomicManyDuplicates(t *testing.T) {
 	require.Nil(t, err, "Error reading resultant file")
 	require.Equal(t, []byte(expectedString), resultantFileBytes, "Written file had incorrect bytes")
 }
+
+func TestAppendToFileSync(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "wal")
+
+	var synced []int
+	defer func(orig func(*os.File) error) { syncFile = orig }(syncFile)
+	syncFile = func(f *os.File) error {
+		stat, err := f.Stat()
+		if err != nil {
+			return err
+		}
+		synced = append(synced, int(stat.Size()))
+		return f.Sync()
+	}
+
+	records := [][]byte{[]byte("first\n"), []byte("second\n"), []byte("third\n")}
+	var expected []byte
+	var expectedSynced []int
+	for _, rec := range records {
+		require.NoError(t, AppendToFileSync(name, rec))
+		expected = append(expected, rec...)
+		expectedSynced = append(expectedSynced, len(expected))
+	}
+
+	data, err := os.ReadFile(name)
+	require.NoError(t, err)
+	require.Equal(t, expected, data)
+	// every append must have been synced after its bytes were written
+	require.Equal(t, expectedSynced, synced)
+}
+
+func TestReplaceFileContents(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "log")
+	require.NoError(t, os.WriteFile(name, []byte("original"), 0600))
+
+	rollback, err := ReplaceFileContents(name, []byte("replaced"), 0600)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(name)
+	require.NoError(t, err)
+	require.Equal(t, []byte("replaced"), data)
+
+	require.NoError(t, rollback())
+
+	data, err = os.ReadFile(name)
+	require.NoError(t, err)
+	require.Equal(t, []byte("original"), data)
+
+	// the backup file must have been cleaned up
+	entries, err := os.ReadDir(filepath.Dir(name))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	// rolling back the creation of a new file removes it
+	newName := filepath.Join(filepath.Dir(name), "new")
+	rollback, err = ReplaceFileContents(newName, []byte("data"), 0600)
+	require.NoError(t, err)
+	require.NoError(t, rollback())
+	_, err = os.Stat(newName)
+	require.True(t, os.IsNotExist(err))
+}
@@ -1,6 +1,10 @@
 package schema
 
-import "github.com/cometbft/cometbft/libs/trace"
+import (
+	"time"
+
+	"github.com/cometbft/cometbft/libs/trace"
+)
 
 // P2PTables returns the list of tables that are used for p2p tracing.
 func P2PTables() []string {
@@ -8,6 +12,7 @@ func P2PTables() []string {
 		PeersTable,
 		PendingBytesTable,
 		ReceivedBytesTable,
+		HandshakesTable,
 	}
 }
 
@@ -80,3 +85,47 @@ func (ReceivedBytes) Table() string {
 func WriteReceivedBytes(client trace.Tracer, peerID string, channel byte, bytes int) {
 	client.Write(ReceivedBytes{PeerID: peerID, Channel: channel, Bytes: bytes})
 }
+
+const (
+	// HandshakesTable is the name of the table that stores the stages of the
+	// handshakes with peers.
+	HandshakesTable = "handshakes"
+)
+
+// Handshake describes schema for the "handshakes" table, each entry being a
+// stage of the handshake with a peer. The stages of a handshake share its
+// trace ID, which is also the one of the rejection of the peer, if any.
+type Handshake struct {
+	TraceID    string `json:"trace_id"`
+	Stage      string `json:"stage"`
+	PeerID     string `json:"peer_id"`
+	RemoteAddr string `json:"remote_addr"`
+	Outbound   bool   `json:"outbound"`
+	DurationMs int64  `json:"duration_ms"`
+	Error      string `json:"error"`
+}
+
+// Table returns the table name for the Handshake struct.
+func (Handshake) Table() string {
+	return HandshakesTable
+}
+
+// WriteHandshake writes a tracing point for a stage of the handshake with a
+// peer, err being empty if the stage succeeded.
+func WriteHandshake(
+	client trace.Tracer,
+	traceID, stage, peerID, remoteAddr string,
+	outbound bool,
+	duration time.Duration,
+	err string,
+) {
+	client.Write(Handshake{
+		TraceID:    traceID,
+		Stage:      stage,
+		PeerID:     peerID,
+		RemoteAddr: remoteAddr,
+		Outbound:   outbound,
+		DurationMs: duration.Milliseconds(),
+		Error:      err,
+	})
+}
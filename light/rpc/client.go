@@ -209,6 +209,10 @@ func (c *Client) NumUnconfirmedTxs(ctx context.Context) (*ctypes.ResultUnconfirm
 	return c.next.NumUnconfirmedTxs(ctx)
 }
 
+func (c *Client) MempoolContents(ctx context.Context, limit *int) (*ctypes.ResultMempoolContents, error) {
+	return c.next.MempoolContents(ctx, limit)
+}
+
 func (c *Client) CheckTx(ctx context.Context, tx types.Tx) (*ctypes.ResultCheckTx, error) {
 	return c.next.CheckTx(ctx, tx)
 }
@@ -258,6 +262,10 @@ func (c *Client) Health(ctx context.Context) (*ctypes.ResultHealth, error) {
 	return c.next.Health(ctx)
 }
 
+func (c *Client) StateSyncStatus(ctx context.Context) (*ctypes.ResultStateSyncStatus, error) {
+	return c.next.StateSyncStatus(ctx)
+}
+
 // BlockchainInfo calls rpcclient#BlockchainInfo and then verifies every header
 // returned.
 func (c *Client) BlockchainInfo(ctx context.Context, minHeight, maxHeight int64) (*ctypes.ResultBlockchainInfo, error) {
@@ -0,0 +1,114 @@
+package mempool
+
+import (
+	"github.com/cosmos/gogoproto/proto"
+
+	cfg "github.com/cometbft/cometbft/config"
+	"github.com/cometbft/cometbft/libs/compress"
+	"github.com/cometbft/cometbft/p2p"
+	protomem "github.com/cometbft/cometbft/proto/tendermint/mempool"
+	tmp2p "github.com/cometbft/cometbft/proto/tendermint/p2p"
+)
+
+// MaxTxsMessageSize returns the maximum size of a mempool message carrying
+// transactions: one transaction of the maximum size, or a batch of the
+// protocol-level maximum of cfg.MaxMempoolBatchBytes of transactions, as peers
+// may batch more transactions than our own MaxBatchBytes.
+func MaxTxsMessageSize(config *cfg.MempoolConfig) int {
+	largestTx := make([]byte, config.MaxTxBytes)
+	msg := protomem.Message{
+		Sum: &protomem.Message_Txs{
+			Txs: &protomem.Txs{Txs: [][]byte{largestTx}},
+		},
+	}
+	size := msg.Size()
+	if batch := 1 + proto.SizeVarint(cfg.MaxMempoolBatchBytes) + cfg.MaxMempoolBatchBytes; batch > size {
+		size = batch
+	}
+	return size
+}
+
+// PeerCompression returns compression, one of the cfg.MempoolCompression
+// values, if the peer advertises it supports it in its node info, and ""
+// otherwise, as peers which don't may not know CompressedTxs messages.
+func PeerCompression(peer p2p.Peer, compression string) string {
+	if compression == "" {
+		return ""
+	}
+	if ni, ok := peer.NodeInfo().(p2p.DefaultNodeInfo); ok && ni.SupportsCompression(compression) {
+		return compression
+	}
+	return ""
+}
+
+// TxBatch coalesces transactions to be gossiped to a peer in a single message.
+type TxBatch struct {
+	maxBytes int
+	size     int // size of the encoded Txs message
+	txs      [][]byte
+}
+
+// NewTxBatch returns an empty batch of up to maxBytes of encoded transactions.
+// The first transaction is always accepted, whatever its size, so that a
+// maxBytes of 0 makes batches of a single transaction.
+func NewTxBatch(maxBytes int) *TxBatch {
+	return &TxBatch{maxBytes: maxBytes}
+}
+
+// Add adds tx to the batch and returns true, unless it would make the batch
+// exceed its maximum size.
+func (b *TxBatch) Add(tx []byte) bool {
+	size := 1 + proto.SizeVarint(uint64(len(tx))) + len(tx)
+	if len(b.txs) > 0 && b.size+size > b.maxBytes {
+		return false
+	}
+	b.txs = append(b.txs, tx)
+	b.size += size
+	return true
+}
+
+// Len returns the number of transactions in the batch.
+func (b *TxBatch) Len() int {
+	return len(b.txs)
+}
+
+// Message returns the message gossiping the batch: a CompressedTxs message if
+// compression, one of the cfg.MempoolCompression values, is set and makes the
+// message smaller, and a Txs message otherwise.
+func (b *TxBatch) Message(compression string) proto.Message {
+	txs := &protomem.Txs{Txs: b.txs}
+
+	algo := compress.FromConfig(compression)
+	if algo == tmp2p.Compression_COMPRESSION_NONE {
+		return txs
+	}
+	raw, err := txs.Marshal()
+	if err != nil {
+		return txs
+	}
+	data, used := compress.Compress(algo, raw)
+	if used == tmp2p.Compression_COMPRESSION_NONE {
+		return txs
+	}
+	ctxs := &protomem.CompressedTxs{Compression: used, Data: data}
+	if ctxs.Size() >= len(raw) {
+		return txs
+	}
+	return ctxs
+}
+
+// DecompressTxs returns the transactions of a CompressedTxs message. It fails
+// if they take more than maxSize bytes once decompressed, to protect against
+// messages decompressing to huge sizes.
+func DecompressTxs(msg *protomem.CompressedTxs, maxSize int) ([][]byte, error) {
+	raw, err := compress.Decompress(msg.Compression, msg.Data, maxSize)
+	if err != nil {
+		return nil, err
+	}
+
+	var txs protomem.Txs
+	if err := txs.Unmarshal(raw); err != nil {
+		return nil, err
+	}
+	return txs.Txs, nil
+}
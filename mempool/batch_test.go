@@ -0,0 +1,115 @@
+package mempool
+
+import (
+	"bytes"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	cfg "github.com/cometbft/cometbft/config"
+	"github.com/cometbft/cometbft/p2p"
+	"github.com/cometbft/cometbft/p2p/mocks"
+	protomem "github.com/cometbft/cometbft/proto/tendermint/mempool"
+	tmp2p "github.com/cometbft/cometbft/proto/tendermint/p2p"
+)
+
+func TestTxBatch(t *testing.T) {
+	tx := bytes.Repeat([]byte{'a'}, 100) // 102 bytes once encoded
+
+	// A batch of 0 bytes holds a single transaction.
+	b := NewTxBatch(0)
+	require.True(t, b.Add(tx))
+	require.False(t, b.Add(tx))
+	require.Equal(t, 1, b.Len())
+
+	b = NewTxBatch(250)
+	require.True(t, b.Add(tx))
+	require.True(t, b.Add(tx))
+	require.False(t, b.Add(tx))
+	require.True(t, b.Add([]byte("tx")))
+	require.Equal(t, 3, b.Len())
+
+	msg, ok := b.Message("").(*protomem.Txs)
+	require.True(t, ok)
+	require.Equal(t, [][]byte{tx, tx, []byte("tx")}, msg.Txs)
+	require.LessOrEqual(t, msg.Size(), 250)
+}
+
+func TestTxBatchCompression(t *testing.T) {
+	maxSize := MaxTxsMessageSize(cfg.DefaultMempoolConfig())
+	for _, compression := range []string{cfg.MempoolCompressionZstd, cfg.MempoolCompressionSnappy} {
+		t.Run(compression, func(t *testing.T) {
+			b := NewTxBatch(10_000)
+			var txs [][]byte
+			for i := 0; i < 50; i++ {
+				tx := bytes.Repeat([]byte{byte('a' + i%26)}, 100)
+				require.True(t, b.Add(tx))
+				txs = append(txs, tx)
+			}
+
+			msg, ok := b.Message(compression).(*protomem.CompressedTxs)
+			require.True(t, ok)
+			require.Less(t, msg.Size(), (&protomem.Txs{Txs: txs}).Size())
+			decompressed, err := DecompressTxs(msg, maxSize)
+			require.NoError(t, err)
+			require.Equal(t, txs, decompressed)
+
+			// Txs larger than allowed once decompressed are rejected.
+			_, err = DecompressTxs(msg, 1000)
+			require.Error(t, err)
+
+			// Incompressible txs are sent uncompressed.
+			b = NewTxBatch(0)
+			b.Add([]byte{0x8f, 0x12, 0xe4})
+			_, ok = b.Message(compression).(*protomem.Txs)
+			require.True(t, ok)
+		})
+	}
+
+	_, err := DecompressTxs(&protomem.CompressedTxs{
+		Compression: tmp2p.Compression_COMPRESSION_ZSTD,
+		Data:        []byte("not zstd"),
+	}, maxSize)
+	require.Error(t, err)
+}
+
+func TestDecompressTxsOversizedWindow(t *testing.T) {
+	// A zstd frame declaring a 512 MiB window, far above the maximum size of
+	// the message.
+	msg := &protomem.CompressedTxs{
+		Compression: tmp2p.Compression_COMPRESSION_ZSTD,
+		Data:        []byte{0x28, 0xb5, 0x2f, 0xfd, 0x00, 0x98, 0x09, 0x00, 0x00, 0x61},
+	}
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	_, err := DecompressTxs(msg, MaxTxsMessageSize(cfg.DefaultMempoolConfig()))
+	runtime.ReadMemStats(&after)
+	require.Error(t, err)
+	require.Less(t, after.TotalAlloc-before.TotalAlloc, uint64(64<<20))
+}
+
+func TestMaxTxsMessageSize(t *testing.T) {
+	// The capacity doesn't depend on the batches we send.
+	config := cfg.TestMempoolConfig()
+	size := MaxTxsMessageSize(config)
+	config.MaxBatchBytes = 0
+	require.Equal(t, size, MaxTxsMessageSize(config))
+	require.Greater(t, size, cfg.MaxMempoolBatchBytes)
+}
+
+func TestPeerCompression(t *testing.T) {
+	peer := &mocks.Peer{}
+	peer.On("NodeInfo").Return(p2p.DefaultNodeInfo{
+		Other: p2p.DefaultNodeInfoOther{Compression: cfg.MempoolCompressionSnappy},
+	})
+	require.Equal(t, cfg.MempoolCompressionSnappy, PeerCompression(peer, cfg.MempoolCompressionSnappy))
+	require.Empty(t, PeerCompression(peer, cfg.MempoolCompressionZstd))
+	require.Empty(t, PeerCompression(peer, ""))
+
+	// Peers which don't advertise compression are sent plain messages.
+	peer = &mocks.Peer{}
+	peer.On("NodeInfo").Return(p2p.DefaultNodeInfo{})
+	require.Empty(t, PeerCompression(peer, cfg.MempoolCompressionZstd))
+}
@@ -70,6 +70,9 @@ type TxPool struct {
 	// Store of wrapped transactions
 	store *store
 
+	// evictions, set by WithEvictionCallback. Immutable after construction.
+	evictions *mempool.EvictionQueue
+
 	// broadcastCh is an unbuffered channel of new transactions that need to
 	// be broadcasted to peers. Only populated if `broadcast` in the config is enabled
 	broadcastCh      chan *wrappedTx
@@ -128,6 +131,15 @@ func WithMetrics(metrics *mempool.Metrics) TxPoolOption {
 	return func(txmp *TxPool) { txmp.metrics = metrics }
 }
 
+// WithEvictionCallback sets a function called with each transaction leaving
+// the mempool without being committed, and the reason why, one of the
+// types.TxEvicted values. It is called in order from a separate goroutine, not
+// to hold the mempool lock while it runs; evictions are dropped while as many
+// as the size of the mempool are pending.
+func WithEvictionCallback(fn func(types.EventDataTxEvicted)) TxPoolOption {
+	return func(txmp *TxPool) { txmp.evictions = mempool.NewEvictionQueue(fn, txmp.config.Size) }
+}
+
 // Lock locks the mempool, no new transactions can be processed
 func (txmp *TxPool) Lock() {
 	txmp.mtx.Lock()
@@ -218,6 +230,7 @@ func (txmp *TxPool) CheckToPurgeExpiredTxs() {
 		// Add the purged transactions to the evicted cache
 		for _, tx := range purgedTxs {
 			txmp.evictedTxCache.Push(tx.key())
+			txmp.notifyEvicted(tx.tx, types.TxEvictedExpired)
 		}
 		txmp.metrics.EvictedTxs.Add(float64(numExpired))
 		txmp.lastPurgeTime = time.Now()
@@ -546,6 +559,7 @@ func (txmp *TxPool) addNewTransaction(wtx *wrappedTx) error {
 		if len(victims) == 0 || victimBytes < wtx.size() {
 			txmp.metrics.EvictedTxs.Add(1)
 			txmp.evictedTxCache.Push(wtx.key())
+			txmp.notifyEvicted(wtx.tx, types.TxEvictedMempoolFull)
 			return fmt.Errorf("rejected valid incoming transaction; mempool is full (%X). Size: (%d:%d)",
 				wtx.key().String(), txmp.Size(), txmp.SizeBytes())
 		}
@@ -605,6 +619,15 @@ func (txmp *TxPool) evictTx(wtx *wrappedTx) {
 		"old_tx", fmt.Sprintf("%X", wtx.key()),
 		"old_priority", wtx.priority,
 	)
+	txmp.notifyEvicted(wtx.tx, types.TxEvictedMempoolFull)
+}
+
+// notifyEvicted queues the eviction of tx for the given reason for the
+// eviction callback, if any.
+func (txmp *TxPool) notifyEvicted(tx *types.CachedTx, reason string) {
+	if txmp.evictions != nil && !txmp.evictions.Push(types.EventDataTxEvicted{Tx: tx.Tx, Reason: reason}) {
+		txmp.logger.Error("dropped eviction notification; too many pending", "tx", fmt.Sprintf("%X", tx.Key()), "reason", reason)
+	}
 }
 
 // handleRecheckResult handles the responses from ABCI CheckTx calls issued
@@ -634,6 +657,7 @@ func (txmp *TxPool) handleRecheckResult(wtx *wrappedTx, checkTxRes *abci.Respons
 	)
 	txmp.store.remove(wtx.key())
 	txmp.rejectedTxCache.Push(wtx.key())
+	txmp.notifyEvicted(wtx.tx, types.TxEvictedRecheckFailed)
 	txmp.metrics.FailedTxs.Add(1)
 	txmp.metrics.Size.Set(float64(txmp.Size()))
 	txmp.metrics.SizeBytes.Set(float64(txmp.SizeBytes()))
@@ -723,6 +747,7 @@ func (txmp *TxPool) purgeExpiredTxs(blockHeight int64) {
 	// Add the purged transactions to the evicted cache
 	for _, tx := range purgedTxs {
 		txmp.evictedTxCache.Push(tx.key())
+		txmp.notifyEvicted(tx.tx, types.TxEvictedExpired)
 	}
 	txmp.metrics.ExpiredTxs.Add(float64(numExpired))
 
@@ -323,6 +323,41 @@ func TestTxPool_RecheckConcurrency(t *testing.T) {
 	require.NoError(t, txmp.CheckTx(newDefaultTx("final-test"), nil, mempool.TxInfo{}))
 }
 
+func TestTxPool_EvictionCallback(t *testing.T) {
+	evictions := make(chan types.EventDataTxEvicted, 10)
+	txmp := setup(t, 1000, WithEvictionCallback(func(data types.EventDataTxEvicted) {
+		evictions <- data
+	}))
+	txmp.config.Size = 1 // which holds two transactions
+	txmp.config.MaxTxsBytes = 1000
+	evicted := func() types.EventDataTxEvicted {
+		select {
+		case data := <-evictions:
+			return data
+		case <-time.After(time.Second):
+			require.FailNow(t, "eviction not notified")
+			return types.EventDataTxEvicted{}
+		}
+	}
+
+	// A full mempool evicts lower-priority transactions, or the incoming one.
+	mustCheckTx(t, txmp, "key1=0000=5")
+	mustCheckTx(t, txmp, "key2=0001=3")
+	mustCheckTx(t, txmp, "key3=0002=7")
+	require.Equal(t, types.EventDataTxEvicted{Tx: types.Tx("key2=0001=3"), Reason: types.TxEvictedMempoolFull}, evicted())
+	require.Error(t, txmp.CheckTx(types.Tx("key4=0003=1"), nil, mempool.TxInfo{}))
+	require.Equal(t, types.EventDataTxEvicted{Tx: types.Tx("key4=0003=1"), Reason: types.TxEvictedMempoolFull}, evicted())
+
+	// Expired transactions are reported as such.
+	txmp.config.TTLNumBlocks = 1
+	txmp.Lock()
+	require.NoError(t, txmp.Update(txmp.height+2, nil, nil, nil, nil))
+	txmp.Unlock()
+	for i := 0; i < 2; i++ {
+		require.Equal(t, types.TxEvictedExpired, evicted().Reason)
+	}
+}
+
 func TestTxPool_Eviction(t *testing.T) {
 	txmp := setup(t, 1000)
 	txmp.config.Size = 5
@@ -41,6 +41,7 @@ type Reactor struct {
 	mempool     *TxPool
 	ids         *mempoolIDs
 	requests    *requestScheduler
+	limiter     *mempool.PeerRateLimiter
 	traceClient trace.Tracer
 }
 
@@ -81,16 +82,17 @@ func (opts *ReactorOptions) VerifyAndComplete() error {
 }
 
 // NewReactor returns a new Reactor with the given config and mempool.
-func NewReactor(mempool *TxPool, opts *ReactorOptions) (*Reactor, error) {
+func NewReactor(txmp *TxPool, opts *ReactorOptions) (*Reactor, error) {
 	err := opts.VerifyAndComplete()
 	if err != nil {
 		return nil, err
 	}
 	memR := &Reactor{
 		opts:        opts,
-		mempool:     mempool,
+		mempool:     txmp,
 		ids:         newMempoolIDs(),
 		requests:    newRequestScheduler(opts.MaxGossipDelay, defaultGlobalRequestTimeout),
+		limiter:     mempool.NewPeerRateLimiter(txmp.config),
 		traceClient: trace.NoOpTracer(),
 	}
 	memR.BaseReactor = *p2p.NewBaseReactor("Mempool", memR)
@@ -199,6 +201,7 @@ func (memR *Reactor) RemovePeer(peer p2p.Peer, reason interface{}) {
 	peerID := memR.ids.Reclaim(peer.ID())
 	// clear all memory of seen txs by that peer
 	memR.mempool.seenByPeersSet.RemovePeer(peerID)
+	memR.limiter.RemovePeer(peer.ID())
 
 	// remove and rerequest all pending outbound requests to that peer since we know
 	// we won't receive any responses from them.
@@ -228,11 +231,19 @@ func (memR *Reactor) Receive(e p2p.Envelope) {
 		txInfo := mempool.TxInfo{SenderID: peerID}
 		txInfo.SenderP2PID = e.Src.ID()
 
-		var err error
 		for _, tx := range protoTxs {
 			ntx := types.Tx(tx)
 			key := ntx.Key()
 			schema.WriteMempoolTx(memR.traceClient, string(e.Src.ID()), key[:], len(tx), schema.Download)
+			ok, err := memR.limiter.Allow(e.Src.ID(), len(tx))
+			if err != nil {
+				memR.Switch.StopPeerForError(e.Src, err, memR.String())
+				return
+			}
+			if !ok {
+				memR.Logger.Debug("dropped tx exceeding the peer rate limits", "peerID", peerID, "txKey", key)
+				continue
+			}
 			// If we requested the transaction we mark it as received.
 			if memR.requests.Has(peerID, key) {
 				memR.requests.MarkReceived(peerID, key)
@@ -481,3 +492,30 @@ func (memR *Reactor) findNewPeerToRequestTx(txKey types.TxKey) {
 		memR.requestTx(txKey, peer)
 	}
 }
+
+// FetchTxs requests the transactions with the given keys that are neither in
+// the mempool nor already requested, each from a peer that has seen it or,
+// failing that, from the src peer. It is used by the block propagation reactor
+// to fetch the transactions of a proposal missing from the mempool without
+// waiting for the block parts holding them. It returns the number of
+// transactions requested.
+func (memR *Reactor) FetchTxs(keys []types.TxKey, src p2p.ID) int {
+	requested := 0
+	for _, key := range keys {
+		if memR.mempool.Has(key) || memR.mempool.WasRecentlyRejected(key) || memR.requests.ForTx(key) != 0 {
+			continue
+		}
+		peerID := memR.ids.GetIDForPeer(src)
+		for seenBy := range memR.mempool.seenByPeersSet.Get(key) {
+			peerID = seenBy
+			break
+		}
+		peer := memR.ids.GetPeer(peerID)
+		if peer == nil {
+			continue
+		}
+		memR.requestTx(key, peer)
+		requested++
+	}
+	return requested
+}
@@ -159,6 +159,31 @@ func TestReactorBroadcastsSeenTxAfterReceivingTx(t *testing.T) {
 	peers[1].AssertExpectations(t)
 }
 
+func TestReactorRateLimitsPeers(t *testing.T) {
+	app := &application{kvstore.NewApplication(db.NewMemDB())}
+	cc := proxy.NewLocalClientCreator(app)
+	conf := test.ResetTestRoot("mempool_test")
+	conf.Mempool.PeerMaxTxsPerSecond = 1
+	pool, cleanup := newMempoolWithAppAndConfig(cc, conf)
+	t.Cleanup(cleanup)
+	reactor, err := NewReactor(pool, &ReactorOptions{})
+	require.NoError(t, err)
+
+	peer := genPeer()
+	reactor.InitPeer(peer)
+	reactor.Receive(
+		p2p.Envelope{
+			ChannelID: mempool.MempoolChannel,
+			Message:   &protomem.Txs{Txs: [][]byte{newDefaultTx("hello"), newDefaultTx("world")}},
+			Src:       peer,
+		},
+	)
+
+	// The second transaction exceeds the rate limit of the peer and is dropped.
+	require.Equal(t, 1, pool.Size())
+	require.True(t, pool.Has(newDefaultTx("hello").Key()))
+}
+
 func TestRemovePeerRequestFromOtherPeer(t *testing.T) {
 	reactor, _ := setupReactor(t)
 
@@ -204,6 +229,42 @@ func TestRemovePeerRequestFromOtherPeer(t *testing.T) {
 	require.False(t, reactor.mempool.seenByPeersSet.Has(key, 1))
 }
 
+func TestReactorFetchTxs(t *testing.T) {
+	reactor, pool := setupReactor(t)
+
+	peers := genPeers(2)
+	reactor.InitPeer(peers[0])
+	reactor.InitPeer(peers[1])
+
+	// The mempool has the first transaction, the second peer has seen the
+	// second one, and no peer has seen the third one.
+	txs := []types.Tx{newDefaultTx("have"), newDefaultTx("seen"), newDefaultTx("unseen")}
+	require.NoError(t, pool.CheckTx(txs[0], nil, mempool.TxInfo{}))
+	pool.PeerHasTx(2, txs[1].Key())
+
+	wantEnv := func(tx types.Tx) p2p.Envelope {
+		key := tx.Key()
+		return p2p.Envelope{
+			ChannelID: MempoolWantsChannel,
+			Message: &protomem.Message{
+				Sum: &protomem.Message_WantTx{WantTx: &protomem.WantTx{TxKey: key[:]}},
+			},
+		}
+	}
+	peers[1].On("Send", wantEnv(txs[1])).Return(true).Once()
+	peers[0].On("Send", wantEnv(txs[2])).Return(true).Once()
+
+	keys := []types.TxKey{txs[0].Key(), txs[1].Key(), txs[2].Key()}
+	require.Equal(t, 2, reactor.FetchTxs(keys, peers[0].ID()))
+	peers[0].AssertExpectations(t)
+	peers[1].AssertExpectations(t)
+	require.True(t, reactor.requests.Has(2, txs[1].Key()))
+	require.True(t, reactor.requests.Has(1, txs[2].Key()))
+
+	// Transactions already requested aren't requested again.
+	require.Zero(t, reactor.FetchTxs(keys, peers[0].ID()))
+}
+
 func TestMempoolVectors(t *testing.T) {
 	testCases := []struct {
 		testName string
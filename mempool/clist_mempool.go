@@ -260,11 +260,6 @@ func (mem *CListMempool) CheckTx(
 
 	txSize := len(tx)
 
-	if err := mem.isFull(txSize); err != nil {
-		mem.metrics.RejectedTxs.Add(1)
-		return err
-	}
-
 	if txSize > mem.config.MaxTxBytes {
 		return ErrTxTooLarge{
 			Max:    mem.config.MaxTxBytes,
@@ -272,6 +267,11 @@ func (mem *CListMempool) CheckTx(
 		}
 	}
 
+	if err := mem.isFull(txSize); err != nil {
+		mem.metrics.RejectedTxs.Add(1)
+		return err
+	}
+
 	cachedTx := tx.ToCachedTx()
 	if mem.preCheck != nil {
 		if err := mem.preCheck(cachedTx); err != nil {
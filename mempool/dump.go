@@ -0,0 +1,50 @@
+package mempool
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	cmtjson "github.com/cometbft/cometbft/libs/json"
+	"github.com/cometbft/cometbft/libs/tempfile"
+	"github.com/cometbft/cometbft/types"
+)
+
+// DumpedTx is a transaction in a mempool dump, along with the metadata the
+// mempool kept for it.
+type DumpedTx struct {
+	Tx        types.Tx  `json:"tx"`
+	Priority  int64     `json:"priority"`
+	GasWanted int64     `json:"gas_wanted"`
+	Sender    string    `json:"sender"`
+	Height    int64     `json:"height"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// WriteDumpFile atomically writes the given transactions to a mempool dump at
+// path.
+func WriteDumpFile(path string, txs []DumpedTx) error {
+	bz, err := cmtjson.Marshal(txs)
+	if err != nil {
+		return fmt.Errorf("failed to encode mempool dump: %w", err)
+	}
+	return tempfile.WriteFileAtomic(path, bz, 0o600)
+}
+
+// ReadDumpFile reads the transactions of the mempool dump at path, in the
+// order they were written.
+func ReadDumpFile(path string) ([]DumpedTx, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var txs []DumpedTx
+	if err := cmtjson.Unmarshal(bz, &txs); err != nil {
+		return nil, fmt.Errorf("failed to decode mempool dump %v: %w", path, err)
+	}
+	if txs == nil {
+		// Tell a dump of an empty mempool apart from a missing one.
+		txs = []DumpedTx{}
+	}
+	return txs, nil
+}
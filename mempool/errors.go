@@ -5,6 +5,15 @@ import (
 	"fmt"
 )
 
+// Codespace is the codespace of the CheckTx codes set by the mempool itself,
+// rather than by the application, in the responses of the broadcast_tx RPC
+// endpoints.
+const Codespace = "mempool"
+
+// CodeTxTooLarge is the CheckTx code of a transaction rejected for exceeding
+// the maximum transaction size of the mempool (see ErrTxTooLarge).
+const CodeTxTooLarge uint32 = 1
+
 // ErrTxNotFound is returned to the client if tx is not found in mempool
 var ErrTxNotFound = errors.New("transaction not found in mempool")
 
@@ -46,6 +55,17 @@ func (e ErrMempoolIsFull) Error() string {
 	)
 }
 
+// ErrPeerRateExceeded defines an error where a peer keeps sending
+// transactions faster than the configured rate limits.
+type ErrPeerRateExceeded struct {
+	Score float64
+	Max   int
+}
+
+func (e ErrPeerRateExceeded) Error() string {
+	return fmt.Sprintf("peer exceeds the mempool rate limits: score %.1f (max: %d)", e.Score, e.Max)
+}
+
 // ErrPreCheck defines an error where a transaction fails a pre-check.
 type ErrPreCheck struct {
 	Err error
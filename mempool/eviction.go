@@ -0,0 +1,61 @@
+package mempool
+
+import (
+	"sync"
+
+	"github.com/cometbft/cometbft/types"
+)
+
+// EvictionQueue calls a function with the evictions of transactions from a
+// mempool, in order, from its own goroutine, so that the mempool can report
+// them while locked without waiting for the function, e.g. publishing them on
+// the event bus. Evictions are dropped while max of them are pending.
+type EvictionQueue struct {
+	fn  func(types.EventDataTxEvicted)
+	max int
+
+	mtx      sync.Mutex
+	pending  []types.EventDataTxEvicted
+	draining bool // whether a goroutine is calling fn with the pending evictions
+}
+
+// NewEvictionQueue returns a queue calling fn with the evictions pushed to it,
+// with up to max of them pending.
+func NewEvictionQueue(fn func(types.EventDataTxEvicted), max int) *EvictionQueue {
+	return &EvictionQueue{fn: fn, max: max}
+}
+
+// Push queues the eviction, and reports false if it was dropped because the
+// queue is full. It never blocks.
+func (q *EvictionQueue) Push(data types.EventDataTxEvicted) bool {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	if len(q.pending) >= q.max {
+		return false
+	}
+	q.pending = append(q.pending, data)
+	if !q.draining {
+		q.draining = true
+		go q.drain()
+	}
+	return true
+}
+
+// drain calls fn with the pending evictions until there are none left.
+func (q *EvictionQueue) drain() {
+	for {
+		q.mtx.Lock()
+		if len(q.pending) == 0 {
+			q.draining = false
+			q.pending = nil
+			q.mtx.Unlock()
+			return
+		}
+		data := q.pending[0]
+		q.pending = q.pending[1:]
+		q.mtx.Unlock()
+
+		q.fn(data)
+	}
+}
@@ -0,0 +1,39 @@
+package mempool
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cometbft/cometbft/types"
+)
+
+func TestEvictionQueue(t *testing.T) {
+	release := make(chan struct{})
+	got := make(chan types.EventDataTxEvicted, 10)
+	q := NewEvictionQueue(func(data types.EventDataTxEvicted) {
+		<-release
+		got <- data
+	}, 2)
+
+	// Pushing never blocks, even while the function does, and evictions are
+	// dropped once the queue is full.
+	require.True(t, q.Push(types.EventDataTxEvicted{Tx: types.Tx("a")}))
+	require.Eventually(t, func() bool {
+		q.mtx.Lock()
+		defer q.mtx.Unlock()
+		return len(q.pending) == 0
+	}, time.Second, time.Millisecond)
+	require.True(t, q.Push(types.EventDataTxEvicted{Tx: types.Tx("b")}))
+	require.True(t, q.Push(types.EventDataTxEvicted{Tx: types.Tx("c")}))
+	require.False(t, q.Push(types.EventDataTxEvicted{Tx: types.Tx("d")}))
+
+	// The function is called with the evictions in order.
+	close(release)
+	for _, tx := range []string{"a", "b", "c"} {
+		require.Equal(t, types.Tx(tx), (<-got).Tx)
+	}
+	require.True(t, q.Push(types.EventDataTxEvicted{Tx: types.Tx("e")}))
+	require.Equal(t, types.Tx("e"), (<-got).Tx)
+}
@@ -34,6 +34,22 @@ func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
 
 			Buckets: stdprometheus.ExponentialBuckets(1, 3, 7),
 		}, labels).With(labelsAndValues...),
+		TxPriority: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "tx_priority",
+			Help:      "Histogram of the priorities of the transactions added to the mempool. Only recorded by the priority mempool.",
+
+			Buckets: stdprometheus.ExponentialBucketsRange(1, 1000000, 13),
+		}, labels).With(labelsAndValues...),
+		TxTimeInMempoolSeconds: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "tx_time_in_mempool_seconds",
+			Help:      "Histogram of the time transactions spent in the mempool before being committed, in seconds. Only recorded by the priority mempool.",
+
+			Buckets: stdprometheus.ExponentialBucketsRange(0.1, 600, 10),
+		}, labels).With(labelsAndValues...),
 		FailedTxs: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
 			Namespace: namespace,
 			Subsystem: MetricsSubsystem,
@@ -70,6 +86,18 @@ func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
 			Name:      "expired_txs",
 			Help:      "ExpiredTxs defines transactions that were removed from the mempool due to a TTL",
 		}, labels).With(labelsAndValues...),
+		ExpiredTxsByMechanism: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "expired_txs_by_mechanism",
+			Help:      "ExpiredTxsByMechanism defines transactions that were removed from the mempool due to a TTL, by expiry mechanism: height for TTLNumBlocks and duration for TTLDuration. Only recorded by the priority mempool.",
+		}, append(labels, "mechanism")).With(labelsAndValues...),
+		EvictedTxsByReason: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "evicted_txs_by_reason",
+			Help:      "EvictedTxsByReason defines transactions that left the mempool without being committed, by reason: mempool_full, expired, recheck_failed or replaced. Only recorded by the priority mempool.",
+		}, append(labels, "reason")).With(labelsAndValues...),
 		SuccessfulTxs: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
 			Namespace: namespace,
 			Subsystem: MetricsSubsystem,
@@ -94,6 +122,12 @@ func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
 			Name:      "rerequested_txs",
 			Help:      "RerequestedTxs defines the number of times that a requested tx never received a response in time and a new request was made.",
 		}, labels).With(labelsAndValues...),
+		ReplacedTxs: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "replaced_txs",
+			Help:      "ReplacedTxs defines the number of transactions that were replaced by a transaction of the same sender and nonce with a higher priority.",
+		}, labels).With(labelsAndValues...),
 	}
 }
 
@@ -102,15 +136,20 @@ func NopMetrics() *Metrics {
 		Size:                      discard.NewGauge(),
 		SizeBytes:                 discard.NewGauge(),
 		TxSizeBytes:               discard.NewHistogram(),
+		TxPriority:                discard.NewHistogram(),
+		TxTimeInMempoolSeconds:    discard.NewHistogram(),
 		FailedTxs:                 discard.NewCounter(),
 		RejectedTxs:               discard.NewCounter(),
 		EvictedTxs:                discard.NewCounter(),
 		RecheckTimes:              discard.NewCounter(),
 		ActiveOutboundConnections: discard.NewGauge(),
 		ExpiredTxs:                discard.NewCounter(),
+		ExpiredTxsByMechanism:     discard.NewCounter(),
+		EvictedTxsByReason:        discard.NewCounter(),
 		SuccessfulTxs:             discard.NewCounter(),
 		AlreadySeenTxs:            discard.NewCounter(),
 		RequestedTxs:              discard.NewCounter(),
 		RerequestedTxs:            discard.NewCounter(),
+		ReplacedTxs:               discard.NewCounter(),
 	}
 }
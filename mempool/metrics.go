@@ -24,6 +24,14 @@ type Metrics struct {
 	// Histogram of transaction sizes in bytes.
 	TxSizeBytes metrics.Histogram `metrics_buckettype:"exp" metrics_bucketsizes:"1,3,7"`
 
+	// Histogram of the priorities of the transactions added to the mempool.
+	// Only recorded by the priority mempool.
+	TxPriority metrics.Histogram `metrics_buckettype:"exprange" metrics_bucketsizes:"1, 1000000, 13"`
+
+	// Histogram of the time transactions spent in the mempool before being
+	// committed, in seconds. Only recorded by the priority mempool.
+	TxTimeInMempoolSeconds metrics.Histogram `metrics_buckettype:"exprange" metrics_bucketsizes:"0.1, 600, 10"`
+
 	// FailedTxs defines the number of failed transactions. These are
 	// transactions that failed to make it into the mempool because they were
 	// deemed invalid.
@@ -53,6 +61,16 @@ type Metrics struct {
 	// to a TTL
 	ExpiredTxs metrics.Counter
 
+	// ExpiredTxsByMechanism defines transactions that were removed from the
+	// mempool due to a TTL, by expiry mechanism: height for TTLNumBlocks and
+	// duration for TTLDuration. Only recorded by the priority mempool.
+	ExpiredTxsByMechanism metrics.Counter `metrics_labels:"mechanism"`
+
+	// EvictedTxsByReason defines transactions that left the mempool without
+	// being committed, by reason: mempool_full, expired, recheck_failed or
+	// replaced. Only recorded by the priority mempool.
+	EvictedTxsByReason metrics.Counter `metrics_labels:"reason"`
+
 	// SuccessfulTxs defines the number of transactions that successfully made
 	// it into a block.
 	SuccessfulTxs metrics.Counter
@@ -69,4 +87,8 @@ type Metrics struct {
 	// RerequestedTxs defines the number of times that a requested tx
 	// never received a response in time and a new request was made.
 	RerequestedTxs metrics.Counter
+
+	// ReplacedTxs defines the number of transactions that were replaced by
+	// a transaction of the same sender and nonce with a higher priority.
+	ReplacedTxs metrics.Counter
 }
@@ -0,0 +1,26 @@
+package mempool
+
+import (
+	"time"
+
+	"github.com/cometbft/cometbft/types"
+)
+
+// PendingTx is a transaction in the mempool, along with the metadata the
+// mempool keeps for it and the time it has left before it expires.
+type PendingTx struct {
+	Tx        types.Tx  `json:"tx"`
+	Priority  int64     `json:"priority"`
+	GasWanted int64     `json:"gas_wanted"`
+	Sender    string    `json:"sender"`
+	Lane      string    `json:"lane"`
+	Height    int64     `json:"height"`
+	Timestamp time.Time `json:"timestamp"`
+	// RemainingBlocks is the number of blocks that can still be committed
+	// before the transaction expires, or nil if transactions don't expire
+	// after a number of blocks.
+	RemainingBlocks *int64 `json:"remaining_blocks,omitempty"`
+	// RemainingTime is the time left before the transaction expires, or nil
+	// if transactions don't expire after a duration.
+	RemainingTime *time.Duration `json:"remaining_time,omitempty"`
+}
@@ -0,0 +1,201 @@
+package priority
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	cfg "github.com/cometbft/cometbft/config"
+	"github.com/cometbft/cometbft/mempool"
+	"github.com/cometbft/cometbft/p2p"
+	protomem "github.com/cometbft/cometbft/proto/tendermint/mempool"
+	"github.com/cometbft/cometbft/types"
+)
+
+// defaultGossipDelay is how long the reactor waits for a transaction it asked
+// a peer for before asking another peer announcing it, if MaxGossipDelay is
+// not set.
+const defaultGossipDelay = 200 * time.Millisecond
+
+// txRequests tracks the transactions asked for with WantTx messages, so that
+// each is only asked to one peer at a time. If a peer doesn't send the
+// transaction in time, it is asked to the next peer which announced it.
+type txRequests struct {
+	mtx     sync.Mutex
+	timeout time.Duration
+	max     int // maximum number of pending requests
+	pending map[types.TxKey]*txRequest
+
+	// ask sends a WantTx message for the transaction to the peer, and reports
+	// whether it was sent.
+	ask func(key types.TxKey, peer p2p.ID) bool
+}
+
+// txRequest is a pending request for a transaction.
+type txRequest struct {
+	peer   p2p.ID   // peer asked for the transaction
+	others []p2p.ID // other peers which announced it, to ask next
+	timer  *time.Timer
+}
+
+func newTxRequests(timeout time.Duration, max int, ask func(types.TxKey, p2p.ID) bool) *txRequests {
+	if timeout <= 0 {
+		timeout = defaultGossipDelay
+	}
+	return &txRequests{
+		timeout: timeout,
+		max:     max,
+		pending: make(map[types.TxKey]*txRequest),
+		ask:     ask,
+	}
+}
+
+// Request reports whether the transaction with the given key, announced by
+// peer, should be asked to it, and records it as asked for if so. If it is
+// already asked to another peer, peer is asked next if that one doesn't send
+// it in time.
+func (r *txRequests) Request(key types.TxKey, peer p2p.ID) bool {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if req, ok := r.pending[key]; ok {
+		if req.peer != peer && !slices.Contains(req.others, peer) {
+			req.others = append(req.others, peer)
+		}
+		return false
+	}
+	if len(r.pending) >= r.max {
+		return false
+	}
+	r.pending[key] = &txRequest{
+		peer:  peer,
+		timer: time.AfterFunc(r.timeout, func() { r.retry(key) }),
+	}
+	return true
+}
+
+// retry asks the transaction with the given key to the next peer which
+// announced it, once the request to the previous one timed out. The request
+// is forgotten if there is none left, so that the next announcement asks for
+// the transaction again.
+func (r *txRequests) retry(key types.TxKey) {
+	for {
+		r.mtx.Lock()
+		req, ok := r.pending[key]
+		if !ok {
+			r.mtx.Unlock()
+			return
+		}
+		if len(req.others) == 0 {
+			delete(r.pending, key)
+			r.mtx.Unlock()
+			return
+		}
+		req.peer, req.others = req.others[0], req.others[1:]
+		req.timer.Reset(r.timeout)
+		peer := req.peer
+		r.mtx.Unlock()
+
+		if r.ask(key, peer) {
+			return
+		}
+	}
+}
+
+// Done forgets the request for the transaction with the given key, once it
+// is received or could not be asked for.
+func (r *txRequests) Done(key types.TxKey) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if req, ok := r.pending[key]; ok {
+		req.timer.Stop()
+		delete(r.pending, key)
+	}
+}
+
+// Stop forgets all the pending requests.
+func (r *txRequests) Stop() {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	for key, req := range r.pending {
+		req.timer.Stop()
+		delete(r.pending, key)
+	}
+}
+
+// haveWant reports whether transactions are gossiped with the have/want
+// protocol rather than flooded.
+func (memR *Reactor) haveWant() bool {
+	return memR.config.GossipProtocol == cfg.MempoolGossipHaveWant
+}
+
+// receiveSeenTx handles the announcement by src that it has a transaction.
+// src is recorded as having it, and the transaction is asked for unless we
+// have already seen it or asked another peer for it recently.
+func (memR *Reactor) receiveSeenTx(src p2p.Peer, msg *protomem.SeenTx) {
+	key, err := types.TxKeyFromBytes(msg.TxKey)
+	if err != nil {
+		memR.Logger.Error("peer sent SeenTx with incorrect tx key", "src", src, "err", err)
+		memR.Switch.StopPeerForError(src, err, memR.String())
+		return
+	}
+	if wtx, ok := memR.mempool.getWrappedTx(key); ok {
+		wtx.SetPeer(memR.ids.GetForPeer(src))
+		return
+	}
+	if memR.mempool.cache.HasKey(key) || memR.mempool.WasRecentlyRejected(key) {
+		return
+	}
+	if !memR.requests.Request(key, src.ID()) {
+		return
+	}
+	if !src.Send(p2p.Envelope{
+		ChannelID: mempool.MempoolChannel,
+		Message:   &protomem.WantTx{TxKey: key[:]},
+	}) {
+		memR.requests.Done(key)
+	}
+}
+
+// askForTx sends a WantTx message for the transaction with the given key to
+// the peer, unless we got the transaction in the meantime or the peer is gone.
+// It reports whether the message was sent.
+func (memR *Reactor) askForTx(key types.TxKey, peerID p2p.ID) bool {
+	if memR.mempool.cache.HasKey(key) {
+		memR.requests.Done(key)
+		return false
+	}
+	peer := memR.Switch.Peers().Get(peerID)
+	if peer == nil {
+		return false
+	}
+	return peer.Send(p2p.Envelope{
+		ChannelID: mempool.MempoolChannel,
+		Message:   &protomem.WantTx{TxKey: key[:]},
+	})
+}
+
+// receiveWantTx sends src the transaction it asks for, if we have it. Each
+// transaction is sent at most once to a peer.
+func (memR *Reactor) receiveWantTx(src p2p.Peer, msg *protomem.WantTx) {
+	key, err := types.TxKeyFromBytes(msg.TxKey)
+	if err != nil {
+		memR.Logger.Error("peer sent WantTx with incorrect tx key", "src", src, "err", err)
+		memR.Switch.StopPeerForError(src, err, memR.String())
+		return
+	}
+	wtx, ok := memR.mempool.getWrappedTx(key)
+	if !ok {
+		return
+	}
+	peerID := memR.ids.GetForPeer(src)
+	if wtx.HasPeer(peerID) {
+		return
+	}
+	if src.Send(p2p.Envelope{
+		ChannelID: mempool.MempoolChannel,
+		Message:   &protomem.Txs{Txs: [][]byte{wtx.tx.Tx}},
+	}) {
+		wtx.SetPeer(peerID)
+	}
+}
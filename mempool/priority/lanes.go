@@ -0,0 +1,153 @@
+package priority
+
+import (
+	"fmt"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+)
+
+const (
+	// LaneEventType is the type of the CheckTx event from which
+	// LaneFromEvents reads the lane of a transaction.
+	LaneEventType = "mempool"
+	// LaneAttributeKey is the key of the attribute of a LaneEventType event
+	// holding the lane of a transaction.
+	LaneAttributeKey = "lane"
+)
+
+// Lane is a named class of transactions, e.g. "blob", "transfer" or "oracle",
+// with its own quotas. Lanes keep transactions of one class, however many and
+// whatever their priority, from crowding out transactions of another.
+type Lane struct {
+	// Name identifies the lane in CheckTx responses.
+	Name string
+	// MaxTxs is the maximum number of transactions in the lane. Zero means
+	// that only the mempool size limits the lane.
+	MaxTxs int
+	// MaxTxsBytes is the maximum total size of the transactions in the lane,
+	// in bytes. Zero means that only the mempool size limits the lane.
+	MaxTxsBytes int64
+}
+
+// LaneFunc returns the lane of a transaction from the response of the
+// application to its CheckTx.
+type LaneFunc func(*abci.ResponseCheckTx) string
+
+// LaneFromEvents returns the value of the LaneAttributeKey attribute of the
+// first LaneEventType event of the CheckTx response, or "" if there is none.
+func LaneFromEvents(res *abci.ResponseCheckTx) string {
+	for _, ev := range res.Events {
+		if ev.Type != LaneEventType {
+			continue
+		}
+		for _, attr := range ev.Attributes {
+			if attr.Key == LaneAttributeKey {
+				return attr.Value
+			}
+		}
+	}
+	return ""
+}
+
+// WithLanes sets the lanes of the mempool, with laneFn choosing the lane of
+// each transaction; if laneFn is nil, LaneFromEvents is used. Transactions are
+// reaped lane by lane in the order the lanes are given, and by priority within
+// a lane. Transactions of no configured lane are reaped last, and are only
+// limited by the mempool size.
+func WithLanes(laneFn LaneFunc, lanes ...Lane) TxMempoolOption {
+	return func(txmp *TxMempool) {
+		if laneFn == nil {
+			laneFn = LaneFromEvents
+		}
+		txmp.laneFn = laneFn
+		txmp.lanes = make(map[string]int, len(lanes))
+		txmp.laneQuotas = lanes
+		for i, lane := range lanes {
+			txmp.lanes[lane.Name] = i
+		}
+	}
+}
+
+// laneUsage is the number and total size of the transactions in a lane.
+type laneUsage struct {
+	txs   int
+	bytes int64
+}
+
+// laneOf returns the lane of a transaction from its CheckTx response, or ""
+// if the lane isn't one of the configured lanes.
+func (txmp *TxMempool) laneOf(res *abci.ResponseCheckTx) string {
+	if txmp.laneFn == nil {
+		return ""
+	}
+	lane := txmp.laneFn(res)
+	if _, ok := txmp.lanes[lane]; !ok {
+		return ""
+	}
+	return lane
+}
+
+// laneRank returns the reaping order of a lane, the transactions of the lowest
+// ranks being reaped first.
+func (txmp *TxMempool) laneRank(lane string) int {
+	if rank, ok := txmp.lanes[lane]; ok {
+		return rank
+	}
+	return len(txmp.lanes)
+}
+
+// canAddToLane returns an error if inserting wtx would exceed the quotas of
+// its lane.
+//
+// The caller must hold txmp.mtx.
+func (txmp *TxMempool) canAddToLane(wtx *WrappedTx) error {
+	rank, ok := txmp.lanes[wtx.lane]
+	if !ok {
+		return nil
+	}
+	quota := txmp.laneQuotas[rank]
+	usage := txmp.laneUsage[wtx.lane]
+	if (quota.MaxTxs > 0 && usage.txs >= quota.MaxTxs) ||
+		(quota.MaxTxsBytes > 0 && usage.bytes+wtx.Size() > quota.MaxTxsBytes) {
+		return fmt.Errorf("lane %q is full: %d txs (max %d), %d bytes (max %d)",
+			wtx.lane, usage.txs, quota.MaxTxs, usage.bytes, quota.MaxTxsBytes)
+	}
+	return nil
+}
+
+// canAddToLanes returns an error if adding the given number and size of
+// transactions to each lane would exceed the quotas of any of them.
+func (txmp *TxMempool) canAddToLanes(added map[string]laneUsage) error {
+	for lane, add := range added {
+		rank, ok := txmp.lanes[lane]
+		if !ok {
+			continue
+		}
+		quota := txmp.laneQuotas[rank]
+		usage := txmp.laneUsage[lane]
+		if (quota.MaxTxs > 0 && usage.txs+add.txs > quota.MaxTxs) ||
+			(quota.MaxTxsBytes > 0 && usage.bytes+add.bytes > quota.MaxTxsBytes) {
+			return fmt.Errorf("lane %q is full: %d txs (max %d), %d bytes (max %d)",
+				lane, usage.txs, quota.MaxTxs, usage.bytes, quota.MaxTxsBytes)
+		}
+	}
+	return nil
+}
+
+// trackLane updates the usage of the lane of wtx when it is inserted (sign 1)
+// or removed (sign -1).
+//
+// The caller must hold txmp.mtx exclusively.
+func (txmp *TxMempool) trackLane(wtx *WrappedTx, sign int) {
+	if wtx.lane == "" {
+		return
+	}
+	usage := txmp.laneUsage[wtx.lane]
+	usage.txs += sign
+	usage.bytes += int64(sign) * wtx.Size()
+	if usage.txs == 0 {
+		delete(txmp.laneUsage, wtx.lane)
+		return
+	}
+	txmp.laneUsage[wtx.lane] = usage
+}
@@ -3,6 +3,7 @@ package priority
 import (
 	"context"
 	"fmt"
+	"io"
 	"sort"
 	"sync"
 	"sync/atomic"
@@ -48,14 +49,55 @@ type TxMempool struct {
 	txsAvailable         chan struct{} // one value sent per height when mempool is not empty
 	preCheckFn           mempool.PreCheckFunc
 	postCheckFn          mempool.PostCheckFunc
-	height               int64     // the latest height passed to Update
-	lastPurgeTime        time.Time // the last time we attempted to purge transactions via the TTL
+	height               int64              // the latest height passed to Update
+	lastPurgeTime        time.Time          // the last time we attempted to purge transactions via the TTL
+	lastRecheckHeight    int64              // the height of the latest recheck
+	cancelRecheck        context.CancelFunc // abandons the asynchronous recheck in progress, if any
 
 	txs         *clist.CList // valid transactions (passed CheckTx)
 	txByKey     map[types.TxKey]*clist.CElement
-	txBySender  map[string]*clist.CElement // for sender != ""
-	evictedTxs  mempool.TxCache            // for tracking evicted transactions
-	rejectedTxs mempool.TxCache            // for tracking rejected transactions
+	txBySender  map[string]*clist.CElement       // for sender != "" and transactions without a nonce
+	txByNonce   map[accountNonce]*clist.CElement // for transactions with a nonce
+	evictedTxs  mempool.TxCache                  // for tracking evicted transactions
+	rejectedTxs mempool.TxCache                  // for tracking rejected transactions
+	reserved    map[types.TxKey]int              // number of reservations pinning each transaction
+	laneUsage   map[string]laneUsage             // usage of each configured lane
+
+	// ordered holds the valid transactions in reaping order, and accountTxs
+	// the transactions of each account in nonce order (see order.go).
+	ordered    []*WrappedTx
+	accountTxs map[string][]*WrappedTx
+
+	// Lanes, set by WithLanes. Immutable after construction.
+	laneFn     LaneFunc
+	lanes      map[string]int // rank of each lane by name
+	laneQuotas []Lane
+
+	// nonceFn, set by WithNonces. Immutable after construction.
+	nonceFn NonceFunc
+
+	// evictions, set by WithEvictionCallback. Immutable after construction.
+	evictions *mempool.EvictionQueue
+
+	// wal journals the transactions of the mempool if it persists them to
+	// disk. It is set by InitWAL and protected by mtx.
+	wal *txWAL
+	// walPending holds the transactions of the journal from before the
+	// restart until ReplayWAL adds them to the mempool. Protected by mtx.
+	walPending []types.Tx
+	// walReplaying is set while ReplayWAL replays walPending. Protected by
+	// mtx.
+	walReplaying bool
+
+	// reapedTxs records the transactions handed out by the Reap methods so
+	// that they can be requeued with their original metadata if the block
+	// they were reaped for is not committed. It is protected by reapedMtx.
+	reapedMtx sync.Mutex
+	reapedTxs map[types.TxKey]*WrappedTx
+
+	// committedTxs records the keys of recently committed transactions so
+	// that the reactor stops gossiping them. It is bounded by the mempool size.
+	committedTxs mempool.TxCache
 }
 
 // NewTxMempool constructs a new, empty priority mempool at the specified
@@ -79,6 +121,12 @@ func NewTxMempool(
 		height:       height,
 		txByKey:      make(map[types.TxKey]*clist.CElement),
 		txBySender:   make(map[string]*clist.CElement),
+		txByNonce:    make(map[accountNonce]*clist.CElement),
+		reserved:     make(map[types.TxKey]int),
+		laneUsage:    make(map[string]laneUsage),
+		accountTxs:   make(map[string][]*WrappedTx),
+		reapedTxs:    make(map[types.TxKey]*WrappedTx),
+		committedTxs: mempool.NewLRUTxCache(cfg.Size),
 	}
 	if cfg.CacheSize > 0 {
 		txmp.cache = mempool.NewLRUTxCache(cfg.CacheSize)
@@ -112,6 +160,15 @@ func WithMetrics(metrics *mempool.Metrics) TxMempoolOption {
 	return func(txmp *TxMempool) { txmp.metrics = metrics }
 }
 
+// WithEvictionCallback sets a function called with each transaction leaving
+// the mempool without being committed, and the reason why, one of the
+// types.TxEvicted values. It is called in order from a separate goroutine, not
+// to hold the mempool lock while it runs; evictions are dropped while as many
+// as the size of the mempool are pending.
+func WithEvictionCallback(fn func(types.EventDataTxEvicted)) TxMempoolOption {
+	return func(txmp *TxMempool) { txmp.evictions = mempool.NewEvictionQueue(fn, txmp.config.Size) }
+}
+
 // Lock obtains a write-lock on the mempool. A caller must be sure to explicitly
 // release the lock when finished.
 func (txmp *TxMempool) Lock() { txmp.mtx.Lock() }
@@ -231,6 +288,7 @@ func (txmp *TxMempool) CheckTx(
 		tx:        cachedTx,
 		timestamp: time.Now().UTC(),
 		height:    txmp.height,
+		source:    txInfo.SenderID,
 	}
 	wtx.SetPeer(txInfo.SenderID)
 	// This won't add the transaction if the response code is non zero (i.e. there was an error)
@@ -262,6 +320,18 @@ func (txmp *TxMempool) GetTxByKey(txKey types.TxKey) (*types.CachedTx, bool) {
 	return nil, false
 }
 
+// getWrappedTx returns the transaction with the given key, if it is in the
+// mempool.
+func (txmp *TxMempool) getWrappedTx(txKey types.TxKey) (*WrappedTx, bool) {
+	txmp.mtx.RLock()
+	defer txmp.mtx.RUnlock()
+
+	if elt, ok := txmp.txByKey[txKey]; ok {
+		return elt.Value.(*WrappedTx), true
+	}
+	return nil, false
+}
+
 // WasRecentlyEvicted returns a bool indicating whether the transaction with
 // the specified key was recently evicted and is currently within the evicted cache.
 func (txmp *TxMempool) WasRecentlyEvicted(txKey types.TxKey) bool {
@@ -281,10 +351,13 @@ func (txmp *TxMempool) removeTxByKey(key types.TxKey) error {
 	if elt, ok := txmp.txByKey[key]; ok {
 		w := elt.Value.(*WrappedTx)
 		delete(txmp.txByKey, key)
-		delete(txmp.txBySender, w.sender)
+		txmp.removeSender(w)
+		txmp.removeFromOrder(w)
+		txmp.journalRemove(w)
 		txmp.txs.Remove(elt)
 		elt.DetachPrev()
 		elt.DetachNext()
+		txmp.trackLane(w, -1)
 		atomic.AddInt64(&txmp.txsBytes, -w.Size())
 		return nil
 	}
@@ -296,10 +369,13 @@ func (txmp *TxMempool) removeTxByKey(key types.TxKey) error {
 func (txmp *TxMempool) removeTxByElement(elt *clist.CElement) {
 	w := elt.Value.(*WrappedTx)
 	delete(txmp.txByKey, w.tx.Key())
-	delete(txmp.txBySender, w.sender)
+	txmp.removeSender(w)
+	txmp.removeFromOrder(w)
+	txmp.journalRemove(w)
 	txmp.txs.Remove(elt)
 	elt.DetachPrev()
 	elt.DetachNext()
+	txmp.trackLane(w, -1)
 	atomic.AddInt64(&txmp.txsBytes, -w.Size())
 }
 
@@ -318,24 +394,24 @@ func (txmp *TxMempool) Flush() {
 		cur = next
 	}
 	txmp.cache.Reset()
+
+	txmp.reapedMtx.Lock()
+	txmp.reapedTxs = make(map[types.TxKey]*WrappedTx)
+	txmp.reapedMtx.Unlock()
 }
 
 // allEntriesSorted returns a slice of all the transactions currently in the
-// mempool, sorted in nonincreasing order by priority with ties broken by
-// increasing order of arrival time.
+// mempool, sorted by lane in reaping order, then in nonincreasing order by
+// priority with ties broken by increasing order of arrival time. The
+// transactions of an account are in nonce order among their positions.
 func (txmp *TxMempool) allEntriesSorted() []*WrappedTx {
 	txmp.mtx.RLock()
 	defer txmp.mtx.RUnlock()
 
-	all := make([]*WrappedTx, 0, len(txmp.txByKey))
-	for _, tx := range txmp.txByKey {
-		all = append(all, tx.Value.(*WrappedTx))
-	}
-	sort.Slice(all, func(i, j int) bool {
-		if all[i].priority == all[j].priority {
-			return all[i].timestamp.Before(all[j].timestamp)
-		}
-		return all[i].priority > all[j].priority // N.B. higher priorities first
+	all := make([]*WrappedTx, 0, len(txmp.ordered))
+	txmp.walkOrder(func(wtx *WrappedTx) bool {
+		all = append(all, wtx)
+		return true
 	})
 	return all
 }
@@ -343,7 +419,9 @@ func (txmp *TxMempool) allEntriesSorted() []*WrappedTx {
 // ReapMaxBytesMaxGas returns a slice of valid transactions that fit within the
 // size and gas constraints. The results are ordered by nonincreasing priority,
 // with ties broken by increasing order of arrival.  Reaping transactions does
-// not remove them from the mempool.
+// not remove them from the mempool. If nonces are enabled, the transactions of
+// an account are reaped in nonce order, and none are reaped after one that
+// does not fit.
 //
 // If maxBytes < 0, no limit is set on the total size in bytes.
 // If maxGas < 0, no limit is set on the total gas cost.
@@ -353,19 +431,32 @@ func (txmp *TxMempool) allEntriesSorted() []*WrappedTx {
 func (txmp *TxMempool) ReapMaxBytesMaxGas(maxBytes, maxGas int64) []*types.CachedTx {
 	var totalGas, totalBytes int64
 
-	var keep []*types.CachedTx //nolint:prealloc
-	for _, w := range txmp.allEntriesSorted() {
+	var keep []*types.CachedTx       //nolint:prealloc
+	var reaped []*WrappedTx          //nolint:prealloc
+	skipped := make(map[string]bool) // accounts with a transaction left out
+	txmp.Iterate(func(w *WrappedTx) bool {
+		// Leaving out a transaction of an account leaves out its later nonces,
+		// which could not be executed without it.
+		if w.hasNonce && skipped[w.account] {
+			return true
+		}
 		// N.B. When computing byte size, we need to include the overhead for
 		// encoding as protobuf to send to the application. This actually overestimates it
 		// as we add the proto overhead to each transaction
 		txBytes := types.ComputeProtoSizeForTxs([]types.Tx{w.tx.Tx})
 		if (maxGas >= 0 && totalGas+w.gasWanted > maxGas) || (maxBytes >= 0 && totalBytes+txBytes > maxBytes) {
-			continue
+			if w.hasNonce {
+				skipped[w.account] = true
+			}
+			return true
 		}
 		totalBytes += txBytes
 		totalGas += w.gasWanted
 		keep = append(keep, w.tx)
-	}
+		reaped = append(reaped, w)
+		return true
+	})
+	txmp.recordReaped(reaped)
 	return keep
 }
 
@@ -387,24 +478,298 @@ func (txmp *TxMempool) TxsFront() *clist.CElement { return txmp.txs.Front() }
 // does not have that many transactions available.
 func (txmp *TxMempool) ReapMaxTxs(max int) []*types.CachedTx {
 	var keep []*types.CachedTx //nolint:prealloc
+	var reaped []*WrappedTx    //nolint:prealloc
 
-	for _, w := range txmp.allEntriesSorted() {
+	txmp.Iterate(func(w *WrappedTx) bool {
 		if max >= 0 && len(keep) >= max {
-			break
+			return false
 		}
 		keep = append(keep, w.tx)
-	}
+		reaped = append(reaped, w)
+		return true
+	})
+	txmp.recordReaped(reaped)
 	return keep
 }
 
+// Iterate calls fn for each transaction in the mempool, in the order they
+// would be reaped, until fn returns false. It lets callers such as
+// PrepareProposal pick transactions, e.g. until a block is full, without
+// reaping them into a slice first.
+//
+// Iterate walks the ordered index of the mempool under its read lock, so fn
+// must not call the methods of the mempool modifying it. Unlike the Reap
+// methods, it does not record the transactions visited as reaped.
+func (txmp *TxMempool) Iterate(fn func(tx *WrappedTx) bool) {
+	txmp.mtx.RLock()
+	defer txmp.mtx.RUnlock()
+
+	txmp.walkOrder(fn)
+}
+
+// DumpToFile writes all the transactions currently in the mempool, along with
+// their priorities, to a mempool dump at path. The transactions are ordered as
+// they would be reaped. The dump can be loaded for offline analysis with the
+// inspect command.
+func (txmp *TxMempool) DumpToFile(path string) error {
+	wtxs := txmp.allEntriesSorted()
+	txs := make([]mempool.DumpedTx, len(wtxs))
+	for i, w := range wtxs {
+		txs[i] = mempool.DumpedTx{
+			Tx:        w.tx.Tx,
+			Priority:  w.Priority(),
+			GasWanted: w.GasWanted(),
+			Sender:    w.Sender(),
+			Height:    w.height,
+			Timestamp: w.timestamp,
+		}
+	}
+	return mempool.WriteDumpFile(path, txs)
+}
+
+// PendingTxs returns up to max transactions from the mempool, in the order
+// they would be reaped, along with their metadata and the time they have left
+// before they expire. Transactions reserved for a proposal don't expire, but
+// their remaining time is reported regardless. If max < 0, all transactions in
+// the mempool are returned.
+func (txmp *TxMempool) PendingTxs(max int) []mempool.PendingTx {
+	txmp.mtx.RLock()
+	height := txmp.height
+	txmp.mtx.RUnlock()
+
+	wtxs := txmp.allEntriesSorted()
+	if max >= 0 && len(wtxs) > max {
+		wtxs = wtxs[:max]
+	}
+	now := time.Now()
+	txs := make([]mempool.PendingTx, len(wtxs))
+	for i, w := range wtxs {
+		txs[i] = mempool.PendingTx{
+			Tx:        w.tx.Tx,
+			Priority:  w.Priority(),
+			GasWanted: w.GasWanted(),
+			Sender:    w.Sender(),
+			Lane:      w.lane,
+			Height:    w.height,
+			Timestamp: w.timestamp,
+		}
+		if ttl := txmp.config.TTLNumBlocks; ttl > 0 {
+			remaining := w.height + ttl - height
+			if remaining < 0 {
+				remaining = 0
+			}
+			txs[i].RemainingBlocks = &remaining
+		}
+		if ttl := txmp.config.TTLDuration; ttl > 0 {
+			remaining := ttl - now.Sub(w.timestamp)
+			if remaining < 0 {
+				remaining = 0
+			}
+			txs[i].RemainingTime = &remaining
+		}
+	}
+	return txs
+}
+
+// WritePrometheus writes the current mempool gauges to w in the Prometheus
+// text exposition format, for nodes that expose mempool health without
+// running a metrics server.
+func (txmp *TxMempool) WritePrometheus(w io.Writer) {
+	txmp.mtx.RLock()
+	size := int64(txmp.txs.Len())
+	senders := int64(txmp.numSenders())
+	var highest int64
+	for cur := txmp.txs.Front(); cur != nil; cur = cur.Next() {
+		if p := cur.Value.(*WrappedTx).priority; p > highest || cur == txmp.txs.Front() {
+			highest = p
+		}
+	}
+	txmp.mtx.RUnlock()
+
+	writeGauge(w, "size", "Number of uncommitted transactions in the mempool.", size)
+	writeGauge(w, "size_bytes", "Total size of the mempool in bytes.", txmp.SizeBytes())
+	writeGauge(w, "highest_priority", "Highest priority of a transaction in the mempool.", highest)
+	writeGauge(w, "senders", "Number of distinct senders and accounts with transactions in the mempool.", senders)
+}
+
+// writeGauge writes a mempool gauge in the Prometheus text exposition format.
+func writeGauge(w io.Writer, name, help string, value int64) {
+	name = "cometbft_" + mempool.MetricsSubsystem + "_" + name
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n%s %d\n", name, help, name, name, value)
+}
+
+// recordReaped remembers the given reaped transactions for Requeue. To bound
+// memory, the record is reset once it holds more than the mempool size.
+func (txmp *TxMempool) recordReaped(wtxs []*WrappedTx) {
+	txmp.reapedMtx.Lock()
+	defer txmp.reapedMtx.Unlock()
+
+	if len(txmp.reapedTxs)+len(wtxs) > txmp.config.Size {
+		txmp.reapedTxs = make(map[types.TxKey]*WrappedTx)
+	}
+	for _, w := range wtxs {
+		txmp.reapedTxs[w.tx.Key()] = w
+	}
+}
+
+// Requeue re-inserts transactions that were previously reaped from the
+// mempool but were not committed, e.g. because the proposed block failed to
+// commit. Requeued transactions keep the priority, gas and sender assigned by
+// the application when they were first checked, and are not re-checked.
+//
+// Transactions that are already in the mempool (for instance because they
+// were re-added by gossip) or whose sender already has a transaction in the
+// mempool are skipped. Requeue reports an error if a transaction was not
+// reaped from this mempool or if the mempool is full, in which case none of
+// the transactions are requeued.
+func (txmp *TxMempool) Requeue(txs types.Txs) error {
+	txmp.mtx.Lock()
+	defer txmp.mtx.Unlock()
+	txmp.reapedMtx.Lock()
+	defer txmp.reapedMtx.Unlock()
+
+	// Validate all the transactions before inserting any of them.
+	var (
+		wtxs    []*WrappedTx
+		size    int64
+		lanes   = make(map[string]laneUsage)
+		keys    = make(map[types.TxKey]struct{})
+		nonces  = make(map[accountNonce]struct{})
+		senders = make(map[string]struct{})
+	)
+	for _, tx := range txs {
+		key := tx.Key()
+		if _, ok := txmp.txByKey[key]; ok {
+			continue
+		}
+		if _, ok := keys[key]; ok {
+			continue
+		}
+		reaped, ok := txmp.reapedTxs[key]
+		if !ok {
+			return fmt.Errorf("transaction %X was not reaped from the mempool", key)
+		}
+		if reaped.hasNonce {
+			an := accountNonce{reaped.account, reaped.nonce}
+			if _, ok := txmp.txByNonce[an]; ok {
+				continue
+			}
+			if _, ok := nonces[an]; ok {
+				continue
+			}
+			nonces[an] = struct{}{}
+		} else if s := reaped.Sender(); s != "" {
+			if _, ok := txmp.txBySender[s]; ok {
+				continue
+			}
+			if _, ok := senders[s]; ok {
+				continue
+			}
+			senders[s] = struct{}{}
+		}
+		keys[key] = struct{}{}
+
+		wtx := &WrappedTx{
+			tx:        reaped.tx,
+			height:    reaped.height,
+			timestamp: reaped.timestamp,
+			source:    reaped.source,
+			gasWanted: reaped.GasWanted(),
+			priority:  reaped.Priority(),
+			sender:    reaped.Sender(),
+			lane:      reaped.lane,
+			account:   reaped.account,
+			nonce:     reaped.nonce,
+			hasNonce:  reaped.hasNonce,
+		}
+		wtxs = append(wtxs, wtx)
+		size += wtx.Size()
+		if wtx.lane != "" {
+			usage := lanes[wtx.lane]
+			usage.txs++
+			usage.bytes += wtx.Size()
+			lanes[wtx.lane] = usage
+		}
+	}
+
+	numTxs := txmp.Size()
+	txBytes := txmp.SizeBytes()
+	if numTxs+len(wtxs) > txmp.config.Size || txBytes+size > txmp.config.MaxTxsBytes {
+		return mempool.ErrMempoolIsFull{
+			NumTxs:      numTxs,
+			MaxTxs:      txmp.config.Size,
+			TxsBytes:    txBytes,
+			MaxTxsBytes: txmp.config.MaxTxsBytes,
+		}
+	}
+	if err := txmp.canAddToLanes(lanes); err != nil {
+		return err
+	}
+
+	for _, wtx := range wtxs {
+		_ = txmp.cache.Push(wtx.tx)
+		txmp.insertTx(wtx)
+	}
+
+	txmp.metrics.Size.Set(float64(txmp.Size()))
+	txmp.metrics.SizeBytes.Set(float64(txmp.SizeBytes()))
+	txmp.notifyTxsAvailable()
+	return nil
+}
+
+// Reserve pins the transactions with the given keys, typically those reaped for
+// a pending proposal, so that they are neither evicted to make room for other
+// transactions nor purged when they expire. The transactions are still removed
+// when they are committed or fail a recheck. The returned function releases
+// the reservation, and must be called once the block is committed or
+// abandoned. Calling it more than once has no further effect.
+func (txmp *TxMempool) Reserve(keys []types.TxKey) (release func()) {
+	txmp.mtx.Lock()
+	defer txmp.mtx.Unlock()
+	for _, key := range keys {
+		txmp.reserved[key]++
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			txmp.mtx.Lock()
+			defer txmp.mtx.Unlock()
+			for _, key := range keys {
+				if txmp.reserved[key] <= 1 {
+					delete(txmp.reserved, key)
+				} else {
+					txmp.reserved[key]--
+				}
+			}
+		})
+	}
+}
+
+// MarkCommitted records the given transactions as committed. Transactions
+// marked as committed are no longer gossiped to peers, even if they are still
+// in the mempool, until they fall out of the bounded committed set.
+func (txmp *TxMempool) MarkCommitted(txs types.Txs) {
+	for _, tx := range txs {
+		_ = txmp.committedTxs.Push(tx.ToCachedTx())
+	}
+}
+
+// WasRecentlyCommitted returns true if the transaction with the given key was
+// recently marked as committed.
+func (txmp *TxMempool) WasRecentlyCommitted(key types.TxKey) bool {
+	return txmp.committedTxs.HasKey(key)
+}
+
 // Update removes all the given transactions from the mempool and the cache,
 // and updates the current block height. The blockTxs and deliverTxResponses
 // must have the same length with each response corresponding to the tx at the
 // same offset.
 //
-// If the configuration enables recheck, Update sends each remaining
-// transaction after removing blockTxs to the ABCI CheckTx method.  Any
-// transactions marked as invalid during recheck are also removed.
+// If the recheck policy requires it at this height, Update sends each
+// remaining transaction after removing blockTxs to the ABCI CheckTx method.
+// Any transactions marked as invalid during recheck are also removed. With
+// asynchronous rechecks, this happens after Update returns.
 //
 // The caller must hold an exclusive mempool lock (by calling txmp.Lock) before
 // calling Update.
@@ -442,10 +807,24 @@ func (txmp *TxMempool) Update(
 			txmp.cache.Remove(tx)
 		}
 
-		// Regardless of success, remove the transaction from the mempool.
+		if elt, ok := txmp.txByKey[tx.Key()]; ok {
+			wtx := elt.Value.(*WrappedTx)
+			txmp.metrics.TxTimeInMempoolSeconds.Observe(time.Since(wtx.timestamp).Seconds())
+		}
+
+		// Regardless of success, remove the transaction from the mempool and
+		// stop gossiping it.
+		_ = txmp.committedTxs.Push(tx)
 		_ = txmp.removeTxByKey(tx.Key())
 	}
 
+	// Committed transactions can no longer be requeued.
+	txmp.reapedMtx.Lock()
+	for _, tx := range blockTxs {
+		delete(txmp.reapedTxs, tx.Key())
+	}
+	txmp.reapedMtx.Unlock()
+
 	txmp.purgeExpiredTxs(blockHeight)
 
 	// If there any uncommitted transactions left in the mempool, we either
@@ -455,12 +834,13 @@ func (txmp *TxMempool) Update(
 	txmp.metrics.Size.Set(float64(size))
 	txmp.metrics.SizeBytes.Set(float64(txmp.SizeBytes()))
 	if size > 0 {
-		if txmp.config.Recheck {
+		if txmp.shouldRecheck(blockHeight) {
 			txmp.recheckTransactions()
 		} else {
 			txmp.notifyTxsAvailable()
 		}
 	}
+	txmp.compactWAL()
 	return nil
 }
 
@@ -471,10 +851,14 @@ func (txmp *TxMempool) Update(
 // If either the application rejected the transaction or a post-check hook is
 // defined and rejects the transaction, it is discarded.
 //
-// Otherwise, if the mempool is full, check for lower-priority transactions
-// that can be evicted to make room for the new one. If no such transactions
-// exist, this transaction is logged and dropped; otherwise the selected
-// transactions are evicted.
+// If another transaction of the same sender, or of the same account and nonce,
+// is in the mempool, the new one replaces it if it has a higher priority, and
+// is discarded otherwise.
+//
+// Otherwise, if the lane of the transaction or the mempool is full, check for
+// lower-priority transactions that can be evicted to make room for the new
+// one. If no such transactions exist, this transaction is logged and dropped;
+// otherwise the selected transactions are evicted.
 //
 // Finally, the new transaction is added and size stats updated.
 func (txmp *TxMempool) addNewTransaction(wtx *WrappedTx, checkTxRes *abci.ResponseCheckTx) {
@@ -510,12 +894,47 @@ func (txmp *TxMempool) addNewTransaction(wtx *WrappedTx, checkTxRes *abci.Respon
 	priority := checkTxRes.Priority
 	sender := checkTxRes.Address
 
+	// Enforce the operator's priority floor, if any.
+	if minPriority := txmp.config.MinPriority; minPriority > 0 && priority < minPriority {
+		txmp.cache.Remove(wtx.tx)
+		txmp.rejectedTxs.Push(wtx.tx)
+		txmp.logger.Debug(
+			"rejected valid incoming transaction; priority below floor",
+			"tx", fmt.Sprintf("%X", wtx.tx.Hash()),
+			"priority", priority,
+			"min_priority", minPriority,
+		)
+		txmp.metrics.RejectedTxs.Add(1)
+		return
+	}
+
+	// An account may have several transactions with different nonces. A
+	// transaction with the nonce of another replaces it if it has a higher
+	// priority, so that users can bump the fee of a pending transaction.
+	var replaced *clist.CElement
+	txmp.setNonce(wtx, checkTxRes)
+	if wtx.hasNonce {
+		if elt, ok := txmp.txByNonce[accountNonce{wtx.account, wtx.nonce}]; ok {
+			if !txmp.canReplace(elt, priority) {
+				txmp.logger.Debug(
+					"rejected valid incoming transaction; tx already exists for nonce",
+					"tx", fmt.Sprintf("%X", elt.Value.(*WrappedTx).tx.Hash()),
+					"account", wtx.account,
+					"nonce", wtx.nonce,
+				)
+				return
+			}
+			replaced = elt
+		}
+	}
+
 	// Disallow multiple concurrent transactions from the same sender assigned
-	// by the ABCI application. As a special case, an empty sender is not
-	// restricted.
-	if len(sender) > 0 {
+	// by the ABCI application, unless they have a nonce or the new one replaces
+	// the existing one with a higher priority. As a special case, an empty
+	// sender is not restricted.
+	if len(sender) > 0 && !wtx.hasNonce {
 		elt, ok := txmp.txBySender[string(sender)]
-		if ok {
+		if ok && !txmp.canReplace(elt, priority) {
 			w := elt.Value.(*WrappedTx)
 			txmp.logger.Debug(
 				"rejected valid incoming transaction; tx already exists for sender",
@@ -524,79 +943,76 @@ func (txmp *TxMempool) addNewTransaction(wtx *WrappedTx, checkTxRes *abci.Respon
 			)
 			return
 		}
+		if ok {
+			replaced = elt
+		}
 	}
 
-	// At this point the application has ruled the transaction valid, but the
-	// mempool might be full. If so, find the lowest-priority items with lower
-	// priority than the application assigned to this new one, and evict as many
-	// of them as necessary to make room for tx. If no such items exist, we
-	// discard tx.
+	// Bound the number of distinct senders, and accounts of transactions with
+	// a nonce, tracked by the mempool. Those that already have a transaction
+	// in the mempool are not affected.
+	if maxSenders := txmp.config.MaxSenders; maxSenders > 0 && txmp.isNewSender(wtx, string(sender)) && txmp.numSenders() >= maxSenders {
+		txmp.cache.Remove(wtx.tx)
+		txmp.rejectedTxs.Push(wtx.tx)
+		txmp.logger.Debug(
+			"rejected valid incoming transaction; too many senders",
+			"tx", fmt.Sprintf("%X", wtx.tx.Hash()),
+			"sender", sender,
+			"max_senders", maxSenders,
+		)
+		txmp.metrics.RejectedTxs.Add(1)
+		return
+	}
 
-	if err := txmp.canAddTx(wtx); err != nil {
-		var victims []*clist.CElement // eligible transactions for eviction
-		var victimBytes int64         // total size of victims
-		for cur := txmp.txs.Front(); cur != nil; cur = cur.Next() {
-			cw := cur.Value.(*WrappedTx)
-			if cw.priority < priority {
-				victims = append(victims, cur)
-				victimBytes += cw.Size()
-			}
+	// At this point the application has ruled the transaction valid, but its
+	// lane or the mempool might be full. If so, find the lowest-priority items
+	// with lower priority than the application assigned to this new one, in
+	// the same lane if it is the lane that is full, and evict as many of them
+	// as necessary to make room for tx. If no such items exist, we discard tx.
+	// The replaced transaction, if any, makes room for tx first, and is put
+	// back if tx is discarded.
+	//
+	// The victims are only evicted once room was found both in the lane and in
+	// the mempool, so that nothing is evicted if tx is discarded.
+	var old *WrappedTx
+	if replaced != nil {
+		old = replaced.Value.(*WrappedTx)
+		txmp.removeTxByElement(replaced)
+	}
+	wtx.lane = txmp.laneOf(checkTxRes)
+	var victims []*clist.CElement
+	if err := txmp.canAddToLane(wtx); err != nil {
+		inLane := func(w *WrappedTx) bool { return w.lane == wtx.lane }
+		var ok bool
+		if victims, ok = txmp.evictionVictims(wtx, priority, inLane, nil); !ok {
+			txmp.rejectFull(wtx, err)
+			txmp.restoreReplaced(old)
+			return
 		}
-
-		// If there are no suitable eviction candidates, or the total size of
-		// those candidates is not enough to make room for the new transaction,
-		// drop the new one.
-		if len(victims) == 0 || victimBytes < wtx.Size() {
-			txmp.cache.Remove(wtx.tx)
-			txmp.logger.Error(
-				"rejected valid incoming transaction; mempool is full",
-				"tx", fmt.Sprintf("%X", wtx.tx.Hash()),
-				"err", err.Error(),
-			)
-			txmp.metrics.EvictedTxs.Add(1)
-			// Add it to evicted transactions cache
-			txmp.evictedTxs.Push(wtx.tx)
+	}
+	if err := txmp.canAddTx(wtx, victims); err != nil {
+		more, ok := txmp.evictionVictims(wtx, priority, nil, victims)
+		if !ok {
+			txmp.rejectFull(wtx, err)
+			txmp.restoreReplaced(old)
 			return
 		}
-
-		txmp.logger.Debug("evicting lower-priority transactions",
+		victims = append(victims, more...)
+	}
+	txmp.evict(wtx, priority, victims)
+	if old != nil {
+		txmp.logger.Debug(
+			"replaced existing transaction with a higher-priority one",
+			"old_tx", fmt.Sprintf("%X", old.tx.Hash()),
+			"old_priority", old.Priority(),
 			"new_tx", fmt.Sprintf("%X", wtx.tx.Hash()),
 			"new_priority", priority,
 		)
-
-		// Sort lowest priority items first so they will be evicted first.  Break
-		// ties in favor of newer items (to maintain FIFO semantics in a group).
-		sort.Slice(victims, func(i, j int) bool {
-			iw := victims[i].Value.(*WrappedTx)
-			jw := victims[j].Value.(*WrappedTx)
-			if iw.Priority() == jw.Priority() {
-				return iw.timestamp.After(jw.timestamp)
-			}
-			return iw.Priority() < jw.Priority()
-		})
-
-		// Evict as many of the victims as necessary to make room.
-		var evictedBytes int64
-		for _, vic := range victims {
-			w := vic.Value.(*WrappedTx)
-
-			txmp.logger.Debug(
-				"evicted valid existing transaction; mempool full",
-				"old_tx", fmt.Sprintf("%X", w.tx.Hash()),
-				"old_priority", w.priority,
-			)
-			txmp.removeTxByElement(vic)
-			txmp.cache.Remove(w.tx)
-			txmp.metrics.EvictedTxs.Add(1)
-			// Add it to evicted transactions cache
-			txmp.evictedTxs.Push(w.tx)
-			// We may not need to evict all the eligible transactions.  Bail out
-			// early if we have made enough room.
-			evictedBytes += w.Size()
-			if evictedBytes >= wtx.Size() {
-				break
-			}
+		if txmp.evictedTxs != nil {
+			txmp.evictedTxs.Push(old.tx)
 		}
+		txmp.metrics.ReplacedTxs.Add(1)
+		txmp.notifyEvicted(old.tx, types.TxEvictedReplaced)
 	}
 
 	wtx.SetGasWanted(checkTxRes.GasWanted)
@@ -605,6 +1021,7 @@ func (txmp *TxMempool) addNewTransaction(wtx *WrappedTx, checkTxRes *abci.Respon
 	txmp.insertTx(wtx)
 
 	txmp.metrics.TxSizeBytes.Observe(float64(wtx.Size()))
+	txmp.metrics.TxPriority.Observe(float64(wtx.priority))
 	txmp.metrics.Size.Set(float64(txmp.Size()))
 	txmp.metrics.SizeBytes.Set(float64(txmp.SizeBytes()))
 	txmp.logger.Debug(
@@ -617,12 +1034,152 @@ func (txmp *TxMempool) addNewTransaction(wtx *WrappedTx, checkTxRes *abci.Respon
 	txmp.notifyTxsAvailable()
 }
 
+// evictionVictims returns the lowest-priority transactions with a lower
+// priority than wtx, among those for which filter returns true if it is not nil
+// and which are not in exclude, whose eviction makes room for wtx. Reserved
+// transactions are never evicted. It reports false if evicting all of the
+// eligible transactions would not make enough room. Nothing is evicted.
+//
+// The caller must hold txmp.mtx.
+func (txmp *TxMempool) evictionVictims(
+	wtx *WrappedTx,
+	priority int64,
+	filter func(*WrappedTx) bool,
+	exclude []*clist.CElement,
+) ([]*clist.CElement, bool) {
+	excluded := make(map[*clist.CElement]struct{}, len(exclude))
+	for _, elt := range exclude {
+		excluded[elt] = struct{}{}
+	}
+	var victims []*clist.CElement // eligible transactions for eviction
+	var victimBytes int64         // total size of victims
+	for cur := txmp.txs.Front(); cur != nil; cur = cur.Next() {
+		cw := cur.Value.(*WrappedTx)
+		if _, ok := excluded[cur]; ok {
+			continue
+		}
+		if cw.priority < priority && txmp.reserved[cw.tx.Key()] == 0 && (filter == nil || filter(cw)) {
+			victims = append(victims, cur)
+			victimBytes += cw.Size()
+		}
+	}
+
+	// If there are no suitable eviction candidates, or the total size of
+	// those candidates is not enough to make room for the new transaction,
+	// the new one must be dropped.
+	if len(victims) == 0 || victimBytes < wtx.Size() {
+		return nil, false
+	}
+
+	// Sort lowest priority items first so they will be evicted first.  Break
+	// ties in favor of newer items (to maintain FIFO semantics in a group).
+	sort.Slice(victims, func(i, j int) bool {
+		iw := victims[i].Value.(*WrappedTx)
+		jw := victims[j].Value.(*WrappedTx)
+		if iw.Priority() == jw.Priority() {
+			return iw.timestamp.After(jw.timestamp)
+		}
+		return iw.Priority() < jw.Priority()
+	})
+
+	// We may not need to evict all the eligible transactions. Stop once we
+	// have made enough room.
+	var evictedBytes int64
+	for i, vic := range victims {
+		evictedBytes += vic.Value.(*WrappedTx).Size()
+		if evictedBytes >= wtx.Size() {
+			return victims[:i+1], true
+		}
+	}
+	return victims, true
+}
+
+// evict evicts the victims to make room for wtx.
+//
+// The caller must hold txmp.mtx exclusively.
+func (txmp *TxMempool) evict(wtx *WrappedTx, priority int64, victims []*clist.CElement) {
+	if len(victims) == 0 {
+		return
+	}
+	txmp.logger.Debug("evicting lower-priority transactions",
+		"new_tx", fmt.Sprintf("%X", wtx.tx.Hash()),
+		"new_priority", priority,
+	)
+	for _, vic := range victims {
+		w := vic.Value.(*WrappedTx)
+
+		txmp.logger.Debug(
+			"evicted valid existing transaction; mempool full",
+			"old_tx", fmt.Sprintf("%X", w.tx.Hash()),
+			"old_priority", w.priority,
+		)
+		txmp.removeTxByElement(vic)
+		txmp.cache.Remove(w.tx)
+		txmp.metrics.EvictedTxs.Add(1)
+		// Add it to evicted transactions cache
+		txmp.evictedTxs.Push(w.tx)
+		txmp.notifyEvicted(w.tx, types.TxEvictedMempoolFull)
+	}
+}
+
+// rejectFull drops wtx, which could not be added because of err, as there is
+// no room for it in its lane or in the mempool.
+//
+// The caller must hold txmp.mtx exclusively.
+func (txmp *TxMempool) rejectFull(wtx *WrappedTx, err error) {
+	txmp.cache.Remove(wtx.tx)
+	txmp.logger.Error(
+		"rejected valid incoming transaction; mempool is full",
+		"tx", fmt.Sprintf("%X", wtx.tx.Hash()),
+		"err", err.Error(),
+	)
+	txmp.metrics.EvictedTxs.Add(1)
+	// Add it to evicted transactions cache
+	txmp.evictedTxs.Push(wtx.tx)
+	txmp.notifyEvicted(wtx.tx, types.TxEvictedMempoolFull)
+}
+
+// canReplace reports whether a new transaction with the given priority may
+// replace the transaction at elt, which has the same sender or the same
+// account and nonce. Transactions reserved for a proposal aren't replaced.
+//
+// The caller must hold txmp.mtx.
+func (txmp *TxMempool) canReplace(elt *clist.CElement, priority int64) bool {
+	w := elt.Value.(*WrappedTx)
+	return priority > w.Priority() && txmp.reserved[w.tx.Key()] == 0
+}
+
+// restoreReplaced puts back a transaction removed to be replaced by one that
+// was discarded after all. It does nothing if old is nil.
+//
+// The caller must hold txmp.mtx exclusively.
+func (txmp *TxMempool) restoreReplaced(old *WrappedTx) {
+	if old != nil {
+		txmp.insertTx(old)
+	}
+}
+
+// removeSender removes w from the sender or nonce index.
+// The caller must hold txmp.mtx exclusively.
+func (txmp *TxMempool) removeSender(w *WrappedTx) {
+	if w.hasNonce {
+		delete(txmp.txByNonce, accountNonce{w.account, w.nonce})
+	} else {
+		delete(txmp.txBySender, w.sender)
+	}
+}
+
 func (txmp *TxMempool) insertTx(wtx *WrappedTx) {
 	elt := txmp.txs.PushBack(wtx)
 	txmp.txByKey[wtx.tx.Key()] = elt
-	if s := wtx.Sender(); s != "" {
+	if wtx.hasNonce {
+		txmp.txByNonce[accountNonce{wtx.account, wtx.nonce}] = elt
+	} else if s := wtx.Sender(); s != "" {
 		txmp.txBySender[s] = elt
 	}
+	txmp.trackLane(wtx, 1)
+	txmp.addToOrder(wtx)
+	txmp.journalAdd(wtx)
 
 	atomic.AddInt64(&txmp.txsBytes, wtx.Size())
 }
@@ -652,7 +1209,11 @@ func (txmp *TxMempool) handleRecheckResult(tx *types.CachedTx, checkTxRes *abci.
 	}
 
 	if checkTxRes.Code == abci.CodeTypeOK && err == nil {
-		wtx.SetPriority(checkTxRes.Priority)
+		if checkTxRes.Priority != wtx.Priority() {
+			txmp.removeFromOrder(wtx)
+			wtx.SetPriority(checkTxRes.Priority)
+			txmp.addToOrder(wtx)
+		}
 		return // N.B. Size of mempool did not change
 	}
 
@@ -666,6 +1227,7 @@ func (txmp *TxMempool) handleRecheckResult(tx *types.CachedTx, checkTxRes *abci.
 	txmp.rejectedTxs.Push(wtx.tx)
 	txmp.removeTxByElement(elt)
 	txmp.metrics.FailedTxs.Add(1)
+	txmp.notifyEvicted(wtx.tx, types.TxEvictedRecheckFailed)
 	if !txmp.config.KeepInvalidTxsInCache {
 		txmp.cache.Remove(wtx.tx)
 	}
@@ -674,8 +1236,7 @@ func (txmp *TxMempool) handleRecheckResult(tx *types.CachedTx, checkTxRes *abci.
 }
 
 // recheckTransactions initiates re-CheckTx ABCI calls for all the transactions
-// currently in the mempool. It reports the number of recheck calls that were
-// successfully initiated.
+// currently in the mempool, in the background if rechecks are asynchronous.
 //
 // Precondition: The mempool is not empty.
 // The caller must hold txmp.mtx exclusively.
@@ -687,7 +1248,9 @@ func (txmp *TxMempool) recheckTransactions() {
 		"executing re-CheckTx for all remaining transactions",
 		"num_txs", txmp.Size(),
 		"height", txmp.height,
+		"async", txmp.config.RecheckAsync,
 	)
+	txmp.lastRecheckHeight = txmp.height
 
 	// Collect transactions currently in the mempool requiring recheck.
 	wtxs := make([]*WrappedTx, 0, txmp.txs.Len())
@@ -695,35 +1258,57 @@ func (txmp *TxMempool) recheckTransactions() {
 		wtxs = append(wtxs, e.Value.(*WrappedTx))
 	}
 
+	if txmp.config.RecheckAsync {
+		txmp.startAsyncRecheck(wtxs)
+		return
+	}
+	// A synchronous recheck supersedes an asynchronous one in progress.
+	if txmp.cancelRecheck != nil {
+		txmp.cancelRecheck()
+		txmp.cancelRecheck = nil
+	}
+
 	// Issue CheckTx calls for each remaining transaction, and when all the
 	// rechecks are complete signal watchers that transactions may be available.
 	for _, wtx := range wtxs {
-		wtx := wtx
-		// The response for this CheckTx is handled by the default recheckTxCallback.
-		rsp, err := txmp.proxyAppConn.CheckTx(context.Background(), &abci.RequestCheckTx{
-			Tx:   wtx.tx.Tx,
-			Type: abci.CheckTxType_Recheck,
-		})
-		if err != nil {
-			txmp.logger.Error("failed to execute CheckTx during recheck",
-				"err", err, "hash", fmt.Sprintf("%x", wtx.tx.Hash()))
-		} else {
-			txmp.handleRecheckResult(wtx.tx, rsp)
-		}
+		txmp.recheckTx(context.Background(), wtx)
 	}
 	_ = txmp.proxyAppConn.Flush(context.TODO())
 
 	txmp.notifyTxsAvailable()
 }
 
+// recheckTx rechecks wtx with the application and handles the result.
+//
+// The caller must hold txmp.mtx exclusively.
+func (txmp *TxMempool) recheckTx(ctx context.Context, wtx *WrappedTx) {
+	rsp, err := txmp.proxyAppConn.CheckTx(ctx, &abci.RequestCheckTx{
+		Tx:   wtx.tx.Tx,
+		Type: abci.CheckTxType_Recheck,
+	})
+	if err != nil {
+		txmp.logger.Error("failed to execute CheckTx during recheck",
+			"err", err, "hash", fmt.Sprintf("%x", wtx.tx.Hash()))
+		return
+	}
+	txmp.handleRecheckResult(wtx.tx, rsp)
+}
+
 // canAddTx returns an error if we cannot insert the provided *WrappedTx into
-// the mempool due to mempool configured constraints. Otherwise, nil is
-// returned and the transaction can be inserted into the mempool.
-func (txmp *TxMempool) canAddTx(wtx *WrappedTx) error {
+// the mempool due to mempool configured constraints, once the given victims
+// are evicted. Otherwise, nil is returned and the transaction can be inserted
+// into the mempool.
+func (txmp *TxMempool) canAddTx(wtx *WrappedTx, victims []*clist.CElement) error {
 	numTxs := txmp.Size()
 	txBytes := txmp.SizeBytes()
 
-	if numTxs >= txmp.config.Size || wtx.Size()+txBytes > txmp.config.MaxTxsBytes {
+	freedTxs := len(victims)
+	var freedBytes int64
+	for _, vic := range victims {
+		freedBytes += vic.Value.(*WrappedTx).Size()
+	}
+
+	if numTxs-freedTxs >= txmp.config.Size || wtx.Size()+txBytes-freedBytes > txmp.config.MaxTxsBytes {
 		return mempool.ErrMempoolIsFull{
 			NumTxs:      numTxs,
 			MaxTxs:      txmp.config.Size,
@@ -747,8 +1332,10 @@ func (txmp *TxMempool) CheckToPurgeExpiredTxs() {
 }
 
 // purgeExpiredTxs removes all transactions from the mempool that have exceeded
-// their respective height or time-based limits as of the given blockHeight.
-// Transactions removed by this operation are not removed from the cache.
+// their respective height or time-based limits as of the given blockHeight:
+// those not committed within TTLNumBlocks blocks or TTLDuration. Transactions
+// removed by this operation are removed from the cache, so that they can be
+// submitted again.
 //
 // The caller must hold txmp.mtx exclusively.
 func (txmp *TxMempool) purgeExpiredTxs(blockHeight int64) {
@@ -764,12 +1351,24 @@ func (txmp *TxMempool) purgeExpiredTxs(blockHeight int64) {
 		next := cur.Next()
 
 		w := cur.Value.(*WrappedTx)
-		if txmp.config.TTLNumBlocks > 0 && (blockHeight-w.height) > txmp.config.TTLNumBlocks ||
-			txmp.config.TTLDuration > 0 && now.Sub(w.timestamp) > txmp.config.TTLDuration {
+		if txmp.reserved[w.tx.Key()] > 0 {
+			cur = next
+			continue
+		}
+		var mechanism string
+		switch {
+		case txmp.config.TTLNumBlocks > 0 && (blockHeight-w.height) > txmp.config.TTLNumBlocks:
+			mechanism = "height"
+		case txmp.config.TTLDuration > 0 && now.Sub(w.timestamp) > txmp.config.TTLDuration:
+			mechanism = "duration"
+		}
+		if mechanism != "" {
 			txmp.removeTxByElement(cur)
 			txmp.cache.Remove(w.tx)
 			txmp.evictedTxs.Push(w.tx)
 			txmp.metrics.ExpiredTxs.Add(1)
+			txmp.metrics.ExpiredTxsByMechanism.With("mechanism", mechanism).Add(1)
+			txmp.notifyEvicted(w.tx, types.TxEvictedExpired)
 		}
 		cur = next
 	}
@@ -777,6 +1376,15 @@ func (txmp *TxMempool) purgeExpiredTxs(blockHeight int64) {
 	txmp.lastPurgeTime = now
 }
 
+// notifyEvicted counts the eviction of tx for the given reason and queues it
+// for the eviction callback, if any.
+func (txmp *TxMempool) notifyEvicted(tx *types.CachedTx, reason string) {
+	txmp.metrics.EvictedTxsByReason.With("reason", reason).Add(1)
+	if txmp.evictions != nil && !txmp.evictions.Push(types.EventDataTxEvicted{Tx: tx.Tx, Reason: reason}) {
+		txmp.logger.Error("dropped eviction notification; too many pending", "tx", fmt.Sprintf("%X", tx.Hash()), "reason", reason)
+	}
+}
+
 func (txmp *TxMempool) notifyTxsAvailable() {
 	if txmp.Size() == 0 {
 		return // nothing to do
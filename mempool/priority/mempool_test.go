@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"math/rand"
 	"os"
+	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
@@ -15,6 +16,7 @@ import (
 	"time"
 
 	share "github.com/celestiaorg/go-square/v2/share"
+	"github.com/go-kit/kit/metrics"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -113,6 +115,16 @@ func mustCheckTx(t *testing.T, txmp *TxMempool, spec string) {
 	<-done
 }
 
+// txExists reports whether the transaction with the given spec is in the
+// mempool.
+func txExists(t *testing.T, txmp *TxMempool, spec string) bool {
+	t.Helper()
+	txmp.Lock()
+	defer txmp.Unlock()
+	_, ok := txmp.txByKey[types.Tx(spec).Key()]
+	return ok
+}
+
 // checkTxs generates a specified number of txs, checks them into the mempool,
 // and returns them.
 func checkTxs(t *testing.T, txmp *TxMempool, numTxs int, peerID uint16) []testTx {
@@ -220,14 +232,6 @@ func TestTxMempool_Eviction(t *testing.T) {
 	txmp := setup(t, 1000)
 	txmp.config.Size = 5
 	txmp.config.MaxTxsBytes = 60
-	txExists := func(spec string) bool {
-		txmp.Lock()
-		defer txmp.Unlock()
-		key := types.Tx(spec).Key()
-		_, ok := txmp.txByKey[key]
-		return ok
-	}
-
 	// A transaction bigger than the mempool should be rejected even when there
 	// are slots available.
 	mustCheckTx(t, txmp, "big=0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef=1")
@@ -238,14 +242,14 @@ func TestTxMempool_Eviction(t *testing.T) {
 	const bigTx = "big=0123456789abcdef0123456789abcdef0123456789abcdef01234=2"
 	mustCheckTx(t, txmp, bigTx)
 	require.Equal(t, 1, txmp.Size()) // bigTx is the only element
-	require.True(t, txExists(bigTx))
+	require.True(t, txExists(t, txmp, bigTx))
 	require.Equal(t, int64(len(bigTx)), txmp.SizeBytes())
 
 	// The next transaction should evict bigTx, because it is higher priority
 	// but does not fit on size.
 	mustCheckTx(t, txmp, "key1=0000=25")
-	require.True(t, txExists("key1=0000=25"))
-	require.False(t, txExists(bigTx))
+	require.True(t, txExists(t, txmp, "key1=0000=25"))
+	require.False(t, txExists(t, txmp, bigTx))
 	bigTxKey := types.Tx((bigTx)).Key()
 	require.False(t, txmp.cache.HasKey(bigTxKey))
 	require.True(t, txmp.WasRecentlyEvicted(bigTxKey)) // bigTx evicted
@@ -259,42 +263,82 @@ func TestTxMempool_Eviction(t *testing.T) {
 
 	// A new transaction with low priority should be discarded.
 	mustCheckTx(t, txmp, "key6=0005=1")
-	require.False(t, txExists("key6=0005=1"))
+	require.False(t, txExists(t, txmp, "key6=0005=1"))
 	require.True(t, txmp.WasRecentlyEvicted(types.Tx(("key6=0005=1")).Key())) // key6 evicted
 
 	// A new transaction with higher priority should evict key5, which is the
 	// newest of the two transactions with lowest priority.
 	mustCheckTx(t, txmp, "key7=0006=7")
-	require.True(t, txExists("key7=0006=7"))                                  // new transaction added
-	require.False(t, txExists("key5=0004=3"))                                 // newest low-priority tx evicted
+	require.True(t, txExists(t, txmp, "key7=0006=7"))                         // new transaction added
+	require.False(t, txExists(t, txmp, "key5=0004=3"))                        // newest low-priority tx evicted
 	require.True(t, txmp.WasRecentlyEvicted(types.Tx(("key5=0004=3")).Key())) // key5 evicted
-	require.True(t, txExists("key4=0003=3"))                                  // older low-priority tx retained
+	require.True(t, txExists(t, txmp, "key4=0003=3"))                         // older low-priority tx retained
 
 	// Another new transaction evicts the other low-priority element.
 	mustCheckTx(t, txmp, "key8=0007=20")
-	require.True(t, txExists("key8=0007=20"))
-	require.False(t, txExists("key4=0003=3"))
+	require.True(t, txExists(t, txmp, "key8=0007=20"))
+	require.False(t, txExists(t, txmp, "key4=0003=3"))
 
 	// Now the lowest-priority tx is 5, so that should be the next to go.
 	mustCheckTx(t, txmp, "key9=0008=9")
-	require.True(t, txExists("key9=0008=9"))
-	require.False(t, txExists("key2=0001=5"))
+	require.True(t, txExists(t, txmp, "key9=0008=9"))
+	require.False(t, txExists(t, txmp, "key2=0001=5"))
 	require.True(t, txmp.WasRecentlyEvicted(types.Tx(("key2=0001=5")).Key())) // key2 evicted
 
 	// Add a transaction that requires eviction of multiple lower-priority
 	// entries, in order to fit the size of the element.
 	mustCheckTx(t, txmp, "key10=0123456789abcdef=11") // evict 10, 9, 7; keep 25, 20, 11
-	require.True(t, txExists("key1=0000=25"))
-	require.True(t, txExists("key8=0007=20"))
-	require.True(t, txExists("key10=0123456789abcdef=11"))
-	require.False(t, txExists("key3=0002=10"))
+	require.True(t, txExists(t, txmp, "key1=0000=25"))
+	require.True(t, txExists(t, txmp, "key8=0007=20"))
+	require.True(t, txExists(t, txmp, "key10=0123456789abcdef=11"))
+	require.False(t, txExists(t, txmp, "key3=0002=10"))
 	require.True(t, txmp.WasRecentlyEvicted(types.Tx(("key3=0002=10")).Key())) // key3 evicted
-	require.False(t, txExists("key9=0008=9"))
+	require.False(t, txExists(t, txmp, "key9=0008=9"))
 	require.True(t, txmp.WasRecentlyEvicted(types.Tx(("key9=0008=9")).Key())) // key9 evicted
-	require.False(t, txExists("key7=0006=7"))
+	require.False(t, txExists(t, txmp, "key7=0006=7"))
 	require.True(t, txmp.WasRecentlyEvicted(types.Tx(("key7=0006=7")).Key())) // key7 evicted
 }
 
+func TestTxMempool_Reserve(t *testing.T) {
+	txmp := setup(t, 1000)
+	txmp.config.Size = 3
+	mustCheckTx(t, txmp, "key1=0000=1")
+	mustCheckTx(t, txmp, "key2=0001=2")
+	mustCheckTx(t, txmp, "key3=0002=3")
+	require.Equal(t, 3, txmp.Size())
+
+	// Reserve the two lowest-priority transactions, as if they had been reaped
+	// for a proposal.
+	release := txmp.Reserve([]types.TxKey{
+		types.Tx("key1=0000=1").Key(),
+		types.Tx("key2=0001=2").Key(),
+	})
+
+	// A higher-priority transaction evicts the only unreserved one.
+	mustCheckTx(t, txmp, "key4=0003=9")
+	require.True(t, txExists(t, txmp, "key4=0003=9"))
+	require.False(t, txExists(t, txmp, "key3=0002=3"))
+	require.True(t, txExists(t, txmp, "key1=0000=1"))
+	require.True(t, txExists(t, txmp, "key2=0001=2"))
+
+	// With no unreserved lower-priority transaction left, the new one is
+	// rejected and the reserved ones survive.
+	mustCheckTx(t, txmp, "key5=0004=5")
+	require.False(t, txExists(t, txmp, "key5=0004=5"))
+	require.True(t, txmp.WasRecentlyEvicted(types.Tx("key5=0004=5").Key()))
+	require.True(t, txExists(t, txmp, "key1=0000=1"))
+	require.True(t, txExists(t, txmp, "key2=0001=2"))
+
+	// Once released, the reserved transactions can be evicted again. Releasing
+	// twice is harmless.
+	release()
+	release()
+	mustCheckTx(t, txmp, "key6=0005=6")
+	require.True(t, txExists(t, txmp, "key6=0005=6"))
+	require.False(t, txExists(t, txmp, "key1=0000=1"))
+	require.True(t, txExists(t, txmp, "key2=0001=2"))
+}
+
 func TestTxMempool_Flush(t *testing.T) {
 	txmp := setup(t, 0)
 	txs := checkTxs(t, txmp, 100, 0)
@@ -446,6 +490,122 @@ func TestTxMempool_ReapMaxTxs(t *testing.T) {
 	require.Len(t, reapedTxs, len(tTxs)/2)
 }
 
+func TestTxMempool_Iterate(t *testing.T) {
+	txmp := setup(t, 0)
+	tTxs := checkTxs(t, txmp, 100, 0)
+
+	priorities := make([]int64, len(tTxs))
+	for i, tTx := range tTxs {
+		priorities[i] = tTx.priority
+	}
+	sort.Slice(priorities, func(i, j int) bool { return priorities[i] > priorities[j] })
+
+	// Transactions are visited in priority order.
+	var visited []int64
+	txmp.Iterate(func(tx *WrappedTx) bool {
+		visited = append(visited, tx.Priority())
+		return true
+	})
+	require.Equal(t, priorities, visited)
+
+	// Iteration stops once the callback returns false, e.g. when a block is
+	// full.
+	var totalBytes int64
+	visited = nil
+	txmp.Iterate(func(tx *WrappedTx) bool {
+		if totalBytes+tx.Size() > 580 {
+			return false
+		}
+		totalBytes += tx.Size()
+		visited = append(visited, tx.Priority())
+		return true
+	})
+	require.Equal(t, priorities[:10], visited)
+	require.Equal(t, len(tTxs), txmp.Size())
+
+	// The order is kept as transactions are committed and rechecked.
+	rawTxs := make([]types.Tx, 50)
+	responses := make([]*abci.ExecTxResult, len(rawTxs))
+	for i := range rawTxs {
+		rawTxs[i] = tTxs[i].tx
+		responses[i] = &abci.ExecTxResult{Code: abci.CodeTypeOK}
+	}
+	txmp.Lock()
+	require.NoError(t, txmp.Update(1, types.CachedTxFromTxs(rawTxs), responses, nil, nil))
+	txmp.Unlock()
+
+	priorities = priorities[:0]
+	for _, tTx := range tTxs[50:] {
+		priorities = append(priorities, tTx.priority)
+	}
+	sort.Slice(priorities, func(i, j int) bool { return priorities[i] > priorities[j] })
+	visited = nil
+	txmp.Iterate(func(tx *WrappedTx) bool {
+		visited = append(visited, tx.Priority())
+		return true
+	})
+	require.Equal(t, priorities, visited)
+}
+
+func TestTxMempool_Requeue(t *testing.T) {
+	txmp := setup(t, 0)
+	tTxs := checkTxs(t, txmp, 10, 0)
+	priorities := make(map[types.TxKey]int64, len(tTxs))
+	for _, tTx := range tTxs {
+		priorities[tTx.tx.Key()] = tTx.priority
+	}
+
+	reapedTxs := txmp.ReapMaxTxs(-1)
+	require.Len(t, reapedTxs, len(tTxs))
+
+	// The reaped transactions leave the mempool, e.g. while proposed in a
+	// block that then fails to commit.
+	for _, tx := range reapedTxs {
+		require.NoError(t, txmp.RemoveTxByKey(tx.Key()))
+	}
+	require.Zero(t, txmp.Size())
+
+	// One of them is received again through gossip before being requeued.
+	require.NoError(t, txmp.CheckTx(reapedTxs[0].Tx, nil, mempool.TxInfo{}))
+	require.Equal(t, 1, txmp.Size())
+
+	txs := make(types.Txs, len(reapedTxs))
+	for i, tx := range reapedTxs {
+		txs[i] = tx.Tx
+	}
+	require.NoError(t, txmp.Requeue(txs))
+	require.Equal(t, len(tTxs), txmp.Size())
+	require.Equal(t, int64(580), txmp.SizeBytes())
+
+	// All transactions can be reaped again, once each, with their original
+	// priority.
+	reapedAgain := txmp.ReapMaxTxs(-1)
+	require.Len(t, reapedAgain, len(tTxs))
+	seen := make(map[types.TxKey]bool)
+	for _, tx := range reapedAgain {
+		require.False(t, seen[tx.Key()])
+		seen[tx.Key()] = true
+		elt := txmp.txByKey[tx.Key()]
+		require.Equal(t, priorities[tx.Key()], elt.Value.(*WrappedTx).Priority())
+	}
+
+	// Requeuing again is a no-op.
+	require.NoError(t, txmp.Requeue(txs))
+	require.Equal(t, len(tTxs), txmp.Size())
+
+	// Transactions that were never reaped cannot be requeued.
+	require.Error(t, txmp.Requeue(types.Txs{types.Tx("sender=unknown=1")}))
+
+	// If the mempool can't hold all of the transactions, none is requeued.
+	for _, tx := range reapedAgain {
+		require.NoError(t, txmp.RemoveTxByKey(tx.Key()))
+	}
+	txmp.config.Size = len(tTxs) - 1
+	require.Error(t, txmp.Requeue(txs))
+	require.Zero(t, txmp.Size())
+	require.Zero(t, txmp.SizeBytes())
+}
+
 func TestTxMempool_CheckTxExceedsMaxSize(t *testing.T) {
 	txmp := setup(t, 1)
 
@@ -500,6 +660,384 @@ func TestTxMempool_CheckTxSameSender(t *testing.T) {
 	require.Equal(t, 1, txmp.Size())
 }
 
+func TestTxMempool_MaxSenders(t *testing.T) {
+	txmp := setup(t, 100)
+	txmp.config.MaxSenders = 3
+	// Fill up the sender slots.
+	mustCheckTx(t, txmp, "sender1=0000=1")
+	mustCheckTx(t, txmp, "sender2=0001=1")
+	mustCheckTx(t, txmp, "sender3=0002=1")
+	require.Equal(t, 3, txmp.Size())
+
+	// Transactions from new senders are rejected, even with a higher priority.
+	mustCheckTx(t, txmp, "sender4=0003=1")
+	mustCheckTx(t, txmp, "sender5=0004=100")
+	require.False(t, txExists(t, txmp, "sender4=0003=1"))
+	require.False(t, txExists(t, txmp, "sender5=0004=100"))
+	require.False(t, txmp.cache.HasKey(types.Tx("sender4=0003=1").Key()))
+	require.True(t, txmp.WasRecentlyRejected(types.Tx("sender4=0003=1").Key()))
+	require.True(t, txmp.WasRecentlyRejected(types.Tx("sender5=0004=100").Key()))
+
+	// Transactions without a sender are not affected by the limit.
+	mustCheckTx(t, txmp, "=0005=1")
+	require.True(t, txExists(t, txmp, "=0005=1"))
+	require.Equal(t, 4, txmp.Size())
+
+	// Once an existing sender's transaction is committed, it can submit again
+	// and its slot is not taken by the previously rejected senders.
+	txmp.Lock()
+	require.NoError(t, txmp.Update(1,
+		[]*types.CachedTx{types.Tx("sender1=0000=1").ToCachedTx()},
+		[]*abci.ExecTxResult{{Code: abci.CodeTypeOK}}, nil, nil))
+	txmp.Unlock()
+	mustCheckTx(t, txmp, "sender1=0006=1")
+	require.True(t, txExists(t, txmp, "sender1=0006=1"))
+	mustCheckTx(t, txmp, "sender4=0003=1")
+	require.False(t, txExists(t, txmp, "sender4=0003=1"))
+}
+
+// testNonce returns the sender of a test transaction as its account, and its
+// key as its nonce, if the key is a number.
+func testNonce(tx types.Tx, res *abci.ResponseCheckTx) (string, uint64, bool) {
+	parts := bytes.Split(tx, []byte("="))
+	nonce, err := strconv.ParseUint(string(parts[1]), 10, 64)
+	if err != nil {
+		return "", 0, false
+	}
+	return string(res.Address), nonce, true
+}
+
+func TestTxMempool_Nonces(t *testing.T) {
+	txmp := setup(t, 100, WithNonces(testNonce))
+	reap := func(maxBytes int64) []string {
+		var specs []string
+		for _, tx := range txmp.ReapMaxBytesMaxGas(maxBytes, -1) {
+			specs = append(specs, string(tx.Tx))
+		}
+		return specs
+	}
+
+	// An account may have several transactions, which are reaped in nonce
+	// order whatever the order they arrived in.
+	mustCheckTx(t, txmp, "alice=2=5")
+	mustCheckTx(t, txmp, "alice=1=5")
+	mustCheckTx(t, txmp, "bob=1=7")
+	mustCheckTx(t, txmp, "alice=3=9")
+	require.Equal(t, 4, txmp.Size())
+	require.Equal(t, []string{"alice=1=5", "bob=1=7", "alice=2=5", "alice=3=9"}, reap(-1))
+
+	// A transaction with the nonce of another of the account is rejected,
+	// unless it has a higher priority.
+	mustCheckTx(t, txmp, "alice=1=4")
+	require.Equal(t, 4, txmp.Size())
+
+	// Transactions without a nonce are still limited to one per sender.
+	mustCheckTx(t, txmp, "carol=x=1")
+	mustCheckTx(t, txmp, "carol=y=1")
+	require.Equal(t, 5, txmp.Size())
+
+	// Later nonces aren't reaped if an earlier one doesn't fit.
+	size := types.ComputeProtoSizeForTxs([]types.Tx{types.Tx("alice=1=5")})
+	require.Equal(t, []string{"alice=1=5", "bob=1=7"}, reap(2*size))
+
+	// Removing a transaction frees its nonce.
+	blockTxs := types.CachedTxFromTxs(types.Txs{types.Tx("alice=1=5")})
+	require.NoError(t, txmp.Update(1, blockTxs, abciResponses(1, abci.CodeTypeOK), nil, nil))
+	mustCheckTx(t, txmp, "alice=1=8")
+	require.Equal(t, 5, txmp.Size())
+}
+
+func TestTxMempool_MaxSendersNonces(t *testing.T) {
+	txmp := setup(t, 100, WithNonces(testNonce))
+	txmp.config.MaxSenders = 2
+
+	// Accounts count as senders, whatever their number of transactions.
+	mustCheckTx(t, txmp, "alice=1=1")
+	mustCheckTx(t, txmp, "alice=2=1")
+	mustCheckTx(t, txmp, "bob=1=1")
+	require.Equal(t, 3, txmp.Size())
+
+	// Transactions of new accounts or senders are rejected.
+	mustCheckTx(t, txmp, "carol=1=100")
+	mustCheckTx(t, txmp, "dave=x=100")
+	require.False(t, txExists(t, txmp, "carol=1=100"))
+	require.False(t, txExists(t, txmp, "dave=x=100"))
+	require.True(t, txmp.WasRecentlyRejected(types.Tx("carol=1=100").Key()))
+
+	// Known accounts may still add transactions.
+	mustCheckTx(t, txmp, "alice=3=1")
+	require.True(t, txExists(t, txmp, "alice=3=1"))
+
+	var buf bytes.Buffer
+	txmp.WritePrometheus(&buf)
+	require.Contains(t, buf.String(), "senders 2\n")
+}
+
+func TestTxMempool_RecheckPolicy(t *testing.T) {
+	// The post-check hook set on Update rejects every transaction on recheck.
+	rejectAll := func(*types.CachedTx, *abci.ResponseCheckTx) error {
+		return errors.New("rejected")
+	}
+	update := func(txmp *TxMempool, height int64) {
+		txmp.Lock()
+		defer txmp.Unlock()
+		require.NoError(t, txmp.Update(height, nil, nil, nil, rejectAll))
+	}
+
+	t.Run("every_n_blocks", func(t *testing.T) {
+		txmp := setup(t, 100)
+		txmp.config.RecheckPolicy = config.RecheckPolicyEveryNBlocks
+		txmp.config.RecheckInterval = 2
+		checkTxs(t, txmp, 10, 0)
+
+		update(txmp, 1)
+		require.Equal(t, 10, txmp.Size())
+		update(txmp, 2)
+		require.Zero(t, txmp.Size())
+	})
+
+	t.Run("on_demand", func(t *testing.T) {
+		txmp := setup(t, 100)
+		txmp.config.RecheckPolicy = config.RecheckPolicyOnDemand
+		checkTxs(t, txmp, 10, 0)
+
+		update(txmp, 1)
+		require.Equal(t, 10, txmp.Size())
+		require.Equal(t, 10, txmp.Recheck())
+		require.Zero(t, txmp.Size())
+	})
+
+	t.Run("async", func(t *testing.T) {
+		txmp := setup(t, 100)
+		txmp.config.RecheckAsync = true
+		checkTxs(t, txmp, 10, 0)
+
+		update(txmp, 1)
+		require.Eventually(t, func() bool { return txmp.Size() == 0 }, time.Second, 10*time.Millisecond)
+	})
+}
+
+func TestTxMempool_Replacement(t *testing.T) {
+	nonceFn := func(tx types.Tx, res *abci.ResponseCheckTx) (string, uint64, bool) {
+		parts := bytes.Split(tx, []byte("="))
+		nonce, err := strconv.ParseUint(string(parts[1]), 10, 64)
+		if err != nil {
+			return "", 0, false
+		}
+		return string(res.Address), nonce, true
+	}
+	txmp := setup(t, 100, WithNonces(nonceFn))
+	// A transaction of the same account and nonce with a higher priority
+	// replaces the existing one.
+	mustCheckTx(t, txmp, "alice=1=5")
+	mustCheckTx(t, txmp, "alice=1=9")
+	require.True(t, txExists(t, txmp, "alice=1=9"))
+	require.False(t, txExists(t, txmp, "alice=1=5"))
+	require.True(t, txmp.WasRecentlyEvicted(types.Tx("alice=1=5").Key()))
+	mustCheckTx(t, txmp, "alice=1=7")
+	require.False(t, txExists(t, txmp, "alice=1=7"))
+
+	// So does a transaction of the same sender without a nonce.
+	mustCheckTx(t, txmp, "bob=x=1")
+	mustCheckTx(t, txmp, "bob=y=2")
+	require.True(t, txExists(t, txmp, "bob=y=2"))
+	require.False(t, txExists(t, txmp, "bob=x=1"))
+	require.Equal(t, 2, txmp.Size())
+
+	// Reserved transactions aren't replaced.
+	release := txmp.Reserve([]types.TxKey{types.Tx("bob=y=2").Key()})
+	mustCheckTx(t, txmp, "bob=z=3")
+	require.True(t, txExists(t, txmp, "bob=y=2"))
+	require.False(t, txExists(t, txmp, "bob=z=3"))
+	release()
+
+	// A replacement that doesn't fit leaves the existing transaction in place.
+	txmp.config.MaxTxsBytes = txmp.SizeBytes()
+	mustCheckTx(t, txmp, "alice=1=00000000000000000020")
+	require.False(t, txExists(t, txmp, "alice=1=00000000000000000020"))
+	require.True(t, txExists(t, txmp, "alice=1=9"))
+	require.Equal(t, 2, txmp.Size())
+}
+
+func TestTxMempool_MinPriority(t *testing.T) {
+	txmp := setup(t, 100)
+	txmp.config.MinPriority = 10
+	// Transactions at or above the floor are accepted.
+	mustCheckTx(t, txmp, "sender1=0000=10")
+	mustCheckTx(t, txmp, "sender2=0001=100")
+	require.True(t, txExists(t, txmp, "sender1=0000=10"))
+	require.True(t, txExists(t, txmp, "sender2=0001=100"))
+
+	// Transactions below the floor are rejected and can be resubmitted.
+	mustCheckTx(t, txmp, "sender3=0002=9")
+	mustCheckTx(t, txmp, "=0003=1")
+	require.False(t, txExists(t, txmp, "sender3=0002=9"))
+	require.False(t, txExists(t, txmp, "=0003=1"))
+	require.False(t, txmp.cache.HasKey(types.Tx("sender3=0002=9").Key()))
+	require.True(t, txmp.WasRecentlyRejected(types.Tx("sender3=0002=9").Key()))
+	require.Equal(t, 2, txmp.Size())
+}
+
+func TestTxMempool_Lanes(t *testing.T) {
+	// Transactions of priority below 10 are blobs, the others are transfers.
+	laneFn := func(res *abci.ResponseCheckTx) string {
+		if res.Priority < 10 {
+			return "blob"
+		}
+		return "transfer"
+	}
+	txmp := setup(t, 100, WithLanes(laneFn,
+		Lane{Name: "transfer"},
+		Lane{Name: "blob", MaxTxs: 2},
+	))
+	// Blobs beyond the quota of their lane only evict lower-priority blobs.
+	mustCheckTx(t, txmp, "blob1=0000=5")
+	mustCheckTx(t, txmp, "blob2=0001=6")
+	mustCheckTx(t, txmp, "blob3=0002=4")
+	require.False(t, txExists(t, txmp, "blob3=0002=4"))
+	mustCheckTx(t, txmp, "blob4=0003=7")
+	require.True(t, txExists(t, txmp, "blob4=0003=7"))
+	require.False(t, txExists(t, txmp, "blob1=0000=5"))
+	require.True(t, txmp.WasRecentlyEvicted(types.Tx("blob1=0000=5").Key()))
+
+	// Transfers aren't limited by the blob lane.
+	mustCheckTx(t, txmp, "transfer1=0004=10")
+	mustCheckTx(t, txmp, "transfer2=0005=20")
+	require.Equal(t, 4, txmp.Size())
+
+	// Transfers are reaped first, whatever their priority.
+	got := txmp.ReapMaxTxs(-1)
+	want := []string{"transfer2=0005=20", "transfer1=0004=10", "blob4=0003=7", "blob2=0001=6"}
+	require.Len(t, got, len(want))
+	for i, tx := range got {
+		require.Equal(t, want[i], string(tx.Tx))
+	}
+
+	// Removing transactions frees their lane.
+	blockTxs := types.CachedTxFromTxs(types.Txs{types.Tx("blob2=0001=6")})
+	require.NoError(t, txmp.Update(1, blockTxs, abciResponses(1, abci.CodeTypeOK), nil, nil))
+	mustCheckTx(t, txmp, "blob5=0006=1")
+	require.True(t, txExists(t, txmp, "blob5=0006=1"))
+}
+
+func TestTxMempool_LaneEvictionIsAtomic(t *testing.T) {
+	laneFn := func(res *abci.ResponseCheckTx) string {
+		if res.Priority < 10 {
+			return "blob"
+		}
+		return "transfer"
+	}
+	txmp := setup(t, 100, WithLanes(laneFn,
+		Lane{Name: "transfer"},
+		Lane{Name: "blob", MaxTxs: 1},
+	))
+	mustCheckTx(t, txmp, "blob1=0000=5")
+	mustCheckTx(t, txmp, "transfer1=0001=20")
+	mustCheckTx(t, txmp, "transfer2=0002=30")
+
+	// With the mempool over its size, a new blob can make room in its lane
+	// but not in the mempool, which only holds higher-priority transfers
+	// otherwise: it is rejected without evicting the blob of its lane.
+	txmp.config.Size = 2
+	mustCheckTx(t, txmp, "blob2=0003=6")
+	txmp.Lock()
+	_, ok := txmp.txByKey[types.Tx("blob2=0003=6").Key()]
+	require.False(t, ok)
+	_, ok = txmp.txByKey[types.Tx("blob1=0000=5").Key()]
+	require.True(t, ok)
+	txmp.Unlock()
+	require.Equal(t, 3, txmp.Size())
+	require.False(t, txmp.WasRecentlyEvicted(types.Tx("blob1=0000=5").Key()))
+}
+
+func TestLaneFromEvents(t *testing.T) {
+	res := &abci.ResponseCheckTx{Events: []abci.Event{
+		{Type: "message", Attributes: []abci.EventAttribute{{Key: LaneAttributeKey, Value: "other"}}},
+		{Type: LaneEventType, Attributes: []abci.EventAttribute{{Key: LaneAttributeKey, Value: "oracle"}}},
+	}}
+	require.Equal(t, "oracle", LaneFromEvents(res))
+	require.Equal(t, "", LaneFromEvents(&abci.ResponseCheckTx{}))
+}
+
+func TestTxMempool_DumpToFile(t *testing.T) {
+	txmp := setup(t, 100)
+	mustCheckTx(t, txmp, "sender1=0000=1")
+	mustCheckTx(t, txmp, "sender2=0001=5")
+	mustCheckTx(t, txmp, "=0002=3")
+
+	path := filepath.Join(t.TempDir(), "mempool.dump")
+	require.NoError(t, txmp.DumpToFile(path))
+
+	dumped, err := mempool.ReadDumpFile(path)
+	require.NoError(t, err)
+	require.Len(t, dumped, 3)
+
+	// The transactions are dumped in the order they would be reaped.
+	for i, spec := range []string{"sender2=0001=5", "=0002=3", "sender1=0000=1"} {
+		require.Equal(t, types.Tx(spec), dumped[i].Tx)
+	}
+	require.Equal(t, int64(5), dumped[0].Priority)
+	require.Equal(t, "sender2", dumped[0].Sender)
+	require.Empty(t, dumped[1].Sender)
+}
+
+func TestTxMempool_PendingTxs(t *testing.T) {
+	txmp := setup(t, 100)
+	txmp.config.TTLNumBlocks = 10
+	txmp.config.TTLDuration = time.Hour
+	mustCheckTx(t, txmp, "sender1=0000=1")
+	txmp.Lock()
+	txmp.height = 4
+	txmp.Unlock()
+	mustCheckTx(t, txmp, "sender2=0001=5")
+	txmp.Lock()
+	txmp.height = 6
+	txmp.Unlock()
+
+	txs := txmp.PendingTxs(-1)
+	require.Len(t, txs, 2)
+	// The transactions are listed in the order they would be reaped.
+	require.Equal(t, types.Tx("sender2=0001=5"), txs[0].Tx)
+	require.Equal(t, int64(5), txs[0].Priority)
+	require.Equal(t, int64(1), txs[0].GasWanted)
+	require.Equal(t, "sender2", txs[0].Sender)
+	require.Equal(t, int64(4), txs[0].Height)
+	require.Equal(t, int64(8), *txs[0].RemainingBlocks)
+	require.Equal(t, int64(4), *txs[1].RemainingBlocks)
+	require.True(t, *txs[0].RemainingTime > 0 && *txs[0].RemainingTime <= time.Hour)
+
+	require.Len(t, txmp.PendingTxs(1), 1)
+
+	// Without a TTL, no remaining TTL is reported.
+	txmp.config.TTLNumBlocks = 0
+	txmp.config.TTLDuration = 0
+	txs = txmp.PendingTxs(-1)
+	require.Nil(t, txs[0].RemainingBlocks)
+	require.Nil(t, txs[0].RemainingTime)
+}
+
+func TestTxMempool_WritePrometheus(t *testing.T) {
+	txmp := setup(t, 100)
+	specs := []string{"sender1=0000=5", "sender2=0001=20", "=0002=7"}
+	var sizeBytes int
+	for _, spec := range specs {
+		mustCheckTx(t, txmp, spec)
+		sizeBytes += len(spec)
+	}
+
+	var buf bytes.Buffer
+	txmp.WritePrometheus(&buf)
+	out := buf.String()
+	for _, line := range []string{
+		"# TYPE cometbft_mempool_size gauge\ncometbft_mempool_size 3\n",
+		fmt.Sprintf("# TYPE cometbft_mempool_size_bytes gauge\ncometbft_mempool_size_bytes %d\n", sizeBytes),
+		"# TYPE cometbft_mempool_highest_priority gauge\ncometbft_mempool_highest_priority 20\n",
+		"# TYPE cometbft_mempool_senders gauge\ncometbft_mempool_senders 2\n",
+	} {
+		require.Contains(t, out, line)
+	}
+}
+
 func TestTxMempool_ConcurrentTxs(t *testing.T) {
 	txmp := setup(t, 100)
 	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
@@ -680,6 +1218,138 @@ func TestTxMempool_ExpiredTxs_NumBlocks(t *testing.T) {
 	require.GreaterOrEqual(t, txmp.Size(), 45)
 }
 
+// labeledCounter records the values added to a counter by label value.
+type labeledCounter struct {
+	counts map[string]float64
+	label  string
+}
+
+func (c *labeledCounter) With(labelValues ...string) metrics.Counter {
+	return &labeledCounter{counts: c.counts, label: labelValues[len(labelValues)-1]}
+}
+
+func (c *labeledCounter) Add(delta float64) { c.counts[c.label] += delta }
+
+func TestTxMempool_ExpiredTxs_Mechanism(t *testing.T) {
+	txmp := setup(t, 500)
+	expired := &labeledCounter{counts: make(map[string]float64)}
+	txmp.metrics.ExpiredTxsByMechanism = expired
+	txmp.height = 100
+	txmp.config.TTLNumBlocks = 1
+
+	// Transactions not committed within TTLNumBlocks blocks expire by height.
+	checkTxs(t, txmp, 10, 0)
+	txmp.Lock()
+	require.NoError(t, txmp.Update(102, nil, nil, nil, nil))
+	txmp.Unlock()
+	require.Zero(t, txmp.Size())
+	require.Equal(t, map[string]float64{"height": 10}, expired.counts)
+
+	// Transactions not committed within TTLDuration expire by duration.
+	txmp.config.TTLNumBlocks = 0
+	txmp.config.TTLDuration = time.Millisecond
+	checkTxs(t, txmp, 5, 0)
+	time.Sleep(10 * time.Millisecond)
+	txmp.CheckToPurgeExpiredTxs()
+	require.Zero(t, txmp.Size())
+	require.Equal(t, map[string]float64{"height": 10, "duration": 5}, expired.counts)
+}
+
+func TestTxMempool_EvictionCallback(t *testing.T) {
+	evictions := make(chan types.EventDataTxEvicted, 10)
+	txmp := setup(t, 100, WithEvictionCallback(func(data types.EventDataTxEvicted) {
+		evictions <- data
+	}))
+	txmp.config.Size = 2
+	evicted := func(n int) []types.EventDataTxEvicted {
+		var all []types.EventDataTxEvicted
+		for i := 0; i < n; i++ {
+			select {
+			case data := <-evictions:
+				all = append(all, data)
+			case <-time.After(time.Second):
+				require.FailNow(t, "eviction not notified")
+			}
+		}
+		return all
+	}
+
+	// A transaction of the same sender replaces the existing one.
+	mustCheckTx(t, txmp, "alice=1=5")
+	mustCheckTx(t, txmp, "alice=2=9")
+	require.Equal(t, []types.EventDataTxEvicted{
+		{Tx: types.Tx("alice=1=5"), Reason: types.TxEvictedReplaced},
+	}, evicted(1))
+
+	// A full mempool evicts lower-priority transactions, or the incoming one.
+	mustCheckTx(t, txmp, "bob=1=3")
+	mustCheckTx(t, txmp, "cat=1=7")
+	mustCheckTx(t, txmp, "dan=1=1")
+	require.Equal(t, []types.EventDataTxEvicted{
+		{Tx: types.Tx("bob=1=3"), Reason: types.TxEvictedMempoolFull},
+		{Tx: types.Tx("dan=1=1"), Reason: types.TxEvictedMempoolFull},
+	}, evicted(2))
+
+	// Expired transactions are reported as such.
+	txmp.config.TTLNumBlocks = 1
+	txmp.Lock()
+	require.NoError(t, txmp.Update(txmp.height+2, nil, nil, nil, nil))
+	txmp.Unlock()
+	for _, data := range evicted(2) {
+		require.Equal(t, types.TxEvictedExpired, data.Reason)
+	}
+}
+
+func TestTxMempool_EvictionCallbackDoesNotHoldLock(t *testing.T) {
+	published := make(chan struct{})
+	txmp := setup(t, 100, WithEvictionCallback(func(types.EventDataTxEvicted) {
+		<-published
+	}))
+
+	// The mempool is usable while a subscriber is slow to take the evictions.
+	mustCheckTx(t, txmp, "alice=1=5")
+	mustCheckTx(t, txmp, "alice=2=9")
+	mustCheckTx(t, txmp, "bob=1=5")
+	require.Equal(t, 2, txmp.Size())
+	close(published)
+}
+
+// observedHistogram records the values observed by a histogram.
+type observedHistogram struct {
+	values []float64
+}
+
+func (h *observedHistogram) With(...string) metrics.Histogram { return h }
+
+func (h *observedHistogram) Observe(value float64) { h.values = append(h.values, value) }
+
+func TestTxMempool_Metrics(t *testing.T) {
+	txmp := setup(t, 100)
+	priorities := &observedHistogram{}
+	timeInMempool := &observedHistogram{}
+	evicted := &labeledCounter{counts: make(map[string]float64)}
+	txmp.metrics.TxPriority = priorities
+	txmp.metrics.TxTimeInMempoolSeconds = timeInMempool
+	txmp.metrics.EvictedTxsByReason = evicted
+
+	mustCheckTx(t, txmp, "alice=1=5")
+	mustCheckTx(t, txmp, "alice=2=9")
+	mustCheckTx(t, txmp, "bob=1=3")
+	require.Equal(t, []float64{5, 9, 3}, priorities.values)
+	require.Equal(t, map[string]float64{types.TxEvictedReplaced: 1}, evicted.counts)
+
+	// Only committed transactions that were in the mempool are timed.
+	txs := []*types.CachedTx{
+		types.Tx("alice=2=9").ToCachedTx(),
+		types.Tx("carol=1=1").ToCachedTx(),
+	}
+	txmp.Lock()
+	require.NoError(t, txmp.Update(1, txs, abciResponses(len(txs), abci.CodeTypeOK), nil, nil))
+	txmp.Unlock()
+	require.Len(t, timeInMempool.values, 1)
+	require.GreaterOrEqual(t, timeInMempool.values[0], 0.0)
+}
+
 func TestTxMempool_CheckTxPostCheckError(t *testing.T) {
 	cases := []struct {
 		name string
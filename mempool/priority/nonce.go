@@ -0,0 +1,67 @@
+package priority
+
+import (
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/cometbft/cometbft/types"
+)
+
+// NonceFunc returns the account of a transaction and its nonce, the sequence
+// number of the transaction among those of the account, from the transaction
+// or the response of the application to its CheckTx. It reports false for
+// transactions without a nonce.
+type NonceFunc func(tx types.Tx, res *abci.ResponseCheckTx) (account string, nonce uint64, ok bool)
+
+// NonceFromResponse returns the address and sequence of the CheckTx response
+// as the account and nonce of a transaction, if the address is set.
+func NonceFromResponse(_ types.Tx, res *abci.ResponseCheckTx) (string, uint64, bool) {
+	if len(res.Address) == 0 {
+		return "", 0, false
+	}
+	return string(res.Address), res.Sequence, true
+}
+
+// WithNonces sets a function returning the account and nonce of transactions.
+// An account may then have several transactions in the mempool, instead of
+// one per sender, as long as their nonces differ, and they are reaped in
+// nonce order. Transactions without a nonce are handled as usual.
+func WithNonces(nonceFn NonceFunc) TxMempoolOption {
+	return func(txmp *TxMempool) { txmp.nonceFn = nonceFn }
+}
+
+// accountNonce identifies a transaction by its account and nonce.
+type accountNonce struct {
+	account string
+	nonce   uint64
+}
+
+// setNonce sets the account and nonce of wtx, if it has one.
+func (txmp *TxMempool) setNonce(wtx *WrappedTx, res *abci.ResponseCheckTx) {
+	if txmp.nonceFn == nil {
+		return
+	}
+	if account, nonce, ok := txmp.nonceFn(wtx.tx.Tx, res); ok {
+		wtx.account, wtx.nonce, wtx.hasNonce = account, nonce, true
+	}
+}
+
+// isNewSender reports whether wtx, sent by sender, is the first transaction of
+// its account, if it has a nonce, or else of its sender in the mempool.
+// Transactions without a sender are never from a new sender. The caller must
+// hold txmp.mtx.
+func (txmp *TxMempool) isNewSender(wtx *WrappedTx, sender string) bool {
+	if wtx.hasNonce {
+		_, ok := txmp.accountTxs[wtx.account]
+		return !ok
+	}
+	if sender == "" {
+		return false
+	}
+	_, ok := txmp.txBySender[sender]
+	return !ok
+}
+
+// numSenders returns the number of distinct senders and accounts with
+// transactions in the mempool. The caller must hold txmp.mtx.
+func (txmp *TxMempool) numSenders() int {
+	return len(txmp.txBySender) + len(txmp.accountTxs)
+}
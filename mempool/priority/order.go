@@ -0,0 +1,87 @@
+package priority
+
+import (
+	"sort"
+)
+
+// reapsBefore reports whether a is reaped before b, ignoring nonces: by lane
+// in reaping order, then by nonincreasing priority with ties broken by
+// increasing order of arrival time.
+func (txmp *TxMempool) reapsBefore(a, b *WrappedTx) bool {
+	if ra, rb := txmp.laneRank(a.lane), txmp.laneRank(b.lane); ra != rb {
+		return ra < rb
+	}
+	if pa, pb := a.Priority(), b.Priority(); pa != pb {
+		return pa > pb // N.B. higher priorities first
+	}
+	return a.timestamp.Before(b.timestamp)
+}
+
+// addToOrder adds wtx to the ordered indexes of the mempool. The caller must
+// hold txmp.mtx exclusively, and must not change the priority of wtx until it
+// is removed from them with removeFromOrder.
+func (txmp *TxMempool) addToOrder(wtx *WrappedTx) {
+	i := sort.Search(len(txmp.ordered), func(i int) bool { return txmp.reapsBefore(wtx, txmp.ordered[i]) })
+	txmp.ordered = append(txmp.ordered, nil)
+	copy(txmp.ordered[i+1:], txmp.ordered[i:])
+	txmp.ordered[i] = wtx
+
+	if wtx.hasNonce {
+		txs := txmp.accountTxs[wtx.account]
+		j := sort.Search(len(txs), func(j int) bool { return txs[j].nonce > wtx.nonce })
+		txs = append(txs, nil)
+		copy(txs[j+1:], txs[j:])
+		txs[j] = wtx
+		txmp.accountTxs[wtx.account] = txs
+	}
+}
+
+// removeFromOrder removes wtx from the ordered indexes of the mempool. The
+// caller must hold txmp.mtx exclusively.
+func (txmp *TxMempool) removeFromOrder(wtx *WrappedTx) {
+	// The transactions reaped at the same position as wtx follow the first
+	// one not reaped before it.
+	i := sort.Search(len(txmp.ordered), func(i int) bool { return !txmp.reapsBefore(txmp.ordered[i], wtx) })
+	for ; i < len(txmp.ordered); i++ {
+		if txmp.ordered[i] == wtx {
+			txmp.ordered = append(txmp.ordered[:i], txmp.ordered[i+1:]...)
+			break
+		}
+	}
+
+	if wtx.hasNonce {
+		txs := txmp.accountTxs[wtx.account]
+		for j, tx := range txs {
+			if tx == wtx {
+				txs = append(txs[:j], txs[j+1:]...)
+				break
+			}
+		}
+		if len(txs) == 0 {
+			delete(txmp.accountTxs, wtx.account)
+		} else {
+			txmp.accountTxs[wtx.account] = txs
+		}
+	}
+}
+
+// walkOrder calls fn for each transaction in the mempool in reaping order,
+// until fn returns false. The transactions of an account are visited in nonce
+// order, at the positions they hold in the ordered index. The caller must hold
+// txmp.mtx.
+func (txmp *TxMempool) walkOrder(fn func(wtx *WrappedTx) bool) {
+	var visited map[string]int // number of transactions visited per account
+	for _, wtx := range txmp.ordered {
+		if wtx.hasNonce {
+			if visited == nil {
+				visited = make(map[string]int)
+			}
+			n := visited[wtx.account]
+			visited[wtx.account] = n + 1
+			wtx = txmp.accountTxs[wtx.account][n]
+		}
+		if !fn(wtx) {
+			return
+		}
+	}
+}
@@ -15,14 +15,23 @@ import (
 	"github.com/cometbft/cometbft/types"
 )
 
+// dumpInterval is the interval at which the mempool dump is written, if the
+// mempool configuration has a dump_path.
+const dumpInterval = time.Minute
+
 // Reactor handles mempool tx broadcasting amongst peers.
 // It maintains a map from peer ID to counter, to prevent gossiping txs to the
 // peers you received it from.
 type Reactor struct {
 	p2p.BaseReactor
-	config  *cfg.MempoolConfig
-	mempool *TxMempool
-	ids     *mempoolIDs
+	config   *cfg.MempoolConfig
+	mempool  *TxMempool
+	ids      *mempoolIDs
+	limiter  *mempool.PeerRateLimiter
+	requests *txRequests // transactions asked for to peers with WantTx
+
+	// waitSync defers the replay of the mempool WAL until SwitchToConsensus.
+	waitSync bool
 }
 
 type mempoolIDs struct {
@@ -89,12 +98,14 @@ func newMempoolIDs() *mempoolIDs {
 }
 
 // NewReactor returns a new Reactor with the given config and mempool.
-func NewReactor(config *cfg.MempoolConfig, mempool *TxMempool) *Reactor {
+func NewReactor(config *cfg.MempoolConfig, txmp *TxMempool) *Reactor {
 	memR := &Reactor{
 		config:  config,
-		mempool: mempool,
+		mempool: txmp,
 		ids:     newMempoolIDs(),
+		limiter: mempool.NewPeerRateLimiter(config),
 	}
+	memR.requests = newTxRequests(config.MaxGossipDelay, config.Size, memR.askForTx)
 	memR.BaseReactor = *p2p.NewBaseReactor("Mempool", memR)
 	return memR
 }
@@ -116,6 +127,34 @@ func (memR *Reactor) OnStart() error {
 		memR.Logger.Info("Tx broadcasting is disabled")
 	}
 
+	// Restore the transactions from before the node was restarted, once the
+	// node caught up if it is syncing.
+	if memR.config.PersistToDisk {
+		if err := memR.mempool.InitWAL(); err != nil {
+			return err
+		}
+		if !memR.waitSync {
+			memR.mempool.ReplayWAL()
+		}
+	}
+
+	// Keep a mempool dump up to date, so that the transactions pending when
+	// the node crashed can be analyzed with the inspect command.
+	if memR.config.DumpEnabled() {
+		go func() {
+			ticker := time.NewTicker(dumpInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					memR.dumpMempool()
+				case <-memR.Quit():
+					return
+				}
+			}
+		}()
+	}
+
 	// run a separate go routine to check for time based TTLs
 	if memR.mempool.config.TTLDuration > 0 {
 		go func() {
@@ -134,21 +173,47 @@ func (memR *Reactor) OnStart() error {
 	return nil
 }
 
+// SetWaitSync makes the reactor wait for the node to catch up through state or
+// block sync before replaying the mempool WAL: the transactions are replayed by
+// SwitchToConsensus rather than OnStart, as the application can only check them
+// once it has the latest state. It must be called before the reactor is started.
+func (memR *Reactor) SetWaitSync() {
+	memR.waitSync = true
+}
+
+// SwitchToConsensus is called when the node switches from block sync to
+// consensus, and replays the mempool WAL if it was deferred.
+func (memR *Reactor) SwitchToConsensus() {
+	if memR.config.PersistToDisk {
+		memR.mempool.ReplayWAL()
+	}
+}
+
+// OnStop implements p2p.BaseReactor.
+func (memR *Reactor) OnStop() {
+	memR.requests.Stop()
+	memR.mempool.CloseWAL()
+	if memR.config.DumpEnabled() {
+		memR.dumpMempool()
+	}
+}
+
+// dumpMempool writes the transactions of the mempool to the configured
+// mempool dump.
+func (memR *Reactor) dumpMempool() {
+	if err := memR.mempool.DumpToFile(memR.config.DumpFile()); err != nil {
+		memR.Logger.Error("Failed to write mempool dump", "err", err)
+	}
+}
+
 // GetChannels implements Reactor by returning the list of channels for this
 // reactor.
 func (memR *Reactor) GetChannels() []*p2p.ChannelDescriptor {
-	largestTx := make([]byte, memR.config.MaxTxBytes)
-	batchMsg := protomem.Message{
-		Sum: &protomem.Message_Txs{
-			Txs: &protomem.Txs{Txs: [][]byte{largestTx}},
-		},
-	}
-
 	return []*p2p.ChannelDescriptor{
 		{
 			ID:                  mempool.MempoolChannel,
 			Priority:            1,
-			RecvMessageCapacity: batchMsg.Size(),
+			RecvMessageCapacity: mempool.MaxTxsMessageSize(memR.config),
 			MessageType:         &protomem.Message{},
 		},
 	}
@@ -166,35 +231,30 @@ func (memR *Reactor) AddPeer(peer p2p.Peer) error {
 // RemovePeer implements Reactor.
 func (memR *Reactor) RemovePeer(peer p2p.Peer, reason interface{}) {
 	memR.ids.Reclaim(peer)
+	memR.limiter.RemovePeer(peer.ID())
 	// broadcast routine checks if peer is gone and returns
 }
 
 // Receive implements Reactor.
-// It adds any received transactions to the mempool.
+// It adds any received transactions to the mempool, and handles the SeenTx and
+// WantTx messages of the have/want protocol.
 func (memR *Reactor) Receive(e p2p.Envelope) {
 	memR.Logger.Debug("Receive", "src", e.Src, "chId", e.ChannelID, "msg", e.Message)
 	switch msg := e.Message.(type) {
 	case *protomem.Txs:
-		protoTxs := msg.GetTxs()
-		if len(protoTxs) == 0 {
-			memR.Logger.Error("received tmpty txs from peer", "src", e.Src)
+		memR.receiveTxs(e.Src, msg.GetTxs())
+	case *protomem.CompressedTxs:
+		txs, err := mempool.DecompressTxs(msg, mempool.MaxTxsMessageSize(memR.config))
+		if err != nil {
+			memR.Logger.Error("received invalid compressed txs from peer", "src", e.Src, "err", err)
+			memR.Switch.StopPeerForError(e.Src, err, memR.String())
 			return
 		}
-		txInfo := mempool.TxInfo{SenderID: memR.ids.GetForPeer(e.Src)}
-		if e.Src != nil {
-			txInfo.SenderP2PID = e.Src.ID()
-		}
-
-		var err error
-		for _, tx := range protoTxs {
-			ntx := types.Tx(tx)
-			err = memR.mempool.CheckTx(ntx, nil, txInfo)
-			if errors.Is(err, mempool.ErrTxInCache) {
-				memR.Logger.Debug("Tx already exists in cache", "tx", ntx.String())
-			} else if err != nil {
-				memR.Logger.Info("Could not check tx", "tx", ntx.String(), "err", err)
-			}
-		}
+		memR.receiveTxs(e.Src, txs)
+	case *protomem.SeenTx:
+		memR.receiveSeenTx(e.Src, msg)
+	case *protomem.WantTx:
+		memR.receiveWantTx(e.Src, msg)
 	default:
 		memR.Logger.Error("unknown message type", "src", e.Src, "chId", e.ChannelID, "msg", e.Message)
 		memR.Switch.StopPeerForError(e.Src, fmt.Errorf("mempool cannot handle message of type: %T", e.Message), memR.String())
@@ -204,15 +264,62 @@ func (memR *Reactor) Receive(e p2p.Envelope) {
 	// broadcasting happens from go routines per peer
 }
 
+// receiveTxs checks the transactions received from src, which is nil for
+// transactions not received from a peer.
+func (memR *Reactor) receiveTxs(src p2p.Peer, protoTxs [][]byte) {
+	if len(protoTxs) == 0 {
+		memR.Logger.Error("received tmpty txs from peer", "src", src)
+		return
+	}
+	txInfo := mempool.TxInfo{SenderID: memR.ids.GetForPeer(src)}
+	if src != nil {
+		txInfo.SenderP2PID = src.ID()
+	}
+
+	var err error
+	for _, tx := range protoTxs {
+		ntx := types.Tx(tx)
+		if memR.haveWant() {
+			memR.requests.Done(ntx.Key())
+		}
+		if src != nil {
+			ok, err := memR.limiter.Allow(src.ID(), len(tx))
+			if err != nil {
+				memR.Switch.StopPeerForError(src, err, memR.String())
+				return
+			}
+			if !ok {
+				memR.Logger.Debug("dropped tx exceeding the peer rate limits", "src", src, "tx", ntx.String())
+				continue
+			}
+		}
+		err = memR.mempool.CheckTx(ntx, nil, txInfo)
+		if errors.Is(err, mempool.ErrTxInCache) {
+			memR.Logger.Debug("Tx already exists in cache", "tx", ntx.String())
+		} else if err != nil {
+			memR.Logger.Info("Could not check tx", "tx", ntx.String(), "err", err)
+		}
+	}
+}
+
 // PeerState describes the state of a peer.
 type PeerState interface {
 	GetHeight() int64
 }
 
 // Send new mempool txs to peer.
+//
+// Transactions are not sent strictly in the order they entered the mempool.
+// Instead, they are queued in a txScheduler, which interleaves the
+// transactions received from different peers, so that a single high-volume
+// peer cannot dominate what we forward.
 func (memR *Reactor) broadcastTxRoutine(peer p2p.Peer) {
 	peerID := memR.ids.GetForPeer(peer)
-	var next *clist.CElement
+	sched := newTxScheduler()
+	var (
+		next  *clist.CElement // the last element queued in sched
+		memTx *WrappedTx      // the transaction being sent
+	)
 
 	for {
 		// In case of both next.NextWaitChan() and peer.Quit() are variable at the same time
@@ -220,22 +327,43 @@ func (memR *Reactor) broadcastTxRoutine(peer p2p.Peer) {
 			return
 		}
 
-		// This happens because the CElement we were looking at got garbage
-		// collected (removed). That is, .NextWait() returned nil. Go ahead and
-		// start from the beginning.
+		// Queue the transactions added to the mempool since we last looked. If
+		// the last queued element got garbage collected (removed), start over
+		// from the beginning. Transactions already sent to the peer are skipped.
+		if next != nil && next.Removed() {
+			next = nil
+		}
 		if next == nil {
-			select {
-			case <-memR.mempool.TxsWaitChan(): // Wait until a tx is available
-				if next = memR.mempool.TxsFront(); next == nil {
-					continue
-				}
+			if next = memR.mempool.TxsFront(); next != nil {
+				memR.schedule(sched, next, peerID)
+			}
+		}
+		for next != nil {
+			n := next.Next()
+			if n == nil {
+				break
+			}
+			memR.schedule(sched, n, peerID)
+			next = n
+		}
 
+		if memTx == nil {
+			memTx = sched.Pop()
+		}
+		if memTx == nil {
+			// Nothing to send, wait until a tx is available.
+			waitCh := memR.mempool.TxsWaitChan()
+			if next != nil {
+				waitCh = next.NextWaitChan()
+			}
+			select {
+			case <-waitCh:
 			case <-peer.Quit():
 				return
-
 			case <-memR.Quit():
 				return
 			}
+			continue
 		}
 
 		// Make sure the peer is up to date.
@@ -251,40 +379,97 @@ func (memR *Reactor) broadcastTxRoutine(peer p2p.Peer) {
 		}
 
 		// Allow for a lag of 1 block.
-		memTx := next.Value.(*WrappedTx)
 		if peerState.GetHeight() < memTx.height-1 {
 			time.Sleep(mempool.PeerCatchupSleepIntervalMS * time.Millisecond)
 			continue
 		}
 
-		// NOTE: Transaction batching was disabled due to
-		// https://github.com/tendermint/tendermint/issues/5796
-		if !memTx.HasPeer(peerID) {
-			success := peer.Send(p2p.Envelope{
+		// Do not gossip transactions that have already been committed.
+		if !memR.shouldSend(memTx, peerID) {
+			memTx = nil
+			continue
+		}
+
+		// With the have/want protocol, only announce the transaction: the
+		// peer asks for it if it doesn't have it.
+		if memR.haveWant() {
+			key := memTx.tx.Key()
+			if !peer.Send(p2p.Envelope{
 				ChannelID: mempool.MempoolChannel,
-				Message:   &protomem.Txs{Txs: [][]byte{memTx.tx.Tx}},
-			})
-			if !success {
+				Message:   &protomem.SeenTx{TxKey: key[:]},
+			}) {
 				time.Sleep(mempool.PeerCatchupSleepIntervalMS * time.Millisecond)
 				continue
-			} else {
-				// record that we have sent the peer the transaction
-				// to avoid doing it a second time
-				memTx.SetPeer(peerID)
 			}
+			memTx = nil
+			continue
 		}
 
-		select {
-		case <-next.NextWaitChan():
-			// see the start of the for loop for nil check
-			next = next.Next()
-
-		case <-peer.Quit():
-			return
+		// Coalesce the next scheduled transactions, up to MaxBatchBytes. The
+		// first one that doesn't fit is sent next.
+		batch := mempool.NewTxBatch(memR.config.MaxBatchBytes)
+		batch.Add(memTx.tx.Tx)
+		batched := []*WrappedTx{memTx}
+		var leftover *WrappedTx
+		for {
+			wtx := sched.Pop()
+			if wtx == nil {
+				break
+			}
+			if !memR.shouldSend(wtx, peerID) {
+				continue
+			}
+			if peerState.GetHeight() < wtx.height-1 || !batch.Add(wtx.tx.Tx) {
+				leftover = wtx
+				break
+			}
+			batched = append(batched, wtx)
+		}
 
-		case <-memR.Quit():
-			return
+		success := peer.Send(p2p.Envelope{
+			ChannelID: mempool.MempoolChannel,
+			Message:   batch.Message(mempool.PeerCompression(peer, memR.config.GossipCompression)),
+		})
+		if !success {
+			// Schedule the batch again, but for its first transaction, which
+			// is retried first.
+			for _, wtx := range batched[1:] {
+				sched.Push(wtx)
+			}
+			if leftover != nil {
+				sched.Push(leftover)
+			}
+			time.Sleep(mempool.PeerCatchupSleepIntervalMS * time.Millisecond)
+			continue
 		}
+		// record that we have sent the peer the transactions
+		// to avoid doing it a second time
+		for _, wtx := range batched {
+			wtx.SetPeer(peerID)
+		}
+		memTx = leftover
+	}
+}
+
+// shouldSend returns whether wtx should be gossiped to the peer with the
+// given ID: the peer doesn't have it yet, it wasn't committed and it is still
+// in the mempool.
+func (memR *Reactor) shouldSend(wtx *WrappedTx, peerID uint16) bool {
+	if wtx.HasPeer(peerID) || memR.mempool.WasRecentlyCommitted(wtx.tx.Key()) {
+		return false
+	}
+	// The transaction may have been removed, e.g. evicted or invalidated by a
+	// recheck, since it was scheduled.
+	cur, ok := memR.mempool.getWrappedTx(wtx.tx.Key())
+	return ok && cur == wtx
+}
+
+// schedule queues the transaction in elem to be sent to the peer with the
+// given ID, unless the peer already has it.
+func (memR *Reactor) schedule(sched *txScheduler, elem *clist.CElement, peerID uint16) {
+	memTx := elem.Value.(*WrappedTx)
+	if !memTx.HasPeer(peerID) {
+		sched.Push(memTx)
 	}
 }
 
@@ -3,6 +3,7 @@ package priority
 import (
 	"encoding/hex"
 	"os"
+	"path/filepath"
 	"sync"
 	"testing"
 	"time"
@@ -70,6 +71,190 @@ func TestReactorBroadcastTxsMessage(t *testing.T) {
 	waitForTxsOnReactors(t, transactions, reactors)
 }
 
+// Same as TestReactorBroadcastTxsMessage, with txs gossiped in compressed
+// batches.
+func TestReactorBroadcastTxsMessageBatched(t *testing.T) {
+	config := cfg.TestConfig()
+	config.Mempool.MaxBatchBytes = 10 * 1024
+	config.Mempool.GossipCompression = cfg.MempoolCompressionZstd
+	const N = 2
+	reactors := makeAndConnectReactors(config, N)
+	defer func() {
+		for _, r := range reactors {
+			if err := r.Stop(); err != nil {
+				assert.NoError(t, err)
+			}
+		}
+	}()
+	for _, r := range reactors {
+		for _, peer := range r.Switch.Peers().List() {
+			peer.Set(types.PeerStateKey, peerState{1})
+		}
+	}
+
+	txs := checkTxs(t, reactors[0].mempool, numTxs, mempool.UnknownPeerID)
+	transactions := make(types.Txs, len(txs))
+	for idx, tx := range txs {
+		transactions[idx] = tx.tx
+	}
+
+	waitForTxsOnReactors(t, transactions, reactors)
+}
+
+// Same as TestReactorBroadcastTxsMessage, with txs announced by hash and only
+// sent to the peers asking for them.
+func TestReactorBroadcastTxsMessageHaveWant(t *testing.T) {
+	config := cfg.TestConfig()
+	config.Mempool.GossipProtocol = cfg.MempoolGossipHaveWant
+	const N = 2
+	reactors := makeAndConnectReactors(config, N)
+	defer func() {
+		for _, r := range reactors {
+			if err := r.Stop(); err != nil {
+				assert.NoError(t, err)
+			}
+		}
+	}()
+	for _, r := range reactors {
+		for _, peer := range r.Switch.Peers().List() {
+			peer.Set(types.PeerStateKey, peerState{1})
+		}
+	}
+
+	txs := checkTxs(t, reactors[0].mempool, numTxs, mempool.UnknownPeerID)
+	transactions := make(types.Txs, len(txs))
+	for idx, tx := range txs {
+		transactions[idx] = tx.tx
+	}
+
+	waitForTxsOnReactors(t, transactions, reactors)
+}
+
+func TestTxRequests(t *testing.T) {
+	asked := make(chan p2p.ID, 10)
+	r := newTxRequests(time.Hour, 10, func(_ types.TxKey, peer p2p.ID) bool {
+		asked <- peer
+		return peer != "gone"
+	})
+	defer r.Stop()
+	key := types.Tx("tx").Key()
+
+	// A transaction is only asked for once at a time.
+	require.True(t, r.Request(key, "a"))
+	require.False(t, r.Request(key, "b"))
+	require.False(t, r.Request(key, "gone"))
+	require.False(t, r.Request(key, "c"))
+	r.Done(key)
+	require.True(t, r.Request(key, "a"))
+	r.Done(key)
+
+	// Once a request times out, the transaction is asked to the next peer
+	// which announced it, skipping the peers it can't be asked to.
+	r.timeout = 10 * time.Millisecond
+	require.True(t, r.Request(key, "a"))
+	require.False(t, r.Request(key, "gone"))
+	require.False(t, r.Request(key, "b"))
+	require.False(t, r.Request(key, "b"))
+	require.Equal(t, p2p.ID("gone"), <-asked)
+	require.Equal(t, p2p.ID("b"), <-asked)
+
+	// The request is forgotten once no peer is left to ask.
+	require.Eventually(t, func() bool {
+		r.mtx.Lock()
+		defer r.mtx.Unlock()
+		return len(r.pending) == 0
+	}, time.Second, 5*time.Millisecond)
+	require.Empty(t, asked)
+	require.True(t, r.Request(key, "c"))
+}
+
+// Mark some txs as committed on the first reactor and make sure they are not
+// gossiped to the second one.
+func TestReactorDoesNotBroadcastCommittedTxs(t *testing.T) {
+	config := cfg.TestConfig()
+	const N = 2
+	reactors := makeAndConnectReactors(config, N)
+	defer func() {
+		for _, r := range reactors {
+			if err := r.Stop(); err != nil {
+				assert.NoError(t, err)
+			}
+		}
+	}()
+
+	// The peers have no state yet, so nothing is gossiped until it is set below.
+	txs := checkTxs(t, reactors[0].mempool, numTxs, mempool.UnknownPeerID)
+	committed := make(types.Txs, 0, numTxs/2)
+	pending := make(types.Txs, 0, numTxs/2)
+	for idx, tx := range txs {
+		if idx%2 == 0 {
+			committed = append(committed, tx.tx)
+		} else {
+			pending = append(pending, tx.tx)
+		}
+	}
+	reactors[0].mempool.MarkCommitted(committed)
+
+	for _, r := range reactors {
+		for _, peer := range r.Switch.Peers().List() {
+			peer.Set(types.PeerStateKey, peerState{1})
+		}
+	}
+
+	waitForTxsOnReactor(t, pending, reactors[1], 1)
+
+	// Give the broadcast routine time to (wrongly) send the committed txs.
+	time.Sleep(500 * time.Millisecond)
+	require.Equal(t, len(pending), reactors[1].mempool.Size())
+	for _, tx := range committed {
+		_, ok := reactors[1].mempool.GetTxByKey(tx.Key())
+		assert.False(t, ok, "committed tx was gossiped")
+	}
+}
+
+func TestReactorDoesNotBroadcastRemovedTxs(t *testing.T) {
+	config := cfg.TestConfig()
+	const N = 2
+	reactors := makeAndConnectReactors(config, N)
+	defer func() {
+		for _, r := range reactors {
+			if err := r.Stop(); err != nil {
+				assert.NoError(t, err)
+			}
+		}
+	}()
+
+	// The peers have no state yet, so the txs are scheduled but not sent.
+	txs := checkTxs(t, reactors[0].mempool, numTxs, mempool.UnknownPeerID)
+	time.Sleep(100 * time.Millisecond)
+	removed := make(types.Txs, 0, numTxs/2)
+	pending := make(types.Txs, 0, numTxs/2)
+	for idx, tx := range txs {
+		if idx%2 == 0 {
+			removed = append(removed, tx.tx)
+			require.NoError(t, reactors[0].mempool.RemoveTxByKey(tx.tx.Key()))
+		} else {
+			pending = append(pending, tx.tx)
+		}
+	}
+
+	for _, r := range reactors {
+		for _, peer := range r.Switch.Peers().List() {
+			peer.Set(types.PeerStateKey, peerState{1})
+		}
+	}
+
+	waitForTxsOnReactor(t, pending, reactors[1], 1)
+
+	// Give the broadcast routine time to (wrongly) send the removed txs.
+	time.Sleep(500 * time.Millisecond)
+	require.Equal(t, len(pending), reactors[1].mempool.Size())
+	for _, tx := range removed {
+		_, ok := reactors[1].mempool.GetTxByKey(tx.Key())
+		assert.False(t, ok, "removed tx was gossiped")
+	}
+}
+
 func TestMempoolVectors(t *testing.T) {
 	testCases := []struct {
 		testName string
@@ -130,6 +315,22 @@ func TestReactorEventuallyRemovesExpiredTransaction(t *testing.T) {
 		"transaction was not removed after TTL expired")
 }
 
+func TestReactorDumpsMempoolOnStop(t *testing.T) {
+	txmp := setup(t, 100)
+	txmp.config.DumpPath = filepath.Join(t.TempDir(), "mempool.json")
+	memR := NewReactor(txmp.config, txmp)
+	memR.SetLogger(log.TestingLogger())
+	require.NoError(t, memR.Start())
+
+	mustCheckTx(t, txmp, "sender1=0000=1")
+	require.NoError(t, memR.Stop())
+
+	txs, err := mempool.ReadDumpFile(txmp.config.DumpFile())
+	require.NoError(t, err)
+	require.Len(t, txs, 1)
+	require.Equal(t, types.Tx("sender1=0000=1"), txs[0].Tx)
+}
+
 func TestLegacyReactorReceiveBasic(t *testing.T) {
 	config := cfg.TestConfig()
 	// if there were more than two reactors, the order of transactions could not be
@@ -0,0 +1,73 @@
+package priority
+
+import (
+	"context"
+
+	"github.com/cometbft/cometbft/config"
+)
+
+// shouldRecheck reports whether the recheck policy requires rechecking the
+// transactions of the mempool after the block at height.
+func (txmp *TxMempool) shouldRecheck(height int64) bool {
+	switch txmp.config.GetRecheckPolicy() {
+	case config.RecheckPolicyAlways:
+		return true
+	case config.RecheckPolicyEveryNBlocks:
+		return height-txmp.lastRecheckHeight >= txmp.config.RecheckInterval
+	default:
+		return false
+	}
+}
+
+// Recheck rechecks all the transactions in the mempool with the application,
+// as after a block, whatever the recheck policy, and returns their number.
+// It is meant for the "on_demand" recheck policy. With asynchronous rechecks,
+// Recheck returns once the recheck is started.
+func (txmp *TxMempool) Recheck() int {
+	txmp.mtx.Lock()
+	defer txmp.mtx.Unlock()
+
+	n := txmp.Size()
+	if n > 0 {
+		txmp.recheckTransactions()
+	}
+	return n
+}
+
+// startAsyncRecheck abandons the asynchronous recheck in progress, if any,
+// and rechecks wtxs in the background. The mempool is locked for the recheck
+// of each transaction rather than for the whole recheck, so that it can be
+// used in the meantime; transactions removed in the meantime are skipped.
+//
+// The caller must hold txmp.mtx exclusively.
+func (txmp *TxMempool) startAsyncRecheck(wtxs []*WrappedTx) {
+	if txmp.cancelRecheck != nil {
+		txmp.cancelRecheck()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	txmp.cancelRecheck = cancel
+
+	go func() {
+		for _, wtx := range wtxs {
+			txmp.mtx.Lock()
+			if ctx.Err() != nil {
+				txmp.mtx.Unlock()
+				return
+			}
+			if _, ok := txmp.txByKey[wtx.tx.Key()]; ok {
+				txmp.recheckTx(ctx, wtx)
+			}
+			txmp.mtx.Unlock()
+		}
+		_ = txmp.proxyAppConn.Flush(ctx)
+
+		txmp.mtx.Lock()
+		defer txmp.mtx.Unlock()
+		if ctx.Err() != nil {
+			return
+		}
+		cancel()
+		txmp.cancelRecheck = nil
+		txmp.notifyTxsAvailable()
+	}()
+}
@@ -0,0 +1,72 @@
+package priority
+
+import (
+	"github.com/cometbft/cometbft/types"
+)
+
+// txScheduler orders the transactions to be sent to a peer so that the
+// transactions received from different peers are interleaved in a round-robin
+// fashion. This prevents a single high-volume peer from dominating the send
+// queues. Transactions from the same peer are sent in the order they were
+// pushed.
+//
+// A txScheduler is not safe for concurrent use.
+type txScheduler struct {
+	queues map[uint16][]*WrappedTx // pending transactions by source peer ID
+	order  []uint16                // source peer IDs with pending transactions
+	next   int                     // index in order of the next source to pop from
+	queued map[types.TxKey]struct{}
+}
+
+func newTxScheduler() *txScheduler {
+	return &txScheduler{
+		queues: make(map[uint16][]*WrappedTx),
+		queued: make(map[types.TxKey]struct{}),
+	}
+}
+
+// Len returns the number of pending transactions.
+func (s *txScheduler) Len() int { return len(s.queued) }
+
+// Push adds wtx to the queue of the peer it was received from. Transactions
+// that are already pending are ignored.
+func (s *txScheduler) Push(wtx *WrappedTx) {
+	key := wtx.tx.Key()
+	if _, ok := s.queued[key]; ok {
+		return
+	}
+	s.queued[key] = struct{}{}
+
+	if _, ok := s.queues[wtx.source]; !ok {
+		s.order = append(s.order, wtx.source)
+	}
+	s.queues[wtx.source] = append(s.queues[wtx.source], wtx)
+}
+
+// Pop removes and returns the next transaction to send, taking turns between
+// the source peers. It returns nil if there are no pending transactions.
+func (s *txScheduler) Pop() *WrappedTx {
+	if len(s.order) == 0 {
+		return nil
+	}
+	if s.next >= len(s.order) {
+		s.next = 0
+	}
+
+	source := s.order[s.next]
+	queue := s.queues[source]
+	wtx := queue[0]
+	queue[0] = nil
+	if len(queue) == 1 {
+		// The source has no more pending transactions, so the next source
+		// moves into its position in order.
+		delete(s.queues, source)
+		s.order = append(s.order[:s.next], s.order[s.next+1:]...)
+	} else {
+		s.queues[source] = queue[1:]
+		s.next++
+	}
+
+	delete(s.queued, wtx.tx.Key())
+	return wtx
+}
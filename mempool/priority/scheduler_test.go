@@ -0,0 +1,69 @@
+package priority
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cometbft/cometbft/types"
+)
+
+func TestTxScheduler_RoundRobin(t *testing.T) {
+	sched := newTxScheduler()
+	require.Nil(t, sched.Pop())
+
+	// Peer 1 sends many more transactions than peers 2 and 3, and all of them
+	// arrive first.
+	volumes := []struct {
+		source uint16
+		count  int
+	}{
+		{1, 6},
+		{2, 2},
+		{3, 1},
+	}
+	for _, v := range volumes {
+		for i := 0; i < v.count; i++ {
+			sched.Push(&WrappedTx{
+				tx:     types.Tx(fmt.Sprintf("tx-%d-%d", v.source, i)).ToCachedTx(),
+				source: v.source,
+			})
+		}
+	}
+	require.Equal(t, 9, sched.Len())
+
+	// Pushing a pending transaction again is a no-op.
+	sched.Push(&WrappedTx{tx: types.Tx("tx-1-0").ToCachedTx(), source: 1})
+	require.Equal(t, 9, sched.Len())
+
+	var got []string
+	for wtx := sched.Pop(); wtx != nil; wtx = sched.Pop() {
+		got = append(got, string(wtx.tx.Tx))
+	}
+	require.Equal(t, []string{
+		"tx-1-0", "tx-2-0", "tx-3-0",
+		"tx-1-1", "tx-2-1",
+		"tx-1-2",
+		"tx-1-3",
+		"tx-1-4",
+		"tx-1-5",
+	}, got)
+	require.Zero(t, sched.Len())
+}
+
+func TestTxScheduler_NewSourceJoinsRotation(t *testing.T) {
+	sched := newTxScheduler()
+	for i := 0; i < 3; i++ {
+		sched.Push(&WrappedTx{tx: types.Tx(fmt.Sprintf("tx-1-%d", i)).ToCachedTx(), source: 1})
+	}
+	require.Equal(t, "tx-1-0", string(sched.Pop().tx.Tx))
+
+	// A peer whose transactions arrive later does not wait for the backlog of
+	// the first one to be drained.
+	sched.Push(&WrappedTx{tx: types.Tx("tx-2-0").ToCachedTx(), source: 2})
+	require.Equal(t, "tx-2-0", string(sched.Pop().tx.Tx))
+	require.Equal(t, "tx-1-1", string(sched.Pop().tx.Tx))
+	require.Equal(t, "tx-1-2", string(sched.Pop().tx.Tx))
+	require.Nil(t, sched.Pop())
+}
@@ -13,6 +13,11 @@ type WrappedTx struct {
 	tx        *types.CachedTx // the original transaction data along with a cached hash
 	height    int64           // height when this transaction was initially checked (for expiry)
 	timestamp time.Time       // time when transaction was entered (for TTL)
+	source    uint16          // ID of the peer that first sent us this transaction
+	lane      string          // lane of this transaction, set before it is inserted
+	account   string          // account of this transaction, if hasNonce
+	nonce     uint64          // nonce of this transaction in its account, if hasNonce
+	hasNonce  bool            // whether the transaction has an account and nonce
 
 	mtx       sync.Mutex
 	gasWanted int64           // app: gas required to execute this transaction
@@ -21,6 +26,9 @@ type WrappedTx struct {
 	peers     map[uint16]bool // peer IDs who have sent us this transaction
 }
 
+// Tx returns the transaction wrapped by w.
+func (w *WrappedTx) Tx() *types.CachedTx { return w.tx }
+
 // Size reports the size of the raw transaction in bytes.
 func (w *WrappedTx) Size() int64 { return int64(len(w.tx.Tx)) }
 
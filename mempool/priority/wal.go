@@ -0,0 +1,346 @@
+package priority
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/cometbft/cometbft/mempool"
+	"github.com/cometbft/cometbft/types"
+)
+
+// walFile is the name of the journal within the WAL directory.
+const walFile = "txs.wal"
+
+// Kinds of journal records.
+const (
+	walRecordAdd    byte = 1 // the payload is a transaction added to the mempool
+	walRecordRemove byte = 2 // the payload is the key of a removed transaction
+)
+
+// txWAL is a journal of the transactions added to and removed from the
+// mempool, so that the transactions in the mempool can be restored after a
+// restart. Each record is a kind byte followed by the uvarint length of its
+// payload and the payload.
+//
+// Records are not synced to disk as they are written, so that the journal
+// survives restarts of the node but not necessarily crashes of its host.
+type txWAL struct {
+	path    string
+	file    *os.File
+	records int // number of records in the file
+}
+
+// openTxWAL opens the journal in dir, creating dir if needed, and returns it
+// along with the transactions it holds, in the order they were added, with
+// records larger than maxTxBytes treated as truncated (see readTxWAL). The
+// journal is kept as is, with new records appended to it, until it is
+// compacted, so that its transactions aren't lost if the node stops before
+// they are added to the mempool again.
+func openTxWAL(dir string, maxTxBytes int) (*txWAL, []types.Tx, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, nil, err
+	}
+	path := filepath.Join(dir, walFile)
+	txs, size, records, err := readTxWAL(path, maxTxBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Drop a truncated last record, if any, before appending to the journal.
+	if err := file.Truncate(size); err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+	if _, err := file.Seek(size, io.SeekStart); err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+	return &txWAL{path: path, file: file, records: records}, txs, nil
+}
+
+// readTxWAL returns the transactions added and not removed in the journal at
+// path, in the order they were added, along with the size and the number of
+// the complete records of the journal. A truncated last record, as left by a
+// crash while writing it, is ignored, as is everything from a record with a
+// payload larger than maxTxBytes, which only a corrupt journal holds.
+func readTxWAL(path string, maxTxBytes int) ([]types.Tx, int64, int, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, 0, 0, nil
+	}
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	defer f.Close()
+
+	var (
+		r       = bufio.NewReader(f)
+		size    int64
+		records int
+		added   []types.Tx
+		live    = make(map[types.TxKey]int) // index in added of the last add
+	)
+	for {
+		kind, payload, n, err := readWALRecord(r, maxTxBytes)
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, errWALRecordTooLarge) {
+			break
+		}
+		if err != nil {
+			return nil, 0, 0, fmt.Errorf("failed to read mempool WAL %v: %w", path, err)
+		}
+		size += int64(n)
+		records++
+		switch kind {
+		case walRecordAdd:
+			tx := types.Tx(payload)
+			if _, ok := live[tx.Key()]; !ok {
+				live[tx.Key()] = len(added)
+				added = append(added, tx)
+			}
+		case walRecordRemove:
+			var key types.TxKey
+			if len(payload) != len(key) {
+				return nil, 0, 0, fmt.Errorf("invalid key of %d bytes in mempool WAL %v", len(payload), path)
+			}
+			copy(key[:], payload)
+			delete(live, key)
+		default:
+			return nil, 0, 0, fmt.Errorf("unknown record kind %d in mempool WAL %v", kind, path)
+		}
+	}
+
+	txs := make([]types.Tx, 0, len(live))
+	for i, tx := range added {
+		if idx, ok := live[tx.Key()]; ok && idx == i {
+			txs = append(txs, tx)
+		}
+	}
+	return txs, size, records, nil
+}
+
+// errWALRecordTooLarge is returned by readWALRecord for a record whose payload
+// is larger than allowed.
+var errWALRecordTooLarge = errors.New("mempool WAL record too large")
+
+// readWALRecord reads a record, returning its kind, its payload and its size.
+// Payloads are at most maxTxBytes long, or the size of a transaction key.
+func readWALRecord(r *bufio.Reader, maxTxBytes int) (byte, []byte, int, error) {
+	kind, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, 0, err
+	}
+	size, err := binary.ReadUvarint(r)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return 0, nil, 0, err
+	}
+	if size > uint64(max(maxTxBytes, len(types.TxKey{}))) {
+		return 0, nil, 0, errWALRecordTooLarge
+	}
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return 0, nil, 0, err
+	}
+	var buf [binary.MaxVarintLen64]byte
+	return kind, payload, 1 + binary.PutUvarint(buf[:], size) + len(payload), nil
+}
+
+func appendWALRecord(w io.Writer, kind byte, payload []byte) error {
+	buf := make([]byte, 1+binary.MaxVarintLen64, 1+binary.MaxVarintLen64+len(payload))
+	buf[0] = kind
+	n := binary.PutUvarint(buf[1:], uint64(len(payload)))
+	buf = append(buf[:1+n], payload...)
+	_, err := w.Write(buf)
+	return err
+}
+
+// Add records that tx was added to the mempool.
+func (w *txWAL) Add(tx types.Tx) error {
+	w.records++
+	return appendWALRecord(w.file, walRecordAdd, tx)
+}
+
+// Remove records that the transaction with the given key was removed from the
+// mempool.
+func (w *txWAL) Remove(key types.TxKey) error {
+	w.records++
+	return appendWALRecord(w.file, walRecordRemove, key[:])
+}
+
+// Compact replaces the journal with one only adding txs.
+func (w *txWAL) Compact(txs []types.Tx) error {
+	tmp := w.path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(f)
+	for _, tx := range txs {
+		if err := appendWALRecord(bw, walRecordAdd, tx); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, w.path); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(w.path, os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	w.file.Close()
+	w.file = file
+	w.records = len(txs)
+	return nil
+}
+
+// Close closes the journal.
+func (w *txWAL) Close() error {
+	return w.file.Close()
+}
+
+// InitWAL opens the journal of the mempool in the configured WAL directory.
+// From then on, the transactions added to and removed from the mempool are
+// journaled, until CloseWAL. The transactions the journal holds, from before
+// the node was restarted, are kept in it until ReplayWAL adds them to the
+// mempool again.
+func (txmp *TxMempool) InitWAL() error {
+	wal, txs, err := openTxWAL(txmp.config.PersistDir(), txmp.config.MaxTxBytes)
+	if err != nil {
+		return fmt.Errorf("failed to open mempool WAL: %w", err)
+	}
+	txmp.mtx.Lock()
+	txmp.wal = wal
+	txmp.walPending = txs
+	txmp.mtx.Unlock()
+	return nil
+}
+
+// ReplayWAL replays the transactions held by the journal when InitWAL opened
+// it, by checking them again with the application, and then compacts the
+// journal. It does nothing if they were already replayed, or are being
+// replayed.
+//
+// ReplayWAL must be called once the application has caught up with the state
+// of the network, as the transactions are checked against its state.
+func (txmp *TxMempool) ReplayWAL() {
+	txmp.mtx.Lock()
+	txs := txmp.walPending
+	if txmp.walReplaying {
+		txs = nil
+	}
+	txmp.walReplaying = txs != nil
+	txmp.mtx.Unlock()
+	if txs == nil {
+		return
+	}
+
+	var restored int
+	for _, tx := range txs {
+		// Transactions that are no longer valid are rejected as usual.
+		if err := txmp.CheckTx(tx, nil, mempool.TxInfo{}); err != nil {
+			txmp.logger.Debug("failed to replay transaction from mempool WAL",
+				"tx", fmt.Sprintf("%X", tx.Hash()), "err", err)
+			continue
+		}
+		if _, ok := txmp.GetTxByKey(tx.Key()); ok {
+			restored++
+		}
+	}
+	txmp.logger.Info("replayed mempool WAL", "txs", len(txs), "restored", restored)
+
+	// The journal no longer needs the records from before the restart. They
+	// are kept until now, walPending preventing compactions, so that they
+	// survive a crash during the replay.
+	txmp.mtx.Lock()
+	defer txmp.mtx.Unlock()
+	txmp.walPending = nil
+	txmp.walReplaying = false
+	txmp.rewriteWAL()
+}
+
+// CloseWAL closes the journal of the mempool, if InitWAL opened it.
+func (txmp *TxMempool) CloseWAL() {
+	txmp.mtx.Lock()
+	defer txmp.mtx.Unlock()
+	if txmp.wal == nil {
+		return
+	}
+	if err := txmp.wal.Close(); err != nil {
+		txmp.logger.Error("failed to close mempool WAL", "err", err)
+	}
+	txmp.wal = nil
+}
+
+// journalAdd records in the journal, if any, that w was added to the mempool.
+// The caller must hold txmp.mtx exclusively.
+func (txmp *TxMempool) journalAdd(w *WrappedTx) {
+	if txmp.wal == nil {
+		return
+	}
+	if err := txmp.wal.Add(w.tx.Tx); err != nil {
+		txmp.logger.Error("failed to journal transaction to mempool WAL", "err", err)
+	}
+}
+
+// journalRemove records in the journal, if any, that w was removed from the
+// mempool. The caller must hold txmp.mtx exclusively.
+func (txmp *TxMempool) journalRemove(w *WrappedTx) {
+	if txmp.wal == nil {
+		return
+	}
+	if err := txmp.wal.Remove(w.tx.Key()); err != nil {
+		txmp.logger.Error("failed to journal transaction removal to mempool WAL", "err", err)
+	}
+}
+
+// compactWAL rewrites the journal, if any, with only the transactions in the
+// mempool once it holds more than twice as many records as the mempool can
+// hold transactions. The journal isn't compacted until its transactions from
+// before the restart are replayed, so as not to lose them.
+//
+// The caller must hold txmp.mtx exclusively.
+func (txmp *TxMempool) compactWAL() {
+	if txmp.wal == nil || txmp.walPending != nil || txmp.wal.records <= 2*txmp.config.Size {
+		return
+	}
+	txmp.rewriteWAL()
+}
+
+// rewriteWAL rewrites the journal, if any, with only the transactions in the
+// mempool.
+//
+// The caller must hold txmp.mtx exclusively.
+func (txmp *TxMempool) rewriteWAL() {
+	if txmp.wal == nil {
+		return
+	}
+	txs := make([]types.Tx, 0, txmp.txs.Len())
+	for e := txmp.txs.Front(); e != nil; e = e.Next() {
+		txs = append(txs, e.Value.(*WrappedTx).tx.Tx)
+	}
+	if err := txmp.wal.Compact(txs); err != nil {
+		txmp.logger.Error("failed to compact mempool WAL", "err", err)
+	}
+}
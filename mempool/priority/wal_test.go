@@ -0,0 +1,171 @@
+package priority
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/cometbft/cometbft/libs/log"
+	"github.com/cometbft/cometbft/types"
+)
+
+func TestTxMempool_WAL(t *testing.T) {
+	dir := t.TempDir()
+	txmp := setup(t, 100)
+	txmp.config.WalPath = dir
+	require.NoError(t, txmp.InitWAL())
+
+	mustCheckTx(t, txmp, "sender1=0000=1")
+	mustCheckTx(t, txmp, "sender2=0001=5")
+	mustCheckTx(t, txmp, "sender3=0002=3")
+	blockTxs := types.CachedTxFromTxs(types.Txs{types.Tx("sender2=0001=5")})
+	require.NoError(t, txmp.Update(1, blockTxs, abciResponses(1, abci.CodeTypeOK), nil, nil))
+	txmp.CloseWAL()
+
+	// The journal keeps its transactions until they are replayed, even if the
+	// node stops again before that.
+	stopped := setup(t, 100)
+	stopped.config.WalPath = dir
+	stopped.config.Size = 1
+	require.NoError(t, stopped.InitWAL())
+	require.Zero(t, stopped.Size())
+	require.NoError(t, stopped.Update(1, nil, nil, nil, nil))
+	stopped.CloseWAL()
+
+	// The transactions left in the mempool are restored after a restart.
+	restarted := setup(t, 100)
+	restarted.config.WalPath = dir
+	require.NoError(t, restarted.InitWAL())
+	defer restarted.CloseWAL()
+	restarted.ReplayWAL()
+	require.Equal(t, 2, restarted.Size())
+	for _, spec := range []string{"sender1=0000=1", "sender3=0002=3"} {
+		_, ok := restarted.GetTxByKey(types.Tx(spec).Key())
+		require.True(t, ok, spec)
+	}
+
+	// The journal is compacted once it grows past twice the mempool size.
+	restarted.config.Size = 1
+	restarted.Lock()
+	require.NoError(t, restarted.Update(2, nil, nil, nil, nil))
+	records := restarted.wal.records
+	restarted.Unlock()
+	require.Equal(t, 2, records)
+}
+
+func TestTxMempool_WALKeptDuringReplay(t *testing.T) {
+	dir := t.TempDir()
+	txmp := setup(t, 100)
+	txmp.config.WalPath = dir
+	require.NoError(t, txmp.InitWAL())
+	specs := []string{"sender1=0000=1", "sender2=0001=5", "sender3=0002=3"}
+	for _, spec := range specs {
+		mustCheckTx(t, txmp, spec)
+	}
+	txmp.CloseWAL()
+
+	// Compactions triggered by blocks committed during the replay must not
+	// drop the transactions not replayed yet.
+	var restarted *TxMempool
+	var journaled [][]types.Tx
+	restarted = setup(t, 100, WithPreCheck(func(*types.CachedTx) error {
+		restarted.wal.records = 2*restarted.config.Size + 1
+		restarted.compactWAL()
+		txs, _, _, err := readTxWAL(filepath.Join(dir, walFile), restarted.config.MaxTxBytes)
+		require.NoError(t, err)
+		journaled = append(journaled, txs)
+		return nil
+	}))
+	restarted.config.WalPath = dir
+	require.NoError(t, restarted.InitWAL())
+	defer restarted.CloseWAL()
+	restarted.ReplayWAL()
+	require.Len(t, journaled, len(specs))
+	for _, txs := range journaled {
+		require.Len(t, txs, len(specs))
+	}
+}
+
+func TestReadTxWAL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), walFile)
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	a, b := types.Tx("a"), types.Tx("b")
+	require.NoError(t, appendWALRecord(f, walRecordAdd, a))
+	require.NoError(t, appendWALRecord(f, walRecordAdd, b))
+	key := a.Key()
+	require.NoError(t, appendWALRecord(f, walRecordRemove, key[:]))
+	require.NoError(t, appendWALRecord(f, walRecordAdd, a))
+	// A record truncated by a crash is ignored.
+	_, err = f.Write([]byte{walRecordAdd, 10, 'c'})
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	txs, size, records, err := readTxWAL(path, 1024)
+	require.NoError(t, err)
+	require.Equal(t, []types.Tx{b, a}, txs)
+	require.EqualValues(t, 3+3+2+len(key)+3, size)
+	require.Equal(t, 4, records)
+
+	// The truncated record is dropped before appending to the journal.
+	wal, _, err := openTxWAL(filepath.Dir(path), 1024)
+	require.NoError(t, err)
+	require.NoError(t, wal.Add(types.Tx("d")))
+	require.NoError(t, wal.Close())
+	txs, _, records, err = readTxWAL(path, 1024)
+	require.NoError(t, err)
+	require.Equal(t, []types.Tx{b, a, types.Tx("d")}, txs)
+	require.Equal(t, 5, records)
+
+	txs, _, _, err = readTxWAL(filepath.Join(t.TempDir(), walFile), 1024)
+	require.NoError(t, err)
+	require.Empty(t, txs)
+}
+
+func TestReadTxWALOversizedRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), walFile)
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	a := types.Tx("a")
+	require.NoError(t, appendWALRecord(f, walRecordAdd, a))
+	// A corrupt length is treated as a truncated record, instead of being
+	// allocated.
+	_, err = f.Write([]byte{walRecordAdd, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f})
+	require.NoError(t, err)
+	require.NoError(t, appendWALRecord(f, walRecordAdd, types.Tx("b")))
+	require.NoError(t, f.Close())
+
+	txs, size, records, err := readTxWAL(path, 16)
+	require.NoError(t, err)
+	require.Equal(t, []types.Tx{a}, txs)
+	require.EqualValues(t, 3, size)
+	require.Equal(t, 1, records)
+}
+
+func TestReactor_WaitSyncDefersWALReplay(t *testing.T) {
+	dir := t.TempDir()
+	txmp := setup(t, 100)
+	txmp.config.WalPath = dir
+	require.NoError(t, txmp.InitWAL())
+	mustCheckTx(t, txmp, "sender1=0000=1")
+	txmp.CloseWAL()
+
+	restarted := setup(t, 100)
+	restarted.config.WalPath = dir
+	restarted.config.PersistToDisk = true
+	memR := NewReactor(restarted.config, restarted)
+	memR.SetLogger(log.TestingLogger())
+	memR.SetWaitSync()
+	require.NoError(t, memR.Start())
+	t.Cleanup(func() {
+		require.NoError(t, memR.Stop())
+	})
+
+	// The transactions are only replayed once the node caught up.
+	require.Zero(t, restarted.Size())
+	memR.SwitchToConsensus()
+	require.Equal(t, 1, restarted.Size())
+}
@@ -0,0 +1,87 @@
+package mempool
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	cfg "github.com/cometbft/cometbft/config"
+	"github.com/cometbft/cometbft/p2p"
+)
+
+// rateViolationHalfLife is the time it takes for the score of a peer to halve.
+const rateViolationHalfLife = 10 * time.Second
+
+// PeerRateLimiter limits the rate at which each peer may send transactions,
+// in transactions and in bytes per second, and scores the peers exceeding it.
+// Each peer may send up to one second worth of transactions in a burst, and
+// a transaction larger than that once the peer has not sent any for a second.
+type PeerRateLimiter struct {
+	maxTxs   float64 // per second, 0 if unlimited
+	maxBytes float64 // per second, 0 if unlimited
+	maxScore int     // 0 if peers are never disconnected
+	now      func() time.Time
+	mtx      sync.Mutex
+	peers    map[p2p.ID]*peerRate
+}
+
+// peerRate is the state of the rate limits of a peer.
+type peerRate struct {
+	txs   float64 // transactions the peer may still send
+	bytes float64 // bytes the peer may still send
+	score float64 // decaying number of transactions dropped
+	last  time.Time
+}
+
+// NewPeerRateLimiter returns a rate limiter with the limits of the config.
+func NewPeerRateLimiter(config *cfg.MempoolConfig) *PeerRateLimiter {
+	return &PeerRateLimiter{
+		maxTxs:   float64(config.PeerMaxTxsPerSecond),
+		maxBytes: float64(config.PeerMaxBytesPerSecond),
+		maxScore: config.PeerMaxRateViolations,
+		now:      time.Now,
+		peers:    make(map[p2p.ID]*peerRate),
+	}
+}
+
+// Allow reports whether the peer may send a transaction of the given size. If
+// not, the transaction should be dropped, and the score of the peer rises; an
+// ErrPeerRateExceeded error is returned once it reaches the maximum, and the
+// peer should then be disconnected.
+func (l *PeerRateLimiter) Allow(peer p2p.ID, size int) (bool, error) {
+	if l.maxTxs == 0 && l.maxBytes == 0 {
+		return true, nil
+	}
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	now := l.now()
+	r, ok := l.peers[peer]
+	if !ok {
+		r = &peerRate{txs: l.maxTxs, bytes: l.maxBytes, last: now}
+		l.peers[peer] = r
+	}
+	elapsed := now.Sub(r.last).Seconds()
+	r.last = now
+	r.txs = math.Min(r.txs+elapsed*l.maxTxs, l.maxTxs)
+	r.bytes = math.Min(r.bytes+elapsed*l.maxBytes, l.maxBytes)
+	r.score *= math.Exp2(-elapsed / rateViolationHalfLife.Seconds())
+
+	if (l.maxTxs == 0 || r.txs >= 1) && (l.maxBytes == 0 || r.bytes >= math.Min(float64(size), l.maxBytes)) {
+		r.txs--
+		r.bytes -= float64(size)
+		return true, nil
+	}
+	r.score++
+	if l.maxScore > 0 && r.score >= float64(l.maxScore) {
+		return false, ErrPeerRateExceeded{Score: r.score, Max: l.maxScore}
+	}
+	return false, nil
+}
+
+// RemovePeer forgets the state of the peer.
+func (l *PeerRateLimiter) RemovePeer(peer p2p.ID) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	delete(l.peers, peer)
+}
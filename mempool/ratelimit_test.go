@@ -0,0 +1,79 @@
+package mempool
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	cfg "github.com/cometbft/cometbft/config"
+	"github.com/cometbft/cometbft/p2p"
+)
+
+func TestPeerRateLimiter(t *testing.T) {
+	config := cfg.DefaultMempoolConfig()
+	config.PeerMaxTxsPerSecond = 2
+	config.PeerMaxBytesPerSecond = 100
+	config.PeerMaxRateViolations = 3
+	l := NewPeerRateLimiter(config)
+	now := time.Now()
+	l.now = func() time.Time { return now }
+
+	allow := func(peer string, size int) (bool, error) {
+		return l.Allow(p2p.ID(peer), size)
+	}
+
+	// A peer may send a second worth of transactions in a burst.
+	for i := 0; i < 2; i++ {
+		ok, err := allow("a", 10)
+		require.NoError(t, err)
+		require.True(t, ok)
+	}
+	ok, err := allow("a", 10)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	// Other peers aren't affected.
+	ok, err = allow("b", 10)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// The limits are replenished over time, and the score decays.
+	now = now.Add(time.Second)
+	ok, err = allow("a", 10)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// The byte limit applies too, but a transaction larger than the limit
+	// goes through once the limit is replenished.
+	now = now.Add(time.Second)
+	ok, err = allow("b", 150)
+	require.NoError(t, err)
+	require.True(t, ok)
+	ok, err = allow("b", 1)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	// Sustained abuse is reported.
+	_, err = allow("b", 1)
+	require.NoError(t, err)
+	_, err = allow("b", 1)
+	var errRate ErrPeerRateExceeded
+	require.ErrorAs(t, err, &errRate)
+	require.Equal(t, 3, errRate.Max)
+
+	// Removing a peer resets its state.
+	l.RemovePeer(p2p.ID("b"))
+	ok, err = allow("b", 10)
+	require.NoError(t, err)
+	require.True(t, ok)
+}
+
+func TestPeerRateLimiterUnlimited(t *testing.T) {
+	l := NewPeerRateLimiter(cfg.DefaultMempoolConfig())
+	for i := 0; i < 1000; i++ {
+		ok, err := l.Allow("a", 1<<20)
+		require.NoError(t, err)
+		require.True(t, ok)
+	}
+}
@@ -25,6 +25,7 @@ type Reactor struct {
 	config  *cfg.MempoolConfig
 	mempool *CListMempool
 	ids     *mempoolIDs
+	limiter *PeerRateLimiter
 
 	// Semaphores to keep track of how many connections to peers are active for broadcasting
 	// transactions. Each semaphore has a capacity that puts an upper bound on the number of
@@ -39,6 +40,7 @@ func NewReactor(config *cfg.MempoolConfig, mempool *CListMempool) *Reactor {
 		config:  config,
 		mempool: mempool,
 		ids:     newMempoolIDs(),
+		limiter: NewPeerRateLimiter(config),
 	}
 	memR.BaseReactor = *p2p.NewBaseReactor("Mempool", memR)
 	memR.activePersistentPeersSemaphore = semaphore.NewWeighted(int64(memR.config.ExperimentalMaxGossipConnectionsToPersistentPeers))
@@ -70,18 +72,11 @@ func (memR *Reactor) OnStart() error {
 // GetChannels implements Reactor by returning the list of channels for this
 // reactor.
 func (memR *Reactor) GetChannels() []*p2p.ChannelDescriptor {
-	largestTx := make([]byte, memR.config.MaxTxBytes)
-	batchMsg := protomem.Message{
-		Sum: &protomem.Message_Txs{
-			Txs: &protomem.Txs{Txs: [][]byte{largestTx}},
-		},
-	}
-
 	return []*p2p.ChannelDescriptor{
 		{
 			ID:                  MempoolChannel,
 			Priority:            5,
-			RecvMessageCapacity: batchMsg.Size(),
+			RecvMessageCapacity: MaxTxsMessageSize(memR.config),
 			MessageType:         &protomem.Message{},
 		},
 	}
@@ -134,6 +129,7 @@ func (memR *Reactor) AddPeer(peer p2p.Peer) error {
 // RemovePeer implements Reactor.
 func (memR *Reactor) RemovePeer(peer p2p.Peer, _ interface{}) {
 	memR.ids.Reclaim(peer)
+	memR.limiter.RemovePeer(peer.ID())
 	// broadcast routine checks if peer is gone and returns
 }
 
@@ -143,32 +139,15 @@ func (memR *Reactor) Receive(e p2p.Envelope) {
 	memR.Logger.Debug("Receive", "src", e.Src, "chId", e.ChannelID, "msg", e.Message)
 	switch msg := e.Message.(type) {
 	case *protomem.Txs:
-		protoTxs := msg.GetTxs()
-		if len(protoTxs) == 0 {
-			memR.Logger.Error("received empty txs from peer", "src", e.Src)
+		memR.receiveTxs(e.Src, msg.GetTxs())
+	case *protomem.CompressedTxs:
+		txs, err := DecompressTxs(msg, MaxTxsMessageSize(memR.config))
+		if err != nil {
+			memR.Logger.Error("received invalid compressed txs from peer", "src", e.Src, "err", err)
+			memR.Switch.StopPeerForError(e.Src, err, memR.String())
 			return
 		}
-		txInfo := TxInfo{SenderID: memR.ids.GetForPeer(e.Src)}
-		if e.Src != nil {
-			txInfo.SenderP2PID = e.Src.ID()
-		}
-
-		var err error
-		for _, tx := range protoTxs {
-			ntx := types.Tx(tx)
-			err = memR.mempool.CheckTx(ntx, nil, txInfo)
-			if err != nil {
-				switch {
-				case errors.Is(err, ErrTxInCache):
-					memR.Logger.Debug("Tx already exists in cache", "tx", ntx.String())
-				case errors.As(err, &ErrMempoolIsFull{}):
-					// using debug level to avoid flooding when traffic is high
-					memR.Logger.Debug(err.Error())
-				default:
-					memR.Logger.Info("Could not check tx", "tx", ntx.String(), "err", err)
-				}
-			}
-		}
+		memR.receiveTxs(e.Src, txs)
 	default:
 		memR.Logger.Error("unknown message type", "src", e.Src, "chId", e.ChannelID, "msg", e.Message)
 		memR.Switch.StopPeerForError(e.Src, fmt.Errorf("mempool cannot handle message of type: %T", e.Message), memR.String())
@@ -178,6 +157,47 @@ func (memR *Reactor) Receive(e p2p.Envelope) {
 	// broadcasting happens from go routines per peer
 }
 
+// receiveTxs checks the transactions received from src, which is nil for
+// transactions not received from a peer.
+func (memR *Reactor) receiveTxs(src p2p.Peer, protoTxs [][]byte) {
+	if len(protoTxs) == 0 {
+		memR.Logger.Error("received empty txs from peer", "src", src)
+		return
+	}
+	txInfo := TxInfo{SenderID: memR.ids.GetForPeer(src)}
+	if src != nil {
+		txInfo.SenderP2PID = src.ID()
+	}
+
+	var err error
+	for _, tx := range protoTxs {
+		ntx := types.Tx(tx)
+		if src != nil {
+			ok, err := memR.limiter.Allow(src.ID(), len(tx))
+			if err != nil {
+				memR.Switch.StopPeerForError(src, err, memR.String())
+				return
+			}
+			if !ok {
+				memR.Logger.Debug("dropped tx exceeding the peer rate limits", "src", src, "tx", ntx.String())
+				continue
+			}
+		}
+		err = memR.mempool.CheckTx(ntx, nil, txInfo)
+		if err != nil {
+			switch {
+			case errors.Is(err, ErrTxInCache):
+				memR.Logger.Debug("Tx already exists in cache", "tx", ntx.String())
+			case errors.As(err, &ErrMempoolIsFull{}):
+				// using debug level to avoid flooding when traffic is high
+				memR.Logger.Debug(err.Error())
+			default:
+				memR.Logger.Info("Could not check tx", "tx", ntx.String(), "err", err)
+			}
+		}
+	}
+}
+
 // PeerState describes the state of a peer.
 type PeerState interface {
 	GetHeight() int64
@@ -229,13 +249,25 @@ func (memR *Reactor) broadcastTxRoutine(peer p2p.Peer) {
 			continue
 		}
 
-		// NOTE: Transaction batching was disabled due to
-		// https://github.com/tendermint/tendermint/issues/5796
+		// Coalesce the following transactions, up to MaxBatchBytes, skipping
+		// those the peer sent us. last is the last element of the batch.
+		batch := NewTxBatch(memR.config.MaxBatchBytes)
+		last := next
+		for elem := next; elem != nil; elem = elem.Next() {
+			memTx = elem.Value.(*mempoolTx)
+			if peerState.GetHeight() < memTx.Height()-1 {
+				break
+			}
+			if !memTx.isSender(peerID) && !batch.Add(memTx.tx.Tx) {
+				break
+			}
+			last = elem
+		}
 
-		if !memTx.isSender(peerID) {
+		if batch.Len() > 0 {
 			success := peer.Send(p2p.Envelope{
 				ChannelID: MempoolChannel,
-				Message:   &protomem.Txs{Txs: [][]byte{memTx.tx.Tx}},
+				Message:   batch.Message(PeerCompression(peer, memR.config.GossipCompression)),
 			})
 			if !success {
 				time.Sleep(PeerCatchupSleepIntervalMS * time.Millisecond)
@@ -244,9 +276,9 @@ func (memR *Reactor) broadcastTxRoutine(peer p2p.Peer) {
 		}
 
 		select {
-		case <-next.NextWaitChan():
+		case <-last.NextWaitChan():
 			// see the start of the for loop for nil check
-			next = next.Next()
+			next = last.Next()
 		case <-peer.Quit():
 			return
 		case <-memR.Quit():
@@ -63,6 +63,35 @@ func TestReactorBroadcastTxsMessage(t *testing.T) {
 	waitForTxsOnReactors(t, txs, reactors)
 }
 
+// Same as TestReactorBroadcastTxsMessage, with txs gossiped in compressed
+// batches.
+func TestReactorBroadcastTxsMessageBatched(t *testing.T) {
+	for _, compression := range []string{"", cfg.MempoolCompressionZstd, cfg.MempoolCompressionSnappy} {
+		t.Run(compression, func(t *testing.T) {
+			config := cfg.TestConfig()
+			config.Mempool.MaxBatchBytes = 10 * 1024
+			config.Mempool.GossipCompression = compression
+			const N = 2
+			reactors, _ := makeAndConnectReactors(config, N)
+			defer func() {
+				for _, r := range reactors {
+					if err := r.Stop(); err != nil {
+						assert.NoError(t, err)
+					}
+				}
+			}()
+			for _, r := range reactors {
+				for _, peer := range r.Switch.Peers().List() {
+					peer.Set(types.PeerStateKey, peerState{1})
+				}
+			}
+
+			txs := addRandomTxs(t, reactors[0].mempool, numTxs, UnknownPeerID)
+			waitForTxsOnReactors(t, txs, reactors)
+		})
+	}
+}
+
 // regression test for https://github.com/tendermint/tendermint/issues/5408
 func TestReactorConcurrency(t *testing.T) {
 	config := cfg.TestConfig()
@@ -3,10 +3,13 @@ package node
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/cometbft/cometbft/consensus/propagation"
@@ -30,6 +33,8 @@ import (
 	"github.com/cometbft/cometbft/libs/trace"
 	mempl "github.com/cometbft/cometbft/mempool"
 	"github.com/cometbft/cometbft/p2p"
+	"github.com/cometbft/cometbft/p2p/filter"
+	"github.com/cometbft/cometbft/p2p/nat"
 	"github.com/cometbft/cometbft/p2p/pex"
 	"github.com/cometbft/cometbft/proxy"
 	rpccore "github.com/cometbft/cometbft/rpc/core"
@@ -65,6 +70,8 @@ type Node struct {
 	nodeInfo    p2p.NodeInfo
 	nodeKey     *p2p.NodeKey // our node privkey
 	isListening bool
+	accessList  *filter.AccessList
+	natMapper   *nat.PortMapper // maps the listen port on the gateway, if enabled
 
 	// services
 	eventBus          *types.EventBus // pub/sub for services
@@ -132,6 +139,7 @@ func CustomReactors(reactors map[string]p2p.Reactor) Option {
 					}
 				}
 				n.nodeInfo = ni
+				n.sw.SetNodeInfo(ni)
 			} else {
 				n.Logger.Error("Node info is not of type DefaultNodeInfo. Custom reactor channels can not be added.")
 			}
@@ -148,6 +156,25 @@ func StateProvider(stateProvider statesync.StateProvider) Option {
 	}
 }
 
+// SnapshotUsageHook sets the hook notified of the local snapshots requested by peers over state
+// sync, every statesync.snapshot_usage_interval, so that the application can prune the snapshots
+// which are not used.
+// WARNING: this interface is considered unstable and subject to change.
+func SnapshotUsageHook(hook statesync.SnapshotUsageHook) Option {
+	return func(n *Node) {
+		n.stateSyncReactor.SetSnapshotUsageHook(hook)
+	}
+}
+
+// WithDataHasher sets the function the hash of the data of blocks is computed with, as with
+// types.SetDataHasher. Since the handshake of NewNode replays blocks before the options are
+// applied, the hasher is installed as soon as the option is created, and so must only be created
+// for the node it is passed to. It is process-wide, and so shared by all the nodes of the process.
+func WithDataHasher(hasher types.DataHasher) Option {
+	types.SetDataHasher(hasher)
+	return func(*Node) {}
+}
+
 // BootstrapState synchronizes the stores with the application after state sync
 // has been performed offline. It is expected that the block store and state
 // store are empty at the time the function is called.
@@ -401,7 +428,8 @@ func NewNodeWithContext(ctx context.Context,
 		return nil, err
 	}
 
-	mempool, mempoolReactor := createMempoolAndMempoolReactor(config, proxyApp, state, memplMetrics, logger, tracer)
+	mempool, mempoolReactor := createMempoolAndMempoolReactor(config, proxyApp, state, memplMetrics, eventBus, logger, tracer,
+		stateSync || blockSync)
 
 	evidenceReactor, evidencePool, err := createEvidenceReactor(config, dbProvider, stateStore, blockStore, logger)
 	if err != nil {
@@ -440,11 +468,15 @@ func NewNodeWithContext(ctx context.Context,
 	}
 	partsChan := make(chan types.PartInfo, 2500)
 	proposalChan := make(chan types.Proposal, 100)
+	// mempool reactors able to request transactions by hash, such as the CAT
+	// one, fetch the transactions of proposals missing from the mempool
+	txFetcher, _ := mempoolReactor.(propagation.TxFetcher)
 	propagationReactor := propagation.NewReactor(
 		nodeKey.ID(),
 		propagation.Config{
 			Store:         blockStore,
 			Mempool:       mempool,
+			TxFetcher:     txFetcher,
 			Privval:       privValidator,
 			ChainID:       state.ChainID,
 			BlockMaxBytes: state.ConsensusParams.Block.MaxBytes,
@@ -473,25 +505,37 @@ func NewNodeWithContext(ctx context.Context,
 	// FIXME The way we do phased startups (e.g. replay -> block sync -> consensus) is very messy,
 	// we should clean this whole thing up. See:
 	// https://github.com/tendermint/tendermint/issues/4644
-	stateSyncReactor := statesync.NewReactor(
+	stateSyncReactor, err := statesync.NewReactor(
 		*config.StateSync,
 		proxyApp.Snapshot(),
 		proxyApp.Query(),
 		ssMetrics,
 	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create state sync reactor: %w", err)
+	}
 	stateSyncReactor.SetLogger(logger.With("module", "statesync"))
+	stateSyncReactor.SetEventBus(eventBus)
 
 	nodeInfo, err := makeNodeInfo(config, nodeKey, txIndexer, genDoc, state, softwareVersion)
 	if err != nil {
 		return nil, err
 	}
 
-	transport, peerFilters := createTransport(config, nodeInfo, nodeKey, proxyApp, tracer)
+	var accessList *filter.AccessList
+	if path := config.P2P.AccessListFile(); path != "" {
+		accessList, err = filter.NewAccessList(path)
+		if err != nil {
+			return nil, fmt.Errorf("could not load p2p access list: %w", err)
+		}
+	}
+
+	transport, peerFilters := createTransport(config, nodeInfo, nodeKey, proxyApp, accessList, tracer)
 
 	p2pLogger := logger.With("module", "p2p")
 	sw := createSwitch(
 		config, transport, p2pMetrics, peerFilters, mempoolReactor, bcReactor,
-		stateSyncReactor, consensusReactor, evidenceReactor, propagationReactor, nodeInfo, nodeKey, p2pLogger, tracer,
+		stateSyncReactor, consensusReactor, evidenceReactor, propagationReactor, nodeInfo, nodeKey, eventBus, p2pLogger, tracer,
 	)
 
 	err = sw.AddPersistentPeers(splitAndTrimEmpty(config.P2P.PersistentPeers, ",", " "))
@@ -522,7 +566,7 @@ func NewNodeWithContext(ctx context.Context,
 	// If PEX is on, it should handle dialing the seeds. Otherwise the switch does it.
 	// Note we currently use the addrBook regardless at least for AddOurAddress
 	var pexReactor *pex.Reactor
-	if config.P2P.PexReactor {
+	if config.P2P.PexEnabled() {
 		pexReactor = createPEXReactorAndAddToSwitch(addrBook, config, sw, logger)
 	}
 
@@ -540,6 +584,8 @@ func NewNodeWithContext(ctx context.Context,
 		nodeInfo:  nodeInfo,
 		nodeKey:   nodeKey,
 
+		accessList: accessList,
+
 		stateStore:       stateStore,
 		blockStore:       blockStore,
 		bcReactor:        bcReactor,
@@ -626,6 +672,24 @@ func (n *Node) OnStart() error {
 		return err
 	}
 
+	if n.accessList != nil {
+		go n.reloadAccessListOnSignal()
+	}
+
+	// Map the listen port on the gateway of the node, and advertise the
+	// external address of the mapping once it is verified to reach us.
+	if n.config.P2P.NAT != "" && n.config.P2P.ExternalAddress == "" {
+		n.natMapper = nat.NewPortMapper(n.config.P2P.NAT, int(addr.Port), func(externalAddr string) {
+			if err := n.sw.AdvertiseExternalAddress(externalAddr); err != nil {
+				n.Logger.Error("Failed to advertise the address of the port mapping", "addr", externalAddr, "err", err)
+			}
+		})
+		n.natMapper.SetLogger(n.Logger.With("module", "nat"))
+		if err := n.natMapper.Start(); err != nil {
+			return err
+		}
+	}
+
 	// Always connect to persistent peers
 	err = n.sw.DialPeersAsync(splitAndTrimEmpty(n.config.P2P.PersistentPeers, ",", " "))
 	if err != nil {
@@ -663,6 +727,11 @@ func (n *Node) OnStop() {
 			n.Logger.Error("Error closing indexerService", "err", err)
 		}
 	}
+	if n.natMapper != nil {
+		if err := n.natMapper.Stop(); err != nil {
+			n.Logger.Error("Error closing port mapper", "err", err)
+		}
+	}
 	// now stop the reactors
 	if err := n.sw.Stop(); err != nil {
 		n.Logger.Error("Error closing switch", "err", err)
@@ -750,12 +819,14 @@ func (n *Node) ConfigureRPC() (*rpccore.Environment, error) {
 		ConsensusState: n.consensusState,
 		P2PPeers:       n.sw,
 		P2PTransport:   n,
+		P2PAccessList:  n,
 		PubKey:         pubKey,
 
 		GenDoc:           n.genesisDoc,
 		TxIndexer:        n.txIndexer,
 		BlockIndexer:     n.blockIndexer,
 		ConsensusReactor: n.consensusReactor,
+		StateSyncReactor: n.stateSyncReactor,
 		EventBus:         n.eventBus,
 		Mempool:          n.mempool,
 
@@ -1001,9 +1072,49 @@ func (n *Node) IsListening() bool {
 	return n.isListening
 }
 
+// ReloadAccessList reads the access list of peers again (see
+// config.P2PConfig.AccessList), and disconnects the peers it now rejects. It
+// returns the number of peers disconnected.
+func (n *Node) ReloadAccessList() (int, error) {
+	if n.accessList == nil {
+		return 0, errors.New("no p2p access list configured")
+	}
+	if err := n.accessList.Reload(); err != nil {
+		return 0, err
+	}
+	denied := n.accessList.DeniedPeers(n.sw.Peers())
+	for _, p := range denied {
+		n.Logger.Info("Disconnecting peer rejected by the p2p access list", "peer", p)
+		n.sw.StopPeerGracefully(p, "")
+	}
+	return len(denied), nil
+}
+
+// reloadAccessListOnSignal reloads the access list of peers on SIGHUP, until
+// the node stops.
+func (n *Node) reloadAccessListOnSignal() {
+	hupc := make(chan os.Signal, 1)
+	signal.Notify(hupc, syscall.SIGHUP)
+	defer signal.Stop(hupc)
+
+	for {
+		select {
+		case <-hupc:
+			disconnected, err := n.ReloadAccessList()
+			if err != nil {
+				n.Logger.Error("Failed to reload p2p access list", "err", err)
+				continue
+			}
+			n.Logger.Info("Reloaded p2p access list", "disconnected", disconnected)
+		case <-n.Quit():
+			return
+		}
+	}
+}
+
 // NodeInfo returns the Node's Info from the Switch.
 func (n *Node) NodeInfo() p2p.NodeInfo {
-	return n.nodeInfo
+	return n.sw.NodeInfo()
 }
 
 func makeNodeInfo(
@@ -1043,12 +1154,14 @@ func makeNodeInfo(
 		Channels:      channels,
 		Moniker:       config.Moniker,
 		Other: p2p.DefaultNodeInfoOther{
-			TxIndex:    txIndexerStatus,
-			RPCAddress: config.RPC.ListenAddress,
+			TxIndex:     txIndexerStatus,
+			RPCAddress:  config.RPC.ListenAddress,
+			Compression: p2p.SupportedCompression,
+			Role:        config.P2P.NodeRole(),
 		},
 	}
 
-	if config.P2P.PexReactor {
+	if config.P2P.PexEnabled() {
 		nodeInfo.Channels = append(nodeInfo.Channels, pex.PexChannel)
 	}
 
@@ -18,6 +18,7 @@ import (
 	"github.com/cometbft/cometbft/abci/example/kvstore"
 	cfg "github.com/cometbft/cometbft/config"
 	"github.com/cometbft/cometbft/crypto/ed25519"
+	"github.com/cometbft/cometbft/crypto/tmhash"
 	"github.com/cometbft/cometbft/evidence"
 	"github.com/cometbft/cometbft/internal/test"
 	"github.com/cometbft/cometbft/libs/log"
@@ -157,6 +158,29 @@ func TestPprofServer(t *testing.T) {
 	assert.Equal(t, 200, resp.StatusCode)
 }
 
+func TestNodeReloadAccessList(t *testing.T) {
+	config := test.ResetTestRoot("node_access_list_test")
+	defer os.RemoveAll(config.RootDir)
+
+	n, err := DefaultNewNode(config, log.TestingLogger())
+	require.NoError(t, err)
+	_, err = n.ReloadAccessList()
+	assert.Error(t, err, "no access list configured")
+
+	config.P2P.AccessList = "config/access_list.json"
+	path := config.P2P.AccessListFile()
+	require.NoError(t, os.WriteFile(path, []byte(`{"deny": ["192.0.2.0/24"]}`), 0o600))
+	n, err = DefaultNewNode(config, log.TestingLogger())
+	require.NoError(t, err)
+	assert.Error(t, n.accessList.Check("", net.ParseIP("192.0.2.1")))
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"deny": []}`), 0o600))
+	disconnected, err := n.ReloadAccessList()
+	require.NoError(t, err)
+	assert.Zero(t, disconnected)
+	assert.NoError(t, n.accessList.Check("", net.ParseIP("192.0.2.1")))
+}
+
 func TestNodeSetPrivValTCP(t *testing.T) {
 	addr := "tcp://" + testFreeAddr(t)
 
@@ -464,6 +488,47 @@ func TestNodeNewNodeCustomReactors(t *testing.T) {
 	assert.Contains(t, channels, cr.Channels[0].ID)
 }
 
+func TestNodeNewNodeWithDataHasher(t *testing.T) {
+	config := test.ResetTestRoot("node_new_node_with_data_hasher_test")
+	defer os.RemoveAll(config.RootDir)
+
+	hasher := func(data *types.Data) []byte {
+		hash, err := types.DefaultDataHash(data)
+		if err != nil {
+			return nil
+		}
+		return tmhash.Sum(append([]byte("square"), hash...))
+	}
+	defer types.SetDataHasher(nil)
+
+	nodeKey, err := p2p.LoadOrGenNodeKey(config.NodeKeyFile())
+	require.NoError(t, err)
+	n, err := NewNode(config,
+		privval.LoadOrGenFilePV(config.PrivValidatorKeyFile(), config.PrivValidatorStateFile()),
+		nodeKey,
+		proxy.DefaultClientCreator(config.ProxyApp, config.ABCI, config.DBDir()),
+		DefaultGenesisDocProviderFunc(config),
+		cfg.DefaultDBProvider,
+		DefaultMetricsProvider(config.Instrumentation),
+		log.TestingLogger(),
+		WithDataHasher(hasher),
+	)
+	require.NoError(t, err)
+
+	blocksSub, err := n.EventBus().Subscribe(context.Background(), "node_test", types.EventQueryNewBlock)
+	require.NoError(t, err)
+	require.NoError(t, n.Start())
+	defer n.Stop() //nolint:errcheck // ignore for tests
+
+	select {
+	case msg := <-blocksSub.Out():
+		block := msg.Data().(types.EventDataNewBlock).Block
+		assert.EqualValues(t, hasher(&block.Data), block.DataHash)
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for the node to produce a block")
+	}
+}
+
 func state(nVals int, height int64) (sm.State, dbm.DB, []types.PrivValidator) {
 	privVals := make([]types.PrivValidator, nVals)
 	vals := make([]types.GenesisValidator, nVals)
@@ -27,10 +27,13 @@ import (
 	"github.com/cometbft/cometbft/libs/log"
 	"github.com/cometbft/cometbft/libs/trace"
 	"github.com/cometbft/cometbft/light"
+	lightprovider "github.com/cometbft/cometbft/light/provider"
+	lighthttp "github.com/cometbft/cometbft/light/provider/http"
 	mempl "github.com/cometbft/cometbft/mempool"
 	"github.com/cometbft/cometbft/mempool/cat"
 	"github.com/cometbft/cometbft/mempool/priority"
 	"github.com/cometbft/cometbft/p2p"
+	"github.com/cometbft/cometbft/p2p/filter"
 	"github.com/cometbft/cometbft/p2p/pex"
 	"github.com/cometbft/cometbft/privval"
 	"github.com/cometbft/cometbft/proxy"
@@ -107,6 +110,12 @@ type blockSyncReactor interface {
 	SwitchToBlockSync(sm.State) error
 }
 
+// blockSyncBackfiller is implemented by block sync reactors which can backfill
+// the blocks below the height restored by state sync.
+type blockSyncBackfiller interface {
+	Backfill(state sm.State, depth int64) error
+}
+
 //------------------------------------------------------------------------------
 
 // initDBs opens or creates the blockstore and state databases.
@@ -234,15 +243,34 @@ func onlyValidatorIsUs(state sm.State, localAddr crypto.Address) bool {
 	return bytes.Equal(localAddr, valAddr)
 }
 
+// mempoolLanes converts the configured lanes to priority mempool lanes.
+func mempoolLanes(lanes []cfg.MempoolLane) []priority.Lane {
+	out := make([]priority.Lane, len(lanes))
+	for i, lane := range lanes {
+		out[i] = priority.Lane{Name: lane.Name, MaxTxs: lane.MaxTxs, MaxTxsBytes: lane.MaxTxsBytes}
+	}
+	return out
+}
+
 // createMempoolAndMempoolReactor creates a mempool and a mempool reactor based on the config.
 func createMempoolAndMempoolReactor(
 	config *cfg.Config,
 	proxyApp proxy.AppConns,
 	state sm.State,
 	memplMetrics *mempl.Metrics,
+	eventBus *types.EventBus,
 	logger log.Logger,
 	traceClient trace.Tracer,
+	waitSync bool,
 ) (mempl.Mempool, p2p.Reactor) {
+	// The priority and cat mempools queue their evictions, so that they are
+	// published outside of the mempool lock.
+	publishEvicted := func(data types.EventDataTxEvicted) {
+		if err := eventBus.PublishEventTxEvicted(data); err != nil {
+			logger.Error("failed publishing evicted transaction", "err", err)
+		}
+	}
+
 	switch config.Mempool.Type {
 	// allow empty string for backward compatibility
 	case cfg.MempoolTypeFlood, cfg.LegacyMempoolTypeFlood, "":
@@ -272,18 +300,32 @@ func createMempoolAndMempoolReactor(
 		// adding it leads to a cleaner code.
 		return &mempl.NopMempool{}, mempl.NewNopMempoolReactor()
 	case cfg.MempoolTypePriority, cfg.LegacyMempoolTypePriority:
+		options := []priority.TxMempoolOption{
+			priority.WithMetrics(memplMetrics),
+			priority.WithPreCheck(sm.TxPreCheck(state)),
+			priority.WithEvictionCallback(publishEvicted),
+		}
+		// The lanes were validated along with the rest of the configuration.
+		if lanes, _ := cfg.ParseMempoolLanes(config.Mempool.Lanes); len(lanes) > 0 {
+			options = append(options, priority.WithLanes(nil, mempoolLanes(lanes)...))
+		}
+		if config.Mempool.NonceOrdering {
+			options = append(options, priority.WithNonces(priority.NonceFromResponse))
+		}
 		mp := priority.NewTxMempool(
 			logger,
 			config.Mempool,
 			proxyApp.Mempool(),
 			state.LastBlockHeight,
-			priority.WithMetrics(memplMetrics),
-			priority.WithPreCheck(sm.TxPreCheck(state)),
+			options...,
 		)
 		reactor := priority.NewReactor(
 			config.Mempool,
 			mp,
 		)
+		if waitSync {
+			reactor.SetWaitSync()
+		}
 		reactor.SetLogger(logger)
 		return mp, reactor
 	case cfg.MempoolTypeCAT, cfg.LegacyMempoolTypeCAT:
@@ -295,6 +337,7 @@ func createMempoolAndMempoolReactor(
 			cat.WithMetrics(memplMetrics),
 			cat.WithPreCheck(sm.TxPreCheck(state)),
 			cat.WithPostCheck(sm.TxPostCheck(state)),
+			cat.WithEvictionCallback(publishEvicted),
 		)
 
 		reactor, err := cat.NewReactor(
@@ -350,7 +393,31 @@ func createBlocksyncReactor(config *cfg.Config,
 ) (bcReactor p2p.Reactor, err error) {
 	switch config.BlockSync.Version {
 	case "v0":
-		bcReactor = blocksync.NewReactorWithAddr(state.Copy(), blockExec, blockStore, blockSync, localAddr, metrics, offlineStateSyncHeight)
+		r := blocksync.NewReactorWithAddr(state.Copy(), blockExec, blockStore, blockSync, localAddr, metrics, offlineStateSyncHeight)
+		if config.BlockSync.ArchiveSource != "" {
+			if config.BlockSync.ArchiveSource == blocksync.ArchiveSourceRPC &&
+				state.ConsensusParams.ABCI.VoteExtensionsEnableHeight > 0 {
+				return nil, errors.New("the rpc block archive can't provide the extended commits " +
+					"required by vote extensions, use an object_store archive instead")
+			}
+			archive, err := blocksync.NewBlockArchive(config.BlockSync.ArchiveSource, config.BlockSync.ArchiveURL)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create block archive: %w", err)
+			}
+			r.SetBlockArchive(archive, config.BlockSync.ArchiveDelay)
+		}
+		if len(config.BlockSync.Witnesses) > 0 && config.BlockSync.WitnessSampleRate > 0 {
+			witnesses := make([]lightprovider.Provider, 0, len(config.BlockSync.Witnesses))
+			for _, addr := range config.BlockSync.Witnesses {
+				witness, err := lighthttp.New(state.ChainID, addr)
+				if err != nil {
+					return nil, fmt.Errorf("failed to create witness %s: %w", addr, err)
+				}
+				witnesses = append(witnesses, witness)
+			}
+			r.SetWitnesses(witnesses, config.BlockSync.WitnessSampleRate)
+		}
+		bcReactor = r
 	case "v1", "v2":
 		return nil, fmt.Errorf("block sync version %s has been deprecated. Please use v0", config.BlockSync.Version)
 	default:
@@ -409,6 +476,7 @@ func createTransport(
 	nodeInfo p2p.NodeInfo,
 	nodeKey *p2p.NodeKey,
 	proxyApp proxy.AppConns,
+	accessList *filter.AccessList,
 	traceClient trace.Tracer,
 ) (
 	*p2p.MultiplexTransport,
@@ -465,12 +533,25 @@ func createTransport(
 		)
 	}
 
+	// Filter peers by IP and ID with the access list, if any.
+	if accessList != nil {
+		connFilters = append(connFilters, accessList.FilterConn)
+		peerFilters = append(peerFilters, accessList.FilterPeer)
+	}
+
 	p2p.MultiplexTransportConnFilters(connFilters...)(transport)
 
 	// Limit the number of incoming connections.
 	max := config.P2P.MaxNumInboundPeers + len(splitAndTrimEmpty(config.P2P.UnconditionalPeerIDs, ",", " "))
 	p2p.MultiplexTransportMaxIncomingConnections(max)(transport)
 
+	// Compress the messages sent to the peers supporting it.
+	p2p.MultiplexTransportCompression(config.P2P.Compression)(transport)
+
+	if config.P2P.HandshakeTimeout > 0 {
+		p2p.MultiplexTransportHandshakeTimeout(config.P2P.HandshakeTimeout)(transport)
+	}
+
 	return transport, peerFilters
 }
 
@@ -486,6 +567,7 @@ func createSwitch(config *cfg.Config,
 	propagationReactor *propagation.Reactor,
 	nodeInfo p2p.NodeInfo,
 	nodeKey *p2p.NodeKey,
+	eventBus *types.EventBus,
 	p2pLogger log.Logger,
 	traceClient trace.Tracer,
 ) *p2p.Switch {
@@ -495,6 +577,28 @@ func createSwitch(config *cfg.Config,
 		p2p.WithMetrics(p2pMetrics),
 		p2p.SwitchPeerFilters(peerFilters...),
 		p2p.WithTracer(traceClient),
+		p2p.WithRejectionCallback(func(err p2p.ErrRejected, outbound bool) {
+			data := types.EventDataPeerRejected{
+				PeerID:         string(err.ID()),
+				Reason:         err.Reason(),
+				Outbound:       outbound,
+				LocalAddr:      err.LocalAddr(),
+				RemoteAddr:     err.RemoteAddr(),
+				HandshakeStage: err.HandshakeStage(),
+				TraceID:        err.TraceID(),
+				ChainID:        err.ChainID(),
+				PeerChainID:    err.PeerChainID(),
+			}
+			if err.Err() != nil {
+				data.Error = err.Err().Error()
+			}
+			if data.PeerID == "" {
+				data.PeerID = err.RemoteNodeID()
+			}
+			if err := eventBus.PublishEventPeerRejected(data); err != nil {
+				p2pLogger.Error("failed publishing rejected peer", "err", err)
+			}
+		}),
 	)
 	sw.SetLogger(p2pLogger)
 	if config.Mempool.Type != cfg.MempoolTypeNop {
@@ -607,6 +711,18 @@ func startStateSync(
 			return
 		}
 
+		if config.BackfillBlocks > 0 {
+			if backfiller, ok := bcR.(blockSyncBackfiller); ok {
+				// A failed backfill leaves a gap below the snapshot height, as
+				// without a backfill, so the node still switches to block sync.
+				if err := backfiller.Backfill(state, config.BackfillBlocks); err != nil {
+					ssR.Logger.Error("Failed to backfill blocks", "err", err)
+				}
+			} else {
+				ssR.Logger.Error("This blocksync reactor does not support backfilling blocks")
+			}
+		}
+
 		err = bcR.SwitchToBlockSync(state)
 		if err != nil {
 			ssR.Logger.Error("Failed to switch to block sync", "err", err)
@@ -0,0 +1,89 @@
+package conn
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cometbft/cometbft/config"
+	"github.com/cometbft/cometbft/libs/log"
+)
+
+func TestMConnectionCompression(t *testing.T) {
+	server, client := NetPipe()
+	defer server.Close()
+	defer client.Close()
+
+	receivedCh := make(chan []byte, 2)
+	recvCfg := DefaultMConnConfig()
+	recvCfg.RecvCompression = config.P2PCompressionSnappy + "," + config.P2PCompressionZstd
+	chDescs := []*ChannelDescriptor{{ID: 0x01, Priority: 1, SendQueueCapacity: 1}}
+	mconn1 := NewMConnectionWithConfig(client, chDescs, func(_ byte, msgBytes []byte) {
+		receivedCh <- msgBytes
+	}, func(interface{}) {}, recvCfg)
+	mconn1.SetLogger(log.TestingLogger())
+	err := mconn1.Start()
+	require.Nil(t, err)
+	defer mconn1.Stop() //nolint:errcheck // ignore for tests
+
+	cfg := DefaultMConnConfig()
+	cfg.Compression = config.P2PCompressionZstd
+	cfg.CompressionMinSize = 100
+	mconn2 := NewMConnectionWithConfig(server, chDescs, func(byte, []byte) {}, func(interface{}) {}, cfg)
+	mconn2.SetLogger(log.TestingLogger())
+	err = mconn2.Start()
+	require.Nil(t, err)
+	defer mconn2.Stop() //nolint:errcheck // ignore for tests
+
+	// A message larger than several packets, and one below the threshold.
+	msgs := [][]byte{bytes.Repeat([]byte("Hulk"), 2000), []byte("Hawkeye")}
+	for _, msg := range msgs {
+		assert.True(t, mconn2.Send(0x01, msg))
+	}
+	for _, msg := range msgs {
+		select {
+		case receivedBytes := <-receivedCh:
+			assert.Equal(t, msg, receivedBytes)
+		case <-time.After(3 * time.Second):
+			t.Fatalf("Did not receive message of %d bytes in 3s", len(msg))
+		}
+	}
+	// Far fewer bytes were sent than the messages hold.
+	assert.Less(t, mconn2.Status().SendMonitor.Bytes, int64(1000))
+}
+
+func TestMConnectionCompressionNotNegotiated(t *testing.T) {
+	server, client := NetPipe()
+	defer server.Close()
+	defer client.Close()
+
+	errorsCh := make(chan interface{}, 1)
+	mconn1 := createMConnectionWithCallbacks(client, func(byte, []byte) {
+		t.Error("received a message compressed without negotiating it")
+	}, func(r interface{}) {
+		errorsCh <- r
+	})
+	err := mconn1.Start()
+	require.Nil(t, err)
+	defer mconn1.Stop() //nolint:errcheck // ignore for tests
+
+	cfg := DefaultMConnConfig()
+	cfg.Compression = config.P2PCompressionZstd
+	cfg.CompressionMinSize = 100
+	chDescs := []*ChannelDescriptor{{ID: 0x01, Priority: 1, SendQueueCapacity: 1}}
+	mconn2 := NewMConnectionWithConfig(server, chDescs, func(byte, []byte) {}, func(interface{}) {}, cfg)
+	mconn2.SetLogger(log.TestingLogger())
+	err = mconn2.Start()
+	require.Nil(t, err)
+	defer mconn2.Stop() //nolint:errcheck // ignore for tests
+
+	assert.True(t, mconn2.Send(0x01, bytes.Repeat([]byte("Hulk"), 2000)))
+	select {
+	case <-errorsCh:
+	case <-time.After(3 * time.Second):
+		t.Fatal("Did not stop the connection sending a compressed message in 3s")
+	}
+}
@@ -15,10 +15,12 @@ import (
 	"github.com/cosmos/gogoproto/proto"
 
 	"github.com/cometbft/cometbft/config"
+	"github.com/cometbft/cometbft/libs/compress"
 	flow "github.com/cometbft/cometbft/libs/flowrate"
 	"github.com/cometbft/cometbft/libs/log"
 	"github.com/cometbft/cometbft/libs/protoio"
 	"github.com/cometbft/cometbft/libs/service"
+	cmtstrings "github.com/cometbft/cometbft/libs/strings"
 	cmtsync "github.com/cometbft/cometbft/libs/sync"
 	"github.com/cometbft/cometbft/libs/timer"
 	tmp2p "github.com/cometbft/cometbft/proto/tendermint/p2p"
@@ -32,6 +34,11 @@ const (
 	minWriteBufferSize = 65536
 	updateStats        = 2 * time.Second
 
+	// throttledWakeup is how long the sendRoutine waits before trying again
+	// to send on channels that exceeded their send rate. It matches the
+	// sampling period of the channel send monitors.
+	throttledWakeup = 100 * time.Millisecond
+
 	// some of these defaults are written in the user config
 	// flushThrottle, sendRate, recvRate
 	// TODO: remove values present in config
@@ -93,6 +100,9 @@ type MConnection struct {
 	onError       errorCbFunc
 	errored       uint32
 	config        MConnConfig
+	compression   tmp2p.Compression
+	// algorithms the peer may compress messages with
+	recvCompression map[tmp2p.Compression]struct{}
 
 	// Closing quitSendRoutine will cause the sendRoutine to eventually quit.
 	// doneSendRoutine is closed when the sendRoutine actually quits.
@@ -106,13 +116,18 @@ type MConnection struct {
 	// are safe to call concurrently.
 	stopMtx cmtsync.Mutex
 
-	flushTimer *timer.ThrottleTimer // flush writes as necessary but throttled.
-	pingTimer  *time.Ticker         // send pings periodically
+	flushTimer    *timer.ThrottleTimer // flush writes as necessary but throttled.
+	throttleTimer *timer.ThrottleTimer // wake up sendRoutine for rate-limited channels.
+	pingTimer     *time.Ticker         // send pings periodically
 
 	// close conn if pong is not received in pongTimeout
 	pongTimer     *time.Timer
 	pongTimeoutCh chan bool // true - timeout, false - peer sent pong
 
+	// round-trip time of the pings, measured when pongs are received (atomic)
+	pingSentAt int64 // unix nanoseconds, 0 once the pong was received
+	rtt        int64 // nanoseconds
+
 	chStatsTimer *time.Ticker // update channel stats periodically
 
 	created time.Time // time of creation
@@ -137,6 +152,24 @@ type MConnConfig struct {
 	// Maximum wait time for pongs
 	PongTimeout time.Duration `mapstructure:"pong_timeout"`
 
+	// Rates at which packets can be sent on given channels, in bytes/second,
+	// on top of SendRate. Channels without a rate are only limited by
+	// SendRate.
+	ChannelSendRates map[byte]int64 `mapstructure:"channel_send_rates"`
+
+	// Algorithm to compress the messages sent with, one of the
+	// config.P2PCompression values, as negotiated with the peer. Empty
+	// disables compression.
+	Compression string `mapstructure:"compression"`
+
+	// Comma separated algorithms, among the config.P2PCompression values,
+	// which the peer may compress the messages it sends with, as negotiated
+	// with it. Receiving messages compressed otherwise is a protocol error.
+	RecvCompression string `mapstructure:"recv_compression"`
+
+	// Minimum size of the messages compressed, in bytes
+	CompressionMinSize int `mapstructure:"compression_min_size"`
+
 	// Fuzz connection
 	TestFuzz       bool                   `mapstructure:"test_fuzz"`
 	TestFuzzConfig *config.FuzzConnConfig `mapstructure:"test_fuzz_config"`
@@ -192,9 +225,17 @@ func NewMConnectionWithConfig(
 		onReceive:     onReceive,
 		onError:       onError,
 		config:        config,
+		compression:   compress.FromConfig(config.Compression),
 		created:       time.Now(),
 	}
 
+	mconn.recvCompression = make(map[tmp2p.Compression]struct{})
+	for _, algo := range cmtstrings.SplitAndTrimEmpty(config.RecvCompression, ",", " ") {
+		if c := compress.FromConfig(algo); c != tmp2p.Compression_COMPRESSION_NONE {
+			mconn.recvCompression[c] = struct{}{}
+		}
+	}
+
 	// Create channels
 	channelsIdx := map[byte]*Channel{}
 	channels := []*Channel{}
@@ -228,6 +269,7 @@ func (c *MConnection) OnStart() error {
 		return err
 	}
 	c.flushTimer = timer.NewThrottleTimer("flush", c.config.FlushThrottle)
+	c.throttleTimer = timer.NewThrottleTimer("throttle", throttledWakeup)
 	c.pingTimer = time.NewTicker(c.config.PingInterval)
 	c.pongTimeoutCh = make(chan bool, 1)
 	c.chStatsTimer = time.NewTicker(updateStats)
@@ -262,6 +304,7 @@ func (c *MConnection) stopServices() (alreadyStopped bool) {
 
 	c.BaseService.OnStop()
 	c.flushTimer.Stop()
+	c.throttleTimer.Stop()
 	c.pingTimer.Stop()
 	c.chStatsTimer.Stop()
 
@@ -425,6 +468,25 @@ func (c *MConnection) CanSend(chID byte) bool {
 	return channel.canSend()
 }
 
+// SetChannelSendRate sets the rate at which packets can be sent on the
+// channel with the given id, in bytes/second, on top of the send rate of the
+// connection. A rate of 0 removes the limit. It returns false if there is no
+// such channel.
+// Goroutine-safe
+func (c *MConnection) SetChannelSendRate(chID byte, rate int64) bool {
+	channel, ok := c.channelsIdx[chID]
+	if !ok {
+		return false
+	}
+	atomic.StoreInt64(&channel.sendRate, rate)
+	// Wake up sendRoutine in case the channel was throttled.
+	select {
+	case c.send <- struct{}{}:
+	default:
+	}
+	return true
+}
+
 // sendRoutine polls for packets to send from channels.
 func (c *MConnection) sendRoutine() {
 	defer c._recover()
@@ -460,6 +522,7 @@ FOR_LOOP:
 				default:
 				}
 			})
+			atomic.StoreInt64(&c.pingSentAt, time.Now().UnixNano())
 			c.flush()
 		case timeout := <-c.pongTimeoutCh:
 			if timeout {
@@ -477,6 +540,12 @@ FOR_LOOP:
 			}
 			c.sendMonitor.Update(_n)
 			c.flush()
+		case <-c.throttleTimer.Ch:
+			// Rate-limited channels may send again.
+			select {
+			case c.send <- struct{}{}:
+			default:
+			}
 		case <-c.quitSendRoutine:
 			break FOR_LOOP
 		case <-c.send:
@@ -528,9 +597,13 @@ func (c *MConnection) sendBatchPacketMsgs(w protoio.Writer, batchSize int) bool
 		}
 	}()
 	for i := 0; i < batchSize; i++ {
-		channel := selectChannelToGossipOn(c.channels)
+		channel, throttled := selectChannelToGossipOn(c.channels, c._maxPacketMsgSize)
 		// nothing to send across any channel.
 		if channel == nil {
+			if throttled {
+				// Try again once the rate-limited channels may send.
+				c.throttleTimer.Set()
+			}
 			return true
 		}
 		bytesWritten, err := c.sendPacketMsgOnChannel(w, channel)
@@ -542,21 +615,30 @@ func (c *MConnection) sendBatchPacketMsgs(w protoio.Writer, batchSize int) bool
 	return false
 }
 
-// selects a channel to gossip our next message on.
+// selects a channel to gossip our next message on. Channels are scheduled in
+// a weighted-fair manner, by the bytes they recently sent weighted by their
+// priority, skipping the channels that exceeded their send rate, so that bulk
+// traffic (e.g. blocks or snapshot chunks) cannot starve the other channels
+// (e.g. votes). It also reports whether a channel had something to send but
+// was skipped for exceeding its send rate.
 // TODO: Make "batchChannelToGossipOn", so we can do our proto marshaling overheads in parallel,
 // and we can avoid re-checking for `isSendPending`.
 // We can easily mock the recentlySent differences for the batch choosing.
-func selectChannelToGossipOn(channels []*Channel) *Channel {
+func selectChannelToGossipOn(channels []*Channel, packetSize int) (leastChannel *Channel, throttled bool) {
 	// Choose a channel to create a PacketMsg from.
 	// The chosen channel will be the one whose recentlySent/priority is the least.
 	var leastRatio float32 = math.MaxFloat32
-	var leastChannel *Channel
 	for _, channel := range channels {
 		// If nothing to send, skip this channel
 		// TODO: Skip continually looking for isSendPending on channels we've already skipped in this batch-send.
 		if !channel.isSendPending() {
 			continue
 		}
+		// If the channel exceeded its send rate, skip it for now.
+		if channel.isThrottled(packetSize) {
+			throttled = true
+			continue
+		}
 		// Get ratio, and keep track of lowest ratio.
 		// TODO: RecentlySent right now is bytes. This should be refactored to num messages to fix
 		// gossip prioritization bugs.
@@ -566,7 +648,7 @@ func selectChannelToGossipOn(channels []*Channel) *Channel {
 			leastChannel = channel
 		}
 	}
-	return leastChannel
+	return leastChannel, throttled
 }
 
 // returns (num_bytes_written, error_occurred).
@@ -649,6 +731,9 @@ FOR_LOOP:
 			}
 		case *tmp2p.Packet_PacketPong:
 			c.Logger.Debug("Receive Pong")
+			if sentAt := atomic.SwapInt64(&c.pingSentAt, 0); sentAt != 0 {
+				atomic.StoreInt64(&c.rtt, time.Now().UnixNano()-sentAt)
+			}
 			select {
 			case c.pongTimeoutCh <- false:
 			default:
@@ -704,9 +789,10 @@ func (c *MConnection) stopPongTimer() {
 // maxPacketMsgSize returns a maximum size of PacketMsg
 func (c *MConnection) maxPacketMsgSize() int {
 	bz, err := proto.Marshal(mustWrapPacket(&tmp2p.PacketMsg{
-		ChannelID: 0x01,
-		EOF:       true,
-		Data:      make([]byte, c.config.MaxPacketMsgPayloadSize),
+		ChannelID:   0x01,
+		EOF:         true,
+		Data:        make([]byte, c.config.MaxPacketMsgPayloadSize),
+		Compression: tmp2p.Compression_COMPRESSION_ZSTD,
 	}))
 	if err != nil {
 		panic(err)
@@ -719,6 +805,8 @@ type ConnectionStatus struct {
 	SendMonitor flow.Status
 	RecvMonitor flow.Status
 	Channels    []ChannelStatus
+	// Round-trip time of the last ping, or 0 if no pong was received yet.
+	RTT time.Duration
 }
 
 type ChannelStatus struct {
@@ -727,6 +815,7 @@ type ChannelStatus struct {
 	SendQueueSize     int
 	Priority          int
 	RecentlySent      int64
+	SendRate          int64
 }
 
 func (c *MConnection) Status() ConnectionStatus {
@@ -734,6 +823,7 @@ func (c *MConnection) Status() ConnectionStatus {
 	status.Duration = time.Since(c.created)
 	status.SendMonitor = c.sendMonitor.Status()
 	status.RecvMonitor = c.recvMonitor.Status()
+	status.RTT = time.Duration(atomic.LoadInt64(&c.rtt))
 	status.Channels = make([]ChannelStatus, len(c.channels))
 	for i, channel := range c.channels {
 		channel := channel
@@ -743,6 +833,7 @@ func (c *MConnection) Status() ConnectionStatus {
 			SendQueueSize:     int(atomic.LoadInt32(&channel.sendQueueSize)),
 			Priority:          channel.desc.Priority,
 			RecentlySent:      atomic.LoadInt64(&channel.recentlySent),
+			SendRate:          atomic.LoadInt64(&channel.sendRate),
 		}
 	}
 	return status
@@ -782,7 +873,10 @@ type Channel struct {
 	sendQueueSize int32 // atomic.
 	recving       []byte
 	sending       []byte
-	recentlySent  int64 // exponential moving average
+	compression   tmp2p.Compression // of the message being sent
+	recentlySent  int64             // exponential moving average
+	sendRate      int64             // atomic. bytes/second, 0 if unlimited.
+	sendMonitor   *flow.Monitor
 
 	maxPacketMsgPayloadSize int
 
@@ -799,6 +893,8 @@ func newChannel(conn *MConnection, desc ChannelDescriptor) *Channel {
 		desc:                    desc,
 		sendQueue:               make(chan []byte, desc.SendQueueCapacity),
 		recving:                 make([]byte, 0, desc.RecvBufferCapacity),
+		sendRate:                conn.config.ChannelSendRates[desc.ID],
+		sendMonitor:             flow.New(0, 0),
 		maxPacketMsgPayloadSize: conn.config.MaxPacketMsgPayloadSize,
 	}
 }
@@ -853,14 +949,30 @@ func (ch *Channel) isSendPending() bool {
 			return false
 		}
 		ch.sending = <-ch.sendQueue
+		ch.compression = tmp2p.Compression_COMPRESSION_NONE
+		if ch.conn.compression != tmp2p.Compression_COMPRESSION_NONE &&
+			len(ch.sending) >= ch.conn.config.CompressionMinSize {
+			ch.sending, ch.compression = compress.Compress(ch.conn.compression, ch.sending)
+		}
 	}
 	return true
 }
 
+// Returns true if the channel exceeded its send rate, and may not send a
+// packet of the given size for now.
+// Goroutine-safe
+func (ch *Channel) isThrottled(packetSize int) bool {
+	rate := atomic.LoadInt64(&ch.sendRate)
+	if rate <= 0 {
+		return false
+	}
+	return ch.sendMonitor.Limit(packetSize, rate, false) == 0
+}
+
 // Creates a new PacketMsg to send.
 // Not goroutine-safe
 func (ch *Channel) nextPacketMsg() tmp2p.PacketMsg {
-	packet := tmp2p.PacketMsg{ChannelID: int32(ch.desc.ID)}
+	packet := tmp2p.PacketMsg{ChannelID: int32(ch.desc.ID), Compression: ch.compression}
 	maxSize := ch.maxPacketMsgPayloadSize
 	if len(ch.sending) <= maxSize {
 		packet.Data = ch.sending
@@ -884,6 +996,7 @@ func (ch *Channel) writePacketMsgTo(w protoio.Writer) (n int, err error) {
 		return 0, err
 	}
 	atomic.AddInt64(&ch.recentlySent, int64(n))
+	ch.sendMonitor.Update(n)
 	return n, nil
 }
 
@@ -896,8 +1009,18 @@ func (ch *Channel) recvPacketMsg(packet tmp2p.PacketMsg) ([]byte, error) {
 	if recvCap < recvReceived {
 		return nil, fmt.Errorf("received message exceeds available capacity: %v < %v", recvCap, recvReceived)
 	}
+	if packet.Compression != tmp2p.Compression_COMPRESSION_NONE {
+		if _, ok := ch.conn.recvCompression[packet.Compression]; !ok {
+			return nil, fmt.Errorf("received message compressed with %v, which was not negotiated", packet.Compression)
+		}
+	}
 	ch.recving = append(ch.recving, packet.Data...)
 	if packet.EOF {
+		if packet.Compression != tmp2p.Compression_COMPRESSION_NONE {
+			msgBytes, err := compress.Decompress(packet.Compression, ch.recving, recvCap)
+			ch.recving = ch.recving[:0]
+			return msgBytes, err
+		}
 		msgBytes := make([]byte, len(ch.recving))
 		copy(msgBytes, ch.recving)
 
@@ -364,6 +364,39 @@ func TestMConnectionPingPongs(t *testing.T) {
 	}
 }
 
+func TestMConnectionRTT(t *testing.T) {
+	server, client := net.Pipe()
+	defer server.Close()
+	defer client.Close()
+
+	onReceive := func(chID byte, msgBytes []byte) {}
+	onError := func(r interface{}) {}
+	mconn := createMConnectionWithCallbacks(client, onReceive, onError)
+	err := mconn.Start()
+	require.Nil(t, err)
+	defer mconn.Stop() //nolint:errcheck // ignore for tests
+
+	assert.Zero(t, mconn.Status().RTT)
+
+	const delay = 20 * time.Millisecond
+	go func() {
+		protoReader := protoio.NewDelimitedReader(server, maxPingPongPacketSize)
+		protoWriter := protoio.NewDelimitedWriter(server)
+		var pkt tmp2p.Packet
+
+		// answer the first ping after the delay
+		if _, err := protoReader.ReadMsg(&pkt); err != nil {
+			return
+		}
+		time.Sleep(delay)
+		_, _ = protoWriter.WriteMsg(mustWrapPacket(&tmp2p.PacketPong{}))
+	}()
+
+	assert.Eventually(t, func() bool {
+		return mconn.Status().RTT >= delay
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
 func TestMConnectionStopsAndReturnsError(t *testing.T) {
 	server, client := NetPipe()
 	defer server.Close()
@@ -631,3 +664,80 @@ func stopAll(t *testing.T, stoppers ...stopper) func() {
 		}
 	}
 }
+
+func TestMConnectionChannelSendRate(t *testing.T) {
+	server, client := NetPipe()
+	defer server.Close()
+	defer client.Close()
+
+	cfg := DefaultMConnConfig()
+	cfg.ChannelSendRates = map[byte]int64{0x01: 1000}
+	chDescs := []*ChannelDescriptor{
+		{ID: 0x01, Priority: 1, SendQueueCapacity: 1},
+		{ID: 0x02, Priority: 1, SendQueueCapacity: 1},
+	}
+	mconn := NewMConnectionWithConfig(client, chDescs, func(byte, []byte) {}, func(interface{}) {}, cfg)
+	mconn.SetLogger(log.TestingLogger())
+
+	limited, unlimited := mconn.channelsIdx[0x01], mconn.channelsIdx[0x02]
+	assert.EqualValues(t, 1000, limited.sendRate)
+	assert.Zero(t, unlimited.sendRate)
+
+	// Exhaust the rate of the limited channel.
+	limited.sendMonitor.Update(2000)
+	require.True(t, limited.trySendBytes([]byte("votes")))
+	require.True(t, unlimited.trySendBytes([]byte("blocks")))
+
+	// The limited channel is skipped, even though it sent less recently.
+	unlimited.recentlySent = 1000
+	ch, throttled := selectChannelToGossipOn(mconn.channels, mconn._maxPacketMsgSize)
+	assert.Equal(t, unlimited, ch)
+	assert.True(t, throttled)
+
+	// With nothing else to send, nothing is selected until the rate allows it.
+	unlimited.sending = nil
+	ch, throttled = selectChannelToGossipOn(mconn.channels, mconn._maxPacketMsgSize)
+	assert.Nil(t, ch)
+	assert.True(t, throttled)
+
+	// Removing the limit lets the channel send right away.
+	require.True(t, mconn.SetChannelSendRate(0x01, 0))
+	assert.False(t, mconn.SetChannelSendRate(0x05, 0))
+	ch, throttled = selectChannelToGossipOn(mconn.channels, mconn._maxPacketMsgSize)
+	assert.Equal(t, limited, ch)
+	assert.False(t, throttled)
+	assert.Zero(t, mconn.Status().Channels[0].SendRate)
+}
+
+func TestMConnectionThrottledChannelEventuallySends(t *testing.T) {
+	server, client := NetPipe()
+	defer server.Close()
+	defer client.Close()
+
+	receivedCh := make(chan []byte, 3)
+	mconn1 := createMConnectionWithCallbacks(client, func(_ byte, msgBytes []byte) {
+		receivedCh <- msgBytes
+	}, func(interface{}) {})
+	err := mconn1.Start()
+	require.Nil(t, err)
+	defer mconn1.Stop() //nolint:errcheck // ignore for tests
+
+	mconn2 := createTestMConnection(server)
+	require.True(t, mconn2.SetChannelSendRate(0x01, 100))
+	err = mconn2.Start()
+	require.Nil(t, err)
+	defer mconn2.Stop() //nolint:errcheck // ignore for tests
+
+	msgs := [][]byte{[]byte("Wolverine"), []byte("Storm"), []byte("Rogue")}
+	for _, msg := range msgs {
+		assert.True(t, mconn2.Send(0x01, msg))
+	}
+	for _, msg := range msgs {
+		select {
+		case receivedBytes := <-receivedCh:
+			assert.Equal(t, msg, receivedBytes)
+		case <-time.After(3 * time.Second):
+			t.Fatalf("Did not receive %s message in 3s", msg)
+		}
+	}
+}
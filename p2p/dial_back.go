@@ -0,0 +1,108 @@
+package p2p
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cometbft/cometbft/crypto/ed25519"
+)
+
+const (
+	// maximum number of addresses verified at the same time, and in a burst
+	maxConcurrentDialBacks = 10
+	// interval at which a verification is allowed beyond the burst, limiting
+	// them to 10 per second
+	dialBackInterval = 100 * time.Millisecond
+	// maximum number of addresses reported by a single peer verified at the
+	// same time
+	maxDialBacksPerSource = 2
+)
+
+// dialBack dials addr and completes a secret connection handshake with it,
+// failing unless the remote node authenticates as addr.ID. The handshake uses
+// an ephemeral key, and the connection is closed right after it, before any
+// node info is exchanged.
+func dialBack(addr *NetAddress, timeout time.Duration) error {
+	c, err := addr.DialTimeout(timeout)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	sc, err := upgradeSecretConn(c, timeout, ed25519.GenPrivKey())
+	if err != nil {
+		return fmt.Errorf("secret connection handshake failed: %w", err)
+	}
+	if id := PubKeyToID(sc.RemotePubKey()); id != addr.ID {
+		return fmt.Errorf("node at %v authenticated as %v", addr, id)
+	}
+	return nil
+}
+
+// dialBackLimiter limits the number and rate of the dial-backs verifying
+// addresses, so that peers can't make us dial arbitrary addresses at will. The
+// rate is limited with a token bucket of maxConcurrentDialBacks tokens,
+// refilled every dialBackInterval. If perSource is positive, the dial-backs of
+// the addresses reported by each peer are also limited to perSource at the
+// same time, so that a single peer can't use up all of them.
+type dialBackLimiter struct {
+	slots     chan struct{}
+	perSource int
+
+	mtx      sync.Mutex
+	tokens   int
+	refilled time.Time  // last time tokens were added
+	sources  map[ID]int // dial-backs in progress, by source peer
+}
+
+func newDialBackLimiter(perSource int) *dialBackLimiter {
+	return &dialBackLimiter{
+		slots:     make(chan struct{}, maxConcurrentDialBacks),
+		perSource: perSource,
+		tokens:    maxConcurrentDialBacks,
+		refilled:  time.Now(),
+		sources:   make(map[ID]int),
+	}
+}
+
+// acquire returns true if a dial-back of an address reported by src may start
+// now, in which case release must be called with src once it is done.
+func (l *dialBackLimiter) acquire(src ID) bool {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if l.perSource > 0 && l.sources[src] >= l.perSource {
+		return false
+	}
+
+	now := time.Now()
+	if n := int(now.Sub(l.refilled) / dialBackInterval); n > 0 {
+		l.tokens += n
+		l.refilled = l.refilled.Add(time.Duration(n) * dialBackInterval)
+		if l.tokens >= maxConcurrentDialBacks {
+			l.tokens = maxConcurrentDialBacks
+			l.refilled = now
+		}
+	}
+	if l.tokens == 0 {
+		return false
+	}
+	select {
+	case l.slots <- struct{}{}:
+	default:
+		return false
+	}
+	l.tokens--
+	l.sources[src]++
+	return true
+}
+
+func (l *dialBackLimiter) release(src ID) {
+	<-l.slots
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	if l.sources[src]--; l.sources[src] <= 0 {
+		delete(l.sources, src)
+	}
+}
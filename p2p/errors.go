@@ -118,6 +118,215 @@ func (e ErrRejected) IsNodeInfoInvalid() bool { return e.isNodeInfoInvalid }
 // IsSelf when Peer is our own node.
 func (e ErrRejected) IsSelf() bool { return e.isSelf }
 
+// IsMalformedHandshake when the handshake of the Peer was malformed.
+func (e ErrRejected) IsMalformedHandshake() bool { return e.malformedHandshake }
+
+// Err returns the underlying error, if any.
+func (e ErrRejected) Err() error { return e.err }
+
+// ID returns the ID of the rejected Peer, if known.
+func (e ErrRejected) ID() ID { return e.id }
+
+// LocalNodeID returns our node ID, if it was recorded.
+func (e ErrRejected) LocalNodeID() string { return e.localNodeID }
+
+// RemoteNodeID returns the node ID claimed by the Peer, if known.
+func (e ErrRejected) RemoteNodeID() string { return e.remoteNodeID }
+
+// LocalAddr returns the local address of the rejected connection, if known.
+func (e ErrRejected) LocalAddr() string {
+	if e.localAddr == "" && e.conn != nil {
+		return e.conn.LocalAddr().String()
+	}
+	return e.localAddr
+}
+
+// RemoteAddr returns the remote address of the rejected connection, if known.
+func (e ErrRejected) RemoteAddr() string {
+	if e.remoteAddr == "" && e.conn != nil {
+		return e.conn.RemoteAddr().String()
+	}
+	return e.remoteAddr
+}
+
+// HandshakeStage returns the handshake stage at which the Peer was rejected,
+// if any.
+func (e ErrRejected) HandshakeStage() string { return e.handshakeStage }
+
+// TraceID returns the trace ID of the connection upgrade, if any.
+func (e ErrRejected) TraceID() string { return e.traceID }
+
+// ChainID returns our chain ID, if the rejection involved chain IDs.
+func (e ErrRejected) ChainID() string { return e.chainID }
+
+// PeerChainID returns the chain ID reported by the Peer, if the rejection
+// involved chain IDs.
+func (e ErrRejected) PeerChainID() string { return e.peerChainID }
+
+// Reasons of a rejection, as returned by ErrRejected.Reason.
+const (
+	RejectReasonAuthFailure        = "auth_failure"
+	RejectReasonChainIDMismatch    = "chain_id_mismatch"
+	RejectReasonDuplicate          = "duplicate"
+	RejectReasonFiltered           = "filtered"
+	RejectReasonIncompatible       = "incompatible"
+	RejectReasonMalformedHandshake = "malformed_handshake"
+	RejectReasonNodeInfoInvalid    = "node_info_invalid"
+	RejectReasonSelf               = "self"
+	RejectReasonOther              = "other"
+)
+
+// Reason returns why the Peer was rejected, as one of the RejectReason values.
+// Incompatible peers on another chain are reported as RejectReasonChainIDMismatch.
+func (e ErrRejected) Reason() string {
+	switch {
+	case e.isAuthFailure:
+		return RejectReasonAuthFailure
+	case e.isDuplicate:
+		return RejectReasonDuplicate
+	case e.isFiltered:
+		return RejectReasonFiltered
+	case e.isIncompatible:
+		if e.chainID != "" && e.peerChainID != "" && e.chainID != e.peerChainID {
+			return RejectReasonChainIDMismatch
+		}
+		return RejectReasonIncompatible
+	case e.isNodeInfoInvalid:
+		return RejectReasonNodeInfoInvalid
+	case e.isSelf:
+		return RejectReasonSelf
+	case e.malformedHandshake:
+		return RejectReasonMalformedHandshake
+	default:
+		return RejectReasonOther
+	}
+}
+
+// ErrRejectedBuilder constructs an ErrRejected, making sure that the reason
+// flag and its related fields are always set together.
+type ErrRejectedBuilder struct {
+	e ErrRejected
+}
+
+// NewErrRejectedBuilder returns an empty ErrRejectedBuilder.
+func NewErrRejectedBuilder() *ErrRejectedBuilder {
+	return &ErrRejectedBuilder{}
+}
+
+// AuthFailure marks the rejection as an authentication failure caused by err.
+func (b *ErrRejectedBuilder) AuthFailure(err error) *ErrRejectedBuilder {
+	b.e.err = err
+	b.e.isAuthFailure = true
+	return b
+}
+
+// Duplicate marks the rejection as a duplicate connection.
+func (b *ErrRejectedBuilder) Duplicate(conn net.Conn) *ErrRejectedBuilder {
+	b.e.conn = conn
+	b.e.isDuplicate = true
+	return b
+}
+
+// DuplicateID marks the rejection as a duplicate peer ID.
+func (b *ErrRejectedBuilder) DuplicateID(id ID) *ErrRejectedBuilder {
+	b.e.id = id
+	b.e.isDuplicate = true
+	return b
+}
+
+// Filtered marks the rejection as caused by a filter returning err.
+func (b *ErrRejectedBuilder) Filtered(err error) *ErrRejectedBuilder {
+	b.e.err = err
+	b.e.isFiltered = true
+	return b
+}
+
+// Incompatible marks the rejection as caused by an incompatible NodeInfo.
+func (b *ErrRejectedBuilder) Incompatible(err error) *ErrRejectedBuilder {
+	b.e.err = err
+	b.e.isIncompatible = true
+	return b
+}
+
+// NodeInfoInvalid marks the rejection as caused by an invalid NodeInfo.
+func (b *ErrRejectedBuilder) NodeInfoInvalid(err error) *ErrRejectedBuilder {
+	b.e.err = err
+	b.e.isNodeInfoInvalid = true
+	return b
+}
+
+// Self marks the rejection as a connection to our own node.
+func (b *ErrRejectedBuilder) Self(addr NetAddress) *ErrRejectedBuilder {
+	b.e.addr = addr
+	b.e.id = addr.ID
+	b.e.isSelf = true
+	return b
+}
+
+// WithErr sets the underlying error.
+func (b *ErrRejectedBuilder) WithErr(err error) *ErrRejectedBuilder {
+	b.e.err = err
+	return b
+}
+
+// WithConn sets the rejected connection.
+func (b *ErrRejectedBuilder) WithConn(conn net.Conn) *ErrRejectedBuilder {
+	b.e.conn = conn
+	return b
+}
+
+// WithID sets the ID of the rejected peer.
+func (b *ErrRejectedBuilder) WithID(id ID) *ErrRejectedBuilder {
+	b.e.id = id
+	return b
+}
+
+// WithConnAddrs sets the connection together with its local and remote
+// addresses.
+func (b *ErrRejectedBuilder) WithConnAddrs(conn net.Conn) *ErrRejectedBuilder {
+	b.e.conn = conn
+	b.e.localAddr = conn.LocalAddr().String()
+	b.e.remoteAddr = conn.RemoteAddr().String()
+	return b
+}
+
+// WithNodeIDs sets the local and remote node IDs.
+func (b *ErrRejectedBuilder) WithNodeIDs(local, remote string) *ErrRejectedBuilder {
+	b.e.localNodeID = local
+	b.e.remoteNodeID = remote
+	return b
+}
+
+// WithHandshakeStage sets the handshake stage at which the peer was rejected.
+func (b *ErrRejectedBuilder) WithHandshakeStage(s string) *ErrRejectedBuilder {
+	b.e.handshakeStage = s
+	return b
+}
+
+// WithTraceID sets the trace ID of the connection upgrade.
+func (b *ErrRejectedBuilder) WithTraceID(id string) *ErrRejectedBuilder {
+	b.e.traceID = id
+	return b
+}
+
+// WithChainIDs sets our chain ID and the chain ID reported by the peer.
+func (b *ErrRejectedBuilder) WithChainIDs(chainID, peerChainID string) *ErrRejectedBuilder {
+	b.e.chainID = chainID
+	b.e.peerChainID = peerChainID
+	return b
+}
+
+// MalformedHandshake marks the handshake as malformed.
+func (b *ErrRejectedBuilder) MalformedHandshake() *ErrRejectedBuilder {
+	b.e.malformedHandshake = true
+	return b
+}
+
+// Build returns the constructed ErrRejected.
+func (b *ErrRejectedBuilder) Build() ErrRejected {
+	return b.e
+}
+
 // ErrSwitchDuplicatePeerID to be raised when a peer is connecting with a known
 // ID.
 type ErrSwitchDuplicatePeerID struct {
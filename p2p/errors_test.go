@@ -0,0 +1,152 @@
+package p2p
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrRejectedBuilder(t *testing.T) {
+	c1, c2 := net.Pipe()
+	defer c1.Close()
+	defer c2.Close()
+
+	errTest := errors.New("test")
+	id := ID("0123456789abcdef0123456789abcdef01234567")
+	addr := NetAddress{ID: id, IP: net.IPv4(127, 0, 0, 1), Port: 26656}
+
+	testCases := []struct {
+		name     string
+		built    ErrRejected
+		expected ErrRejected
+	}{
+		{
+			"duplicate conn",
+			NewErrRejectedBuilder().Duplicate(c1).Build(),
+			ErrRejected{conn: c1, isDuplicate: true},
+		},
+		{
+			"duplicate id",
+			NewErrRejectedBuilder().DuplicateID(id).Build(),
+			ErrRejected{id: id, isDuplicate: true},
+		},
+		{
+			"filtered",
+			NewErrRejectedBuilder().WithID(id).Filtered(errTest).Build(),
+			ErrRejected{id: id, err: errTest, isFiltered: true},
+		},
+		{
+			"auth failure",
+			NewErrRejectedBuilder().
+				WithConnAddrs(c1).
+				AuthFailure(errTest).
+				WithNodeIDs("local", "remote").
+				WithHandshakeStage("secret-conn-start").
+				WithTraceID("trace").
+				Build(),
+			ErrRejected{
+				conn:           c1,
+				err:            errTest,
+				isAuthFailure:  true,
+				localNodeID:    "local",
+				remoteNodeID:   "remote",
+				localAddr:      c1.LocalAddr().String(),
+				remoteAddr:     c1.RemoteAddr().String(),
+				handshakeStage: "secret-conn-start",
+				traceID:        "trace",
+			},
+		},
+		{
+			"self",
+			NewErrRejectedBuilder().Self(addr).Build(),
+			ErrRejected{addr: addr, id: id, isSelf: true},
+		},
+		{
+			"incompatible",
+			NewErrRejectedBuilder().
+				WithID(id).
+				Incompatible(errTest).
+				WithChainIDs("chain-a", "chain-b").
+				MalformedHandshake().
+				Build(),
+			ErrRejected{
+				id:                 id,
+				err:                errTest,
+				isIncompatible:     true,
+				chainID:            "chain-a",
+				peerChainID:        "chain-b",
+				malformedHandshake: true,
+			},
+		},
+		{
+			"node info invalid",
+			NewErrRejectedBuilder().WithConn(c1).NodeInfoInvalid(errTest).Build(),
+			ErrRejected{conn: c1, err: errTest, isNodeInfoInvalid: true},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.built)
+			assert.Equal(t, tc.expected.Error(), tc.built.Error())
+		})
+	}
+}
+
+func TestErrRejectedReason(t *testing.T) {
+	errTest := errors.New("test")
+
+	testCases := []struct {
+		err    ErrRejected
+		reason string
+	}{
+		{NewErrRejectedBuilder().AuthFailure(errTest).Build(), RejectReasonAuthFailure},
+		{NewErrRejectedBuilder().DuplicateID("id").Build(), RejectReasonDuplicate},
+		{NewErrRejectedBuilder().Filtered(errTest).Build(), RejectReasonFiltered},
+		{NewErrRejectedBuilder().Incompatible(errTest).Build(), RejectReasonIncompatible},
+		{NewErrRejectedBuilder().Incompatible(errTest).WithChainIDs("a", "a").Build(), RejectReasonIncompatible},
+		{NewErrRejectedBuilder().Incompatible(errTest).WithChainIDs("a", "b").Build(), RejectReasonChainIDMismatch},
+		{NewErrRejectedBuilder().NodeInfoInvalid(errTest).Build(), RejectReasonNodeInfoInvalid},
+		{NewErrRejectedBuilder().Self(NetAddress{ID: "id"}).Build(), RejectReasonSelf},
+		{NewErrRejectedBuilder().WithErr(errTest).MalformedHandshake().Build(), RejectReasonMalformedHandshake},
+		{NewErrRejectedBuilder().WithErr(errTest).Build(), RejectReasonOther},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.reason, tc.err.Reason(), tc.err.Error())
+	}
+}
+
+func TestErrRejectedAccessors(t *testing.T) {
+	c1, c2 := net.Pipe()
+	defer c1.Close()
+	defer c2.Close()
+
+	errTest := errors.New("test")
+	e := NewErrRejectedBuilder().
+		WithConnAddrs(c1).
+		WithID("id").
+		AuthFailure(errTest).
+		WithNodeIDs("local", "remote").
+		WithHandshakeStage("secret-conn-auth").
+		WithTraceID("trace").
+		WithChainIDs("chain-a", "chain-b").
+		Build()
+
+	assert.Equal(t, errTest, e.Err())
+	assert.Equal(t, ID("id"), e.ID())
+	assert.Equal(t, "local", e.LocalNodeID())
+	assert.Equal(t, "remote", e.RemoteNodeID())
+	assert.Equal(t, c1.LocalAddr().String(), e.LocalAddr())
+	assert.Equal(t, c1.RemoteAddr().String(), e.RemoteAddr())
+	assert.Equal(t, "secret-conn-auth", e.HandshakeStage())
+	assert.Equal(t, "trace", e.TraceID())
+	assert.Equal(t, "chain-a", e.ChainID())
+	assert.Equal(t, "chain-b", e.PeerChainID())
+	assert.False(t, e.IsMalformedHandshake())
+
+	// The addresses of the connection are used when they were not recorded.
+	e = NewErrRejectedBuilder().Duplicate(c1).Build()
+	assert.Equal(t, c1.RemoteAddr().String(), e.RemoteAddr())
+}
@@ -0,0 +1,61 @@
+package p2p
+
+import (
+	"net"
+	"sync"
+)
+
+// minExternalIPVotes is the number of outbound peers which must observe the
+// same IP for it to be taken as our external IP.
+const minExternalIPVotes = 3
+
+// externalIPDetector infers the external IP of a node behind a NAT from the
+// IPs its peers observe it connecting from, similarly to a STUN client. Only
+// the peers we dialed vote, since inbound peers could be many and chosen by an
+// attacker, and only for routable IPs. The IP observed by the most peers is
+// detected once at least minExternalIPVotes of them agree on it.
+type externalIPDetector struct {
+	mtx      sync.Mutex
+	votes    map[ID]string // observed IP by peer
+	detected string
+}
+
+func newExternalIPDetector() *externalIPDetector {
+	return &externalIPDetector{votes: make(map[ID]string)}
+}
+
+// observe records that the peer with the given ID observed ip, and returns
+// the external IP if it just changed.
+func (d *externalIPDetector) observe(id ID, ip net.IP) (net.IP, bool) {
+	if ip == nil || !(&NetAddress{ID: id, IP: ip}).Routable() {
+		return nil, false
+	}
+
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	d.votes[id] = ip.String()
+	return d.detect()
+}
+
+// remove forgets the vote of the peer with the given ID.
+func (d *externalIPDetector) remove(id ID) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	delete(d.votes, id)
+}
+
+func (d *externalIPDetector) detect() (net.IP, bool) {
+	counts := make(map[string]int)
+	best, bestCount := "", 0
+	for _, ip := range d.votes {
+		counts[ip]++
+		if counts[ip] > bestCount {
+			best, bestCount = ip, counts[ip]
+		}
+	}
+	if bestCount < minExternalIPVotes || counts[d.detected] >= bestCount {
+		return nil, false
+	}
+	d.detected = best
+	return net.ParseIP(best), true
+}
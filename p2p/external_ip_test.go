@@ -0,0 +1,41 @@
+package p2p
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExternalIPDetector(t *testing.T) {
+	d := newExternalIPDetector()
+	ip1, ip2 := net.ParseIP("198.51.100.1"), net.ParseIP("203.0.113.1")
+	id := func(i byte) ID { return ID(fmt.Sprintf("%040x", i)) }
+
+	// Private IPs don't count.
+	for i := byte(0); i < minExternalIPVotes; i++ {
+		_, ok := d.observe(id(i), net.ParseIP("192.168.1.2"))
+		assert.False(t, ok)
+	}
+
+	for i := byte(0); i < minExternalIPVotes-1; i++ {
+		_, ok := d.observe(id(i), ip1)
+		assert.False(t, ok)
+	}
+	ip, ok := d.observe(id(minExternalIPVotes-1), ip1)
+	assert.True(t, ok)
+	assert.Equal(t, ip1.String(), ip.String())
+	_, ok = d.observe(id(minExternalIPVotes), ip1)
+	assert.False(t, ok, "already detected")
+
+	// The IP observed by most peers wins.
+	for i := byte(0); i < 2; i++ {
+		d.remove(id(i))
+	}
+	for i := byte(4); i < 4+minExternalIPVotes; i++ {
+		_, ok = d.observe(id(i), ip2)
+		assert.Equal(t, i == 4+minExternalIPVotes-1, ok)
+	}
+	assert.Equal(t, ip2.String(), d.detected)
+}
@@ -0,0 +1,196 @@
+// Package filter implements filters of the peers of a node configured with
+// files, rather than compiled into the binary.
+package filter
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/cometbft/cometbft/p2p"
+)
+
+// AccessList filters peers by IP address, with CIDR ranges, and by ID,
+// according to the lists of allowed and denied entries of a JSON file:
+//
+//	{
+//	  "allow": ["10.0.0.0/8", "2001:db8::/32", "<peer ID>"],
+//	  "deny": ["192.0.2.1", "<peer ID>"]
+//	}
+//
+// A peer is rejected if its IP or its ID is denied. If any entry is allowed, a
+// peer is also rejected unless its IP or its ID is allowed. A missing file
+// allows all peers.
+//
+// Reload reads the file again, so that the lists can be changed without
+// restarting the node.
+type AccessList struct {
+	path string
+
+	mtx   sync.RWMutex
+	allow rules
+	deny  rules
+}
+
+// NewAccessList returns the access list of the file at path.
+func NewAccessList(path string) (*AccessList, error) {
+	l := &AccessList{path: path}
+	if err := l.Reload(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+type accessListFile struct {
+	Allow []string `json:"allow"`
+	Deny  []string `json:"deny"`
+}
+
+// Reload reads the file of the access list again. The list is left unchanged
+// if the file is invalid.
+func (l *AccessList) Reload() error {
+	var f accessListFile
+	bz, err := os.ReadFile(l.path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return err
+	default:
+		if err := json.Unmarshal(bz, &f); err != nil {
+			return fmt.Errorf("invalid p2p access list %v: %w", l.path, err)
+		}
+	}
+
+	allow, err := parseRules(f.Allow)
+	if err != nil {
+		return fmt.Errorf("invalid allowed entry in p2p access list %v: %w", l.path, err)
+	}
+	deny, err := parseRules(f.Deny)
+	if err != nil {
+		return fmt.Errorf("invalid denied entry in p2p access list %v: %w", l.path, err)
+	}
+
+	l.mtx.Lock()
+	l.allow, l.deny = allow, deny
+	l.mtx.Unlock()
+	return nil
+}
+
+// FilterConn implements p2p.ConnFilterFunc. As the ID of the peer is not known
+// yet, it only rejects connections from denied IPs, and from IPs that are not
+// allowed if only IPs are.
+func (l *AccessList) FilterConn(_ p2p.ConnSet, _ net.Conn, ips []net.IP) error {
+	l.mtx.RLock()
+	defer l.mtx.RUnlock()
+
+	for _, ip := range ips {
+		if l.deny.hasIP(ip) {
+			return fmt.Errorf("ip %v is denied", ip)
+		}
+	}
+	if l.allow.empty() || len(l.allow.ids) > 0 {
+		return nil
+	}
+	for _, ip := range ips {
+		if l.allow.hasIP(ip) {
+			return nil
+		}
+	}
+	return fmt.Errorf("ips %v are not allowed", ips)
+}
+
+// FilterPeer implements p2p.PeerFilterFunc.
+func (l *AccessList) FilterPeer(_ p2p.IPeerSet, p p2p.Peer) error {
+	return l.Check(p.ID(), p.RemoteIP())
+}
+
+// Check returns an error if the peer with the given ID and IP is rejected.
+func (l *AccessList) Check(id p2p.ID, ip net.IP) error {
+	l.mtx.RLock()
+	defer l.mtx.RUnlock()
+
+	if l.deny.hasID(id) {
+		return fmt.Errorf("peer %v is denied", id)
+	}
+	if l.deny.hasIP(ip) {
+		return fmt.Errorf("ip %v is denied", ip)
+	}
+	if l.allow.empty() || l.allow.hasID(id) || l.allow.hasIP(ip) {
+		return nil
+	}
+	return fmt.Errorf("peer %v with ip %v is not allowed", id, ip)
+}
+
+// DeniedPeers returns the peers of ps that the list rejects, for instance to
+// disconnect them once the list is reloaded.
+func (l *AccessList) DeniedPeers(ps p2p.IPeerSet) []p2p.Peer {
+	var denied []p2p.Peer
+	for _, p := range ps.List() {
+		if l.Check(p.ID(), p.RemoteIP()) != nil {
+			denied = append(denied, p)
+		}
+	}
+	return denied
+}
+
+// rules are the IP ranges and IDs of the peers allowed or denied.
+type rules struct {
+	nets []*net.IPNet
+	ids  map[p2p.ID]struct{}
+}
+
+// parseRules parses entries which are CIDR ranges, IP addresses or peer IDs.
+func parseRules(entries []string) (rules, error) {
+	r := rules{ids: make(map[p2p.ID]struct{})}
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		switch {
+		case strings.Contains(entry, "/"):
+			_, ipNet, err := net.ParseCIDR(entry)
+			if err != nil {
+				return rules{}, err
+			}
+			r.nets = append(r.nets, ipNet)
+		case net.ParseIP(entry) != nil:
+			ip := net.ParseIP(entry)
+			bits := 8 * net.IPv6len
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 8*net.IPv4len
+			}
+			r.nets = append(r.nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+		default:
+			id, err := hex.DecodeString(entry)
+			if err != nil || len(id) != p2p.IDByteLength {
+				return rules{}, fmt.Errorf("%q is neither a CIDR range, an IP address nor a peer ID", entry)
+			}
+			r.ids[p2p.ID(hex.EncodeToString(id))] = struct{}{}
+		}
+	}
+	return r, nil
+}
+
+func (r rules) empty() bool {
+	return len(r.nets) == 0 && len(r.ids) == 0
+}
+
+func (r rules) hasIP(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	for _, ipNet := range r.nets {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r rules) hasID(id p2p.ID) bool {
+	_, ok := r.ids[id]
+	return ok
+}
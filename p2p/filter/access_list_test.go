@@ -0,0 +1,92 @@
+package filter
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cometbft/cometbft/p2p"
+	"github.com/cometbft/cometbft/p2p/mock"
+)
+
+func writeAccessList(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+func TestAccessList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "access_list.json")
+	id1 := p2p.ID("0123456789abcdef0123456789abcdef01234567")
+	id2 := p2p.ID("89abcdef0123456789abcdef0123456789abcdef")
+
+	// A missing file allows all peers.
+	l, err := NewAccessList(path)
+	require.NoError(t, err)
+	assert.NoError(t, l.Check(id1, net.ParseIP("192.0.2.1")))
+
+	writeAccessList(t, path, `{"deny": ["192.0.2.0/24", "2001:db8::1", "`+string(id2)+`"]}`)
+	require.NoError(t, l.Reload())
+	assert.Error(t, l.Check(id1, net.ParseIP("192.0.2.1")))
+	assert.Error(t, l.Check(id1, net.ParseIP("2001:db8::1")))
+	assert.NoError(t, l.Check(id1, net.ParseIP("2001:db8::2")))
+	assert.Error(t, l.Check(id2, net.ParseIP("198.51.100.1")))
+	assert.NoError(t, l.Check(id1, net.ParseIP("198.51.100.1")))
+
+	writeAccessList(t, path, `{"allow": ["10.0.0.0/8", "`+string(id1)+`"], "deny": ["10.0.0.1"]}`)
+	require.NoError(t, l.Reload())
+	assert.NoError(t, l.Check(id2, net.ParseIP("10.1.2.3")))
+	assert.Error(t, l.Check(id2, net.ParseIP("10.0.0.1")))
+	assert.Error(t, l.Check(id1, net.ParseIP("10.0.0.1")), "deny wins over allow")
+	assert.NoError(t, l.Check(id1, net.ParseIP("198.51.100.1")))
+	assert.Error(t, l.Check(id2, net.ParseIP("198.51.100.1")))
+
+	// Invalid files leave the list unchanged.
+	for _, content := range []string{
+		`{"allow": [`,
+		`{"allow": ["10.0.0.0/33"]}`,
+		`{"deny": ["not-an-id"]}`,
+	} {
+		writeAccessList(t, path, content)
+		assert.Error(t, l.Reload(), content)
+		assert.Error(t, l.Check(id2, net.ParseIP("198.51.100.1")))
+	}
+}
+
+func TestAccessListFilterConn(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "access_list.json")
+
+	writeAccessList(t, path, `{"allow": ["10.0.0.0/8"], "deny": ["10.0.0.1"]}`)
+	l, err := NewAccessList(path)
+	require.NoError(t, err)
+	assert.NoError(t, l.FilterConn(nil, nil, []net.IP{net.ParseIP("10.1.2.3")}))
+	assert.Error(t, l.FilterConn(nil, nil, []net.IP{net.ParseIP("10.0.0.1")}))
+	assert.Error(t, l.FilterConn(nil, nil, []net.IP{net.ParseIP("198.51.100.1")}))
+
+	// Once IDs are allowed, connections from any IP may be of allowed peers.
+	writeAccessList(t, path, `{"allow": ["10.0.0.0/8", "0123456789abcdef0123456789abcdef01234567"]}`)
+	require.NoError(t, l.Reload())
+	assert.NoError(t, l.FilterConn(nil, nil, []net.IP{net.ParseIP("198.51.100.1")}))
+}
+
+func TestAccessListDeniedPeers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "access_list.json")
+	l, err := NewAccessList(path)
+	require.NoError(t, err)
+
+	allowed := mock.NewPeer(net.ParseIP("10.0.0.1"))
+	denied := mock.NewPeer(net.ParseIP("192.0.2.1"))
+	ps := p2p.NewPeerSet()
+	require.NoError(t, ps.Add(allowed))
+	require.NoError(t, ps.Add(denied))
+	assert.Empty(t, l.DeniedPeers(ps))
+
+	writeAccessList(t, path, `{"deny": ["192.0.2.0/24"]}`)
+	require.NoError(t, l.Reload())
+	assert.Equal(t, []p2p.Peer{denied}, l.DeniedPeers(ps))
+	assert.Error(t, l.FilterPeer(ps, denied))
+	assert.NoError(t, l.FilterPeer(ps, allowed))
+}
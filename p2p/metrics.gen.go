@@ -56,6 +56,36 @@ func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
 			Name:      "message_send_bytes_total",
 			Help:      "Number of bytes of each message type sent.",
 		}, append(labels, "message_type", "chID", "peer_id")).With(labelsAndValues...),
+		MessageReceiveTotal: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "message_receive_total",
+			Help:      "Number of messages of each message type received.",
+		}, append(labels, "message_type", "chID", "peer_id")).With(labelsAndValues...),
+		MessageSendTotal: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "message_send_total",
+			Help:      "Number of messages of each message type sent.",
+		}, append(labels, "message_type", "chID", "peer_id")).With(labelsAndValues...),
+		PeerRTTSeconds: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "peer_rtt_seconds",
+			Help:      "Round-trip time of the last ping to a given peer, in seconds.",
+		}, append(labels, "peer_id")).With(labelsAndValues...),
+		PeerSendQueueSaturation: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "peer_send_queue_saturation",
+			Help:      "Fraction of the capacity of the send queue of a channel used, for a given peer. Saturated queues make sends to the peer fail or block.",
+		}, append(labels, "peer_id", "chID")).With(labelsAndValues...),
+		PeerRejections: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "peer_rejections",
+			Help:      "Number of peers rejected, by reason of the rejection.",
+		}, append(labels, "reason")).With(labelsAndValues...),
 	}
 }
 
@@ -68,5 +98,10 @@ func NopMetrics() *Metrics {
 		NumTxs:                   discard.NewGauge(),
 		MessageReceiveBytesTotal: discard.NewCounter(),
 		MessageSendBytesTotal:    discard.NewCounter(),
+		MessageReceiveTotal:      discard.NewCounter(),
+		MessageSendTotal:         discard.NewCounter(),
+		PeerRTTSeconds:           discard.NewGauge(),
+		PeerSendQueueSaturation:  discard.NewGauge(),
+		PeerRejections:           discard.NewCounter(),
 	}
 }
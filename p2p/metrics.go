@@ -40,6 +40,17 @@ type Metrics struct {
 	MessageReceiveBytesTotal metrics.Counter `metrics_labels:"message_type,chID,peer_id"`
 	// Number of bytes of each message type sent.
 	MessageSendBytesTotal metrics.Counter `metrics_labels:"message_type,chID,peer_id"`
+	// Number of messages of each message type received.
+	MessageReceiveTotal metrics.Counter `metrics_labels:"message_type,chID,peer_id"`
+	// Number of messages of each message type sent.
+	MessageSendTotal metrics.Counter `metrics_labels:"message_type,chID,peer_id"`
+	// Round-trip time of the last ping to a given peer, in seconds.
+	PeerRTTSeconds metrics.Gauge `metrics_labels:"peer_id"`
+	// Fraction of the capacity of the send queue of a channel used, for a
+	// given peer. Saturated queues make sends to the peer fail or block.
+	PeerSendQueueSaturation metrics.Gauge `metrics_labels:"peer_id,chID"`
+	// Number of peers rejected, by reason of the rejection.
+	PeerRejections metrics.Counter `metrics_labels:"reason"`
 }
 
 type metricsLabelCache struct {
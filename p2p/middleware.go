@@ -0,0 +1,54 @@
+package p2p
+
+import "fmt"
+
+// EnvelopeMiddleware intercepts the envelopes received by a reactor before
+// they reach its Receive method, e.g. to record them, or to inject faults in
+// tests. It passes the envelope on, possibly modified, by calling next, or
+// drops it by not calling next. It is called from the receive routine of the
+// peer, so it must not block.
+type EnvelopeMiddleware func(e Envelope, next func(Envelope))
+
+// interceptedReactor is a reactor whose received envelopes go through its
+// middleware first, in the order it was added.
+type interceptedReactor struct {
+	Reactor
+	middleware []EnvelopeMiddleware
+}
+
+// Receive implements Reactor.
+func (r *interceptedReactor) Receive(e Envelope) {
+	r.receive(0, e)
+}
+
+func (r *interceptedReactor) receive(i int, e Envelope) {
+	if i == len(r.middleware) {
+		r.Reactor.Receive(e)
+		return
+	}
+	r.middleware[i](e, func(e Envelope) { r.receive(i+1, e) })
+}
+
+// AddMiddleware installs mw on the envelopes received by the reactor with the
+// given name, after the middleware already installed on it.
+// NOTE: Not goroutine safe, it must be called before the switch is started.
+func (sw *Switch) AddMiddleware(reactorName string, mw EnvelopeMiddleware) error {
+	reactor, ok := sw.reactors[reactorName]
+	if !ok {
+		return fmt.Errorf("no reactor %q", reactorName)
+	}
+	chDescs := reactor.GetChannels()
+	if len(chDescs) == 0 {
+		return fmt.Errorf("reactor %q has no channels", reactorName)
+	}
+
+	intercepted, ok := sw.reactorsByCh[chDescs[0].ID].(*interceptedReactor)
+	if !ok {
+		intercepted = &interceptedReactor{Reactor: reactor}
+		for _, chDesc := range chDescs {
+			sw.reactorsByCh[chDesc.ID] = intercepted
+		}
+	}
+	intercepted.middleware = append(intercepted.middleware, mw)
+	return nil
+}
@@ -0,0 +1,75 @@
+package p2p
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	p2pproto "github.com/cometbft/cometbft/proto/tendermint/p2p"
+)
+
+func TestSwitchMiddleware(t *testing.T) {
+	var (
+		mtx   sync.Mutex
+		order []string
+	)
+	record := func(name string) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		order = append(order, name)
+	}
+	recorded := func() int {
+		mtx.Lock()
+		defer mtx.Unlock()
+		return len(order)
+	}
+	s1, s2 := MakeSwitchPair(func(i int, sw *Switch) *Switch {
+		sw = initSwitchFunc(i, sw)
+		if i == 0 {
+			return sw
+		}
+		// Drop the envelopes of the channel 0x00 and modify those of 0x01.
+		require.NoError(t, sw.AddMiddleware("foo", func(e Envelope, next func(Envelope)) {
+			record("first")
+			if e.ChannelID == 0x00 {
+				return
+			}
+			e.Message = &p2pproto.PexRequest{}
+			next(e)
+		}))
+		require.NoError(t, sw.AddMiddleware("foo", func(e Envelope, next func(Envelope)) {
+			record("second")
+			next(e)
+		}))
+		assert.Error(t, sw.AddMiddleware("baz", func(e Envelope, next func(Envelope)) {}))
+		return sw
+	})
+	t.Cleanup(func() {
+		if err := s1.Stop(); err != nil {
+			t.Error(err)
+		}
+		if err := s2.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	msg := &p2pproto.PexAddrs{Addrs: []p2pproto.NetAddress{{ID: "1"}}}
+	s1.Broadcast(Envelope{ChannelID: 0x00, Message: msg})
+	s1.Broadcast(Envelope{ChannelID: 0x01, Message: msg})
+	s1.Broadcast(Envelope{ChannelID: 0x02, Message: msg})
+
+	foo, bar := s2.Reactor("foo").(*TestReactor), s2.Reactor("bar").(*TestReactor)
+	require.Eventually(t, func() bool {
+		return recorded() == 3 && len(foo.getMsgs(0x01)) == 1 && len(bar.getMsgs(0x02)) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Empty(t, foo.getMsgs(0x00))
+	assert.Equal(t, &p2pproto.PexRequest{}, foo.getMsgs(0x01)[0].Contents)
+	assert.Equal(t, msg, bar.getMsgs(0x02)[0].Contents)
+	// The envelopes of both channels went through the first middleware.
+	mtx.Lock()
+	defer mtx.Unlock()
+	assert.ElementsMatch(t, []string{"first", "first", "second"}, order)
+}
@@ -0,0 +1,47 @@
+//go:build linux
+// +build linux
+
+package nat
+
+import (
+	"bufio"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+)
+
+// defaultGateway returns the gateway of the default IPv4 route of the host,
+// read from the routing table of Linux.
+func defaultGateway() (net.IP, error) {
+	f, err := os.Open("/proc/net/route")
+	if err != nil {
+		return nil, fmt.Errorf("can't read the routing table: %w", err)
+	}
+	defer f.Close()
+
+	// Each line lists the interface, the destination and the gateway of a
+	// route, the addresses being in hex and little-endian.
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 || fields[1] != "00000000" {
+			continue
+		}
+		gateway, err := hex.DecodeString(fields[2])
+		if err != nil || len(gateway) != net.IPv4len {
+			continue
+		}
+		ip := net.IPv4(gateway[3], gateway[2], gateway[1], gateway[0])
+		if ip.IsUnspecified() {
+			continue
+		}
+		return ip, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, errors.New("no default route")
+}
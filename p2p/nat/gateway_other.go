@@ -0,0 +1,15 @@
+//go:build !linux
+// +build !linux
+
+package nat
+
+import (
+	"errors"
+	"net"
+)
+
+// defaultGateway fails: the default gateway of the host is only read from the
+// routing table of Linux.
+func defaultGateway() (net.IP, error) {
+	return nil, errors.New("finding the default gateway is only supported on Linux")
+}
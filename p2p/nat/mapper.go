@@ -0,0 +1,138 @@
+package nat
+
+import (
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cometbft/cometbft/libs/service"
+)
+
+const (
+	discoverTimeout    = 5 * time.Second
+	mappingLifetime    = 20 * time.Minute
+	mappingDescription = "CometBFT p2p"
+
+	// renewInterval is the interval at which mappings are renewed before they
+	// expire, and at which the gateway is looked for again if none was found.
+	renewInterval = mappingLifetime / 2
+)
+
+// sharedAddressSpace is the range of the addresses of carrier-grade NATs
+// (RFC 6598).
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// PortMapper maps a TCP port of the node on its gateway, renews the mapping
+// before it expires and deletes it when stopped. The external address of the
+// mapping is passed to a callback each time it changes, e.g. to advertise it
+// to peers.
+type PortMapper struct {
+	service.BaseService
+
+	port     int
+	onMapped func(addr string)
+	discover func() (NAT, error)
+
+	mtx          sync.Mutex
+	nat          NAT
+	externalPort int
+	externalAddr string
+}
+
+// NewPortMapper returns a PortMapper mapping port on the gateway of the node
+// supporting the protocol kind, one of the config.P2PNAT values, and calling
+// onMapped with the external address (host:port) of the mapping.
+func NewPortMapper(kind string, port int, onMapped func(addr string)) *PortMapper {
+	m := &PortMapper{
+		port:     port,
+		onMapped: onMapped,
+		discover: func() (NAT, error) { return Discover(kind, discoverTimeout) },
+	}
+	m.BaseService = *service.NewBaseService(nil, "PortMapper", m)
+	return m
+}
+
+// OnStart implements service.Service by mapping the port in the background.
+func (m *PortMapper) OnStart() error {
+	go m.mapRoutine()
+	return nil
+}
+
+// OnStop implements service.Service by deleting the mapping.
+func (m *PortMapper) OnStop() {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if m.nat == nil || m.externalPort == 0 {
+		return
+	}
+	if err := m.nat.DeletePortMapping(TCP, m.port, m.externalPort); err != nil {
+		m.Logger.Error("Failed to delete port mapping", "nat", m.nat, "port", m.externalPort, "err", err)
+	}
+}
+
+func (m *PortMapper) mapRoutine() {
+	for {
+		m.mapPort()
+		select {
+		case <-time.After(renewInterval):
+		case <-m.Quit():
+			return
+		}
+	}
+}
+
+// mapPort maps the port, or renews its mapping, looking for the gateway first
+// if it isn't known yet.
+func (m *PortMapper) mapPort() {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if !m.IsRunning() {
+		return
+	}
+
+	if m.nat == nil {
+		nat, err := m.discover()
+		if err != nil {
+			m.Logger.Info("No gateway found to map the listen port on", "err", err)
+			return
+		}
+		m.nat = nat
+		m.Logger.Info("Found gateway", "nat", nat)
+	}
+
+	requested := m.externalPort
+	if requested == 0 {
+		requested = m.port
+	}
+	port, err := m.nat.AddPortMapping(TCP, m.port, requested, mappingDescription, mappingLifetime)
+	if err != nil {
+		m.Logger.Error("Failed to map the listen port", "nat", m.nat, "port", m.port, "err", err)
+		// The gateway may have changed: look for it again at the next renewal.
+		m.nat = nil
+		return
+	}
+	m.externalPort = port
+
+	ip, err := m.nat.ExternalIP()
+	if err != nil {
+		m.Logger.Error("Failed to get the external IP of the gateway", "nat", m.nat, "err", err)
+		return
+	}
+	if !isPublicIP(ip) {
+		m.Logger.Info("Gateway is behind another NAT, not advertising its address", "nat", m.nat, "ip", ip)
+		return
+	}
+	addr := net.JoinHostPort(ip.String(), strconv.Itoa(port))
+	if addr == m.externalAddr {
+		return
+	}
+	m.externalAddr = addr
+	m.Logger.Info("Mapped the listen port", "nat", m.nat, "addr", addr)
+	m.onMapped(addr)
+}
+
+// isPublicIP returns whether ip can be reached from the internet.
+func isPublicIP(ip net.IP) bool {
+	return ip.IsGlobalUnicast() && !ip.IsPrivate() && !sharedAddressSpace.Contains(ip)
+}
@@ -0,0 +1,91 @@
+package nat
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cometbft/cometbft/libs/log"
+)
+
+type mockNAT struct {
+	mtx      sync.Mutex
+	ip       net.IP
+	mappings map[int]int // internal port by external port
+}
+
+func (n *mockNAT) ExternalIP() (net.IP, error) {
+	return n.ip, nil
+}
+
+func (n *mockNAT) AddPortMapping(_ string, internalPort, externalPort int, _ string, _ time.Duration) (int, error) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	n.mappings[externalPort] = internalPort
+	return externalPort, nil
+}
+
+func (n *mockNAT) DeletePortMapping(_ string, _, externalPort int) error {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	delete(n.mappings, externalPort)
+	return nil
+}
+
+func (n *mockNAT) String() string {
+	return "mock"
+}
+
+func TestPortMapper(t *testing.T) {
+	nat := &mockNAT{ip: net.ParseIP("198.51.100.1"), mappings: make(map[int]int)}
+	mapped := make(chan string, 1)
+	m := NewPortMapper("", 26656, func(addr string) { mapped <- addr })
+	m.SetLogger(log.TestingLogger())
+	m.discover = func() (NAT, error) { return nat, nil }
+
+	require.NoError(t, m.Start())
+	select {
+	case addr := <-mapped:
+		assert.Equal(t, "198.51.100.1:26656", addr)
+	case <-time.After(5 * time.Second):
+		t.Fatal("port not mapped")
+	}
+	nat.mtx.Lock()
+	assert.Equal(t, map[int]int{26656: 26656}, nat.mappings)
+	nat.mtx.Unlock()
+
+	// Renewing the mapping doesn't call the callback again.
+	m.mapPort()
+	assert.Empty(t, mapped)
+
+	require.NoError(t, m.Stop())
+	assert.Empty(t, nat.mappings)
+}
+
+func TestPortMapperNoGateway(t *testing.T) {
+	m := NewPortMapper("", 26656, func(string) { t.Error("unexpected mapping") })
+	m.SetLogger(log.TestingLogger())
+	m.discover = func() (NAT, error) { return nil, errors.New("no gateway") }
+	require.NoError(t, m.Start())
+	m.mapPort()
+	require.NoError(t, m.Stop())
+}
+
+func TestIsPublicIP(t *testing.T) {
+	for ip, public := range map[string]bool{
+		"198.51.100.1": true,
+		"2001:db8::1":  true,
+		"192.168.1.1":  false,
+		"10.1.2.3":     false,
+		"100.64.1.1":   false,
+		"127.0.0.1":    false,
+		"0.0.0.0":      false,
+	} {
+		assert.Equal(t, public, isPublicIP(net.ParseIP(ip)), ip)
+	}
+}
@@ -0,0 +1,72 @@
+// Package nat maps ports on the gateway of a node behind a NAT, e.g. a home
+// router, with UPnP or NAT-PMP, so that peers can dial the node without ports
+// being forwarded manually.
+package nat
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/cometbft/cometbft/config"
+)
+
+// Protocols of the ports mapped.
+const (
+	TCP = "TCP"
+	UDP = "UDP"
+)
+
+// NAT is a gateway able to map ports of the hosts behind it.
+type NAT interface {
+	// ExternalIP returns the IP of the gateway on the internet.
+	ExternalIP() (net.IP, error)
+
+	// AddPortMapping maps externalPort of the gateway to internalPort of the
+	// host for lifetime, and returns the external port actually mapped,
+	// which the gateway may choose differently.
+	AddPortMapping(protocol string, internalPort, externalPort int, description string,
+		lifetime time.Duration) (int, error)
+
+	// DeletePortMapping deletes a mapping added with AddPortMapping.
+	DeletePortMapping(protocol string, internalPort, externalPort int) error
+
+	String() string
+}
+
+// Discover returns the gateway of the host supporting the protocol kind, one
+// of the config.P2PNAT values. With config.P2PNATAny, UPnP and NAT-PMP
+// gateways are looked for concurrently, and the first one found is returned.
+func Discover(kind string, timeout time.Duration) (NAT, error) {
+	switch kind {
+	case config.P2PNATUPnP:
+		return DiscoverUPnP(timeout)
+	case config.P2PNATNATPMP:
+		return DiscoverNATPMP(timeout)
+	case config.P2PNATAny:
+	default:
+		return nil, fmt.Errorf("unsupported nat %q", kind)
+	}
+
+	type result struct {
+		nat NAT
+		err error
+	}
+	results := make(chan result, 2)
+	for _, discover := range []func(time.Duration) (NAT, error){DiscoverUPnP, DiscoverNATPMP} {
+		go func(discover func(time.Duration) (NAT, error)) {
+			nat, err := discover(timeout)
+			results <- result{nat, err}
+		}(discover)
+	}
+	var errs []error
+	for i := 0; i < cap(results); i++ {
+		r := <-results
+		if r.err == nil {
+			return r.nat, nil
+		}
+		errs = append(errs, r.err)
+	}
+	return nil, errors.Join(errs...)
+}
@@ -0,0 +1,142 @@
+package nat
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+const (
+	natpmpPort = 5351
+
+	// natpmpInitialTimeout is the timeout of the first attempt of a request,
+	// doubled at each retry as specified by RFC 6886.
+	natpmpInitialTimeout = 250 * time.Millisecond
+
+	natpmpOpExternalIP = 0
+	natpmpOpMapUDP     = 1
+	natpmpOpMapTCP     = 2
+)
+
+// natpmp is a gateway supporting NAT-PMP (RFC 6886).
+type natpmp struct {
+	gateway *net.UDPAddr
+	timeout time.Duration
+}
+
+var _ NAT = (*natpmp)(nil)
+
+// DiscoverNATPMP checks whether the default gateway of the host supports
+// NAT-PMP, waiting for up to timeout for it to answer. The default gateway is
+// only found on Linux (see defaultGateway): elsewhere, NAT-PMP is never
+// discovered.
+func DiscoverNATPMP(timeout time.Duration) (NAT, error) {
+	gateway, err := defaultGateway()
+	if err != nil {
+		return nil, fmt.Errorf("no NAT-PMP gateway found: %w", err)
+	}
+	n := newNATPMP(&net.UDPAddr{IP: gateway, Port: natpmpPort}, timeout)
+	if _, err := n.ExternalIP(); err != nil {
+		return nil, fmt.Errorf("no NAT-PMP gateway found: %w", err)
+	}
+	return n, nil
+}
+
+func newNATPMP(gateway *net.UDPAddr, timeout time.Duration) *natpmp {
+	return &natpmp{gateway: gateway, timeout: timeout}
+}
+
+// request sends msg to the gateway, retrying until the timeout of the gateway
+// expires, and returns the response, of size respSize.
+func (n *natpmp) request(msg []byte, respSize int) ([]byte, error) {
+	conn, err := net.DialUDP("udp4", nil, n.gateway)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(n.timeout)
+	resp := make([]byte, 16)
+	for wait := natpmpInitialTimeout; time.Now().Before(deadline); wait *= 2 {
+		if _, err := conn.Write(msg); err != nil {
+			return nil, err
+		}
+		readDeadline := time.Now().Add(wait)
+		if readDeadline.After(deadline) {
+			readDeadline = deadline
+		}
+		if err := conn.SetReadDeadline(readDeadline); err != nil {
+			return nil, err
+		}
+		for {
+			nRead, err := conn.Read(resp)
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			// Responses have the version 0 and the opcode of the request + 128.
+			if nRead < respSize || resp[0] != 0 || resp[1] != msg[1]+128 {
+				continue
+			}
+			if code := binary.BigEndian.Uint16(resp[2:4]); code != 0 {
+				return nil, fmt.Errorf("NAT-PMP gateway returned result code %d", code)
+			}
+			return resp[:respSize], nil
+		}
+	}
+	return nil, fmt.Errorf("no NAT-PMP response from %v", n.gateway)
+}
+
+func (n *natpmp) ExternalIP() (net.IP, error) {
+	resp, err := n.request([]byte{0, natpmpOpExternalIP}, 12)
+	if err != nil {
+		return nil, err
+	}
+	return net.IPv4(resp[8], resp[9], resp[10], resp[11]), nil
+}
+
+func (n *natpmp) AddPortMapping(
+	protocol string,
+	internalPort, externalPort int,
+	_ string,
+	lifetime time.Duration,
+) (int, error) {
+	return n.mapPort(protocol, internalPort, externalPort, lifetime)
+}
+
+// DeletePortMapping deletes the mapping of internalPort, requesting a mapping
+// with a null lifetime and external port as specified by RFC 6886.
+func (n *natpmp) DeletePortMapping(protocol string, internalPort, _ int) error {
+	_, err := n.mapPort(protocol, internalPort, 0, 0)
+	return err
+}
+
+func (n *natpmp) mapPort(protocol string, internalPort, externalPort int, lifetime time.Duration) (int, error) {
+	msg := make([]byte, 12)
+	switch protocol {
+	case UDP:
+		msg[1] = natpmpOpMapUDP
+	case TCP:
+		msg[1] = natpmpOpMapTCP
+	default:
+		return 0, fmt.Errorf("unsupported protocol %q", protocol)
+	}
+	binary.BigEndian.PutUint16(msg[4:6], uint16(internalPort))
+	binary.BigEndian.PutUint16(msg[6:8], uint16(externalPort))
+	binary.BigEndian.PutUint32(msg[8:12], uint32(lifetime/time.Second))
+
+	resp, err := n.request(msg, 16)
+	if err != nil {
+		return 0, err
+	}
+	return int(binary.BigEndian.Uint16(resp[10:12])), nil
+}
+
+func (n *natpmp) String() string {
+	return fmt.Sprintf("NAT-PMP(%v)", n.gateway.IP)
+}
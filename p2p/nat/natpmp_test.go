@@ -0,0 +1,76 @@
+package nat
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// serveNATPMP answers NAT-PMP requests on a local port, as a gateway with the
+// external IP 198.51.100.1 mapping ports to the internal port + 1000.
+func serveNATPMP(t *testing.T) *net.UDPAddr {
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 16)
+		for {
+			n, addr, err := conn.ReadFromUDP(buf)
+			if err != nil {
+				return
+			}
+			var resp []byte
+			switch {
+			case n == 2 && buf[1] == natpmpOpExternalIP:
+				resp = []byte{0, 128, 0, 0, 0, 0, 0, 1, 198, 51, 100, 1}
+			case n == 12 && (buf[1] == natpmpOpMapTCP || buf[1] == natpmpOpMapUDP):
+				resp = make([]byte, 16)
+				resp[1] = buf[1] + 128
+				copy(resp[8:10], buf[4:6])
+				internal := binary.BigEndian.Uint16(buf[4:6])
+				if binary.BigEndian.Uint32(buf[8:12]) != 0 {
+					binary.BigEndian.PutUint16(resp[10:12], internal+1000)
+				}
+				copy(resp[12:16], buf[8:12])
+			default:
+				// Unsupported opcode.
+				resp = []byte{0, buf[1] + 128, 0, 5, 0, 0, 0, 1}
+			}
+			_, _ = conn.WriteToUDP(resp, addr)
+		}
+	}()
+
+	return conn.LocalAddr().(*net.UDPAddr)
+}
+
+func TestNATPMP(t *testing.T) {
+	n := newNATPMP(serveNATPMP(t), time.Second)
+
+	ip, err := n.ExternalIP()
+	require.NoError(t, err)
+	assert.Equal(t, "198.51.100.1", ip.String())
+
+	port, err := n.AddPortMapping(TCP, 26656, 26656, mappingDescription, mappingLifetime)
+	require.NoError(t, err)
+	assert.Equal(t, 27656, port)
+	assert.NoError(t, n.DeletePortMapping(TCP, 26656, port))
+
+	_, err = n.AddPortMapping("SCTP", 26656, 26656, mappingDescription, mappingLifetime)
+	assert.Error(t, err)
+}
+
+func TestNATPMPTimeout(t *testing.T) {
+	// Nothing answers on this port.
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	n := newNATPMP(conn.LocalAddr().(*net.UDPAddr), 300*time.Millisecond)
+	_, err = n.ExternalIP()
+	assert.Error(t, err)
+}
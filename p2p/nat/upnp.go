@@ -0,0 +1,294 @@
+package nat
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ssdpAddr  = "239.255.255.250:1900"
+	igdDevice = "urn:schemas-upnp-org:device:InternetGatewayDevice:1"
+
+	// maxDescriptionSize limits the size of the device descriptions and of the
+	// SOAP responses read from gateways.
+	maxDescriptionSize = 1 << 20
+
+	// errOnlyPermanentLeases is the UPnP error returned by gateways which
+	// don't support mappings expiring.
+	errOnlyPermanentLeases = 725
+)
+
+// wanServices are the types of the UPnP services of gateways mapping ports.
+var wanServices = []string{
+	"urn:schemas-upnp-org:service:WANIPConnection:1",
+	"urn:schemas-upnp-org:service:WANIPConnection:2",
+	"urn:schemas-upnp-org:service:WANPPPConnection:1",
+}
+
+// upnp is a gateway supporting the WANIPConnection or WANPPPConnection
+// service of the UPnP Internet Gateway Device protocol.
+type upnp struct {
+	serviceURL  string
+	serviceType string
+	localIP     net.IP // of the host, on the network of the gateway
+	client      *http.Client
+}
+
+var _ NAT = (*upnp)(nil)
+
+// DiscoverUPnP looks for a UPnP Internet gateway on the local network, with
+// an SSDP search, for up to timeout.
+func DiscoverUPnP(timeout time.Duration) (NAT, error) {
+	conn, err := net.ListenPacket("udp4", ":0")
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	addr, err := net.ResolveUDPAddr("udp4", ssdpAddr)
+	if err != nil {
+		return nil, err
+	}
+	search := "M-SEARCH * HTTP/1.1\r\n" +
+		"HOST: " + ssdpAddr + "\r\n" +
+		"ST: " + igdDevice + "\r\n" +
+		"MAN: \"ssdp:discover\"\r\n" +
+		"MX: 2\r\n\r\n"
+	if _, err := conn.WriteTo([]byte(search), addr); err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(timeout)
+	if err := conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+	var lastErr error
+	buf := make([]byte, 1536)
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			if lastErr != nil {
+				err = lastErr
+			}
+			return nil, fmt.Errorf("no UPnP gateway found: %w", err)
+		}
+		resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(buf[:n])), nil)
+		if err != nil {
+			continue
+		}
+		resp.Body.Close()
+		location := resp.Header.Get("Location")
+		if !strings.Contains(resp.Header.Get("St"), "InternetGatewayDevice") || location == "" {
+			continue
+		}
+		nat, err := newUPnP(location, time.Until(deadline))
+		if err != nil {
+			lastErr = fmt.Errorf("%v: %w", location, err)
+			continue
+		}
+		return nat, nil
+	}
+}
+
+type upnpDevice struct {
+	Services []upnpService `xml:"serviceList>service"`
+	Devices  []upnpDevice  `xml:"deviceList>device"`
+}
+
+type upnpService struct {
+	ServiceType string `xml:"serviceType"`
+	ControlURL  string `xml:"controlURL"`
+}
+
+// wanService returns the service of d or of its embedded devices mapping
+// ports, if any.
+func (d upnpDevice) wanService() (upnpService, bool) {
+	for _, svc := range d.Services {
+		for _, serviceType := range wanServices {
+			if svc.ServiceType == serviceType {
+				return svc, true
+			}
+		}
+	}
+	for _, device := range d.Devices {
+		if svc, ok := device.wanService(); ok {
+			return svc, true
+		}
+	}
+	return upnpService{}, false
+}
+
+// newUPnP returns the gateway with the device description at location.
+func newUPnP(location string, timeout time.Duration) (*upnp, error) {
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Get(location)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("device description: %v", resp.Status)
+	}
+
+	var root struct {
+		URLBase string     `xml:"URLBase"`
+		Device  upnpDevice `xml:"device"`
+	}
+	if err := xml.NewDecoder(io.LimitReader(resp.Body, maxDescriptionSize)).Decode(&root); err != nil {
+		return nil, fmt.Errorf("invalid device description: %w", err)
+	}
+	svc, ok := root.Device.wanService()
+	if !ok {
+		return nil, errors.New("gateway has no WANIPConnection or WANPPPConnection service")
+	}
+
+	base := location
+	if root.URLBase != "" {
+		base = root.URLBase
+	}
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return nil, err
+	}
+	controlURL, err := url.Parse(svc.ControlURL)
+	if err != nil {
+		return nil, err
+	}
+	serviceURL := baseURL.ResolveReference(controlURL)
+
+	// The address of the host on the network of the gateway is the one the
+	// gateway is reached from.
+	conn, err := net.Dial("udp4", net.JoinHostPort(serviceURL.Hostname(), "1"))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	return &upnp{
+		serviceURL:  serviceURL.String(),
+		serviceType: svc.ServiceType,
+		localIP:     conn.LocalAddr().(*net.UDPAddr).IP,
+		client:      client,
+	}, nil
+}
+
+// upnpError is an error returned by a gateway in a SOAP fault.
+type upnpError struct {
+	Code        int    `xml:"errorCode"`
+	Description string `xml:"errorDescription"`
+}
+
+func (e upnpError) Error() string {
+	return fmt.Sprintf("UPnP error %d: %s", e.Code, e.Description)
+}
+
+// soapRequest invokes action of the service of the gateway with args, pairs of
+// argument names and values, and decodes the body of the response in result,
+// if not nil.
+func (u *upnp) soapRequest(action string, result interface{}, args ...string) error {
+	var body bytes.Buffer
+	body.WriteString(xml.Header)
+	body.WriteString(`<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/" ` +
+		`s:encodingStyle="http://schemas.xmlsoap.org/soap/encoding/"><s:Body>`)
+	fmt.Fprintf(&body, `<u:%s xmlns:u="%s">`, action, u.serviceType)
+	for i := 0; i+1 < len(args); i += 2 {
+		fmt.Fprintf(&body, "<%s>", args[i])
+		if err := xml.EscapeText(&body, []byte(args[i+1])); err != nil {
+			return err
+		}
+		fmt.Fprintf(&body, "</%s>", args[i])
+	}
+	fmt.Fprintf(&body, "</u:%s></s:Body></s:Envelope>", action)
+
+	req, err := http.NewRequest(http.MethodPost, u.serviceURL, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", `text/xml; charset="utf-8"`)
+	req.Header.Set("SOAPAction", fmt.Sprintf(`"%s#%s"`, u.serviceType, action))
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	dec := xml.NewDecoder(io.LimitReader(resp.Body, maxDescriptionSize))
+
+	if resp.StatusCode != http.StatusOK {
+		var fault struct {
+			Error upnpError `xml:"Body>Fault>detail>UPnPError"`
+		}
+		if err := dec.Decode(&fault); err == nil && fault.Error.Code != 0 {
+			return fault.Error
+		}
+		return fmt.Errorf("UPnP %s: %v", action, resp.Status)
+	}
+	if result == nil {
+		return nil
+	}
+	return dec.Decode(result)
+}
+
+func (u *upnp) ExternalIP() (net.IP, error) {
+	var resp struct {
+		IP string `xml:"Body>GetExternalIPAddressResponse>NewExternalIPAddress"`
+	}
+	if err := u.soapRequest("GetExternalIPAddress", &resp); err != nil {
+		return nil, err
+	}
+	ip := net.ParseIP(strings.TrimSpace(resp.IP))
+	if ip == nil {
+		return nil, fmt.Errorf("invalid external IP %q", resp.IP)
+	}
+	return ip, nil
+}
+
+func (u *upnp) AddPortMapping(
+	protocol string,
+	internalPort, externalPort int,
+	description string,
+	lifetime time.Duration,
+) (int, error) {
+	addPortMapping := func(lifetime time.Duration) error {
+		return u.soapRequest("AddPortMapping", nil,
+			"NewRemoteHost", "",
+			"NewExternalPort", strconv.Itoa(externalPort),
+			"NewProtocol", protocol,
+			"NewInternalPort", strconv.Itoa(internalPort),
+			"NewInternalClient", u.localIP.String(),
+			"NewEnabled", "1",
+			"NewPortMappingDescription", description,
+			"NewLeaseDuration", strconv.Itoa(int(lifetime.Seconds())),
+		)
+	}
+	err := addPortMapping(lifetime)
+	var upnpErr upnpError
+	if errors.As(err, &upnpErr) && upnpErr.Code == errOnlyPermanentLeases {
+		err = addPortMapping(0)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return externalPort, nil
+}
+
+func (u *upnp) DeletePortMapping(protocol string, _, externalPort int) error {
+	return u.soapRequest("DeletePortMapping", nil,
+		"NewRemoteHost", "",
+		"NewExternalPort", strconv.Itoa(externalPort),
+		"NewProtocol", protocol,
+	)
+}
+
+func (u *upnp) String() string {
+	return fmt.Sprintf("UPnP(%s)", u.serviceURL)
+}
@@ -0,0 +1,129 @@
+package nat
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testDeviceDescription = `<?xml version="1.0"?>
+<root xmlns="urn:schemas-upnp-org:device-1-0">
+  <device>
+    <deviceType>urn:schemas-upnp-org:device:InternetGatewayDevice:1</deviceType>
+    <deviceList>
+      <device>
+        <deviceType>urn:schemas-upnp-org:device:WANDevice:1</deviceType>
+        <deviceList>
+          <device>
+            <deviceType>urn:schemas-upnp-org:device:WANConnectionDevice:1</deviceType>
+            <serviceList>
+              <service>
+                <serviceType>urn:schemas-upnp-org:service:WANIPConnection:1</serviceType>
+                <controlURL>/ctl/IPConn</controlURL>
+              </service>
+            </serviceList>
+          </device>
+        </deviceList>
+      </device>
+    </deviceList>
+  </device>
+</root>`
+
+// testGateway serves the description and the WANIPConnection service of a
+// UPnP gateway supporting only permanent port mappings.
+type testGateway struct {
+	mtx      sync.Mutex
+	mappings map[string]string // external port by internal client and port
+}
+
+func (g *testGateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/desc.xml" {
+		_, _ = io.WriteString(w, testDeviceDescription)
+		return
+	}
+
+	var req struct {
+		Body struct {
+			Action struct {
+				XMLName        xml.Name
+				ExternalPort   string `xml:"NewExternalPort"`
+				InternalPort   string `xml:"NewInternalPort"`
+				InternalClient string `xml:"NewInternalClient"`
+				LeaseDuration  string `xml:"NewLeaseDuration"`
+			} `xml:",any"`
+		} `xml:"Body"`
+	}
+	if err := xml.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	action := req.Body.Action
+	if !strings.HasSuffix(r.Header.Get("SOAPAction"), "#"+action.XMLName.Local+`"`) {
+		http.Error(w, "invalid SOAPAction", http.StatusBadRequest)
+		return
+	}
+
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+	switch action.XMLName.Local {
+	case "GetExternalIPAddress":
+		_, _ = io.WriteString(w, `<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"><s:Body>`+
+			`<u:GetExternalIPAddressResponse xmlns:u="urn:schemas-upnp-org:service:WANIPConnection:1">`+
+			`<NewExternalIPAddress>198.51.100.1</NewExternalIPAddress>`+
+			`</u:GetExternalIPAddressResponse></s:Body></s:Envelope>`)
+	case "AddPortMapping":
+		if action.LeaseDuration != "0" {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = io.WriteString(w, `<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"><s:Body>`+
+				`<s:Fault><faultcode>s:Client</faultcode><faultstring>UPnPError</faultstring><detail>`+
+				`<UPnPError xmlns="urn:schemas-upnp-org:control-1-0"><errorCode>725</errorCode>`+
+				`<errorDescription>OnlyPermanentLeasesSupported</errorDescription></UPnPError>`+
+				`</detail></s:Fault></s:Body></s:Envelope>`)
+			return
+		}
+		g.mappings[action.InternalClient+":"+action.InternalPort] = action.ExternalPort
+	case "DeletePortMapping":
+		for k, port := range g.mappings {
+			if port == action.ExternalPort {
+				delete(g.mappings, k)
+			}
+		}
+	default:
+		http.Error(w, "unknown action", http.StatusInternalServerError)
+	}
+}
+
+func TestUPnP(t *testing.T) {
+	gateway := &testGateway{mappings: make(map[string]string)}
+	srv := httptest.NewServer(gateway)
+	t.Cleanup(srv.Close)
+
+	u, err := newUPnP(srv.URL+"/desc.xml", time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, srv.URL+"/ctl/IPConn", u.serviceURL)
+	assert.Equal(t, "127.0.0.1", u.localIP.String())
+
+	ip, err := u.ExternalIP()
+	require.NoError(t, err)
+	assert.Equal(t, "198.51.100.1", ip.String())
+
+	// The gateway only supports permanent mappings.
+	port, err := u.AddPortMapping(TCP, 26656, 26656, mappingDescription, mappingLifetime)
+	require.NoError(t, err)
+	assert.Equal(t, 26656, port)
+	assert.Equal(t, map[string]string{"127.0.0.1:26656": "26656"}, gateway.mappings)
+
+	require.NoError(t, u.DeletePortMapping(TCP, 26656, port))
+	assert.Empty(t, gateway.mappings)
+
+	_, err = newUPnP(srv.URL+"/missing.xml", time.Second)
+	assert.Error(t, err)
+}
@@ -4,8 +4,11 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"net"
 	"reflect"
+	"strings"
 
+	"github.com/cometbft/cometbft/config"
 	cmtbytes "github.com/cometbft/cometbft/libs/bytes"
 	cmtstrings "github.com/cometbft/cometbft/libs/strings"
 	tmp2p "github.com/cometbft/cometbft/proto/tendermint/p2p"
@@ -15,6 +18,10 @@ import (
 const (
 	maxNodeInfoSize = 10240 // 10KB
 	maxNumChannels  = 16    // plenty of room for upgrades, for now
+
+	// SupportedCompression is the comma separated list of the compression
+	// algorithms nodes advertise they can decompress messages with.
+	SupportedCompression = config.P2PCompressionZstd + "," + config.P2PCompressionSnappy
 )
 
 // Max size of the NodeInfo struct
@@ -99,6 +106,13 @@ type DefaultNodeInfo struct {
 type DefaultNodeInfoOther struct {
 	TxIndex    string `json:"tx_index"`
 	RPCAddress string `json:"rpc_address"`
+	// Comma separated list of the compression algorithms the node can
+	// decompress p2p packets and mempool transactions with.
+	Compression string `json:"compression"`
+	// Role of the node in the network, one of the config.P2PRole values.
+	Role string `json:"role"`
+	// IP address of the receiver of the node info, as observed by the node.
+	ObservedIP string `json:"observed_ip"`
 }
 
 // ID returns the node's peer ID.
@@ -169,10 +183,95 @@ func (info DefaultNodeInfo) Validate() error {
 	if len(rpcAddr) > 0 && (!cmtstrings.IsASCIIText(rpcAddr) || cmtstrings.ASCIITrim(rpcAddr) == "") {
 		return fmt.Errorf("info.Other.RPCAddress=%v must be valid ASCII text without tabs", rpcAddr)
 	}
+	if compression := other.Compression; len(compression) > 0 && !cmtstrings.IsASCIIText(compression) {
+		return fmt.Errorf("info.Other.Compression=%v must be valid ASCII text without tabs", compression)
+	}
+	if role := other.Role; len(role) > 0 && !cmtstrings.IsASCIIText(role) {
+		return fmt.Errorf("info.Other.Role=%v must be valid ASCII text without tabs", role)
+	}
+	if observedIP := other.ObservedIP; len(observedIP) > 0 && net.ParseIP(observedIP) == nil {
+		return fmt.Errorf("info.Other.ObservedIP=%v must be a valid IP address", observedIP)
+	}
+
+	return nil
+}
+
+// PeerRole returns the role the peer advertised in its node info, or
+// config.P2PRoleFull if it didn't.
+func PeerRole(ni NodeInfo) string {
+	if info, ok := ni.(DefaultNodeInfo); ok && info.Other.Role != "" {
+		return info.Other.Role
+	}
+	return config.P2PRoleFull
+}
 
+// PeerObservedIP returns the IP address the peer observed us connecting
+// from, as reported in its node info, or nil if it didn't report one.
+func PeerObservedIP(ni NodeInfo) net.IP {
+	if info, ok := ni.(DefaultNodeInfo); ok && info.Other.ObservedIP != "" {
+		return net.ParseIP(info.Other.ObservedIP)
+	}
 	return nil
 }
 
+// withObservedIP returns ni reporting the IP address of remoteAddr, the
+// address of the peer ni is sent to, if ni is a DefaultNodeInfo.
+func withObservedIP(ni NodeInfo, remoteAddr net.Addr) NodeInfo {
+	info, ok := ni.(DefaultNodeInfo)
+	if !ok {
+		return ni
+	}
+	host, _, err := net.SplitHostPort(remoteAddr.String())
+	if ip := net.ParseIP(host); err == nil && ip != nil {
+		info.Other.ObservedIP = ip.String()
+	}
+	return info
+}
+
+// SupportsCompression returns true if the node advertises it can decompress
+// messages compressed with algo.
+func (info DefaultNodeInfo) SupportsCompression(algo string) bool {
+	return cmtstrings.StringInSlice(algo, cmtstrings.SplitAndTrimEmpty(info.Other.Compression, ",", " "))
+}
+
+// negotiateCompression returns the algorithm to compress the messages sent to
+// a peer with: the first of the comma separated algorithms we want to compress
+// with that the peer supports, or "" if there is none.
+func negotiateCompression(algos string, theirs NodeInfo) string {
+	theirInfo, ok := theirs.(DefaultNodeInfo)
+	if !ok {
+		return ""
+	}
+	for _, algo := range cmtstrings.SplitAndTrimEmpty(algos, ",", " ") {
+		if theirInfo.SupportsCompression(algo) {
+			return algo
+		}
+	}
+	return ""
+}
+
+// acceptedCompression returns the comma separated algorithms a peer may
+// compress the messages it sends us with: those both we and the peer
+// advertise, as it picks one of the algorithms we advertise and which it
+// supports itself.
+func acceptedCompression(ours, theirs NodeInfo) string {
+	ourInfo, ok := ours.(DefaultNodeInfo)
+	if !ok {
+		return ""
+	}
+	theirInfo, ok := theirs.(DefaultNodeInfo)
+	if !ok {
+		return ""
+	}
+	var algos []string
+	for _, algo := range cmtstrings.SplitAndTrimEmpty(ourInfo.Other.Compression, ",", " ") {
+		if theirInfo.SupportsCompression(algo) {
+			algos = append(algos, algo)
+		}
+	}
+	return strings.Join(algos, ",")
+}
+
 // CompatibleWith checks if two DefaultNodeInfo are compatible with eachother.
 // CONTRACT: two nodes are compatible if the Block version and network match
 // and they have at least one channel in common.
@@ -243,8 +342,11 @@ func (info DefaultNodeInfo) ToProto() *tmp2p.DefaultNodeInfo {
 	dni.Channels = info.Channels
 	dni.Moniker = info.Moniker
 	dni.Other = tmp2p.DefaultNodeInfoOther{
-		TxIndex:    info.Other.TxIndex,
-		RPCAddress: info.Other.RPCAddress,
+		TxIndex:     info.Other.TxIndex,
+		RPCAddress:  info.Other.RPCAddress,
+		Compression: info.Other.Compression,
+		Role:        info.Other.Role,
+		ObservedIP:  info.Other.ObservedIP,
 	}
 
 	return dni
@@ -267,8 +369,11 @@ func DefaultNodeInfoFromToProto(pb *tmp2p.DefaultNodeInfo) (DefaultNodeInfo, err
 		Channels:      pb.Channels,
 		Moniker:       pb.Moniker,
 		Other: DefaultNodeInfoOther{
-			TxIndex:    pb.Other.TxIndex,
-			RPCAddress: pb.Other.RPCAddress,
+			TxIndex:     pb.Other.TxIndex,
+			RPCAddress:  pb.Other.RPCAddress,
+			Compression: pb.Other.Compression,
+			Role:        pb.Other.Role,
+			ObservedIP:  pb.Other.ObservedIP,
 		},
 	}
 
@@ -64,6 +64,9 @@ func TestNodeInfoValidate(t *testing.T) {
 		{"Empty space RPCAddress", func(ni *DefaultNodeInfo) { ni.Other.RPCAddress = emptySpace }, true},
 		{"Empty RPCAddress", func(ni *DefaultNodeInfo) { ni.Other.RPCAddress = "" }, false},
 		{"Good RPCAddress", func(ni *DefaultNodeInfo) { ni.Other.RPCAddress = "0.0.0.0:26657" }, false},
+
+		{"Invalid ObservedIP", func(ni *DefaultNodeInfo) { ni.Other.ObservedIP = "198.51.100.1:26656" }, true},
+		{"Good ObservedIP", func(ni *DefaultNodeInfo) { ni.Other.ObservedIP = "2001:db8::1" }, false},
 	}
 
 	nodeKey := NodeKey{PrivKey: ed25519.GenPrivKey()}
@@ -126,3 +129,53 @@ func TestNodeInfoCompatible(t *testing.T) {
 		assert.Error(t, ni1.CompatibleWith(ni))
 	}
 }
+
+func TestNegotiateCompression(t *testing.T) {
+	nodeKey := NodeKey{PrivKey: ed25519.GenPrivKey()}
+	ni := testNodeInfo(nodeKey.ID(), "testing").(DefaultNodeInfo)
+
+	testCases := []struct {
+		ours, theirs string
+		expected     string
+	}{
+		{"", "", ""},
+		{"zstd", "", ""},
+		{"", "zstd", ""},
+		{"zstd", "snappy", ""},
+		{"zstd,snappy", "snappy,zstd", "zstd"},
+		{"snappy, zstd", "zstd,snappy", "snappy"},
+		{"zstd,snappy", "lz4,snappy", "snappy"},
+	}
+	for _, tc := range testCases {
+		ni.Other.Compression = tc.theirs
+		assert.Equal(t, tc.expected, negotiateCompression(tc.ours, ni), "%q with %q", tc.ours, tc.theirs)
+	}
+
+	_, netAddr := CreateRoutableAddr()
+	assert.Empty(t, negotiateCompression(SupportedCompression, mockNodeInfo{netAddr}))
+}
+
+func TestAcceptedCompression(t *testing.T) {
+	ours := testNodeInfo(PubKeyToID(ed25519.GenPrivKey().PubKey()), "ours").(DefaultNodeInfo)
+	theirs := testNodeInfo(PubKeyToID(ed25519.GenPrivKey().PubKey()), "theirs").(DefaultNodeInfo)
+
+	testCases := []struct {
+		ours, theirs string
+		expected     string
+	}{
+		{"", "", ""},
+		{"zstd,snappy", "", ""},
+		{"", "zstd,snappy", ""},
+		{"zstd", "snappy", ""},
+		{"zstd,snappy", "snappy,zstd", "zstd,snappy"},
+		{"zstd,snappy", "lz4,snappy", "snappy"},
+	}
+	for _, tc := range testCases {
+		ours.Other.Compression = tc.ours
+		theirs.Other.Compression = tc.theirs
+		assert.Equal(t, tc.expected, acceptedCompression(ours, theirs), "%q with %q", tc.ours, tc.theirs)
+	}
+
+	_, netAddr := CreateRoutableAddr()
+	assert.Empty(t, acceptedCompression(ours, mockNodeInfo{netAddr}))
+}
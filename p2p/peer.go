@@ -360,6 +360,7 @@ func (p *peer) send(chID byte, msg proto.Message, sendFunc func(byte, []byte) bo
 		p.metrics.PeerSendBytesTotal.With(labels...).Add(float64(len(msgBytes)))
 		labels = append(labels, "message_type", metricLabelValue)
 		p.metrics.MessageSendBytesTotal.With(labels...).Add(float64(len(msgBytes)))
+		p.metrics.MessageSendTotal.With(labels...).Add(1)
 	}
 	return res
 }
@@ -421,6 +422,12 @@ func (p *peer) RemoteAddr() net.Addr {
 	return p.peerConn.conn.RemoteAddr() //nolint:staticcheck
 }
 
+// SetChannelSendRate sets the rate at which packets can be sent on the given
+// channel, in bytes/second. A rate of 0 removes the limit.
+func (p *peer) SetChannelSendRate(chID byte, rate int64) bool {
+	return p.mconn.SetChannelSendRate(chID, rate)
+}
+
 // CanSend returns true if the send queue is not full, false otherwise.
 func (p *peer) CanSend(chID byte) bool {
 	if !p.IsRunning() {
@@ -447,9 +454,17 @@ func (p *peer) metricsReporter() {
 			for _, chStatus := range status.Channels {
 				sendQueueSize += float64(chStatus.SendQueueSize)
 				queues[chStatus.ID] = chStatus.SendQueueSize
+				if chStatus.SendQueueCapacity > 0 {
+					p.metrics.PeerSendQueueSaturation.
+						With("peer_id", string(p.ID()), "chID", fmt.Sprintf("%#x", chStatus.ID)).
+						Set(float64(chStatus.SendQueueSize) / float64(chStatus.SendQueueCapacity))
+				}
 			}
 
 			p.metrics.PeerPendingSendBytes.With("peer_id", string(p.ID())).Set(sendQueueSize)
+			if status.RTT > 0 {
+				p.metrics.PeerRTTSeconds.With("peer_id", string(p.ID())).Set(status.RTT.Seconds())
+			}
 			schema.WritePendingBytes(p.traceClient, string(p.ID()), queues)
 		case <-p.Quit():
 			return
@@ -495,7 +510,9 @@ func createMConnection(
 		}
 		schema.WriteReceivedBytes(p.traceClient, string(p.ID()), chID, len(msgBytes))
 		p.metrics.PeerReceiveBytesTotal.With(labels...).Add(float64(len(msgBytes)))
-		p.metrics.MessageReceiveBytesTotal.With(append(labels, "message_type", p.mlc.ValueToMetricLabel(msg))...).Add(float64(len(msgBytes)))
+		labels = append(labels, "message_type", p.mlc.ValueToMetricLabel(msg))
+		p.metrics.MessageReceiveBytesTotal.With(labels...).Add(float64(len(msgBytes)))
+		p.metrics.MessageReceiveTotal.With(labels...).Add(1)
 		reactor.Receive(Envelope{
 			ChannelID: chID,
 			Src:       p,
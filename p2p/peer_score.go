@@ -0,0 +1,48 @@
+package p2p
+
+import (
+	"math"
+	"time"
+)
+
+// dialLatencyWeight is the weight of the latest dial in the moving average of
+// the dial latencies of a peer.
+const dialLatencyWeight = 0.2
+
+// PeerScore is the track record of a peer across connections. It is kept in
+// the address book, so that the best peers are dialed first, even after a
+// restart.
+type PeerScore struct {
+	// Moving average of the time taken to dial the peer and handshake with it.
+	DialLatency time.Duration `json:"dial_latency"`
+	// Number of connections established with the peer.
+	Connections int64 `json:"connections"`
+	// Number of times the peer was marked as good for doing something useful,
+	// such as contributing to consensus (see Switch.MarkPeerAsGood).
+	Useful int64 `json:"useful"`
+	// Number of times a reactor disconnected the peer for an error, such as
+	// an invalid message.
+	Misbehaviors int64 `json:"misbehaviors"`
+}
+
+// RecordConnection records a connection to the peer, along with the time taken
+// to dial it, or zero for inbound connections.
+func (s *PeerScore) RecordConnection(dialLatency time.Duration) {
+	s.Connections++
+	if dialLatency <= 0 {
+		return
+	}
+	if s.DialLatency == 0 {
+		s.DialLatency = dialLatency
+		return
+	}
+	s.DialLatency = time.Duration((1-dialLatencyWeight)*float64(s.DialLatency) + dialLatencyWeight*float64(dialLatency))
+}
+
+// Value returns the score of the peer, the higher the better: the logarithm
+// of its useful count per connection, minus two points per misbehavior per
+// connection and a point per second of dial latency. Unknown peers score 0.
+func (s PeerScore) Value() float64 {
+	conns := math.Max(float64(s.Connections), 1)
+	return math.Log1p(float64(s.Useful)/conns) - 2*float64(s.Misbehaviors)/conns - s.DialLatency.Seconds()
+}
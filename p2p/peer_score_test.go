@@ -0,0 +1,30 @@
+package p2p
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeerScore(t *testing.T) {
+	var score PeerScore
+	assert.Zero(t, score.Value())
+
+	score.RecordConnection(100 * time.Millisecond)
+	assert.Equal(t, 100*time.Millisecond, score.DialLatency)
+	// Inbound connections don't change the dial latency.
+	score.RecordConnection(0)
+	assert.Equal(t, 100*time.Millisecond, score.DialLatency)
+	score.RecordConnection(600 * time.Millisecond)
+	assert.Equal(t, 200*time.Millisecond, score.DialLatency)
+	assert.Equal(t, int64(3), score.Connections)
+	assert.InDelta(t, -0.2, score.Value(), 1e-9)
+
+	useful := score
+	useful.Useful = 30
+	misbehaving := score
+	misbehaving.Misbehaviors = 1
+	assert.Greater(t, useful.Value(), score.Value())
+	assert.Less(t, misbehaving.Value(), score.Value())
+}
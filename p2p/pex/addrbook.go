@@ -11,6 +11,7 @@ import (
 	"math"
 	"math/rand"
 	"net"
+	"sort"
 	"sync"
 	"time"
 
@@ -59,6 +60,8 @@ type AddrBook interface {
 
 	// Mark address
 	MarkGood(p2p.ID)
+	MarkConnected(id p2p.ID, dialLatency time.Duration)
+	MarkMisbehaved(p2p.ID)
 	MarkAttempt(*p2p.NetAddress)
 	MarkBad(*p2p.NetAddress, time.Duration) // Move peer to bad peers list
 	// Add bad peers back to addrBook
@@ -67,8 +70,17 @@ type AddrBook interface {
 	IsGood(*p2p.NetAddress) bool
 	IsBanned(*p2p.NetAddress) bool
 
+	// Score of a peer
+	PeerScore(p2p.ID) p2p.PeerScore
+
+	// Liveness of a peer, as seen by us
+	MarkSeen(id p2p.ID, lastSeen time.Time)
+	LastSeen(p2p.ID) time.Time
+
 	// Send a selection of addresses to peers
 	GetSelection() []*p2p.NetAddress
+	// Select addresses to dial, best scores first
+	GetDialSelection() []*p2p.NetAddress
 	// Send a selection of addresses with bias
 	GetSelectionWithBias(biasTowardsNewAddrs int) []*p2p.NetAddress
 
@@ -281,6 +293,65 @@ func (a *addrBook) MarkGood(id p2p.ID) {
 	}
 }
 
+// MarkConnected implements AddrBook - it records a connection to the peer in
+// its score, along with the time taken to dial it, or zero for inbound peers.
+func (a *addrBook) MarkConnected(id p2p.ID, dialLatency time.Duration) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	if ka := a.knownAddress(id); ka != nil {
+		ka.Score.RecordConnection(dialLatency)
+		ka.markSeen(time.Now())
+	}
+}
+
+// MarkMisbehaved implements AddrBook - it records in its score that the peer
+// was disconnected for an error.
+func (a *addrBook) MarkMisbehaved(id p2p.ID) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	if ka := a.knownAddress(id); ka != nil {
+		ka.Score.Misbehaviors++
+	}
+}
+
+// PeerScore implements AddrBook - it returns the score of the peer, or a zero
+// score if the peer is unknown.
+func (a *addrBook) PeerScore(id p2p.ID) p2p.PeerScore {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	if ka := a.knownAddress(id); ka != nil {
+		return ka.Score
+	}
+	return p2p.PeerScore{}
+}
+
+// MarkSeen implements AddrBook - it records that the peer was seen live at
+// lastSeen, e.g. when we verified it accepts connections. Times in the future
+// are taken as now.
+func (a *addrBook) MarkSeen(id p2p.ID, lastSeen time.Time) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	if ka := a.addrLookup[id]; ka != nil {
+		ka.markSeen(lastSeen)
+	}
+}
+
+// LastSeen implements AddrBook - it returns the last time the peer was seen
+// live, or the zero time if it never was or is unknown.
+func (a *addrBook) LastSeen(id p2p.ID) time.Time {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	if ka := a.knownAddress(id); ka != nil {
+		return ka.LastSeen
+	}
+	return time.Time{}
+}
+
 // MarkAttempt implements AddrBook - it marks that an attempt was made to connect to the address.
 func (a *addrBook) MarkAttempt(addr *p2p.NetAddress) {
 	a.mtx.Lock()
@@ -360,6 +431,35 @@ func (a *addrBook) GetSelection() []*p2p.NetAddress {
 	return addresses
 }
 
+// GetDialSelection implements AddrBook.
+// It returns all addresses (old & new), by decreasing score of their peer, the
+// recently live ones first among equal scores, and randomly otherwise.
+// Suitable for choosing the peers to dial.
+// Must never return a nil address.
+func (a *addrBook) GetDialSelection() []*p2p.NetAddress {
+	addresses := a.GetSelection()
+
+	a.mtx.Lock()
+	scores := make(map[p2p.ID]float64, len(addresses))
+	live := make(map[p2p.ID]bool, len(addresses))
+	for _, addr := range addresses {
+		if ka := a.addrLookup[addr.ID]; ka != nil {
+			scores[addr.ID] = ka.Score.Value()
+			live[addr.ID] = ka.isRecentlyLive()
+		}
+	}
+	a.mtx.Unlock()
+
+	sort.SliceStable(addresses, func(i, j int) bool {
+		idI, idJ := addresses[i].ID, addresses[j].ID
+		if scores[idI] != scores[idJ] {
+			return scores[idI] > scores[idJ]
+		}
+		return live[idI] && !live[idJ]
+	})
+	return addresses
+}
+
 func percentageOfNum(p, n int) int {
 	return int(math.Round((float64(p) / float64(100)) * float64(n)))
 }
@@ -731,6 +831,15 @@ func (a *addrBook) moveToOld(ka *knownAddress) error {
 	return nil
 }
 
+// knownAddress returns the known address of the peer, in the book or among
+// the bad peers, or nil.
+func (a *addrBook) knownAddress(id p2p.ID) *knownAddress {
+	if ka := a.addrLookup[id]; ka != nil {
+		return ka
+	}
+	return a.badPeers[id]
+}
+
 func (a *addrBook) removeAddress(addr *p2p.NetAddress) {
 	ka := a.addrLookup[addr.ID]
 	if ka == nil {
@@ -344,6 +344,93 @@ func TestAddrBookGetSelectionWithBias(t *testing.T) {
 	}
 }
 
+func TestAddrBookPeerScores(t *testing.T) {
+	fname := createTempFileName("addrbook_test")
+	defer deleteTempFile(fname)
+
+	book := NewAddrBook(fname, true)
+	book.SetLogger(log.TestingLogger())
+	book.Save()
+	require.NoError(t, book.Start())
+
+	randAddrs := randNetAddressPairs(t, 3)
+	for _, addrSrc := range randAddrs {
+		require.NoError(t, book.AddAddress(addrSrc.addr, addrSrc.src))
+	}
+	good, unknown, bad := randAddrs[0].addr, randAddrs[1].addr, randAddrs[2].addr
+
+	book.MarkConnected(good.ID, 100*time.Millisecond)
+	book.MarkGood(good.ID)
+	book.MarkGood(good.ID)
+	book.MarkConnected(bad.ID, 0)
+	book.MarkMisbehaved(bad.ID)
+
+	assert.Equal(t, p2p.PeerScore{DialLatency: 100 * time.Millisecond, Connections: 1, Useful: 2}, book.PeerScore(good.ID))
+	assert.Equal(t, p2p.PeerScore{}, book.PeerScore(unknown.ID))
+
+	// The best peers are dialed first.
+	assert.Equal(t, []*p2p.NetAddress{good, unknown, bad}, book.GetDialSelection())
+
+	// Scores are kept across restarts.
+	book.Save()
+	book = NewAddrBook(fname, true)
+	book.SetLogger(log.TestingLogger())
+	require.NoError(t, book.Start())
+	assert.Equal(t, int64(1), book.PeerScore(bad.ID).Misbehaviors)
+	assert.Equal(t, []*p2p.NetAddress{good, unknown, bad}, book.GetDialSelection())
+}
+
+func TestAddrBookLiveness(t *testing.T) {
+	fname := createTempFileName("addrbook_test")
+	defer deleteTempFile(fname)
+
+	book := NewAddrBook(fname, true)
+	book.SetLogger(log.TestingLogger())
+	book.Save()
+	require.NoError(t, book.Start())
+
+	randAddrs := randNetAddressPairs(t, 3)
+	for _, addrSrc := range randAddrs {
+		require.NoError(t, book.AddAddress(addrSrc.addr, addrSrc.src))
+	}
+	live, stale, unknown := randAddrs[0].addr, randAddrs[1].addr, randAddrs[2].addr
+
+	book.MarkSeen(live.ID, time.Now().Add(time.Hour))
+	assert.WithinDuration(t, time.Now(), book.LastSeen(live.ID), time.Minute, "times in the future are taken as now")
+	lastSeen := time.Now().Add(-2 * recentlyLivePeriod).Truncate(time.Second)
+	book.MarkSeen(stale.ID, lastSeen)
+	book.MarkSeen(stale.ID, lastSeen.Add(-time.Hour))
+	assert.True(t, lastSeen.Equal(book.LastSeen(stale.ID)))
+	assert.True(t, book.LastSeen(unknown.ID).IsZero())
+
+	// Recently live addresses are dialed first among equal scores.
+	assert.Equal(t, live, book.GetDialSelection()[0])
+
+	// Liveness is kept across restarts.
+	book.Save()
+	book = NewAddrBook(fname, true)
+	book.SetLogger(log.TestingLogger())
+	require.NoError(t, book.Start())
+	assert.True(t, lastSeen.Equal(book.LastSeen(stale.ID)))
+	assert.Equal(t, live, book.GetDialSelection()[0])
+}
+
+func TestKnownAddressIsBadWhenStale(t *testing.T) {
+	addr := randIPv4Address(t)
+	ka := newKnownAddress(addr, addr)
+	ka.LastAttempt = time.Now().Add(-time.Hour)
+	assert.False(t, ka.isBad())
+
+	// An address seen live a while ago is only bad once it fails.
+	ka.markSeen(time.Now().Add(-2 * numStaleDays * 24 * time.Hour))
+	assert.False(t, ka.isBad())
+	ka.Attempts = 1
+	assert.True(t, ka.isBad())
+
+	ka.markSeen(time.Now())
+	assert.False(t, ka.isBad())
+}
+
 func TestAddrBookHasAddress(t *testing.T) {
 	fname := createTempFileName("addrbook_test")
 	defer deleteTempFile(fname)
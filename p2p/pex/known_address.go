@@ -17,6 +17,8 @@ type knownAddress struct {
 	LastAttempt time.Time       `json:"last_attempt"`
 	LastSuccess time.Time       `json:"last_success"`
 	LastBanTime time.Time       `json:"last_ban_time"`
+	LastSeen    time.Time       `json:"last_seen"`
+	Score       p2p.PeerScore   `json:"score"`
 }
 
 func newKnownAddress(addr *p2p.NetAddress, src *p2p.NetAddress) *knownAddress {
@@ -53,6 +55,25 @@ func (ka *knownAddress) markGood() {
 	ka.LastAttempt = now
 	ka.Attempts = 0
 	ka.LastSuccess = now
+	ka.LastSeen = now
+	ka.Score.Useful++
+}
+
+// markSeen records that the address was seen live at t. Times in the future
+// are taken as now.
+func (ka *knownAddress) markSeen(t time.Time) {
+	if now := time.Now(); t.After(now) {
+		t = now
+	}
+	if t.After(ka.LastSeen) {
+		ka.LastSeen = t
+	}
+}
+
+// isRecentlyLive returns true if the address was seen live in the last
+// recentlyLivePeriod.
+func (ka *knownAddress) isRecentlyLive() bool {
+	return ka.LastSeen.After(time.Now().Add(-recentlyLivePeriod))
 }
 
 func (ka *knownAddress) ban(banTime time.Duration) {
@@ -101,6 +122,7 @@ minute, and meets one of the following criteria:
 2) It hasn't been seen in over a week
 3) It has failed at least three times and never succeeded
 4) It has failed ten times in the last week
+5) It has failed since it was last seen live, over a day ago
 
 All addresses that meet these criteria are assumed to be worthless and not
 worth keeping hold of.
@@ -119,11 +141,21 @@ func (ka *knownAddress) isBad() bool {
 	// TODO: From the future?
 
 	// Too old?
-	// TODO: should be a timestamp of last seen, not just last attempt
 	if ka.LastAttempt.Before(time.Now().Add(-1 * numMissingDays * time.Hour * 24)) {
 		return true
 	}
 
+	// Not seen live in too long? Addresses are only known to have been seen
+	// live if we connected to them.
+	if !ka.LastSeen.IsZero() {
+		if ka.LastSeen.Before(time.Now().Add(-1 * numMissingDays * time.Hour * 24)) {
+			return true
+		}
+		if ka.LastSeen.Before(time.Now().Add(-1*numStaleDays*time.Hour*24)) && ka.Attempts > 0 {
+			return true
+		}
+	}
+
 	// Never succeeded?
 	if ka.LastSuccess.IsZero() && ka.Attempts >= numRetries {
 		return true
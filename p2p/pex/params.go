@@ -34,6 +34,14 @@ const (
 	// if we have not seen it announced in that long.
 	numMissingDays = 7
 
+	// days without being seen live, after which an address which failed to
+	// be dialed is assumed to be gone.
+	numStaleDays = 1
+
+	// period over which an address seen live is dialed before the others of
+	// the same score.
+	recentlyLivePeriod = time.Hour
+
 	// tries without a single success before we assume an address is bad.
 	numRetries = 3
 
@@ -213,8 +213,19 @@ func (r *Reactor) AddPeer(p Peer) error {
 
 		// add to book. dont RequestAddrs right away because
 		// we don't trust inbound as much - let ensurePeersRoutine handle it.
-		err = r.book.AddAddress(addr, src)
-		r.logErrAddrBook(err)
+		addToBook := func(reachable bool) {
+			if !reachable {
+				return
+			}
+			err := r.book.AddAddress(addr, src)
+			r.logErrAddrBook(err)
+		}
+		if r.Switch == nil {
+			addToBook(true)
+		} else {
+			// the address is only added once verified, if dial-back is enabled
+			r.Switch.VerifyListenAddr(addr, addToBook)
+		}
 	}
 	return nil
 }
@@ -283,6 +294,9 @@ func (r *Reactor) Receive(e p2p.Envelope) {
 	case *tmp2p.PexAddrs:
 		// If we asked for addresses, add them to the book
 		addrs, err := p2p.NetAddressesFromProto(msg.Addrs)
+		if err == nil && len(msg.Liveness) > 0 && len(msg.Liveness) != len(addrs) {
+			err = fmt.Errorf("got the liveness of %d addresses for %d addresses", len(msg.Liveness), len(addrs))
+		}
 		if err != nil {
 			r.Switch.StopPeerForError(e.Src, err, r.String())
 			r.book.MarkBad(e.Src.SocketAddr(), defaultBanTime)
@@ -296,6 +310,7 @@ func (r *Reactor) Receive(e p2p.Envelope) {
 			}
 			return
 		}
+		r.markSeen(e.Src.ID(), addrs, msg.Liveness)
 
 	default:
 		r.Logger.Error(fmt.Sprintf("Unknown message type %T", msg))
@@ -391,15 +406,59 @@ func (r *Reactor) ReceiveAddrs(addrs []*p2p.NetAddress, src Peer) error {
 	return nil
 }
 
-// SendAddrs sends addrs to the peer.
+// markSeen verifies the liveness of addrs reported by the peer src, which it
+// could make up: the addresses it reports as recently live, and which we didn't see
+// live ourselves since, are dialed back, and only marked as seen live in the
+// book if they accept the connection.
+func (r *Reactor) markSeen(src p2p.ID, addrs []*p2p.NetAddress, liveness []tmp2p.PexAddrLiveness) {
+	if r.Switch == nil {
+		return
+	}
+	since := time.Now().Add(-recentlyLivePeriod)
+	for i, l := range liveness {
+		addr := addrs[i]
+		if !l.Connected && !time.Unix(l.LastSeen, 0).After(since) {
+			continue
+		}
+		if r.book.LastSeen(addr.ID).After(since) {
+			continue
+		}
+		r.Switch.VerifyReportedAddr(src, addr, func(live bool) {
+			if live {
+				r.book.MarkSeen(addr.ID, time.Now())
+			}
+		})
+	}
+}
+
+// SendAddrs sends addrs to the peer, along with what we know of their
+// liveness.
 func (r *Reactor) SendAddrs(p Peer, netAddrs []*p2p.NetAddress) {
 	e := p2p.Envelope{
 		ChannelID: PexChannel,
-		Message:   &tmp2p.PexAddrs{Addrs: p2p.NetAddressesToProto(netAddrs)},
+		Message: &tmp2p.PexAddrs{
+			Addrs:    p2p.NetAddressesToProto(netAddrs),
+			Liveness: r.addrsLiveness(netAddrs),
+		},
 	}
 	p.Send(e)
 }
 
+// addrsLiveness returns the last time we were connected to each of addrs, and
+// whether we still are.
+func (r *Reactor) addrsLiveness(addrs []*p2p.NetAddress) []tmp2p.PexAddrLiveness {
+	now := time.Now().Unix()
+	liveness := make([]tmp2p.PexAddrLiveness, len(addrs))
+	for i, addr := range addrs {
+		if r.Switch != nil && r.Switch.Peers().Has(addr.ID) {
+			liveness[i] = tmp2p.PexAddrLiveness{LastSeen: now, Connected: true}
+		} else if lastSeen := r.book.LastSeen(addr.ID); !lastSeen.IsZero() {
+			liveness[i].LastSeen = lastSeen.Unix()
+		}
+	}
+	return liveness
+}
+
 // SetEnsurePeersPeriod sets period to ensure peers connected.
 func (r *Reactor) SetEnsurePeersPeriod(d time.Duration) {
 	r.ensurePeersPeriod = d
@@ -460,13 +519,14 @@ func (r *Reactor) ensurePeers(ensurePeersPeriodElapsed bool) {
 		return
 	}
 
-	addrBook := r.book.GetSelection()
+	addrBook := r.book.GetDialSelection()
 	maxDials := r.Switch.MaxNumOutboundPeers() * 4
 	// check if the addressbook is smaller than maxDials
 	if len(addrBook) < maxDials {
 		maxDials = len(addrBook)
 	}
-	// We don't need to randomize the addresses since the addressbook is already shuffled
+	// We don't need to randomize the addresses since the addressbook is
+	// already shuffled among peers of equal scores
 	for i := 0; i < maxDials; i++ {
 		addr := addrBook[i]
 
@@ -14,8 +14,10 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/cometbft/cometbft/config"
+	"github.com/cometbft/cometbft/crypto/ed25519"
 	"github.com/cometbft/cometbft/libs/log"
 	"github.com/cometbft/cometbft/p2p"
+	"github.com/cometbft/cometbft/p2p/conn"
 	"github.com/cometbft/cometbft/p2p/mock"
 	tmp2p "github.com/cometbft/cometbft/proto/tendermint/p2p"
 )
@@ -137,6 +139,80 @@ func TestPEXReactorReceive(t *testing.T) {
 	r.Receive(p2p.Envelope{ChannelID: PexChannel, Src: peer, Message: &tmp2p.PexRequest{}})
 }
 
+// listenAuthenticated returns the address of a node accepting secret
+// connections.
+func listenAuthenticated(t *testing.T) *p2p.NetAddress {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+	nodeKey := p2p.NodeKey{PrivKey: ed25519.GenPrivKey()}
+	go func() {
+		for {
+			c, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			_, _ = conn.MakeSecretConnection(c, nodeKey.PrivKey)
+			c.Close()
+		}
+	}()
+	addr, err := p2p.NewNetAddressString(p2p.IDAddressString(nodeKey.ID(), ln.Addr().String()))
+	require.NoError(t, err)
+	return addr
+}
+
+func TestPEXReactorReceiveLiveness(t *testing.T) {
+	book := NewAddrBook(filepath.Join(t.TempDir(), "addrbook.json"), false)
+	book.SetLogger(log.TestingLogger())
+	r := NewReactor(book, &ReactorConfig{})
+	r.SetLogger(log.TestingLogger())
+
+	sw := createSwitchAndAddReactors(r)
+	sw.SetAddrBook(book)
+
+	peer := mock.NewPeer(nil)
+	p2p.AddPeerToSwitchPeerSet(sw, peer)
+
+	// The liveness reported by peers is only recorded once verified, and only
+	// recent liveness is verified.
+	live, stale := listenAuthenticated(t), listenAuthenticated(t)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	dead, err := p2p.NewNetAddressString(p2p.IDAddressString(p2p.CreateRandomPeer(false).ID(), ln.Addr().String()))
+	require.NoError(t, err)
+	require.NoError(t, ln.Close())
+	r.RequestAddrs(peer)
+	r.Receive(p2p.Envelope{ChannelID: PexChannel, Src: peer, Message: &tmp2p.PexAddrs{
+		Addrs: []tmp2p.NetAddress{live.ToProto(), stale.ToProto(), dead.ToProto()},
+		Liveness: []tmp2p.PexAddrLiveness{
+			{LastSeen: time.Now().Add(-2 * recentlyLivePeriod).Unix(), Connected: true},
+			{LastSeen: time.Now().Add(-2 * recentlyLivePeriod).Unix()},
+			{LastSeen: time.Now().Unix()},
+		},
+	}})
+	assert.Eventually(t, func() bool {
+		return time.Since(book.LastSeen(live.ID)) < time.Minute
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Never(t, func() bool {
+		return !book.LastSeen(stale.ID).IsZero() || !book.LastSeen(dead.ID).IsZero()
+	}, 500*time.Millisecond, 10*time.Millisecond)
+
+	// Our peers are attested live to others.
+	liveness := r.addrsLiveness([]*p2p.NetAddress{peer.SocketAddr(), live, stale})
+	assert.True(t, liveness[0].Connected)
+	assert.False(t, liveness[1].Connected)
+	assert.Equal(t, book.LastSeen(live.ID).Unix(), liveness[1].LastSeen)
+	assert.Equal(t, tmp2p.PexAddrLiveness{}, liveness[2])
+
+	// The liveness must match the addresses.
+	r.RequestAddrs(peer)
+	r.Receive(p2p.Envelope{ChannelID: PexChannel, Src: peer, Message: &tmp2p.PexAddrs{
+		Addrs:    []tmp2p.NetAddress{live.ToProto()},
+		Liveness: []tmp2p.PexAddrLiveness{{}, {}},
+	}})
+	assert.False(t, sw.Peers().Has(peer.ID()))
+}
+
 func TestPEXReactorRequestMessageAbuse(t *testing.T) {
 	r, book := createReactor(&ReactorConfig{})
 	defer teardownReactor(book)
@@ -839,6 +915,10 @@ func TestPexVectors(t *testing.T) {
 	}{
 		{"PexRequest", &tmp2p.PexRequest{}, "0a00"},
 		{"PexAddrs", &tmp2p.PexAddrs{Addrs: []tmp2p.NetAddress{addr}}, "12130a110a013112093132372e302e302e31188247"},
+		{"PexAddrsLiveness", &tmp2p.PexAddrs{
+			Addrs:    []tmp2p.NetAddress{addr},
+			Liveness: []tmp2p.PexAddrLiveness{{LastSeen: 1700000000, Connected: true}},
+		}, "121d0a110a013112093132372e302e302e3118824712080880e2cfaa061001"},
 	}
 
 	for _, tc := range testCases {
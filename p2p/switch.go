@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"net"
+	"strconv"
 	"sync"
 	"time"
 
@@ -13,6 +15,7 @@ import (
 	"github.com/cometbft/cometbft/libs/cmap"
 	"github.com/cometbft/cometbft/libs/rand"
 	"github.com/cometbft/cometbft/libs/service"
+	cmtstrings "github.com/cometbft/cometbft/libs/strings"
 	"github.com/cometbft/cometbft/libs/trace"
 	"github.com/cometbft/cometbft/libs/trace/schema"
 	"github.com/cometbft/cometbft/p2p/conn"
@@ -32,6 +35,10 @@ const (
 	// ie. 3**10 = 16hrs
 	reconnectBackOffAttempts    = 10
 	reconnectBackOffBaseSeconds = 3
+
+	// maximum number of rejections pending for the rejection callback, the
+	// others being dropped
+	rejectionQueueSize = 100
 )
 
 // MConnConfig returns an MConnConfig with fields updated
@@ -41,6 +48,10 @@ func MConnConfig(cfg *config.P2PConfig) conn.MConnConfig {
 	mConfig.FlushThrottle = cfg.FlushThrottleTimeout
 	mConfig.SendRate = cfg.SendRate
 	mConfig.RecvRate = cfg.RecvRate
+	// The rates are checked by P2PConfig.ValidateBasic.
+	mConfig.ChannelSendRates, _ = config.ParseChannelRates(cfg.ChannelSendRates)
+	// The compression is negotiated with each peer (see negotiateCompression).
+	mConfig.CompressionMinSize = cfg.CompressionMinSize
 	mConfig.MaxPacketMsgPayloadSize = cfg.MaxPacketMsgPayloadSize
 	mConfig.TestFuzz = cfg.TestFuzz
 	mConfig.TestFuzzConfig = cfg.TestFuzzConfig
@@ -57,6 +68,9 @@ type AddrBook interface {
 	AddOurAddress(*NetAddress)
 	OurAddress(*NetAddress) bool
 	MarkGood(ID)
+	MarkConnected(id ID, dialLatency time.Duration)
+	MarkMisbehaved(ID)
+	PeerScore(ID) PeerScore
 	RemoveAddress(*NetAddress)
 	HasAddress(*NetAddress) bool
 	Save()
@@ -84,20 +98,43 @@ type Switch struct {
 	peers         *PeerSet
 	dialing       *cmap.CMap
 	reconnecting  *cmap.CMap
-	nodeInfo      NodeInfo // our node info
 	nodeKey       *NodeKey // our node privkey
 	addrBook      AddrBook
+
+	nodeInfoMtx sync.RWMutex
+	nodeInfo    NodeInfo // our node info
+
+	// detects our external IP from the IPs our outbound peers observe, nil if
+	// disabled
+	externalIPs *externalIPDetector
 	// peers addresses with whom we'll maintain constant connection
 	persistentPeersAddrs []*NetAddress
+
+	unconditionalMtx     sync.RWMutex
 	unconditionalPeerIDs map[ID]struct{}
 
 	transport Transport
 
+	// limit the dial-backs verifying listen addresses, and the addresses
+	// reported live by peers, separately, so that peers reporting addresses
+	// can't prevent the verification of inbound peers
+	dialBacks         *dialBackLimiter
+	reportedDialBacks *dialBackLimiter
+
+	// reactors that peers of each role may use, nil for all of them
+	roleReactors map[string][]string
+
 	filterTimeout time.Duration
 	peerFilters   []PeerFilterFunc
+	rejectionFn   func(err ErrRejected, outbound bool)
+	rejections    chan peerRejection // pending calls to rejectionFn
 
 	rng *rand.Rand // seed for randomizing dial times and orders
 
+	// channel send rates set at runtime, applied to new peers
+	channelRatesMtx  sync.Mutex
+	channelSendRates map[byte]int64
+
 	metrics     *Metrics
 	mlc         *metricsLabelCache
 	traceClient trace.Tracer
@@ -134,6 +171,8 @@ func NewSwitch(
 		filterTimeout:        defaultFilterTimeout,
 		persistentPeersAddrs: make([]*NetAddress, 0),
 		unconditionalPeerIDs: make(map[ID]struct{}),
+		dialBacks:            newDialBackLimiter(0),
+		reportedDialBacks:    newDialBackLimiter(maxDialBacksPerSource),
 		mlc:                  newMetricsLabelCache(),
 		traceClient:          trace.NoOpTracer(),
 	}
@@ -141,6 +180,13 @@ func NewSwitch(
 	// Ensure we have a completely undeterministic PRNG.
 	sw.rng = rand.NewRand()
 
+	// The roles are checked by P2PConfig.ValidateBasic.
+	sw.roleReactors, _ = config.ParseRoleReactors(cfg.RoleReactors)
+
+	if cfg.DetectExternalAddress && cfg.ExternalAddress == "" {
+		sw.externalIPs = newExternalIPDetector()
+	}
+
 	sw.BaseService = *service.NewBaseService(nil, "P2P Switch", sw)
 
 	for _, option := range options {
@@ -160,6 +206,24 @@ func SwitchPeerFilters(filters ...PeerFilterFunc) SwitchOption {
 	return func(sw *Switch) { sw.peerFilters = filters }
 }
 
+// peerRejection is a rejection of a peer pending for the rejection callback.
+type peerRejection struct {
+	err      ErrRejected
+	outbound bool
+}
+
+// WithRejectionCallback sets a function called with the reason of each
+// rejection of a peer, inbound or outbound, for instance to publish it. It is
+// called in order from a routine of its own, so that a slow function doesn't
+// hold up accepting and dialing peers: rejections are dropped while
+// rejectionQueueSize of them are pending.
+func WithRejectionCallback(fn func(err ErrRejected, outbound bool)) SwitchOption {
+	return func(sw *Switch) {
+		sw.rejectionFn = fn
+		sw.rejections = make(chan peerRejection, rejectionQueueSize)
+	}
+}
+
 // WithMetrics sets the metrics.
 func WithMetrics(metrics *Metrics) SwitchOption {
 	return func(sw *Switch) { sw.metrics = metrics }
@@ -224,17 +288,91 @@ func (sw *Switch) Reactor(name string) Reactor {
 }
 
 // SetNodeInfo sets the switch's NodeInfo for checking compatibility and handshaking with other nodes.
-// NOTE: Not goroutine safe.
 func (sw *Switch) SetNodeInfo(nodeInfo NodeInfo) {
+	sw.nodeInfoMtx.Lock()
+	defer sw.nodeInfoMtx.Unlock()
 	sw.nodeInfo = nodeInfo
 }
 
 // NodeInfo returns the switch's NodeInfo.
-// NOTE: Not goroutine safe.
 func (sw *Switch) NodeInfo() NodeInfo {
+	sw.nodeInfoMtx.RLock()
+	defer sw.nodeInfoMtx.RUnlock()
 	return sw.nodeInfo
 }
 
+// SetExternalAddress sets the address (host:port) advertised to peers in our
+// NodeInfo, for them to dial us, e.g. once it is detected or mapped on the
+// gateway of the node. The address is also added to the address book as ours,
+// so that we don't dial ourselves.
+// NOTE: NodeInfo must be of type DefaultNodeInfo else it won't be updated
+func (sw *Switch) SetExternalAddress(addr string) error {
+	netAddr, err := NewNetAddressString(IDAddressString(sw.nodeKey.ID(), addr))
+	if err != nil {
+		return err
+	}
+
+	sw.nodeInfoMtx.Lock()
+	ni, ok := sw.nodeInfo.(DefaultNodeInfo)
+	if !ok || ni.ListenAddr == addr {
+		sw.nodeInfoMtx.Unlock()
+		return nil
+	}
+	ni.ListenAddr = addr
+	sw.nodeInfo = ni
+	sw.nodeInfoMtx.Unlock()
+
+	if t, ok := sw.transport.(interface{ SetListenAddr(addr string) }); ok {
+		t.SetListenAddr(addr)
+	}
+	if sw.addrBook != nil {
+		sw.addrBook.AddOurAddress(netAddr)
+	}
+	sw.Logger.Info("Advertising new external address", "addr", addr)
+	return nil
+}
+
+// AdvertiseExternalAddress sets addr (host:port) as our external address, as
+// SetExternalAddress, once it is verified to reach us: addr is dialed back,
+// from another goroutine, and the node listening there must authenticate with
+// our node ID. Behind a NAT, this requires the gateway to support hairpinning,
+// i.e. connections from the inside to its external address.
+func (sw *Switch) AdvertiseExternalAddress(addr string) error {
+	netAddr, err := NewNetAddressString(IDAddressString(sw.nodeKey.ID(), addr))
+	if err != nil {
+		return err
+	}
+	go func() {
+		if err := dialBack(netAddr, sw.config.DialTimeout); err != nil {
+			sw.Logger.Info("External address is unreachable, not advertising it", "addr", addr, "err", err)
+			return
+		}
+		if err := sw.SetExternalAddress(addr); err != nil {
+			sw.Logger.Error("Failed to set external address", "addr", addr, "err", err)
+		}
+	}()
+	return nil
+}
+
+// observeExternalIP records the IP the outbound peer p observed us connecting
+// from, and advertises it with our listen port once it is detected as our
+// external IP and verified to reach us.
+func (sw *Switch) observeExternalIP(p Peer) {
+	ip, ok := sw.externalIPs.observe(p.ID(), PeerObservedIP(p.NodeInfo()))
+	if !ok {
+		return
+	}
+	ourAddr, err := sw.NodeInfo().NetAddress()
+	if err != nil {
+		sw.Logger.Error("Failed to parse our listen address", "err", err)
+		return
+	}
+	addr := net.JoinHostPort(ip.String(), strconv.Itoa(int(ourAddr.Port)))
+	if err := sw.AdvertiseExternalAddress(addr); err != nil {
+		sw.Logger.Error("Failed to advertise detected external address", "addr", addr, "err", err)
+	}
+}
+
 // SetNodeKey sets the switch's private key for authenticated encryption.
 // NOTE: Not goroutine safe.
 func (sw *Switch) SetNodeKey(nodeKey *NodeKey) {
@@ -257,6 +395,10 @@ func (sw *Switch) OnStart() error {
 	// Start accepting Peers.
 	go sw.acceptRoutine()
 
+	if sw.rejectionFn != nil {
+		go sw.rejectionRoutine()
+	}
+
 	return nil
 }
 
@@ -338,6 +480,8 @@ func (sw *Switch) NumPeers() (outbound, inbound, dialing int) {
 }
 
 func (sw *Switch) IsPeerUnconditional(id ID) bool {
+	sw.unconditionalMtx.RLock()
+	defer sw.unconditionalMtx.RUnlock()
 	_, ok := sw.unconditionalPeerIDs[id]
 	return ok
 }
@@ -363,6 +507,13 @@ func (sw *Switch) StopPeerForError(peer Peer, reason interface{}, reactorName st
 	sw.Logger.Error("Stopping peer for error", "peer", peer, "err", reason, "reactor", reactorName)
 	sw.stopAndRemovePeer(peer, reason)
 
+	// Errors of the connection itself, reported as from "p2p", include plain
+	// disconnections, so only the errors reported by reactors count against
+	// the peer.
+	if sw.addrBook != nil && reactorName != "p2p" {
+		sw.addrBook.MarkMisbehaved(peer.ID())
+	}
+
 	if peer.IsPersistent() {
 		addr, err := sw.getPeerAddress(peer)
 		if err != nil {
@@ -425,6 +576,9 @@ func (sw *Switch) stopAndRemovePeer(peer Peer, reason interface{}) {
 	// https://github.com/tendermint/tendermint/issues/3338
 	if sw.peers.Remove(peer) {
 		sw.metrics.Peers.Add(float64(-1))
+		if sw.externalIPs != nil {
+			sw.externalIPs.remove(peer.ID())
+		}
 	} else {
 		// Removal of the peer has failed. The function above sets a flag within the peer to mark this.
 		// We keep this message here as information to the developer.
@@ -504,6 +658,165 @@ func (sw *Switch) MarkPeerAsGood(peer Peer) {
 	}
 }
 
+// VerifyListenAddr calls fn with whether addr, the listen address claimed by
+// an inbound peer, accepts connections from the node with addr.ID. If
+// dial-back is enabled (see config.P2PConfig.DialBackInbound), addr is
+// verified with VerifyAddr. Otherwise, fn is called right away with true.
+func (sw *Switch) VerifyListenAddr(addr *NetAddress, fn func(reachable bool)) {
+	if !sw.config.DialBackInbound {
+		fn(true)
+		return
+	}
+	sw.VerifyAddr(addr, fn)
+}
+
+// VerifyAddr calls fn, from another goroutine, with whether addr accepts
+// connections from the node with addr.ID: addr is dialed, and the node must
+// authenticate in the secret connection handshake. The verifications are
+// limited in number and rate: fn is called right away with false when the
+// limits are reached.
+func (sw *Switch) VerifyAddr(addr *NetAddress, fn func(reachable bool)) {
+	sw.verifyAddr(sw.dialBacks, "", addr, fn)
+}
+
+// VerifyReportedAddr is like VerifyAddr, for an address which the peer src
+// reported, e.g. as recently live. These verifications have their own limits,
+// and each peer may only have a few of them in progress at the same time.
+func (sw *Switch) VerifyReportedAddr(src ID, addr *NetAddress, fn func(reachable bool)) {
+	sw.verifyAddr(sw.reportedDialBacks, src, addr, fn)
+}
+
+func (sw *Switch) verifyAddr(limiter *dialBackLimiter, src ID, addr *NetAddress, fn func(reachable bool)) {
+	if !limiter.acquire(src) {
+		sw.Logger.Debug("Too many addresses to verify, skipping", "addr", addr, "src", src)
+		fn(false)
+		return
+	}
+	go func() {
+		defer limiter.release(src)
+		if err := dialBack(addr, sw.config.DialTimeout); err != nil {
+			sw.Logger.Debug("Address is unreachable", "addr", addr, "err", err)
+			fn(false)
+			return
+		}
+		fn(true)
+	}()
+}
+
+// PeerScore returns the score of the peer with the given ID kept in the
+// address book, or a zero score if there is no address book.
+func (sw *Switch) PeerScore(id ID) PeerScore {
+	if sw.addrBook == nil {
+		return PeerScore{}
+	}
+	return sw.addrBook.PeerScore(id)
+}
+
+// channelRateSetter is implemented by peers whose channels can be rate
+// limited.
+type channelRateSetter interface {
+	SetChannelSendRate(chID byte, rate int64) bool
+}
+
+// SetChannelSendRates sets the rates at which packets can be sent on the given
+// channels, in bytes/second, to all the peers, including those connected
+// later. A rate of 0 removes the limit of a channel. The rates of the other
+// channels are left unchanged.
+func (sw *Switch) SetChannelSendRates(rates map[byte]int64) {
+	sw.channelRatesMtx.Lock()
+	defer sw.channelRatesMtx.Unlock()
+	if sw.channelSendRates == nil {
+		sw.channelSendRates = make(map[byte]int64, len(rates))
+	}
+	for chID, rate := range rates {
+		sw.channelSendRates[chID] = rate
+	}
+	for _, p := range sw.peers.List() {
+		sw.applyChannelSendRates(p, rates)
+	}
+	sw.Logger.Info("Set channel send rates", "rates", rates)
+}
+
+// applyChannelSendRates sets the given channel send rates on the peer, if it
+// supports them.
+func (sw *Switch) applyChannelSendRates(p Peer, rates map[byte]int64) {
+	s, ok := p.(channelRateSetter)
+	if !ok {
+		return
+	}
+	for chID, rate := range rates {
+		s.SetChannelSendRate(chID, rate)
+	}
+}
+
+// reactorAllowed returns true if peers of the given role may use the reactor
+// with the given name.
+func (sw *Switch) reactorAllowed(role, name string) bool {
+	reactors := sw.roleReactors[role]
+	if reactors == nil {
+		return true
+	}
+	return cmtstrings.StringInSlice(name, reactors)
+}
+
+// peerReactorAllowed returns true if the peer with the given NodeInfo may use
+// the reactor with the given name according to the role it advertises. Only
+// persistent and unconditional peers are trusted with their role: the others
+// may not use reactors full nodes may not use, whatever role they claim.
+func (sw *Switch) peerReactorAllowed(ni NodeInfo, persistent bool, name string) bool {
+	if !sw.reactorAllowed(PeerRole(ni), name) {
+		return false
+	}
+	return persistent || sw.IsPeerUnconditional(ni.ID()) || sw.reactorAllowed(config.P2PRoleFull, name)
+}
+
+// reactorsForPeer returns the reactors, by channel, the peer with the given
+// NodeInfo may use according to its role. Messages on the other channels are
+// rejected.
+func (sw *Switch) reactorsForPeer(ni NodeInfo, persistent bool) map[byte]Reactor {
+	if sw.roleReactors[PeerRole(ni)] == nil && sw.roleReactors[config.P2PRoleFull] == nil {
+		return sw.reactorsByCh
+	}
+	reactorsByCh := make(map[byte]Reactor)
+	for name, reactor := range sw.reactors {
+		if !sw.peerReactorAllowed(ni, persistent, name) {
+			continue
+		}
+		for _, chDesc := range reactor.GetChannels() {
+			// with its middleware, if any
+			reactorsByCh[chDesc.ID] = sw.reactorsByCh[chDesc.ID]
+		}
+	}
+	return reactorsByCh
+}
+
+// shieldValidatorPeer makes a persistent peer advertising the validator role
+// private and unconditional if we are a sentry, so that the address of the
+// validator isn't gossiped and that it is always accepted.
+func (sw *Switch) shieldValidatorPeer(p Peer) {
+	if sw.config.NodeRole() != config.P2PRoleSentry ||
+		PeerRole(p.NodeInfo()) != config.P2PRoleValidator ||
+		!p.IsPersistent() {
+		return
+	}
+	if sw.addrBook != nil {
+		sw.addrBook.AddPrivateIDs([]string{string(p.ID())})
+	}
+	sw.unconditionalMtx.Lock()
+	defer sw.unconditionalMtx.Unlock()
+	if _, ok := sw.unconditionalPeerIDs[p.ID()]; !ok {
+		sw.Logger.Info("Shielding validator peer", "peer", p.ID())
+		sw.unconditionalPeerIDs[p.ID()] = struct{}{}
+	}
+}
+
+// markConnected records a connection to the peer in the address book, if any.
+func (sw *Switch) markConnected(p Peer, dialLatency time.Duration) {
+	if sw.addrBook != nil {
+		sw.addrBook.MarkConnected(p.ID(), dialLatency)
+	}
+}
+
 //---------------------------------------------------------------------
 // Dialing
 
@@ -643,6 +956,8 @@ func (sw *Switch) AddPersistentPeers(addrs []string) error {
 
 func (sw *Switch) AddUnconditionalPeerIDs(ids []string) error {
 	sw.Logger.Info("Adding unconditional peer ids", "ids", ids)
+	sw.unconditionalMtx.Lock()
+	defer sw.unconditionalMtx.Unlock()
 	for i, id := range ids {
 		err := validateID(ID(id))
 		if err != nil {
@@ -680,13 +995,14 @@ func (sw *Switch) IsPeerPersistent(na *NetAddress) bool {
 func (sw *Switch) acceptRoutine() {
 	for {
 		p, err := sw.transport.Accept(peerConfig{
-			chDescs:       sw.chDescs,
-			onPeerError:   sw.StopPeerForError,
-			reactorsByCh:  sw.reactorsByCh,
-			msgTypeByChID: sw.msgTypeByChID,
-			metrics:       sw.metrics,
-			mlc:           sw.mlc,
-			isPersistent:  sw.IsPeerPersistent,
+			chDescs:         sw.chDescs,
+			onPeerError:     sw.StopPeerForError,
+			reactorsByCh:    sw.reactorsByCh,
+			reactorsForPeer: sw.reactorsForPeer,
+			msgTypeByChID:   sw.msgTypeByChID,
+			metrics:         sw.metrics,
+			mlc:             sw.mlc,
+			isPersistent:    sw.IsPeerPersistent,
 		})
 		if err != nil {
 			switch err := err.(type) {
@@ -704,6 +1020,7 @@ func (sw *Switch) acceptRoutine() {
 					"err", err,
 					"numPeers", sw.peers.Size(),
 				)
+				sw.peerRejected(err, false)
 
 				continue
 			case ErrFilterTimeout:
@@ -735,6 +1052,8 @@ func (sw *Switch) acceptRoutine() {
 			break
 		}
 
+		sw.shieldValidatorPeer(p)
+
 		if !sw.IsPeerUnconditional(p.NodeInfo().ID()) {
 			// Ignore connection if we already have enough peers.
 			_, in, _ := sw.NumPeers()
@@ -763,6 +1082,40 @@ func (sw *Switch) acceptRoutine() {
 				"err", err,
 				"id", p.ID(),
 			)
+			sw.peerRejected(err, false)
+			continue
+		}
+		sw.markConnected(p, 0)
+	}
+}
+
+// peerRejected counts the rejection of a peer, if err is an ErrRejected, and
+// reports it to the rejection callback, if any.
+func (sw *Switch) peerRejected(err error, outbound bool) {
+	e, ok := err.(ErrRejected)
+	if !ok {
+		return
+	}
+	sw.metrics.PeerRejections.With("reason", e.Reason()).Add(1)
+	if sw.rejectionFn == nil {
+		return
+	}
+	select {
+	case sw.rejections <- peerRejection{e, outbound}:
+	default:
+		sw.Logger.Debug("Dropping peer rejection, too many pending", "err", e)
+	}
+}
+
+// rejectionRoutine calls the rejection callback with the pending rejections
+// until the switch stops.
+func (sw *Switch) rejectionRoutine() {
+	for {
+		select {
+		case r := <-sw.rejections:
+			sw.rejectionFn(r.err, r.outbound)
+		case <-sw.Quit():
+			return
 		}
 	}
 }
@@ -784,16 +1137,19 @@ func (sw *Switch) addOutboundPeerWithConfig(
 		return fmt.Errorf("dial err (peerConfig.DialFail == true)")
 	}
 
+	start := time.Now()
 	p, err := sw.transport.Dial(*addr, peerConfig{
-		chDescs:       sw.chDescs,
-		onPeerError:   sw.StopPeerForError,
-		isPersistent:  sw.IsPeerPersistent,
-		reactorsByCh:  sw.reactorsByCh,
-		msgTypeByChID: sw.msgTypeByChID,
-		metrics:       sw.metrics,
-		mlc:           sw.mlc,
+		chDescs:         sw.chDescs,
+		onPeerError:     sw.StopPeerForError,
+		isPersistent:    sw.IsPeerPersistent,
+		reactorsByCh:    sw.reactorsByCh,
+		reactorsForPeer: sw.reactorsForPeer,
+		msgTypeByChID:   sw.msgTypeByChID,
+		metrics:         sw.metrics,
+		mlc:             sw.mlc,
 	})
 	if err != nil {
+		sw.peerRejected(err, true)
 		if e, ok := err.(ErrRejected); ok {
 			if e.IsSelf() {
 				// Remove the given address from the address book and add to our addresses
@@ -814,13 +1170,17 @@ func (sw *Switch) addOutboundPeerWithConfig(
 		return err
 	}
 
+	dialLatency := time.Since(start)
+
 	if err := sw.addPeer(p); err != nil {
 		sw.transport.Cleanup(p)
 		if p.IsRunning() {
 			_ = p.Stop()
 		}
+		sw.peerRejected(err, true)
 		return err
 	}
+	sw.markConnected(p, dialLatency)
 
 	return nil
 }
@@ -828,7 +1188,7 @@ func (sw *Switch) addOutboundPeerWithConfig(
 func (sw *Switch) filterPeer(p Peer) error {
 	// Avoid duplicate
 	if sw.peers.Has(p.ID()) {
-		return ErrRejected{id: p.ID(), isDuplicate: true}
+		return NewErrRejectedBuilder().DuplicateID(p.ID()).Build()
 	}
 
 	errc := make(chan error, len(sw.peerFilters))
@@ -843,7 +1203,7 @@ func (sw *Switch) filterPeer(p Peer) error {
 		select {
 		case err := <-errc:
 			if err != nil {
-				return ErrRejected{id: p.ID(), err: err, isFiltered: true}
+				return NewErrRejectedBuilder().WithID(p.ID()).Filtered(err).Build()
 			}
 		case <-time.After(sw.filterTimeout):
 			return ErrFilterTimeout{}
@@ -862,6 +1222,9 @@ func (sw *Switch) addPeer(p Peer) error {
 
 	p.SetLogger(sw.Logger.With("peer", p.SocketAddr()))
 
+	sw.shieldValidatorPeer(p)
+	ni, persistent := p.NodeInfo(), p.IsPersistent()
+
 	// Handle the shut down case where the switch has stopped but we're
 	// concurrently trying to add a peer.
 	if !sw.IsRunning() {
@@ -871,8 +1234,10 @@ func (sw *Switch) addPeer(p Peer) error {
 	}
 
 	// Add some data to the peer, which is required by reactors.
-	for _, reactor := range sw.reactors {
-		p = reactor.InitPeer(p)
+	for name, reactor := range sw.reactors {
+		if sw.peerReactorAllowed(ni, persistent, name) {
+			p = reactor.InitPeer(p)
+		}
 	}
 
 	// Start the peer's send/recv routines.
@@ -897,12 +1262,22 @@ func (sw *Switch) addPeer(p Peer) error {
 		}
 		return err
 	}
+	sw.channelRatesMtx.Lock()
+	sw.applyChannelSendRates(p, sw.channelSendRates)
+	sw.channelRatesMtx.Unlock()
 	sw.metrics.Peers.Add(float64(1))
 	schema.WritePeerUpdate(sw.traceClient, string(p.ID()), schema.PeerJoin, "")
 
+	if sw.externalIPs != nil && p.IsOutbound() {
+		sw.observeExternalIP(p)
+	}
+
 	// Start all the reactor protocols on the peer.
 	peerWanted := false
-	for _, reactor := range sw.reactors {
+	for name, reactor := range sw.reactors {
+		if !sw.peerReactorAllowed(ni, persistent, name) {
+			continue
+		}
 		if err := reactor.AddPeer(p); err != nil {
 			sw.Logger.Info("Reactor rejected peer", "peer", p, "err", err, "reactor", reactor.String())
 			continue
@@ -10,6 +10,7 @@ import (
 	"net/http/httptest"
 	"regexp"
 	"strconv"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -625,6 +626,119 @@ func TestSwitchFullConnectivity(t *testing.T) {
 	}
 }
 
+func TestSwitchSetChannelSendRates(t *testing.T) {
+	s1, s2 := MakeSwitchPair(initSwitchFunc)
+	t.Cleanup(func() {
+		if err := s1.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+	t.Cleanup(func() {
+		if err := s2.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	s1.SetChannelSendRates(map[byte]int64{0x01: 1000, 0x02: 2000})
+	s1.SetChannelSendRates(map[byte]int64{0x02: 0})
+
+	peers := s1.Peers().List()
+	require.Len(t, peers, 1)
+	rates := make(map[byte]int64)
+	for _, ch := range peers[0].Status().Channels {
+		rates[ch.ID] = ch.SendRate
+	}
+	assert.Equal(t, map[byte]int64{0x00: 0, 0x01: 1000, 0x02: 0, 0x03: 0}, rates)
+	assert.Equal(t, map[byte]int64{0x01: 1000, 0x02: 0}, s1.channelSendRates)
+}
+
+func TestSwitchRoleReactors(t *testing.T) {
+	cfg := config.DefaultP2PConfig()
+	cfg.AllowDuplicateIP = true
+	// Full nodes may only use the "foo" reactor.
+	cfg.RoleReactors = "full:foo"
+	switches := MakeConnectedSwitches(cfg, 2, initSwitchFunc, Connect2Switches)
+	s1, s2 := switches[0], switches[1]
+	t.Cleanup(func() {
+		for _, sw := range switches {
+			if err := sw.Stop(); err != nil {
+				t.Error(err)
+			}
+		}
+	})
+
+	require.Equal(t, 1, s1.Peers().Size())
+	assert.Len(t, s1.peerSetByChID[0x00].List(), 1)
+	assert.Empty(t, s1.peerSetByChID[0x02].List())
+	reactorsByCh := s1.reactorsForPeer(s2.NodeInfo(), false)
+	assert.Len(t, reactorsByCh, 2)
+	assert.Equal(t, s1.Reactor("foo"), reactorsByCh[0x00])
+
+	// Messages to the "bar" reactor get the peer disconnected.
+	p := s2.Peers().List()[0]
+	p.Send(Envelope{ChannelID: 0x02, Message: &p2pproto.Message{}})
+	assert.Eventually(t, func() bool { return s1.Peers().Size() == 0 }, 3*time.Second, 10*time.Millisecond)
+}
+
+func TestSwitchRoleClaims(t *testing.T) {
+	cfg := config.DefaultP2PConfig()
+	// Full nodes may only use the "foo" reactor, validators all of them.
+	cfg.RoleReactors = "full:foo;validator:*"
+	sw := MakeSwitch(cfg, 1, initSwitchFunc)
+
+	id := ID(strings.Repeat("a", 40))
+	validator := DefaultNodeInfo{DefaultNodeID: id, Other: DefaultNodeInfoOther{Role: config.P2PRoleValidator}}
+
+	// Peers claiming to be validators are treated as full nodes...
+	assert.Len(t, sw.reactorsForPeer(validator, false), 2)
+	assert.False(t, sw.peerReactorAllowed(validator, false, "bar"))
+
+	// ...unless they are persistent or unconditional.
+	assert.Len(t, sw.reactorsForPeer(validator, true), 4)
+	require.NoError(t, sw.AddUnconditionalPeerIDs([]string{string(id)}))
+	assert.Len(t, sw.reactorsForPeer(validator, false), 4)
+	assert.True(t, sw.peerReactorAllowed(validator, false, "bar"))
+
+	// Claims restricting the peer are always honoured.
+	cfg.RoleReactors = "seed:foo"
+	sw = MakeSwitch(cfg, 1, initSwitchFunc)
+	seed := DefaultNodeInfo{DefaultNodeID: id, Other: DefaultNodeInfoOther{Role: config.P2PRoleSeed}}
+	assert.Len(t, sw.reactorsForPeer(seed, false), 2)
+}
+
+// rolePeer is a mock peer advertising a role.
+type rolePeer struct {
+	*mockPeer
+	role string
+}
+
+func (p rolePeer) NodeInfo() NodeInfo {
+	return DefaultNodeInfo{Other: DefaultNodeInfoOther{Role: p.role}}
+}
+
+func TestSwitchShieldValidatorPeer(t *testing.T) {
+	cfg := config.DefaultP2PConfig()
+	sw := MakeSwitch(cfg, 1, initSwitchFunc)
+	book := sw.addrBook.(*AddrBookMock)
+	book.PrivateAddrs = make(map[string]struct{})
+
+	// Full nodes don't shield their validator peers.
+	validator := rolePeer{newMockPeer(nil), config.P2PRoleValidator}
+	sw.shieldValidatorPeer(validator)
+	assert.False(t, sw.IsPeerUnconditional(validator.ID()))
+
+	// Sentries only shield their validator peers.
+	cfg.Role = config.P2PRoleSentry
+	full := rolePeer{newMockPeer(nil), ""}
+	sw.shieldValidatorPeer(full)
+	assert.False(t, sw.IsPeerUnconditional(full.ID()))
+	assert.NotContains(t, book.PrivateAddrs, string(full.ID()))
+
+	sw.shieldValidatorPeer(validator)
+	assert.True(t, sw.IsPeerUnconditional(validator.ID()))
+	assert.Contains(t, book.PrivateAddrs, string(validator.ID()))
+}
+
 func TestSwitchAcceptRoutine(t *testing.T) {
 	cfg.MaxNumInboundPeers = 5
 
@@ -763,6 +877,237 @@ func TestSwitchAcceptRoutineErrorCases(t *testing.T) {
 	})
 }
 
+func TestSwitchRejectionCallback(t *testing.T) {
+	rejected := NewErrRejectedBuilder().
+		Incompatible(errors.New("incompatible")).
+		WithChainIDs("chain-a", "chain-b").
+		Build()
+
+	type rejection struct {
+		err      ErrRejected
+		outbound bool
+	}
+	rejections := make(chan rejection, 1)
+	sw := NewSwitch(cfg, errorTransport{rejected}, WithRejectionCallback(func(err ErrRejected, outbound bool) {
+		select {
+		case rejections <- rejection{err, outbound}:
+		default:
+		}
+	}))
+	require.NoError(t, sw.Start())
+	t.Cleanup(func() {
+		if err := sw.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	select {
+	case r := <-rejections:
+		assert.False(t, r.outbound)
+		assert.Equal(t, RejectReasonChainIDMismatch, r.err.Reason())
+		assert.Equal(t, "chain-b", r.err.PeerChainID())
+	case <-time.After(time.Second):
+		t.Fatal("rejection callback not called")
+	}
+}
+
+func TestSwitchRejectionCallbackDoesNotBlock(t *testing.T) {
+	unblock := make(chan struct{})
+	sw := NewSwitch(cfg, errorTransport{ErrTransportClosed{}}, WithRejectionCallback(func(ErrRejected, bool) {
+		<-unblock
+	}))
+	require.NoError(t, sw.Start())
+	t.Cleanup(func() {
+		close(unblock)
+		if err := sw.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	// Rejections past the queue size are dropped instead of blocking.
+	rejected := NewErrRejectedBuilder().Incompatible(errors.New("incompatible")).Build()
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 2*rejectionQueueSize; i++ {
+			sw.peerRejected(rejected, true)
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("rejecting peers blocked on the callback")
+	}
+}
+
+func TestSwitchVerifyListenAddr(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	nodeKey := NodeKey{PrivKey: ed25519.GenPrivKey()}
+	addr, err := NewNetAddressString(IDAddressString(nodeKey.ID(), ln.Addr().String()))
+	require.NoError(t, err)
+	go func() {
+		for {
+			c, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			_, _ = conn.MakeSecretConnection(c, nodeKey.PrivKey)
+			c.Close()
+		}
+	}()
+
+	verify := func(sw *Switch, addr *NetAddress) bool {
+		// Don't let the rate limit get in the way.
+		sw.dialBacks = newDialBackLimiter(0)
+		res := make(chan bool, 1)
+		sw.VerifyListenAddr(addr, func(reachable bool) { res <- reachable })
+		select {
+		case reachable := <-res:
+			return reachable
+		case <-time.After(5 * time.Second):
+			t.Fatal("listen address not verified")
+			return false
+		}
+	}
+
+	conf := *cfg
+	conf.DialBackInbound = true
+	conf.DialTimeout = time.Second
+	sw := NewSwitch(&conf, errorTransport{})
+	sw.SetLogger(log.TestingLogger())
+	assert.True(t, verify(sw, addr))
+
+	// The node listening must authenticate as the peer claiming the address.
+	otherKey := NodeKey{PrivKey: ed25519.GenPrivKey()}
+	other, err := NewNetAddressString(IDAddressString(otherKey.ID(), ln.Addr().String()))
+	require.NoError(t, err)
+	assert.False(t, verify(sw, other))
+
+	require.NoError(t, ln.Close())
+	assert.False(t, verify(sw, addr))
+
+	// Without dial-back, listen addresses are assumed to be reachable.
+	sw = NewSwitch(cfg, errorTransport{})
+	assert.True(t, verify(sw, addr))
+}
+
+func TestDialBackLimiter(t *testing.T) {
+	l := newDialBackLimiter(0)
+	for i := 0; i < maxConcurrentDialBacks; i++ {
+		require.True(t, l.acquire(""))
+	}
+	// Dial-backs are limited in number...
+	l.refilled = l.refilled.Add(-dialBackInterval)
+	assert.False(t, l.acquire(""))
+	l.release("")
+	assert.True(t, l.acquire(""))
+
+	// ...and in rate.
+	l.release("")
+	assert.False(t, l.acquire(""))
+	l.refilled = l.refilled.Add(-dialBackInterval)
+	assert.True(t, l.acquire(""))
+}
+
+func TestDialBackLimiter_perSource(t *testing.T) {
+	l := newDialBackLimiter(maxDialBacksPerSource)
+	for i := 0; i < maxDialBacksPerSource; i++ {
+		require.True(t, l.acquire("a"))
+	}
+	// A peer can't use up the dial-backs of the others.
+	assert.False(t, l.acquire("a"))
+	assert.True(t, l.acquire("b"))
+	l.release("a")
+	assert.True(t, l.acquire("a"))
+}
+
+func TestSwitchVerifyReportedAddr(t *testing.T) {
+	sw := NewSwitch(cfg, errorTransport{})
+	sw.SetLogger(log.TestingLogger())
+	addr, err := NewNetAddressString(IDAddressString(PubKeyToID(ed25519.GenPrivKey().PubKey()), "127.0.0.1:1"))
+	require.NoError(t, err)
+
+	// The verifications of reported addresses don't use up those of listen
+	// addresses.
+	for i := 0; i < maxConcurrentDialBacks; i++ {
+		require.True(t, sw.reportedDialBacks.acquire(ID(strconv.Itoa(i))))
+	}
+	res := make(chan bool, 1)
+	sw.VerifyReportedAddr("src", addr, func(reachable bool) { res <- reachable })
+	assert.False(t, <-res)
+	assert.True(t, sw.dialBacks.acquire(""))
+}
+
+func TestSwitchSetExternalAddress(t *testing.T) {
+	nodeKey := NodeKey{PrivKey: ed25519.GenPrivKey()}
+	nodeInfo := testNodeInfo(nodeKey.ID(), "node")
+	mt := newMultiplexTransport(nodeInfo, nodeKey)
+	addr, err := NewNetAddressString(IDAddressString(nodeKey.ID(), "127.0.0.1:0"))
+	require.NoError(t, err)
+	require.NoError(t, mt.Listen(*addr))
+	t.Cleanup(func() { _ = mt.Close() })
+	sw := NewSwitch(cfg, mt)
+	sw.SetLogger(log.TestingLogger())
+	sw.SetNodeKey(&nodeKey)
+	sw.SetNodeInfo(nodeInfo)
+
+	assert.Error(t, sw.SetExternalAddress("not an address"))
+	require.NoError(t, sw.SetExternalAddress("198.51.100.1:26656"))
+	assert.Equal(t, "198.51.100.1:26656", sw.NodeInfo().(DefaultNodeInfo).ListenAddr)
+
+	// Peers receive the new address, and the IP they connect from.
+	dialerKey := NodeKey{PrivKey: ed25519.GenPrivKey()}
+	dialer := newMultiplexTransport(testNodeInfo(dialerKey.ID(), "dialer"), dialerKey)
+	accepted := make(chan Peer, 1)
+	go func() {
+		p, err := mt.Accept(peerConfig{})
+		assert.NoError(t, err)
+		accepted <- p
+	}()
+	p, err := dialer.Dial(*NewNetAddress(nodeKey.ID(), mt.listener.Addr()), peerConfig{})
+	require.NoError(t, err)
+	assert.Equal(t, "198.51.100.1:26656", p.NodeInfo().(DefaultNodeInfo).ListenAddr)
+	assert.Equal(t, "127.0.0.1", PeerObservedIP(p.NodeInfo()).String())
+	select {
+	case p := <-accepted:
+		assert.Equal(t, "127.0.0.1", PeerObservedIP(p.NodeInfo()).String())
+	case <-time.After(5 * time.Second):
+		t.Fatal("peer not accepted")
+	}
+}
+
+func TestSwitchAdvertiseExternalAddress(t *testing.T) {
+	nodeKey := NodeKey{PrivKey: ed25519.GenPrivKey()}
+	nodeInfo := testNodeInfo(nodeKey.ID(), "node")
+	mt := newMultiplexTransport(nodeInfo, nodeKey)
+	addr, err := NewNetAddressString(IDAddressString(nodeKey.ID(), "127.0.0.1:0"))
+	require.NoError(t, err)
+	require.NoError(t, mt.Listen(*addr))
+	t.Cleanup(func() { _ = mt.Close() })
+	conf := *cfg
+	conf.DialTimeout = time.Second
+	sw := NewSwitch(&conf, mt)
+	sw.SetLogger(log.TestingLogger())
+	sw.SetNodeKey(&nodeKey)
+	sw.SetNodeInfo(nodeInfo)
+	listenAddr := func() string { return sw.NodeInfo().(DefaultNodeInfo).ListenAddr }
+
+	// Addresses that don't reach us aren't advertised.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	unreachable := ln.Addr().String()
+	require.NoError(t, ln.Close())
+	require.NoError(t, sw.AdvertiseExternalAddress(unreachable))
+	assert.Never(t, func() bool { return listenAddr() == unreachable }, 500*time.Millisecond, 10*time.Millisecond)
+
+	reachable := mt.listener.Addr().String()
+	require.NoError(t, sw.AdvertiseExternalAddress(reachable))
+	assert.Eventually(t, func() bool { return listenAddr() == reachable }, 5*time.Second, 10*time.Millisecond)
+
+	assert.Error(t, sw.AdvertiseExternalAddress("not an address"))
+}
+
 // mockReactor checks that InitPeer never called before RemovePeer. If that's
 // not true, InitCalledBeforeRemoveFinished will return true.
 type mockReactor struct {
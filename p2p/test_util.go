@@ -271,8 +271,9 @@ func testNodeInfoWithNetwork(id ID, name, network string) NodeInfo {
 		Channels:        []byte{testCh},
 		Moniker:         name,
 		Other: DefaultNodeInfoOther{
-			TxIndex:    "on",
-			RPCAddress: fmt.Sprintf("127.0.0.1:%d", getFreePort()),
+			TxIndex:     "on",
+			RPCAddress:  fmt.Sprintf("127.0.0.1:%d", getFreePort()),
+			Compression: SupportedCompression,
 		},
 	}
 }
@@ -302,7 +303,10 @@ func (book *AddrBookMock) OurAddress(addr *NetAddress) bool {
 	_, ok := book.OurAddrs[addr.String()]
 	return ok
 }
-func (book *AddrBookMock) MarkGood(ID) {}
+func (book *AddrBookMock) MarkGood(ID)                     {}
+func (book *AddrBookMock) MarkConnected(ID, time.Duration) {}
+func (book *AddrBookMock) MarkMisbehaved(ID)               {}
+func (book *AddrBookMock) PeerScore(ID) PeerScore          { return PeerScore{} }
 func (book *AddrBookMock) HasAddress(addr *NetAddress) bool {
 	_, ok := book.Addrs[addr.String()]
 	return ok
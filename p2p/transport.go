@@ -6,6 +6,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"net"
+	"sync"
 	"time"
 
 	"golang.org/x/net/netutil"
@@ -15,6 +16,7 @@ import (
 	"github.com/cometbft/cometbft/crypto"
 	"github.com/cometbft/cometbft/libs/protoio"
 	"github.com/cometbft/cometbft/libs/trace"
+	"github.com/cometbft/cometbft/libs/trace/schema"
 	"github.com/cometbft/cometbft/p2p/conn"
 	tmp2p "github.com/cometbft/cometbft/proto/tendermint/p2p"
 )
@@ -57,6 +59,9 @@ type peerConfig struct {
 	msgTypeByChID map[byte]proto.Message
 	metrics       *Metrics
 	mlc           *metricsLabelCache
+	// reactorsForPeer, if set, returns the reactors a peer may use, given its
+	// NodeInfo and whether it is persistent, instead of reactorsByCh.
+	reactorsForPeer func(ni NodeInfo, persistent bool) map[byte]Reactor
 }
 
 // Transport emits and connects to Peers. The implementation of Peer is left to
@@ -94,17 +99,90 @@ func generateTraceID() string {
 	return hex.EncodeToString(b)
 }
 
+// Stages of the handshake with peers, traced in the handshakes table and
+// passed to MultiplexTransportHandshakeCallback. A failed stage ends the
+// handshake with an ErrRejected detailing the step of the stage which failed
+// (see ErrRejected.HandshakeStage).
+const (
+	// The secret connection is established and the key of the peer checked.
+	HandshakeStageSecretConn = "secret_conn"
+	// The node infos are exchanged.
+	HandshakeStageNodeInfo = "node_info"
+	// The node info of the peer is validated and its versions checked
+	// against ours.
+	HandshakeStageVerify = "verify"
+)
+
+// HandshakeStageTrace is the outcome of a stage of the handshake with a peer.
+type HandshakeStageTrace struct {
+	// TraceID is shared by the stages of a handshake, and by the ErrRejected
+	// it failed with, if any.
+	TraceID    string
+	Stage      string // one of the HandshakeStage values
+	PeerID     ID     // empty if the secret connection failed early
+	RemoteAddr string
+	Outbound   bool
+	Duration   time.Duration
+	Err        error // nil if the stage succeeded
+}
+
+// handshakeTracer traces the stages of a handshake, each one lasting from the
+// end of the previous one.
+type handshakeTracer struct {
+	mt         *MultiplexTransport
+	traceID    string
+	remoteAddr string
+	outbound   bool
+	stageStart time.Time
+}
+
+func (mt *MultiplexTransport) newHandshakeTracer(c net.Conn, traceID string, outbound bool) *handshakeTracer {
+	return &handshakeTracer{
+		mt:         mt,
+		traceID:    traceID,
+		remoteAddr: c.RemoteAddr().String(),
+		outbound:   outbound,
+		stageStart: time.Now(),
+	}
+}
+
+// trace records the outcome of stage with the peer, and returns err, nil if
+// the stage succeeded.
+func (ht *handshakeTracer) trace(stage string, peerID ID, err error) error {
+	now := time.Now()
+	st := HandshakeStageTrace{
+		TraceID:    ht.traceID,
+		Stage:      stage,
+		PeerID:     peerID,
+		RemoteAddr: ht.remoteAddr,
+		Outbound:   ht.outbound,
+		Duration:   now.Sub(ht.stageStart),
+		Err:        err,
+	}
+	ht.stageStart = now
+
+	var errStr string
+	if err != nil {
+		errStr = err.Error()
+	}
+	schema.WriteHandshake(ht.mt.tracer, st.TraceID, stage, string(peerID), st.RemoteAddr,
+		st.Outbound, st.Duration, errStr)
+	if ht.mt.handshakeFn != nil {
+		ht.mt.handshakeFn(st)
+	}
+	return err
+}
+
 // ConnDuplicateIPFilter resolves and keeps all ips for an incoming connection
 // and refuses new ones if they come from a known ip.
 func ConnDuplicateIPFilter() ConnFilterFunc {
 	return func(cs ConnSet, c net.Conn, ips []net.IP) error {
 		for _, ip := range ips {
 			if cs.HasIP(ip) {
-				return ErrRejected{
-					conn:        c,
-					err:         fmt.Errorf("ip<%v> already connected", ip),
-					isDuplicate: true,
-				}
+				return NewErrRejectedBuilder().
+					Duplicate(c).
+					WithErr(fmt.Errorf("ip<%v> already connected", ip)).
+					Build()
 			}
 		}
 
@@ -143,6 +221,27 @@ func MultiplexTransportMaxIncomingConnections(n int) MultiplexTransportOption {
 	return func(mt *MultiplexTransport) { mt.maxIncomingConnections = n }
 }
 
+// MultiplexTransportHandshakeTimeout sets the timeout of each stage of the
+// handshake with peers: the secret connection and the node info exchange.
+func MultiplexTransportHandshakeTimeout(timeout time.Duration) MultiplexTransportOption {
+	return func(mt *MultiplexTransport) { mt.handshakeTimeout = timeout }
+}
+
+// MultiplexTransportHandshakeCallback sets a function called with the
+// outcome of each stage of the handshake with peers, e.g. to debug chronic
+// dial failures. It is called synchronously, so it must not block.
+func MultiplexTransportHandshakeCallback(fn func(HandshakeStageTrace)) MultiplexTransportOption {
+	return func(mt *MultiplexTransport) { mt.handshakeFn = fn }
+}
+
+// MultiplexTransportCompression sets the comma separated list of the
+// compression algorithms to compress the messages sent to peers with, in order
+// of preference. Each peer is sent messages compressed with the first of them
+// it supports (see negotiateCompression).
+func MultiplexTransportCompression(algos string) MultiplexTransportOption {
+	return func(mt *MultiplexTransport) { mt.compression = algos }
+}
+
 // MultiplexTransport accepts and dials tcp connections and upgrades them to
 // multiplexed peers.
 type MultiplexTransport struct {
@@ -160,16 +259,21 @@ type MultiplexTransport struct {
 	dialTimeout      time.Duration
 	filterTimeout    time.Duration
 	handshakeTimeout time.Duration
-	nodeInfo         NodeInfo
+	compression      string // algorithms to compress messages with, see MultiplexTransportCompression
 	nodeKey          NodeKey
 	resolver         IPResolver
 
+	nodeInfoMtx sync.RWMutex
+	nodeInfo    NodeInfo
+
 	// TODO(xla): This config is still needed as we parameterise peerConn and
 	// peer currently. All relevant configuration should be refactored into options
 	// with sane defaults.
 	mConfig conn.MConnConfig
 	// the tracer is passed to peers for collecting trace data
 	tracer trace.Tracer
+	// called with the outcome of each stage of the handshakes, if set
+	handshakeFn func(HandshakeStageTrace)
 }
 
 // Test multiplexTransport for interface completeness.
@@ -288,6 +392,8 @@ func (mt *MultiplexTransport) Listen(addr NetAddress) error {
 // This is a bit messy at the moment but is cleaned up in the following version
 // when NodeInfo changes from an interface to a concrete type
 func (mt *MultiplexTransport) AddChannel(chID byte) {
+	mt.nodeInfoMtx.Lock()
+	defer mt.nodeInfoMtx.Unlock()
 	if ni, ok := mt.nodeInfo.(DefaultNodeInfo); ok {
 		if !ni.HasChannel(chID) {
 			ni.Channels = append(ni.Channels, chID)
@@ -296,6 +402,25 @@ func (mt *MultiplexTransport) AddChannel(chID byte) {
 	}
 }
 
+// SetListenAddr sets the address advertised to peers in nodeInfo, for them to
+// dial us, e.g. once our external address is detected.
+// NOTE: NodeInfo must be of type DefaultNodeInfo else it won't be updated
+func (mt *MultiplexTransport) SetListenAddr(addr string) {
+	mt.nodeInfoMtx.Lock()
+	defer mt.nodeInfoMtx.Unlock()
+	if ni, ok := mt.nodeInfo.(DefaultNodeInfo); ok {
+		ni.ListenAddr = addr
+		mt.nodeInfo = ni
+	}
+}
+
+// currentNodeInfo returns the nodeInfo sent to peers.
+func (mt *MultiplexTransport) currentNodeInfo() NodeInfo {
+	mt.nodeInfoMtx.RLock()
+	defer mt.nodeInfoMtx.RUnlock()
+	return mt.nodeInfo
+}
+
 func (mt *MultiplexTransport) acceptPeers() {
 	for {
 		c, err := mt.listener.Accept()
@@ -322,11 +447,10 @@ func (mt *MultiplexTransport) acceptPeers() {
 		go func(c net.Conn) {
 			defer func() {
 				if r := recover(); r != nil {
-					err := ErrRejected{
-						conn:          c,
-						err:           fmt.Errorf("recovered from panic: %v", r),
-						isAuthFailure: true,
-					}
+					err := NewErrRejectedBuilder().
+						WithConn(c).
+						AuthFailure(fmt.Errorf("recovered from panic: %v", r)).
+						Build()
 					select {
 					case mt.acceptc <- accept{err: err}:
 					case <-mt.closec:
@@ -385,15 +509,29 @@ func (mt *MultiplexTransport) filterConn(c net.Conn) (err error) {
 		}
 	}()
 
+	ips, err := mt.checkConn(c)
+	if err != nil {
+		return err
+	}
+
+	mt.conns.Set(c, ips)
+
+	return nil
+}
+
+// checkConn runs the duplicate check and the connection filters of the
+// transport against c, without tracking it, and returns the ips of the
+// connection.
+func (mt *MultiplexTransport) checkConn(c net.Conn) ([]net.IP, error) {
 	// Reject if connection is already present.
 	if mt.conns.Has(c) {
-		return ErrRejected{conn: c, isDuplicate: true}
+		return nil, NewErrRejectedBuilder().Duplicate(c).Build()
 	}
 
 	// Resolve ips for incoming conn.
 	ips, err := resolveIPs(mt.resolver, c)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	errc := make(chan error, len(mt.connFilters))
@@ -408,17 +546,15 @@ func (mt *MultiplexTransport) filterConn(c net.Conn) (err error) {
 		select {
 		case err := <-errc:
 			if err != nil {
-				return ErrRejected{conn: c, err: err, isFiltered: true}
+				return nil, NewErrRejectedBuilder().WithConn(c).Filtered(err).Build()
 			}
 		case <-time.After(mt.filterTimeout):
-			return ErrFilterTimeout{}
+			return nil, ErrFilterTimeout{}
 		}
 
 	}
 
-	mt.conns.Set(c, ips)
-
-	return nil
+	return ips, nil
 }
 
 func (mt *MultiplexTransport) upgrade(
@@ -431,6 +567,7 @@ func (mt *MultiplexTransport) upgrade(
 		}
 	}()
 	traceID := generateTraceID()
+	ht := mt.newHandshakeTracer(c, traceID, dialedAddr != nil)
 	secretConn, err = upgradeSecretConn(c, mt.handshakeTimeout, mt.nodeKey.PrivKey)
 	getRemoteNodeID := func() string {
 		if secretConn != nil && secretConn.RemotePubKey() != nil {
@@ -439,123 +576,112 @@ func (mt *MultiplexTransport) upgrade(
 		return ""
 	}
 	if err != nil {
-		return nil, nil, ErrRejected{
-			conn:               c,
-			err:                fmt.Errorf("secret conn failed: %v", err),
-			isAuthFailure:      true,
-			localNodeID:        string(mt.nodeInfo.ID()),
-			remoteNodeID:       getRemoteNodeID(),
-			localAddr:          c.LocalAddr().String(),
-			remoteAddr:         c.RemoteAddr().String(),
-			handshakeStage:     "secret-conn-start",
-			traceID:            traceID,
-			malformedHandshake: false,
-		}
+		return nil, nil, ht.trace(HandshakeStageSecretConn, ID(getRemoteNodeID()), NewErrRejectedBuilder().
+			WithConnAddrs(c).
+			AuthFailure(fmt.Errorf("secret conn failed: %v", err)).
+			WithNodeIDs(string(mt.currentNodeInfo().ID()), getRemoteNodeID()).
+			WithHandshakeStage("secret-conn-start").
+			WithTraceID(traceID).
+			Build())
 	}
 
 	connID := PubKeyToID(secretConn.RemotePubKey())
 
 	if dialedAddr != nil {
 		if dialedID := dialedAddr.ID; connID != dialedID {
-			return nil, nil, ErrRejected{
-				conn:           c,
-				id:             connID,
-				err:            fmt.Errorf("conn.ID (%v) dialed ID (%v) mismatch", connID, dialedID),
-				isAuthFailure:  true,
-				localNodeID:    string(mt.nodeInfo.ID()),
-				remoteNodeID:   string(PubKeyToID(secretConn.RemotePubKey())),
-				localAddr:      c.LocalAddr().String(),
-				remoteAddr:     c.RemoteAddr().String(),
-				handshakeStage: "secret-conn-auth",
-				traceID:        traceID,
-			}
+			return nil, nil, ht.trace(HandshakeStageSecretConn, connID, NewErrRejectedBuilder().
+				WithConnAddrs(c).
+				WithID(connID).
+				AuthFailure(fmt.Errorf("conn.ID (%v) dialed ID (%v) mismatch", connID, dialedID)).
+				WithNodeIDs(string(mt.currentNodeInfo().ID()), string(PubKeyToID(secretConn.RemotePubKey()))).
+				WithHandshakeStage("secret-conn-auth").
+				WithTraceID(traceID).
+				Build())
 		}
 	}
+	_ = ht.trace(HandshakeStageSecretConn, connID, nil)
 
-	nodeInfo, err = handshake(secretConn, mt.handshakeTimeout, mt.nodeInfo)
+	nodeInfo, err = handshake(secretConn, mt.handshakeTimeout, withObservedIP(mt.currentNodeInfo(), c.RemoteAddr()))
 	if err != nil {
-		return nil, nil, ErrRejected{
-			conn:           c,
-			err:            fmt.Errorf("handshake failed: %v", err),
-			isAuthFailure:  true,
-			localNodeID:    string(mt.nodeInfo.ID()),
-			remoteNodeID:   string(PubKeyToID(secretConn.RemotePubKey())),
-			localAddr:      c.LocalAddr().String(),
-			remoteAddr:     c.RemoteAddr().String(),
-			handshakeStage: "challenge-response",
-			traceID:        traceID,
-		}
+		return nil, nil, ht.trace(HandshakeStageNodeInfo, connID, NewErrRejectedBuilder().
+			WithConnAddrs(c).
+			AuthFailure(fmt.Errorf("handshake failed: %v", err)).
+			WithNodeIDs(string(mt.currentNodeInfo().ID()), string(PubKeyToID(secretConn.RemotePubKey()))).
+			WithHandshakeStage("challenge-response").
+			WithTraceID(traceID).
+			Build())
 	}
+	_ = ht.trace(HandshakeStageNodeInfo, connID, nil)
 
-	if err := nodeInfo.Validate(); err != nil {
-		return nil, nil, ErrRejected{
-			conn:              c,
-			err:               err,
-			isNodeInfoInvalid: true,
-			localNodeID:       string(mt.nodeInfo.ID()),
-			remoteNodeID:      string(PubKeyToID(secretConn.RemotePubKey())),
-			localAddr:         c.LocalAddr().String(),
-			remoteAddr:        c.RemoteAddr().String(),
-			handshakeStage:    "handshake-nodeinfo-validate",
-			traceID:           traceID,
-		}
+	if err := ht.trace(HandshakeStageVerify, connID, mt.verifyNodeInfo(c, secretConn, nodeInfo, traceID)); err != nil {
+		return nil, nil, err
 	}
 
-	if connID != nodeInfo.ID() {
-		return nil, nil, ErrRejected{
-			conn:           c,
-			id:             connID,
-			err:            fmt.Errorf("conn.ID (%v) NodeInfo.ID (%v) mismatch", connID, nodeInfo.ID()),
-			isAuthFailure:  true,
-			localNodeID:    string(mt.nodeInfo.ID()),
-			remoteNodeID:   string(nodeInfo.ID()),
-			localAddr:      c.LocalAddr().String(),
-			remoteAddr:     c.RemoteAddr().String(),
-			handshakeStage: "connid-vs-nodeid",
-			traceID:        traceID,
-		}
-	}
+	return secretConn, nodeInfo, nil
+}
 
-	if mt.nodeInfo.ID() == nodeInfo.ID() {
-		return nil, nil, ErrRejected{
-			addr:           *NewNetAddress(nodeInfo.ID(), c.RemoteAddr()),
-			conn:           c,
-			id:             nodeInfo.ID(),
-			isSelf:         true,
-			localNodeID:    string(mt.nodeInfo.ID()),
-			remoteNodeID:   string(nodeInfo.ID()),
-			localAddr:      c.LocalAddr().String(),
-			remoteAddr:     c.RemoteAddr().String(),
-			handshakeStage: "self-detect",
-			traceID:        traceID,
-		}
+// verifyNodeInfo checks the NodeInfo received from the peer at the other end
+// of secretConn, established over c: it must be valid, match the key of the
+// peer, not be ours and be compatible with ours.
+func (mt *MultiplexTransport) verifyNodeInfo(
+	c net.Conn,
+	secretConn *conn.SecretConnection,
+	nodeInfo NodeInfo,
+	traceID string,
+) error {
+	connID := PubKeyToID(secretConn.RemotePubKey())
+
+	if err := nodeInfo.Validate(); err != nil {
+		return NewErrRejectedBuilder().
+			WithConnAddrs(c).
+			NodeInfoInvalid(err).
+			WithNodeIDs(string(mt.currentNodeInfo().ID()), string(PubKeyToID(secretConn.RemotePubKey()))).
+			WithHandshakeStage("handshake-nodeinfo-validate").
+			WithTraceID(traceID).
+			Build()
 	}
 
-	if err := mt.nodeInfo.CompatibleWith(nodeInfo); err != nil {
+	if connID != nodeInfo.ID() {
+		return NewErrRejectedBuilder().
+			WithConnAddrs(c).
+			WithID(connID).
+			AuthFailure(fmt.Errorf("conn.ID (%v) NodeInfo.ID (%v) mismatch", connID, nodeInfo.ID())).
+			WithNodeIDs(string(mt.currentNodeInfo().ID()), string(nodeInfo.ID())).
+			WithHandshakeStage("connid-vs-nodeid").
+			WithTraceID(traceID).
+			Build()
+	}
+
+	if mt.currentNodeInfo().ID() == nodeInfo.ID() {
+		return NewErrRejectedBuilder().
+			WithConnAddrs(c).
+			Self(*NewNetAddress(nodeInfo.ID(), c.RemoteAddr())).
+			WithNodeIDs(string(mt.currentNodeInfo().ID()), string(nodeInfo.ID())).
+			WithHandshakeStage("self-detect").
+			WithTraceID(traceID).
+			Build()
+	}
+
+	if err := mt.currentNodeInfo().CompatibleWith(nodeInfo); err != nil {
 		var chainID, peerChainID string
-		if ni, ok := mt.nodeInfo.(DefaultNodeInfo); ok {
+		if ni, ok := mt.currentNodeInfo().(DefaultNodeInfo); ok {
 			chainID = ni.Network
 		}
 		if ni, ok := nodeInfo.(DefaultNodeInfo); ok {
 			peerChainID = ni.Network
 		}
-		return nil, nil, ErrRejected{
-			conn:           c,
-			err:            err,
-			id:             nodeInfo.ID(),
-			isIncompatible: true,
-			localNodeID:    string(mt.nodeInfo.ID()),
-			remoteNodeID:   string(nodeInfo.ID()),
-			localAddr:      c.LocalAddr().String(),
-			remoteAddr:     c.RemoteAddr().String(),
-			handshakeStage: "post-handshake",
-			traceID:        traceID,
-			chainID:        chainID,
-			peerChainID:    peerChainID,
-		}
+		return NewErrRejectedBuilder().
+			WithConnAddrs(c).
+			WithID(nodeInfo.ID()).
+			Incompatible(err).
+			WithNodeIDs(string(mt.currentNodeInfo().ID()), string(nodeInfo.ID())).
+			WithHandshakeStage("post-handshake").
+			WithTraceID(traceID).
+			WithChainIDs(chainID, peerChainID).
+			Build()
 	}
 
-	return secretConn, nodeInfo, nil
+	return nil
 }
 
 func (mt *MultiplexTransport) wrapPeer(
@@ -584,11 +710,20 @@ func (mt *MultiplexTransport) wrapPeer(
 		socketAddr,
 	)
 
+	mConfig := mt.mConfig
+	mConfig.Compression = negotiateCompression(mt.compression, ni)
+	mConfig.RecvCompression = acceptedCompression(mt.currentNodeInfo(), ni)
+
+	reactorsByCh := cfg.reactorsByCh
+	if cfg.reactorsForPeer != nil {
+		reactorsByCh = cfg.reactorsForPeer(ni, persistent)
+	}
+
 	p := newPeer(
 		peerConn,
-		mt.mConfig,
+		mConfig,
 		ni,
-		cfg.reactorsByCh,
+		reactorsByCh,
 		cfg.msgTypeByChID,
 		cfg.chDescs,
 		cfg.onPeerError,
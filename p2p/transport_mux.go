@@ -0,0 +1,313 @@
+package p2p
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"golang.org/x/net/netutil"
+
+	"github.com/cometbft/cometbft/libs/log"
+	"github.com/cometbft/cometbft/libs/protoio"
+	"github.com/cometbft/cometbft/p2p/conn"
+	tmp2p "github.com/cometbft/cometbft/proto/tendermint/p2p"
+)
+
+// ChainMuxOption sets an optional parameter on the ChainMux.
+type ChainMuxOption func(*ChainMux)
+
+// ChainMuxHandshakeTimeout sets the timeout of the secret connection
+// handshake and of the reception of the NodeInfo of the peers.
+func ChainMuxHandshakeTimeout(timeout time.Duration) ChainMuxOption {
+	return func(m *ChainMux) { m.handshakeTimeout = timeout }
+}
+
+// ChainMuxMaxIncomingConnections sets the maximum number of simultaneous
+// incoming connections, for all the chains. Default: 0 (unlimited)
+func ChainMuxMaxIncomingConnections(n int) ChainMuxOption {
+	return func(m *ChainMux) { m.maxIncomingConnections = n }
+}
+
+// ChainMux shares a listen port between the transports of several chains, so
+// that one process can host several switches, e.g. one for the main chain and
+// one for a side network, on the same port. It accepts the connections on the
+// port and hands each of them to the transport of the chain the peer
+// advertises in its NodeInfo.
+//
+// The secret connection being established before the chain of the peer is
+// known, all the transports must use the same node key. They must not listen
+// themselves, and still dial their peers as usual.
+type ChainMux struct {
+	netAddr                NetAddress
+	listener               net.Listener
+	maxIncomingConnections int // see ChainMuxMaxIncomingConnections
+
+	handshakeTimeout time.Duration
+	nodeKey          NodeKey
+	logger           log.Logger
+
+	mtx        sync.RWMutex
+	transports map[string]*MultiplexTransport // by chain ID
+
+	closec chan struct{}
+}
+
+// NewChainMux returns a ChainMux for transports using nodeKey.
+func NewChainMux(nodeKey NodeKey, options ...ChainMuxOption) *ChainMux {
+	m := &ChainMux{
+		handshakeTimeout: defaultHandshakeTimeout,
+		nodeKey:          nodeKey,
+		logger:           log.NewNopLogger(),
+		transports:       make(map[string]*MultiplexTransport),
+		closec:           make(chan struct{}),
+	}
+	for _, option := range options {
+		option(m)
+	}
+	return m
+}
+
+// SetLogger sets the logger of the mux.
+func (m *ChainMux) SetLogger(l log.Logger) {
+	m.logger = l
+}
+
+// AddTransport registers mt as the transport of the chain of its NodeInfo,
+// which must be a DefaultNodeInfo. Peers of that chain connecting to the port
+// of the mux are then accepted by mt.
+func (m *ChainMux) AddTransport(mt *MultiplexTransport) error {
+	ni, ok := mt.currentNodeInfo().(DefaultNodeInfo)
+	if !ok {
+		return fmt.Errorf("nodeInfo is not DefaultNodeInfo, got: %T", mt.currentNodeInfo())
+	}
+	if mt.nodeKey.ID() != m.nodeKey.ID() {
+		return fmt.Errorf("transport of chain %q uses node key %v, instead of %v",
+			ni.Network, mt.nodeKey.ID(), m.nodeKey.ID())
+	}
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if _, ok := m.transports[ni.Network]; ok {
+		return fmt.Errorf("chain %q already has a transport", ni.Network)
+	}
+	m.transports[ni.Network] = mt
+	if m.listener != nil {
+		mt.netAddr = m.netAddr
+	}
+	return nil
+}
+
+// NetAddress returns the address the mux is listening on.
+func (m *ChainMux) NetAddress() NetAddress {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+	return m.netAddr
+}
+
+// Listen starts accepting connections on addr, which becomes the address of
+// all the transports.
+func (m *ChainMux) Listen(addr NetAddress) error {
+	ln, err := net.Listen("tcp", addr.DialString())
+	if err != nil {
+		return err
+	}
+
+	if m.maxIncomingConnections > 0 {
+		ln = netutil.LimitListener(ln, m.maxIncomingConnections)
+	}
+
+	m.mtx.Lock()
+	m.netAddr = addr
+	m.listener = ln
+	for _, mt := range m.transports {
+		mt.netAddr = addr
+	}
+	m.mtx.Unlock()
+
+	go m.acceptConns()
+
+	return nil
+}
+
+// Close stops accepting connections. The transports must be closed
+// separately.
+func (m *ChainMux) Close() error {
+	close(m.closec)
+
+	if m.listener != nil {
+		return m.listener.Close()
+	}
+
+	return nil
+}
+
+func (m *ChainMux) acceptConns() {
+	for {
+		c, err := m.listener.Accept()
+		if err != nil {
+			// If Close() has been called, silently exit.
+			select {
+			case <-m.closec:
+				return
+			default:
+				// Mux is not closed
+			}
+
+			// Report the error to the transports, as their own listener would.
+			m.mtx.RLock()
+			for _, mt := range m.transports {
+				go func(mt *MultiplexTransport) {
+					select {
+					case mt.acceptc <- accept{err: err}:
+					case <-mt.closec:
+					}
+				}(mt)
+			}
+			m.mtx.RUnlock()
+			return
+		}
+
+		// Connections are upgraded asynchronously, as by the transports, to
+		// avoid head-of-line blocking.
+		go m.route(c)
+	}
+}
+
+// route establishes the secret connection over c, receives the NodeInfo of
+// the peer and hands the connection to the transport of its chain, if any.
+func (m *ChainMux) route(c net.Conn) {
+	defer func() {
+		if r := recover(); r != nil {
+			m.logger.Error("Recovered from panic while routing connection", "err", r)
+			_ = c.Close()
+		}
+	}()
+
+	// Filter the connection before the handshake, as the transports do, so
+	// that rejected peers can't make the mux perform it. The chain of the
+	// peer is not known yet, so the connection must pass the filters of all
+	// the transports.
+	if err := m.checkConn(c); err != nil {
+		m.logger.Debug("Rejected inbound peer", "addr", c.RemoteAddr(), "err", err)
+		_ = c.Close()
+		return
+	}
+
+	secretConn, nodeInfo, err := m.readNodeInfo(c)
+	if err != nil {
+		m.logger.Debug("Failed to read NodeInfo of inbound peer", "addr", c.RemoteAddr(), "err", err)
+		_ = c.Close()
+		return
+	}
+
+	m.mtx.RLock()
+	mt, ok := m.transports[nodeInfo.Network]
+	m.mtx.RUnlock()
+	if !ok {
+		m.logger.Debug("Rejected inbound peer of unknown chain",
+			"addr", c.RemoteAddr(), "chain", nodeInfo.Network)
+		_ = c.Close()
+		return
+	}
+
+	mt.acceptRouted(c, secretConn, nodeInfo)
+}
+
+// checkConn returns an error if any of the transports rejects c.
+func (m *ChainMux) checkConn(c net.Conn) error {
+	m.mtx.RLock()
+	transports := make([]*MultiplexTransport, 0, len(m.transports))
+	for _, mt := range m.transports {
+		transports = append(transports, mt)
+	}
+	m.mtx.RUnlock()
+
+	for _, mt := range transports {
+		if _, err := mt.checkConn(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *ChainMux) readNodeInfo(c net.Conn) (*conn.SecretConnection, DefaultNodeInfo, error) {
+	secretConn, err := upgradeSecretConn(c, m.handshakeTimeout, m.nodeKey.PrivKey)
+	if err != nil {
+		return nil, DefaultNodeInfo{}, fmt.Errorf("secret conn failed: %w", err)
+	}
+
+	if err := secretConn.SetDeadline(time.Now().Add(m.handshakeTimeout)); err != nil {
+		return nil, DefaultNodeInfo{}, err
+	}
+	var pbNodeInfo tmp2p.DefaultNodeInfo
+	_, err = protoio.NewDelimitedReader(secretConn, MaxNodeInfoSize()).ReadMsg(&pbNodeInfo)
+	if err != nil {
+		return nil, DefaultNodeInfo{}, err
+	}
+	nodeInfo, err := DefaultNodeInfoFromToProto(&pbNodeInfo)
+	if err != nil {
+		return nil, DefaultNodeInfo{}, err
+	}
+	return secretConn, nodeInfo, secretConn.SetDeadline(time.Time{})
+}
+
+// acceptRouted completes the upgrade of c, routed to the transport by a
+// ChainMux once it received the NodeInfo of the peer: the connection is
+// filtered, our NodeInfo is sent and the NodeInfo of the peer is checked,
+// before making the peer available to Accept.
+func (mt *MultiplexTransport) acceptRouted(c net.Conn, secretConn *conn.SecretConnection, nodeInfo NodeInfo) {
+	var netAddr *NetAddress
+
+	err := mt.filterConn(c)
+	if err == nil {
+		err = mt.completeRoutedHandshake(c, secretConn, nodeInfo)
+		if err != nil {
+			_ = mt.cleanup(c)
+		} else {
+			netAddr = NewNetAddress(PubKeyToID(secretConn.RemotePubKey()), c.RemoteAddr())
+		}
+	}
+
+	select {
+	case mt.acceptc <- accept{netAddr, secretConn, nodeInfo, err}:
+		// Make the upgraded peer available.
+	case <-mt.closec:
+		// Give up if the transport was closed.
+		_ = c.Close()
+	}
+}
+
+func (mt *MultiplexTransport) completeRoutedHandshake(
+	c net.Conn,
+	secretConn *conn.SecretConnection,
+	nodeInfo NodeInfo,
+) error {
+	traceID := generateTraceID()
+	ht := mt.newHandshakeTracer(c, traceID, false)
+	peerID := PubKeyToID(secretConn.RemotePubKey())
+
+	ourNodeInfo, ok := withObservedIP(mt.currentNodeInfo(), c.RemoteAddr()).(DefaultNodeInfo)
+	if !ok {
+		return fmt.Errorf("nodeInfo is not DefaultNodeInfo, got: %T", mt.currentNodeInfo())
+	}
+	err := secretConn.SetDeadline(time.Now().Add(mt.handshakeTimeout))
+	if err == nil {
+		_, err = protoio.NewDelimitedWriter(secretConn).WriteMsg(ourNodeInfo.ToProto())
+	}
+	if err == nil {
+		err = secretConn.SetDeadline(time.Time{})
+	}
+	if err != nil {
+		return ht.trace(HandshakeStageNodeInfo, peerID, NewErrRejectedBuilder().
+			WithConnAddrs(c).
+			AuthFailure(fmt.Errorf("handshake failed: %v", err)).
+			WithNodeIDs(string(mt.currentNodeInfo().ID()), string(peerID)).
+			WithHandshakeStage("challenge-response").
+			WithTraceID(traceID).
+			Build())
+	}
+	_ = ht.trace(HandshakeStageNodeInfo, peerID, nil)
+
+	return ht.trace(HandshakeStageVerify, peerID, mt.verifyNodeInfo(c, secretConn, nodeInfo, traceID))
+}
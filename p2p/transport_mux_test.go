@@ -0,0 +1,121 @@
+package p2p
+
+import (
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cometbft/cometbft/crypto/ed25519"
+)
+
+func TestChainMux(t *testing.T) {
+	nodeKey := NodeKey{PrivKey: ed25519.GenPrivKey()}
+	mux := NewChainMux(nodeKey)
+
+	transports := make(map[string]*MultiplexTransport)
+	for _, chainID := range []string{"chain-a", "chain-b"} {
+		mt := newMultiplexTransport(testNodeInfoWithNetwork(nodeKey.ID(), "mux", chainID), nodeKey)
+		require.NoError(t, mux.AddTransport(mt))
+		transports[chainID] = mt
+	}
+
+	// Transports must have another chain and the same key.
+	err := mux.AddTransport(newMultiplexTransport(testNodeInfoWithNetwork(nodeKey.ID(), "mux", "chain-a"), nodeKey))
+	assert.Error(t, err)
+	otherKey := NodeKey{PrivKey: ed25519.GenPrivKey()}
+	err = mux.AddTransport(newMultiplexTransport(testNodeInfoWithNetwork(otherKey.ID(), "mux", "chain-c"), otherKey))
+	assert.Error(t, err)
+
+	addr, err := NewNetAddressString(IDAddressString(nodeKey.ID(), "127.0.0.1:0"))
+	require.NoError(t, err)
+	require.NoError(t, mux.Listen(*addr))
+	t.Cleanup(func() {
+		assert.NoError(t, mux.Close())
+		for _, mt := range transports {
+			assert.NoError(t, mt.Close())
+		}
+	})
+	laddr := NewNetAddress(nodeKey.ID(), mux.listener.Addr())
+	assert.Equal(t, *addr, transports["chain-a"].NetAddress())
+
+	dial := func(chainID string) (ID, Peer, error) {
+		dialerKey := NodeKey{PrivKey: ed25519.GenPrivKey()}
+		dialer := newMultiplexTransport(
+			testNodeInfoWithNetwork(dialerKey.ID(), defaultNodeName, chainID),
+			dialerKey,
+		)
+		p, err := dialer.Dial(*laddr, peerConfig{})
+		return dialerKey.ID(), p, err
+	}
+
+	for chainID, mt := range transports {
+		id, dialed, err := dial(chainID)
+		require.NoError(t, err, chainID)
+		assert.Equal(t, nodeKey.ID(), dialed.ID())
+		assert.Equal(t, chainID, dialed.NodeInfo().(DefaultNodeInfo).Network)
+
+		accepted, err := mt.Accept(peerConfig{})
+		require.NoError(t, err, chainID)
+		assert.Equal(t, id, accepted.ID())
+		assert.Equal(t, chainID, accepted.NodeInfo().(DefaultNodeInfo).Network)
+	}
+
+	// Peers of other chains are disconnected.
+	_, _, err = dial("chain-c")
+	assert.Error(t, err)
+	for chainID, mt := range transports {
+		accepted := make(chan struct{})
+		go func() {
+			if _, err := mt.Accept(peerConfig{}); err == nil {
+				close(accepted)
+			}
+		}()
+		select {
+		case <-accepted:
+			t.Errorf("transport of %s accepted a peer of chain-c", chainID)
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
+func TestChainMuxFiltersConnsBeforeHandshake(t *testing.T) {
+	nodeKey := NodeKey{PrivKey: ed25519.GenPrivKey()}
+	mux := NewChainMux(nodeKey)
+
+	filtered := make(chan struct{}, 1)
+	mt := newMultiplexTransport(testNodeInfoWithNetwork(nodeKey.ID(), "mux", "chain-a"), nodeKey)
+	MultiplexTransportConnFilters(func(_ ConnSet, _ net.Conn, _ []net.IP) error {
+		filtered <- struct{}{}
+		return errors.New("rejected")
+	})(mt)
+	require.NoError(t, mux.AddTransport(mt))
+
+	addr, err := NewNetAddressString(IDAddressString(nodeKey.ID(), "127.0.0.1:0"))
+	require.NoError(t, err)
+	require.NoError(t, mux.Listen(*addr))
+	t.Cleanup(func() {
+		assert.NoError(t, mux.Close())
+		assert.NoError(t, mt.Close())
+	})
+
+	c, err := net.Dial("tcp", mux.listener.Addr().String())
+	require.NoError(t, err)
+	defer c.Close()
+
+	// The connection is closed without the mux starting the secret connection
+	// handshake, which would send its ephemeral key first.
+	require.NoError(t, c.SetReadDeadline(time.Now().Add(5*time.Second)))
+	n, err := c.Read(make([]byte, 64))
+	assert.Zero(t, n)
+	assert.ErrorIs(t, err, io.EOF)
+	select {
+	case <-filtered:
+	default:
+		t.Error("connection was not filtered")
+	}
+}
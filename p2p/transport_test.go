@@ -7,6 +7,7 @@ import (
 	"reflect"
 	"runtime"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -350,7 +351,10 @@ func TestTransportMultiplexAcceptNonBlocking(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if have, want := p.NodeInfo(), fastNodeInfo; !reflect.DeepEqual(have, want) {
+	// The fast node reports the IP it observed us connecting from.
+	want := fastNodeInfo.(DefaultNodeInfo)
+	want.Other.ObservedIP = "127.0.0.1"
+	if have := p.NodeInfo(); !reflect.DeepEqual(have, want) {
 		t.Errorf("have %v, want %v", have, want)
 	}
 }
@@ -464,6 +468,65 @@ func TestTransportMultiplexDialRejectWrongID(t *testing.T) {
 	}
 }
 
+func TestTransportMultiplexHandshakeCallback(t *testing.T) {
+	mt := testSetupMultiplexTransport(t)
+
+	var (
+		mtx    sync.Mutex
+		traces []HandshakeStageTrace
+	)
+	newDialer := func() *MultiplexTransport {
+		pv := ed25519.GenPrivKey()
+		dialer := newMultiplexTransport(
+			testNodeInfo(PubKeyToID(pv.PubKey()), "dialer"),
+			NodeKey{PrivKey: pv},
+		)
+		MultiplexTransportHandshakeCallback(func(st HandshakeStageTrace) {
+			mtx.Lock()
+			defer mtx.Unlock()
+			traces = append(traces, st)
+		})(dialer)
+		return dialer
+	}
+
+	addr := NewNetAddress(mt.nodeKey.ID(), mt.listener.Addr())
+	if _, err := newDialer().Dial(*addr, peerConfig{}); err != nil {
+		t.Fatal(err)
+	}
+
+	mtx.Lock()
+	stages := []string{HandshakeStageSecretConn, HandshakeStageNodeInfo, HandshakeStageVerify}
+	if len(traces) != len(stages) {
+		t.Fatalf("have %d traces, want %d", len(traces), len(stages))
+	}
+	for i, stage := range stages {
+		st := traces[i]
+		if st.Stage != stage || st.TraceID != traces[0].TraceID || st.PeerID != mt.nodeKey.ID() ||
+			!st.Outbound || st.Err != nil {
+			t.Errorf("unexpected trace of stage %s: %+v", stage, st)
+		}
+	}
+	traces = nil
+	mtx.Unlock()
+
+	// A failed stage ends the handshake, and shares its trace ID with the
+	// rejection.
+	wrongID := PubKeyToID(ed25519.GenPrivKey().PubKey())
+	if _, err := newDialer().Dial(*NewNetAddress(wrongID, mt.listener.Addr()), peerConfig{}); err == nil {
+		t.Fatal("expected dial to fail")
+	}
+
+	mtx.Lock()
+	defer mtx.Unlock()
+	if len(traces) != 1 || traces[0].Stage != HandshakeStageSecretConn {
+		t.Fatalf("expected a failed %s stage, got %+v", HandshakeStageSecretConn, traces)
+	}
+	e, ok := traces[0].Err.(ErrRejected)
+	if !ok || !e.IsAuthFailure() || e.TraceID() != traces[0].TraceID {
+		t.Errorf("expected auth failure with trace ID %s, got %v", traces[0].TraceID, traces[0].Err)
+	}
+}
+
 func TestTransportMultiplexRejectIncompatible(t *testing.T) {
 	mt := testSetupMultiplexTransport(t)
 
@@ -32,6 +32,14 @@ func (m *WantTx) Wrap() proto.Message {
 	return mm
 }
 
+// Wrap implements the p2p Wrapper interface and wraps a mempool compressed
+// txs message.
+func (m *CompressedTxs) Wrap() proto.Message {
+	mm := &Message{}
+	mm.Sum = &Message_CompressedTxs{CompressedTxs: m}
+	return mm
+}
+
 // Unwrap implements the p2p Wrapper interface and unwraps a wrapped mempool
 // message.
 func (m *Message) Unwrap() (proto.Message, error) {
@@ -43,6 +51,9 @@ func (m *Message) Unwrap() (proto.Message, error) {
 
 	case *Message_WantTx:
 		return m.GetWantTx(), nil
+
+	case *Message_CompressedTxs:
+		return m.GetCompressedTxs(), nil
 	default:
 		return nil, fmt.Errorf("unknown message: %T", msg)
 	}
@@ -5,6 +5,7 @@ package mempool
 
 import (
 	fmt "fmt"
+	p2p "github.com/cometbft/cometbft/proto/tendermint/p2p"
 	proto "github.com/cosmos/gogoproto/proto"
 	io "io"
 	math "math"
@@ -66,6 +67,61 @@ func (m *Txs) GetTxs() [][]byte {
 	return nil
 }
 
+// CompressedTxs is a Txs message, encoded and compressed with the given
+// algorithm, to save bandwidth when gossiping batches of transactions. The
+// algorithms are the same as those compressing p2p packets.
+type CompressedTxs struct {
+	Compression p2p.Compression `protobuf:"varint,1,opt,name=compression,proto3,enum=tendermint.p2p.Compression" json:"compression,omitempty"`
+	Data        []byte          `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (m *CompressedTxs) Reset()         { *m = CompressedTxs{} }
+func (m *CompressedTxs) String() string { return proto.CompactTextString(m) }
+func (*CompressedTxs) ProtoMessage()    {}
+func (*CompressedTxs) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2af51926fdbcbc05, []int{1}
+}
+func (m *CompressedTxs) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CompressedTxs) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_CompressedTxs.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *CompressedTxs) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CompressedTxs.Merge(m, src)
+}
+func (m *CompressedTxs) XXX_Size() int {
+	return m.Size()
+}
+func (m *CompressedTxs) XXX_DiscardUnknown() {
+	xxx_messageInfo_CompressedTxs.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CompressedTxs proto.InternalMessageInfo
+
+func (m *CompressedTxs) GetCompression() p2p.Compression {
+	if m != nil {
+		return m.Compression
+	}
+	return p2p.Compression_COMPRESSION_NONE
+}
+
+func (m *CompressedTxs) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
 type SeenTx struct {
 	TxKey []byte `protobuf:"bytes,1,opt,name=tx_key,json=txKey,proto3" json:"tx_key,omitempty"`
 }
@@ -74,7 +130,7 @@ func (m *SeenTx) Reset()         { *m = SeenTx{} }
 func (m *SeenTx) String() string { return proto.CompactTextString(m) }
 func (*SeenTx) ProtoMessage()    {}
 func (*SeenTx) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2af51926fdbcbc05, []int{1}
+	return fileDescriptor_2af51926fdbcbc05, []int{2}
 }
 func (m *SeenTx) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -118,7 +174,7 @@ func (m *WantTx) Reset()         { *m = WantTx{} }
 func (m *WantTx) String() string { return proto.CompactTextString(m) }
 func (*WantTx) ProtoMessage()    {}
 func (*WantTx) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2af51926fdbcbc05, []int{2}
+	return fileDescriptor_2af51926fdbcbc05, []int{3}
 }
 func (m *WantTx) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -156,10 +212,10 @@ func (m *WantTx) GetTxKey() []byte {
 
 type Message struct {
 	// Types that are valid to be assigned to Sum:
-	//
 	//	*Message_Txs
 	//	*Message_SeenTx
 	//	*Message_WantTx
+	//	*Message_CompressedTxs
 	Sum isMessage_Sum `protobuf_oneof:"sum"`
 }
 
@@ -167,7 +223,7 @@ func (m *Message) Reset()         { *m = Message{} }
 func (m *Message) String() string { return proto.CompactTextString(m) }
 func (*Message) ProtoMessage()    {}
 func (*Message) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2af51926fdbcbc05, []int{3}
+	return fileDescriptor_2af51926fdbcbc05, []int{4}
 }
 func (m *Message) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -211,10 +267,14 @@ type Message_SeenTx struct {
 type Message_WantTx struct {
 	WantTx *WantTx `protobuf:"bytes,3,opt,name=want_tx,json=wantTx,proto3,oneof" json:"want_tx,omitempty"`
 }
+type Message_CompressedTxs struct {
+	CompressedTxs *CompressedTxs `protobuf:"bytes,4,opt,name=compressed_txs,json=compressedTxs,proto3,oneof" json:"compressed_txs,omitempty"`
+}
 
-func (*Message_Txs) isMessage_Sum()    {}
-func (*Message_SeenTx) isMessage_Sum() {}
-func (*Message_WantTx) isMessage_Sum() {}
+func (*Message_Txs) isMessage_Sum()           {}
+func (*Message_SeenTx) isMessage_Sum()        {}
+func (*Message_WantTx) isMessage_Sum()        {}
+func (*Message_CompressedTxs) isMessage_Sum() {}
 
 func (m *Message) GetSum() isMessage_Sum {
 	if m != nil {
@@ -244,17 +304,26 @@ func (m *Message) GetWantTx() *WantTx {
 	return nil
 }
 
+func (m *Message) GetCompressedTxs() *CompressedTxs {
+	if x, ok := m.GetSum().(*Message_CompressedTxs); ok {
+		return x.CompressedTxs
+	}
+	return nil
+}
+
 // XXX_OneofWrappers is for the internal use of the proto package.
 func (*Message) XXX_OneofWrappers() []interface{} {
 	return []interface{}{
 		(*Message_Txs)(nil),
 		(*Message_SeenTx)(nil),
 		(*Message_WantTx)(nil),
+		(*Message_CompressedTxs)(nil),
 	}
 }
 
 func init() {
 	proto.RegisterType((*Txs)(nil), "tendermint.mempool.Txs")
+	proto.RegisterType((*CompressedTxs)(nil), "tendermint.mempool.CompressedTxs")
 	proto.RegisterType((*SeenTx)(nil), "tendermint.mempool.SeenTx")
 	proto.RegisterType((*WantTx)(nil), "tendermint.mempool.WantTx")
 	proto.RegisterType((*Message)(nil), "tendermint.mempool.Message")
@@ -263,25 +332,30 @@ func init() {
 func init() { proto.RegisterFile("tendermint/mempool/types.proto", fileDescriptor_2af51926fdbcbc05) }
 
 var fileDescriptor_2af51926fdbcbc05 = []byte{
-	// 274 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x92, 0x2b, 0x49, 0xcd, 0x4b,
-	0x49, 0x2d, 0xca, 0xcd, 0xcc, 0x2b, 0xd1, 0xcf, 0x4d, 0xcd, 0x2d, 0xc8, 0xcf, 0xcf, 0xd1, 0x2f,
-	0xa9, 0x2c, 0x48, 0x2d, 0xd6, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x12, 0x42, 0xc8, 0xeb, 0x41,
-	0xe5, 0x95, 0xc4, 0xb9, 0x98, 0x43, 0x2a, 0x8a, 0x85, 0x04, 0xb8, 0x98, 0x4b, 0x2a, 0x8a, 0x25,
-	0x18, 0x15, 0x98, 0x35, 0x78, 0x82, 0x40, 0x4c, 0x25, 0x79, 0x2e, 0xb6, 0xe0, 0xd4, 0xd4, 0xbc,
-	0x90, 0x0a, 0x21, 0x51, 0x2e, 0xb6, 0x92, 0x8a, 0xf8, 0xec, 0xd4, 0x4a, 0x09, 0x46, 0x05, 0x46,
-	0x0d, 0x9e, 0x20, 0xd6, 0x92, 0x0a, 0xef, 0xd4, 0x4a, 0x90, 0x82, 0xf0, 0xc4, 0xbc, 0x12, 0xdc,
-	0x0a, 0x56, 0x33, 0x72, 0xb1, 0xfb, 0xa6, 0x16, 0x17, 0x27, 0xa6, 0xa7, 0x0a, 0x69, 0xc3, 0xcc,
-	0x67, 0xd4, 0xe0, 0x36, 0x12, 0xd7, 0xc3, 0x74, 0x88, 0x5e, 0x48, 0x45, 0xb1, 0x07, 0x03, 0xd8,
-	0x6a, 0x21, 0x53, 0x2e, 0xf6, 0xe2, 0xd4, 0xd4, 0xbc, 0xf8, 0x92, 0x0a, 0x09, 0x26, 0xb0, 0x06,
-	0x29, 0x6c, 0x1a, 0x20, 0xae, 0xf3, 0x60, 0x08, 0x62, 0x2b, 0x86, 0xb8, 0xd3, 0x94, 0x8b, 0xbd,
-	0x3c, 0x31, 0xaf, 0x04, 0xa4, 0x8d, 0x19, 0xb7, 0x36, 0x88, 0x9b, 0x41, 0xda, 0xca, 0xc1, 0x2c,
-	0x27, 0x56, 0x2e, 0xe6, 0xe2, 0xd2, 0x5c, 0x27, 0xff, 0x13, 0x8f, 0xe4, 0x18, 0x2f, 0x3c, 0x92,
-	0x63, 0x7c, 0xf0, 0x48, 0x8e, 0x71, 0xc2, 0x63, 0x39, 0x86, 0x0b, 0x8f, 0xe5, 0x18, 0x6e, 0x3c,
-	0x96, 0x63, 0x88, 0x32, 0x4d, 0xcf, 0x2c, 0xc9, 0x28, 0x4d, 0xd2, 0x4b, 0xce, 0xcf, 0xd5, 0x4f,
-	0xce, 0xcf, 0x4d, 0x2d, 0x49, 0x4a, 0x2b, 0x41, 0x30, 0xc0, 0x41, 0xab, 0x8f, 0x19, 0xf2, 0x49,
-	0x6c, 0x60, 0x19, 0x63, 0x40, 0x00, 0x00, 0x00, 0xff, 0xff, 0x66, 0x7b, 0xde, 0x37, 0x96, 0x01,
-	0x00, 0x00,
+	// 359 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x92, 0xcf, 0x4a, 0xc3, 0x40,
+	0x10, 0xc6, 0xb3, 0xa6, 0x4d, 0x61, 0xfb, 0x07, 0x59, 0x90, 0xd6, 0x0a, 0xb1, 0xf6, 0x14, 0x10,
+	0x12, 0x88, 0xf4, 0xe8, 0xa5, 0xbd, 0x14, 0x45, 0x84, 0x58, 0x10, 0xbc, 0x94, 0x24, 0x1d, 0x6b,
+	0xd1, 0xec, 0x2e, 0xdd, 0x29, 0x4d, 0xdf, 0xc2, 0xc7, 0xf2, 0xd8, 0xa3, 0x47, 0x69, 0x1f, 0xc2,
+	0xab, 0x24, 0xa9, 0x36, 0xd2, 0xf6, 0x36, 0xcc, 0x7c, 0xbf, 0xdd, 0x6f, 0xbe, 0x5d, 0x6a, 0x22,
+	0xf0, 0x11, 0x4c, 0xa3, 0x09, 0x47, 0x27, 0x82, 0x48, 0x0a, 0xf1, 0xe6, 0xe0, 0x42, 0x82, 0xb2,
+	0xe5, 0x54, 0xa0, 0x60, 0x6c, 0x3b, 0xb7, 0x37, 0xf3, 0xe6, 0x69, 0x8e, 0x91, 0xae, 0x74, 0x42,
+	0xc1, 0x79, 0x26, 0x6f, 0xd7, 0xa9, 0x3e, 0x88, 0x15, 0x3b, 0xa6, 0x3a, 0xc6, 0xaa, 0x41, 0x5a,
+	0xba, 0x55, 0xf1, 0x92, 0xb2, 0x1d, 0xd0, 0x6a, 0x4f, 0x44, 0x72, 0x0a, 0x4a, 0xc1, 0x28, 0x91,
+	0x5c, 0xd3, 0x72, 0xb8, 0x69, 0x4c, 0x04, 0x6f, 0x90, 0x16, 0xb1, 0x6a, 0xee, 0x99, 0x9d, 0xbb,
+	0x4e, 0xba, 0xd2, 0xee, 0x6d, 0x25, 0x5e, 0x5e, 0xcf, 0x18, 0x2d, 0x8c, 0x7c, 0xf4, 0x1b, 0x47,
+	0x2d, 0x62, 0x55, 0xbc, 0xb4, 0x6e, 0x9f, 0x53, 0xe3, 0x01, 0x80, 0x0f, 0x62, 0x76, 0x42, 0x0d,
+	0x8c, 0x87, 0xaf, 0xb0, 0x48, 0xcf, 0xad, 0x78, 0x45, 0x8c, 0x6f, 0x61, 0x91, 0x08, 0x1e, 0x7d,
+	0x8e, 0x87, 0x05, 0xdf, 0x84, 0x96, 0xee, 0x40, 0x29, 0x7f, 0x0c, 0xec, 0xf2, 0x77, 0x07, 0x62,
+	0x95, 0xdd, 0xba, 0xbd, 0x9b, 0x83, 0x3d, 0x88, 0x55, 0x5f, 0x4b, 0xd7, 0x63, 0x1d, 0x5a, 0x52,
+	0x00, 0x7c, 0x88, 0x71, 0xea, 0xa8, 0xec, 0x36, 0xf7, 0x01, 0x99, 0xbb, 0xbe, 0xe6, 0x19, 0x2a,
+	0xf3, 0xd9, 0xa1, 0xa5, 0xb9, 0xcf, 0x31, 0xc1, 0xf4, 0xc3, 0x58, 0xe6, 0x39, 0xc1, 0xe6, 0x99,
+	0xfb, 0x1b, 0x5a, 0x0b, 0xff, 0xc2, 0x1c, 0x26, 0x2e, 0x0b, 0x29, 0x7d, 0xb1, 0x8f, 0xfe, 0x17,
+	0x7b, 0x5f, 0xf3, 0xaa, 0x61, 0xbe, 0xd1, 0x2d, 0x52, 0x5d, 0xcd, 0xa2, 0xee, 0xfd, 0xc7, 0xca,
+	0x24, 0xcb, 0x95, 0x49, 0xbe, 0x56, 0x26, 0x79, 0x5f, 0x9b, 0xda, 0x72, 0x6d, 0x6a, 0x9f, 0x6b,
+	0x53, 0x7b, 0xea, 0x8c, 0x27, 0xf8, 0x32, 0x0b, 0xec, 0x50, 0x44, 0x4e, 0x28, 0x22, 0xc0, 0xe0,
+	0x19, 0xb7, 0x45, 0xfa, 0xec, 0xce, 0xee, 0x27, 0x0a, 0x8c, 0x74, 0x72, 0xf5, 0x33, 0x00, 0x18,
+	0xf0, 0xd9, 0xb7, 0x61, 0x02, 0x00, 0x00,
 }
 
 func (m *Txs) Marshal() (dAtA []byte, err error) {
@@ -316,6 +390,41 @@ func (m *Txs) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *CompressedTxs) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CompressedTxs) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CompressedTxs) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Data) > 0 {
+		i -= len(m.Data)
+		copy(dAtA[i:], m.Data)
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.Data)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.Compression != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.Compression))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
 func (m *SeenTx) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -471,6 +580,27 @@ func (m *Message_WantTx) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	}
 	return len(dAtA) - i, nil
 }
+func (m *Message_CompressedTxs) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Message_CompressedTxs) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.CompressedTxs != nil {
+		{
+			size, err := m.CompressedTxs.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTypes(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x22
+	}
+	return len(dAtA) - i, nil
+}
 func encodeVarintTypes(dAtA []byte, offset int, v uint64) int {
 	offset -= sovTypes(v)
 	base := offset
@@ -497,6 +627,22 @@ func (m *Txs) Size() (n int) {
 	return n
 }
 
+func (m *CompressedTxs) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Compression != 0 {
+		n += 1 + sovTypes(uint64(m.Compression))
+	}
+	l = len(m.Data)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	return n
+}
+
 func (m *SeenTx) Size() (n int) {
 	if m == nil {
 		return 0
@@ -571,6 +717,18 @@ func (m *Message_WantTx) Size() (n int) {
 	}
 	return n
 }
+func (m *Message_CompressedTxs) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.CompressedTxs != nil {
+		l = m.CompressedTxs.Size()
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	return n
+}
 
 func sovTypes(x uint64) (n int) {
 	return (math_bits.Len64(x|1) + 6) / 7
@@ -660,6 +818,109 @@ func (m *Txs) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *CompressedTxs) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTypes
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CompressedTxs: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CompressedTxs: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Compression", wireType)
+			}
+			m.Compression = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Compression |= p2p.Compression(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Data", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Data = append(m.Data[:0], dAtA[iNdEx:postIndex]...)
+			if m.Data == nil {
+				m.Data = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTypes(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func (m *SeenTx) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
@@ -962,6 +1223,41 @@ func (m *Message) Unmarshal(dAtA []byte) error {
 			}
 			m.Sum = &Message_WantTx{v}
 			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CompressedTxs", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &CompressedTxs{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Message_CompressedTxs{v}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTypes(dAtA[iNdEx:])
@@ -24,6 +24,34 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
 
+type Compression int32
+
+const (
+	Compression_COMPRESSION_NONE   Compression = 0
+	Compression_COMPRESSION_SNAPPY Compression = 1
+	Compression_COMPRESSION_ZSTD   Compression = 2
+)
+
+var Compression_name = map[int32]string{
+	0: "COMPRESSION_NONE",
+	1: "COMPRESSION_SNAPPY",
+	2: "COMPRESSION_ZSTD",
+}
+
+var Compression_value = map[string]int32{
+	"COMPRESSION_NONE":   0,
+	"COMPRESSION_SNAPPY": 1,
+	"COMPRESSION_ZSTD":   2,
+}
+
+func (x Compression) String() string {
+	return proto.EnumName(Compression_name, int32(x))
+}
+
+func (Compression) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_22474b5527c8fa9f, []int{0}
+}
+
 type PacketPing struct {
 }
 
@@ -100,6 +128,8 @@ type PacketMsg struct {
 	ChannelID int32  `protobuf:"varint,1,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
 	EOF       bool   `protobuf:"varint,2,opt,name=eof,proto3" json:"eof,omitempty"`
 	Data      []byte `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	// Algorithm the message the packet is part of was compressed with.
+	Compression Compression `protobuf:"varint,4,opt,name=compression,proto3,enum=tendermint.p2p.Compression" json:"compression,omitempty"`
 }
 
 func (m *PacketMsg) Reset()         { *m = PacketMsg{} }
@@ -156,6 +186,13 @@ func (m *PacketMsg) GetData() []byte {
 	return nil
 }
 
+func (m *PacketMsg) GetCompression() Compression {
+	if m != nil {
+		return m.Compression
+	}
+	return Compression_COMPRESSION_NONE
+}
+
 type Packet struct {
 	// Types that are valid to be assigned to Sum:
 	//
@@ -308,6 +345,7 @@ func (m *AuthSigMessage) GetSig() []byte {
 }
 
 func init() {
+	proto.RegisterEnum("tendermint.p2p.Compression", Compression_name, Compression_value)
 	proto.RegisterType((*PacketPing)(nil), "tendermint.p2p.PacketPing")
 	proto.RegisterType((*PacketPong)(nil), "tendermint.p2p.PacketPong")
 	proto.RegisterType((*PacketMsg)(nil), "tendermint.p2p.PacketMsg")
@@ -318,32 +356,37 @@ func init() {
 func init() { proto.RegisterFile("tendermint/p2p/conn.proto", fileDescriptor_22474b5527c8fa9f) }
 
 var fileDescriptor_22474b5527c8fa9f = []byte{
-	// 397 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x52, 0x4d, 0x8f, 0xd3, 0x30,
-	0x10, 0x8d, 0xc9, 0x6e, 0x97, 0x4e, 0xcb, 0x0a, 0x59, 0x1c, 0xda, 0x6a, 0x95, 0x56, 0x3d, 0xf5,
-	0x80, 0x12, 0x11, 0x6e, 0x20, 0x0e, 0x84, 0x0f, 0xb1, 0xaa, 0x2a, 0xaa, 0x70, 0xe3, 0x12, 0xe5,
-	0xc3, 0xeb, 0x58, 0xdd, 0xd8, 0x56, 0xed, 0x1c, 0xf2, 0x2f, 0xf8, 0x59, 0xcb, 0xad, 0x47, 0x4e,
-	0x15, 0x4a, 0xff, 0x08, 0x4a, 0x5c, 0x68, 0x2a, 0xb1, 0xb7, 0xf7, 0x66, 0xfc, 0x66, 0xde, 0x93,
-	0x07, 0xc6, 0x9a, 0xf0, 0x8c, 0x6c, 0x0b, 0xc6, 0xb5, 0x27, 0x7d, 0xe9, 0xa5, 0x82, 0x73, 0x57,
-	0x6e, 0x85, 0x16, 0xf8, 0xfa, 0xd4, 0x72, 0xa5, 0x2f, 0x27, 0x2f, 0xa8, 0xa0, 0xa2, 0x6d, 0x79,
-	0x0d, 0x32, 0xaf, 0x26, 0x37, 0x9d, 0x01, 0xe9, 0xb6, 0x92, 0x5a, 0x78, 0x1b, 0x52, 0x29, 0xd3,
-	0x9d, 0x0f, 0x01, 0xd6, 0x71, 0xba, 0x21, 0x7a, 0xcd, 0x38, 0xed, 0x30, 0xc1, 0xe9, 0x3c, 0x87,
-	0xbe, 0x61, 0x2b, 0x45, 0xf1, 0x4b, 0x80, 0x34, 0x8f, 0x39, 0x27, 0xf7, 0x11, 0xcb, 0x46, 0x68,
-	0x86, 0x16, 0x97, 0xc1, 0xb3, 0x7a, 0x3f, 0xed, 0x7f, 0x30, 0xd5, 0xdb, 0x8f, 0x61, 0xff, 0xf8,
-	0xe0, 0x36, 0xc3, 0x63, 0xb0, 0x89, 0xb8, 0x1b, 0x3d, 0x99, 0xa1, 0xc5, 0xd3, 0xe0, 0xaa, 0xde,
-	0x4f, 0xed, 0x4f, 0x5f, 0x3f, 0x87, 0x4d, 0x0d, 0x63, 0xb8, 0xc8, 0x62, 0x1d, 0x8f, 0xec, 0x19,
-	0x5a, 0x0c, 0xc3, 0x16, 0xcf, 0x7f, 0x22, 0xe8, 0x99, 0x55, 0xf8, 0x1d, 0x0c, 0x64, 0x8b, 0x22,
-	0xc9, 0x38, 0x6d, 0x17, 0x0d, 0xfc, 0x89, 0x7b, 0x1e, 0xd5, 0x3d, 0x79, 0xfe, 0x62, 0x85, 0x20,
-	0xff, 0xb1, 0xae, 0x5c, 0x70, 0xda, 0x1a, 0x78, 0x5c, 0x2e, 0xce, 0xe4, 0x82, 0x53, 0xfc, 0x06,
-	0x8e, 0x2c, 0x2a, 0x14, 0x6d, 0x2d, 0x0e, 0xfc, 0xf1, 0xff, 0xd5, 0x2b, 0xd5, 0x88, 0xfb, 0xf2,
-	0x2f, 0x09, 0x2e, 0xc1, 0x56, 0x65, 0x31, 0x8f, 0xe0, 0xfa, 0x7d, 0xa9, 0xf3, 0x6f, 0x8c, 0xae,
-	0x88, 0x52, 0x31, 0x25, 0xf8, 0x2d, 0x5c, 0xc9, 0x32, 0x89, 0x36, 0xa4, 0x3a, 0xc6, 0xb9, 0xe9,
-	0x4e, 0x34, 0x7f, 0xe2, 0xae, 0xcb, 0xe4, 0x9e, 0xa5, 0x4b, 0x52, 0x05, 0x17, 0x0f, 0xfb, 0xa9,
-	0x15, 0xf6, 0x64, 0x99, 0x2c, 0x49, 0x85, 0x9f, 0x83, 0xad, 0x98, 0x09, 0x32, 0x0c, 0x1b, 0x18,
-	0x2c, 0x1f, 0x6a, 0x07, 0xed, 0x6a, 0x07, 0xfd, 0xae, 0x1d, 0xf4, 0xe3, 0xe0, 0x58, 0xbb, 0x83,
-	0x63, 0xfd, 0x3a, 0x38, 0xd6, 0xf7, 0x57, 0x94, 0xe9, 0xbc, 0x4c, 0xdc, 0x54, 0x14, 0x5e, 0x2a,
-	0x0a, 0xa2, 0x93, 0x3b, 0x7d, 0x02, 0xe6, 0x32, 0xce, 0xcf, 0x29, 0xe9, 0xb5, 0xd5, 0xd7, 0x7f,
-	0x02, 0x00, 0x00, 0xff, 0xff, 0xa9, 0xf9, 0x75, 0xae, 0x67, 0x02, 0x00, 0x00,
+	// 467 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x52, 0x41, 0x8f, 0xd2, 0x40,
+	0x18, 0xed, 0x2c, 0x2c, 0x2b, 0x03, 0x12, 0x32, 0xd9, 0x18, 0xc0, 0x4d, 0x21, 0x9c, 0x88, 0x31,
+	0x6d, 0xac, 0x37, 0x8d, 0x87, 0x2d, 0x8b, 0x91, 0x10, 0xa0, 0xb6, 0x5e, 0xdc, 0x4b, 0xd3, 0x96,
+	0xd9, 0xa1, 0x61, 0x3b, 0x33, 0x61, 0xa6, 0x87, 0xfe, 0x0b, 0xff, 0x85, 0x7f, 0x65, 0xbd, 0xed,
+	0xd1, 0x13, 0x31, 0xe5, 0x8f, 0x98, 0xb6, 0x28, 0x65, 0x63, 0xbc, 0xbd, 0xf7, 0x7d, 0xef, 0xbd,
+	0xcc, 0x6b, 0x3f, 0xd8, 0x95, 0x98, 0xae, 0xf0, 0x36, 0x0a, 0xa9, 0xd4, 0xb9, 0xc1, 0xf5, 0x80,
+	0x51, 0xaa, 0xf1, 0x2d, 0x93, 0x0c, 0xb5, 0x8e, 0x2b, 0x8d, 0x1b, 0xbc, 0x77, 0x49, 0x18, 0x61,
+	0xf9, 0x4a, 0xcf, 0x50, 0xa1, 0xea, 0x5d, 0x95, 0x02, 0x82, 0x6d, 0xc2, 0x25, 0xd3, 0x37, 0x38,
+	0x11, 0xc5, 0x76, 0xd8, 0x84, 0xd0, 0xf2, 0x82, 0x0d, 0x96, 0x56, 0x48, 0x49, 0x89, 0x31, 0x4a,
+	0x86, 0xdf, 0x01, 0xac, 0x17, 0x74, 0x2e, 0x08, 0x7a, 0x0d, 0x61, 0xb0, 0xf6, 0x28, 0xc5, 0xf7,
+	0x6e, 0xb8, 0xea, 0x80, 0x01, 0x18, 0x9d, 0x9b, 0xcf, 0xd3, 0x5d, 0xbf, 0x3e, 0x2e, 0xa6, 0xd3,
+	0x1b, 0xbb, 0x7e, 0x10, 0x4c, 0x57, 0xa8, 0x0b, 0x2b, 0x98, 0xdd, 0x75, 0xce, 0x06, 0x60, 0xf4,
+	0xcc, 0xbc, 0x48, 0x77, 0xfd, 0xca, 0x64, 0xf9, 0xd1, 0xce, 0x66, 0x08, 0xc1, 0xea, 0xca, 0x93,
+	0x5e, 0xa7, 0x32, 0x00, 0xa3, 0xa6, 0x9d, 0x63, 0xf4, 0x01, 0x36, 0x02, 0x16, 0xf1, 0x2d, 0x16,
+	0x22, 0x64, 0xb4, 0x53, 0x1d, 0x80, 0x51, 0xcb, 0x78, 0xa9, 0x9d, 0x16, 0xd4, 0xc6, 0x47, 0x89,
+	0x5d, 0xd6, 0x0f, 0x7f, 0x00, 0x58, 0x2b, 0x5e, 0x9a, 0x25, 0xf1, 0x1c, 0xb9, 0x3c, 0xa4, 0x24,
+	0x7f, 0x67, 0xc3, 0xe8, 0x3d, 0x4d, 0x3a, 0x76, 0xfe, 0xa4, 0xd8, 0x90, 0xff, 0x65, 0x65, 0x3b,
+	0xa3, 0xa4, 0x73, 0xf6, 0x5f, 0x3b, 0x3b, 0xb1, 0x33, 0x4a, 0xd0, 0x3b, 0x78, 0x60, 0x6e, 0x24,
+	0x48, 0xde, 0xb0, 0x61, 0x74, 0xff, 0xed, 0x9e, 0x8b, 0xcc, 0x5c, 0xe7, 0x7f, 0x88, 0x79, 0x0e,
+	0x2b, 0x22, 0x8e, 0x86, 0x2e, 0x6c, 0x5d, 0xc7, 0x72, 0xed, 0x84, 0x64, 0x8e, 0x85, 0xf0, 0x08,
+	0x46, 0xef, 0xe1, 0x05, 0x8f, 0x7d, 0x77, 0x83, 0x93, 0x43, 0x9d, 0xab, 0x72, 0x62, 0xf1, 0x4f,
+	0x35, 0x2b, 0xf6, 0xef, 0xc3, 0x60, 0x86, 0x13, 0xb3, 0xfa, 0xb0, 0xeb, 0x2b, 0x76, 0x8d, 0xc7,
+	0xfe, 0x0c, 0x27, 0xa8, 0x0d, 0x2b, 0x22, 0x2c, 0x8a, 0x34, 0xed, 0x0c, 0xbe, 0xfa, 0x0c, 0x1b,
+	0xa5, 0x0f, 0x89, 0x2e, 0x61, 0x7b, 0xbc, 0x9c, 0x5b, 0xf6, 0xc4, 0x71, 0xa6, 0xcb, 0x85, 0xbb,
+	0x58, 0x2e, 0x26, 0x6d, 0x05, 0xbd, 0x80, 0xa8, 0x3c, 0x75, 0x16, 0xd7, 0x96, 0xf5, 0xb5, 0x0d,
+	0x9e, 0xaa, 0x6f, 0x9d, 0x2f, 0x37, 0xed, 0x33, 0x73, 0xf6, 0x90, 0xaa, 0xe0, 0x31, 0x55, 0xc1,
+	0xaf, 0x54, 0x05, 0xdf, 0xf6, 0xaa, 0xf2, 0xb8, 0x57, 0x95, 0x9f, 0x7b, 0x55, 0xb9, 0x7d, 0x43,
+	0x42, 0xb9, 0x8e, 0x7d, 0x2d, 0x60, 0x91, 0x1e, 0xb0, 0x08, 0x4b, 0xff, 0x4e, 0x1e, 0x41, 0x71,
+	0xac, 0xa7, 0x17, 0xee, 0xd7, 0xf2, 0xe9, 0xdb, 0xdf, 0x03, 0x00, 0x6f, 0x1a, 0xc3, 0xae, 0xfa,
+	0x02, 0x00, 0x00,
 }
 
 func (m *PacketPing) Marshal() (dAtA []byte, err error) {
@@ -412,6 +455,11 @@ func (m *PacketMsg) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.Compression != 0 {
+		i = encodeVarintConn(dAtA, i, uint64(m.Compression))
+		i--
+		dAtA[i] = 0x20
+	}
 	if len(m.Data) > 0 {
 		i -= len(m.Data)
 		copy(dAtA[i:], m.Data)
@@ -617,6 +665,9 @@ func (m *PacketMsg) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovConn(uint64(l))
 	}
+	if m.Compression != 0 {
+		n += 1 + sovConn(uint64(m.Compression))
+	}
 	return n
 }
 
@@ -891,6 +942,25 @@ func (m *PacketMsg) Unmarshal(dAtA []byte) error {
 				m.Data = []byte{}
 			}
 			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Compression", wireType)
+			}
+			m.Compression = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConn
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Compression |= Compression(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipConn(dAtA[iNdEx:])
@@ -61,6 +61,9 @@ var xxx_messageInfo_PexRequest proto.InternalMessageInfo
 
 type PexAddrs struct {
 	Addrs []NetAddress `protobuf:"bytes,1,rep,name=addrs,proto3" json:"addrs"`
+	// liveness holds the liveness of each address of addrs, in the same order.
+	// It is empty when sent by nodes which don't track it.
+	Liveness []PexAddrLiveness `protobuf:"bytes,2,rep,name=liveness,proto3" json:"liveness"`
 }
 
 func (m *PexAddrs) Reset()         { *m = PexAddrs{} }
@@ -103,6 +106,70 @@ func (m *PexAddrs) GetAddrs() []NetAddress {
 	return nil
 }
 
+func (m *PexAddrs) GetLiveness() []PexAddrLiveness {
+	if m != nil {
+		return m.Liveness
+	}
+	return nil
+}
+
+// PexAddrLiveness is what the sender of PexAddrs knows of the liveness of an
+// address.
+type PexAddrLiveness struct {
+	// last_seen is the unix time, in seconds, at which the sender was last
+	// connected to the address, or 0 if it never was.
+	LastSeen int64 `protobuf:"varint,1,opt,name=last_seen,json=lastSeen,proto3" json:"last_seen,omitempty"`
+	// connected attests that the sender is connected to the address.
+	Connected bool `protobuf:"varint,2,opt,name=connected,proto3" json:"connected,omitempty"`
+}
+
+func (m *PexAddrLiveness) Reset()         { *m = PexAddrLiveness{} }
+func (m *PexAddrLiveness) String() string { return proto.CompactTextString(m) }
+func (*PexAddrLiveness) ProtoMessage()    {}
+func (*PexAddrLiveness) Descriptor() ([]byte, []int) {
+	return fileDescriptor_81c2f011fd13be57, []int{2}
+}
+func (m *PexAddrLiveness) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *PexAddrLiveness) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_PexAddrLiveness.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *PexAddrLiveness) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PexAddrLiveness.Merge(m, src)
+}
+func (m *PexAddrLiveness) XXX_Size() int {
+	return m.Size()
+}
+func (m *PexAddrLiveness) XXX_DiscardUnknown() {
+	xxx_messageInfo_PexAddrLiveness.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PexAddrLiveness proto.InternalMessageInfo
+
+func (m *PexAddrLiveness) GetLastSeen() int64 {
+	if m != nil {
+		return m.LastSeen
+	}
+	return 0
+}
+
+func (m *PexAddrLiveness) GetConnected() bool {
+	if m != nil {
+		return m.Connected
+	}
+	return false
+}
+
 type Message struct {
 	// Types that are valid to be assigned to Sum:
 	//	*Message_PexRequest
@@ -114,7 +181,7 @@ func (m *Message) Reset()         { *m = Message{} }
 func (m *Message) String() string { return proto.CompactTextString(m) }
 func (*Message) ProtoMessage()    {}
 func (*Message) Descriptor() ([]byte, []int) {
-	return fileDescriptor_81c2f011fd13be57, []int{2}
+	return fileDescriptor_81c2f011fd13be57, []int{3}
 }
 func (m *Message) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -191,31 +258,36 @@ func (*Message) XXX_OneofWrappers() []interface{} {
 func init() {
 	proto.RegisterType((*PexRequest)(nil), "tendermint.p2p.PexRequest")
 	proto.RegisterType((*PexAddrs)(nil), "tendermint.p2p.PexAddrs")
+	proto.RegisterType((*PexAddrLiveness)(nil), "tendermint.p2p.PexAddrLiveness")
 	proto.RegisterType((*Message)(nil), "tendermint.p2p.Message")
 }
 
 func init() { proto.RegisterFile("tendermint/p2p/pex.proto", fileDescriptor_81c2f011fd13be57) }
 
 var fileDescriptor_81c2f011fd13be57 = []byte{
-	// 273 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x92, 0x28, 0x49, 0xcd, 0x4b,
-	0x49, 0x2d, 0xca, 0xcd, 0xcc, 0x2b, 0xd1, 0x2f, 0x30, 0x2a, 0xd0, 0x2f, 0x48, 0xad, 0xd0, 0x2b,
-	0x28, 0xca, 0x2f, 0xc9, 0x17, 0xe2, 0x43, 0xc8, 0xe8, 0x15, 0x18, 0x15, 0x48, 0x49, 0xa1, 0xa9,
-	0x2c, 0xa9, 0x2c, 0x48, 0x2d, 0x86, 0xa8, 0x95, 0x12, 0x49, 0xcf, 0x4f, 0xcf, 0x07, 0x33, 0xf5,
-	0x41, 0x2c, 0x88, 0xa8, 0x12, 0x0f, 0x17, 0x57, 0x40, 0x6a, 0x45, 0x50, 0x6a, 0x61, 0x69, 0x6a,
-	0x71, 0x89, 0x92, 0x13, 0x17, 0x47, 0x40, 0x6a, 0x85, 0x63, 0x4a, 0x4a, 0x51, 0xb1, 0x90, 0x19,
-	0x17, 0x6b, 0x22, 0x88, 0x21, 0xc1, 0xa8, 0xc0, 0xac, 0xc1, 0x6d, 0x24, 0xa5, 0x87, 0x6a, 0x97,
-	0x9e, 0x5f, 0x6a, 0x09, 0x48, 0x61, 0x6a, 0x71, 0xb1, 0x13, 0xcb, 0x89, 0x7b, 0xf2, 0x0c, 0x41,
-	0x10, 0xe5, 0x4a, 0x1d, 0x8c, 0x5c, 0xec, 0xbe, 0xa9, 0xc5, 0xc5, 0x89, 0xe9, 0xa9, 0x42, 0xb6,
-	0x5c, 0xdc, 0x05, 0xa9, 0x15, 0xf1, 0x45, 0x10, 0xe3, 0x25, 0x18, 0x15, 0x18, 0xb1, 0x99, 0x84,
-	0x70, 0x80, 0x07, 0x43, 0x10, 0x57, 0x01, 0x9c, 0x27, 0x64, 0xce, 0xc5, 0x09, 0xd2, 0x0e, 0x71,
-	0x06, 0x13, 0x58, 0xb3, 0x04, 0x16, 0xcd, 0x60, 0xf7, 0x7a, 0x30, 0x04, 0x71, 0x14, 0x40, 0xd9,
-	0x4e, 0xac, 0x5c, 0xcc, 0xc5, 0xa5, 0xb9, 0x4e, 0xde, 0x27, 0x1e, 0xc9, 0x31, 0x5e, 0x78, 0x24,
-	0xc7, 0xf8, 0xe0, 0x91, 0x1c, 0xe3, 0x84, 0xc7, 0x72, 0x0c, 0x17, 0x1e, 0xcb, 0x31, 0xdc, 0x78,
-	0x2c, 0xc7, 0x10, 0x65, 0x98, 0x9e, 0x59, 0x92, 0x51, 0x9a, 0xa4, 0x97, 0x9c, 0x9f, 0xab, 0x9f,
-	0x9c, 0x9f, 0x9b, 0x5a, 0x92, 0x94, 0x56, 0x82, 0x60, 0x40, 0x42, 0x09, 0x35, 0x2c, 0x93, 0xd8,
-	0xc0, 0xa2, 0xc6, 0x80, 0x00, 0x00, 0x00, 0xff, 0xff, 0x02, 0xad, 0x52, 0xe1, 0x8e, 0x01, 0x00,
-	0x00,
+	// 341 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x91, 0xcf, 0x4a, 0xf3, 0x40,
+	0x14, 0xc5, 0x33, 0xed, 0xd7, 0xcf, 0xf4, 0x56, 0x14, 0x06, 0x17, 0x21, 0x4a, 0x5a, 0xb2, 0xea,
+	0x2a, 0xc1, 0x0a, 0xba, 0x72, 0xd1, 0xae, 0x0a, 0x56, 0x29, 0x71, 0xe7, 0xa6, 0xb4, 0xc9, 0x35,
+	0x16, 0x9a, 0x99, 0x31, 0x33, 0x95, 0xf8, 0x00, 0x82, 0x4b, 0x1f, 0xab, 0xcb, 0x2e, 0x5d, 0x89,
+	0xb4, 0x2f, 0x22, 0xf9, 0x43, 0x43, 0x83, 0xee, 0x4e, 0x4e, 0xce, 0x6f, 0x2e, 0xe7, 0x5e, 0x30,
+	0x14, 0xb2, 0x00, 0xe3, 0x68, 0xce, 0x94, 0x2b, 0x7a, 0xc2, 0x15, 0x98, 0x38, 0x22, 0xe6, 0x8a,
+	0xd3, 0xa3, 0xf2, 0x8f, 0x23, 0x7a, 0xc2, 0x34, 0x2b, 0x49, 0xf5, 0x2a, 0x50, 0xe6, 0x59, 0xf3,
+	0x24, 0xe4, 0x21, 0xcf, 0xa4, 0x9b, 0xaa, 0xdc, 0xb5, 0x0f, 0x01, 0xc6, 0x98, 0x78, 0xf8, 0xbc,
+	0x44, 0xa9, 0xec, 0x37, 0x02, 0xfa, 0x18, 0x93, 0x7e, 0x10, 0xc4, 0x92, 0x5e, 0x42, 0x63, 0x9a,
+	0x0a, 0x83, 0x74, 0xea, 0xdd, 0x56, 0xcf, 0x74, 0xf6, 0x87, 0x39, 0x77, 0xa8, 0xd2, 0x20, 0x4a,
+	0x39, 0xf8, 0xb7, 0xfa, 0x6a, 0x6b, 0x5e, 0x1e, 0xa7, 0x7d, 0xd0, 0x17, 0xf3, 0x17, 0x64, 0x28,
+	0xa5, 0x51, 0xcb, 0xd0, 0x76, 0x15, 0x2d, 0x66, 0x8c, 0x8a, 0x58, 0xc1, 0xef, 0x30, 0x7b, 0x04,
+	0xc7, 0x95, 0x08, 0x3d, 0x85, 0xe6, 0x62, 0x2a, 0xd5, 0x44, 0x22, 0x32, 0x83, 0x74, 0x48, 0xb7,
+	0xee, 0xe9, 0xa9, 0x71, 0x8f, 0xc8, 0xe8, 0x19, 0x34, 0x7d, 0xce, 0x18, 0xfa, 0x0a, 0x03, 0xa3,
+	0xd6, 0x21, 0x5d, 0xdd, 0x2b, 0x0d, 0xfb, 0x9d, 0xc0, 0xc1, 0x2d, 0x4a, 0x39, 0x0d, 0x91, 0x5e,
+	0x43, 0x4b, 0x60, 0x32, 0x89, 0xf3, 0xc2, 0xd9, 0x43, 0xbf, 0x54, 0x2b, 0x57, 0x32, 0xd4, 0x3c,
+	0x10, 0xbb, 0x2f, 0x7a, 0x05, 0xcd, 0x14, 0xcf, 0xf7, 0x52, 0xcb, 0x60, 0xe3, 0x8f, 0x72, 0x72,
+	0xa8, 0x79, 0xba, 0x28, 0xf4, 0xa0, 0x01, 0x75, 0xb9, 0x8c, 0x06, 0x37, 0xab, 0x8d, 0x45, 0xd6,
+	0x1b, 0x8b, 0x7c, 0x6f, 0x2c, 0xf2, 0xb1, 0xb5, 0xb4, 0xf5, 0xd6, 0xd2, 0x3e, 0xb7, 0x96, 0xf6,
+	0x70, 0x1e, 0xce, 0xd5, 0xd3, 0x72, 0xe6, 0xf8, 0x3c, 0x72, 0x7d, 0x1e, 0xa1, 0x9a, 0x3d, 0xaa,
+	0x52, 0xe4, 0x77, 0xdb, 0xbf, 0xee, 0xec, 0x7f, 0xe6, 0x5e, 0xfc, 0x0c, 0x00, 0xed, 0x44, 0x88,
+	0xbe, 0x20, 0x02, 0x00, 0x00,
 }
 
 func (m *PexRequest) Marshal() (dAtA []byte, err error) {
@@ -261,6 +333,20 @@ func (m *PexAddrs) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.Liveness) > 0 {
+		for iNdEx := len(m.Liveness) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Liveness[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintPex(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
 	if len(m.Addrs) > 0 {
 		for iNdEx := len(m.Addrs) - 1; iNdEx >= 0; iNdEx-- {
 			{
@@ -278,6 +364,44 @@ func (m *PexAddrs) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *PexAddrLiveness) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PexAddrLiveness) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PexAddrLiveness) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Connected {
+		i--
+		if m.Connected {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.LastSeen != 0 {
+		i = encodeVarintPex(dAtA, i, uint64(m.LastSeen))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
 func (m *Message) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -384,6 +508,27 @@ func (m *PexAddrs) Size() (n int) {
 			n += 1 + l + sovPex(uint64(l))
 		}
 	}
+	if len(m.Liveness) > 0 {
+		for _, e := range m.Liveness {
+			l = e.Size()
+			n += 1 + l + sovPex(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *PexAddrLiveness) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.LastSeen != 0 {
+		n += 1 + sovPex(uint64(m.LastSeen))
+	}
+	if m.Connected {
+		n += 2
+	}
 	return n
 }
 
@@ -543,6 +688,129 @@ func (m *PexAddrs) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Liveness", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPex
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPex
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthPex
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Liveness = append(m.Liveness, PexAddrLiveness{})
+			if err := m.Liveness[len(m.Liveness)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPex(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthPex
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *PexAddrLiveness) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowPex
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PexAddrLiveness: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PexAddrLiveness: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LastSeen", wireType)
+			}
+			m.LastSeen = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPex
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.LastSeen |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Connected", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPex
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Connected = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipPex(dAtA[iNdEx:])
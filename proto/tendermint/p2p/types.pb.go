@@ -246,6 +246,14 @@ func (m *DefaultNodeInfo) GetOther() DefaultNodeInfoOther {
 type DefaultNodeInfoOther struct {
 	TxIndex    string `protobuf:"bytes,1,opt,name=tx_index,json=txIndex,proto3" json:"tx_index,omitempty"`
 	RPCAddress string `protobuf:"bytes,2,opt,name=rpc_address,json=rpcAddress,proto3" json:"rpc_address,omitempty"`
+	// Comma separated list of the compression algorithms supported by the
+	// node for p2p messages, in order of preference.
+	Compression string `protobuf:"bytes,3,opt,name=compression,proto3" json:"compression,omitempty"`
+	// Role of the node in the network: full, validator, sentry or seed.
+	Role string `protobuf:"bytes,4,opt,name=role,proto3" json:"role,omitempty"`
+	// IP address of the peer receiving the node info, as observed by the
+	// node, with which nodes behind a NAT learn their external address.
+	ObservedIP string `protobuf:"bytes,5,opt,name=observed_ip,json=observedIp,proto3" json:"observed_ip,omitempty"`
 }
 
 func (m *DefaultNodeInfoOther) Reset()         { *m = DefaultNodeInfoOther{} }
@@ -295,6 +303,27 @@ func (m *DefaultNodeInfoOther) GetRPCAddress() string {
 	return ""
 }
 
+func (m *DefaultNodeInfoOther) GetCompression() string {
+	if m != nil {
+		return m.Compression
+	}
+	return ""
+}
+
+func (m *DefaultNodeInfoOther) GetRole() string {
+	if m != nil {
+		return m.Role
+	}
+	return ""
+}
+
+func (m *DefaultNodeInfoOther) GetObservedIP() string {
+	if m != nil {
+		return m.ObservedIP
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*NetAddress)(nil), "tendermint.p2p.NetAddress")
 	proto.RegisterType((*ProtocolVersion)(nil), "tendermint.p2p.ProtocolVersion")
@@ -305,38 +334,40 @@ func init() {
 func init() { proto.RegisterFile("tendermint/p2p/types.proto", fileDescriptor_c8a29e659aeca578) }
 
 var fileDescriptor_c8a29e659aeca578 = []byte{
-	// 483 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x52, 0x3d, 0x8f, 0xda, 0x40,
-	0x10, 0xc5, 0xc6, 0x7c, 0xdc, 0x10, 0x8e, 0xcb, 0x0a, 0x45, 0x3e, 0x0a, 0x1b, 0xa1, 0x14, 0x54,
-	0xa0, 0x90, 0x2a, 0x5d, 0x42, 0x68, 0x50, 0xa4, 0x8b, 0xb5, 0x8a, 0x52, 0xa4, 0x41, 0xe0, 0x5d,
-	0x60, 0x85, 0xd9, 0x5d, 0xad, 0xf7, 0x12, 0xf2, 0x2f, 0xf2, 0xb3, 0xae, 0xbc, 0x32, 0x95, 0x15,
-	0x99, 0x32, 0x7f, 0x22, 0xf2, 0xae, 0x2f, 0xc7, 0xa1, 0xeb, 0xe6, 0xcd, 0x9b, 0x99, 0x37, 0xf3,
-	0x34, 0xd0, 0xd3, 0x94, 0x13, 0xaa, 0xf6, 0x8c, 0xeb, 0xb1, 0x9c, 0xc8, 0xb1, 0xfe, 0x29, 0x69,
-	0x3a, 0x92, 0x4a, 0x68, 0x81, 0x2e, 0x1f, 0xb9, 0x91, 0x9c, 0xc8, 0x5e, 0x77, 0x23, 0x36, 0xc2,
-	0x50, 0xe3, 0x22, 0xb2, 0x55, 0x83, 0x08, 0xe0, 0x86, 0xea, 0x0f, 0x84, 0x28, 0x9a, 0xa6, 0xe8,
-	0x15, 0xb8, 0x8c, 0xf8, 0x4e, 0xdf, 0x19, 0x5e, 0x4c, 0xeb, 0x79, 0x16, 0xba, 0xf3, 0x19, 0x76,
-	0x19, 0x31, 0x79, 0xe9, 0xbb, 0x27, 0xf9, 0x08, 0xbb, 0x4c, 0x22, 0x04, 0x9e, 0x14, 0x4a, 0xfb,
-	0xd5, 0xbe, 0x33, 0x6c, 0x63, 0x13, 0x0f, 0xbe, 0x40, 0x27, 0x2a, 0x46, 0xc7, 0x22, 0xf9, 0x4a,
-	0x55, 0xca, 0x04, 0x47, 0xd7, 0x50, 0x95, 0x13, 0x69, 0xe6, 0x7a, 0xd3, 0x46, 0x9e, 0x85, 0xd5,
-	0x68, 0x12, 0xe1, 0x22, 0x87, 0xba, 0x50, 0x5b, 0x25, 0x22, 0xde, 0x99, 0xe1, 0x1e, 0xb6, 0x00,
-	0x5d, 0x41, 0x75, 0x29, 0xa5, 0x19, 0xeb, 0xe1, 0x22, 0x1c, 0xfc, 0x75, 0xa1, 0x33, 0xa3, 0xeb,
-	0xe5, 0x6d, 0xa2, 0x6f, 0x04, 0xa1, 0x73, 0xbe, 0x16, 0x28, 0x82, 0x2b, 0x59, 0x2a, 0x2d, 0xbe,
-	0x5b, 0x29, 0xa3, 0xd1, 0x9a, 0x84, 0xa3, 0xa7, 0xc7, 0x8f, 0xce, 0x36, 0x9a, 0x7a, 0x77, 0x59,
-	0x58, 0xc1, 0x1d, 0x79, 0xb6, 0xe8, 0x3b, 0xe8, 0x10, 0x2b, 0xb2, 0xe0, 0x82, 0xd0, 0x05, 0x23,
-	0xe5, 0xd1, 0x2f, 0xf3, 0x2c, 0x6c, 0x9f, 0xea, 0xcf, 0x70, 0x9b, 0x9c, 0x40, 0x82, 0x42, 0x68,
-	0x25, 0x2c, 0xd5, 0x94, 0x2f, 0x96, 0x84, 0x28, 0xb3, 0xfa, 0x05, 0x06, 0x9b, 0x2a, 0xec, 0x45,
-	0x3e, 0x34, 0x38, 0xd5, 0x3f, 0x84, 0xda, 0xf9, 0x9e, 0x21, 0x1f, 0x60, 0xc1, 0x3c, 0xac, 0x5f,
-	0xb3, 0x4c, 0x09, 0x51, 0x0f, 0x9a, 0xf1, 0x76, 0xc9, 0x39, 0x4d, 0x52, 0xbf, 0xde, 0x77, 0x86,
-	0x2f, 0xf0, 0x7f, 0x5c, 0x74, 0xed, 0x05, 0x67, 0x3b, 0xaa, 0xfc, 0x86, 0xed, 0x2a, 0x21, 0x7a,
-	0x0f, 0x35, 0xa1, 0xb7, 0x54, 0xf9, 0x4d, 0x63, 0xc6, 0xeb, 0x73, 0x33, 0xce, 0x7c, 0xfc, 0x5c,
-	0xd4, 0x96, 0x8e, 0xd8, 0xc6, 0xc1, 0x0a, 0xba, 0xcf, 0x15, 0xa1, 0x6b, 0x68, 0xea, 0xc3, 0x82,
-	0x71, 0x42, 0x0f, 0xf6, 0x4b, 0x70, 0x43, 0x1f, 0xe6, 0x05, 0x44, 0x63, 0x68, 0x29, 0x19, 0x9b,
-	0xe3, 0x69, 0x9a, 0x96, 0xb6, 0x5d, 0xe6, 0x59, 0x08, 0x38, 0xfa, 0x58, 0xfe, 0x17, 0x06, 0x25,
-	0xe3, 0x32, 0x9e, 0x7e, 0xba, 0xcb, 0x03, 0xe7, 0x3e, 0x0f, 0x9c, 0x3f, 0x79, 0xe0, 0xfc, 0x3a,
-	0x06, 0x95, 0xfb, 0x63, 0x50, 0xf9, 0x7d, 0x0c, 0x2a, 0xdf, 0xde, 0x6c, 0x98, 0xde, 0xde, 0xae,
-	0x46, 0xb1, 0xd8, 0x8f, 0x63, 0xb1, 0xa7, 0x7a, 0xb5, 0xd6, 0x8f, 0x81, 0x7d, 0xe1, 0xa7, 0x8f,
-	0xbf, 0xaa, 0x9b, 0xec, 0xdb, 0x7f, 0x01, 0x00, 0x00, 0xff, 0xff, 0xae, 0xdb, 0x56, 0x6d, 0x11,
-	0x03, 0x00, 0x00,
+	// 525 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x53, 0x41, 0x6f, 0xda, 0x30,
+	0x14, 0x26, 0x21, 0x94, 0xf6, 0xb1, 0x96, 0xce, 0x42, 0x53, 0xca, 0x21, 0x41, 0x68, 0x07, 0x4e,
+	0xa0, 0xb1, 0xd3, 0x6e, 0x1b, 0xe3, 0x82, 0x26, 0xb5, 0x91, 0x35, 0xed, 0xb0, 0x4b, 0x04, 0xb1,
+	0x81, 0x88, 0x60, 0x5b, 0x8e, 0xdb, 0xb1, 0x7f, 0xb1, 0x9f, 0xd5, 0xdd, 0x7a, 0xdc, 0x09, 0x4d,
+	0xe1, 0xb8, 0x3f, 0x31, 0xd9, 0x0e, 0x83, 0xa2, 0xdd, 0xbe, 0xef, 0x7b, 0xf6, 0xfb, 0xde, 0xfb,
+	0x64, 0x43, 0x5b, 0x51, 0x46, 0xa8, 0x5c, 0xa7, 0x4c, 0x0d, 0xc4, 0x50, 0x0c, 0xd4, 0x77, 0x41,
+	0xf3, 0xbe, 0x90, 0x5c, 0x71, 0x74, 0x75, 0xa8, 0xf5, 0xc5, 0x50, 0xb4, 0x5b, 0x0b, 0xbe, 0xe0,
+	0xa6, 0x34, 0xd0, 0xc8, 0x9e, 0xea, 0x46, 0x00, 0xb7, 0x54, 0x7d, 0x20, 0x44, 0xd2, 0x3c, 0x47,
+	0xaf, 0xc0, 0x4d, 0x89, 0xef, 0x74, 0x9c, 0xde, 0xc5, 0xe8, 0xac, 0xd8, 0x86, 0xee, 0x64, 0x8c,
+	0xdd, 0x94, 0x18, 0x5d, 0xf8, 0xee, 0x91, 0x1e, 0x61, 0x37, 0x15, 0x08, 0x81, 0x27, 0xb8, 0x54,
+	0x7e, 0xb5, 0xe3, 0xf4, 0x2e, 0xb1, 0xc1, 0xdd, 0xcf, 0xd0, 0x8c, 0x74, 0xeb, 0x84, 0x67, 0x5f,
+	0xa8, 0xcc, 0x53, 0xce, 0xd0, 0x0d, 0x54, 0xc5, 0x50, 0x98, 0xbe, 0xde, 0xa8, 0x5e, 0x6c, 0xc3,
+	0x6a, 0x34, 0x8c, 0xb0, 0xd6, 0x50, 0x0b, 0x6a, 0xb3, 0x8c, 0x27, 0x2b, 0xd3, 0xdc, 0xc3, 0x96,
+	0xa0, 0x6b, 0xa8, 0x4e, 0x85, 0x30, 0x6d, 0x3d, 0xac, 0x61, 0xf7, 0x8f, 0x0b, 0xcd, 0x31, 0x9d,
+	0x4f, 0xef, 0x33, 0x75, 0xcb, 0x09, 0x9d, 0xb0, 0x39, 0x47, 0x11, 0x5c, 0x8b, 0xd2, 0x29, 0x7e,
+	0xb0, 0x56, 0xc6, 0xa3, 0x31, 0x0c, 0xfb, 0xcf, 0x97, 0xef, 0x9f, 0x4c, 0x34, 0xf2, 0x1e, 0xb7,
+	0x61, 0x05, 0x37, 0xc5, 0xc9, 0xa0, 0xef, 0xa0, 0x49, 0xac, 0x49, 0xcc, 0x38, 0xa1, 0x71, 0x4a,
+	0xca, 0xa5, 0x5f, 0x16, 0xdb, 0xf0, 0xf2, 0xd8, 0x7f, 0x8c, 0x2f, 0xc9, 0x11, 0x25, 0x28, 0x84,
+	0x46, 0x96, 0xe6, 0x8a, 0xb2, 0x78, 0x4a, 0x88, 0x34, 0xa3, 0x5f, 0x60, 0xb0, 0x92, 0x8e, 0x17,
+	0xf9, 0x50, 0x67, 0x54, 0x7d, 0xe3, 0x72, 0xe5, 0x7b, 0xa6, 0xb8, 0xa7, 0xba, 0xb2, 0x1f, 0xbf,
+	0x66, 0x2b, 0x25, 0x45, 0x6d, 0x38, 0x4f, 0x96, 0x53, 0xc6, 0x68, 0x96, 0xfb, 0x67, 0x1d, 0xa7,
+	0xf7, 0x02, 0xff, 0xe3, 0xfa, 0xd6, 0x9a, 0xb3, 0x74, 0x45, 0xa5, 0x5f, 0xb7, 0xb7, 0x4a, 0x8a,
+	0xde, 0x43, 0x8d, 0xab, 0x25, 0x95, 0xfe, 0xb9, 0x09, 0xe3, 0xf5, 0x69, 0x18, 0x27, 0x39, 0xde,
+	0xe9, 0xb3, 0x65, 0x22, 0xf6, 0x62, 0xf7, 0xa7, 0x03, 0xad, 0xff, 0x9d, 0x42, 0x37, 0x70, 0xae,
+	0x36, 0x71, 0xca, 0x08, 0xdd, 0xd8, 0x67, 0x82, 0xeb, 0x6a, 0x33, 0xd1, 0x14, 0x0d, 0xa0, 0x21,
+	0x45, 0x62, 0xb6, 0xa7, 0x79, 0x5e, 0xe6, 0x76, 0x55, 0x6c, 0x43, 0xc0, 0xd1, 0xc7, 0xf2, 0x81,
+	0x61, 0x90, 0x22, 0x29, 0x31, 0xea, 0x40, 0x23, 0xe1, 0x6b, 0xa1, 0xb1, 0x5e, 0xdd, 0x26, 0x76,
+	0x2c, 0xe9, 0xe7, 0x25, 0x79, 0x46, 0xcb, 0xbc, 0x0c, 0xd6, 0x36, 0x7c, 0x96, 0x53, 0xf9, 0x40,
+	0x49, 0x9c, 0x0a, 0xbf, 0x76, 0xb0, 0xb9, 0x2b, 0xe5, 0x49, 0x84, 0x61, 0x7f, 0x64, 0x22, 0x46,
+	0x9f, 0x1e, 0x8b, 0xc0, 0x79, 0x2a, 0x02, 0xe7, 0x77, 0x11, 0x38, 0x3f, 0x76, 0x41, 0xe5, 0x69,
+	0x17, 0x54, 0x7e, 0xed, 0x82, 0xca, 0xd7, 0x37, 0x8b, 0x54, 0x2d, 0xef, 0x67, 0xfd, 0x84, 0xaf,
+	0x07, 0x09, 0x5f, 0x53, 0x35, 0x9b, 0xab, 0x03, 0xb0, 0x5f, 0xe5, 0xf9, 0x07, 0x9b, 0x9d, 0x19,
+	0xf5, 0xed, 0xdf, 0x01, 0x00, 0x7d, 0xbc, 0x30, 0xed, 0x79, 0x03, 0x00, 0x00,
 }
 
 func (m *NetAddress) Marshal() (dAtA []byte, err error) {
@@ -524,6 +555,27 @@ func (m *DefaultNodeInfoOther) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.ObservedIP) > 0 {
+		i -= len(m.ObservedIP)
+		copy(dAtA[i:], m.ObservedIP)
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.ObservedIP)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if len(m.Role) > 0 {
+		i -= len(m.Role)
+		copy(dAtA[i:], m.Role)
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.Role)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.Compression) > 0 {
+		i -= len(m.Compression)
+		copy(dAtA[i:], m.Compression)
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.Compression)))
+		i--
+		dAtA[i] = 0x1a
+	}
 	if len(m.RPCAddress) > 0 {
 		i -= len(m.RPCAddress)
 		copy(dAtA[i:], m.RPCAddress)
@@ -641,6 +693,18 @@ func (m *DefaultNodeInfoOther) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovTypes(uint64(l))
 	}
+	l = len(m.Compression)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	l = len(m.Role)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	l = len(m.ObservedIP)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
 	return n
 }
 
@@ -1293,6 +1357,102 @@ func (m *DefaultNodeInfoOther) Unmarshal(dAtA []byte) error {
 			}
 			m.RPCAddress = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Compression", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Compression = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Role", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Role = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ObservedIP", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ObservedIP = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTypes(dAtA[iNdEx:])
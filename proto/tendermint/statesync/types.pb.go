@@ -176,6 +176,9 @@ type SnapshotsResponse struct {
 	Chunks   uint32 `protobuf:"varint,3,opt,name=chunks,proto3" json:"chunks,omitempty"`
 	Hash     []byte `protobuf:"bytes,4,opt,name=hash,proto3" json:"hash,omitempty"`
 	Metadata []byte `protobuf:"bytes,5,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	// The SHA-256 hashes of the chunks, by index, if computed by the peer, to
+	// verify each chunk before it is applied.
+	ChunkHashes [][]byte `protobuf:"bytes,6,rep,name=chunk_hashes,json=chunkHashes,proto3" json:"chunk_hashes,omitempty"`
 }
 
 func (m *SnapshotsResponse) Reset()         { *m = SnapshotsResponse{} }
@@ -246,6 +249,13 @@ func (m *SnapshotsResponse) GetMetadata() []byte {
 	return nil
 }
 
+func (m *SnapshotsResponse) GetChunkHashes() [][]byte {
+	if m != nil {
+		return m.ChunkHashes
+	}
+	return nil
+}
+
 type ChunkRequest struct {
 	Height uint64 `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
 	Format uint32 `protobuf:"varint,2,opt,name=format,proto3" json:"format,omitempty"`
@@ -393,32 +403,33 @@ func init() {
 func init() { proto.RegisterFile("tendermint/statesync/types.proto", fileDescriptor_a1c2869546ca7914) }
 
 var fileDescriptor_a1c2869546ca7914 = []byte{
-	// 397 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x53, 0x3f, 0x6b, 0xdb, 0x40,
-	0x1c, 0x95, 0xfc, 0x9f, 0x5f, 0xad, 0x62, 0x1f, 0xa6, 0x88, 0x0e, 0xc2, 0xa8, 0xd0, 0x76, 0x92,
-	0xa0, 0x1d, 0xba, 0xbb, 0x8b, 0x0b, 0xed, 0xd0, 0x6b, 0x03, 0x21, 0x4b, 0x38, 0xcb, 0x67, 0x49,
-	0x04, 0x9d, 0x14, 0xfd, 0x4e, 0x10, 0x7f, 0x80, 0x4c, 0x59, 0xf2, 0xb1, 0x32, 0x7a, 0x0c, 0x99,
-	0x82, 0xfd, 0x45, 0x82, 0x4e, 0xb2, 0xac, 0x38, 0x26, 0x21, 0x90, 0xed, 0xde, 0xd3, 0xd3, 0xbb,
-	0xf7, 0x1e, 0x1c, 0x8c, 0x25, 0x17, 0x73, 0x9e, 0x46, 0xa1, 0x90, 0x2e, 0x4a, 0x26, 0x39, 0x2e,
-	0x85, 0xe7, 0xca, 0x65, 0xc2, 0xd1, 0x49, 0xd2, 0x58, 0xc6, 0x64, 0xb4, 0x53, 0x38, 0x95, 0xc2,
-	0xbe, 0x6b, 0x40, 0xf7, 0x0f, 0x47, 0x64, 0x3e, 0x27, 0x47, 0x30, 0x44, 0xc1, 0x12, 0x0c, 0x62,
-	0x89, 0xa7, 0x29, 0x3f, 0xcf, 0x38, 0x4a, 0x53, 0x1f, 0xeb, 0x5f, 0xdf, 0x7d, 0xfb, 0xec, 0x1c,
-	0xfa, 0xdb, 0xf9, 0xb7, 0x95, 0xd3, 0x42, 0x3d, 0xd5, 0xe8, 0x00, 0xf7, 0x38, 0x72, 0x0c, 0xa4,
-	0x6e, 0x8b, 0x49, 0x2c, 0x90, 0x9b, 0x0d, 0xe5, 0xfb, 0xe5, 0x45, 0xdf, 0x42, 0x3e, 0xd5, 0xe8,
-	0x10, 0xf7, 0x49, 0xf2, 0x0b, 0x0c, 0x2f, 0xc8, 0xc4, 0x59, 0x15, 0xb6, 0xa9, 0x4c, 0xed, 0xc3,
-	0xa6, 0x3f, 0x73, 0xe9, 0x2e, 0x68, 0xdf, 0xab, 0x61, 0xf2, 0x1b, 0xde, 0x6f, 0xad, 0xca, 0x80,
-	0x2d, 0xe5, 0xf5, 0xe9, 0x59, 0xaf, 0x2a, 0x9c, 0xe1, 0xd5, 0x89, 0x49, 0x1b, 0x9a, 0x98, 0x45,
-	0x36, 0x81, 0xc1, 0xfe, 0x42, 0xf6, 0x95, 0x0e, 0xc3, 0x27, 0xf5, 0xc8, 0x07, 0xe8, 0x04, 0x3c,
-	0xf4, 0x83, 0x62, 0xef, 0x16, 0x2d, 0x51, 0xce, 0x2f, 0xe2, 0x34, 0x62, 0x52, 0xed, 0x65, 0xd0,
-	0x12, 0xe5, 0xbc, 0xba, 0x11, 0x55, 0x65, 0x83, 0x96, 0x88, 0x10, 0x68, 0x05, 0x0c, 0x03, 0x15,
-	0xbe, 0x4f, 0xd5, 0x99, 0x7c, 0x84, 0x5e, 0xc4, 0x25, 0x9b, 0x33, 0xc9, 0xcc, 0xb6, 0xe2, 0x2b,
-	0x6c, 0xff, 0x87, 0x7e, 0x7d, 0x96, 0x57, 0xe7, 0x18, 0x41, 0x3b, 0x14, 0x73, 0x7e, 0x51, 0xc6,
-	0x28, 0x80, 0x7d, 0xa9, 0x83, 0xf1, 0x68, 0xa1, 0xb7, 0xf1, 0xcd, 0x59, 0xd5, 0xb3, 0xac, 0x57,
-	0x00, 0x62, 0x42, 0x37, 0x0a, 0x11, 0x43, 0xe1, 0xab, 0x7a, 0x3d, 0xba, 0x85, 0x93, 0xbf, 0x37,
-	0x6b, 0x4b, 0x5f, 0xad, 0x2d, 0xfd, 0x7e, 0x6d, 0xe9, 0xd7, 0x1b, 0x4b, 0x5b, 0x6d, 0x2c, 0xed,
-	0x76, 0x63, 0x69, 0x27, 0x3f, 0xfc, 0x50, 0x06, 0xd9, 0xcc, 0xf1, 0xe2, 0xc8, 0xf5, 0xe2, 0x88,
-	0xcb, 0xd9, 0x42, 0xee, 0x0e, 0xea, 0xc1, 0xb8, 0x87, 0x5e, 0xd4, 0xac, 0xa3, 0xbe, 0x7d, 0x7f,
-	0x08, 0x00, 0x00, 0xff, 0xff, 0x04, 0xbe, 0xb0, 0x90, 0x70, 0x03, 0x00, 0x00,
+	// 413 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x93, 0xcf, 0xaa, 0xd3, 0x40,
+	0x18, 0xc5, 0x93, 0xfe, 0xe7, 0x6b, 0x22, 0xed, 0x50, 0x24, 0xb8, 0x08, 0x35, 0x82, 0x76, 0x95,
+	0x80, 0x2e, 0xdc, 0xd7, 0x4d, 0x05, 0x5d, 0x38, 0x2a, 0x88, 0x9b, 0x32, 0x4d, 0xa7, 0x49, 0x90,
+	0x4c, 0x62, 0xbe, 0x09, 0xd8, 0x07, 0x70, 0xef, 0x93, 0xf8, 0x1c, 0x2e, 0xbb, 0x14, 0x57, 0xd2,
+	0xbe, 0xc8, 0x25, 0x93, 0x34, 0xcd, 0xed, 0x2d, 0xf7, 0x72, 0xe1, 0xee, 0xe6, 0x9c, 0x9e, 0xfe,
+	0x72, 0xe6, 0xc0, 0xc0, 0x54, 0x72, 0xb1, 0xe6, 0x59, 0x1c, 0x09, 0xe9, 0xa1, 0x64, 0x92, 0xe3,
+	0x56, 0xf8, 0x9e, 0xdc, 0xa6, 0x1c, 0xdd, 0x34, 0x4b, 0x64, 0x42, 0x26, 0xa7, 0x84, 0x5b, 0x27,
+	0x9c, 0x7f, 0x2d, 0xe8, 0xbf, 0xe7, 0x88, 0x2c, 0xe0, 0xe4, 0x33, 0x8c, 0x51, 0xb0, 0x14, 0xc3,
+	0x44, 0xe2, 0x32, 0xe3, 0xdf, 0x73, 0x8e, 0xd2, 0xd2, 0xa7, 0xfa, 0x6c, 0xf8, 0xf2, 0xb9, 0x7b,
+	0xe9, 0xdf, 0xee, 0xc7, 0x63, 0x9c, 0x96, 0xe9, 0x85, 0x46, 0x47, 0x78, 0xe6, 0x91, 0x2f, 0x40,
+	0x9a, 0x58, 0x4c, 0x13, 0x81, 0xdc, 0x6a, 0x29, 0xee, 0x8b, 0x3b, 0xb9, 0x65, 0x7c, 0xa1, 0xd1,
+	0x31, 0x9e, 0x9b, 0xe4, 0x2d, 0x98, 0x7e, 0x98, 0x8b, 0x6f, 0x75, 0xd9, 0xb6, 0x82, 0x3a, 0x97,
+	0xa1, 0x6f, 0x8a, 0xe8, 0xa9, 0xa8, 0xe1, 0x37, 0x34, 0x79, 0x07, 0x8f, 0x8e, 0xa8, 0xaa, 0x60,
+	0x47, 0xb1, 0x9e, 0xdd, 0xca, 0xaa, 0xcb, 0x99, 0x7e, 0xd3, 0x98, 0x77, 0xa1, 0x8d, 0x79, 0xec,
+	0x10, 0x18, 0x9d, 0x2f, 0xe4, 0xfc, 0xd6, 0x61, 0x7c, 0xe3, 0x7a, 0xe4, 0x31, 0xf4, 0x42, 0x1e,
+	0x05, 0x61, 0xb9, 0x77, 0x87, 0x56, 0xaa, 0xf0, 0x37, 0x49, 0x16, 0x33, 0xa9, 0xf6, 0x32, 0x69,
+	0xa5, 0x0a, 0x5f, 0x7d, 0x11, 0xd5, 0x95, 0x4d, 0x5a, 0x29, 0x42, 0xa0, 0x13, 0x32, 0x0c, 0x55,
+	0x79, 0x83, 0xaa, 0x33, 0x79, 0x02, 0x83, 0x98, 0x4b, 0xb6, 0x66, 0x92, 0x59, 0x5d, 0xe5, 0xd7,
+	0x9a, 0x3c, 0x85, 0x72, 0x86, 0x65, 0x91, 0xe4, 0x68, 0xf5, 0xa6, 0xed, 0x99, 0x41, 0x87, 0xca,
+	0x5b, 0x28, 0xcb, 0xf9, 0x04, 0x46, 0x73, 0xb9, 0x7b, 0x57, 0x9d, 0x40, 0x37, 0x12, 0x6b, 0xfe,
+	0xa3, 0x6a, 0x5a, 0x0a, 0xe7, 0xa7, 0x0e, 0xe6, 0xb5, 0x11, 0x1f, 0x86, 0x5b, 0xb8, 0xaa, 0x7c,
+	0xb5, 0x40, 0x29, 0x88, 0x05, 0xfd, 0x38, 0x42, 0x8c, 0x44, 0xa0, 0x16, 0x18, 0xd0, 0xa3, 0x9c,
+	0x7f, 0xf8, 0xb3, 0xb7, 0xf5, 0xdd, 0xde, 0xd6, 0xff, 0xef, 0x6d, 0xfd, 0xd7, 0xc1, 0xd6, 0x76,
+	0x07, 0x5b, 0xfb, 0x7b, 0xb0, 0xb5, 0xaf, 0xaf, 0x83, 0x48, 0x86, 0xf9, 0xca, 0xf5, 0x93, 0xd8,
+	0xf3, 0x93, 0x98, 0xcb, 0xd5, 0x46, 0x9e, 0x0e, 0xea, 0x4d, 0x79, 0x97, 0x1e, 0xdd, 0xaa, 0xa7,
+	0x7e, 0x7b, 0x75, 0x35, 0x00, 0x9c, 0xc7, 0x1f, 0x6a, 0x93, 0x03, 0x00, 0x00,
 }
 
 func (m *Message) Marshal() (dAtA []byte, err error) {
@@ -580,6 +591,15 @@ func (m *SnapshotsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.ChunkHashes) > 0 {
+		for iNdEx := len(m.ChunkHashes) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.ChunkHashes[iNdEx])
+			copy(dAtA[i:], m.ChunkHashes[iNdEx])
+			i = encodeVarintTypes(dAtA, i, uint64(len(m.ChunkHashes[iNdEx])))
+			i--
+			dAtA[i] = 0x32
+		}
+	}
 	if len(m.Metadata) > 0 {
 		i -= len(m.Metadata)
 		copy(dAtA[i:], m.Metadata)
@@ -808,6 +828,12 @@ func (m *SnapshotsResponse) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovTypes(uint64(l))
 	}
+	if len(m.ChunkHashes) > 0 {
+		for _, b := range m.ChunkHashes {
+			l = len(b)
+			n += 1 + l + sovTypes(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -1254,6 +1280,38 @@ func (m *SnapshotsResponse) Unmarshal(dAtA []byte) error {
 				m.Metadata = []byte{}
 			}
 			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ChunkHashes", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ChunkHashes = append(m.ChunkHashes, make([]byte, postIndex-iNdEx))
+			copy(m.ChunkHashes[len(m.ChunkHashes)-1], dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTypes(dAtA[iNdEx:])
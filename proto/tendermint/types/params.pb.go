@@ -179,6 +179,10 @@ type EvidenceParams struct {
 	// and should fall comfortably under the max block bytes.
 	// Default is 1048576 or 1MB
 	MaxBytes int64 `protobuf:"varint,3,opt,name=max_bytes,json=maxBytes,proto3" json:"max_bytes,omitempty"`
+	// This sets the maximum number of evidence items that can be committed in a
+	// single block. Default is 0, which means there is no limit other than
+	// max_bytes.
+	MaxCount int64 `protobuf:"varint,4,opt,name=max_count,json=maxCount,proto3" json:"max_count,omitempty"`
 }
 
 func (m *EvidenceParams) Reset()         { *m = EvidenceParams{} }
@@ -235,6 +239,13 @@ func (m *EvidenceParams) GetMaxBytes() int64 {
 	return 0
 }
 
+func (m *EvidenceParams) GetMaxCount() int64 {
+	if m != nil {
+		return m.MaxCount
+	}
+	return 0
+}
+
 // ValidatorParams restrict the public key types validators can use.
 // NOTE: uses ABCI pubkey naming, not Amino names.
 type ValidatorParams struct {
@@ -393,6 +404,11 @@ type ABCIParams struct {
 	// passed to the application for validation in VerifyVoteExtension and given
 	// to the application to use when proposing a block during PrepareProposal.
 	VoteExtensionsEnableHeight int64 `protobuf:"varint,1,opt,name=vote_extensions_enable_height,json=voteExtensionsEnableHeight,proto3" json:"vote_extensions_enable_height,omitempty"`
+	// commit_aggregation_enable_height configures the first height from which
+	// commits may carry a single aggregated signature of the validators instead
+	// of a signature per validator. It requires all validators to use a key type
+	// that supports signature aggregation. Zero disables commit aggregation.
+	CommitAggregationEnableHeight int64 `protobuf:"varint,2,opt,name=commit_aggregation_enable_height,json=commitAggregationEnableHeight,proto3" json:"commit_aggregation_enable_height,omitempty"`
 }
 
 func (m *ABCIParams) Reset()         { *m = ABCIParams{} }
@@ -435,6 +451,13 @@ func (m *ABCIParams) GetVoteExtensionsEnableHeight() int64 {
 	return 0
 }
 
+func (m *ABCIParams) GetCommitAggregationEnableHeight() int64 {
+	if m != nil {
+		return m.CommitAggregationEnableHeight
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*ConsensusParams)(nil), "tendermint.types.ConsensusParams")
 	proto.RegisterType((*BlockParams)(nil), "tendermint.types.BlockParams")
@@ -448,43 +471,46 @@ func init() {
 func init() { proto.RegisterFile("tendermint/types/params.proto", fileDescriptor_e12598271a686f57) }
 
 var fileDescriptor_e12598271a686f57 = []byte{
-	// 576 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x93, 0x3f, 0x6f, 0xd3, 0x4e,
-	0x18, 0xc7, 0x73, 0x75, 0xda, 0xa6, 0x4f, 0x7e, 0x69, 0xa2, 0xd3, 0x4f, 0xc2, 0x14, 0xe2, 0x04,
-	0x0f, 0xa8, 0x52, 0x25, 0x1b, 0x91, 0x09, 0x84, 0x54, 0x25, 0x25, 0x6a, 0x0b, 0x2a, 0x7f, 0x2c,
-	0xc4, 0xd0, 0xc5, 0x3a, 0x27, 0x57, 0xc7, 0x6a, 0xec, 0xb3, 0x7c, 0xe7, 0x28, 0x79, 0x17, 0x8c,
-	0x8c, 0x1d, 0x61, 0x65, 0xe2, 0x25, 0x74, 0xec, 0xc8, 0x04, 0x28, 0x59, 0x78, 0x19, 0xc8, 0x67,
-	0xbb, 0x6e, 0x12, 0xb6, 0xbb, 0x7b, 0x3e, 0x9f, 0xfb, 0xf3, 0x7d, 0x74, 0xd0, 0x14, 0x34, 0x18,
-	0xd2, 0xc8, 0xf7, 0x02, 0x61, 0x8a, 0x59, 0x48, 0xb9, 0x19, 0x92, 0x88, 0xf8, 0xdc, 0x08, 0x23,
-	0x26, 0x18, 0x6e, 0x14, 0x65, 0x43, 0x96, 0xf7, 0xfe, 0x77, 0x99, 0xcb, 0x64, 0xd1, 0x4c, 0x46,
-	0x29, 0xb7, 0xa7, 0xb9, 0x8c, 0xb9, 0x63, 0x6a, 0xca, 0x99, 0x13, 0x5f, 0x98, 0xc3, 0x38, 0x22,
-	0xc2, 0x63, 0x41, 0x5a, 0xd7, 0xbf, 0x6d, 0x40, 0xfd, 0x88, 0x05, 0x9c, 0x06, 0x3c, 0xe6, 0xef,
-	0xe4, 0x09, 0xb8, 0x03, 0x9b, 0xce, 0x98, 0x0d, 0x2e, 0x55, 0xd4, 0x46, 0xfb, 0xd5, 0xa7, 0x4d,
-	0x63, 0xf5, 0x2c, 0xa3, 0x97, 0x94, 0x53, 0xda, 0x4a, 0x59, 0xfc, 0x02, 0x2a, 0x74, 0xe2, 0x0d,
-	0x69, 0x30, 0xa0, 0xea, 0x86, 0xf4, 0xda, 0xeb, 0x5e, 0x3f, 0x23, 0x32, 0xf5, 0xd6, 0xc0, 0x87,
-	0xb0, 0x33, 0x21, 0x63, 0x6f, 0x48, 0x04, 0x8b, 0x54, 0x45, 0xea, 0x8f, 0xd6, 0xf5, 0x8f, 0x39,
-	0x92, 0xf9, 0x85, 0x83, 0x9f, 0xc1, 0xf6, 0x84, 0x46, 0xdc, 0x63, 0x81, 0x5a, 0x96, 0x7a, 0xeb,
-	0x1f, 0x7a, 0x0a, 0x64, 0x72, 0xce, 0xe3, 0x27, 0x50, 0x26, 0xce, 0xc0, 0x53, 0x37, 0xa5, 0xf7,
-	0x70, 0xdd, 0xeb, 0xf6, 0x8e, 0x4e, 0x33, 0x49, 0x92, 0xfa, 0x29, 0x54, 0xef, 0x24, 0x80, 0x1f,
-	0xc0, 0x8e, 0x4f, 0xa6, 0xb6, 0x33, 0x13, 0x94, 0xcb, 0xcc, 0x14, 0xab, 0xe2, 0x93, 0x69, 0x2f,
-	0x99, 0xe3, 0x7b, 0xb0, 0x9d, 0x14, 0x5d, 0xc2, 0x65, 0x2c, 0x8a, 0xb5, 0xe5, 0x93, 0xe9, 0x31,
-	0xe1, 0xaf, 0xca, 0x15, 0xa5, 0x51, 0xd6, 0xbf, 0x22, 0xd8, 0x5d, 0x4e, 0x05, 0x1f, 0x00, 0x4e,
-	0x0c, 0xe2, 0x52, 0x3b, 0x88, 0x7d, 0x5b, 0xc6, 0x9b, 0xef, 0x5b, 0xf7, 0xc9, 0xb4, 0xeb, 0xd2,
-	0x37, 0xb1, 0x2f, 0x2f, 0xc0, 0xf1, 0x19, 0x34, 0x72, 0x38, 0xef, 0x6c, 0x16, 0xff, 0x7d, 0x23,
-	0x6d, 0xbd, 0x91, 0xb7, 0xde, 0x78, 0x99, 0x01, 0xbd, 0xca, 0xf5, 0xcf, 0x56, 0xe9, 0xf3, 0xaf,
-	0x16, 0xb2, 0x76, 0xd3, 0xfd, 0xf2, 0xca, 0xf2, 0x53, 0x94, 0xe5, 0xa7, 0xe8, 0x87, 0x50, 0x5f,
-	0xe9, 0x00, 0xd6, 0xa1, 0x16, 0xc6, 0x8e, 0x7d, 0x49, 0x67, 0xb6, 0xcc, 0x4a, 0x45, 0x6d, 0x65,
-	0x7f, 0xc7, 0xaa, 0x86, 0xb1, 0xf3, 0x9a, 0xce, 0x3e, 0x24, 0x4b, 0xcf, 0x2b, 0xdf, 0xaf, 0x5a,
-	0xe8, 0xcf, 0x55, 0x0b, 0xe9, 0x07, 0x50, 0x5b, 0xea, 0x01, 0x6e, 0x80, 0x42, 0xc2, 0x50, 0xbe,
-	0xad, 0x6c, 0x25, 0xc3, 0x3b, 0xf0, 0x39, 0xfc, 0x77, 0x42, 0xf8, 0x88, 0x0e, 0x33, 0xf6, 0x31,
-	0xd4, 0x65, 0x14, 0xf6, 0x6a, 0xd6, 0x35, 0xb9, 0x7c, 0x96, 0x07, 0xae, 0x43, 0xad, 0xe0, 0x8a,
-	0xd8, 0xab, 0x39, 0x75, 0x4c, 0xb8, 0xfe, 0x16, 0xa0, 0x68, 0x2a, 0xee, 0x42, 0x73, 0xc2, 0x04,
-	0xb5, 0xe9, 0x54, 0xd0, 0x20, 0xb9, 0x1d, 0xb7, 0x69, 0x40, 0x9c, 0x31, 0xb5, 0x47, 0xd4, 0x73,
-	0x47, 0x22, 0x3b, 0x67, 0x2f, 0x81, 0xfa, 0xb7, 0x4c, 0x5f, 0x22, 0x27, 0x92, 0xe8, 0xbd, 0xff,
-	0x32, 0xd7, 0xd0, 0xf5, 0x5c, 0x43, 0x37, 0x73, 0x0d, 0xfd, 0x9e, 0x6b, 0xe8, 0xd3, 0x42, 0x2b,
-	0xdd, 0x2c, 0xb4, 0xd2, 0x8f, 0x85, 0x56, 0x3a, 0xef, 0xb8, 0x9e, 0x18, 0xc5, 0x8e, 0x31, 0x60,
-	0xbe, 0x39, 0x60, 0x3e, 0x15, 0xce, 0x85, 0x28, 0x06, 0xe9, 0x9f, 0x5d, 0xfd, 0xee, 0xce, 0x96,
-	0x5c, 0xef, 0xfc, 0x0d, 0x00, 0x00, 0xff, 0xff, 0xb9, 0xe8, 0xce, 0x9a, 0x09, 0x04, 0x00, 0x00,
+	// 611 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x94, 0x3f, 0x6f, 0xd3, 0x4e,
+	0x1c, 0xc6, 0xe3, 0x3a, 0x6d, 0xd3, 0x6f, 0x7e, 0x69, 0xa2, 0xd3, 0x4f, 0xc2, 0x14, 0xe2, 0x04,
+	0x0f, 0xa8, 0x52, 0x25, 0x07, 0xd1, 0x09, 0x84, 0x54, 0x25, 0xa5, 0x6a, 0x0b, 0x2a, 0x82, 0x08,
+	0x31, 0x74, 0xb1, 0xce, 0xce, 0xf5, 0x62, 0x35, 0xe7, 0xb3, 0x7c, 0xe7, 0x28, 0x79, 0x17, 0x8c,
+	0x1d, 0x3b, 0x32, 0x33, 0xf1, 0x0a, 0x50, 0xc7, 0x8e, 0x4c, 0x80, 0xd2, 0x85, 0x97, 0x81, 0x7c,
+	0xb6, 0xeb, 0xd6, 0x61, 0xbb, 0xbb, 0xe7, 0xf3, 0xdc, 0x9f, 0xe7, 0x91, 0x0e, 0xda, 0x92, 0x04,
+	0x23, 0x12, 0x31, 0x3f, 0x90, 0x3d, 0x39, 0x0f, 0x89, 0xe8, 0x85, 0x38, 0xc2, 0x4c, 0xd8, 0x61,
+	0xc4, 0x25, 0x47, 0xad, 0x42, 0xb6, 0x95, 0xbc, 0xf5, 0x3f, 0xe5, 0x94, 0x2b, 0xb1, 0x97, 0x8c,
+	0x52, 0x6e, 0xcb, 0xa4, 0x9c, 0xd3, 0x09, 0xe9, 0xa9, 0x99, 0x1b, 0x9f, 0xf5, 0x46, 0x71, 0x84,
+	0xa5, 0xcf, 0x83, 0x54, 0xb7, 0xbe, 0xae, 0x40, 0x73, 0x9f, 0x07, 0x82, 0x04, 0x22, 0x16, 0xef,
+	0xd5, 0x09, 0x68, 0x17, 0x56, 0xdd, 0x09, 0xf7, 0xce, 0x0d, 0xad, 0xab, 0x6d, 0xd7, 0x9f, 0xb7,
+	0xed, 0xf2, 0x59, 0xf6, 0x20, 0x91, 0x53, 0x7a, 0x98, 0xb2, 0xe8, 0x15, 0xd4, 0xc8, 0xd4, 0x1f,
+	0x91, 0xc0, 0x23, 0xc6, 0x8a, 0xf2, 0x75, 0x97, 0x7d, 0x07, 0x19, 0x91, 0x59, 0x6f, 0x1d, 0x68,
+	0x0f, 0x36, 0xa6, 0x78, 0xe2, 0x8f, 0xb0, 0xe4, 0x91, 0xa1, 0x2b, 0xfb, 0x93, 0x65, 0xfb, 0xa7,
+	0x1c, 0xc9, 0xfc, 0x85, 0x07, 0xbd, 0x80, 0xf5, 0x29, 0x89, 0x84, 0xcf, 0x03, 0xa3, 0xaa, 0xec,
+	0x9d, 0x7f, 0xd8, 0x53, 0x20, 0x33, 0xe7, 0x3c, 0x7a, 0x06, 0x55, 0xec, 0x7a, 0xbe, 0xb1, 0xaa,
+	0x7c, 0x8f, 0x97, 0x7d, 0xfd, 0xc1, 0xfe, 0x71, 0x66, 0x52, 0xa4, 0x75, 0x0c, 0xf5, 0x3b, 0x09,
+	0xa0, 0x47, 0xb0, 0xc1, 0xf0, 0xcc, 0x71, 0xe7, 0x92, 0x08, 0x95, 0x99, 0x3e, 0xac, 0x31, 0x3c,
+	0x1b, 0x24, 0x73, 0xf4, 0x00, 0xd6, 0x13, 0x91, 0x62, 0xa1, 0x62, 0xd1, 0x87, 0x6b, 0x0c, 0xcf,
+	0x0e, 0xb1, 0x78, 0x53, 0xad, 0xe9, 0xad, 0xaa, 0xf5, 0x5d, 0x83, 0xcd, 0xfb, 0xa9, 0xa0, 0x1d,
+	0x40, 0x89, 0x03, 0x53, 0xe2, 0x04, 0x31, 0x73, 0x54, 0xbc, 0xf9, 0xbe, 0x4d, 0x86, 0x67, 0x7d,
+	0x4a, 0xde, 0xc5, 0x4c, 0x5d, 0x40, 0xa0, 0x13, 0x68, 0xe5, 0x70, 0xde, 0x6c, 0x16, 0xff, 0x43,
+	0x3b, 0xad, 0xde, 0xce, 0xab, 0xb7, 0x5f, 0x67, 0xc0, 0xa0, 0x76, 0xf5, 0xb3, 0x53, 0xb9, 0xf8,
+	0xd5, 0xd1, 0x86, 0x9b, 0xe9, 0x7e, 0xb9, 0x72, 0xff, 0x29, 0x7a, 0xe9, 0x29, 0x99, 0xe8, 0xf1,
+	0x38, 0x90, 0x46, 0xf5, 0x56, 0xdc, 0x4f, 0xe6, 0xd6, 0x1e, 0x34, 0x4b, 0xf5, 0x20, 0x0b, 0x1a,
+	0x61, 0xec, 0x3a, 0xe7, 0x64, 0xee, 0xa8, 0x20, 0x0d, 0xad, 0xab, 0x6f, 0x6f, 0x0c, 0xeb, 0x61,
+	0xec, 0xbe, 0x25, 0xf3, 0x8f, 0xc9, 0xd2, 0xcb, 0xda, 0xb7, 0xcb, 0x8e, 0xf6, 0xe7, 0xb2, 0xa3,
+	0x59, 0x3b, 0xd0, 0xb8, 0x57, 0x10, 0x6a, 0x81, 0x8e, 0xc3, 0x50, 0x3d, 0xbc, 0x3a, 0x4c, 0x86,
+	0x77, 0xe0, 0x53, 0xf8, 0xef, 0x08, 0x8b, 0x31, 0x19, 0x65, 0xec, 0x53, 0x68, 0xaa, 0x9c, 0x9c,
+	0x72, 0x11, 0x0d, 0xb5, 0x7c, 0x92, 0x3f, 0xc1, 0x82, 0x46, 0xc1, 0x15, 0x9d, 0xd4, 0x73, 0xea,
+	0x10, 0x0b, 0xeb, 0x42, 0x03, 0x28, 0x2a, 0x47, 0x7d, 0x68, 0x4f, 0xb9, 0x24, 0x0e, 0x99, 0x49,
+	0x12, 0x24, 0xd7, 0x13, 0x0e, 0x09, 0xb0, 0x3b, 0x21, 0xce, 0x98, 0xf8, 0x74, 0x2c, 0xb3, 0x83,
+	0xb6, 0x12, 0xe8, 0xe0, 0x96, 0x39, 0x50, 0xc8, 0x91, 0x22, 0xd0, 0x21, 0x74, 0x3d, 0xce, 0x98,
+	0x2f, 0x1d, 0x4c, 0x69, 0x44, 0xa8, 0xca, 0xba, 0xb4, 0x4b, 0x7a, 0x91, 0x76, 0xca, 0xf5, 0x0b,
+	0xec, 0xee, 0x46, 0x83, 0x0f, 0x5f, 0x16, 0xa6, 0x76, 0xb5, 0x30, 0xb5, 0xeb, 0x85, 0xa9, 0xfd,
+	0x5e, 0x98, 0xda, 0xe7, 0x1b, 0xb3, 0x72, 0x7d, 0x63, 0x56, 0x7e, 0xdc, 0x98, 0x95, 0xd3, 0x5d,
+	0xea, 0xcb, 0x71, 0xec, 0xda, 0x1e, 0x67, 0x3d, 0x8f, 0x33, 0x22, 0xdd, 0x33, 0x59, 0x0c, 0xd2,
+	0xaf, 0xa1, 0xfc, 0xab, 0xb8, 0x6b, 0x6a, 0x7d, 0xf7, 0xef, 0x00, 0x61, 0x8a, 0x28, 0xd9, 0x70,
+	0x04, 0x00, 0x00,
 }
 
 func (this *ConsensusParams) Equal(that interface{}) bool {
@@ -578,6 +604,9 @@ func (this *EvidenceParams) Equal(that interface{}) bool {
 	if this.MaxBytes != that1.MaxBytes {
 		return false
 	}
+	if this.MaxCount != that1.MaxCount {
+		return false
+	}
 	return true
 }
 func (this *ValidatorParams) Equal(that interface{}) bool {
@@ -682,6 +711,9 @@ func (this *ABCIParams) Equal(that interface{}) bool {
 	if this.VoteExtensionsEnableHeight != that1.VoteExtensionsEnableHeight {
 		return false
 	}
+	if this.CommitAggregationEnableHeight != that1.CommitAggregationEnableHeight {
+		return false
+	}
 	return true
 }
 func (m *ConsensusParams) Marshal() (dAtA []byte, err error) {
@@ -820,6 +852,11 @@ func (m *EvidenceParams) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.MaxCount != 0 {
+		i = encodeVarintParams(dAtA, i, uint64(m.MaxCount))
+		i--
+		dAtA[i] = 0x20
+	}
 	if m.MaxBytes != 0 {
 		i = encodeVarintParams(dAtA, i, uint64(m.MaxBytes))
 		i--
@@ -954,6 +991,11 @@ func (m *ABCIParams) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.CommitAggregationEnableHeight != 0 {
+		i = encodeVarintParams(dAtA, i, uint64(m.CommitAggregationEnableHeight))
+		i--
+		dAtA[i] = 0x10
+	}
 	if m.VoteExtensionsEnableHeight != 0 {
 		i = encodeVarintParams(dAtA, i, uint64(m.VoteExtensionsEnableHeight))
 		i--
@@ -1123,6 +1165,9 @@ func (m *EvidenceParams) Size() (n int) {
 	if m.MaxBytes != 0 {
 		n += 1 + sovParams(uint64(m.MaxBytes))
 	}
+	if m.MaxCount != 0 {
+		n += 1 + sovParams(uint64(m.MaxCount))
+	}
 	return n
 }
 
@@ -1177,6 +1222,9 @@ func (m *ABCIParams) Size() (n int) {
 	if m.VoteExtensionsEnableHeight != 0 {
 		n += 1 + sovParams(uint64(m.VoteExtensionsEnableHeight))
 	}
+	if m.CommitAggregationEnableHeight != 0 {
+		n += 1 + sovParams(uint64(m.CommitAggregationEnableHeight))
+	}
 	return n
 }
 
@@ -1604,6 +1652,25 @@ func (m *EvidenceParams) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxCount", wireType)
+			}
+			m.MaxCount = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowParams
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxCount |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipParams(dAtA[iNdEx:])
@@ -1912,6 +1979,25 @@ func (m *ABCIParams) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CommitAggregationEnableHeight", wireType)
+			}
+			m.CommitAggregationEnableHeight = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowParams
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.CommitAggregationEnableHeight |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipParams(dAtA[iNdEx:])
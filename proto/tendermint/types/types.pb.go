@@ -6,6 +6,7 @@ package types
 import (
 	fmt "fmt"
 	crypto "github.com/cometbft/cometbft/proto/tendermint/crypto"
+	bits "github.com/cometbft/cometbft/proto/tendermint/libs/bits"
 	version "github.com/cometbft/cometbft/proto/tendermint/version"
 	_ "github.com/cosmos/gogoproto/gogoproto"
 	proto "github.com/cosmos/gogoproto/proto"
@@ -389,6 +390,8 @@ type Data struct {
 	// NOTE: not all txs here are valid.  We're just agreeing on the order first.
 	// This means that block.AppHash does not include these txs.
 	Txs [][]byte `protobuf:"bytes,1,rep,name=txs,proto3" json:"txs,omitempty"`
+	// Blobs that are published in this block alongside the txs.
+	Blobs []*Blob `protobuf:"bytes,4,rep,name=blobs,proto3" json:"blobs,omitempty"`
 	// SquareSize is the number of rows or columns in the original data square.
 	SquareSize uint64 `protobuf:"varint,5,opt,name=square_size,json=squareSize,proto3" json:"square_size,omitempty"`
 	// Hash is the root of a binary Merkle tree where the leaves of the tree are
@@ -437,6 +440,13 @@ func (m *Data) GetTxs() [][]byte {
 	return nil
 }
 
+func (m *Data) GetBlobs() []*Blob {
+	if m != nil {
+		return m.Blobs
+	}
+	return nil
+}
+
 func (m *Data) GetSquareSize() uint64 {
 	if m != nil {
 		return m.SquareSize
@@ -786,6 +796,94 @@ func (m *CommitSig) GetSignature() []byte {
 	return nil
 }
 
+// AggregatedCommit is a commit carrying a single aggregated signature of the
+// validators that voted for the block, along with a bitmap of them, instead of
+// a CommitSig per validator.
+type AggregatedCommit struct {
+	Height  int64          `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+	Round   int32          `protobuf:"varint,2,opt,name=round,proto3" json:"round,omitempty"`
+	BlockID BlockID        `protobuf:"bytes,3,opt,name=block_id,json=blockId,proto3" json:"block_id"`
+	Signers *bits.BitArray `protobuf:"bytes,4,opt,name=signers,proto3" json:"signers,omitempty"`
+	// the timestamp of the vote of each signer, in order.
+	Timestamps []time.Time `protobuf:"bytes,5,rep,name=timestamps,proto3,stdtime" json:"timestamps"`
+	Signature  []byte      `protobuf:"bytes,6,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (m *AggregatedCommit) Reset()         { *m = AggregatedCommit{} }
+func (m *AggregatedCommit) String() string { return proto.CompactTextString(m) }
+func (*AggregatedCommit) ProtoMessage()    {}
+func (*AggregatedCommit) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d3a6e55e2345de56, []int{9}
+}
+func (m *AggregatedCommit) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *AggregatedCommit) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_AggregatedCommit.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *AggregatedCommit) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AggregatedCommit.Merge(m, src)
+}
+func (m *AggregatedCommit) XXX_Size() int {
+	return m.Size()
+}
+func (m *AggregatedCommit) XXX_DiscardUnknown() {
+	xxx_messageInfo_AggregatedCommit.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AggregatedCommit proto.InternalMessageInfo
+
+func (m *AggregatedCommit) GetHeight() int64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *AggregatedCommit) GetRound() int32 {
+	if m != nil {
+		return m.Round
+	}
+	return 0
+}
+
+func (m *AggregatedCommit) GetBlockID() BlockID {
+	if m != nil {
+		return m.BlockID
+	}
+	return BlockID{}
+}
+
+func (m *AggregatedCommit) GetSigners() *bits.BitArray {
+	if m != nil {
+		return m.Signers
+	}
+	return nil
+}
+
+func (m *AggregatedCommit) GetTimestamps() []time.Time {
+	if m != nil {
+		return m.Timestamps
+	}
+	return nil
+}
+
+func (m *AggregatedCommit) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
 type ExtendedCommit struct {
 	Height             int64               `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
 	Round              int32               `protobuf:"varint,2,opt,name=round,proto3" json:"round,omitempty"`
@@ -797,7 +895,7 @@ func (m *ExtendedCommit) Reset()         { *m = ExtendedCommit{} }
 func (m *ExtendedCommit) String() string { return proto.CompactTextString(m) }
 func (*ExtendedCommit) ProtoMessage()    {}
 func (*ExtendedCommit) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d3a6e55e2345de56, []int{9}
+	return fileDescriptor_d3a6e55e2345de56, []int{10}
 }
 func (m *ExtendedCommit) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -872,7 +970,7 @@ func (m *ExtendedCommitSig) Reset()         { *m = ExtendedCommitSig{} }
 func (m *ExtendedCommitSig) String() string { return proto.CompactTextString(m) }
 func (*ExtendedCommitSig) ProtoMessage()    {}
 func (*ExtendedCommitSig) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d3a6e55e2345de56, []int{10}
+	return fileDescriptor_d3a6e55e2345de56, []int{11}
 }
 func (m *ExtendedCommitSig) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -957,7 +1055,7 @@ func (m *Proposal) Reset()         { *m = Proposal{} }
 func (m *Proposal) String() string { return proto.CompactTextString(m) }
 func (*Proposal) ProtoMessage()    {}
 func (*Proposal) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d3a6e55e2345de56, []int{11}
+	return fileDescriptor_d3a6e55e2345de56, []int{12}
 }
 func (m *Proposal) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1044,7 +1142,7 @@ func (m *SignedHeader) Reset()         { *m = SignedHeader{} }
 func (m *SignedHeader) String() string { return proto.CompactTextString(m) }
 func (*SignedHeader) ProtoMessage()    {}
 func (*SignedHeader) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d3a6e55e2345de56, []int{12}
+	return fileDescriptor_d3a6e55e2345de56, []int{13}
 }
 func (m *SignedHeader) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1096,7 +1194,7 @@ func (m *LightBlock) Reset()         { *m = LightBlock{} }
 func (m *LightBlock) String() string { return proto.CompactTextString(m) }
 func (*LightBlock) ProtoMessage()    {}
 func (*LightBlock) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d3a6e55e2345de56, []int{13}
+	return fileDescriptor_d3a6e55e2345de56, []int{14}
 }
 func (m *LightBlock) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1144,13 +1242,15 @@ type BlockMeta struct {
 	BlockSize int64   `protobuf:"varint,2,opt,name=block_size,json=blockSize,proto3" json:"block_size,omitempty"`
 	Header    Header  `protobuf:"bytes,3,opt,name=header,proto3" json:"header"`
 	NumTxs    int64   `protobuf:"varint,4,opt,name=num_txs,json=numTxs,proto3" json:"num_txs,omitempty"`
+	// Size of the data square of the block, see Data.square_size.
+	SquareSize uint64 `protobuf:"varint,5,opt,name=square_size,json=squareSize,proto3" json:"square_size,omitempty"`
 }
 
 func (m *BlockMeta) Reset()         { *m = BlockMeta{} }
 func (m *BlockMeta) String() string { return proto.CompactTextString(m) }
 func (*BlockMeta) ProtoMessage()    {}
 func (*BlockMeta) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d3a6e55e2345de56, []int{14}
+	return fileDescriptor_d3a6e55e2345de56, []int{15}
 }
 func (m *BlockMeta) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1207,6 +1307,13 @@ func (m *BlockMeta) GetNumTxs() int64 {
 	return 0
 }
 
+func (m *BlockMeta) GetSquareSize() uint64 {
+	if m != nil {
+		return m.SquareSize
+	}
+	return 0
+}
+
 // TxProof represents a Merkle proof of the presence of a transaction in the Merkle tree.
 type TxProof struct {
 	RootHash []byte        `protobuf:"bytes,1,opt,name=root_hash,json=rootHash,proto3" json:"root_hash,omitempty"`
@@ -1218,7 +1325,7 @@ func (m *TxProof) Reset()         { *m = TxProof{} }
 func (m *TxProof) String() string { return proto.CompactTextString(m) }
 func (*TxProof) ProtoMessage()    {}
 func (*TxProof) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d3a6e55e2345de56, []int{15}
+	return fileDescriptor_d3a6e55e2345de56, []int{16}
 }
 func (m *TxProof) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1280,7 +1387,7 @@ func (m *IndexWrapper) Reset()         { *m = IndexWrapper{} }
 func (m *IndexWrapper) String() string { return proto.CompactTextString(m) }
 func (*IndexWrapper) ProtoMessage()    {}
 func (*IndexWrapper) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d3a6e55e2345de56, []int{16}
+	return fileDescriptor_d3a6e55e2345de56, []int{17}
 }
 func (m *IndexWrapper) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1343,7 +1450,7 @@ func (m *BlobTx) Reset()         { *m = BlobTx{} }
 func (m *BlobTx) String() string { return proto.CompactTextString(m) }
 func (*BlobTx) ProtoMessage()    {}
 func (*BlobTx) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d3a6e55e2345de56, []int{17}
+	return fileDescriptor_d3a6e55e2345de56, []int{18}
 }
 func (m *BlobTx) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1407,7 +1514,7 @@ func (m *ShareProof) Reset()         { *m = ShareProof{} }
 func (m *ShareProof) String() string { return proto.CompactTextString(m) }
 func (*ShareProof) ProtoMessage()    {}
 func (*ShareProof) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d3a6e55e2345de56, []int{18}
+	return fileDescriptor_d3a6e55e2345de56, []int{19}
 }
 func (m *ShareProof) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1485,7 +1592,7 @@ func (m *RowProof) Reset()         { *m = RowProof{} }
 func (m *RowProof) String() string { return proto.CompactTextString(m) }
 func (*RowProof) ProtoMessage()    {}
 func (*RowProof) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d3a6e55e2345de56, []int{19}
+	return fileDescriptor_d3a6e55e2345de56, []int{20}
 }
 func (m *RowProof) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1575,7 +1682,7 @@ func (m *NMTProof) Reset()         { *m = NMTProof{} }
 func (m *NMTProof) String() string { return proto.CompactTextString(m) }
 func (*NMTProof) ProtoMessage()    {}
 func (*NMTProof) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d3a6e55e2345de56, []int{20}
+	return fileDescriptor_d3a6e55e2345de56, []int{21}
 }
 func (m *NMTProof) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1643,6 +1750,7 @@ func init() {
 	proto.RegisterType((*Vote)(nil), "tendermint.types.Vote")
 	proto.RegisterType((*Commit)(nil), "tendermint.types.Commit")
 	proto.RegisterType((*CommitSig)(nil), "tendermint.types.CommitSig")
+	proto.RegisterType((*AggregatedCommit)(nil), "tendermint.types.AggregatedCommit")
 	proto.RegisterType((*ExtendedCommit)(nil), "tendermint.types.ExtendedCommit")
 	proto.RegisterType((*ExtendedCommitSig)(nil), "tendermint.types.ExtendedCommitSig")
 	proto.RegisterType((*Proposal)(nil), "tendermint.types.Proposal")
@@ -1660,111 +1768,116 @@ func init() {
 func init() { proto.RegisterFile("tendermint/types/types.proto", fileDescriptor_d3a6e55e2345de56) }
 
 var fileDescriptor_d3a6e55e2345de56 = []byte{
-	// 1661 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xdc, 0x58, 0xcd, 0x8f, 0x1a, 0xc9,
-	0x15, 0x9f, 0x86, 0x06, 0x9a, 0x07, 0xcc, 0x30, 0x9d, 0x91, 0x8d, 0xb1, 0x87, 0x21, 0x58, 0x49,
-	0x26, 0x8e, 0xc5, 0x58, 0xe3, 0x28, 0x1f, 0x07, 0x1f, 0xe6, 0x2b, 0x36, 0x8e, 0x99, 0x41, 0x0d,
-	0x1e, 0x2b, 0x56, 0xa4, 0x56, 0x43, 0xd7, 0x40, 0xc7, 0xd0, 0xd5, 0xe9, 0x2e, 0x66, 0x18, 0xff,
-	0x05, 0x91, 0x2f, 0xf1, 0x29, 0x37, 0x9f, 0x9c, 0x43, 0xee, 0x89, 0x94, 0x6b, 0x94, 0x93, 0x8f,
-	0xbe, 0x25, 0x97, 0x38, 0xbb, 0x63, 0x69, 0xb5, 0x7f, 0xc6, 0xaa, 0x5e, 0x55, 0x37, 0x30, 0xc0,
-	0xae, 0x65, 0x59, 0xbb, 0xd2, 0x5e, 0x50, 0xd5, 0x7b, 0xbf, 0xf7, 0x51, 0xef, 0xa3, 0xfa, 0x15,
-	0x70, 0x83, 0x11, 0xd7, 0x26, 0xfe, 0xc0, 0x71, 0xd9, 0x16, 0x3b, 0xf7, 0x48, 0x20, 0x7e, 0xab,
-	0x9e, 0x4f, 0x19, 0xd5, 0xf3, 0x63, 0x6e, 0x15, 0xe9, 0xc5, 0xb5, 0x2e, 0xed, 0x52, 0x64, 0x6e,
-	0xf1, 0x95, 0xc0, 0x15, 0x37, 0xba, 0x94, 0x76, 0xfb, 0x64, 0x0b, 0x77, 0xed, 0xe1, 0xc9, 0x16,
-	0x73, 0x06, 0x24, 0x60, 0xd6, 0xc0, 0x93, 0x80, 0xf5, 0x09, 0x33, 0x1d, 0xff, 0xdc, 0x63, 0x94,
-	0x63, 0xe9, 0x89, 0x64, 0x97, 0x26, 0xd8, 0xa7, 0xc4, 0x0f, 0x1c, 0xea, 0x4e, 0xfa, 0x51, 0x2c,
-	0xcf, 0x78, 0x79, 0x6a, 0xf5, 0x1d, 0xdb, 0x62, 0xd4, 0x17, 0x88, 0xca, 0xaf, 0x21, 0xd7, 0xb0,
-	0x7c, 0xd6, 0x24, 0xec, 0x01, 0xb1, 0x6c, 0xe2, 0xeb, 0x6b, 0x90, 0x60, 0x94, 0x59, 0xfd, 0x82,
-	0x52, 0x56, 0x36, 0x73, 0x86, 0xd8, 0xe8, 0x3a, 0xa8, 0x3d, 0x2b, 0xe8, 0x15, 0x62, 0x65, 0x65,
-	0x33, 0x6b, 0xe0, 0xba, 0xd2, 0x03, 0x95, 0x8b, 0x72, 0x09, 0xc7, 0xb5, 0xc9, 0x28, 0x94, 0xc0,
-	0x0d, 0xa7, 0xb6, 0xcf, 0x19, 0x09, 0xa4, 0x88, 0xd8, 0xe8, 0x3f, 0x87, 0x04, 0xfa, 0x5f, 0x88,
-	0x97, 0x95, 0xcd, 0xcc, 0x76, 0xa1, 0x3a, 0x11, 0x28, 0x71, 0xbe, 0x6a, 0x83, 0xf3, 0x77, 0xd5,
-	0x37, 0xef, 0x36, 0x96, 0x0c, 0x01, 0xae, 0xf4, 0x21, 0xb5, 0xdb, 0xa7, 0x9d, 0x67, 0xb5, 0xfd,
-	0xc8, 0x11, 0x65, 0xec, 0x88, 0x5e, 0x87, 0x15, 0xcf, 0xf2, 0x99, 0x19, 0x10, 0x66, 0xf6, 0xf0,
-	0x14, 0x68, 0x34, 0xb3, 0xbd, 0x51, 0xbd, 0x9c, 0x87, 0xea, 0xd4, 0x61, 0xa5, 0x95, 0x9c, 0x37,
-	0x49, 0xac, 0x7c, 0xa1, 0x42, 0x52, 0x06, 0xe3, 0x1e, 0xa4, 0x64, 0x58, 0xd1, 0x60, 0x66, 0x7b,
-	0x7d, 0x52, 0xa3, 0x64, 0x55, 0xf7, 0xa8, 0x1b, 0x10, 0x37, 0x18, 0x06, 0x52, 0x5f, 0x28, 0xa3,
-	0xff, 0x18, 0xb4, 0x4e, 0xcf, 0x72, 0x5c, 0xd3, 0xb1, 0xd1, 0xa3, 0xf4, 0x6e, 0xe6, 0xe2, 0xdd,
-	0x46, 0x6a, 0x8f, 0xd3, 0x6a, 0xfb, 0x46, 0x0a, 0x99, 0x35, 0x5b, 0xbf, 0x02, 0xc9, 0x1e, 0x71,
-	0xba, 0x3d, 0x86, 0x61, 0x89, 0x1b, 0x72, 0xa7, 0xff, 0x0a, 0x54, 0x5e, 0x10, 0x05, 0x15, 0x6d,
-	0x17, 0xab, 0xa2, 0x5a, 0xaa, 0x61, 0xb5, 0x54, 0x5b, 0x61, 0xb5, 0xec, 0x6a, 0xdc, 0xf0, 0xcb,
-	0xff, 0x6f, 0x28, 0x06, 0x4a, 0xe8, 0x7b, 0x90, 0xeb, 0x5b, 0x01, 0x33, 0xdb, 0x3c, 0x6c, 0xdc,
-	0x7c, 0x02, 0x55, 0x5c, 0x9b, 0x0d, 0x88, 0x0c, 0xac, 0x74, 0x3d, 0xc3, 0xa5, 0x04, 0xc9, 0xd6,
-	0x37, 0x21, 0x8f, 0x4a, 0x3a, 0x74, 0x30, 0x70, 0x98, 0x89, 0x71, 0x4f, 0x62, 0xdc, 0x97, 0x39,
-	0x7d, 0x0f, 0xc9, 0x0f, 0x78, 0x06, 0xae, 0x43, 0xda, 0xb6, 0x98, 0x25, 0x20, 0x29, 0x84, 0x68,
-	0x9c, 0x80, 0xcc, 0x9f, 0xc0, 0x4a, 0x54, 0x75, 0x81, 0x80, 0x68, 0x42, 0xcb, 0x98, 0x8c, 0xc0,
-	0x3b, 0xb0, 0xe6, 0x92, 0x11, 0x33, 0x2f, 0xa3, 0xd3, 0x88, 0xd6, 0x39, 0xef, 0x78, 0x5a, 0xe2,
-	0x47, 0xb0, 0xdc, 0x09, 0x83, 0x2f, 0xb0, 0x80, 0xd8, 0x5c, 0x44, 0x45, 0xd8, 0x35, 0xd0, 0x2c,
-	0xcf, 0x13, 0x80, 0x0c, 0x02, 0x52, 0x96, 0xe7, 0x21, 0xeb, 0x16, 0xac, 0xe2, 0x19, 0x7d, 0x12,
-	0x0c, 0xfb, 0x4c, 0x2a, 0xc9, 0x22, 0x66, 0x85, 0x33, 0x0c, 0x41, 0x47, 0xec, 0x4d, 0xc8, 0x91,
-	0x53, 0xc7, 0x26, 0x6e, 0x87, 0x08, 0x5c, 0x0e, 0x71, 0xd9, 0x90, 0x88, 0xa0, 0x9f, 0x42, 0xde,
-	0xf3, 0xa9, 0x47, 0x03, 0xe2, 0x9b, 0x96, 0x6d, 0xfb, 0x24, 0x08, 0x0a, 0xcb, 0x42, 0x5f, 0x48,
-	0xdf, 0x11, 0xe4, 0x8a, 0x09, 0xea, 0xbe, 0xc5, 0x2c, 0x3d, 0x0f, 0x71, 0x36, 0x0a, 0x0a, 0x4a,
-	0x39, 0xbe, 0x99, 0x35, 0xf8, 0x52, 0xdf, 0x80, 0x4c, 0xf0, 0xc7, 0xa1, 0xe5, 0x13, 0x33, 0x70,
-	0x9e, 0x13, 0x4c, 0x9e, 0x6a, 0x80, 0x20, 0x35, 0x9d, 0xe7, 0x24, 0x6a, 0x83, 0xe4, 0xb8, 0x0d,
-	0x1e, 0xaa, 0x5a, 0x2c, 0x1f, 0x7f, 0xa8, 0x6a, 0xf1, 0xbc, 0xfa, 0x50, 0xd5, 0xd4, 0x7c, 0xa2,
-	0xf2, 0x67, 0x05, 0xd4, 0xdd, 0x3e, 0x6d, 0xeb, 0x3f, 0x84, 0xac, 0x6b, 0x0d, 0x48, 0xe0, 0x59,
-	0x1d, 0xc2, 0xab, 0x41, 0x74, 0x4f, 0x26, 0xa2, 0xd5, 0x6c, 0xae, 0x91, 0x67, 0x2c, 0xec, 0x70,
-	0xbe, 0xe6, 0x07, 0x0e, 0x7a, 0xdc, 0x8b, 0xb0, 0x09, 0xe2, 0xd8, 0xe1, 0x59, 0x24, 0x1e, 0xcb,
-	0x22, 0xff, 0x19, 0xac, 0x8e, 0x75, 0x87, 0x40, 0x15, 0x81, 0xf9, 0x88, 0x21, 0xc1, 0x95, 0x7f,
-	0xc6, 0x41, 0x3d, 0xa6, 0x8c, 0xe8, 0x77, 0x41, 0xe5, 0xf5, 0x87, 0x9e, 0x2c, 0xcf, 0x6b, 0xd4,
-	0xa6, 0xd3, 0x75, 0x89, 0x5d, 0x0f, 0xba, 0xad, 0x73, 0x8f, 0x18, 0x08, 0x9e, 0xe8, 0x93, 0xd8,
-	0x54, 0x9f, 0xac, 0x41, 0xc2, 0xa7, 0x43, 0xd7, 0x46, 0xff, 0x12, 0x86, 0xd8, 0xe8, 0x07, 0xa0,
-	0x45, 0xe5, 0xaf, 0x7e, 0x53, 0xf9, 0xaf, 0xf0, 0xf2, 0xe7, 0xcd, 0x29, 0x09, 0x46, 0xaa, 0x2d,
-	0xbb, 0x60, 0x17, 0xd2, 0xd1, 0xad, 0x2c, 0xdb, 0xe8, 0xc3, 0x3a, 0x71, 0x2c, 0xc6, 0x63, 0x14,
-	0x15, 0x75, 0x54, 0x15, 0x22, 0x77, 0xf9, 0x88, 0x21, 0xcb, 0x62, 0xaa, 0x5f, 0x4c, 0x71, 0xb3,
-	0xa6, 0xf0, 0x5c, 0xe3, 0x7e, 0xa9, 0xe1, 0x15, 0x7b, 0x03, 0xd2, 0x81, 0xd3, 0x75, 0x2d, 0x36,
-	0xf4, 0x89, 0x6c, 0xa9, 0x31, 0x81, 0x73, 0xc9, 0x88, 0x11, 0x17, 0xf3, 0x21, 0x5a, 0x68, 0x4c,
-	0xd0, 0xb7, 0xe0, 0x07, 0xd1, 0xc6, 0x1c, 0x6b, 0x11, 0xed, 0xa3, 0x47, 0xac, 0x66, 0xc8, 0xa9,
-	0xfc, 0x4b, 0x81, 0xa4, 0xe8, 0xf8, 0x89, 0x34, 0x28, 0xf3, 0xd3, 0x10, 0x5b, 0x94, 0x86, 0xf8,
-	0xc7, 0xa7, 0x61, 0x07, 0x20, 0x72, 0x33, 0x28, 0xa8, 0xe5, 0xf8, 0x66, 0x66, 0xfb, 0xfa, 0xac,
-	0x22, 0xe1, 0x62, 0xd3, 0xe9, 0xca, 0x0b, 0x6d, 0x42, 0xa8, 0xf2, 0x3f, 0x05, 0xd2, 0x11, 0x5f,
-	0xdf, 0x81, 0x5c, 0xe8, 0x97, 0x79, 0xd2, 0xb7, 0xba, 0xb2, 0x14, 0xd7, 0x17, 0x3a, 0xf7, 0x9b,
-	0xbe, 0xd5, 0x35, 0x32, 0xd2, 0x1f, 0xbe, 0x99, 0x9f, 0xd6, 0xd8, 0x82, 0xb4, 0x4e, 0xd5, 0x51,
-	0xfc, 0xe3, 0xea, 0x68, 0x2a, 0xe3, 0xea, 0xa5, 0x8c, 0x57, 0x3e, 0x57, 0x60, 0xf9, 0x60, 0x84,
-	0xee, 0xdb, 0xdf, 0x65, 0xaa, 0x9e, 0xca, 0xda, 0xb2, 0x89, 0x6d, 0xce, 0xe4, 0xec, 0xe6, 0xac,
-	0xc6, 0x69, 0x9f, 0xc7, 0xb9, 0xd3, 0x43, 0x2d, 0xcd, 0x71, 0x0e, 0xff, 0x11, 0x83, 0xd5, 0x19,
-	0xfc, 0xf7, 0x2f, 0x97, 0xd3, 0xdd, 0x9b, 0xf8, 0xc0, 0xee, 0x4d, 0x2e, 0xec, 0xde, 0xbf, 0xc7,
-	0x40, 0x6b, 0xe0, 0xe7, 0xc7, 0xea, 0x7f, 0x1b, 0x77, 0xef, 0x75, 0x48, 0x7b, 0xb4, 0x6f, 0x0a,
-	0x8e, 0x8a, 0x1c, 0xcd, 0xa3, 0x7d, 0x63, 0xa6, 0xcc, 0x12, 0x9f, 0xe8, 0x62, 0x4e, 0x7e, 0x82,
-	0x24, 0xa4, 0x2e, 0x37, 0x94, 0x0f, 0x59, 0x11, 0x0a, 0x39, 0x0e, 0xde, 0xe1, 0x31, 0xc0, 0xf9,
-	0x52, 0x99, 0x1d, 0x5f, 0x85, 0xdb, 0x02, 0x69, 0x48, 0x1c, 0x97, 0x10, 0xd3, 0x93, 0x9c, 0x48,
-	0x0b, 0x8b, 0x6e, 0x2c, 0x43, 0xe2, 0x2a, 0x7f, 0x51, 0x00, 0x1e, 0xf1, 0xc8, 0xe2, 0x79, 0xf9,
-	0x20, 0x17, 0xa0, 0x0b, 0xe6, 0x94, 0xe5, 0xd2, 0xa2, 0xa4, 0x49, 0xfb, 0xd9, 0x60, 0xd2, 0xef,
-	0x3d, 0xc8, 0x8d, 0x6b, 0x3b, 0x20, 0xa1, 0x33, 0x73, 0x94, 0x44, 0xf3, 0x55, 0x93, 0x30, 0x23,
-	0x7b, 0x3a, 0xb1, 0xab, 0xfc, 0x5b, 0x81, 0x34, 0xfa, 0x54, 0x27, 0xcc, 0x9a, 0xca, 0xa1, 0xf2,
-	0xf1, 0x39, 0x5c, 0x07, 0x10, 0x6a, 0x70, 0xce, 0x11, 0x95, 0x95, 0x46, 0x0a, 0x8e, 0x39, 0xbf,
-	0x88, 0x02, 0x1e, 0xff, 0xfa, 0x80, 0xcb, 0x1b, 0x23, 0x0c, 0xfb, 0x55, 0x48, 0xb9, 0xc3, 0x81,
-	0xc9, 0xa7, 0x2a, 0x55, 0x54, 0xab, 0x3b, 0x1c, 0xb4, 0x46, 0x41, 0xe5, 0x0f, 0x90, 0x6a, 0x8d,
-	0xf0, 0x85, 0xc1, 0x4b, 0xd4, 0xa7, 0x54, 0x8e, 0xb5, 0x62, 0x20, 0xd2, 0x38, 0x01, 0xa7, 0xb8,
-	0x79, 0xd3, 0x50, 0xf5, 0x03, 0xdf, 0x2e, 0xe1, 0xab, 0xe5, 0xf7, 0x90, 0xc5, 0xef, 0xf4, 0x13,
-	0xdf, 0xf2, 0x3c, 0xe2, 0xeb, 0xcb, 0x10, 0x63, 0x23, 0x69, 0x29, 0xc6, 0x46, 0xe3, 0xe9, 0x0a,
-	0xbf, 0xf1, 0xf8, 0x52, 0x8a, 0x47, 0xd3, 0x55, 0x4d, 0xd0, 0xf8, 0x49, 0xf8, 0x39, 0xc3, 0x1b,
-	0x39, 0x6d, 0x24, 0xf9, 0xb6, 0x66, 0x57, 0x4c, 0x48, 0xf2, 0xd1, 0xae, 0x35, 0x9a, 0xd1, 0x7b,
-	0x1b, 0x12, 0xed, 0x3e, 0x6d, 0x0b, 0x7d, 0x99, 0xed, 0x2b, 0x73, 0xf3, 0xd2, 0x36, 0x04, 0x68,
-	0xb1, 0x81, 0x2f, 0x15, 0x80, 0x26, 0x77, 0x45, 0x84, 0x2b, 0x8c, 0x88, 0x98, 0x52, 0x45, 0x44,
-	0xee, 0x81, 0x70, 0xd6, 0xc4, 0x03, 0x87, 0x06, 0x8b, 0xb3, 0x06, 0x0f, 0xeb, 0x2d, 0x11, 0x9a,
-	0x4c, 0x10, 0x69, 0x0c, 0x66, 0xa6, 0xd2, 0xf8, 0xec, 0x54, 0xfa, 0x4b, 0x9e, 0xa4, 0x33, 0xa1,
-	0x3f, 0x7a, 0x06, 0xcd, 0xa8, 0x37, 0xe8, 0x99, 0x50, 0xaf, 0xf9, 0x72, 0x35, 0x7f, 0x2a, 0x4d,
-	0x2c, 0x98, 0x4a, 0x5f, 0x2b, 0xa0, 0x85, 0x3a, 0x44, 0x5d, 0x9c, 0x99, 0xbc, 0x14, 0xc2, 0x99,
-	0x9c, 0xab, 0x35, 0xf8, 0x9e, 0xf7, 0xf3, 0xd4, 0x59, 0x17, 0x17, 0x81, 0xc4, 0xf1, 0xb8, 0x71,
-	0x55, 0xf2, 0x70, 0xb8, 0xe6, 0x26, 0x02, 0xc6, 0x5f, 0xac, 0x3e, 0x3d, 0x93, 0xa3, 0xb2, 0x86,
-	0x04, 0x83, 0x9e, 0xf1, 0x84, 0x10, 0xd7, 0x46, 0x96, 0xf0, 0x37, 0x49, 0x5c, 0xdb, 0xa0, 0x67,
-	0x15, 0x02, 0x5a, 0x18, 0x47, 0x7e, 0xeb, 0xa2, 0x00, 0xa6, 0x3d, 0x61, 0x88, 0x0d, 0x7f, 0x48,
-	0x90, 0xe8, 0x9b, 0xce, 0x97, 0x1c, 0xe7, 0x52, 0x9b, 0x04, 0x85, 0x38, 0x1e, 0x44, 0x6c, 0xb8,
-	0xfd, 0x3e, 0xb1, 0x4e, 0x44, 0xe9, 0x8b, 0x4f, 0x8f, 0xc6, 0x09, 0xbc, 0xf4, 0x6f, 0xfd, 0x47,
-	0x81, 0xdc, 0xd4, 0x07, 0x40, 0xbf, 0x0d, 0x57, 0x9b, 0xb5, 0xfb, 0x87, 0x07, 0xfb, 0x66, 0xbd,
-	0x79, 0xdf, 0x6c, 0xfd, 0xae, 0x71, 0x60, 0x3e, 0x3e, 0xfc, 0xed, 0xe1, 0xd1, 0x93, 0xc3, 0xfc,
-	0x52, 0x71, 0xe5, 0xc5, 0xab, 0x72, 0xe6, 0xb1, 0xfb, 0xcc, 0xa5, 0x67, 0xee, 0x22, 0x74, 0xc3,
-	0x38, 0x38, 0x3e, 0x6a, 0x1d, 0xe4, 0x15, 0x81, 0x6e, 0xf8, 0xe4, 0x94, 0x32, 0x82, 0xe8, 0x3b,
-	0x70, 0x6d, 0x0e, 0x7a, 0xef, 0xa8, 0x5e, 0xaf, 0xb5, 0xf2, 0xb1, 0xe2, 0xea, 0x8b, 0x57, 0xe5,
-	0x5c, 0xc3, 0x27, 0xe2, 0x72, 0x44, 0x89, 0x2a, 0x14, 0x66, 0x25, 0x8e, 0x1a, 0x47, 0xcd, 0x9d,
-	0x47, 0xf9, 0x72, 0x31, 0xff, 0xe2, 0x55, 0x39, 0x1b, 0x7e, 0xe9, 0x38, 0xbe, 0xa8, 0xfd, 0xe9,
-	0x75, 0x69, 0xe9, 0x6f, 0x7f, 0x2d, 0x29, 0xbb, 0xf5, 0x37, 0x17, 0x25, 0xe5, 0xed, 0x45, 0x49,
-	0xf9, 0xec, 0xa2, 0xa4, 0xbc, 0x7c, 0x5f, 0x5a, 0x7a, 0xfb, 0xbe, 0xb4, 0xf4, 0xdf, 0xf7, 0xa5,
-	0xa5, 0xa7, 0x77, 0xbb, 0x0e, 0xeb, 0x0d, 0xdb, 0xd5, 0x0e, 0x1d, 0x6c, 0x75, 0xe8, 0x80, 0xb0,
-	0xf6, 0x09, 0x1b, 0x2f, 0xc4, 0xdf, 0x36, 0x97, 0xff, 0x4a, 0x69, 0x27, 0x91, 0x7e, 0xf7, 0xab,
-	0x00, 0x00, 0x00, 0xff, 0xff, 0xf2, 0xee, 0xae, 0xad, 0x0b, 0x12, 0x00, 0x00,
+	// 1736 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xdc, 0x58, 0xcd, 0x8f, 0x23, 0x47,
+	0x15, 0x9f, 0x76, 0xb7, 0xed, 0xf6, 0xb3, 0x3d, 0xe3, 0x6d, 0x56, 0x89, 0xd7, 0x9b, 0xf5, 0x18,
+	0x47, 0xc0, 0x10, 0x22, 0xcf, 0x6a, 0x17, 0x41, 0x38, 0xe4, 0x30, 0x5f, 0x24, 0x0e, 0x99, 0x19,
+	0xab, 0xed, 0x6c, 0x44, 0x84, 0xd4, 0x6a, 0xbb, 0x6b, 0xec, 0x26, 0x76, 0x57, 0xd3, 0x55, 0x9e,
+	0xf1, 0xe4, 0x1f, 0x00, 0xed, 0x85, 0x9c, 0xb8, 0xed, 0x29, 0x20, 0x71, 0x07, 0x89, 0x2b, 0xc7,
+	0x1c, 0x73, 0x83, 0x0b, 0x01, 0x76, 0x25, 0xc4, 0x9f, 0x81, 0xde, 0xab, 0xee, 0xb6, 0x3d, 0xb6,
+	0xc9, 0xb2, 0x8a, 0x88, 0x94, 0x8b, 0x55, 0xf5, 0xde, 0xef, 0x7d, 0xd4, 0xfb, 0xa8, 0x7e, 0x65,
+	0x78, 0x45, 0xb2, 0xc0, 0x63, 0xd1, 0xc4, 0x0f, 0xe4, 0xbe, 0xbc, 0x0e, 0x99, 0x50, 0xbf, 0xad,
+	0x30, 0xe2, 0x92, 0x5b, 0x95, 0x39, 0xb7, 0x45, 0xf4, 0xda, 0xed, 0x21, 0x1f, 0x72, 0x62, 0xee,
+	0xe3, 0x4a, 0xe1, 0x6a, 0xbb, 0x43, 0xce, 0x87, 0x63, 0xb6, 0x4f, 0xbb, 0xfe, 0xf4, 0x62, 0x5f,
+	0xfa, 0x13, 0x26, 0xa4, 0x3b, 0x09, 0x63, 0xc0, 0xbd, 0x05, 0x33, 0x83, 0xe8, 0x3a, 0x94, 0x1c,
+	0xb1, 0xfc, 0x22, 0x66, 0x37, 0x16, 0xd8, 0x63, 0xbf, 0x2f, 0xf6, 0xfb, 0xbe, 0x5c, 0xf2, 0xa4,
+	0x56, 0x5f, 0x40, 0x5c, 0xb2, 0x48, 0xf8, 0x3c, 0x58, 0xe2, 0x37, 0x56, 0xce, 0x71, 0xe9, 0x8e,
+	0x7d, 0xcf, 0x95, 0x3c, 0x52, 0x88, 0xe6, 0x8f, 0xa0, 0xdc, 0x71, 0x23, 0xd9, 0x65, 0xf2, 0x6d,
+	0xe6, 0x7a, 0x2c, 0xb2, 0x6e, 0x43, 0x56, 0x72, 0xe9, 0x8e, 0xab, 0x5a, 0x43, 0xdb, 0x2b, 0xdb,
+	0x6a, 0x63, 0x59, 0x60, 0x8c, 0x5c, 0x31, 0xaa, 0x66, 0x1a, 0xda, 0x5e, 0xc9, 0xa6, 0x75, 0x73,
+	0x04, 0x06, 0x8a, 0xa2, 0x84, 0x1f, 0x78, 0x6c, 0x96, 0x48, 0xd0, 0x06, 0xa9, 0xfd, 0x6b, 0xc9,
+	0x44, 0x2c, 0xa2, 0x36, 0xd6, 0xf7, 0x21, 0x4b, 0x27, 0xac, 0xea, 0x0d, 0x6d, 0xaf, 0xf8, 0xa0,
+	0xda, 0x5a, 0x08, 0xa5, 0x8a, 0x40, 0xab, 0x83, 0xfc, 0x43, 0xe3, 0xd3, 0xcf, 0x77, 0xb7, 0x6c,
+	0x05, 0x6e, 0x8e, 0x21, 0x7f, 0x38, 0xe6, 0x83, 0x0f, 0xdb, 0xc7, 0xa9, 0x23, 0xda, 0xdc, 0x11,
+	0xeb, 0x14, 0x76, 0x42, 0x37, 0x92, 0x8e, 0x60, 0xd2, 0x19, 0xd1, 0x29, 0xc8, 0x68, 0xf1, 0xc1,
+	0x6e, 0xeb, 0x66, 0xa6, 0x5a, 0x4b, 0x87, 0x8d, 0xad, 0x94, 0xc3, 0x45, 0x62, 0xf3, 0x5f, 0x06,
+	0xe4, 0xd4, 0xd2, 0x7a, 0x13, 0xf2, 0x71, 0x58, 0xc9, 0x60, 0xf1, 0xc1, 0xbd, 0x45, 0x8d, 0x31,
+	0xab, 0x75, 0xc4, 0x03, 0xc1, 0x02, 0x31, 0x15, 0xb1, 0xbe, 0x44, 0xc6, 0xfa, 0x36, 0x98, 0x83,
+	0x91, 0xeb, 0x07, 0x8e, 0xef, 0x91, 0x47, 0x85, 0xc3, 0xe2, 0xd3, 0xcf, 0x77, 0xf3, 0x47, 0x48,
+	0x6b, 0x1f, 0xdb, 0x79, 0x62, 0xb6, 0x3d, 0xeb, 0x25, 0xc8, 0x8d, 0x98, 0x3f, 0x1c, 0x49, 0x0a,
+	0x8b, 0x6e, 0xc7, 0x3b, 0xeb, 0x0d, 0x30, 0xb0, 0x64, 0xaa, 0x06, 0xd9, 0xae, 0xb5, 0x54, 0x3d,
+	0xb5, 0x92, 0x7a, 0x6a, 0xf5, 0x92, 0x7a, 0x3a, 0x34, 0xd1, 0xf0, 0xc7, 0x7f, 0xdf, 0xd5, 0x6c,
+	0x92, 0xb0, 0x8e, 0xa0, 0x3c, 0x76, 0x85, 0x74, 0xfa, 0x18, 0x36, 0x34, 0x9f, 0x25, 0x15, 0x77,
+	0x56, 0x03, 0x12, 0x07, 0x36, 0x76, 0xbd, 0x88, 0x52, 0x8a, 0xe4, 0x59, 0x7b, 0x50, 0x21, 0x25,
+	0x03, 0x3e, 0x99, 0xf8, 0xd2, 0xa1, 0xb8, 0xe7, 0x28, 0xee, 0xdb, 0x48, 0x3f, 0x22, 0xf2, 0xdb,
+	0x98, 0x81, 0xbb, 0x50, 0xf0, 0x5c, 0xe9, 0x2a, 0x48, 0x9e, 0x20, 0x26, 0x12, 0x88, 0xf9, 0x1d,
+	0xd8, 0x49, 0xab, 0x4e, 0x28, 0x88, 0xa9, 0xb4, 0xcc, 0xc9, 0x04, 0xbc, 0x0f, 0xb7, 0x03, 0x36,
+	0x93, 0xce, 0x4d, 0x74, 0x81, 0xd0, 0x16, 0xf2, 0x1e, 0x2d, 0x4b, 0x7c, 0x0b, 0xb6, 0x07, 0x49,
+	0xf0, 0x15, 0x16, 0x08, 0x5b, 0x4e, 0xa9, 0x04, 0xbb, 0x03, 0xa6, 0x1b, 0x86, 0x0a, 0x50, 0x24,
+	0x40, 0xde, 0x0d, 0x43, 0x62, 0xbd, 0x06, 0xb7, 0xe8, 0x8c, 0x11, 0x13, 0xd3, 0xb1, 0x8c, 0x95,
+	0x94, 0x08, 0xb3, 0x83, 0x0c, 0x5b, 0xd1, 0x09, 0xfb, 0x2a, 0x94, 0xd9, 0xa5, 0xef, 0xb1, 0x60,
+	0xc0, 0x14, 0xae, 0x4c, 0xb8, 0x52, 0x42, 0x24, 0xd0, 0x77, 0xa1, 0x12, 0x46, 0x3c, 0xe4, 0x82,
+	0x45, 0x8e, 0xeb, 0x79, 0x11, 0x13, 0xa2, 0xba, 0xad, 0xf4, 0x25, 0xf4, 0x03, 0x45, 0x6e, 0xfe,
+	0x52, 0x03, 0xe3, 0xd8, 0x95, 0xae, 0x55, 0x01, 0x5d, 0xce, 0x44, 0x55, 0x6b, 0xe8, 0x7b, 0x25,
+	0x1b, 0x97, 0xd6, 0xeb, 0x90, 0xed, 0x8f, 0x79, 0x5f, 0x54, 0x8d, 0x86, 0xbe, 0x57, 0x7c, 0xf0,
+	0xd2, 0xda, 0xbc, 0xf5, 0x6d, 0x05, 0xb2, 0x76, 0xa1, 0x28, 0x7e, 0x31, 0x75, 0x23, 0xe6, 0x08,
+	0xff, 0x23, 0x46, 0xb9, 0x36, 0x6c, 0x50, 0xa4, 0xae, 0xff, 0x11, 0x4b, 0xbb, 0x26, 0x37, 0xef,
+	0x9a, 0x77, 0x0c, 0x33, 0x53, 0xd1, 0xdf, 0x31, 0x4c, 0xbd, 0x62, 0x34, 0x7f, 0xad, 0x81, 0x81,
+	0x0a, 0xad, 0x6f, 0x42, 0x29, 0x70, 0x27, 0x4c, 0x84, 0xee, 0x80, 0x61, 0xd9, 0xa8, 0x36, 0x2b,
+	0xa6, 0xb4, 0xb6, 0x87, 0xba, 0x30, 0xb5, 0xc9, 0x55, 0x80, 0x6b, 0x8c, 0x8c, 0x18, 0xa1, 0xfd,
+	0xa4, 0x5b, 0x74, 0xba, 0x0a, 0x4a, 0x44, 0x7c, 0xa4, 0x68, 0xd6, 0xf7, 0xe0, 0xd6, 0x5c, 0x77,
+	0x02, 0x34, 0x08, 0x58, 0x49, 0x19, 0x31, 0xb8, 0xf9, 0x27, 0x1d, 0x8c, 0x47, 0x5c, 0x32, 0xeb,
+	0x21, 0x18, 0x78, 0x60, 0xf2, 0x64, 0x7b, 0x5d, 0x47, 0x77, 0xfd, 0x61, 0xc0, 0xbc, 0x53, 0x31,
+	0xec, 0x5d, 0x87, 0xcc, 0x26, 0xf0, 0x42, 0x43, 0x65, 0x96, 0x1a, 0xea, 0x36, 0x64, 0x23, 0x3e,
+	0x0d, 0x3c, 0xf2, 0x2f, 0x6b, 0xab, 0x8d, 0x75, 0x02, 0x66, 0xda, 0x27, 0xc6, 0x17, 0xf5, 0xc9,
+	0x0e, 0xf6, 0x09, 0x76, 0x71, 0x4c, 0xb0, 0xf3, 0xfd, 0xb8, 0x5d, 0x0e, 0xa1, 0x90, 0x5e, 0xf0,
+	0xd5, 0xec, 0xff, 0xd0, 0xb2, 0x73, 0x31, 0x8c, 0x51, 0x5a, 0xfd, 0x69, 0xf9, 0xa8, 0xac, 0x55,
+	0x52, 0x46, 0x5c, 0x3f, 0x4b, 0x8d, 0xe5, 0xa8, 0x2b, 0x38, 0x4f, 0xe7, 0x9a, 0x37, 0x56, 0x1b,
+	0xa9, 0xd6, 0x2b, 0x50, 0x10, 0xfe, 0x30, 0x70, 0xe5, 0x34, 0x62, 0x71, 0xef, 0xcd, 0x09, 0xc8,
+	0x65, 0x33, 0xc9, 0x02, 0xca, 0x87, 0xea, 0xb5, 0x39, 0xc1, 0xda, 0x87, 0x6f, 0xa4, 0x1b, 0x67,
+	0xae, 0x45, 0xf5, 0x99, 0x95, 0xb2, 0xba, 0x09, 0xa7, 0xf9, 0x67, 0x0d, 0x72, 0xea, 0x6a, 0x58,
+	0x48, 0x83, 0xb6, 0x3e, 0x0d, 0x99, 0x4d, 0x69, 0xd0, 0x5f, 0x3c, 0x0d, 0x07, 0x00, 0xa9, 0x9b,
+	0x49, 0xff, 0xdc, 0x5d, 0x55, 0xa4, 0x5c, 0xec, 0xfa, 0xc3, 0xf8, 0xe6, 0x5b, 0x10, 0x6a, 0xfe,
+	0x4d, 0x83, 0x42, 0xca, 0xb7, 0x0e, 0xa0, 0x9c, 0xf8, 0xe5, 0x5c, 0x8c, 0xdd, 0x61, 0x5c, 0x8a,
+	0xf7, 0x36, 0x3a, 0xf7, 0xe3, 0xb1, 0x3b, 0xb4, 0x8b, 0xb1, 0x3f, 0xb8, 0x59, 0x9f, 0xd6, 0xcc,
+	0x86, 0xb4, 0x2e, 0xd5, 0x91, 0xfe, 0x62, 0x75, 0xb4, 0x94, 0x71, 0xe3, 0x46, 0xc6, 0x9b, 0xbf,
+	0xcb, 0x40, 0xe5, 0x60, 0x38, 0x8c, 0xd8, 0xd0, 0x95, 0xcc, 0xfb, 0x2a, 0x93, 0xf5, 0x06, 0xe4,
+	0xd1, 0x2d, 0x16, 0x89, 0xb8, 0xf3, 0xea, 0x8b, 0x5a, 0x70, 0xe8, 0x69, 0xe1, 0xd0, 0xd3, 0x3a,
+	0xf4, 0xe5, 0x41, 0x14, 0xb9, 0xd7, 0x76, 0x02, 0xb7, 0x8e, 0x01, 0xd2, 0xe3, 0x8a, 0x6a, 0xb6,
+	0xa1, 0x3f, 0x77, 0x98, 0x16, 0xe4, 0x96, 0xe3, 0x94, 0xbb, 0x19, 0xa7, 0x7f, 0x6a, 0xb0, 0x7d,
+	0x32, 0x23, 0x87, 0xbe, 0xd2, 0x28, 0x7d, 0x10, 0xf7, 0xa0, 0xc7, 0x3c, 0x67, 0xa5, 0xb6, 0x5f,
+	0x5d, 0xd5, 0xb8, 0xec, 0xf3, 0xbc, 0xc6, 0xad, 0x44, 0x4b, 0x77, 0x5e, 0xeb, 0x7f, 0xcc, 0xc0,
+	0xad, 0x15, 0xfc, 0xd7, 0xaf, 0xe6, 0x97, 0x6f, 0xb9, 0xec, 0x73, 0xde, 0x72, 0xb9, 0x8d, 0xb7,
+	0xdc, 0x1f, 0x32, 0x60, 0x76, 0xe8, 0x7b, 0xee, 0x8e, 0xff, 0x1f, 0xdf, 0xa8, 0xbb, 0x50, 0x08,
+	0xf9, 0xd8, 0x51, 0x1c, 0x83, 0x38, 0x66, 0xc8, 0xc7, 0xf6, 0x4a, 0x99, 0x65, 0xbf, 0xa4, 0x0f,
+	0x58, 0xee, 0x4b, 0x48, 0x42, 0xfe, 0x66, 0x43, 0x45, 0x50, 0x52, 0xa1, 0x88, 0xe7, 0xeb, 0xfb,
+	0x18, 0x03, 0x5c, 0x55, 0xb5, 0xd5, 0xf7, 0x80, 0x72, 0x5b, 0x21, 0xed, 0xdc, 0x28, 0x95, 0x50,
+	0xe3, 0x68, 0x35, 0xb3, 0x49, 0x42, 0x55, 0xb1, 0x1d, 0xe3, 0x9a, 0xbf, 0xd1, 0x00, 0xde, 0xc5,
+	0xc8, 0xd2, 0x79, 0x71, 0x32, 0xa6, 0x2b, 0xc4, 0x73, 0x96, 0x2c, 0xd7, 0x37, 0x25, 0x2d, 0xb6,
+	0x5f, 0x12, 0x8b, 0x7e, 0x1f, 0x41, 0x79, 0x5e, 0xdb, 0x82, 0x25, 0xce, 0xac, 0x51, 0x92, 0x0e,
+	0xac, 0x5d, 0x26, 0xed, 0xd2, 0xe5, 0xc2, 0xae, 0xf9, 0x4c, 0x83, 0x02, 0xf9, 0x74, 0xca, 0xa4,
+	0xbb, 0x94, 0x43, 0xed, 0xc5, 0x73, 0x78, 0x0f, 0x40, 0xa9, 0xa1, 0x49, 0x50, 0x55, 0x56, 0x81,
+	0x28, 0x34, 0x08, 0xfe, 0x20, 0x0d, 0xb8, 0xfe, 0xdf, 0x03, 0x1e, 0xdf, 0x18, 0x49, 0xd8, 0x5f,
+	0x86, 0x7c, 0x30, 0x9d, 0x38, 0x38, 0xa5, 0x1a, 0xaa, 0x5a, 0x83, 0xe9, 0xa4, 0x37, 0xfb, 0xe2,
+	0xd1, 0xb3, 0xf9, 0x73, 0xc8, 0xf7, 0x66, 0xf4, 0xa6, 0xc3, 0x1a, 0x8e, 0x38, 0x8f, 0x1f, 0x12,
+	0x6a, 0xb2, 0x34, 0x91, 0x40, 0x73, 0xf3, 0xba, 0xb1, 0xb2, 0xf5, 0x9c, 0xaf, 0xc5, 0xe4, 0x9d,
+	0xf8, 0x33, 0x28, 0xd1, 0xc0, 0xf3, 0x7e, 0xe4, 0x86, 0x21, 0x8b, 0xac, 0x6d, 0xc8, 0xc8, 0x59,
+	0x6c, 0x29, 0x23, 0x67, 0xf3, 0x31, 0x95, 0x86, 0x25, 0x7a, 0x9b, 0xea, 0xe9, 0x98, 0xda, 0x56,
+	0x34, 0x3c, 0x2a, 0x06, 0x22, 0xb9, 0xb2, 0x0b, 0x76, 0x0e, 0xb7, 0x6d, 0xaf, 0xe9, 0x40, 0x0e,
+	0x67, 0xe4, 0xde, 0x6c, 0x45, 0x6f, 0x3a, 0xad, 0x67, 0x9e, 0x67, 0x5a, 0xdf, 0x68, 0xe0, 0xdf,
+	0x1a, 0x40, 0x17, 0x5d, 0x51, 0xe1, 0x4a, 0x22, 0xa2, 0x9e, 0x05, 0xb4, 0xb6, 0xde, 0x04, 0xe5,
+	0xac, 0x43, 0x07, 0x4e, 0x0c, 0xd6, 0x56, 0x0d, 0x9e, 0x9d, 0xf6, 0x54, 0x68, 0x8a, 0x22, 0xd5,
+	0x28, 0x56, 0xc6, 0x7b, 0x7d, 0x75, 0xbc, 0xff, 0x21, 0x26, 0xe9, 0x4a, 0xe9, 0x4f, 0x1f, 0x9e,
+	0x2b, 0xea, 0x6d, 0x7e, 0xa5, 0xd4, 0x9b, 0x51, 0xbc, 0x5a, 0x3f, 0xde, 0x67, 0x37, 0x8c, 0xf7,
+	0x9f, 0x68, 0x60, 0x26, 0x3a, 0x54, 0x5d, 0x5c, 0x39, 0x58, 0x0a, 0xc9, 0x23, 0x08, 0xd5, 0xda,
+	0xb8, 0xc7, 0x86, 0x5f, 0x3a, 0xeb, 0xe6, 0x22, 0x88, 0x71, 0x18, 0x37, 0x54, 0x15, 0x1f, 0x8e,
+	0xd6, 0x68, 0x42, 0x48, 0xfc, 0x8f, 0x20, 0xe2, 0x57, 0xf1, 0x9b, 0xc3, 0x24, 0x82, 0xcd, 0xaf,
+	0x30, 0x21, 0x2c, 0xf0, 0x88, 0xa5, 0xfc, 0xcd, 0xb1, 0xc0, 0xb3, 0xf9, 0x55, 0x93, 0x81, 0x99,
+	0xc4, 0x11, 0xaf, 0x65, 0x12, 0xa0, 0xb4, 0x67, 0x6d, 0xb5, 0xc1, 0x97, 0x1b, 0x4b, 0x3f, 0xfa,
+	0xb8, 0x44, 0x5c, 0xc0, 0x3d, 0x26, 0xaa, 0x3a, 0x1d, 0x44, 0x6d, 0xd0, 0xfe, 0x98, 0xb9, 0x17,
+	0xaa, 0xf4, 0xd5, 0xb7, 0xc9, 0x44, 0x02, 0x96, 0xfe, 0x6b, 0x7f, 0xd1, 0xa0, 0xbc, 0xf4, 0x85,
+	0xb0, 0x5e, 0x87, 0x97, 0xbb, 0xed, 0xb7, 0xce, 0x4e, 0x8e, 0x9d, 0xd3, 0xee, 0x5b, 0x4e, 0xef,
+	0xa7, 0x9d, 0x13, 0xe7, 0xbd, 0xb3, 0x9f, 0x9c, 0x9d, 0xbf, 0x7f, 0x56, 0xd9, 0xaa, 0xed, 0x3c,
+	0x7e, 0xd2, 0x28, 0xbe, 0x17, 0x7c, 0x18, 0xf0, 0xab, 0x60, 0x13, 0xba, 0x63, 0x9f, 0x3c, 0x3a,
+	0xef, 0x9d, 0x54, 0x34, 0x85, 0xee, 0x44, 0xec, 0x92, 0x4b, 0x46, 0xe8, 0xfb, 0x70, 0x67, 0x0d,
+	0xfa, 0xe8, 0xfc, 0xf4, 0xb4, 0xdd, 0xab, 0x64, 0x6a, 0xb7, 0x1e, 0x3f, 0x69, 0x94, 0x3b, 0x11,
+	0x53, 0xb7, 0x27, 0x49, 0xb4, 0xa0, 0xba, 0x2a, 0x71, 0xde, 0x39, 0xef, 0x1e, 0xbc, 0x5b, 0x69,
+	0xd4, 0x2a, 0x8f, 0x9f, 0x34, 0x4a, 0xc9, 0xa7, 0x10, 0xf1, 0x35, 0xf3, 0x57, 0x9f, 0xd4, 0xb7,
+	0x7e, 0xff, 0xdb, 0xba, 0x76, 0x78, 0xfa, 0xe9, 0xd3, 0xba, 0xf6, 0xd9, 0xd3, 0xba, 0xf6, 0x8f,
+	0xa7, 0x75, 0xed, 0xe3, 0x67, 0xf5, 0xad, 0xcf, 0x9e, 0xd5, 0xb7, 0xfe, 0xfa, 0xac, 0xbe, 0xf5,
+	0xc1, 0xc3, 0xa1, 0x2f, 0x47, 0xd3, 0x7e, 0x6b, 0xc0, 0x27, 0xfb, 0x03, 0x3e, 0x61, 0xb2, 0x7f,
+	0x21, 0xe7, 0x0b, 0xf5, 0x57, 0xda, 0xcd, 0x3f, 0xaf, 0xfa, 0x39, 0xa2, 0x3f, 0xfc, 0xcf, 0x00,
+	0xb4, 0x42, 0x77, 0xd2, 0x9f, 0x13, 0x00, 0x00,
 }
 
 func (m *PartSetHeader) Marshal() (dAtA []byte, err error) {
@@ -2045,6 +2158,20 @@ func (m *Data) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i--
 		dAtA[i] = 0x28
 	}
+	if len(m.Blobs) > 0 {
+		for iNdEx := len(m.Blobs) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Blobs[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTypes(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x22
+		}
+	}
 	if len(m.Txs) > 0 {
 		for iNdEx := len(m.Txs) - 1; iNdEx >= 0; iNdEx-- {
 			i -= len(m.Txs[iNdEx])
@@ -2300,6 +2427,80 @@ func (m *CommitSig) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *AggregatedCommit) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *AggregatedCommit) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *AggregatedCommit) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Signature) > 0 {
+		i -= len(m.Signature)
+		copy(dAtA[i:], m.Signature)
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.Signature)))
+		i--
+		dAtA[i] = 0x32
+	}
+	if len(m.Timestamps) > 0 {
+		for iNdEx := len(m.Timestamps) - 1; iNdEx >= 0; iNdEx-- {
+			n, err := github_com_cosmos_gogoproto_types.StdTimeMarshalTo(m.Timestamps[iNdEx], dAtA[i-github_com_cosmos_gogoproto_types.SizeOfStdTime(m.Timestamps[iNdEx]):])
+			if err != nil {
+				return 0, err
+			}
+			i -= n
+			i = encodeVarintTypes(dAtA, i, uint64(n))
+			i--
+			dAtA[i] = 0x2a
+		}
+	}
+	if m.Signers != nil {
+		{
+			size, err := m.Signers.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTypes(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x22
+	}
+	{
+		size, err := m.BlockID.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintTypes(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x1a
+	if m.Round != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.Round))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Height != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.Height))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
 func (m *ExtendedCommit) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -2398,12 +2599,12 @@ func (m *ExtendedCommitSig) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i--
 		dAtA[i] = 0x22
 	}
-	n11, err11 := github_com_cosmos_gogoproto_types.StdTimeMarshalTo(m.Timestamp, dAtA[i-github_com_cosmos_gogoproto_types.SizeOfStdTime(m.Timestamp):])
-	if err11 != nil {
-		return 0, err11
+	n13, err13 := github_com_cosmos_gogoproto_types.StdTimeMarshalTo(m.Timestamp, dAtA[i-github_com_cosmos_gogoproto_types.SizeOfStdTime(m.Timestamp):])
+	if err13 != nil {
+		return 0, err13
 	}
-	i -= n11
-	i = encodeVarintTypes(dAtA, i, uint64(n11))
+	i -= n13
+	i = encodeVarintTypes(dAtA, i, uint64(n13))
 	i--
 	dAtA[i] = 0x1a
 	if len(m.ValidatorAddress) > 0 {
@@ -2448,12 +2649,12 @@ func (m *Proposal) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i--
 		dAtA[i] = 0x3a
 	}
-	n12, err12 := github_com_cosmos_gogoproto_types.StdTimeMarshalTo(m.Timestamp, dAtA[i-github_com_cosmos_gogoproto_types.SizeOfStdTime(m.Timestamp):])
-	if err12 != nil {
-		return 0, err12
+	n14, err14 := github_com_cosmos_gogoproto_types.StdTimeMarshalTo(m.Timestamp, dAtA[i-github_com_cosmos_gogoproto_types.SizeOfStdTime(m.Timestamp):])
+	if err14 != nil {
+		return 0, err14
 	}
-	i -= n12
-	i = encodeVarintTypes(dAtA, i, uint64(n12))
+	i -= n14
+	i = encodeVarintTypes(dAtA, i, uint64(n14))
 	i--
 	dAtA[i] = 0x32
 	{
@@ -2603,6 +2804,11 @@ func (m *BlockMeta) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.SquareSize != 0 {
+		i = encodeVarintTypes(dAtA, i, uint64(m.SquareSize))
+		i--
+		dAtA[i] = 0x28
+	}
 	if m.NumTxs != 0 {
 		i = encodeVarintTypes(dAtA, i, uint64(m.NumTxs))
 		i--
@@ -2713,20 +2919,20 @@ func (m *IndexWrapper) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		dAtA[i] = 0x1a
 	}
 	if len(m.ShareIndexes) > 0 {
-		dAtA22 := make([]byte, len(m.ShareIndexes)*10)
-		var j21 int
+		dAtA24 := make([]byte, len(m.ShareIndexes)*10)
+		var j23 int
 		for _, num := range m.ShareIndexes {
 			for num >= 1<<7 {
-				dAtA22[j21] = uint8(uint64(num)&0x7f | 0x80)
+				dAtA24[j23] = uint8(uint64(num)&0x7f | 0x80)
 				num >>= 7
-				j21++
+				j23++
 			}
-			dAtA22[j21] = uint8(num)
-			j21++
+			dAtA24[j23] = uint8(num)
+			j23++
 		}
-		i -= j21
-		copy(dAtA[i:], dAtA22[:j21])
-		i = encodeVarintTypes(dAtA, i, uint64(j21))
+		i -= j23
+		copy(dAtA[i:], dAtA24[:j23])
+		i = encodeVarintTypes(dAtA, i, uint64(j23))
 		i--
 		dAtA[i] = 0x12
 	}
@@ -3103,6 +3309,12 @@ func (m *Data) Size() (n int) {
 			n += 1 + l + sovTypes(uint64(l))
 		}
 	}
+	if len(m.Blobs) > 0 {
+		for _, e := range m.Blobs {
+			l = e.Size()
+			n += 1 + l + sovTypes(uint64(l))
+		}
+	}
 	if m.SquareSize != 0 {
 		n += 1 + sovTypes(uint64(m.SquareSize))
 	}
@@ -3222,6 +3434,37 @@ func (m *CommitSig) Size() (n int) {
 	return n
 }
 
+func (m *AggregatedCommit) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Height != 0 {
+		n += 1 + sovTypes(uint64(m.Height))
+	}
+	if m.Round != 0 {
+		n += 1 + sovTypes(uint64(m.Round))
+	}
+	l = m.BlockID.Size()
+	n += 1 + l + sovTypes(uint64(l))
+	if m.Signers != nil {
+		l = m.Signers.Size()
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	if len(m.Timestamps) > 0 {
+		for _, e := range m.Timestamps {
+			l = github_com_cosmos_gogoproto_types.SizeOfStdTime(e)
+			n += 1 + l + sovTypes(uint64(l))
+		}
+	}
+	l = len(m.Signature)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	return n
+}
+
 func (m *ExtendedCommit) Size() (n int) {
 	if m == nil {
 		return 0
@@ -3354,6 +3597,9 @@ func (m *BlockMeta) Size() (n int) {
 	if m.NumTxs != 0 {
 		n += 1 + sovTypes(uint64(m.NumTxs))
 	}
+	if m.SquareSize != 0 {
+		n += 1 + sovTypes(uint64(m.SquareSize))
+	}
 	return n
 }
 
@@ -4442,6 +4688,40 @@ func (m *Data) Unmarshal(dAtA []byte) error {
 			m.Txs = append(m.Txs, make([]byte, postIndex-iNdEx))
 			copy(m.Txs[len(m.Txs)-1], dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Blobs", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Blobs = append(m.Blobs, &Blob{})
+			if err := m.Blobs[len(m.Blobs)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		case 5:
 			if wireType != 0 {
 				return fmt.Errorf("proto: wrong wireType = %d for field SquareSize", wireType)
@@ -5325,6 +5605,231 @@ func (m *CommitSig) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *AggregatedCommit) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTypes
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: AggregatedCommit: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: AggregatedCommit: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Height", wireType)
+			}
+			m.Height = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Height |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Round", wireType)
+			}
+			m.Round = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Round |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BlockID", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.BlockID.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Signers", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Signers == nil {
+				m.Signers = &bits.BitArray{}
+			}
+			if err := m.Signers.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timestamps", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Timestamps = append(m.Timestamps, time.Time{})
+			if err := github_com_cosmos_gogoproto_types.StdTimeUnmarshal(&(m.Timestamps[len(m.Timestamps)-1]), dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Signature", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Signature = append(m.Signature[:0], dAtA[iNdEx:postIndex]...)
+			if m.Signature == nil {
+				m.Signature = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTypes(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func (m *ExtendedCommit) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
@@ -6321,6 +6826,25 @@ func (m *BlockMeta) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SquareSize", wireType)
+			}
+			m.SquareSize = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SquareSize |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTypes(dAtA[iNdEx:])
@@ -312,6 +312,22 @@ func (c *baseRPCClient) NumUnconfirmedTxs(ctx context.Context) (*ctypes.ResultUn
 	return result, nil
 }
 
+func (c *baseRPCClient) MempoolContents(
+	ctx context.Context,
+	limit *int,
+) (*ctypes.ResultMempoolContents, error) {
+	result := new(ctypes.ResultMempoolContents)
+	params := make(map[string]interface{})
+	if limit != nil {
+		params["limit"] = limit
+	}
+	_, err := c.caller.Call(ctx, "mempool_contents", params, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 func (c *baseRPCClient) CheckTx(ctx context.Context, tx types.Tx) (*ctypes.ResultCheckTx, error) {
 	result := new(ctypes.ResultCheckTx)
 	_, err := c.caller.Call(ctx, "check_tx", map[string]interface{}{"tx": tx}, result)
@@ -373,6 +389,15 @@ func (c *baseRPCClient) Health(ctx context.Context) (*ctypes.ResultHealth, error
 	return result, nil
 }
 
+func (c *baseRPCClient) StateSyncStatus(ctx context.Context) (*ctypes.ResultStateSyncStatus, error) {
+	result := new(ctypes.ResultStateSyncStatus)
+	_, err := c.caller.Call(ctx, "statesync_status", map[string]interface{}{}, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 func (c *baseRPCClient) BlockchainInfo(
 	ctx context.Context,
 	minHeight,
@@ -131,6 +131,9 @@ type NetworkClient interface {
 	ConsensusState(context.Context) (*ctypes.ResultConsensusState, error)
 	ConsensusParams(ctx context.Context, height *int64) (*ctypes.ResultConsensusParams, error)
 	Health(context.Context) (*ctypes.ResultHealth, error)
+	// StateSyncStatus returns the progress of the state sync in progress, or
+	// of the last one.
+	StateSyncStatus(context.Context) (*ctypes.ResultStateSyncStatus, error)
 }
 
 // EventsClient is reactive, you can subscribe to any message, given the proper
@@ -154,6 +157,9 @@ type EventsClient interface {
 type MempoolClient interface {
 	UnconfirmedTxs(ctx context.Context, limit *int) (*ctypes.ResultUnconfirmedTxs, error)
 	NumUnconfirmedTxs(context.Context) (*ctypes.ResultUnconfirmedTxs, error)
+	// MempoolContents returns unconfirmed transactions along with their
+	// priority, sender, gas wanted, arrival time and remaining TTL.
+	MempoolContents(ctx context.Context, limit *int) (*ctypes.ResultMempoolContents, error)
 	CheckTx(context.Context, types.Tx) (*ctypes.ResultCheckTx, error)
 }
 
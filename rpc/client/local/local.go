@@ -106,6 +106,10 @@ func (c *Local) NumUnconfirmedTxs(context.Context) (*ctypes.ResultUnconfirmedTxs
 	return c.env.NumUnconfirmedTxs(c.ctx)
 }
 
+func (c *Local) MempoolContents(_ context.Context, limit *int) (*ctypes.ResultMempoolContents, error) {
+	return c.env.MempoolContents(c.ctx, limit)
+}
+
 func (c *Local) CheckTx(_ context.Context, tx types.Tx) (*ctypes.ResultCheckTx, error) {
 	return c.env.CheckTx(c.ctx, tx)
 }
@@ -130,6 +134,10 @@ func (c *Local) Health(context.Context) (*ctypes.ResultHealth, error) {
 	return c.env.Health(c.ctx)
 }
 
+func (c *Local) StateSyncStatus(context.Context) (*ctypes.ResultStateSyncStatus, error) {
+	return c.env.StateSyncStatus(c.ctx)
+}
+
 func (c *Local) DialSeeds(_ context.Context, seeds []string) (*ctypes.ResultDialSeeds, error) {
 	return c.env.UnsafeDialSeeds(c.ctx, seeds)
 }
@@ -140,6 +140,10 @@ func (c Client) Health(_ context.Context) (*ctypes.ResultHealth, error) {
 	return c.env.Health(&rpctypes.Context{})
 }
 
+func (c Client) StateSyncStatus(_ context.Context) (*ctypes.ResultStateSyncStatus, error) {
+	return c.env.StateSyncStatus(&rpctypes.Context{})
+}
+
 func (c Client) DialSeeds(_ context.Context, seeds []string) (*ctypes.ResultDialSeeds, error) {
 	return c.env.UnsafeDialSeeds(&rpctypes.Context{}, seeds)
 }
@@ -1,6 +1,8 @@
 package core
 
 import (
+	"errors"
+
 	ctypes "github.com/cometbft/cometbft/rpc/core/types"
 	rpctypes "github.com/cometbft/cometbft/rpc/jsonrpc/types"
 )
@@ -10,3 +12,20 @@ func (env *Environment) UnsafeFlushMempool(*rpctypes.Context) (*ctypes.ResultUns
 	env.Mempool.Flush()
 	return &ctypes.ResultUnsafeFlushMempool{}, nil
 }
+
+// rechecker is implemented by mempools that can recheck their transactions on
+// demand, such as the priority mempool.
+type rechecker interface {
+	Recheck() int
+}
+
+// UnsafeRecheckMempool rechecks all transactions in the mempool with the
+// application, as after a block. It is meant for mempools configured with the
+// "on_demand" recheck policy.
+func (env *Environment) UnsafeRecheckMempool(*rpctypes.Context) (*ctypes.ResultUnsafeRecheckMempool, error) {
+	r, ok := env.Mempool.(rechecker)
+	if !ok {
+		return nil, errors.New("the mempool does not support on-demand rechecks")
+	}
+	return &ctypes.ResultUnsafeRecheckMempool{Count: r.Recheck()}, nil
+}
@@ -25,6 +25,7 @@ Available endpoints:
 /health
 /unconfirmed_txs
 /unsafe_flush_mempool
+/unsafe_recheck_mempool
 /validators
 
 Endpoints that require arguments:
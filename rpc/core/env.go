@@ -55,12 +55,22 @@ type peers interface {
 	AddPrivatePeerIDs([]string) error
 	DialPeersAsync([]string) error
 	Peers() p2p.IPeerSet
+	PeerScore(p2p.ID) p2p.PeerScore
+	SetChannelSendRates(map[byte]int64)
+}
+
+type accessList interface {
+	ReloadAccessList() (int, error)
 }
 
 type consensusReactor interface {
 	WaitSync() bool
 }
 
+type stateSyncReactor interface {
+	Status() types.EventDataStateSyncStatus
+}
+
 // ----------------------------------------------
 // Environment contains objects and interfaces used by the RPC. It is expected
 // to be setup once during startup.
@@ -75,8 +85,10 @@ type Environment struct {
 	EvidencePool     sm.EvidencePool
 	ConsensusState   Consensus
 	ConsensusReactor consensusReactor
+	StateSyncReactor stateSyncReactor
 	P2PPeers         peers
 	P2PTransport     transport
+	P2PAccessList    accessList
 
 	// objects
 	PubKey       crypto.PubKey
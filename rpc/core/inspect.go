@@ -0,0 +1,176 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	rpctypes "github.com/cometbft/cometbft/rpc/jsonrpc/types"
+	sm "github.com/cometbft/cometbft/state"
+	"github.com/cometbft/cometbft/types"
+)
+
+// The methods in this file are only exposed by the inspect RPC server, which
+// serves a stopped node's stores for offline analysis.
+
+// maxValidatorChangesRange is the maximum number of heights ValidatorChanges
+// loads in one call.
+const maxValidatorChangesRange = 100
+
+// ValidatorChanges reports the validators added, removed or whose voting power
+// changed between consecutive heights in [from, to]. The validator set at
+// from is compared against the one at from-1, if available; if the store no
+// longer has it, e.g. because it was pruned, the changes at from are omitted.
+func (env *Environment) ValidatorChanges(_ *rpctypes.Context, from, to int64) (*ctypes.ResultValidatorChanges, error) {
+	latest := env.latestUncommittedHeight()
+	if _, err := env.getHeight(latest, &from); err != nil {
+		return nil, err
+	}
+	if _, err := env.getHeight(latest, &to); err != nil {
+		return nil, err
+	}
+	if from > to {
+		return nil, fmt.Errorf("from %d must be less than or equal to to %d", from, to)
+	}
+	if to-from+1 > maxValidatorChangesRange {
+		return nil, fmt.Errorf("range [%d, %d] exceeds the maximum of %d heights", from, to, maxValidatorChangesRange)
+	}
+
+	var (
+		start = from
+		prev  *types.ValidatorSet
+		err   error
+	)
+	if from > 1 {
+		if prev, err = env.StateStore.LoadValidators(from - 1); err == nil {
+			start--
+		}
+	}
+	if start == from {
+		if prev, err = env.StateStore.LoadValidators(from); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &ctypes.ResultValidatorChanges{From: from, To: to, Changes: []ctypes.ValidatorSetDiff{}}
+	for height := start + 1; height <= to; height++ {
+		vals, err := env.StateStore.LoadValidators(height)
+		if err != nil {
+			return nil, err
+		}
+		if diff := diffValidatorSets(height, prev, vals); diff != nil {
+			result.Changes = append(result.Changes, *diff)
+		}
+		prev = vals
+	}
+	return result, nil
+}
+
+// diffValidatorSets returns the changes from prev to next, or nil if the two
+// sets have the same validators with the same voting powers.
+func diffValidatorSets(height int64, prev, next *types.ValidatorSet) *ctypes.ValidatorSetDiff {
+	diff := ctypes.ValidatorSetDiff{Height: height}
+	for _, val := range next.Validators {
+		_, old := prev.GetByAddress(val.Address)
+		switch {
+		case old == nil:
+			diff.Added = append(diff.Added, val)
+		case old.VotingPower != val.VotingPower:
+			diff.Updated = append(diff.Updated, ctypes.ValidatorPowerChange{
+				Address:  val.Address,
+				OldPower: old.VotingPower,
+				NewPower: val.VotingPower,
+			})
+		}
+	}
+	for _, val := range prev.Validators {
+		if !next.HasAddress(val.Address) {
+			diff.Removed = append(diff.Removed, val)
+		}
+	}
+	if len(diff.Added) == 0 && len(diff.Removed) == 0 && len(diff.Updated) == 0 {
+		return nil
+	}
+	return &diff
+}
+
+// FinalizeResults returns the FinalizeBlock response stored for the given
+// height together with the LastResultsHash computed from it. The hash should
+// equal the LastResultsHash of the header at height+1.
+func (env *Environment) FinalizeResults(_ *rpctypes.Context, heightPtr *int64) (*ctypes.ResultFinalizeResults, error) {
+	height, err := env.getHeight(env.BlockStore.Height(), heightPtr)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := env.StateStore.LoadFinalizeBlockResponse(height)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ctypes.ResultFinalizeResults{
+		Height:          height,
+		Response:        resp,
+		LastResultsHash: sm.TxResultsHash(resp.TxResults),
+	}, nil
+}
+
+// Blocks returns the blocks at the given heights, in the order requested. At
+// most RPCConfig.MaxBlocksPerRequest heights can be requested at once. Every
+// height must be within the bounds of the block store.
+func (env *Environment) Blocks(_ *rpctypes.Context, heights []int64) (*ctypes.ResultBlocks, error) {
+	if len(heights) == 0 {
+		return nil, errors.New("no heights requested")
+	}
+	if max := env.Config.MaxBlocksPerRequest; max > 0 && len(heights) > max {
+		return nil, fmt.Errorf("requested %d blocks, maximum is %d", len(heights), max)
+	}
+
+	latest := env.BlockStore.Height()
+	blocks := make([]*ctypes.ResultBlock, 0, len(heights))
+	for _, h := range heights {
+		height, err := env.getHeight(latest, &h)
+		if err != nil {
+			return nil, err
+		}
+		block := env.BlockStore.LoadBlock(height)
+		if block == nil {
+			return nil, fmt.Errorf("block at height %d not found", height)
+		}
+		blockMeta := env.BlockStore.LoadBlockMeta(height)
+		if blockMeta == nil {
+			return nil, fmt.Errorf("block meta at height %d not found", height)
+		}
+		blocks = append(blocks, &ctypes.ResultBlock{BlockID: blockMeta.BlockID, Block: block})
+	}
+	return &ctypes.ResultBlocks{Blocks: blocks}, nil
+}
+
+// BlockPart returns the part at partIndex of the block at the given height,
+// together with the PartSetHeader its proof can be verified against. The part
+// set is rebuilt from the stored block using types.BlockPartSizeBytes.
+func (env *Environment) BlockPart(_ *rpctypes.Context, heightPtr *int64, partIndex int) (*ctypes.ResultBlockPart, error) {
+	height, err := env.getHeight(env.BlockStore.Height(), heightPtr)
+	if err != nil {
+		return nil, err
+	}
+
+	block := env.BlockStore.LoadBlock(height)
+	if block == nil {
+		return nil, fmt.Errorf("block at height %d not found", height)
+	}
+	partSet, err := block.MakePartSet(types.BlockPartSizeBytes)
+	if err != nil {
+		return nil, err
+	}
+	if partIndex < 0 || partIndex >= int(partSet.Total()) {
+		return nil, fmt.Errorf("part index %d out of range, block at height %d has %d parts",
+			partIndex, height, partSet.Total())
+	}
+
+	return &ctypes.ResultBlockPart{
+		Height:        height,
+		PartSetHeader: partSet.Header(),
+		Part:          partSet.GetPart(partIndex),
+	}, nil
+}
@@ -0,0 +1,197 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/cometbft/cometbft/crypto/ed25519"
+	cmtrand "github.com/cometbft/cometbft/libs/rand"
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	rpctypes "github.com/cometbft/cometbft/rpc/jsonrpc/types"
+	"github.com/cometbft/cometbft/state/mocks"
+	"github.com/cometbft/cometbft/types"
+)
+
+func TestValidatorChanges(t *testing.T) {
+	val1 := types.NewValidator(ed25519.GenPrivKey().PubKey(), 10)
+	val2 := types.NewValidator(ed25519.GenPrivKey().PubKey(), 10)
+	val3 := types.NewValidator(ed25519.GenPrivKey().PubKey(), 10)
+	val1Updated := types.NewValidator(val1.PubKey, 20)
+
+	sets := map[int64]*types.ValidatorSet{
+		1: types.NewValidatorSet([]*types.Validator{val1.Copy(), val2.Copy()}),
+		2: types.NewValidatorSet([]*types.Validator{val1.Copy(), val2.Copy()}),
+		3: types.NewValidatorSet([]*types.Validator{val1.Copy(), val2.Copy(), val3.Copy()}),
+		4: types.NewValidatorSet([]*types.Validator{val1Updated.Copy(), val3.Copy()}),
+	}
+
+	stateStore := &mocks.Store{}
+	for h, vals := range sets {
+		stateStore.On("LoadValidators", h).Return(vals, nil)
+	}
+	blockStore := &mocks.BlockStore{}
+	blockStore.On("Height").Return(int64(4))
+	blockStore.On("Base").Return(int64(1))
+
+	env := &Environment{
+		StateStore:       stateStore,
+		BlockStore:       blockStore,
+		ConsensusReactor: syncedReactor{},
+	}
+
+	res, err := env.ValidatorChanges(&rpctypes.Context{}, 1, 4)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), res.From)
+	require.Equal(t, int64(4), res.To)
+	require.Len(t, res.Changes, 2)
+
+	require.Equal(t, int64(3), res.Changes[0].Height)
+	require.Len(t, res.Changes[0].Added, 1)
+	require.Equal(t, val3.Address, res.Changes[0].Added[0].Address)
+	require.Empty(t, res.Changes[0].Removed)
+	require.Empty(t, res.Changes[0].Updated)
+
+	require.Equal(t, int64(4), res.Changes[1].Height)
+	require.Empty(t, res.Changes[1].Added)
+	require.Len(t, res.Changes[1].Removed, 1)
+	require.Equal(t, val2.Address, res.Changes[1].Removed[0].Address)
+	require.Equal(t, []ctypes.ValidatorPowerChange{
+		{Address: val1.Address, OldPower: 10, NewPower: 20},
+	}, res.Changes[1].Updated)
+
+	// the first height is compared against the previous one
+	res, err = env.ValidatorChanges(&rpctypes.Context{}, 3, 3)
+	require.NoError(t, err)
+	require.Len(t, res.Changes, 1)
+	require.Equal(t, int64(3), res.Changes[0].Height)
+
+	// the changes at the first height are omitted if the previous validator
+	// set is not available
+	prunedStore := &mocks.Store{}
+	for h, vals := range sets {
+		if h > 2 {
+			prunedStore.On("LoadValidators", h).Return(vals, nil)
+		}
+	}
+	prunedStore.On("LoadValidators", int64(2)).Return(nil, errors.New("pruned"))
+	env.StateStore = prunedStore
+	res, err = env.ValidatorChanges(&rpctypes.Context{}, 3, 4)
+	require.NoError(t, err)
+	require.Len(t, res.Changes, 1)
+	require.Equal(t, int64(4), res.Changes[0].Height)
+	env.StateStore = stateStore
+
+	_, err = env.ValidatorChanges(&rpctypes.Context{}, 3, 2)
+	require.Error(t, err)
+	_, err = env.ValidatorChanges(&rpctypes.Context{}, 0, 2)
+	require.Error(t, err)
+	_, err = env.ValidatorChanges(&rpctypes.Context{}, 1, 10)
+	require.Error(t, err)
+}
+
+func TestFinalizeResults(t *testing.T) {
+	resp := &abci.ResponseFinalizeBlock{
+		TxResults: []*abci.ExecTxResult{
+			{Code: 0, Data: []byte{0x01}},
+			{Code: 1, Log: "not ok"},
+		},
+		AppHash: []byte("app hash"),
+	}
+	height := int64(10)
+
+	nextBlock := types.MakeBlock(height+1, types.MakeData(nil), nil, nil)
+	nextBlock.LastResultsHash = types.NewResults(resp.TxResults).Hash()
+
+	stateStore := &mocks.Store{}
+	stateStore.On("LoadFinalizeBlockResponse", height).Return(resp, nil)
+	blockStore := &mocks.BlockStore{}
+	blockStore.On("Height").Return(height + 1)
+	blockStore.On("Base").Return(int64(1))
+	blockStore.On("LoadBlockMeta", height+1).Return(&types.BlockMeta{Header: nextBlock.Header})
+
+	env := &Environment{StateStore: stateStore, BlockStore: blockStore}
+
+	res, err := env.FinalizeResults(&rpctypes.Context{}, &height)
+	require.NoError(t, err)
+	require.Equal(t, height, res.Height)
+	require.Equal(t, resp, res.Response)
+
+	meta := env.BlockStore.LoadBlockMeta(height + 1)
+	require.Equal(t, meta.Header.LastResultsHash, res.LastResultsHash)
+}
+
+func TestBlocks(t *testing.T) {
+	blockStore := &mocks.BlockStore{}
+	blockStore.On("Height").Return(int64(5))
+	blockStore.On("Base").Return(int64(2))
+	for h := int64(2); h <= 5; h++ {
+		block := types.MakeBlock(h, types.MakeData(nil), nil, nil)
+		blockStore.On("LoadBlock", h).Return(block)
+		blockStore.On("LoadBlockMeta", h).Return(&types.BlockMeta{
+			BlockID: types.BlockID{Hash: block.Hash()},
+			Header:  block.Header,
+		})
+	}
+
+	env := &Environment{BlockStore: blockStore}
+	env.Config.MaxBlocksPerRequest = 3
+
+	res, err := env.Blocks(&rpctypes.Context{}, []int64{5, 2, 4})
+	require.NoError(t, err)
+	require.Len(t, res.Blocks, 3)
+	for i, h := range []int64{5, 2, 4} {
+		require.Equal(t, h, res.Blocks[i].Block.Height)
+		require.Equal(t, res.Blocks[i].Block.Hash(), res.Blocks[i].BlockID.Hash)
+	}
+
+	// exceeds the maximum count
+	_, err = env.Blocks(&rpctypes.Context{}, []int64{2, 3, 4, 5})
+	require.Error(t, err)
+	// below the store base
+	_, err = env.Blocks(&rpctypes.Context{}, []int64{1})
+	require.Error(t, err)
+	// above the store height
+	_, err = env.Blocks(&rpctypes.Context{}, []int64{6})
+	require.Error(t, err)
+	_, err = env.Blocks(&rpctypes.Context{}, nil)
+	require.Error(t, err)
+}
+
+func TestBlockPart(t *testing.T) {
+	height := int64(3)
+	txs := make([]types.Tx, 10)
+	for i := range txs {
+		txs[i] = cmtrand.Bytes(int(types.BlockPartSizeBytes) / 4)
+	}
+	block := types.MakeBlock(height, types.MakeData(txs), nil, nil)
+	partSet, err := block.MakePartSet(types.BlockPartSizeBytes)
+	require.NoError(t, err)
+	require.Greater(t, partSet.Total(), uint32(1))
+
+	blockStore := &mocks.BlockStore{}
+	blockStore.On("Height").Return(height)
+	blockStore.On("Base").Return(int64(1))
+	blockStore.On("LoadBlock", height).Return(block)
+
+	env := &Environment{BlockStore: blockStore}
+
+	res, err := env.BlockPart(&rpctypes.Context{}, &height, 1)
+	require.NoError(t, err)
+	require.Equal(t, height, res.Height)
+	require.Equal(t, partSet.Header(), res.PartSetHeader)
+	require.Equal(t, uint32(1), res.Part.Index)
+	require.NoError(t, res.Part.ValidateBasic())
+	require.NoError(t, res.Part.Proof.Verify(res.PartSetHeader.Hash, res.Part.Bytes))
+
+	_, err = env.BlockPart(&rpctypes.Context{}, &height, -1)
+	require.Error(t, err)
+	_, err = env.BlockPart(&rpctypes.Context{}, &height, int(partSet.Total()))
+	require.Error(t, err)
+}
+
+type syncedReactor struct{}
+
+func (syncedReactor) WaitSync() bool { return false }
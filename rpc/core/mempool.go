@@ -21,6 +21,14 @@ import (
 // More: https://docs.cometbft.com/v0.38.x/rpc/#/Tx/broadcast_tx_async
 func (env *Environment) BroadcastTxAsync(_ *rpctypes.Context, tx types.Tx) (*ctypes.ResultBroadcastTx, error) {
 	err := env.Mempool.CheckTx(tx, nil, mempl.TxInfo{})
+	if res, ok := mempoolCheckTxResponse(err); ok {
+		return &ctypes.ResultBroadcastTx{
+			Code:      res.Code,
+			Log:       res.Log,
+			Codespace: res.Codespace,
+			Hash:      tx.Hash(),
+		}, nil
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -38,7 +46,9 @@ func (env *Environment) BroadcastTxSync(ctx *rpctypes.Context, tx types.Tx) (*ct
 		case resCh <- res:
 		}
 	}, mempl.TxInfo{})
-	if err != nil {
+	if res, ok := mempoolCheckTxResponse(err); ok {
+		resCh <- res
+	} else if err != nil {
 		return nil, err
 	}
 
@@ -91,7 +101,9 @@ func (env *Environment) BroadcastTxCommit(ctx *rpctypes.Context, tx types.Tx) (*
 		case checkTxResCh <- res:
 		}
 	}, mempl.TxInfo{})
-	if err != nil {
+	if res, ok := mempoolCheckTxResponse(err); ok {
+		checkTxResCh <- res
+	} else if err != nil {
 		env.Logger.Error("Error on broadcastTxCommit", "err", err)
 		return nil, fmt.Errorf("error on broadcastTxCommit: %v", err)
 	}
@@ -143,6 +155,21 @@ func (env *Environment) BroadcastTxCommit(ctx *rpctypes.Context, tx types.Tx) (*
 	}
 }
 
+// mempoolCheckTxResponse returns the CheckTx response standing for err when
+// the mempool rejected a transaction with one of its own codes, so that clients
+// can tell the rejection apart from other errors, and false otherwise.
+func mempoolCheckTxResponse(err error) (*abci.ResponseCheckTx, bool) {
+	var tooLarge mempl.ErrTxTooLarge
+	if !errors.As(err, &tooLarge) {
+		return nil, false
+	}
+	return &abci.ResponseCheckTx{
+		Code:      mempl.CodeTxTooLarge,
+		Log:       err.Error(),
+		Codespace: mempl.Codespace,
+	}, true
+}
+
 // UnconfirmedTxs gets unconfirmed transactions (maximum ?limit entries)
 // including their number.
 // More: https://docs.cometbft.com/v0.38.x/rpc/#/Info/unconfirmed_txs
@@ -163,6 +190,37 @@ func (env *Environment) UnconfirmedTxs(_ *rpctypes.Context, limitPtr *int) (*cty
 	}, nil
 }
 
+// pendingTxsLister is implemented by mempools that keep metadata about their
+// transactions, such as the priority mempool.
+type pendingTxsLister interface {
+	PendingTxs(max int) []mempl.PendingTx
+}
+
+// MempoolContents gets unconfirmed transactions (maximum ?limit entries), in
+// the order they would be reaped, along with their priority, sender, gas
+// wanted, arrival time and remaining TTL. It is only supported by mempools
+// that keep such metadata, such as the priority mempool.
+// If limitPtr == -1, it will return all the unconfirmed transactions in the mempool.
+func (env *Environment) MempoolContents(_ *rpctypes.Context, limitPtr *int) (*ctypes.ResultMempoolContents, error) {
+	lister, ok := env.Mempool.(pendingTxsLister)
+	if !ok {
+		return nil, errors.New("the mempool does not keep transaction metadata")
+	}
+	limit := -1
+	if limitPtr == nil || *limitPtr != -1 {
+		// reuse per_page validator
+		limit = env.validatePerPage(limitPtr)
+	}
+
+	txs := lister.PendingTxs(limit)
+	return &ctypes.ResultMempoolContents{
+		Count:      len(txs),
+		Total:      env.Mempool.Size(),
+		TotalBytes: env.Mempool.SizeBytes(),
+		Txs:        txs,
+	}, nil
+}
+
 // NumUnconfirmedTxs gets number of unconfirmed transactions.
 // More: https://docs.cometbft.com/v0.38.x/rpc/#/Info/num_unconfirmed_txs
 func (env *Environment) NumUnconfirmedTxs(*rpctypes.Context) (*ctypes.ResultUnconfirmedTxs, error) {
@@ -0,0 +1,91 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	mempl "github.com/cometbft/cometbft/mempool"
+	rpctypes "github.com/cometbft/cometbft/rpc/jsonrpc/types"
+	"github.com/cometbft/cometbft/types"
+)
+
+// pendingMempool is a mempool listing a fixed set of pending transactions.
+type pendingMempool struct {
+	mempl.NopMempool
+	txs []mempl.PendingTx
+}
+
+func (m *pendingMempool) Size() int { return len(m.txs) }
+
+func (m *pendingMempool) PendingTxs(max int) []mempl.PendingTx {
+	if max >= 0 && len(m.txs) > max {
+		return m.txs[:max]
+	}
+	return m.txs
+}
+
+func TestMempoolContents(t *testing.T) {
+	mp := &pendingMempool{txs: []mempl.PendingTx{
+		{Tx: types.Tx("a"), Priority: 2, Sender: "alice"},
+		{Tx: types.Tx("b"), Priority: 1},
+	}}
+	env := &Environment{Mempool: mp}
+
+	all := -1
+	res, err := env.MempoolContents(&rpctypes.Context{}, &all)
+	require.NoError(t, err)
+	require.Equal(t, 2, res.Count)
+	require.Equal(t, 2, res.Total)
+	require.Equal(t, mp.txs, res.Txs)
+
+	one := 1
+	res, err = env.MempoolContents(&rpctypes.Context{}, &one)
+	require.NoError(t, err)
+	require.Equal(t, 1, res.Count)
+	require.Equal(t, 2, res.Total)
+
+	// Mempools without transaction metadata aren't supported.
+	env.Mempool = &mempl.NopMempool{}
+	_, err = env.MempoolContents(&rpctypes.Context{}, &all)
+	require.Error(t, err)
+}
+
+// sizedMempool is a mempool rejecting transactions larger than maxTxBytes.
+type sizedMempool struct {
+	mempl.NopMempool
+	maxTxBytes int
+}
+
+func (m *sizedMempool) CheckTx(tx types.Tx, cb func(*abci.ResponseCheckTx), _ mempl.TxInfo) error {
+	if len(tx) > m.maxTxBytes {
+		return mempl.ErrTxTooLarge{Max: m.maxTxBytes, Actual: len(tx)}
+	}
+	if cb != nil {
+		cb(&abci.ResponseCheckTx{Code: abci.CodeTypeOK})
+	}
+	return nil
+}
+
+func TestBroadcastTxTooLarge(t *testing.T) {
+	env := &Environment{Mempool: &sizedMempool{maxTxBytes: 4}}
+	tx := types.Tx("too large")
+
+	res, err := env.BroadcastTxAsync(&rpctypes.Context{}, tx)
+	require.NoError(t, err)
+	require.Equal(t, mempl.CodeTxTooLarge, res.Code)
+	require.Equal(t, mempl.Codespace, res.Codespace)
+
+	res, err = env.BroadcastTxSync(&rpctypes.Context{}, tx)
+	require.NoError(t, err)
+	require.Equal(t, mempl.CodeTxTooLarge, res.Code)
+	require.Equal(t, mempl.Codespace, res.Codespace)
+	require.Equal(t, tx.Hash(), []byte(res.Hash))
+
+	// Transactions within the limit are unaffected.
+	res, err = env.BroadcastTxSync(&rpctypes.Context{}, types.Tx("tx"))
+	require.NoError(t, err)
+	require.Equal(t, abci.CodeTypeOK, res.Code)
+	require.Empty(t, res.Codespace)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	cfg "github.com/cometbft/cometbft/config"
 	"github.com/cometbft/cometbft/p2p"
 	ctypes "github.com/cometbft/cometbft/rpc/core/types"
 	rpctypes "github.com/cometbft/cometbft/rpc/jsonrpc/types"
@@ -20,11 +21,14 @@ func (env *Environment) NetInfo(*rpctypes.Context) (*ctypes.ResultNetInfo, error
 		if !ok {
 			return nil, fmt.Errorf("peer.NodeInfo() is not DefaultNodeInfo")
 		}
+		score := env.P2PPeers.PeerScore(peer.ID())
 		peers = append(peers, ctypes.Peer{
 			NodeInfo:         nodeInfo,
 			IsOutbound:       peer.IsOutbound(),
 			ConnectionStatus: peer.Status(),
 			RemoteIP:         peer.RemoteIP().String(),
+			Score:            score.Value(),
+			ScoreDetails:     score,
 		})
 	}
 	// TODO: Should we include PersistentPeers and Seeds in here?
@@ -94,6 +98,45 @@ func (env *Environment) UnsafeDialPeers(
 	return &ctypes.ResultDialPeers{Log: "Dialing peers in progress. See /net_info for details"}, nil
 }
 
+// UnsafeSetChannelSendRates sets the rates at which packets can be sent on
+// the given channels to all peers, in bytes/second, as a comma separated list
+// of channel:rate pairs (e.g. "0x40:1024000,0x61:512000"). A rate of 0 removes
+// the limit of a channel. The rates are lost on restart: set
+// p2p.channel_send_rates in the config to keep them.
+func (env *Environment) UnsafeSetChannelSendRates(
+	_ *rpctypes.Context,
+	rates string,
+) (*ctypes.ResultSetChannelSendRates, error) {
+	parsed, err := cfg.ParseChannelRates(rates)
+	if err != nil {
+		return nil, err
+	}
+	if len(parsed) == 0 {
+		return nil, errors.New("no channel rates provided")
+	}
+	env.P2PPeers.SetChannelSendRates(parsed)
+
+	res := &ctypes.ResultSetChannelSendRates{Rates: make(map[string]int64, len(parsed))}
+	for chID, rate := range parsed {
+		res.Rates[fmt.Sprintf("%#x", chID)] = rate
+	}
+	return res, nil
+}
+
+// UnsafeReloadAccessList reads the p2p access list again (see
+// p2p.access_list_file in the config), and disconnects the peers it now
+// rejects.
+func (env *Environment) UnsafeReloadAccessList(*rpctypes.Context) (*ctypes.ResultReloadAccessList, error) {
+	if env.P2PAccessList == nil {
+		return nil, errors.New("no p2p access list configured")
+	}
+	disconnected, err := env.P2PAccessList.ReloadAccessList()
+	if err != nil {
+		return nil, err
+	}
+	return &ctypes.ResultReloadAccessList{Disconnected: disconnected}, nil
+}
+
 // Genesis returns genesis file.
 // More: https://docs.cometbft.com/v0.38.x/rpc/#/Info/genesis
 func (env *Environment) Genesis(*rpctypes.Context) (*ctypes.ResultGenesis, error) {
@@ -1,6 +1,7 @@
 package core
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -87,3 +88,52 @@ func TestUnsafeDialPeers(t *testing.T) {
 		}
 	}
 }
+
+func TestUnsafeSetChannelSendRates(t *testing.T) {
+	sw := p2p.MakeSwitch(cfg.DefaultP2PConfig(), 1,
+		func(n int, sw *p2p.Switch) *p2p.Switch { return sw })
+	err := sw.Start()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if err := sw.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	env := &Environment{}
+	env.Logger = log.TestingLogger()
+	env.P2PPeers = sw
+
+	_, err = env.UnsafeSetChannelSendRates(&rpctypes.Context{}, "")
+	assert.Error(t, err)
+	_, err = env.UnsafeSetChannelSendRates(&rpctypes.Context{}, "0x40")
+	assert.Error(t, err)
+
+	res, err := env.UnsafeSetChannelSendRates(&rpctypes.Context{}, "0x40:1024000")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"0x40": 1024000}, res.Rates)
+}
+
+type accessListMock struct {
+	disconnected int
+	err          error
+}
+
+func (m accessListMock) ReloadAccessList() (int, error) {
+	return m.disconnected, m.err
+}
+
+func TestUnsafeReloadAccessList(t *testing.T) {
+	env := &Environment{}
+	_, err := env.UnsafeReloadAccessList(&rpctypes.Context{})
+	assert.Error(t, err)
+
+	env.P2PAccessList = accessListMock{err: errors.New("invalid access list")}
+	_, err = env.UnsafeReloadAccessList(&rpctypes.Context{})
+	assert.Error(t, err)
+
+	env.P2PAccessList = accessListMock{disconnected: 2}
+	res, err := env.UnsafeReloadAccessList(&rpctypes.Context{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, res.Disconnected)
+}
@@ -39,6 +39,8 @@ func (env *Environment) GetRoutes() RoutesMap {
 		"consensus_params":     rpc.NewRPCFunc(env.ConsensusParams, "height", rpc.Cacheable("height")),
 		"unconfirmed_txs":      rpc.NewRPCFunc(env.UnconfirmedTxs, "limit"),
 		"num_unconfirmed_txs":  rpc.NewRPCFunc(env.NumUnconfirmedTxs, ""),
+		"mempool_contents":     rpc.NewRPCFunc(env.MempoolContents, "limit"),
+		"statesync_status":     rpc.NewRPCFunc(env.StateSyncStatus, ""),
 
 		// tx broadcast API
 		"broadcast_tx_commit": rpc.NewRPCFunc(env.BroadcastTxCommit, "tx"),
@@ -67,5 +69,8 @@ func (env *Environment) AddUnsafeRoutes(routes RoutesMap) {
 	// control API
 	routes["dial_seeds"] = rpc.NewRPCFunc(env.UnsafeDialSeeds, "seeds")
 	routes["dial_peers"] = rpc.NewRPCFunc(env.UnsafeDialPeers, "peers,persistent,unconditional,private")
+	routes["unsafe_set_channel_send_rates"] = rpc.NewRPCFunc(env.UnsafeSetChannelSendRates, "rates")
+	routes["unsafe_reload_p2p_access_list"] = rpc.NewRPCFunc(env.UnsafeReloadAccessList, "")
 	routes["unsafe_flush_mempool"] = rpc.NewRPCFunc(env.UnsafeFlushMempool, "")
+	routes["unsafe_recheck_mempool"] = rpc.NewRPCFunc(env.UnsafeRecheckMempool, "")
 }
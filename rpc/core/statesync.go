@@ -0,0 +1,19 @@
+package core
+
+import (
+	"errors"
+
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	rpctypes "github.com/cometbft/cometbft/rpc/jsonrpc/types"
+)
+
+// StateSyncStatus gets the progress of the state sync in progress, or of the
+// last one: the snapshots discovered, the snapshot being restored, the chunks
+// fetched, the throughput and the estimated time left.
+func (env *Environment) StateSyncStatus(*rpctypes.Context) (*ctypes.ResultStateSyncStatus, error) {
+	if env.StateSyncReactor == nil {
+		return nil, errors.New("state sync is not available")
+	}
+	status := ctypes.ResultStateSyncStatus(env.StateSyncReactor.Status())
+	return &status, nil
+}
@@ -0,0 +1,33 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	rpctypes "github.com/cometbft/cometbft/rpc/jsonrpc/types"
+	"github.com/cometbft/cometbft/types"
+)
+
+type stateSyncReactorStatus types.EventDataStateSyncStatus
+
+func (s stateSyncReactorStatus) Status() types.EventDataStateSyncStatus {
+	return types.EventDataStateSyncStatus(s)
+}
+
+func TestStateSyncStatus(t *testing.T) {
+	env := &Environment{}
+	_, err := env.StateSyncStatus(&rpctypes.Context{})
+	require.Error(t, err)
+
+	status := types.EventDataStateSyncStatus{
+		Syncing:       true,
+		Snapshot:      &types.StateSyncSnapshot{Height: 10, Format: 1, Chunks: 4},
+		ChunksFetched: 1,
+		ChunksTotal:   4,
+	}
+	env.StateSyncReactor = stateSyncReactorStatus(status)
+	res, err := env.StateSyncStatus(&rpctypes.Context{})
+	require.NoError(t, err)
+	require.Equal(t, status, types.EventDataStateSyncStatus(*res))
+}
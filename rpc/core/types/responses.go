@@ -8,6 +8,7 @@ import (
 	"github.com/cometbft/cometbft/crypto"
 	"github.com/cometbft/cometbft/crypto/merkle"
 	"github.com/cometbft/cometbft/libs/bytes"
+	"github.com/cometbft/cometbft/mempool"
 	"github.com/cometbft/cometbft/p2p"
 	cmtproto "github.com/cometbft/cometbft/proto/tendermint/types"
 	"github.com/cometbft/cometbft/types"
@@ -104,6 +105,9 @@ type ResultStatus struct {
 	ValidatorInfo ValidatorInfo       `json:"validator_info"`
 }
 
+// Progress of the state sync in progress, or of the last one
+type ResultStateSyncStatus types.EventDataStateSyncStatus
+
 // Is TxIndexing enabled
 func (s *ResultStatus) TxIndexEnabled() bool {
 	if s == nil {
@@ -130,12 +134,24 @@ type ResultDialPeers struct {
 	Log string `json:"log"`
 }
 
+// Channel send rates set at runtime, in bytes/second, by hexadecimal channel id
+type ResultSetChannelSendRates struct {
+	Rates map[string]int64 `json:"rates"`
+}
+
+// Result of reloading the p2p access list
+type ResultReloadAccessList struct {
+	Disconnected int `json:"disconnected"`
+}
+
 // A peer
 type Peer struct {
 	NodeInfo         p2p.DefaultNodeInfo  `json:"node_info"`
 	IsOutbound       bool                 `json:"is_outbound"`
 	ConnectionStatus p2p.ConnectionStatus `json:"connection_status"`
 	RemoteIP         string               `json:"remote_ip"`
+	Score            float64              `json:"score"`
+	ScoreDetails     p2p.PeerScore        `json:"score_details"`
 }
 
 // Validators for a height.
@@ -225,6 +241,20 @@ type ResultUnconfirmedTxs struct {
 	Txs        []types.Tx `json:"txs"`
 }
 
+// List of unconfirmed transactions along with their metadata
+type ResultMempoolContents struct {
+	Count      int                 `json:"n_txs"`
+	Total      int                 `json:"total"`
+	TotalBytes int64               `json:"total_bytes"`
+	Txs        []mempool.PendingTx `json:"txs"`
+}
+
+// List of transactions loaded from a mempool dump
+type ResultMempoolDump struct {
+	Count int                `json:"n_txs"`
+	Txs   []mempool.DumpedTx `json:"txs"`
+}
+
 // Info abci msg
 type ResultABCIInfo struct {
 	Response abci.ResponseInfo `json:"response"`
@@ -235,6 +265,11 @@ type ResultABCIQuery struct {
 	Response abci.ResponseQuery `json:"response"`
 }
 
+// Result of rechecking the mempool
+type ResultUnsafeRecheckMempool struct {
+	Count int `json:"n_txs"`
+}
+
 // Result of broadcasting evidence
 type ResultBroadcastEvidence struct {
 	Hash []byte `json:"hash"`
@@ -286,3 +321,51 @@ type ResultDataRootInclusionProof struct {
 type ResultShareProof struct {
 	ShareProof types.ShareProof `json:"share_proof"`
 }
+
+// ValidatorPowerChange describes a validator whose voting power changed
+// between two consecutive heights.
+type ValidatorPowerChange struct {
+	Address  crypto.Address `json:"address"`
+	OldPower int64          `json:"old_power"`
+	NewPower int64          `json:"new_power"`
+}
+
+// ValidatorSetDiff lists the changes of the validator set at Height compared
+// to the validator set at Height-1.
+type ValidatorSetDiff struct {
+	Height  int64                  `json:"height"`
+	Added   []*types.Validator     `json:"added"`
+	Removed []*types.Validator     `json:"removed"`
+	Updated []ValidatorPowerChange `json:"updated"`
+}
+
+// ResultValidatorChanges lists the validator set changes over a range of
+// heights. Heights at which the validator set did not change are omitted, as
+// is the first height if the validator set before it is no longer stored.
+type ResultValidatorChanges struct {
+	From    int64              `json:"from"`
+	To      int64              `json:"to"`
+	Changes []ValidatorSetDiff `json:"changes"`
+}
+
+// ResultFinalizeResults contains the FinalizeBlock response for a height along
+// with the LastResultsHash computed from its tx results, which is committed to
+// in the header of the next block.
+type ResultFinalizeResults struct {
+	Height          int64                       `json:"height"`
+	Response        *abci.ResponseFinalizeBlock `json:"response"`
+	LastResultsHash bytes.HexBytes              `json:"last_results_hash"`
+}
+
+// ResultBlocks contains multiple blocks, in the order they were requested.
+type ResultBlocks struct {
+	Blocks []*ResultBlock `json:"blocks"`
+}
+
+// ResultBlockPart contains a single part of a block, with its Merkle proof
+// against the block's PartSetHeader.
+type ResultBlockPart struct {
+	Height        int64               `json:"height"`
+	PartSetHeader types.PartSetHeader `json:"part_set_header"`
+	Part          *types.Part         `json:"part"`
+}
@@ -138,6 +138,10 @@ func (blockExec *BlockExecutor) CreateProposalBlock(
 	maxGas := state.ConsensusParams.Block.MaxGas
 
 	evidence, evSize := blockExec.evpool.PendingEvidence(state.ConsensusParams.Evidence.MaxBytes)
+	if max := state.ConsensusParams.Evidence.MaxCount; max > 0 && int64(len(evidence)) > max {
+		evidence = evidence[:max]
+		evSize = (&types.EvidenceData{Evidence: evidence}).ByteSize()
+	}
 
 	// Fetch a limited amount of valid txs
 	maxDataBytes := types.MaxDataBytes(maxBytes, evSize, state.Validators.Size())
@@ -216,12 +220,20 @@ func (blockExec *BlockExecutor) CreateProposalBlock(
 		return nil, nil, err
 	}
 
-	// get the cached hashes
-	// TODO: make sure that the hashes are correct here
-	// via also removing hashes that the application removed!
+	// Reuse the hashes the mempool computed for the txs the application kept,
+	// so that each tx is only hashed once. Txs the application added or
+	// modified are hashed here.
+	reaped := make(map[string][]byte, len(txs))
+	for _, tx := range txs {
+		reaped[string(tx.Tx)] = tx.Hash()
+	}
 	hashes := make([][]byte, len(newData.Txs))
-	for i := 0; i < len(newData.Txs); i++ {
-		hashes[i] = newData.Txs[i].Hash()
+	for i, tx := range newData.Txs {
+		if hash, ok := reaped[string(tx)]; ok {
+			hashes[i] = hash
+			continue
+		}
+		hashes[i] = tx.Hash()
 	}
 
 	block.SetCachedHashes(hashes)
@@ -518,7 +530,7 @@ func (blockExec *BlockExecutor) Commit(
 	// Update mempool.
 	err = blockExec.mempool.Update(
 		block.Height,
-		types.CachedTxFromTxs(block.Txs),
+		types.CachedTxFromTxsWithHashes(block.Txs, block.TxHashes()),
 		abciResponse.TxResults,
 		TxPreCheck(state),
 		TxPostCheck(state),
@@ -821,6 +833,8 @@ func fireEvents(
 		}
 	}
 
+	// The hash of a blob tx is the hash of the tx it wraps, see Tx.Hash.
+	hashes := block.TxHashes()
 	for i, tx := range block.Data.Txs { //nolint:staticcheck
 		blobTx, isBlobTx := types.UnmarshalBlobTx(tx)
 		if isBlobTx {
@@ -831,7 +845,7 @@ func fireEvents(
 			Index:  uint32(i),
 			Tx:     tx,
 			Result: *(abciResponse.TxResults[i]),
-		}}); err != nil {
+		}, Hash: hashes[i]}); err != nil {
 			logger.Error("failed publishing event TX", "err", err)
 		}
 	}
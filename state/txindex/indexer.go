@@ -37,12 +37,15 @@ type TxIndexer interface {
 // NOTE: Batch is NOT thread-safe and must not be modified after starting its execution.
 type Batch struct {
 	Ops []*abci.TxResult
+	// Hashes holds the hash of each tx of Ops, or nil if it isn't known.
+	Hashes [][]byte
 }
 
 // NewBatch creates a new Batch.
 func NewBatch(n int64) *Batch {
 	return &Batch{
-		Ops: make([]*abci.TxResult, n),
+		Ops:    make([]*abci.TxResult, n),
+		Hashes: make([][]byte, n),
 	}
 }
 
@@ -52,6 +55,14 @@ func (b *Batch) Add(result *abci.TxResult) error {
 	return nil
 }
 
+// AddWithHash adds or updates an entry for the given result.Index, along
+// with the hash of its tx, so that it isn't computed again.
+func (b *Batch) AddWithHash(result *abci.TxResult, hash []byte) error {
+	b.Ops[result.Index] = result
+	b.Hashes[result.Index] = hash
+	return nil
+}
+
 // Size returns the total number of operations inside the batch.
 func (b *Batch) Size() int {
 	return len(b.Ops)
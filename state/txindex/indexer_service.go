@@ -71,9 +71,10 @@ func (is *IndexerService) OnStart() error {
 
 				for i := int64(0); i < numTxs; i++ {
 					msg2 := <-txsSub.Out()
-					txResult := msg2.Data().(types.EventDataTx).TxResult
+					eventDataTx := msg2.Data().(types.EventDataTx)
+					txResult := eventDataTx.TxResult
 
-					if err = batch.Add(&txResult); err != nil {
+					if err = batch.AddWithHash(&txResult, eventDataTx.Hash); err != nil {
 						is.Logger.Error(
 							"failed to add tx to batch",
 							"height", height,
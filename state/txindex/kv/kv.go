@@ -86,8 +86,12 @@ func (txi *TxIndex) AddBatch(b *txindex.Batch) error {
 	storeBatch := txi.store.NewBatch()
 	defer storeBatch.Close()
 
-	for _, result := range b.Ops {
-		err := txi.indexResult(storeBatch, result)
+	for i, result := range b.Ops {
+		var hash []byte
+		if i < len(b.Hashes) {
+			hash = b.Hashes[i]
+		}
+		err := txi.indexResult(storeBatch, result, hash)
 		if err != nil {
 			return err
 		}
@@ -749,8 +753,12 @@ func startKey(fields ...interface{}) []byte {
 	return b.Bytes()
 }
 
-func (txi *TxIndex) indexResult(batch dbm.Batch, result *abci.TxResult) error {
-	hash := types.Tx(result.Tx).Hash()
+// indexResult indexes the result of the tx with the given hash, which is
+// computed from the tx if nil.
+func (txi *TxIndex) indexResult(batch dbm.Batch, result *abci.TxResult, hash []byte) error {
+	if hash == nil {
+		hash = types.Tx(result.Tx).Hash()
+	}
 
 	rawBytes, err := proto.Marshal(result)
 	if err != nil {
@@ -842,3 +842,28 @@ func TestWrappedTxIndex(t *testing.T) {
 	require.NoError(t, err)
 	assert.True(t, proto.Equal(txResult, loadedTxResult))
 }
+
+func TestTxIndexBatchWithHashes(t *testing.T) {
+	indexer := NewTxIndex(db.NewMemDB())
+
+	tx := types.Tx("HELLO WORLD")
+	txResult := &abci.TxResult{
+		Height: 1,
+		Index:  0,
+		Tx:     tx,
+		Result: abci.ExecTxResult{
+			Data: []byte{0},
+			Code: abci.CodeTypeOK, Log: "", Events: nil,
+		},
+	}
+
+	// The hash the tx was added with is used rather than computed again.
+	hash := []byte("precomputed hash")
+	batch := txindex.NewBatch(1)
+	require.NoError(t, batch.AddWithHash(txResult, hash))
+	require.NoError(t, indexer.AddBatch(batch))
+
+	loadedTxResult, err := indexer.Get(hash)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(txResult, loadedTxResult))
+}
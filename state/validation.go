@@ -88,9 +88,8 @@ func validateBlock(state State, block *types.Block) error {
 			return errors.New("initial block can't have LastCommit signatures")
 		}
 	} else {
-		// LastCommit.Signatures length is checked in VerifyCommit.
-		if err := state.LastValidators.VerifyCommit(
-			state.ChainID, state.LastBlockID, block.Height-1, block.LastCommit); err != nil {
+		// The chain ID and last block ID were checked against the state above.
+		if err := block.VerifyLastCommit(state.LastValidators); err != nil {
 			return err
 		}
 	}
@@ -146,5 +145,10 @@ func validateBlock(state State, block *types.Block) error {
 		return types.NewErrEvidenceOverflow(max, got)
 	}
 
+	// Check the number of evidence items doesn't exceed the limit, if any.
+	if max, got := state.ConsensusParams.Evidence.MaxCount, int64(len(block.Evidence.Evidence)); max > 0 && got > max {
+		return types.NewErrTooManyEvidence(max, got)
+	}
+
 	return nil
 }
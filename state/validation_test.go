@@ -372,3 +372,55 @@ func TestValidateBlockEvidence(t *testing.T) {
 
 	}
 }
+
+func TestValidateBlockEvidenceCount(t *testing.T) {
+	proxyApp := newTestApp()
+	require.NoError(t, proxyApp.Start())
+	defer proxyApp.Stop() //nolint:errcheck // ignore for tests
+
+	state, stateDB, privVals := makeState(1, 1)
+	stateStore := sm.NewStore(stateDB, sm.StoreOptions{
+		DiscardABCIResponses: false,
+	})
+	state.ConsensusParams.Evidence.MaxCount = 3
+
+	evpool := &mocks.EvidencePool{}
+	evpool.On("CheckEvidence", mock.AnythingOfType("types.EvidenceList")).Return(nil)
+
+	blockExec := sm.NewBlockExecutor(
+		stateStore,
+		log.TestingLogger(),
+		proxyApp.Consensus(),
+		&mpmocks.Mempool{},
+		evpool,
+		store.NewBlockStore(dbm.NewMemDB()),
+	)
+
+	proposerAddr := state.Validators.GetProposer().Address
+	makeEvidence := func(n int) []types.Evidence {
+		evidence := make([]types.Evidence, n)
+		for i := range evidence {
+			ev, err := types.NewMockDuplicateVoteEvidenceWithValidator(state.InitialHeight, time.Now(),
+				privVals[proposerAddr.String()], chainID)
+			require.NoError(t, err)
+			evidence[i] = ev
+		}
+		return evidence
+	}
+
+	// A block with as many evidence items as allowed passes.
+	block, _, err := state.MakeBlock(state.InitialHeight, types.MakeData(test.MakeNTxs(1, 10)),
+		new(types.Commit), makeEvidence(3), proposerAddr)
+	require.NoError(t, err)
+	require.NoError(t, blockExec.ValidateBlock(state, block))
+
+	// A block with one evidence item too many fails.
+	block, _, err = state.MakeBlock(state.InitialHeight, types.MakeData(test.MakeNTxs(1, 10)),
+		new(types.Commit), makeEvidence(4), proposerAddr)
+	require.NoError(t, err)
+	err = blockExec.ValidateBlock(state, block)
+	var tooMany *types.ErrTooManyEvidence
+	require.ErrorAs(t, err, &tooMany)
+	require.Equal(t, int64(3), tooMany.Max)
+	require.Equal(t, int64(4), tooMany.Got)
+}
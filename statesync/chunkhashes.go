@@ -0,0 +1,106 @@
+package statesync
+
+import (
+	"context"
+	"crypto/sha256"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	cmtsync "github.com/cometbft/cometbft/libs/sync"
+)
+
+// chunkHashes caches the hashes of the chunks of the local snapshots, which are advertised to
+// peers along the snapshots. Computing them loads every chunk of a snapshot from the app, so they
+// are computed in the background, one snapshot at a time, and snapshots are advertised without
+// chunk hashes until then.
+type chunkHashes struct {
+	mtx       cmtsync.Mutex
+	hashes    map[snapshotKey][][]byte // nil if they could not be computed
+	computing bool
+}
+
+// newChunkHashes creates an empty chunk hash cache.
+func newChunkHashes() *chunkHashes {
+	return &chunkHashes{
+		hashes: make(map[snapshotKey][][]byte),
+	}
+}
+
+// annotate sets the chunk hashes of the snapshots for which they were computed, and drops those
+// of other snapshots. If no chunk hashes are being computed, it returns the first snapshot whose
+// chunk hashes are yet to be computed, which the caller must compute and set.
+func (c *chunkHashes) annotate(snapshots []*snapshot) *snapshot {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	var next *snapshot
+	keys := make(map[snapshotKey]bool, len(snapshots))
+	for _, s := range snapshots {
+		key := s.Key()
+		keys[key] = true
+		if hashes, ok := c.hashes[key]; ok {
+			s.ChunkHashes = hashes
+		} else if next == nil {
+			next = s
+		}
+	}
+	for key := range c.hashes {
+		if !keys[key] {
+			delete(c.hashes, key)
+		}
+	}
+	if c.computing || next == nil {
+		return nil
+	}
+	c.computing = true
+	return next
+}
+
+// set sets the chunk hashes of the snapshot with the given key, nil if they could not be
+// computed.
+func (c *chunkHashes) set(key snapshotKey, hashes [][]byte) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.hashes[key] = hashes
+	c.computing = false
+}
+
+// computeChunkHashes loads the chunks of the local snapshot from the app to compute their hashes,
+// and sets them in the cache.
+func (r *Reactor) computeChunkHashes(s *snapshot) {
+	key := s.Key()
+	var hashes [][]byte
+	defer func() {
+		r.chunkHashes.set(key, hashes)
+	}()
+
+	// Each hash takes a tag and length byte in the snapshots response.
+	if size := int(s.Chunks) * (sha256.Size + 2); size > snapshotMsgSize {
+		r.Logger.Info("Too many chunks to advertise their hashes", "height", s.Height,
+			"format", s.Format, "chunks", s.Chunks)
+		return
+	}
+
+	r.Logger.Debug("Computing chunk hashes", "height", s.Height, "format", s.Format,
+		"chunks", s.Chunks)
+	computed := make([][]byte, 0, s.Chunks)
+	for index := uint32(0); index < s.Chunks; index++ {
+		select {
+		case <-r.Quit():
+			return
+		default:
+		}
+		resp, err := r.conn.LoadSnapshotChunk(context.TODO(), &abci.RequestLoadSnapshotChunk{
+			Height: s.Height,
+			Format: s.Format,
+			Chunk:  index,
+		})
+		if err != nil || resp.Chunk == nil {
+			r.Logger.Error("Failed to load chunk to compute its hash", "height", s.Height,
+				"format", s.Format, "chunk", index, "err", err)
+			return
+		}
+		hash := sha256.Sum256(resp.Chunk)
+		computed = append(computed, hash[:])
+	}
+	hashes = computed
+}
@@ -0,0 +1,103 @@
+package statesync
+
+import (
+	"crypto/sha256"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/cometbft/cometbft/config"
+	"github.com/cometbft/cometbft/p2p"
+	p2pmocks "github.com/cometbft/cometbft/p2p/mocks"
+	ssproto "github.com/cometbft/cometbft/proto/tendermint/statesync"
+	proxymocks "github.com/cometbft/cometbft/proxy/mocks"
+)
+
+func TestChunkHashes_annotate(t *testing.T) {
+	c := newChunkHashes()
+	s1 := &snapshot{Height: 2, Format: 1, Chunks: 1, Hash: []byte{2}}
+	s2 := &snapshot{Height: 1, Format: 1, Chunks: 1, Hash: []byte{1}}
+	key1, key2 := s1.Key(), s2.Key()
+
+	// The first snapshot is computed first, one snapshot at a time.
+	assert.Equal(t, s1, c.annotate([]*snapshot{s1, s2}))
+	assert.Nil(t, c.annotate([]*snapshot{s1, s2}))
+
+	c.set(key1, [][]byte{{1}})
+	assert.Equal(t, s2, c.annotate([]*snapshot{s1, s2}))
+	assert.Equal(t, [][]byte{{1}}, s1.ChunkHashes)
+	assert.Nil(t, s2.ChunkHashes)
+
+	// Snapshots whose chunk hashes could not be computed are not retried.
+	c.set(key2, nil)
+	s1 = &snapshot{Height: 2, Format: 1, Chunks: 1, Hash: []byte{2}}
+	s2 = &snapshot{Height: 1, Format: 1, Chunks: 1, Hash: []byte{1}}
+	assert.Nil(t, c.annotate([]*snapshot{s1, s2}))
+	assert.Equal(t, [][]byte{{1}}, s1.ChunkHashes)
+	assert.Nil(t, s2.ChunkHashes)
+
+	// The chunk hashes of snapshots which are gone are dropped.
+	s2 = &snapshot{Height: 1, Format: 1, Chunks: 1, Hash: []byte{1}}
+	assert.Nil(t, c.annotate([]*snapshot{s2}))
+	assert.NotContains(t, c.hashes, key1)
+	assert.Contains(t, c.hashes, key2)
+}
+
+func TestReactor_Receive_SnapshotsRequest_chunkHashes(t *testing.T) {
+	conn := &proxymocks.AppConnSnapshot{}
+	conn.On("ListSnapshots", mock.Anything, &abci.RequestListSnapshots{}).Return(&abci.ResponseListSnapshots{
+		Snapshots: []*abci.Snapshot{{Height: 1, Format: 1, Chunks: 2, Hash: []byte{1}}},
+	}, nil)
+	for i := uint32(0); i < 2; i++ {
+		conn.On("LoadSnapshotChunk", mock.Anything, &abci.RequestLoadSnapshotChunk{
+			Height: 1, Format: 1, Chunk: i,
+		}).Once().Return(&abci.ResponseLoadSnapshotChunk{Chunk: []byte{1, 1, byte(i)}}, nil)
+	}
+
+	var (
+		mtx       sync.Mutex
+		responses []*ssproto.SnapshotsResponse
+	)
+	peer := &p2pmocks.Peer{}
+	peer.On("ID").Return(p2p.ID("id"))
+	peer.On("Send", mock.Anything).Run(func(args mock.Arguments) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		responses = append(responses, args[0].(p2p.Envelope).Message.(*ssproto.SnapshotsResponse))
+	}).Return(true)
+
+	cfg := config.DefaultStateSyncConfig()
+	cfg.ChunkHashes = true
+	r, err := NewReactor(*cfg, conn, nil, NopMetrics())
+	require.NoError(t, err)
+	require.NoError(t, r.Start())
+	t.Cleanup(func() {
+		if err := r.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	// The snapshot is advertised without chunk hashes until they are computed.
+	hash0, hash1 := sha256.Sum256([]byte{1, 1, 0}), sha256.Sum256([]byte{1, 1, 1})
+	require.Eventually(t, func() bool {
+		r.Receive(p2p.Envelope{
+			ChannelID: SnapshotChannel,
+			Src:       peer,
+			Message:   &ssproto.SnapshotsRequest{},
+		})
+		mtx.Lock()
+		defer mtx.Unlock()
+		return len(responses[len(responses)-1].ChunkHashes) > 0
+	}, time.Second, 10*time.Millisecond)
+
+	mtx.Lock()
+	defer mtx.Unlock()
+	assert.Nil(t, responses[0].ChunkHashes)
+	assert.Equal(t, [][]byte{hash0[:], hash1[:]}, responses[len(responses)-1].ChunkHashes)
+	conn.AssertExpectations(t)
+}
@@ -1,20 +1,37 @@
 package statesync
 
 import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
 	cmtsync "github.com/cometbft/cometbft/libs/sync"
+	"github.com/cometbft/cometbft/libs/tempfile"
 	"github.com/cometbft/cometbft/p2p"
 )
 
 // errDone is returned by chunkQueue.Next() when all chunks have been returned.
 var errDone = errors.New("chunk queue has completed")
 
+const (
+	// resumableDirPrefix prefixes the dirs of resumable chunk queues, followed by the hex key of
+	// their snapshot.
+	resumableDirPrefix = "tm-statesync-"
+	// chunkManifestFile is the manifest of a resumable chunk queue.
+	chunkManifestFile = "manifest.json"
+	// chunkLogFile logs the chunks added to and discarded from a resumable chunk queue since its
+	// manifest was last saved, so that the manifest need not be rewritten for every chunk.
+	chunkLogFile = "chunks.log"
+)
+
 // chunk contains data for a chunk.
 type chunk struct {
 	Height uint64
@@ -36,6 +53,20 @@ type chunkQueue struct {
 	chunkAllocated map[uint32]bool            // chunks that have been allocated via Allocate()
 	chunkReturned  map[uint32]bool            // chunks returned via Next()
 	waiters        map[uint32][]chan<- uint32 // signals WaitFor() waiters about chunk arrival
+	failed         chan struct{}              // closed by Fail() to stop waiting for chunks
+	persist        bool                       // whether the manifest and chunk log are kept up to date
+}
+
+// chunkManifest is the manifest of a resumable chunk queue, saved along its chunks so that the
+// restore of the snapshot can be resumed from the chunks already fetched after a restart.
+type chunkManifest struct {
+	Height      uint64            `json:"height"`
+	Format      uint32            `json:"format"`
+	Chunks      uint32            `json:"chunks"`
+	Hash        []byte            `json:"hash"`
+	Metadata    []byte            `json:"metadata"`
+	ChunkHashes [][]byte          `json:"chunk_hashes,omitempty"`
+	Senders     map[uint32]p2p.ID `json:"senders"` // fetched chunks, by index
 }
 
 // newChunkQueue creates a new chunk queue for a snapshot, using a temp dir for storage.
@@ -48,6 +79,55 @@ func newChunkQueue(snapshot *snapshot, tempDir string) (*chunkQueue, error) {
 	if snapshot.Chunks == 0 {
 		return nil, errors.New("snapshot has no chunks")
 	}
+	return makeChunkQueue(snapshot, dir), nil
+}
+
+// resumeChunkQueue creates a chunk queue for a snapshot, like newChunkQueue, but in a dir of
+// tempDir named after the snapshot, where it keeps a manifest of the chunks fetched. If the
+// dir already holds chunks of the snapshot, e.g. fetched before the node restarted, the queue
+// resumes from them. Callers must call Close() when done, which removes the dir.
+func resumeChunkQueue(snapshot *snapshot, tempDir string) (*chunkQueue, error) {
+	if snapshot.Chunks == 0 {
+		return nil, errors.New("snapshot has no chunks")
+	}
+	if tempDir == "" {
+		tempDir = os.TempDir()
+	}
+	key := snapshot.Key()
+	dir := filepath.Join(tempDir, resumableDirPrefix+hex.EncodeToString(key[:]))
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("unable to create temp dir for state sync chunks: %w", err)
+	}
+	q := makeChunkQueue(snapshot, dir)
+	q.persist = true
+
+	manifest, err := loadChunkManifest(dir)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, err
+	case manifest.snapshot().Key() == key:
+		for index, sender := range manifest.Senders {
+			if index >= snapshot.Chunks {
+				continue
+			}
+			path := filepath.Join(dir, strconv.FormatUint(uint64(index), 10))
+			if _, err := os.Stat(path); err != nil {
+				continue
+			}
+			q.chunkFiles[index] = path
+			q.chunkSenders[index] = sender
+			q.chunkAllocated[index] = true
+		}
+	}
+	if err := q.saveManifest(); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// makeChunkQueue makes a chunk queue for a snapshot, storing its chunks in dir.
+func makeChunkQueue(snapshot *snapshot, dir string) *chunkQueue {
 	return &chunkQueue{
 		snapshot:       snapshot,
 		dir:            dir,
@@ -56,7 +136,157 @@ func newChunkQueue(snapshot *snapshot, tempDir string) (*chunkQueue, error) {
 		chunkAllocated: make(map[uint32]bool, snapshot.Chunks),
 		chunkReturned:  make(map[uint32]bool, snapshot.Chunks),
 		waiters:        make(map[uint32][]chan<- uint32),
-	}, nil
+		failed:         make(chan struct{}),
+	}
+}
+
+// resumableSnapshot returns the snapshot of the resumable chunk queue of tempDir with the
+// greatest height, if any, along with the number of chunks already fetched.
+func resumableSnapshot(tempDir string) (*snapshot, int) {
+	if tempDir == "" {
+		tempDir = os.TempDir()
+	}
+	dirs, err := filepath.Glob(filepath.Join(tempDir, resumableDirPrefix+"*"))
+	if err != nil {
+		return nil, 0
+	}
+	var (
+		best    *snapshot
+		fetched int
+	)
+	for _, dir := range dirs {
+		manifest, err := loadChunkManifest(dir)
+		if err != nil || manifest.Chunks == 0 || len(manifest.Senders) == 0 {
+			continue
+		}
+		s := manifest.snapshot()
+		if best == nil || s.Height > best.Height {
+			best, fetched = s, len(manifest.Senders)
+		}
+	}
+	return best, fetched
+}
+
+// loadChunkManifest loads the manifest of the resumable chunk queue in dir, updated with the
+// entries of its chunk log.
+func loadChunkManifest(dir string) (*chunkManifest, error) {
+	bz, err := os.ReadFile(filepath.Join(dir, chunkManifestFile))
+	if err != nil {
+		return nil, err
+	}
+	manifest := &chunkManifest{}
+	if err := json.Unmarshal(bz, manifest); err != nil {
+		return nil, fmt.Errorf("invalid state sync chunk manifest in %v: %w", dir, err)
+	}
+	if manifest.Senders == nil {
+		manifest.Senders = make(map[uint32]p2p.ID)
+	}
+	if err := readChunkLog(filepath.Join(dir, chunkLogFile), manifest.Senders); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// readChunkLog applies the entries of the chunk log at path to senders. Each entry is a line,
+// either "+<index> <sender>" for an added chunk, whose sender may be empty, or "-<index>" for a
+// discarded one. Reading stops at the first incomplete or invalid line, e.g. one torn by a crash.
+func readChunkLog(path string, senders map[uint32]p2p.ID) error {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadString('\n')
+		if errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if len(line) == 0 {
+			return nil
+		}
+		op, fields := line[0], strings.Fields(line[1:])
+		if len(fields) == 0 {
+			return nil
+		}
+		index, err := strconv.ParseUint(fields[0], 10, 32)
+		if err != nil {
+			return nil
+		}
+		switch {
+		case op == '+' && len(fields) <= 2:
+			senders[uint32(index)] = p2p.ID(strings.Join(fields[1:], ""))
+		case op == '-' && len(fields) == 1:
+			delete(senders, uint32(index))
+		default:
+			return nil
+		}
+	}
+}
+
+// snapshot returns the snapshot of the manifest.
+func (m *chunkManifest) snapshot() *snapshot {
+	return &snapshot{
+		Height:      m.Height,
+		Format:      m.Format,
+		Chunks:      m.Chunks,
+		Hash:        m.Hash,
+		Metadata:    m.Metadata,
+		ChunkHashes: m.ChunkHashes,
+	}
+}
+
+// saveManifest saves the manifest of the chunks in the queue, if the queue is resumable, and
+// truncates the chunk log it supersedes. The caller must hold the mutex lock.
+func (q *chunkQueue) saveManifest() error {
+	if !q.persist {
+		return nil
+	}
+	manifest := chunkManifest{
+		Height:      q.snapshot.Height,
+		Format:      q.snapshot.Format,
+		Chunks:      q.snapshot.Chunks,
+		Hash:        q.snapshot.Hash,
+		Metadata:    q.snapshot.Metadata,
+		ChunkHashes: q.snapshot.ChunkHashes,
+		Senders:     make(map[uint32]p2p.ID, len(q.chunkFiles)),
+	}
+	for index := range q.chunkFiles {
+		manifest.Senders[index] = q.chunkSenders[index]
+	}
+	bz, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	if err := tempfile.WriteFileAtomic(filepath.Join(q.dir, chunkManifestFile), bz, 0o600); err != nil {
+		return err
+	}
+	// Replaying the log over the new manifest would be harmless, so a crash before the log is
+	// removed leaves a consistent manifest.
+	err = os.Remove(filepath.Join(q.dir, chunkLogFile))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// logChunk appends an entry for a chunk to the chunk log, if the queue is resumable: added by
+// sender if added is true, discarded otherwise. The caller must hold the mutex lock.
+func (q *chunkQueue) logChunk(index uint32, sender p2p.ID, added bool) error {
+	if !q.persist {
+		return nil
+	}
+	entry := fmt.Sprintf("-%d\n", index)
+	if added {
+		entry = fmt.Sprintf("+%d %s\n", index, sender)
+	}
+	return tempfile.AppendToFileSync(filepath.Join(q.dir, chunkLogFile), []byte(entry))
 }
 
 // Add adds a chunk to the queue. It ignores chunks that already exist, returning false.
@@ -81,14 +311,20 @@ func (q *chunkQueue) Add(chunk *chunk) (bool, error) {
 	if q.chunkFiles[chunk.Index] != "" {
 		return false, nil
 	}
+	if err := q.snapshot.verifyChunk(chunk.Index, chunk.Chunk); err != nil {
+		return false, err
+	}
 
 	path := filepath.Join(q.dir, strconv.FormatUint(uint64(chunk.Index), 10))
-	err := os.WriteFile(path, chunk.Chunk, 0600)
+	err := tempfile.WriteFileAtomic(path, chunk.Chunk, 0o600)
 	if err != nil {
 		return false, fmt.Errorf("failed to save chunk %v to file %v: %w", chunk.Index, path, err)
 	}
 	q.chunkFiles[chunk.Index] = path
 	q.chunkSenders[chunk.Index] = chunk.Sender
+	if err := q.logChunk(chunk.Index, chunk.Sender, true); err != nil {
+		return false, fmt.Errorf("failed to log chunk %v: %w", chunk.Index, err)
+	}
 
 	// Signal any waiters that the chunk has arrived.
 	for _, waiter := range q.waiters[chunk.Index] {
@@ -141,6 +377,22 @@ func (q *chunkQueue) Close() error {
 	return nil
 }
 
+// SetChunkHashes sets the chunk hashes the chunks are verified against, if the queue is for the
+// snapshot with the given key, e.g. because its peers now agree on other ones. It returns false
+// if the queue is not for this snapshot, or already verifies these chunk hashes.
+func (q *chunkQueue) SetChunkHashes(key snapshotKey, hashes [][]byte) (bool, error) {
+	q.Lock()
+	defer q.Unlock()
+	if q.snapshot == nil || q.snapshot.Key() != key || equalChunkHashes(q.snapshot.ChunkHashes, hashes) {
+		return false, nil
+	}
+	// The snapshot is replaced rather than modified, as the caller may hold it.
+	snapshot := *q.snapshot
+	snapshot.ChunkHashes = hashes
+	q.snapshot = &snapshot
+	return true, q.saveManifest()
+}
+
 // Discard discards a chunk. It will be removed from the queue, available for allocation, and can
 // be added and returned via Next() again. If the chunk is not already in the queue this does
 // nothing, to avoid it being allocated to multiple fetchers.
@@ -166,6 +418,9 @@ func (q *chunkQueue) discard(index uint32) error {
 	delete(q.chunkFiles, index)
 	delete(q.chunkReturned, index)
 	delete(q.chunkAllocated, index)
+	if err := q.logChunk(index, "", false); err != nil {
+		return fmt.Errorf("failed to log discarded chunk %v: %w", index, err)
+	}
 	return nil
 }
 
@@ -243,6 +498,8 @@ func (q *chunkQueue) Next() (*chunk, error) {
 		if !ok {
 			return nil, errDone // queue closed
 		}
+	case <-q.failed:
+		return nil, errTimeout
 	case <-time.After(chunkTimeout):
 		return nil, errTimeout
 	}
@@ -271,6 +528,18 @@ func (q *chunkQueue) nextUp() (uint32, error) {
 	return 0, errDone
 }
 
+// Fail makes Next() return errTimeout instead of waiting for the chunks which
+// are not in the queue yet, once they can't be fetched anymore.
+func (q *chunkQueue) Fail() {
+	q.Lock()
+	defer q.Unlock()
+	select {
+	case <-q.failed:
+	default:
+		close(q.failed)
+	}
+}
+
 // Retry schedules a chunk to be retried, without refetching it.
 func (q *chunkQueue) Retry(index uint32) {
 	q.Lock()
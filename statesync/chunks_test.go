@@ -1,7 +1,10 @@
 package statesync
 
 import (
+	"crypto/sha256"
+	"errors"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -137,6 +140,28 @@ func TestChunkQueue_Add_ChunkErrors(t *testing.T) {
 	}
 }
 
+func TestChunkQueue_Add_ChunkHashes(t *testing.T) {
+	hash := sha256.Sum256([]byte{3, 1, 0})
+	queue, err := newChunkQueue(&snapshot{
+		Height:      3,
+		Format:      1,
+		Chunks:      1,
+		Hash:        []byte{7},
+		ChunkHashes: [][]byte{hash[:]},
+	}, "")
+	require.NoError(t, err)
+	defer queue.Close()
+
+	added, err := queue.Add(&chunk{Height: 3, Format: 1, Index: 0, Chunk: []byte{3, 1, 9}})
+	require.ErrorIs(t, err, errInvalidChunkHash)
+	assert.False(t, added)
+	assert.False(t, queue.Has(0))
+
+	added, err = queue.Add(&chunk{Height: 3, Format: 1, Index: 0, Chunk: []byte{3, 1, 0}})
+	require.NoError(t, err)
+	assert.True(t, added)
+}
+
 func TestChunkQueue_Allocate(t *testing.T) {
 	queue, teardown := setupChunkQueue(t)
 	defer teardown()
@@ -548,3 +573,104 @@ func TestChunkQueue_WaitFor(t *testing.T) {
 	_, ok = <-w
 	assert.False(t, ok)
 }
+
+func TestChunkQueue_Fail(t *testing.T) {
+	queue, teardown := setupChunkQueue(t)
+	defer teardown()
+
+	queue.Fail()
+	queue.Fail()
+	_, err := queue.Next()
+	assert.Equal(t, errTimeout, err)
+}
+
+func TestResumeChunkQueue(t *testing.T) {
+	dir := t.TempDir()
+	s3 := &snapshot{Height: 3, Format: 1, Chunks: 3, Hash: []byte{7}}
+	s, _ := resumableSnapshot(dir)
+	assert.Nil(t, s)
+
+	queue, err := resumeChunkQueue(s3, dir)
+	require.NoError(t, err)
+	_, err = queue.Add(&chunk{Height: 3, Format: 1, Index: 0, Chunk: []byte{3, 1, 0}, Sender: "a"})
+	require.NoError(t, err)
+	_, err = queue.Add(&chunk{Height: 3, Format: 1, Index: 2, Chunk: []byte{3, 1, 2}, Sender: "b"})
+	require.NoError(t, err)
+
+	// The node restarts without closing the queue, and resumes from the chunks fetched.
+	s, fetched := resumableSnapshot(dir)
+	assert.Equal(t, s3, s)
+	assert.Equal(t, 2, fetched)
+
+	queue, err = resumeChunkQueue(s, dir)
+	require.NoError(t, err)
+	assert.True(t, queue.Has(0))
+	assert.False(t, queue.Has(1))
+	assert.True(t, queue.Has(2))
+	assert.EqualValues(t, "b", queue.GetSender(2))
+	index, err := queue.Allocate()
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, index)
+
+	c, err := queue.Next()
+	require.NoError(t, err)
+	assert.Equal(t, &chunk{Height: 3, Format: 1, Index: 0, Chunk: []byte{3, 1, 0}, Sender: "a"}, c)
+
+	// Discarded chunks are not resumed.
+	require.NoError(t, queue.Discard(0))
+	_, fetched = resumableSnapshot(dir)
+	assert.Equal(t, 1, fetched)
+
+	// The chunks of other snapshots are not resumed.
+	other, err := resumeChunkQueue(&snapshot{Height: 2, Format: 1, Chunks: 3, Hash: []byte{8}}, dir)
+	require.NoError(t, err)
+	assert.False(t, other.Has(2))
+	require.NoError(t, other.Close())
+
+	// Closing the queue removes its chunks.
+	require.NoError(t, queue.Close())
+	s, _ = resumableSnapshot(dir)
+	assert.Nil(t, s)
+}
+
+func TestResumeChunkQueue_ChunkLog(t *testing.T) {
+	dir := t.TempDir()
+	s := &snapshot{Height: 3, Format: 1, Chunks: 4, Hash: []byte{7}}
+	queue, err := resumeChunkQueue(s, dir)
+	require.NoError(t, err)
+	manifest, err := os.ReadFile(filepath.Join(queue.dir, chunkManifestFile))
+	require.NoError(t, err)
+
+	// Adding and discarding chunks appends to the chunk log, leaving the manifest untouched.
+	for i := uint32(0); i < 3; i++ {
+		_, err = queue.Add(&chunk{Height: 3, Format: 1, Index: i, Chunk: []byte{3, 1, byte(i)}, Sender: "a"})
+		require.NoError(t, err)
+	}
+	_, err = queue.Add(&chunk{Height: 3, Format: 1, Index: 3, Chunk: []byte{3, 1, 3}})
+	require.NoError(t, err)
+	require.NoError(t, queue.Discard(1))
+	bz, err := os.ReadFile(filepath.Join(queue.dir, chunkManifestFile))
+	require.NoError(t, err)
+	assert.Equal(t, manifest, bz)
+
+	// A torn entry at the end of the log is ignored.
+	f, err := os.OpenFile(filepath.Join(queue.dir, chunkLogFile), os.O_WRONLY|os.O_APPEND, 0o600)
+	require.NoError(t, err)
+	_, err = f.WriteString("-2")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	loaded, err := loadChunkManifest(queue.dir)
+	require.NoError(t, err)
+	assert.Equal(t, map[uint32]p2p.ID{0: "a", 2: "a", 3: ""}, loaded.Senders)
+
+	// Resuming the queue folds the log into the manifest.
+	queue, err = resumeChunkQueue(s, dir)
+	require.NoError(t, err)
+	_, err = os.Stat(filepath.Join(queue.dir, chunkLogFile))
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+	loaded, err = loadChunkManifest(queue.dir)
+	require.NoError(t, err)
+	assert.Equal(t, map[uint32]p2p.ID{0: "a", 2: "a", 3: ""}, loaded.Senders)
+	require.NoError(t, queue.Close())
+}
@@ -0,0 +1,108 @@
+package statesync
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+)
+
+const (
+	// localSnapshotFile is the file of a local snapshot source holding the snapshot, as the JSON
+	// encoding of an abci.Snapshot.
+	localSnapshotFile = "snapshot.json"
+	// localChunksDir is the directory of a local snapshot source holding the chunks, one file per
+	// chunk named after its index.
+	localChunksDir = "chunks"
+)
+
+// localSource loads an exported snapshot and its chunks from a local directory, or from an HTTP
+// URL serving the same layout, to restore it without fetching it from peers.
+type localSource struct {
+	location string
+	client   *http.Client
+}
+
+// newLocalSource creates a local snapshot source for the given directory or http(s) URL.
+func newLocalSource(location string) *localSource {
+	return &localSource{
+		location: location,
+		client:   &http.Client{Timeout: chunkTimeout},
+	}
+}
+
+// isURL returns true if the snapshot is served over HTTP.
+func (l *localSource) isURL() bool {
+	return strings.HasPrefix(l.location, "http://") || strings.HasPrefix(l.location, "https://")
+}
+
+// Snapshot loads the snapshot.
+func (l *localSource) Snapshot(ctx context.Context) (*snapshot, error) {
+	bz, err := l.load(ctx, localSnapshotFile)
+	if err != nil {
+		return nil, err
+	}
+	var s abci.Snapshot
+	if err := json.Unmarshal(bz, &s); err != nil {
+		return nil, fmt.Errorf("invalid snapshot in %v: %w", l.location, err)
+	}
+	if s.Chunks == 0 {
+		return nil, fmt.Errorf("snapshot in %v has no chunks", l.location)
+	}
+	return &snapshot{
+		Height:   s.Height,
+		Format:   s.Format,
+		Chunks:   s.Chunks,
+		Hash:     s.Hash,
+		Metadata: s.Metadata,
+	}, nil
+}
+
+// Chunk loads the chunk with the given index.
+func (l *localSource) Chunk(ctx context.Context, index uint32) ([]byte, error) {
+	return l.load(ctx, localChunksDir, strconv.FormatUint(uint64(index), 10))
+}
+
+// load reads the file with the given path elements, relative to the location of the source.
+func (l *localSource) load(ctx context.Context, elem ...string) ([]byte, error) {
+	if !l.isURL() {
+		file := filepath.Join(append([]string{l.location}, elem...)...)
+		bz, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %v: %w", file, err)
+		}
+		return bz, nil
+	}
+
+	url := strings.TrimSuffix(l.location, "/") + "/" + path.Join(elem...)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %v: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to load %v: %v", url, resp.Status)
+	}
+	// Bound what a misbehaving server can make us buffer by the largest chunk
+	// peers may send.
+	bz, err := io.ReadAll(io.LimitReader(resp.Body, int64(chunkMsgSize)+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %v: %w", url, err)
+	}
+	if len(bz) > chunkMsgSize {
+		return nil, fmt.Errorf("failed to load %v: larger than %d bytes", url, chunkMsgSize)
+	}
+	return bz, nil
+}
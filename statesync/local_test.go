@@ -0,0 +1,144 @@
+package statesync
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/cometbft/cometbft/config"
+	"github.com/cometbft/cometbft/libs/log"
+	"github.com/cometbft/cometbft/proxy"
+	proxymocks "github.com/cometbft/cometbft/proxy/mocks"
+	sm "github.com/cometbft/cometbft/state"
+	"github.com/cometbft/cometbft/statesync/mocks"
+	"github.com/cometbft/cometbft/types"
+)
+
+// exportSnapshot writes the snapshot and its chunks to a temp dir, in the layout of a local
+// snapshot source.
+func exportSnapshot(t *testing.T, s *abci.Snapshot, chunks [][]byte) string {
+	dir := t.TempDir()
+	bz, err := json.Marshal(s)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, localSnapshotFile), bz, 0o600))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, localChunksDir), 0o700))
+	for i, chunk := range chunks {
+		file := filepath.Join(dir, localChunksDir, strconv.Itoa(i))
+		require.NoError(t, os.WriteFile(file, chunk, 0o600))
+	}
+	return dir
+}
+
+func TestLocalSource(t *testing.T) {
+	s := &abci.Snapshot{Height: 3, Format: 1, Chunks: 2, Hash: []byte{1, 2, 3}, Metadata: []byte{4}}
+	dir := exportSnapshot(t, s, [][]byte{{3, 1, 0}, {3, 1, 1}})
+	srv := httptest.NewServer(http.FileServer(http.Dir(dir)))
+	defer srv.Close()
+
+	for name, location := range map[string]string{
+		"dir":  dir,
+		"http": srv.URL + "/",
+	} {
+		t.Run(name, func(t *testing.T) {
+			source := newLocalSource(location)
+			got, err := source.Snapshot(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, &snapshot{
+				Height: 3, Format: 1, Chunks: 2, Hash: []byte{1, 2, 3}, Metadata: []byte{4},
+			}, got)
+
+			bz, err := source.Chunk(context.Background(), 1)
+			require.NoError(t, err)
+			assert.Equal(t, []byte{3, 1, 1}, bz)
+
+			_, err = source.Chunk(context.Background(), 2)
+			assert.Error(t, err)
+		})
+	}
+
+	_, err := newLocalSource(t.TempDir()).Snapshot(context.Background())
+	assert.Error(t, err)
+}
+
+func TestLocalSourceOversizedChunk(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(make([]byte, chunkMsgSize+1))
+	}))
+	defer srv.Close()
+
+	_, err := newLocalSource(srv.URL).Chunk(context.Background(), 0)
+	assert.Error(t, err)
+}
+
+func TestSyncer_SyncAny_local(t *testing.T) {
+	state := sm.State{
+		ChainID:         "chain",
+		LastBlockHeight: 1,
+		AppHash:         []byte("app_hash"),
+		ConsensusParams: *types.DefaultConsensusParams(),
+	}
+	state.Version.Consensus.App = testAppVersion
+	state.ConsensusParams.Version.App = testAppVersion
+	commit := &types.Commit{BlockID: types.BlockID{Hash: []byte("blockhash")}}
+
+	s := &abci.Snapshot{Height: 1, Format: 1, Chunks: 2, Hash: []byte{1, 2, 3}}
+	dir := exportSnapshot(t, s, [][]byte{{1, 1, 0}, {1, 1, 1}})
+
+	testcases := map[string]struct {
+		lastBlockAppHash []byte
+		expectErr        bool
+	}{
+		"verified app hash": {[]byte("app_hash"), false},
+		"invalid app hash":  {[]byte("xxx"), true},
+	}
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			stateProvider := &mocks.StateProvider{}
+			stateProvider.On("AppHash", mock.Anything, uint64(1)).Return(state.AppHash, nil)
+			stateProvider.On("Commit", mock.Anything, uint64(1)).Return(commit, nil)
+			stateProvider.On("State", mock.Anything, uint64(1)).Return(state, nil)
+			connSnapshot := &proxymocks.AppConnSnapshot{}
+			connQuery := &proxymocks.AppConnQuery{}
+
+			cfg := config.DefaultStateSyncConfig()
+			cfg.LocalPath = dir
+			syncer := newSyncer(*cfg, log.NewNopLogger(), connSnapshot, connQuery, stateProvider, "", NopMetrics())
+
+			connSnapshot.On("OfferSnapshot", mock.Anything, &abci.RequestOfferSnapshot{
+				Snapshot:   s,
+				AppHash:    []byte("app_hash"),
+				AppVersion: testAppVersion,
+			}).Return(&abci.ResponseOfferSnapshot{Result: abci.ResponseOfferSnapshot_ACCEPT}, nil)
+			for i := uint32(0); i < s.Chunks; i++ {
+				connSnapshot.On("ApplySnapshotChunk", mock.Anything, &abci.RequestApplySnapshotChunk{
+					Index: i, Chunk: []byte{1, 1, byte(i)},
+				}).Once().Return(&abci.ResponseApplySnapshotChunk{Result: abci.ResponseApplySnapshotChunk_ACCEPT}, nil)
+			}
+			connQuery.On("Info", mock.Anything, proxy.RequestInfo).Return(&abci.ResponseInfo{
+				AppVersion:       testAppVersion,
+				LastBlockHeight:  1,
+				LastBlockAppHash: tc.lastBlockAppHash,
+			}, nil)
+
+			newState, lastCommit, err := syncer.SyncAny(0, func() {})
+			if tc.expectErr {
+				require.ErrorIs(t, err, errVerifyFailed)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, state, newState)
+			assert.Equal(t, commit, lastCommit)
+			connSnapshot.AssertExpectations(t)
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package statesync
 
 import (
+	"crypto/sha256"
 	"errors"
 	"fmt"
 
@@ -16,6 +17,18 @@ const (
 	chunkMsgSize = int(16e6)
 )
 
+// chunkResponseSize returns the encoded size of a chunk response, as sent over the wire.
+func chunkResponseSize(msg *ssproto.ChunkResponse) int {
+	wrapped := &ssproto.Message{Sum: &ssproto.Message_ChunkResponse{ChunkResponse: msg}}
+	return wrapped.Size()
+}
+
+// snapshotsResponseSize returns the encoded size of a snapshots response, as sent over the wire.
+func snapshotsResponseSize(msg *ssproto.SnapshotsResponse) int {
+	wrapped := &ssproto.Message{Sum: &ssproto.Message_SnapshotsResponse{SnapshotsResponse: msg}}
+	return wrapped.Size()
+}
+
 // validateMsg validates a message.
 func validateMsg(pb proto.Message) error {
 	if pb == nil {
@@ -47,6 +60,14 @@ func validateMsg(pb proto.Message) error {
 		if msg.Chunks == 0 {
 			return errors.New("snapshot has no chunks")
 		}
+		if len(msg.ChunkHashes) > 0 && len(msg.ChunkHashes) != int(msg.Chunks) {
+			return fmt.Errorf("snapshot has %v chunk hashes for %v chunks", len(msg.ChunkHashes), msg.Chunks)
+		}
+		for i, hash := range msg.ChunkHashes {
+			if len(hash) != sha256.Size {
+				return fmt.Errorf("chunk hash %v has invalid size %v", i, len(hash))
+			}
+		}
 	default:
 		return fmt.Errorf("unknown message type %T", msg)
 	}
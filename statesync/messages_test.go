@@ -70,6 +70,18 @@ func TestValidateMsg(t *testing.T) {
 		"SnapshotsResponse no hash": {
 			&ssproto.SnapshotsResponse{Height: 1, Format: 1, Chunks: 2, Hash: []byte{}},
 			false},
+		"SnapshotsResponse chunk hashes": {
+			&ssproto.SnapshotsResponse{Height: 1, Format: 1, Chunks: 2, Hash: []byte{1},
+				ChunkHashes: [][]byte{make([]byte, 32), make([]byte, 32)}},
+			true},
+		"SnapshotsResponse missing chunk hashes": {
+			&ssproto.SnapshotsResponse{Height: 1, Format: 1, Chunks: 2, Hash: []byte{1},
+				ChunkHashes: [][]byte{make([]byte, 32)}},
+			false},
+		"SnapshotsResponse invalid chunk hash": {
+			&ssproto.SnapshotsResponse{Height: 1, Format: 1, Chunks: 2, Hash: []byte{1},
+				ChunkHashes: [][]byte{make([]byte, 32), {1}}},
+			false},
 	}
 	for name, tc := range testcases {
 		tc := tc
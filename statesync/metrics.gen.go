@@ -20,11 +20,60 @@ func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
 			Name:      "syncing",
 			Help:      "Whether or not a node is state syncing. 1 if yes, 0 if no.",
 		}, labels).With(labelsAndValues...),
+		SnapshotsDiscovered: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "snapshots_discovered",
+			Help:      "Number of new snapshots discovered from peers.",
+		}, labels).With(labelsAndValues...),
+		SnapshotsRejected: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "snapshots_rejected",
+			Help:      "Number of snapshots rejected, labeled by the reason.",
+		}, append(labels, "reason")).With(labelsAndValues...),
+		SnapshotPeers: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "snapshot_peers",
+			Help:      "Number of peers advertising the snapshot chosen for restoration.",
+		}, labels).With(labelsAndValues...),
+		ChunksApplied: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "chunks_applied",
+			Help:      "Number of snapshot chunks accepted by the application.",
+		}, labels).With(labelsAndValues...),
+		ChunkBytesApplied: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "chunk_bytes_applied",
+			Help:      "Total size in bytes of the snapshot chunks accepted by the application.",
+		}, labels).With(labelsAndValues...),
+		SnapshotsRequests: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "snapshots_requests",
+			Help:      "Number of requests of peers for the local snapshots.",
+		}, labels).With(labelsAndValues...),
+		ChunkRequests: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "chunk_requests",
+			Help:      "Number of requests of peers for chunks of the local snapshots, labeled by the snapshot format, or unserved for snapshots the node does not serve, and whether the chunk was missing.",
+		}, append(labels, "format", "missing")).With(labelsAndValues...),
 	}
 }
 
 func NopMetrics() *Metrics {
 	return &Metrics{
-		Syncing: discard.NewGauge(),
+		Syncing:             discard.NewGauge(),
+		SnapshotsDiscovered: discard.NewCounter(),
+		SnapshotsRejected:   discard.NewCounter(),
+		SnapshotPeers:       discard.NewGauge(),
+		ChunksApplied:       discard.NewCounter(),
+		ChunkBytesApplied:   discard.NewCounter(),
+		SnapshotsRequests:   discard.NewCounter(),
+		ChunkRequests:       discard.NewCounter(),
 	}
 }
@@ -16,4 +16,20 @@ const (
 type Metrics struct {
 	// Whether or not a node is state syncing. 1 if yes, 0 if no.
 	Syncing metrics.Gauge
+	// Number of new snapshots discovered from peers.
+	SnapshotsDiscovered metrics.Counter
+	// Number of snapshots rejected, labeled by the reason.
+	SnapshotsRejected metrics.Counter `metrics_labels:"reason"`
+	// Number of peers advertising the snapshot chosen for restoration.
+	SnapshotPeers metrics.Gauge
+	// Number of snapshot chunks accepted by the application.
+	ChunksApplied metrics.Counter
+	// Total size in bytes of the snapshot chunks accepted by the application.
+	ChunkBytesApplied metrics.Counter
+	// Number of requests of peers for the local snapshots.
+	SnapshotsRequests metrics.Counter
+	// Number of requests of peers for chunks of the local snapshots, labeled
+	// by the snapshot format, or unserved for snapshots the node does not
+	// serve, and whether the chunk was missing.
+	ChunkRequests metrics.Counter `metrics_labels:"format, missing"`
 }
@@ -2,7 +2,9 @@ package statesync
 
 import (
 	"context"
+	"encoding/hex"
 	"errors"
+	"fmt"
 	"sort"
 	"time"
 
@@ -36,28 +38,71 @@ type Reactor struct {
 	tempDir   string
 	metrics   *Metrics
 
+	// maxChunkMsgSize is the RecvMessageCapacity of the chunk channel. Chunks loaded from the
+	// app are not served if the resulting message would exceed it.
+	maxChunkMsgSize int
+
+	// trustedSnapshotHash is the decoded TrustedSnapshotHash of cfg, if TrustedSnapshotHeight
+	// is set.
+	trustedSnapshotHash []byte
+
+	// chunkHashes caches the chunk hashes advertised along the local snapshots, if enabled.
+	chunkHashes *chunkHashes
+
+	// usage tracks the requests of peers for the local snapshots, reported to usageHook.
+	usage     *snapshotUsage
+	usageHook SnapshotUsageHook
+
 	// This will only be set when a state sync is in progress. It is used to feed received
 	// snapshots and chunks into the sync.
 	mtx    cmtsync.RWMutex
 	syncer *syncer
+
+	// progress tracks the progress of the last state sync, if any.
+	progress *syncProgress
+
+	// scores keeps the rejections of peers, formats and snapshots across state syncs and
+	// snapshot discoveries, for rejectionTTL.
+	scores *peerScores
+
+	// This will only be set while DiscoverSnapshots is running. It collects the snapshots
+	// advertised by peers.
+	discovery *snapshotPool
 }
 
-// NewReactor creates a new state sync reactor.
+// NewReactor creates a new state sync reactor. It returns an error if the trusted snapshot
+// hash of cfg is invalid, as it is also used to discover snapshots when state sync is disabled.
 func NewReactor(
 	cfg config.StateSyncConfig,
 	conn proxy.AppConnSnapshot,
 	connQuery proxy.AppConnQuery,
 	metrics *Metrics,
-) *Reactor {
+) (*Reactor, error) {
 	r := &Reactor{
 		cfg:       cfg,
 		conn:      conn,
 		connQuery: connQuery,
 		metrics:   metrics,
+		tempDir:   cfg.TempDir,
+		progress:  newSyncProgress(),
+		scores:    newPeerScores(),
+		usage:     newSnapshotUsage(),
+
+		maxChunkMsgSize: chunkMsgSize,
+	}
+	if cfg.TrustedSnapshotHeight > 0 {
+		hash, err := hex.DecodeString(cfg.TrustedSnapshotHash)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted_snapshot_hash: %w", err)
+		}
+		r.trustedSnapshotHash = hash
+	}
+	if cfg.ChunkHashes {
+		r.chunkHashes = newChunkHashes()
 	}
 	r.BaseReactor = *p2p.NewBaseReactor("StateSync", r)
 
-	return r
+	return r, nil
 }
 
 // GetChannels implements p2p.Reactor.
@@ -74,7 +119,7 @@ func (r *Reactor) GetChannels() []*p2p.ChannelDescriptor {
 			ID:                  ChunkChannel,
 			Priority:            3,
 			SendQueueCapacity:   10,
-			RecvMessageCapacity: chunkMsgSize,
+			RecvMessageCapacity: r.maxChunkMsgSize,
 			MessageType:         &ssproto.Message{},
 		},
 	}
@@ -82,6 +127,9 @@ func (r *Reactor) GetChannels() []*p2p.ChannelDescriptor {
 
 // OnStart implements p2p.Reactor.
 func (r *Reactor) OnStart() error {
+	if r.usageHook != nil && r.cfg.SnapshotUsageInterval > 0 {
+		go r.reportSnapshotUsageRoutine(r.cfg.SnapshotUsageInterval)
+	}
 	return nil
 }
 
@@ -121,41 +169,63 @@ func (r *Reactor) Receive(e p2p.Envelope) {
 	case SnapshotChannel:
 		switch msg := e.Message.(type) {
 		case *ssproto.SnapshotsRequest:
+			r.metrics.SnapshotsRequests.Add(1)
+			r.usage.snapshotsRequested()
 			snapshots, err := r.recentSnapshots(recentSnapshots)
 			if err != nil {
 				r.Logger.Error("Failed to fetch snapshots", "err", err)
 				return
 			}
+			r.usage.snapshotsServed(snapshots)
 			for _, snapshot := range snapshots {
 				r.Logger.Debug("Advertising snapshot", "height", snapshot.Height,
 					"format", snapshot.Format, "peer", e.Src.ID())
+				snapshotsResp := &ssproto.SnapshotsResponse{
+					Height:      snapshot.Height,
+					Format:      snapshot.Format,
+					Chunks:      snapshot.Chunks,
+					Hash:        snapshot.Hash,
+					Metadata:    snapshot.Metadata,
+					ChunkHashes: snapshot.ChunkHashes,
+				}
+				if len(snapshotsResp.ChunkHashes) > 0 && snapshotsResponseSize(snapshotsResp) > snapshotMsgSize {
+					snapshotsResp.ChunkHashes = nil
+				}
 				e.Src.Send(p2p.Envelope{
 					ChannelID: e.ChannelID,
-					Message: &ssproto.SnapshotsResponse{
-						Height:   snapshot.Height,
-						Format:   snapshot.Format,
-						Chunks:   snapshot.Chunks,
-						Hash:     snapshot.Hash,
-						Metadata: snapshot.Metadata,
-					},
+					Message:   snapshotsResp,
 				})
 			}
 
 		case *ssproto.SnapshotsResponse:
 			r.mtx.RLock()
 			defer r.mtx.RUnlock()
-			if r.syncer == nil {
+			if r.syncer == nil && r.discovery == nil {
 				r.Logger.Debug("Received unexpected snapshot, no state sync in progress")
 				return
 			}
 			r.Logger.Debug("Received snapshot", "height", msg.Height, "format", msg.Format, "peer", e.Src.ID())
-			_, err := r.syncer.AddSnapshot(e.Src, &snapshot{
-				Height:   msg.Height,
-				Format:   msg.Format,
-				Chunks:   msg.Chunks,
-				Hash:     msg.Hash,
-				Metadata: msg.Metadata,
-			})
+			s := &snapshot{
+				Height:      msg.Height,
+				Format:      msg.Format,
+				Chunks:      msg.Chunks,
+				Hash:        msg.Hash,
+				Metadata:    msg.Metadata,
+				ChunkHashes: msg.ChunkHashes,
+			}
+			var err error
+			if r.syncer != nil {
+				_, err = r.syncer.AddSnapshot(e.Src, s)
+			} else if err = checkTrustedSnapshot(s, r.cfg.TrustedSnapshotHeight,
+				r.trustedSnapshotHash); err != nil {
+				r.metrics.SnapshotsRejected.With("reason", "untrusted_hash").Add(1)
+			} else {
+				var added bool
+				added, err = r.discovery.Add(e.Src, s)
+				if added {
+					r.metrics.SnapshotsDiscovered.Add(1)
+				}
+			}
 			// TODO: We may want to consider punishing the peer for certain errors
 			if err != nil {
 				r.Logger.Error("Failed to add snapshot", "height", msg.Height, "format", msg.Format,
@@ -180,19 +250,28 @@ func (r *Reactor) Receive(e p2p.Envelope) {
 			if err != nil {
 				r.Logger.Error("Failed to load chunk", "height", msg.Height, "format", msg.Format,
 					"chunk", msg.Index, "err", err)
+				r.chunkRequested(e.Src.ID(), msg, true)
 				return
 			}
+			chunkResp := &ssproto.ChunkResponse{
+				Height:  msg.Height,
+				Format:  msg.Format,
+				Index:   msg.Index,
+				Chunk:   resp.Chunk,
+				Missing: resp.Chunk == nil,
+			}
+			if size := chunkResponseSize(chunkResp); size > r.maxChunkMsgSize {
+				r.Logger.Error("Chunk too large to send, marking it missing", "height", msg.Height,
+					"format", msg.Format, "chunk", msg.Index, "size", size, "max", r.maxChunkMsgSize)
+				chunkResp.Chunk = nil
+				chunkResp.Missing = true
+			}
+			r.chunkRequested(e.Src.ID(), msg, chunkResp.Missing)
 			r.Logger.Debug("Sending chunk", "height", msg.Height, "format", msg.Format,
 				"chunk", msg.Index, "peer", e.Src.ID())
 			e.Src.Send(p2p.Envelope{
 				ChannelID: ChunkChannel,
-				Message: &ssproto.ChunkResponse{
-					Height:  msg.Height,
-					Format:  msg.Format,
-					Index:   msg.Index,
-					Chunk:   resp.Chunk,
-					Missing: resp.Chunk == nil,
-				},
+				Message:   chunkResp,
 			})
 
 		case *ssproto.ChunkResponse:
@@ -211,6 +290,10 @@ func (r *Reactor) Receive(e p2p.Envelope) {
 				Chunk:  msg.Chunk,
 				Sender: e.Src.ID(),
 			})
+			if errors.Is(err, errBadChunk) {
+				r.Switch.StopPeerForError(e.Src, err, r.String())
+				return
+			}
 			if err != nil {
 				r.Logger.Error("Failed to add chunk", "height", msg.Height, "format", msg.Format,
 					"chunk", msg.Index, "err", err)
@@ -257,6 +340,11 @@ func (r *Reactor) recentSnapshots(n uint32) ([]*snapshot, error) {
 			Metadata: s.Metadata,
 		})
 	}
+	if r.chunkHashes != nil {
+		if s := r.chunkHashes.annotate(snapshots); s != nil {
+			go r.computeChunkHashes(s)
+		}
+	}
 	return snapshots, nil
 }
 
@@ -269,7 +357,10 @@ func (r *Reactor) Sync(stateProvider StateProvider, discoveryTime time.Duration)
 		return sm.State{}, nil, errors.New("a state sync is already in progress")
 	}
 	r.metrics.Syncing.Set(1)
-	r.syncer = newSyncer(r.cfg, r.Logger, r.conn, r.connQuery, stateProvider, r.tempDir)
+	r.syncer = newSyncer(r.cfg, r.Logger, r.conn, r.connQuery, stateProvider, r.tempDir, r.metrics)
+	r.syncer.progress = r.progress
+	r.syncer.snapshots.scores = r.scores
+	r.progress.start(r.syncer.snapshots)
 	r.mtx.Unlock()
 
 	hook := func() {
@@ -285,6 +376,7 @@ func (r *Reactor) Sync(stateProvider StateProvider, discoveryTime time.Duration)
 	hook()
 
 	state, commit, err := r.syncer.SyncAny(discoveryTime, hook)
+	r.progress.done(err)
 
 	r.mtx.Lock()
 	r.syncer = nil
@@ -292,3 +384,55 @@ func (r *Reactor) Sync(stateProvider StateProvider, discoveryTime time.Duration)
 	r.mtx.Unlock()
 	return state, commit, err
 }
+
+// SetEventBus sets the event bus to publish the progress of state syncs on.
+func (r *Reactor) SetEventBus(b *types.EventBus) {
+	r.progress.setEventBus(b)
+}
+
+// Status returns the progress of the state sync in progress, or of the last
+// one if none is.
+func (r *Reactor) Status() types.EventDataStateSyncStatus {
+	return r.progress.Status()
+}
+
+// DiscoverSnapshots requests snapshots from all connected peers and collects the responses until
+// the timeout expires or the context is canceled. It returns the discovered snapshots, deduplicated
+// and ordered from best to worst, without starting a sync.
+func (r *Reactor) DiscoverSnapshots(ctx context.Context, timeout time.Duration) ([]*snapshot, error) {
+	r.mtx.Lock()
+	if r.syncer != nil || r.discovery != nil {
+		r.mtx.Unlock()
+		return nil, errors.New("a state sync or snapshot discovery is already in progress")
+	}
+	r.discovery = newSnapshotPool()
+	r.discovery.scores = r.scores
+	r.mtx.Unlock()
+
+	r.Logger.Debug("Requesting snapshots from known peers")
+	r.Switch.Broadcast(p2p.Envelope{
+		ChannelID: SnapshotChannel,
+		Message:   &ssproto.SnapshotsRequest{},
+	})
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	var err error
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	r.mtx.Lock()
+	pool := r.discovery
+	r.discovery = nil
+	r.mtx.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	if best := pool.Best(); best != nil {
+		r.metrics.SnapshotPeers.Set(float64(len(pool.GetPeers(best))))
+	}
+	return pool.Ranked(), nil
+}
@@ -1,6 +1,7 @@
 package statesync
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -73,8 +74,9 @@ func TestReactor_Receive_ChunkRequest(t *testing.T) {
 
 			// Start a reactor and send a ssproto.ChunkRequest, then wait for and check response
 			cfg := config.DefaultStateSyncConfig()
-			r := NewReactor(*cfg, conn, nil, NopMetrics())
-			err := r.Start()
+			r, err := NewReactor(*cfg, conn, nil, NopMetrics())
+			require.NoError(t, err)
+			err = r.Start()
 			require.NoError(t, err)
 			t.Cleanup(func() {
 				if err := r.Stop(); err != nil {
@@ -96,6 +98,55 @@ func TestReactor_Receive_ChunkRequest(t *testing.T) {
 	}
 }
 
+func TestReactor_Receive_ChunkRequest_oversizedChunk(t *testing.T) {
+	request := &ssproto.ChunkRequest{Height: 1, Format: 1, Index: 1}
+
+	// Mock ABCI connection to return a chunk larger than the channel capacity
+	conn := &proxymocks.AppConnSnapshot{}
+	conn.On("LoadSnapshotChunk", mock.Anything, &abci.RequestLoadSnapshotChunk{
+		Height: request.Height,
+		Format: request.Format,
+		Chunk:  request.Index,
+	}).Return(&abci.ResponseLoadSnapshotChunk{Chunk: make([]byte, 1024)}, nil)
+
+	peer := &p2pmocks.Peer{}
+	peer.On("ID").Return(p2p.ID("id"))
+	var response *ssproto.ChunkResponse
+	peer.On("Send", mock.MatchedBy(func(i interface{}) bool {
+		e, ok := i.(p2p.Envelope)
+		return ok && e.ChannelID == ChunkChannel
+	})).Run(func(args mock.Arguments) {
+		e := args[0].(p2p.Envelope)
+		response = e.Message.(*ssproto.ChunkResponse)
+	}).Return(true)
+
+	cfg := config.DefaultStateSyncConfig()
+	r, err := NewReactor(*cfg, conn, nil, NopMetrics())
+	require.NoError(t, err)
+	r.maxChunkMsgSize = 512
+	err = r.Start()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if err := r.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	r.Receive(p2p.Envelope{
+		ChannelID: ChunkChannel,
+		Src:       peer,
+		Message:   request,
+	})
+	time.Sleep(100 * time.Millisecond)
+
+	// The chunk is not sent, but reported as missing so the peer can fetch it elsewhere.
+	assert.Equal(t, &ssproto.ChunkResponse{Height: 1, Format: 1, Index: 1, Missing: true}, response)
+	require.NoError(t, validateMsg(response))
+
+	conn.AssertExpectations(t)
+	peer.AssertExpectations(t)
+}
+
 func TestReactor_Receive_SnapshotsRequest(t *testing.T) {
 	testcases := map[string]struct {
 		snapshots       []*abci.Snapshot
@@ -163,8 +214,9 @@ func TestReactor_Receive_SnapshotsRequest(t *testing.T) {
 
 			// Start a reactor and send a SnapshotsRequestMessage, then wait for and check responses
 			cfg := config.DefaultStateSyncConfig()
-			r := NewReactor(*cfg, conn, nil, NopMetrics())
-			err := r.Start()
+			r, err := NewReactor(*cfg, conn, nil, NopMetrics())
+			require.NoError(t, err)
+			err = r.Start()
 			require.NoError(t, err)
 			t.Cleanup(func() {
 				if err := r.Stop(); err != nil {
@@ -185,3 +237,82 @@ func TestReactor_Receive_SnapshotsRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestNewReactor_InvalidTrustedSnapshotHash(t *testing.T) {
+	// The trusted snapshot hash is used to discover snapshots even if state sync is disabled.
+	cfg := config.DefaultStateSyncConfig()
+	cfg.TrustedSnapshotHeight = 3
+	cfg.TrustedSnapshotHash = "not hex"
+	_, err := NewReactor(*cfg, &proxymocks.AppConnSnapshot{}, nil, NopMetrics())
+	require.Error(t, err)
+
+	// It is ignored without a trusted snapshot height.
+	cfg.TrustedSnapshotHeight = 0
+	_, err = NewReactor(*cfg, &proxymocks.AppConnSnapshot{}, nil, NopMetrics())
+	require.NoError(t, err)
+}
+
+func TestReactor_DiscoverSnapshots(t *testing.T) {
+	cfg := config.DefaultStateSyncConfig()
+	cfg.TrustedSnapshotHeight = 3
+	cfg.TrustedSnapshotHash = "03"
+	r, err := NewReactor(*cfg, &proxymocks.AppConnSnapshot{}, nil, NopMetrics())
+	require.NoError(t, err)
+	p2p.MakeSwitch(config.DefaultP2PConfig(), 1, func(_ int, sw *p2p.Switch) *p2p.Switch {
+		sw.AddReactor("STATESYNC", r)
+		return sw
+	})
+	err = r.Start()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if err := r.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	// Snapshots received outside of a discovery are ignored.
+	r.Receive(p2p.Envelope{
+		ChannelID: SnapshotChannel,
+		Src:       simplePeer("a"),
+		Message:   &ssproto.SnapshotsResponse{Height: 9, Format: 1, Chunks: 1, Hash: []byte{9}},
+	})
+
+	advertised := map[string][]*ssproto.SnapshotsResponse{
+		"a": {
+			{Height: 1, Format: 1, Chunks: 1, Hash: []byte{1}},
+			{Height: 2, Format: 1, Chunks: 1, Hash: []byte{2}},
+		},
+		"b": {
+			{Height: 2, Format: 1, Chunks: 1, Hash: []byte{2}},
+			{Height: 3, Format: 1, Chunks: 1, Hash: []byte{3}},
+		},
+		// A snapshot at the trusted height with another hash is untrusted.
+		"c": {
+			{Height: 3, Format: 1, Chunks: 1, Hash: []byte{4}},
+		},
+	}
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		for id, responses := range advertised {
+			peer := simplePeer(id)
+			for _, resp := range responses {
+				r.Receive(p2p.Envelope{ChannelID: SnapshotChannel, Src: peer, Message: resp})
+			}
+		}
+	}()
+
+	snapshots, err := r.DiscoverSnapshots(context.Background(), 500*time.Millisecond)
+	require.NoError(t, err)
+	require.Len(t, snapshots, 3)
+	for i, height := range []uint64{3, 2, 1} {
+		assert.Equal(t, height, snapshots[i].Height)
+		assert.Equal(t, []byte{byte(height)}, snapshots[i].Hash)
+	}
+
+	// A canceled context aborts the discovery.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = r.DiscoverSnapshots(ctx, time.Second)
+	require.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,120 @@
+package statesync
+
+import (
+	"math/rand"
+	"time"
+
+	cmtsync "github.com/cometbft/cometbft/libs/sync"
+	"github.com/cometbft/cometbft/p2p"
+)
+
+const (
+	// chunkRetryBackoff is the delay before requesting a chunk again once its
+	// request timed out, doubled at each further retry up to
+	// maxChunkRetryBackoff.
+	chunkRetryBackoff    = 500 * time.Millisecond
+	maxChunkRetryBackoff = 30 * time.Second
+
+	// busyPeersInterval is the delay before looking again for a peer to request
+	// a chunk from, when all the peers of the snapshot have as many outstanding
+	// requests as allowed.
+	busyPeersInterval = 100 * time.Millisecond
+)
+
+// chunkScheduler spreads the chunk requests of the fetchers of a snapshot
+// across its peers, limiting the requests outstanding at each peer, and the
+// total number of requests retried once they timed out.
+type chunkScheduler struct {
+	mtx         cmtsync.Mutex
+	maxPerPeer  int
+	outstanding map[p2p.ID]int // requests by peer
+	retryBudget int            // retries left, negative if unlimited
+}
+
+// newChunkScheduler creates a chunk scheduler allowing maxPerPeer requests
+// outstanding at each peer, and retryBudget retries in total, or any number
+// of retries if 0.
+func newChunkScheduler(maxPerPeer, retryBudget int) *chunkScheduler {
+	if retryBudget == 0 {
+		retryBudget = -1
+	}
+	return &chunkScheduler{
+		maxPerPeer:  maxPerPeer,
+		outstanding: make(map[p2p.ID]int),
+		retryBudget: retryBudget,
+	}
+}
+
+// acquire picks the peer to request a chunk from, among peers, and counts the
+// request as outstanding until release is called. It picks the peer with the
+// fewest outstanding requests, preferring the others to avoid, randomly among
+// equal peers, or returns nil if all peers have as many outstanding requests
+// as allowed.
+func (s *chunkScheduler) acquire(peers []p2p.Peer, avoid p2p.ID) p2p.Peer {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	var best p2p.Peer
+	for _, i := range rand.Perm(len(peers)) { //nolint:gosec // G404: Use of weak random number generator
+		peer := peers[i]
+		if s.outstanding[peer.ID()] >= s.maxPerPeer {
+			continue
+		}
+		if best == nil || s.better(peer, best, avoid) {
+			best = peer
+		}
+	}
+	if best != nil {
+		s.outstanding[best.ID()]++
+	}
+	return best
+}
+
+// better returns true if a chunk should rather be requested from peer a than
+// from peer b. The caller must hold the mutex lock.
+func (s *chunkScheduler) better(a, b p2p.Peer, avoid p2p.ID) bool {
+	if avoidA, avoidB := a.ID() == avoid, b.ID() == avoid; avoidA != avoidB {
+		return avoidB
+	}
+	return s.outstanding[a.ID()] < s.outstanding[b.ID()]
+}
+
+// release counts a request to the peer with the given ID as completed, either
+// answered or timed out.
+func (s *chunkScheduler) release(id p2p.ID) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.outstanding[id] <= 1 {
+		delete(s.outstanding, id)
+		return
+	}
+	s.outstanding[id]--
+}
+
+// retry uses a retry of the budget, returning false if it is exhausted.
+func (s *chunkScheduler) retry() bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	switch {
+	case s.retryBudget < 0:
+		return true
+	case s.retryBudget == 0:
+		return false
+	default:
+		s.retryBudget--
+		return true
+	}
+}
+
+// retryBackoff returns the delay before the given retry of a chunk request,
+// starting at 1.
+func retryBackoff(retry int) time.Duration {
+	backoff := chunkRetryBackoff
+	for i := 1; i < retry && backoff < maxChunkRetryBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxChunkRetryBackoff {
+		backoff = maxChunkRetryBackoff
+	}
+	return backoff
+}
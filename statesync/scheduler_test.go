@@ -0,0 +1,52 @@
+package statesync
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cometbft/cometbft/p2p"
+)
+
+func TestChunkScheduler_acquire(t *testing.T) {
+	peerA, peerB := simplePeer("a"), simplePeer("b")
+	peers := []p2p.Peer{peerA, peerB}
+	s := newChunkScheduler(2, 0)
+
+	// Requests are spread across peers, up to 2 per peer.
+	first, second := s.acquire(peers, ""), s.acquire(peers, "")
+	assert.ElementsMatch(t, []p2p.Peer{peerA, peerB}, []p2p.Peer{first, second})
+	s.acquire(peers, "")
+	s.acquire(peers, "")
+	assert.Nil(t, s.acquire(peers, ""))
+	assert.Equal(t, map[p2p.ID]int{"a": 2, "b": 2}, s.outstanding)
+
+	s.release("b")
+	assert.Equal(t, peerB, s.acquire(peers, "a"))
+	s.release("a")
+	s.release("a")
+	s.release("b")
+
+	// Other peers are preferred to the one to avoid, even if busier.
+	assert.Equal(t, peerA, s.acquire(peers, "b"))
+	// Unless it is the only one available.
+	assert.Equal(t, peerB, s.acquire([]p2p.Peer{peerB}, "b"))
+}
+
+func TestChunkScheduler_retry(t *testing.T) {
+	s := newChunkScheduler(1, 2)
+	assert.True(t, s.retry())
+	assert.True(t, s.retry())
+	assert.False(t, s.retry())
+
+	s = newChunkScheduler(1, 0)
+	for i := 0; i < 100; i++ {
+		assert.True(t, s.retry())
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	assert.Equal(t, chunkRetryBackoff, retryBackoff(1))
+	assert.Equal(t, 4*chunkRetryBackoff, retryBackoff(3))
+	assert.Equal(t, maxChunkRetryBackoff, retryBackoff(1000))
+}
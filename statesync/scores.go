@@ -0,0 +1,139 @@
+package statesync
+
+import (
+	"time"
+
+	cmtsync "github.com/cometbft/cometbft/libs/sync"
+	"github.com/cometbft/cometbft/p2p"
+)
+
+const (
+	// rejectionTTL is how long the failures and rejections of peers, formats and snapshots are
+	// remembered across state syncs and snapshot discoveries.
+	rejectionTTL = time.Hour
+
+	// maxSnapshotFailures is the number of snapshots advertised by a peer which may fail within
+	// rejectionTTL before the peer is rejected. Peers with fewer recent failures are only
+	// de-prioritized.
+	maxSnapshotFailures = 3
+)
+
+// peerScores keeps track of the snapshots advertised by each peer which recently failed, of the
+// peers rejected for failing too often or for provable faults, such as sending chunks not
+// matching the hashes they advertised, of the snapshot formats rejected for each peer, and of the
+// rejected snapshots. It is shared by the snapshot pools of successive state syncs and snapshot
+// discoveries, so that rejections are not retried right away, and its failures and rejections
+// expire after rejectionTTL. A single failed snapshot is not enough to reject the peers which
+// advertised it, as they may have done so in good faith.
+type peerScores struct {
+	mtx               cmtsync.Mutex
+	failures          map[p2p.ID][]time.Time // times of the recent failures
+	rejectedPeers     map[p2p.ID]time.Time   // expiry of the rejection
+	rejectedFormats   map[p2p.ID]map[uint32]time.Time
+	rejectedSnapshots map[snapshotKey]time.Time
+}
+
+// newPeerScores creates new peer scores, with no rejections.
+func newPeerScores() *peerScores {
+	return &peerScores{
+		failures:          make(map[p2p.ID][]time.Time),
+		rejectedPeers:     make(map[p2p.ID]time.Time),
+		rejectedFormats:   make(map[p2p.ID]map[uint32]time.Time),
+		rejectedSnapshots: make(map[snapshotKey]time.Time),
+	}
+}
+
+// allowed returns false if the snapshot should be ignored when advertised by the peer, because
+// the peer, the snapshot, or its format for this peer, were rejected.
+func (s *peerScores) allowed(peerID p2p.ID, snapshot *snapshot) bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	now := time.Now()
+	s.expire(now)
+	return s.rejectedPeers[peerID].IsZero() &&
+		s.rejectedFormats[peerID][snapshot.Format].IsZero() &&
+		s.rejectedSnapshots[snapshot.Key()].IsZero()
+}
+
+// expire forgets the failures and rejections which expired at now. The caller must hold the
+// mutex lock.
+func (s *peerScores) expire(now time.Time) {
+	for peerID, failures := range s.failures {
+		for len(failures) > 0 && !now.Before(failures[0].Add(rejectionTTL)) {
+			failures = failures[1:]
+		}
+		if len(failures) == 0 {
+			delete(s.failures, peerID)
+		} else {
+			s.failures[peerID] = failures
+		}
+	}
+	for peerID, expiry := range s.rejectedPeers {
+		if !now.Before(expiry) {
+			delete(s.rejectedPeers, peerID)
+		}
+	}
+	for peerID, formats := range s.rejectedFormats {
+		for format, expiry := range formats {
+			if !now.Before(expiry) {
+				delete(formats, format)
+			}
+		}
+		if len(formats) == 0 {
+			delete(s.rejectedFormats, peerID)
+		}
+	}
+	for key, expiry := range s.rejectedSnapshots {
+		if !now.Before(expiry) {
+			delete(s.rejectedSnapshots, key)
+		}
+	}
+}
+
+// fail records that a snapshot advertised by the peer failed. It returns true if the peer has now
+// failed too many times, and is rejected.
+func (s *peerScores) fail(peerID p2p.ID) bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	now := time.Now()
+	s.expire(now)
+	s.failures[peerID] = append(s.failures[peerID], now)
+	if len(s.failures[peerID]) < maxSnapshotFailures {
+		return false
+	}
+	delete(s.failures, peerID)
+	s.rejectedPeers[peerID] = now.Add(rejectionTTL)
+	return true
+}
+
+// failed returns true if a snapshot advertised by the peer failed recently.
+func (s *peerScores) failed(peerID p2p.ID) bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.expire(time.Now())
+	return len(s.failures[peerID]) > 0
+}
+
+// rejectSnapshot rejects the snapshot, whichever peer advertises it.
+func (s *peerScores) rejectSnapshot(snapshot *snapshot) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.rejectedSnapshots[snapshot.Key()] = time.Now().Add(rejectionTTL)
+}
+
+// rejectFormat rejects the snapshot format for the peer.
+func (s *peerScores) rejectFormat(peerID p2p.ID, format uint32) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.rejectedFormats[peerID] == nil {
+		s.rejectedFormats[peerID] = make(map[uint32]time.Time)
+	}
+	s.rejectedFormats[peerID][format] = time.Now().Add(rejectionTTL)
+}
+
+// rejectPeer rejects the peer.
+func (s *peerScores) rejectPeer(peerID p2p.ID) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.rejectedPeers[peerID] = time.Now().Add(rejectionTTL)
+}
@@ -1,6 +1,7 @@
 package statesync
 
 import (
+	"bytes"
 	"crypto/sha256"
 	"fmt"
 	"math/rand"
@@ -21,6 +22,10 @@ type snapshot struct {
 	Hash     []byte
 	Metadata []byte
 
+	// ChunkHashes are the SHA-256 hashes of the chunks, by index, if advertised along the
+	// snapshot, to verify each chunk before it is applied.
+	ChunkHashes [][]byte
+
 	trustedAppHash    []byte // populated by light client
 	trustedAppVersion uint64 // populated by light client
 }
@@ -28,6 +33,8 @@ type snapshot struct {
 // Key generates a snapshot key, used for lookups. It takes into account not only the height and
 // format, but also the chunks, hash, and metadata in case peers have generated snapshots in a
 // non-deterministic manner. All fields must be equal for the snapshot to be considered the same.
+// The chunk hashes are left out, as they are only advertised by some peers (see
+// snapshotPool.Add).
 func (s *snapshot) Key() snapshotKey {
 	// Hash.Write() never returns an error.
 	hasher := sha256.New()
@@ -39,6 +46,35 @@ func (s *snapshot) Key() snapshotKey {
 	return key
 }
 
+// verifyChunk checks the chunk with the given index against its hash, if the snapshot has chunk
+// hashes.
+func (s *snapshot) verifyChunk(index uint32, chunk []byte) error {
+	if len(s.ChunkHashes) == 0 {
+		return nil
+	}
+	if int(index) >= len(s.ChunkHashes) {
+		return fmt.Errorf("%w: no hash for chunk %v", errInvalidChunkHash, index)
+	}
+	if hash := sha256.Sum256(chunk); !bytes.Equal(hash[:], s.ChunkHashes[index]) {
+		return fmt.Errorf("%w: chunk %v has hash %X, expected %X", errInvalidChunkHash,
+			index, hash, s.ChunkHashes[index])
+	}
+	return nil
+}
+
+// equalChunkHashes returns true if a and b are the same chunk hashes.
+func equalChunkHashes(a, b [][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !bytes.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
 // snapshotPool discovers and aggregates snapshots across peers.
 type snapshotPool struct {
 	cmtsync.Mutex
@@ -54,6 +90,13 @@ type snapshotPool struct {
 	formatBlacklist   map[uint32]bool
 	peerBlacklist     map[p2p.ID]bool
 	snapshotBlacklist map[snapshotKey]bool
+
+	// chunk hashes advertised by each peer of a snapshot
+	peerChunkHashes map[snapshotKey]map[p2p.ID][][]byte
+
+	// scores keeps the failures and rejections of peers, formats and snapshots, possibly across
+	// pools.
+	scores *peerScores
 }
 
 // newSnapshotPool creates a new snapshot pool. The state source is used for
@@ -67,6 +110,8 @@ func newSnapshotPool() *snapshotPool {
 		formatBlacklist:   make(map[uint32]bool),
 		peerBlacklist:     make(map[p2p.ID]bool),
 		snapshotBlacklist: make(map[snapshotKey]bool),
+		peerChunkHashes:   make(map[snapshotKey]map[p2p.ID][][]byte),
+		scores:            newPeerScores(),
 	}
 }
 
@@ -86,6 +131,8 @@ func (p *snapshotPool) Add(peer p2p.Peer, snapshot *snapshot) (bool, error) {
 		return false, nil
 	case p.snapshotBlacklist[key]:
 		return false, nil
+	case !p.scores.allowed(peer.ID(), snapshot):
+		return false, nil
 	case len(p.peerIndex[peer.ID()]) >= recentSnapshots:
 		return false, nil
 	}
@@ -100,7 +147,15 @@ func (p *snapshotPool) Add(peer p2p.Peer, snapshot *snapshot) (bool, error) {
 	}
 	p.peerIndex[peer.ID()][key] = true
 
+	if len(snapshot.ChunkHashes) > 0 {
+		if p.peerChunkHashes[key] == nil {
+			p.peerChunkHashes[key] = make(map[p2p.ID][][]byte)
+		}
+		p.peerChunkHashes[key][peer.ID()] = snapshot.ChunkHashes
+	}
+
 	if p.snapshots[key] != nil {
+		p.resolveChunkHashes(key)
 		return false, nil
 	}
 	p.snapshots[key] = snapshot
@@ -118,6 +173,98 @@ func (p *snapshotPool) Add(peer p2p.Peer, snapshot *snapshot) (bool, error) {
 	return true, nil
 }
 
+// resolveChunkHashes sets the chunk hashes of the snapshot with the given key to those advertised
+// by the most peers, keeping the current ones on a tie, so that the chunks stay verified even if
+// peers disagree on them. Once more than half of the peers advertising chunk hashes agree on them,
+// the peers advertising other ones are provably at fault, and are rejected. The snapshot is
+// replaced rather than modified, as it may be being restored. The caller must hold the mutex
+// lock.
+func (p *snapshotPool) resolveChunkHashes(key snapshotKey) {
+	existing, advertised := p.snapshots[key], p.peerChunkHashes[key]
+	if existing == nil || len(advertised) == 0 {
+		return
+	}
+	peerIDs := make([]p2p.ID, 0, len(advertised))
+	for peerID := range advertised {
+		peerIDs = append(peerIDs, peerID)
+	}
+	sort.Slice(peerIDs, func(i, j int) bool { return peerIDs[i] < peerIDs[j] })
+	count := func(hashes [][]byte) int {
+		n := 0
+		for _, peerID := range peerIDs {
+			if equalChunkHashes(advertised[peerID], hashes) {
+				n++
+			}
+		}
+		return n
+	}
+
+	best, bestCount := existing.ChunkHashes, 0
+	if len(best) > 0 {
+		bestCount = count(best)
+	}
+	for _, peerID := range peerIDs {
+		if n := count(advertised[peerID]); n > bestCount {
+			best, bestCount = advertised[peerID], n
+		}
+	}
+	if !equalChunkHashes(best, existing.ChunkHashes) {
+		resolved := *existing
+		resolved.ChunkHashes = best
+		p.snapshots[key] = &resolved
+	}
+	if bestCount > len(peerIDs)/2 {
+		for _, peerID := range peerIDs {
+			if !equalChunkHashes(advertised[peerID], best) {
+				p.rejectPeer(peerID)
+			}
+		}
+	}
+}
+
+// AdvertisedChunkHashes returns true if the peer advertised the snapshot with its chunk hashes,
+// in which case the peer is at fault if it sends a chunk not matching them.
+func (p *snapshotPool) AdvertisedChunkHashes(peerID p2p.ID, snapshot *snapshot) bool {
+	p.Lock()
+	defer p.Unlock()
+	hashes := p.peerChunkHashes[snapshot.Key()][peerID]
+	return len(hashes) > 0 && equalChunkHashes(hashes, snapshot.ChunkHashes)
+}
+
+// ChunkHashes returns the chunk hashes the chunks of the snapshot are verified against, as
+// resolved from those advertised by its peers.
+func (p *snapshotPool) ChunkHashes(snapshot *snapshot) [][]byte {
+	p.Lock()
+	defer p.Unlock()
+	if s := p.snapshots[snapshot.Key()]; s != nil {
+		return s.ChunkHashes
+	}
+	return nil
+}
+
+// GetChunkHashPeers returns the peers which advertised the snapshot with the chunk hashes its
+// chunks are verified against.
+func (p *snapshotPool) GetChunkHashPeers(snapshot *snapshot) []p2p.Peer {
+	key := snapshot.Key()
+	p.Lock()
+	defer p.Unlock()
+
+	s := p.snapshots[key]
+	if s == nil || len(s.ChunkHashes) == 0 {
+		return nil
+	}
+	peers := make([]p2p.Peer, 0, len(p.peerChunkHashes[key]))
+	for peerID, hashes := range p.peerChunkHashes[key] {
+		if peer := p.snapshotPeers[key][peerID]; peer != nil && equalChunkHashes(hashes, s.ChunkHashes) {
+			peers = append(peers, peer)
+		}
+	}
+	sort.Slice(peers, func(a int, b int) bool {
+		return peers[a].ID() < peers[b].ID()
+	})
+	return peers
+}
+
 // Best returns the "best" currently known snapshot, if any.
 func (p *snapshotPool) Best() *snapshot {
 	ranked := p.Ranked()
@@ -154,15 +301,24 @@ func (p *snapshotPool) GetPeers(snapshot *snapshot) []p2p.Peer {
 }
 
 // Ranked returns a list of snapshots ranked by preference. The current heuristic is very naïve,
-// preferring the snapshot with the greatest height, then greatest format, then greatest number of
-// peers. This can be improved quite a lot.
+// preferring the snapshots advertised by a peer without recent failures, then the snapshot with
+// the greatest height, then greatest format, then greatest number of peers. This can be improved
+// quite a lot.
 func (p *snapshotPool) Ranked() []*snapshot {
 	p.Lock()
 	defer p.Unlock()
 
 	candidates := make([]*snapshot, 0, len(p.snapshots))
+	failed := make(map[snapshotKey]bool, len(p.snapshots))
 	for key := range p.snapshots {
 		candidates = append(candidates, p.snapshots[key])
+		failed[key] = true
+		for peerID := range p.snapshotPeers[key] {
+			if !p.scores.failed(peerID) {
+				failed[key] = false
+				break
+			}
+		}
 	}
 
 	sort.Slice(candidates, func(i, j int) bool {
@@ -170,6 +326,8 @@ func (p *snapshotPool) Ranked() []*snapshot {
 		b := candidates[j]
 
 		switch {
+		case failed[a.Key()] != failed[b.Key()]:
+			return !failed[a.Key()]
 		case a.Height > b.Height:
 			return true
 		case a.Height < b.Height:
@@ -188,14 +346,29 @@ func (p *snapshotPool) Ranked() []*snapshot {
 	return candidates
 }
 
-// Reject rejects a snapshot. Rejected snapshots will never be used again.
-func (p *snapshotPool) Reject(snapshot *snapshot) {
+// Reject rejects a snapshot. Rejected snapshots will never be used again. It counts as a failure
+// of each peer which advertised it, whose snapshots are then de-prioritized, and which is
+// rejected once it failed too many times. It returns the peers rejected for having failed too
+// many times.
+func (p *snapshotPool) Reject(snapshot *snapshot) []p2p.ID {
 	key := snapshot.Key()
 	p.Lock()
 	defer p.Unlock()
 
+	var rejected []p2p.ID
+	for peerID := range p.snapshotPeers[key] {
+		if p.scores.fail(peerID) {
+			rejected = append(rejected, peerID)
+		}
+	}
+	sort.Slice(rejected, func(i, j int) bool { return rejected[i] < rejected[j] })
+	for _, peerID := range rejected {
+		p.rejectPeer(peerID)
+	}
 	p.snapshotBlacklist[key] = true
+	p.scores.rejectSnapshot(snapshot)
 	p.removeSnapshot(key)
+	return rejected
 }
 
 // RejectFormat rejects a snapshot format. It will never be used again.
@@ -205,6 +378,9 @@ func (p *snapshotPool) RejectFormat(format uint32) {
 
 	p.formatBlacklist[format] = true
 	for key := range p.formatIndex[format] {
+		for peerID := range p.snapshotPeers[key] {
+			p.scores.rejectFormat(peerID, format)
+		}
 		p.removeSnapshot(key)
 	}
 }
@@ -216,9 +392,14 @@ func (p *snapshotPool) RejectPeer(peerID p2p.ID) {
 	}
 	p.Lock()
 	defer p.Unlock()
+	p.rejectPeer(peerID)
+}
 
+// rejectPeer rejects a peer. The caller must hold the mutex lock.
+func (p *snapshotPool) rejectPeer(peerID p2p.ID) {
 	p.removePeer(peerID)
 	p.peerBlacklist[peerID] = true
+	p.scores.rejectPeer(peerID)
 }
 
 // RemovePeer removes a peer from the pool, and any snapshots that no longer have peers.
@@ -232,6 +413,7 @@ func (p *snapshotPool) RemovePeer(peerID p2p.ID) {
 func (p *snapshotPool) removePeer(peerID p2p.ID) {
 	for key := range p.peerIndex[peerID] {
 		delete(p.snapshotPeers[key], peerID)
+		delete(p.peerChunkHashes[key], peerID)
 		if len(p.snapshotPeers[key]) == 0 {
 			p.removeSnapshot(key)
 		}
@@ -253,4 +435,5 @@ func (p *snapshotPool) removeSnapshot(key snapshotKey) {
 		delete(p.peerIndex[peerID], key)
 	}
 	delete(p.snapshotPeers, key)
+	delete(p.peerChunkHashes, key)
 }
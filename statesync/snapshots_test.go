@@ -1,7 +1,9 @@
 package statesync
 
 import (
+	"crypto/sha256"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -38,6 +40,24 @@ func TestSnapshot_Key(t *testing.T) {
 	}
 }
 
+func TestSnapshot_Key_chunkHashes(t *testing.T) {
+	s := snapshot{Height: 3, Format: 1, Chunks: 1, Hash: []byte{1, 2, 3}}
+	before := s.Key()
+	s.ChunkHashes = [][]byte{{9}}
+	assert.Equal(t, before, s.Key())
+}
+
+func TestSnapshot_verifyChunk(t *testing.T) {
+	hash := sha256.Sum256([]byte{3, 1, 0})
+	s := &snapshot{Height: 3, Format: 1, Chunks: 2}
+	require.NoError(t, s.verifyChunk(0, []byte{3, 1, 9}))
+
+	s.ChunkHashes = [][]byte{hash[:], hash[:]}
+	require.NoError(t, s.verifyChunk(0, []byte{3, 1, 0}))
+	require.ErrorIs(t, s.verifyChunk(0, []byte{3, 1, 9}), errInvalidChunkHash)
+	require.ErrorIs(t, s.verifyChunk(2, []byte{3, 1, 0}), errInvalidChunkHash)
+}
+
 func TestSnapshotPool_Add(t *testing.T) {
 	peer := &p2pmocks.Peer{}
 	peer.On("ID").Return(p2p.ID("id"))
@@ -70,6 +90,52 @@ func TestSnapshotPool_Add(t *testing.T) {
 	require.NotNil(t, snapshot)
 }
 
+func TestSnapshotPool_Add_chunkHashes(t *testing.T) {
+	hash := sha256.Sum256([]byte{1})
+	bogus := sha256.Sum256([]byte{2})
+	s := &snapshot{Height: 1, Format: 1, Chunks: 1, Hash: []byte{1}}
+	withHashes := func(hashes ...[]byte) *snapshot {
+		return &snapshot{Height: 1, Format: 1, Chunks: 1, Hash: []byte{1}, ChunkHashes: hashes}
+	}
+
+	pool := newSnapshotPool()
+
+	// The chunk hashes are picked up from the first peer advertising them.
+	_, err := pool.Add(simplePeer("a"), s)
+	require.NoError(t, err)
+	added, err := pool.Add(simplePeer("b"), withHashes(hash[:]))
+	require.NoError(t, err)
+	assert.False(t, added)
+	best := pool.Best()
+	assert.Equal(t, [][]byte{hash[:]}, best.ChunkHashes)
+	assert.True(t, pool.AdvertisedChunkHashes("b", best))
+	assert.False(t, pool.AdvertisedChunkHashes("a", best))
+
+	// Conflicting chunk hashes advertised by as many peers don't replace them, nor reject anyone.
+	_, err = pool.Add(simplePeer("c"), withHashes(bogus[:]))
+	require.NoError(t, err)
+	best = pool.Best()
+	assert.Equal(t, [][]byte{hash[:]}, best.ChunkHashes)
+	assert.Len(t, pool.GetPeers(best), 3)
+	assert.True(t, pool.AdvertisedChunkHashes("b", best))
+	assert.False(t, pool.AdvertisedChunkHashes("c", best))
+	chunkHashPeers := pool.GetChunkHashPeers(best)
+	require.Len(t, chunkHashPeers, 1)
+	assert.EqualValues(t, "b", chunkHashPeers[0].ID())
+
+	// Once most advertisers agree on the chunk hashes, those advertising others are rejected.
+	_, err = pool.Add(simplePeer("d"), withHashes(hash[:]))
+	require.NoError(t, err)
+	best = pool.Best()
+	assert.Equal(t, [][]byte{hash[:]}, best.ChunkHashes)
+	peerIDs := []p2p.ID{}
+	for _, peer := range pool.GetPeers(best) {
+		peerIDs = append(peerIDs, peer.ID())
+	}
+	assert.Equal(t, []p2p.ID{"a", "b", "d"}, peerIDs)
+	assert.Len(t, pool.GetChunkHashPeers(best), 2)
+}
+
 func TestSnapshotPool_GetPeer(t *testing.T) {
 	pool := newSnapshotPool()
 
@@ -197,6 +263,94 @@ func TestSnapshotPool_Reject(t *testing.T) {
 	assert.True(t, added)
 }
 
+func TestSnapshotPool_Reject_failures(t *testing.T) {
+	pool := newSnapshotPool()
+	peerA := simplePeer("a")
+	peerB := simplePeer("b")
+
+	// A failed snapshot de-prioritizes the other snapshots of the peers which advertised it.
+	s := &snapshot{Height: 1, Format: 1, Chunks: 1, Hash: []byte{1}}
+	_, err := pool.Add(peerA, s)
+	require.NoError(t, err)
+	assert.Empty(t, pool.Reject(s))
+
+	high := &snapshot{Height: 5, Format: 1, Chunks: 1, Hash: []byte{1}}
+	_, err = pool.Add(peerA, high)
+	require.NoError(t, err)
+	low := &snapshot{Height: 2, Format: 1, Chunks: 1, Hash: []byte{1}}
+	_, err = pool.Add(peerB, low)
+	require.NoError(t, err)
+	assert.Equal(t, []*snapshot{low, high}, pool.Ranked())
+
+	// The peer is rejected once it failed too many times.
+	for h := uint64(2); h < maxSnapshotFailures; h++ {
+		s = &snapshot{Height: 10 + h, Format: 1, Chunks: 1, Hash: []byte{1}}
+		_, err = pool.Add(peerA, s)
+		require.NoError(t, err)
+		assert.Empty(t, pool.Reject(s))
+	}
+	s = &snapshot{Height: 20, Format: 1, Chunks: 1, Hash: []byte{1}}
+	_, err = pool.Add(peerA, s)
+	require.NoError(t, err)
+	_, err = pool.Add(peerB, s)
+	require.NoError(t, err)
+	assert.Equal(t, []p2p.ID{peerA.ID()}, pool.Reject(s))
+	assert.Equal(t, []*snapshot{low}, pool.Ranked())
+
+	added, err := pool.Add(peerA, &snapshot{Height: 21, Format: 1, Chunks: 1, Hash: []byte{1}})
+	require.NoError(t, err)
+	assert.False(t, added)
+
+	// Failures are kept across pools sharing the scores, until they expire.
+	next := newSnapshotPool()
+	next.scores = pool.scores
+	s = &snapshot{Height: 22, Format: 1, Chunks: 1, Hash: []byte{1}}
+	_, err = next.Add(peerB, s)
+	require.NoError(t, err)
+	_, err = next.Add(simplePeer("c"), low)
+	require.NoError(t, err)
+	assert.Equal(t, []*snapshot{low, s}, next.Ranked())
+
+	for peerID, failures := range pool.scores.failures {
+		for i := range failures {
+			pool.scores.failures[peerID][i] = time.Now().Add(-rejectionTTL)
+		}
+	}
+	assert.Equal(t, []*snapshot{s, low}, next.Ranked())
+	assert.Empty(t, pool.scores.failures)
+}
+
+func TestSnapshotPool_Reject_acrossPools(t *testing.T) {
+	pool := newSnapshotPool()
+	peerA := simplePeer("a")
+	peerB := simplePeer("b")
+
+	// A single rejected snapshot doesn't reject the peers which advertised it.
+	s := &snapshot{Height: 1, Format: 1, Chunks: 1, Hash: []byte{1}}
+	_, err := pool.Add(peerA, s)
+	require.NoError(t, err)
+	pool.Reject(s)
+	added, err := pool.Add(peerA, &snapshot{Height: 1, Format: 1, Chunks: 1, Hash: []byte{2}})
+	require.NoError(t, err)
+	assert.True(t, added)
+
+	// Rejected snapshots stay rejected in pools sharing the scores, whichever peer advertises them.
+	next := newSnapshotPool()
+	next.scores = pool.scores
+	added, err = next.Add(peerB, s)
+	require.NoError(t, err)
+	assert.False(t, added)
+
+	// Until the rejection expires.
+	for key := range pool.scores.rejectedSnapshots {
+		pool.scores.rejectedSnapshots[key] = time.Now()
+	}
+	added, err = next.Add(peerB, s)
+	require.NoError(t, err)
+	assert.True(t, added)
+	assert.Empty(t, pool.scores.rejectedSnapshots)
+}
+
 func TestSnapshotPool_RejectFormat(t *testing.T) {
 	pool := newSnapshotPool()
 	peer := &p2pmocks.Peer{}
@@ -224,6 +378,16 @@ func TestSnapshotPool_RejectFormat(t *testing.T) {
 	added, err = pool.Add(peer, &snapshot{Height: 3, Format: 3, Chunks: 1, Hash: []byte{1}})
 	require.NoError(t, err)
 	assert.True(t, added)
+
+	// The format stays rejected for the peer in pools sharing the scores.
+	next := newSnapshotPool()
+	next.scores = pool.scores
+	added, err = next.Add(peer, &snapshot{Height: 4, Format: 1, Chunks: 1, Hash: []byte{1}})
+	require.NoError(t, err)
+	assert.False(t, added)
+	added, err = next.Add(simplePeer("other"), &snapshot{Height: 4, Format: 1, Chunks: 1, Hash: []byte{1}})
+	require.NoError(t, err)
+	assert.True(t, added)
 }
 
 func TestSnapshotPool_RejectPeer(t *testing.T) {
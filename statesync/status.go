@@ -0,0 +1,191 @@
+package statesync
+
+import (
+	"time"
+
+	cmtsync "github.com/cometbft/cometbft/libs/sync"
+	"github.com/cometbft/cometbft/types"
+)
+
+// progressPublishInterval is the minimum interval between two publishes of
+// the progress of a state sync as chunks are fetched and snapshots discovered.
+// The start and end of the state sync and of the restore of a snapshot are
+// always published.
+const progressPublishInterval = time.Second
+
+// syncProgress tracks the progress of a state sync, reporting it in the
+// StateSyncStatus events published on the event bus, if any.
+type syncProgress struct {
+	mtx             cmtsync.Mutex
+	status          types.EventDataStateSyncStatus
+	started         time.Time // when the restore of the current snapshot started
+	eventBus        *types.EventBus
+	pool            *snapshotPool // discovered snapshots, while syncing
+	publishInterval time.Duration
+	published       time.Time // when the progress was last published
+	seq             uint64    // incremented on each publish of the progress
+
+	// publishes are serialized, and skipped if a more recent progress was
+	// published in the meantime
+	publishMtx   cmtsync.Mutex
+	publishedSeq uint64
+}
+
+// newSyncProgress creates the progress of a state sync, which is not started.
+func newSyncProgress() *syncProgress {
+	return &syncProgress{publishInterval: progressPublishInterval}
+}
+
+// Status returns the current progress.
+func (p *syncProgress) Status() types.EventDataStateSyncStatus {
+	p.mtx.Lock()
+	status, pool := p.current()
+	p.mtx.Unlock()
+	return withSnapshots(status, pool)
+}
+
+// current returns a copy of the current progress, and the pool of snapshots
+// if still syncing. The caller must hold the mutex lock.
+func (p *syncProgress) current() (types.EventDataStateSyncStatus, *snapshotPool) {
+	status := p.status
+	if status.Snapshot != nil {
+		snapshot := *status.Snapshot
+		status.Snapshot = &snapshot
+	}
+	return status, p.pool
+}
+
+// withSnapshots returns status with the snapshots of pool, if any. It must be
+// called without holding the mutex lock, to not hold it while locking the
+// pool.
+func withSnapshots(status types.EventDataStateSyncStatus, pool *snapshotPool) types.EventDataStateSyncStatus {
+	if pool != nil {
+		status.Snapshots = stateSyncSnapshots(pool)
+	}
+	return status
+}
+
+// publishLocked publishes the current progress on the event bus, if any, and
+// unless force is false and the progress was published less than
+// publishInterval ago. The caller must hold the mutex lock, which is released
+// before publishing.
+func (p *syncProgress) publishLocked(force bool) {
+	now := time.Now()
+	if p.eventBus == nil || (!force && now.Sub(p.published) < p.publishInterval) {
+		p.mtx.Unlock()
+		return
+	}
+	p.published = now
+	p.seq++
+	seq, bus := p.seq, p.eventBus
+	status, pool := p.current()
+	p.mtx.Unlock()
+
+	p.publishMtx.Lock()
+	defer p.publishMtx.Unlock()
+	if seq <= p.publishedSeq {
+		return
+	}
+	p.publishedSeq = seq
+	_ = bus.PublishEventStateSyncStatus(withSnapshots(status, pool))
+}
+
+// setEventBus sets the event bus to publish the progress on.
+func (p *syncProgress) setEventBus(b *types.EventBus) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.eventBus = b
+}
+
+// start resets the progress for a new state sync, discovering snapshots into
+// the given pool.
+func (p *syncProgress) start(pool *snapshotPool) {
+	p.mtx.Lock()
+	p.status = types.EventDataStateSyncStatus{Syncing: true}
+	p.pool = pool
+	p.started = time.Time{}
+	p.publishLocked(true)
+}
+
+// snapshotDiscovered reports a snapshot added to the pool.
+func (p *syncProgress) snapshotDiscovered() {
+	p.mtx.Lock()
+	p.publishLocked(false)
+}
+
+// restoring reports the start of the restore of a snapshot.
+func (p *syncProgress) restoring(s *snapshot, peers int) {
+	p.mtx.Lock()
+	p.status.Snapshot = &types.StateSyncSnapshot{
+		Height: s.Height,
+		Format: s.Format,
+		Chunks: s.Chunks,
+		Hash:   s.Hash,
+		Peers:  peers,
+	}
+	p.status.ChunksFetched = 0
+	p.status.ChunksTotal = s.Chunks
+	p.status.BytesFetched = 0
+	p.status.Throughput = 0
+	p.status.ETA = 0
+	p.started = time.Now()
+	p.publishLocked(true)
+}
+
+// chunkFetched reports a chunk of the given size added to the chunk queue of
+// the snapshot being restored, updating the throughput and ETA.
+func (p *syncProgress) chunkFetched(size int) {
+	p.mtx.Lock()
+	if p.status.Snapshot == nil {
+		p.mtx.Unlock()
+		return
+	}
+	if p.status.ChunksFetched < p.status.ChunksTotal {
+		p.status.ChunksFetched++
+	}
+	p.status.BytesFetched += int64(size)
+	if elapsed := time.Since(p.started); elapsed > 0 && p.status.ChunksFetched > 0 {
+		p.status.Throughput = float64(p.status.BytesFetched) / elapsed.Seconds()
+		remaining := p.status.ChunksTotal - p.status.ChunksFetched
+		p.status.ETA = elapsed / time.Duration(p.status.ChunksFetched) * time.Duration(remaining)
+	}
+	p.publishLocked(false)
+}
+
+// done reports the end of the state sync, failed if err is not nil.
+func (p *syncProgress) done(err error) {
+	// The snapshots of the pool are kept, ranked without holding the lock.
+	p.mtx.Lock()
+	pool := p.pool
+	p.mtx.Unlock()
+	snapshots := stateSyncSnapshots(pool)
+
+	p.mtx.Lock()
+	p.status.Snapshots = snapshots
+	p.pool = nil
+	p.status.Syncing = false
+	p.status.ETA = 0
+	if err != nil {
+		p.status.Error = err.Error()
+	}
+	p.publishLocked(true)
+}
+
+// stateSyncSnapshots returns the snapshots of the pool, best first.
+func stateSyncSnapshots(pool *snapshotPool) []types.StateSyncSnapshot {
+	if pool == nil {
+		return nil
+	}
+	ranked := pool.Ranked()
+	snapshots := make([]types.StateSyncSnapshot, 0, len(ranked))
+	for _, s := range ranked {
+		snapshots = append(snapshots, types.StateSyncSnapshot{
+			Height: s.Height,
+			Format: s.Format,
+			Chunks: s.Chunks,
+			Hash:   s.Hash,
+			Peers:  len(pool.GetPeers(s)),
+		})
+	}
+	return snapshots
+}
@@ -0,0 +1,113 @@
+package statesync
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cometbft/cometbft/types"
+)
+
+func TestSyncProgress(t *testing.T) {
+	eventBus := types.NewEventBus()
+	require.NoError(t, eventBus.Start())
+	t.Cleanup(func() {
+		if err := eventBus.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+	sub, err := eventBus.Subscribe(context.Background(), "test", types.EventQueryStateSyncStatus, 10)
+	require.NoError(t, err)
+	next := func() types.EventDataStateSyncStatus {
+		select {
+		case msg := <-sub.Out():
+			return msg.Data().(types.EventDataStateSyncStatus)
+		case <-time.After(time.Second):
+			t.Fatal("did not receive a state sync status after 1 sec.")
+		}
+		return types.EventDataStateSyncStatus{}
+	}
+
+	progress := newSyncProgress()
+	progress.publishInterval = 0
+	progress.setEventBus(eventBus)
+	pool := newSnapshotPool()
+	progress.start(pool)
+	assert.True(t, next().Syncing)
+
+	s := &snapshot{Height: 2, Format: 1, Chunks: 4, Hash: []byte{1}}
+	_, err = pool.Add(simplePeer("a"), s)
+	require.NoError(t, err)
+	progress.snapshotDiscovered()
+	assert.Equal(t, []types.StateSyncSnapshot{
+		{Height: 2, Format: 1, Chunks: 4, Hash: []byte{1}, Peers: 1},
+	}, next().Snapshots)
+
+	progress.restoring(s, 1)
+	status := next()
+	require.NotNil(t, status.Snapshot)
+	assert.EqualValues(t, 2, status.Snapshot.Height)
+	assert.EqualValues(t, 4, status.ChunksTotal)
+
+	time.Sleep(10 * time.Millisecond)
+	progress.chunkFetched(100)
+	status = next()
+	assert.EqualValues(t, 1, status.ChunksFetched)
+	assert.EqualValues(t, 100, status.BytesFetched)
+	assert.Greater(t, status.Throughput, float64(0))
+	assert.Greater(t, status.ETA, time.Duration(0))
+
+	progress.done(errors.New("boom"))
+	status = next()
+	assert.False(t, status.Syncing)
+	assert.Equal(t, "boom", status.Error)
+	assert.Zero(t, status.ETA)
+	assert.Len(t, status.Snapshots, 1)
+	assert.Equal(t, status, progress.Status())
+}
+
+func TestSyncProgressThrottled(t *testing.T) {
+	eventBus := types.NewEventBus()
+	require.NoError(t, eventBus.Start())
+	t.Cleanup(func() {
+		if err := eventBus.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+	sub, err := eventBus.Subscribe(context.Background(), "test", types.EventQueryStateSyncStatus, 100)
+	require.NoError(t, err)
+
+	progress := newSyncProgress()
+	progress.setEventBus(eventBus)
+	progress.start(newSnapshotPool())
+	progress.restoring(&snapshot{Height: 2, Format: 1, Chunks: 50, Hash: []byte{1}}, 1)
+	for i := 0; i < 50; i++ {
+		progress.chunkFetched(100)
+	}
+	progress.done(nil)
+
+	// Only the start and end of the sync and of the restore are published,
+	// the chunks fetched right after them being throttled.
+	var statuses []types.EventDataStateSyncStatus
+	for len(statuses) < 3 {
+		select {
+		case msg := <-sub.Out():
+			statuses = append(statuses, msg.Data().(types.EventDataStateSyncStatus))
+		case <-time.After(time.Second):
+			t.Fatal("did not receive a state sync status after 1 sec.")
+		}
+	}
+	select {
+	case msg := <-sub.Out():
+		t.Fatalf("unexpected status %v", msg.Data())
+	case <-time.After(100 * time.Millisecond):
+	}
+	assert.Zero(t, statuses[1].ChunksFetched)
+	assert.False(t, statuses[2].Syncing)
+	assert.EqualValues(t, 50, statuses[2].ChunksFetched)
+	assert.EqualValues(t, 50, progress.Status().ChunksFetched)
+}
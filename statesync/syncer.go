@@ -45,6 +45,15 @@ var (
 	errTimeout = errors.New("timed out waiting for chunk")
 	// errNoSnapshots is returned by SyncAny() if no snapshots are found and discovery is disabled.
 	errNoSnapshots = errors.New("no suitable snapshots found")
+	// errUntrustedSnapshotHash is returned by AddSnapshot() when a snapshot at the trusted
+	// snapshot height does not have the trusted hash.
+	errUntrustedSnapshotHash = errors.New("snapshot hash does not match trusted snapshot hash")
+	// errInvalidChunkHash is returned by AddChunk() when a chunk does not match the hash
+	// advertised for it along the snapshot.
+	errInvalidChunkHash = errors.New("chunk does not match its hash")
+	// errBadChunk is returned by AddChunk() when a peer sent a chunk not matching the hash it
+	// advertised for it, which is a provable fault.
+	errBadChunk = errors.New("peer sent a bad chunk")
 )
 
 // syncer runs a state sync against an ABCI app. Use either SyncAny() to automatically attempt to
@@ -59,6 +68,20 @@ type syncer struct {
 	tempDir       string
 	chunkFetchers int32
 	retryTimeout  time.Duration
+	metrics       *Metrics
+	progress      *syncProgress
+
+	// chunkRequestsPerPeer limits the chunk requests outstanding at each peer,
+	// and chunkRetryBudget the chunk requests retried for a snapshot, if not 0.
+	chunkRequestsPerPeer int
+	chunkRetryBudget     int
+
+	// trustedSnapshotHeight and trustedSnapshotHash pin the snapshot hash at a height.
+	trustedSnapshotHeight uint64
+	trustedSnapshotHash   []byte
+
+	// local, if set, is the source to restore the snapshot from instead of peers.
+	local *localSource
 
 	mtx    cmtsync.RWMutex
 	chunks *chunkQueue
@@ -72,9 +95,9 @@ func newSyncer(
 	connQuery proxy.AppConnQuery,
 	stateProvider StateProvider,
 	tempDir string,
+	metrics *Metrics,
 ) *syncer {
-
-	return &syncer{
+	s := &syncer{
 		logger:        logger,
 		stateProvider: stateProvider,
 		conn:          conn,
@@ -83,22 +106,55 @@ func newSyncer(
 		tempDir:       tempDir,
 		chunkFetchers: cfg.ChunkFetchers,
 		retryTimeout:  cfg.ChunkRequestTimeout,
+		metrics:       metrics,
+		progress:      newSyncProgress(),
+
+		chunkRequestsPerPeer: int(cfg.ChunkRequestsPerPeer),
+		chunkRetryBudget:     int(cfg.ChunkRetryBudget),
+	}
+	if cfg.TrustedSnapshotHeight > 0 {
+		s.trustedSnapshotHeight = cfg.TrustedSnapshotHeight
+		s.trustedSnapshotHash = cfg.TrustedSnapshotHashBytes()
+	}
+	if cfg.LocalPath != "" {
+		s.local = newLocalSource(cfg.LocalPath)
 	}
+	return s
 }
 
 // AddChunk adds a chunk to the chunk queue, if any. It returns false if the chunk has already
 // been added to the queue, or an error if there's no sync in progress.
+//
+// A chunk not matching its hash is dropped, to be refetched from a peer which advertised the
+// chunk hashes, and its sender is rejected. If the sender advertised that hash itself, it is
+// provably at fault, and errBadChunk is returned.
 func (s *syncer) AddChunk(chunk *chunk) (bool, error) {
 	s.mtx.RLock()
-	defer s.mtx.RUnlock()
 	if s.chunks == nil {
+		s.mtx.RUnlock()
 		return false, errors.New("no state sync in progress")
 	}
-	added, err := s.chunks.Add(chunk)
+	chunks := s.chunks
+	chunks.Lock()
+	snapshot := chunks.snapshot
+	chunks.Unlock()
+	added, err := chunks.Add(chunk)
+	s.mtx.RUnlock()
+	if errors.Is(err, errInvalidChunkHash) && snapshot != nil && chunk.Sender != "" {
+		advertised := s.snapshots.AdvertisedChunkHashes(chunk.Sender, snapshot)
+		s.logger.Info("Rejecting peer which sent a chunk not matching its hash", "peer", chunk.Sender,
+			"advertised", advertised, "height", chunk.Height, "format", chunk.Format, "chunk", chunk.Index)
+		s.snapshots.RejectPeer(chunk.Sender)
+		if advertised {
+			return false, fmt.Errorf("%w: %w", errBadChunk, err)
+		}
+	}
 	if err != nil {
 		return false, err
 	}
+	// The progress is reported without holding the lock, as it may publish it.
 	if added {
+		s.progress.chunkFetched(len(chunk.Chunk))
 		s.logger.Debug("Added chunk to queue", "height", chunk.Height, "format", chunk.Format,
 			"chunk", chunk.Index)
 	} else {
@@ -108,20 +164,59 @@ func (s *syncer) AddChunk(chunk *chunk) (bool, error) {
 	return added, nil
 }
 
+// checkTrustedSnapshot returns errUntrustedSnapshotHash if the snapshot is at the trusted snapshot
+// height, but does not have the trusted snapshot hash. A zero trusted height trusts any snapshot.
+func checkTrustedSnapshot(snapshot *snapshot, trustedHeight uint64, trustedHash []byte) error {
+	if trustedHeight > 0 && snapshot.Height == trustedHeight && !bytes.Equal(snapshot.Hash, trustedHash) {
+		return fmt.Errorf("%w: got %X, expected %X", errUntrustedSnapshotHash, snapshot.Hash, trustedHash)
+	}
+	return nil
+}
+
 // AddSnapshot adds a snapshot to the snapshot pool. It returns true if a new, previously unseen
 // snapshot was accepted and added.
 func (s *syncer) AddSnapshot(peer p2p.Peer, snapshot *snapshot) (bool, error) {
+	if err := checkTrustedSnapshot(snapshot, s.trustedSnapshotHeight, s.trustedSnapshotHash); err != nil {
+		s.metrics.SnapshotsRejected.With("reason", "untrusted_hash").Add(1)
+		return false, err
+	}
 	added, err := s.snapshots.Add(peer, snapshot)
 	if err != nil {
 		return false, err
 	}
+	// The chunk hashes of the snapshot being restored, if it's this one, follow those its peers
+	// agree on.
+	if hashes := s.snapshots.ChunkHashes(snapshot); len(hashes) > 0 {
+		s.mtx.RLock()
+		if s.chunks != nil {
+			updated, err := s.chunks.SetChunkHashes(snapshot.Key(), hashes)
+			if err != nil {
+				s.logger.Error("Failed to save chunk manifest", "err", err)
+			}
+			if updated {
+				s.logger.Info("Peers agree on other chunk hashes, verifying the chunks against them",
+					"height", snapshot.Height, "format", snapshot.Format)
+			}
+		}
+		s.mtx.RUnlock()
+	}
 	if added {
+		s.metrics.SnapshotsDiscovered.Add(1)
+		s.progress.snapshotDiscovered()
 		s.logger.Info("Discovered new snapshot", "height", snapshot.Height, "format", snapshot.Format,
 			"hash", log.NewLazySprintf("%X", snapshot.Hash))
 	}
 	return added, nil
 }
 
+// rejectSnapshot rejects a snapshot, and the peers which advertised it if they failed too many
+// times.
+func (s *syncer) rejectSnapshot(snapshot *snapshot) {
+	for _, peerID := range s.snapshots.Reject(snapshot) {
+		s.logger.Info("Snapshot peer rejected after repeated failures", "peer", peerID)
+	}
+}
+
 // AddPeer adds a peer to the pool. For now we just keep it simple and send a single request
 // to discover snapshots, later we may want to do retries and stuff.
 func (s *syncer) AddPeer(peer p2p.Peer) {
@@ -141,13 +236,21 @@ func (s *syncer) RemovePeer(peer p2p.Peer) {
 
 // SyncAny tries to sync any of the snapshots in the snapshot pool, waiting to discover further
 // snapshots if none were found and discoveryTime > 0. It returns the latest state and block commit
-// which the caller must use to bootstrap the node.
+// which the caller must use to bootstrap the node. If a local source is configured, it syncs its
+// snapshot instead.
 func (s *syncer) SyncAny(discoveryTime time.Duration, retryHook func()) (sm.State, *types.Commit, error) {
+	if s.local != nil {
+		return s.syncLocal()
+	}
+
 	if discoveryTime != 0 && discoveryTime < minimumDiscoveryTime {
 		discoveryTime = 5 * minimumDiscoveryTime
 	}
 
-	if discoveryTime > 0 {
+	// If the node restarted while restoring a snapshot, resume from the chunks already fetched
+	// instead of discovering snapshots again.
+	resumed := s.resumableSnapshot()
+	if resumed == nil && discoveryTime > 0 {
 		s.logger.Info("Discovering snapshots", "discoverTime", discoveryTime)
 		time.Sleep(discoveryTime)
 	}
@@ -162,7 +265,10 @@ func (s *syncer) SyncAny(discoveryTime time.Duration, retryHook func()) (sm.Stat
 	for {
 		// If not nil, we're going to retry restoration of the same snapshot.
 		if snapshot == nil {
-			snapshot = s.snapshots.Best()
+			snapshot, resumed = resumed, nil
+			if snapshot == nil {
+				snapshot = s.snapshots.Best()
+			}
 			chunks = nil
 		}
 		if snapshot == nil {
@@ -174,8 +280,9 @@ func (s *syncer) SyncAny(discoveryTime time.Duration, retryHook func()) (sm.Stat
 			time.Sleep(discoveryTime)
 			continue
 		}
+		s.metrics.SnapshotPeers.Set(float64(len(s.snapshots.GetPeers(snapshot))))
 		if chunks == nil {
-			chunks, err = newChunkQueue(snapshot, s.tempDir)
+			chunks, err = resumeChunkQueue(snapshot, s.tempDir)
 			if err != nil {
 				return sm.State{}, nil, fmt.Errorf("failed to create chunk queue: %w", err)
 			}
@@ -197,20 +304,24 @@ func (s *syncer) SyncAny(discoveryTime time.Duration, retryHook func()) (sm.Stat
 			continue
 
 		case errors.Is(err, errTimeout):
-			s.snapshots.Reject(snapshot)
+			s.rejectSnapshot(snapshot)
+			s.metrics.SnapshotsRejected.With("reason", "chunk_timeout").Add(1)
 			s.logger.Error("Timed out waiting for snapshot chunks, rejected snapshot",
 				"height", snapshot.Height, "format", snapshot.Format, "hash", log.NewLazySprintf("%X", snapshot.Hash))
 
 		case errors.Is(err, errRejectSnapshot):
-			s.snapshots.Reject(snapshot)
+			s.rejectSnapshot(snapshot)
+			s.metrics.SnapshotsRejected.With("reason", "rejected").Add(1)
 			s.logger.Info("Snapshot rejected", "height", snapshot.Height, "format", snapshot.Format,
 				"hash", log.NewLazySprintf("%X", snapshot.Hash))
 
 		case errors.Is(err, errRejectFormat):
 			s.snapshots.RejectFormat(snapshot.Format)
+			s.metrics.SnapshotsRejected.With("reason", "format").Add(1)
 			s.logger.Info("Snapshot format rejected", "format", snapshot.Format)
 
 		case errors.Is(err, errRejectSender):
+			s.metrics.SnapshotsRejected.With("reason", "sender").Add(1)
 			s.logger.Info("Snapshot senders rejected", "height", snapshot.Height, "format", snapshot.Format,
 				"hash", log.NewLazySprintf("%X", snapshot.Hash))
 			for _, peer := range s.snapshots.GetPeers(snapshot) {
@@ -220,7 +331,8 @@ func (s *syncer) SyncAny(discoveryTime time.Duration, retryHook func()) (sm.Stat
 
 		case errors.Is(err, context.DeadlineExceeded):
 			s.logger.Info("Timed out validating snapshot, rejecting", "height", snapshot.Height, "err", err)
-			s.snapshots.Reject(snapshot)
+			s.rejectSnapshot(snapshot)
+			s.metrics.SnapshotsRejected.With("reason", "verify_timeout").Add(1)
 
 		default:
 			return sm.State{}, nil, fmt.Errorf("snapshot restoration failed: %w", err)
@@ -236,6 +348,57 @@ func (s *syncer) SyncAny(discoveryTime time.Duration, retryHook func()) (sm.Stat
 	}
 }
 
+// resumableSnapshot returns the snapshot whose restore was interrupted, with chunks left in the
+// temp dir, if any.
+func (s *syncer) resumableSnapshot() *snapshot {
+	snapshot, fetched := resumableSnapshot(s.tempDir)
+	if snapshot == nil {
+		return nil
+	}
+	if checkTrustedSnapshot(snapshot, s.trustedSnapshotHeight, s.trustedSnapshotHash) != nil {
+		return nil
+	}
+	s.logger.Info("Resuming snapshot restoration", "height", snapshot.Height, "format", snapshot.Format,
+		"hash", log.NewLazySprintf("%X", snapshot.Hash), "fetched", fetched, "total", snapshot.Chunks)
+	return snapshot
+}
+
+// syncLocal syncs the snapshot of the local source. As for snapshots discovered from peers, the
+// app hash of the restored app is verified against the one of the light client.
+func (s *syncer) syncLocal() (sm.State, *types.Commit, error) {
+	ctx, cancel := context.WithTimeout(context.TODO(), chunkTimeout)
+	defer cancel()
+	snapshot, err := s.local.Snapshot(ctx)
+	if err != nil {
+		return sm.State{}, nil, fmt.Errorf("failed to load local snapshot: %w", err)
+	}
+	if err := checkTrustedSnapshot(snapshot, s.trustedSnapshotHeight, s.trustedSnapshotHash); err != nil {
+		return sm.State{}, nil, err
+	}
+	s.logger.Info("Restoring local snapshot", "location", s.local.location, "height", snapshot.Height,
+		"format", snapshot.Format, "hash", log.NewLazySprintf("%X", snapshot.Hash))
+
+	chunks, err := resumeChunkQueue(snapshot, s.tempDir)
+	if err != nil {
+		return sm.State{}, nil, fmt.Errorf("failed to create chunk queue: %w", err)
+	}
+	defer chunks.Close()
+
+	for {
+		newState, commit, err := s.Sync(snapshot, chunks)
+		switch {
+		case err == nil:
+			return newState, commit, nil
+		case errors.Is(err, errRetrySnapshot):
+			chunks.RetryAll()
+			s.logger.Info("Retrying local snapshot", "height", snapshot.Height, "format", snapshot.Format,
+				"hash", log.NewLazySprintf("%X", snapshot.Hash))
+		default:
+			return sm.State{}, nil, fmt.Errorf("local snapshot restoration failed: %w", err)
+		}
+	}
+}
+
 // Sync executes a sync for a specific snapshot, returning the latest state and block commit which
 // the caller must use to bootstrap the node.
 func (s *syncer) Sync(snapshot *snapshot, chunks *chunkQueue) (sm.State, *types.Commit, error) {
@@ -283,12 +446,18 @@ func (s *syncer) Sync(snapshot *snapshot, chunks *chunkQueue) (sm.State, *types.
 	if err != nil {
 		return sm.State{}, nil, err
 	}
+	s.progress.restoring(snapshot, len(s.snapshots.GetPeers(snapshot)))
 
 	// Spawn chunk fetchers. They will terminate when the chunk queue is closed or context canceled.
 	fetchCtx, cancel := context.WithCancel(context.TODO())
 	defer cancel()
-	for i := int32(0); i < s.chunkFetchers; i++ {
-		go s.fetchChunks(fetchCtx, snapshot, chunks)
+	if s.local != nil {
+		go s.fetchLocalChunks(fetchCtx, snapshot, chunks)
+	} else {
+		scheduler := newChunkScheduler(s.chunkRequestsPerPeer, s.chunkRetryBudget)
+		for i := int32(0); i < s.chunkFetchers; i++ {
+			go s.fetchChunks(fetchCtx, snapshot, chunks, scheduler)
+		}
 	}
 
 	commit, err := s.stateProvider.Commit(pctx, snapshot.Height)
@@ -402,6 +571,8 @@ func (s *syncer) applyChunks(chunks *chunkQueue) error {
 
 		switch resp.Result {
 		case abci.ResponseApplySnapshotChunk_ACCEPT:
+			s.metrics.ChunksApplied.Add(1)
+			s.metrics.ChunkBytesApplied.Add(float64(len(chunk.Chunk)))
 		case abci.ResponseApplySnapshotChunk_ABORT:
 			return errAbort
 		case abci.ResponseApplySnapshotChunk_RETRY:
@@ -416,13 +587,16 @@ func (s *syncer) applyChunks(chunks *chunkQueue) error {
 	}
 }
 
-// fetchChunks requests chunks from peers, receiving allocations from the chunk queue. Chunks
-// will be received from the reactor via syncer.AddChunks() to chunkQueue.Add().
-func (s *syncer) fetchChunks(ctx context.Context, snapshot *snapshot, chunks *chunkQueue) {
+// fetchChunks requests chunks from peers, receiving allocations from the chunk queue and
+// spreading the requests across peers with the scheduler. Chunks will be received from the
+// reactor via syncer.AddChunks() to chunkQueue.Add().
+func (s *syncer) fetchChunks(ctx context.Context, snapshot *snapshot, chunks *chunkQueue, scheduler *chunkScheduler) {
 	var (
-		next  = true
-		index uint32
-		err   error
+		next     = true
+		index    uint32
+		retries  int
+		lastPeer p2p.ID // the chunk was last requested from
+		err      error
 	)
 
 	for {
@@ -443,38 +617,113 @@ func (s *syncer) fetchChunks(ctx context.Context, snapshot *snapshot, chunks *ch
 				s.logger.Error("Failed to allocate chunk from queue", "err", err)
 				return
 			}
+			next, retries, lastPeer = false, 0, ""
 		}
-		s.logger.Info("Fetching snapshot chunk", "height", snapshot.Height,
-			"format", snapshot.Format, "chunk", index, "total", chunks.Size())
-
-		ticker := time.NewTicker(s.retryTimeout)
-		defer ticker.Stop()
 
-		s.requestChunk(snapshot, index)
+		peers := s.snapshots.GetPeers(snapshot)
+		if retries > 0 {
+			// Retries go to the peers which advertised the chunk hashes if possible, as the
+			// chunk may have been dropped for not matching its hash.
+			if advertisers := s.snapshots.GetChunkHashPeers(snapshot); len(advertisers) > 0 {
+				peers = advertisers
+			}
+		}
+		peer := scheduler.acquire(peers, lastPeer)
+		if peer == nil {
+			// Wait for a peer to be available, or to be added to the snapshot.
+			select {
+			case <-chunks.WaitFor(index):
+				next = true
+			case <-time.After(busyPeersInterval):
+			case <-ctx.Done():
+				return
+			}
+			continue
+		}
+		s.logger.Info("Fetching snapshot chunk", "height", snapshot.Height,
+			"format", snapshot.Format, "chunk", index, "total", chunks.Size(), "peer", peer.ID())
+		s.requestChunk(snapshot, index, peer)
 
+		timer := time.NewTimer(s.retryTimeout)
 		select {
 		case <-chunks.WaitFor(index):
 			next = true
+		case <-timer.C:
+		case <-ctx.Done():
+		}
+		timer.Stop()
+		scheduler.release(peer.ID())
+		if next {
+			continue
+		}
+		if ctx.Err() != nil {
+			return
+		}
 
-		case <-ticker.C:
-			next = false
-
+		// The request timed out, retry it after a backoff, preferably from another peer.
+		if !scheduler.retry() {
+			s.logger.Error("Chunk retry budget exhausted", "height", snapshot.Height,
+				"format", snapshot.Format, "chunk", index)
+			chunks.Fail()
+			return
+		}
+		retries++
+		lastPeer = peer.ID()
+		select {
+		case <-chunks.WaitFor(index):
+			next = true
+		case <-time.After(retryBackoff(retries)):
 		case <-ctx.Done():
 			return
 		}
+	}
+}
 
-		ticker.Stop()
+// fetchLocalChunks loads the chunks allocated from the chunk queue from the local source. If a
+// chunk can't be loaded, the chunk queue fails instead of waiting for it.
+func (s *syncer) fetchLocalChunks(ctx context.Context, snapshot *snapshot, chunks *chunkQueue) {
+	for {
+		index, err := chunks.Allocate()
+		if errors.Is(err, errDone) {
+			// Keep checking until the context is canceled (restore is done), in case any
+			// chunks need to be refetched.
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(2 * time.Second):
+			}
+			continue
+		}
+		if err != nil {
+			s.logger.Error("Failed to allocate chunk from queue", "err", err)
+			return
+		}
+
+		bz, err := s.local.Chunk(ctx, index)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			s.logger.Error("Failed to load local snapshot chunk", "chunk", index, "err", err)
+			chunks.Fail()
+			return
+		}
+		_, err = s.AddChunk(&chunk{
+			Height: snapshot.Height,
+			Format: snapshot.Format,
+			Index:  index,
+			Chunk:  bz,
+		})
+		if err != nil {
+			s.logger.Error("Failed to add local snapshot chunk", "chunk", index, "err", err)
+			chunks.Fail()
+			return
+		}
 	}
 }
 
 // requestChunk requests a chunk from a peer.
-func (s *syncer) requestChunk(snapshot *snapshot, chunk uint32) {
-	peer := s.snapshots.GetPeer(snapshot)
-	if peer == nil {
-		s.logger.Error("No valid peers found for snapshot", "height", snapshot.Height,
-			"format", snapshot.Format, "hash", log.NewLazySprintf("%X", snapshot.Hash))
-		return
-	}
+func (s *syncer) requestChunk(snapshot *snapshot, chunk uint32, peer p2p.Peer) {
 	s.logger.Debug("Requesting snapshot chunk", "height", snapshot.Height,
 		"format", snapshot.Format, "chunk", chunk, "peer", peer.ID())
 	peer.Send(p2p.Envelope{
@@ -1,10 +1,15 @@
 package statesync
 
 import (
+	"context"
+	"crypto/sha256"
 	"errors"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/go-kit/kit/metrics"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -38,7 +43,7 @@ func setupOfferSyncer() (*syncer, *proxymocks.AppConnSnapshot) {
 	stateProvider.On("State", mock.AnythingOfType("*context.timerCtx"), uint64(2)).Return(sm.State{}, nil)
 	stateProvider.On("State", mock.AnythingOfType("*context.timerCtx"), uint64(4)).Return(sm.State{}, nil)
 	cfg := config.DefaultStateSyncConfig()
-	syncer := newSyncer(*cfg, log.NewNopLogger(), connSnapshot, connQuery, stateProvider, "")
+	syncer := newSyncer(*cfg, log.NewNopLogger(), connSnapshot, connQuery, stateProvider, "", NopMetrics())
 
 	return syncer, connSnapshot
 }
@@ -93,7 +98,7 @@ func TestSyncer_SyncAny(t *testing.T) {
 	connQuery := &proxymocks.AppConnQuery{}
 
 	cfg := config.DefaultStateSyncConfig()
-	syncer := newSyncer(*cfg, log.NewNopLogger(), connSnapshot, connQuery, stateProvider, "")
+	syncer := newSyncer(*cfg, log.NewNopLogger(), connSnapshot, connQuery, stateProvider, "", NopMetrics())
 
 	// Adding a chunk should error when no sync is in progress
 	_, err := syncer.AddChunk(&chunk{Height: 1, Format: 1, Index: 0, Chunk: []byte{1}})
@@ -241,6 +246,150 @@ func TestSyncer_SyncAny_noSnapshots(t *testing.T) {
 	assert.Equal(t, errNoSnapshots, err)
 }
 
+func TestSyncer_SyncAny_resume(t *testing.T) {
+	state := sm.State{ChainID: "chain", LastBlockHeight: 1, AppHash: []byte("app_hash")}
+	state.Version.Consensus.App = testAppVersion
+	commit := &types.Commit{BlockID: types.BlockID{Hash: []byte("blockhash")}}
+	s := &snapshot{Height: 1, Format: 1, Chunks: 2, Hash: []byte{1, 2, 3}}
+
+	// The chunks were fetched before the node restarted.
+	tempDir := t.TempDir()
+	chunks, err := resumeChunkQueue(s, tempDir)
+	require.NoError(t, err)
+	for i := uint32(0); i < s.Chunks; i++ {
+		_, err = chunks.Add(&chunk{Height: 1, Format: 1, Index: i, Chunk: []byte{1, 1, byte(i)}, Sender: "a"})
+		require.NoError(t, err)
+	}
+
+	stateProvider := &mocks.StateProvider{}
+	stateProvider.On("AppHash", mock.Anything, uint64(1)).Return(state.AppHash, nil)
+	stateProvider.On("Commit", mock.Anything, uint64(1)).Return(commit, nil)
+	stateProvider.On("State", mock.Anything, uint64(1)).Return(state, nil)
+	connSnapshot := &proxymocks.AppConnSnapshot{}
+	connQuery := &proxymocks.AppConnQuery{}
+	cfg := config.DefaultStateSyncConfig()
+	syncer := newSyncer(*cfg, log.NewNopLogger(), connSnapshot, connQuery, stateProvider, tempDir, NopMetrics())
+
+	connSnapshot.On("OfferSnapshot", mock.Anything, &abci.RequestOfferSnapshot{
+		Snapshot: toABCI(s), AppHash: []byte("app_hash"),
+	}).Return(&abci.ResponseOfferSnapshot{Result: abci.ResponseOfferSnapshot_ACCEPT}, nil)
+	for i := uint32(0); i < s.Chunks; i++ {
+		connSnapshot.On("ApplySnapshotChunk", mock.Anything, &abci.RequestApplySnapshotChunk{
+			Index: i, Chunk: []byte{1, 1, byte(i)}, Sender: "a",
+		}).Once().Return(&abci.ResponseApplySnapshotChunk{Result: abci.ResponseApplySnapshotChunk_ACCEPT}, nil)
+	}
+	connQuery.On("Info", mock.Anything, proxy.RequestInfo).Return(&abci.ResponseInfo{
+		AppVersion:       testAppVersion,
+		LastBlockHeight:  1,
+		LastBlockAppHash: []byte("app_hash"),
+	}, nil)
+
+	// No peer advertised the snapshot, it is restored from the chunks fetched.
+	newState, lastCommit, err := syncer.SyncAny(time.Minute, func() {})
+	require.NoError(t, err)
+	assert.Equal(t, state, newState)
+	assert.Equal(t, commit, lastCommit)
+	connSnapshot.AssertExpectations(t)
+
+	// The chunks are removed once restored.
+	resumed, _ := resumableSnapshot(tempDir)
+	assert.Nil(t, resumed)
+}
+
+func TestSyncer_SyncAny_untrustedSnapshotHash(t *testing.T) {
+	connQuery := &proxymocks.AppConnQuery{}
+	connSnapshot := &proxymocks.AppConnSnapshot{}
+	stateProvider := &mocks.StateProvider{}
+	cfg := config.DefaultStateSyncConfig()
+	cfg.TrustedSnapshotHeight = 2
+	cfg.TrustedSnapshotHash = "010203"
+	syncer := newSyncer(*cfg, log.NewNopLogger(), connSnapshot, connQuery, stateProvider, "", NopMetrics())
+
+	// All peers advertise the same snapshot, but its hash differs from the pinned one.
+	s := &snapshot{Height: 2, Format: 1, Chunks: 3, Hash: []byte{4, 5, 6}}
+	for _, id := range []string{"a", "b", "c"} {
+		added, err := syncer.AddSnapshot(simplePeer(id), s)
+		require.ErrorIs(t, err, errUntrustedSnapshotHash)
+		require.False(t, added)
+	}
+
+	// Snapshots at other heights are not affected.
+	added, err := syncer.AddSnapshot(simplePeer("a"), &snapshot{Height: 1, Format: 1, Chunks: 3, Hash: []byte{4, 5, 6}})
+	require.NoError(t, err)
+	require.True(t, added)
+	// A snapshot with the trusted hash is accepted.
+	added, err = syncer.AddSnapshot(simplePeer("a"), &snapshot{Height: 2, Format: 1, Chunks: 3, Hash: []byte{1, 2, 3}})
+	require.NoError(t, err)
+	require.True(t, added)
+
+	// Only the snapshots above made it into the pool.
+	require.Len(t, syncer.snapshots.Ranked(), 2)
+	connSnapshot.AssertNotCalled(t, "OfferSnapshot", mock.Anything, mock.Anything)
+}
+
+// testCounter is a metrics.Counter recording its values by label values.
+type testCounter struct {
+	lvs    string
+	values map[string]float64
+}
+
+func newTestCounter() *testCounter {
+	return &testCounter{values: make(map[string]float64)}
+}
+
+func (c *testCounter) With(labelValues ...string) metrics.Counter {
+	return &testCounter{lvs: c.lvs + strings.Join(labelValues, ","), values: c.values}
+}
+
+func (c *testCounter) Add(delta float64) {
+	c.values[c.lvs] += delta
+}
+
+func TestSyncer_SnapshotMetrics(t *testing.T) {
+	connQuery := &proxymocks.AppConnQuery{}
+	connSnapshot := &proxymocks.AppConnSnapshot{}
+	stateProvider := &mocks.StateProvider{}
+	stateProvider.On("AppHash", mock.Anything, mock.Anything).Return([]byte("app_hash"), nil)
+	stateProvider.On("State", mock.Anything, mock.Anything).Return(sm.State{}, nil)
+	cfg := config.DefaultStateSyncConfig()
+	cfg.TrustedSnapshotHeight = 3
+	cfg.TrustedSnapshotHash = "0303"
+
+	discovered, rejected := newTestCounter(), newTestCounter()
+	metrics := NopMetrics()
+	metrics.SnapshotsDiscovered = discovered
+	metrics.SnapshotsRejected = rejected
+	syncer := newSyncer(*cfg, log.NewNopLogger(), connSnapshot, connQuery, stateProvider, "", metrics)
+
+	s1 := &snapshot{Height: 1, Format: 1, Chunks: 1, Hash: []byte{1}}
+	s2 := &snapshot{Height: 2, Format: 1, Chunks: 1, Hash: []byte{2}}
+	untrusted := &snapshot{Height: 3, Format: 1, Chunks: 1, Hash: []byte{9}}
+
+	for _, peer := range []string{"a", "b"} {
+		_, err := syncer.AddSnapshot(simplePeer(peer), s1)
+		require.NoError(t, err)
+		_, err = syncer.AddSnapshot(simplePeer(peer), s2)
+		require.NoError(t, err)
+		_, err = syncer.AddSnapshot(simplePeer(peer), untrusted)
+		require.Error(t, err)
+	}
+	assert.Equal(t, map[string]float64{"": 2}, discovered.values)
+	assert.Equal(t, map[string]float64{"reason,untrusted_hash": 2}, rejected.values)
+
+	// The app rejects the format of the best snapshot, then aborts.
+	connSnapshot.On("OfferSnapshot", mock.Anything, mock.MatchedBy(func(req *abci.RequestOfferSnapshot) bool {
+		return req.Snapshot.Height == 2
+	})).Return(&abci.ResponseOfferSnapshot{Result: abci.ResponseOfferSnapshot_REJECT}, nil)
+	connSnapshot.On("OfferSnapshot", mock.Anything, mock.MatchedBy(func(req *abci.RequestOfferSnapshot) bool {
+		return req.Snapshot.Height == 1
+	})).Return(&abci.ResponseOfferSnapshot{Result: abci.ResponseOfferSnapshot_ABORT}, nil)
+
+	_, _, err := syncer.SyncAny(0, func() {})
+	require.Equal(t, errAbort, err)
+	assert.Equal(t, map[string]float64{"reason,untrusted_hash": 2, "reason,rejected": 1}, rejected.values)
+	connSnapshot.AssertExpectations(t)
+}
+
 func TestSyncer_SyncAny_abort(t *testing.T) {
 	syncer, connSnapshot := setupOfferSyncer()
 
@@ -352,6 +501,109 @@ func TestSyncer_SyncAny_reject_sender(t *testing.T) {
 	connSnapshot.AssertExpectations(t)
 }
 
+func TestSyncer_AddChunk_badChunk(t *testing.T) {
+	syncer, _ := setupOfferSyncer()
+	hash := sha256.Sum256([]byte{3, 1, 0})
+	s := &snapshot{Height: 3, Format: 1, Chunks: 2, Hash: []byte{1}, ChunkHashes: [][]byte{hash[:], hash[:]}}
+	_, err := syncer.AddSnapshot(simplePeer("a"), s)
+	require.NoError(t, err)
+	_, err = syncer.AddSnapshot(simplePeer("b"), &snapshot{Height: 3, Format: 1, Chunks: 2, Hash: []byte{1}})
+	require.NoError(t, err)
+
+	chunks, err := newChunkQueue(syncer.snapshots.Best(), "")
+	require.NoError(t, err)
+	defer chunks.Close()
+	syncer.chunks = chunks
+
+	// A bad chunk from a peer which advertised the chunk hashes rejects the peer.
+	added, err := syncer.AddChunk(&chunk{Height: 3, Format: 1, Index: 0, Chunk: []byte{3, 1, 9}, Sender: "a"})
+	require.ErrorIs(t, err, errBadChunk)
+	assert.False(t, added)
+	peers := syncer.snapshots.GetPeers(s)
+	require.Len(t, peers, 1)
+	assert.EqualValues(t, "b", peers[0].ID())
+
+	added, err = syncer.AddChunk(&chunk{Height: 3, Format: 1, Index: 0, Chunk: []byte{3, 1, 0}, Sender: "b"})
+	require.NoError(t, err)
+	assert.True(t, added)
+}
+
+func TestSyncer_AddChunk_nonAdvertiser(t *testing.T) {
+	syncer, _ := setupOfferSyncer()
+	hash := sha256.Sum256([]byte{3, 1, 0})
+	s := &snapshot{Height: 3, Format: 1, Chunks: 2, Hash: []byte{1}}
+	_, err := syncer.AddSnapshot(simplePeer("a"), &snapshot{Height: 3, Format: 1, Chunks: 2, Hash: []byte{1},
+		ChunkHashes: [][]byte{hash[:], hash[:]}})
+	require.NoError(t, err)
+	_, err = syncer.AddSnapshot(simplePeer("b"), s)
+	require.NoError(t, err)
+	_, err = syncer.AddSnapshot(simplePeer("c"), s)
+	require.NoError(t, err)
+
+	chunks, err := newChunkQueue(syncer.snapshots.Best(), "")
+	require.NoError(t, err)
+	defer chunks.Close()
+	syncer.chunks = chunks
+
+	// A bad chunk from a peer which did not advertise the chunk hashes is dropped, and the peer is
+	// rejected without the chunk hashes being dropped nor the peer which advertised them rejected.
+	added, err := syncer.AddChunk(&chunk{Height: 3, Format: 1, Index: 0, Chunk: []byte{3, 1, 9}, Sender: "b"})
+	require.ErrorIs(t, err, errInvalidChunkHash)
+	require.NotErrorIs(t, err, errBadChunk)
+	assert.False(t, added)
+	assert.Equal(t, [][]byte{hash[:], hash[:]}, chunks.snapshot.ChunkHashes)
+	peerIDs := []p2p.ID{}
+	for _, peer := range syncer.snapshots.GetPeers(s) {
+		peerIDs = append(peerIDs, peer.ID())
+	}
+	assert.Equal(t, []p2p.ID{"a", "c"}, peerIDs)
+
+	// The chunk is refetched from the peer which advertised the chunk hashes.
+	advertisers := syncer.snapshots.GetChunkHashPeers(s)
+	require.Len(t, advertisers, 1)
+	assert.EqualValues(t, "a", advertisers[0].ID())
+	added, err = syncer.AddChunk(&chunk{Height: 3, Format: 1, Index: 0, Chunk: []byte{3, 1, 0}, Sender: "a"})
+	require.NoError(t, err)
+	assert.True(t, added)
+}
+
+func TestSyncer_AddSnapshot_conflictingChunkHashes(t *testing.T) {
+	syncer, _ := setupOfferSyncer()
+	hash := sha256.Sum256([]byte{3, 1, 0})
+	other := sha256.Sum256([]byte{9})
+	withHashes := func(hashes ...[]byte) *snapshot {
+		return &snapshot{Height: 3, Format: 1, Chunks: 1, Hash: []byte{1}, ChunkHashes: hashes}
+	}
+	_, err := syncer.AddSnapshot(simplePeer("a"), withHashes(hash[:]))
+	require.NoError(t, err)
+
+	chunks, err := newChunkQueue(syncer.snapshots.Best(), "")
+	require.NoError(t, err)
+	defer chunks.Close()
+	syncer.chunks = chunks
+
+	// A single peer advertising different chunk hashes during the restore doesn't stop the chunks
+	// being verified against those first advertised.
+	_, err = syncer.AddSnapshot(simplePeer("b"), withHashes(other[:]))
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{hash[:]}, chunks.snapshot.ChunkHashes)
+	added, err := syncer.AddChunk(&chunk{Height: 3, Format: 1, Index: 0, Chunk: []byte{9}, Sender: "b"})
+	require.ErrorIs(t, err, errInvalidChunkHash)
+	assert.False(t, added)
+
+	// Once most advertisers agree on other chunk hashes, the chunks are verified against them,
+	// and the peers advertising the previous ones are rejected.
+	_, err = syncer.AddSnapshot(simplePeer("c"), withHashes(other[:]))
+	require.NoError(t, err)
+	_, err = syncer.AddSnapshot(simplePeer("d"), withHashes(other[:]))
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{other[:]}, chunks.snapshot.ChunkHashes)
+	assert.False(t, syncer.snapshots.AdvertisedChunkHashes("a", chunks.snapshot))
+	added, err = syncer.AddChunk(&chunk{Height: 3, Format: 1, Index: 0, Chunk: []byte{9}, Sender: "c"})
+	require.NoError(t, err)
+	assert.True(t, added)
+}
+
 func TestSyncer_SyncAny_abciError(t *testing.T) {
 	syncer, connSnapshot := setupOfferSyncer()
 
@@ -436,7 +688,7 @@ func TestSyncer_applyChunks_Results(t *testing.T) {
 			stateProvider.On("AppHash", mock.Anything, mock.Anything).Return([]byte("app_hash"), nil)
 
 			cfg := config.DefaultStateSyncConfig()
-			syncer := newSyncer(*cfg, log.NewNopLogger(), connSnapshot, connQuery, stateProvider, "")
+			syncer := newSyncer(*cfg, log.NewNopLogger(), connSnapshot, connQuery, stateProvider, "", NopMetrics())
 
 			body := []byte{1, 2, 3}
 			chunks, err := newChunkQueue(&snapshot{Height: 1, Format: 1, Chunks: 1}, "")
@@ -490,7 +742,7 @@ func TestSyncer_applyChunks_RefetchChunks(t *testing.T) {
 			stateProvider.On("AppHash", mock.Anything, mock.Anything).Return([]byte("app_hash"), nil)
 
 			cfg := config.DefaultStateSyncConfig()
-			syncer := newSyncer(*cfg, log.NewNopLogger(), connSnapshot, connQuery, stateProvider, "")
+			syncer := newSyncer(*cfg, log.NewNopLogger(), connSnapshot, connQuery, stateProvider, "", NopMetrics())
 
 			chunks, err := newChunkQueue(&snapshot{Height: 1, Format: 1, Chunks: 3}, "")
 			require.NoError(t, err)
@@ -555,7 +807,7 @@ func TestSyncer_applyChunks_RejectSenders(t *testing.T) {
 			stateProvider.On("AppHash", mock.Anything, mock.Anything).Return([]byte("app_hash"), nil)
 
 			cfg := config.DefaultStateSyncConfig()
-			syncer := newSyncer(*cfg, log.NewNopLogger(), connSnapshot, connQuery, stateProvider, "")
+			syncer := newSyncer(*cfg, log.NewNopLogger(), connSnapshot, connQuery, stateProvider, "", NopMetrics())
 
 			// Set up three peers across two snapshots, and ask for one of them to be banned.
 			// It should be banned from all snapshots.
@@ -677,7 +929,7 @@ func TestSyncer_verifyApp(t *testing.T) {
 			stateProvider := &mocks.StateProvider{}
 
 			cfg := config.DefaultStateSyncConfig()
-			syncer := newSyncer(*cfg, log.NewNopLogger(), connSnapshot, connQuery, stateProvider, "")
+			syncer := newSyncer(*cfg, log.NewNopLogger(), connSnapshot, connQuery, stateProvider, "", NopMetrics())
 
 			connQuery.On("Info", mock.Anything, proxy.RequestInfo).Return(tc.response, tc.err)
 			_, err := syncer.verifyApp(s, appVersion)
@@ -699,3 +951,45 @@ func toABCI(s *snapshot) *abci.Snapshot {
 		Metadata: s.Metadata,
 	}
 }
+
+func TestSyncer_fetchChunks_retryBudget(t *testing.T) {
+	cfg := config.DefaultStateSyncConfig()
+	cfg.ChunkRetryBudget = 2
+	syncer := newSyncer(*cfg, log.NewNopLogger(), &proxymocks.AppConnSnapshot{},
+		&proxymocks.AppConnQuery{}, &mocks.StateProvider{}, "", NopMetrics())
+	syncer.retryTimeout = 10 * time.Millisecond
+
+	// Neither peer answers chunk requests.
+	var (
+		requestsMtx sync.Mutex
+		requests    []p2p.ID
+	)
+	s := &snapshot{Height: 1, Format: 1, Chunks: 1, Hash: []byte{1}}
+	for _, id := range []string{"a", "b"} {
+		peer := simplePeer(id)
+		peer.On("Send", mock.Anything).Run(func(args mock.Arguments) {
+			requestsMtx.Lock()
+			defer requestsMtx.Unlock()
+			requests = append(requests, p2p.ID(id))
+		}).Return(true)
+		_, err := syncer.AddSnapshot(peer, s)
+		require.NoError(t, err)
+	}
+	chunks, err := newChunkQueue(s, "")
+	require.NoError(t, err)
+	defer chunks.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go syncer.fetchChunks(ctx, s, chunks, newChunkScheduler(syncer.chunkRequestsPerPeer, syncer.chunkRetryBudget))
+
+	// The chunk is requested once, then retried twice from alternate peers,
+	// before giving up.
+	_, err = chunks.Next()
+	assert.Equal(t, errTimeout, err)
+	requestsMtx.Lock()
+	defer requestsMtx.Unlock()
+	require.Len(t, requests, 3)
+	assert.NotEqual(t, requests[0], requests[1])
+	assert.NotEqual(t, requests[1], requests[2])
+}
@@ -0,0 +1,197 @@
+package statesync
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	cmtsync "github.com/cometbft/cometbft/libs/sync"
+	"github.com/cometbft/cometbft/p2p"
+	ssproto "github.com/cometbft/cometbft/proto/tendermint/statesync"
+)
+
+const (
+	// maxServedSnapshots is the number of served snapshots whose chunk requests are tracked. It
+	// bounds the snapshots in a usage report.
+	maxServedSnapshots = 10 * recentSnapshots
+	// maxUsagePeers is the number of distinct peers counted for a snapshot in a usage report.
+	maxUsagePeers = 1000
+	// unservedFormat is the format label of the requests for chunks of snapshots we don't serve,
+	// so that peers can't create arbitrary label values.
+	unservedFormat = "unserved"
+)
+
+// SnapshotUsageHook is notified periodically of the local snapshots requested by peers, so that
+// the application can prune the snapshots, or formats, which peers do not use. It is called from
+// a dedicated goroutine, and should not block.
+//
+// WARNING: this interface is considered unstable and subject to change.
+type SnapshotUsageHook interface {
+	ReportSnapshotUsage(report SnapshotUsageReport)
+}
+
+// SnapshotUsageReport is the usage of the local snapshots by peers since the previous report.
+type SnapshotUsageReport struct {
+	// Start and End delimit the period of the report.
+	Start time.Time
+	End   time.Time
+	// SnapshotsRequests is the number of requests for the list of local snapshots.
+	SnapshotsRequests uint64
+	// Snapshots are the snapshots whose chunks were requested, by descending height and format.
+	// Local snapshots which are not in the report were not requested. Requests for snapshots
+	// which the node does not serve are not reported.
+	Snapshots []SnapshotUsage
+}
+
+// SnapshotUsage is the usage of a snapshot by peers.
+type SnapshotUsage struct {
+	Height uint64
+	Format uint32
+	// ChunkRequests is the number of chunks requested.
+	ChunkRequests uint64
+	// MissingChunks is the number of chunks requested which the application did not have, e.g.
+	// because the snapshot was pruned.
+	MissingChunks uint64
+	// Peers is the number of distinct peers which requested chunks, up to maxUsagePeers.
+	Peers int
+}
+
+// usageKey identifies a snapshot by height and format, as requested by peers.
+type usageKey struct {
+	height uint64
+	format uint32
+}
+
+// snapshotUsage tracks the requests of peers for the local snapshots, until reported. Only the
+// requests for snapshots we serve are tracked, i.e. those advertised to peers or whose chunks the
+// application loaded, as the heights and formats of the requests are chosen by peers.
+type snapshotUsage struct {
+	mtx               cmtsync.Mutex
+	start             time.Time
+	snapshotsRequests uint64
+	snapshots         map[usageKey]*SnapshotUsage
+	peers             map[usageKey]map[p2p.ID]bool
+	served            map[usageKey]bool // not reset by reports
+}
+
+// newSnapshotUsage creates a snapshot usage tracker, with no requests.
+func newSnapshotUsage() *snapshotUsage {
+	u := &snapshotUsage{served: make(map[usageKey]bool)}
+	u.reset(time.Now())
+	return u
+}
+
+// reset drops the tracked requests, starting a new report. The caller must hold the mutex lock.
+func (u *snapshotUsage) reset(start time.Time) {
+	u.start = start
+	u.snapshotsRequests = 0
+	u.snapshots = make(map[usageKey]*SnapshotUsage)
+	u.peers = make(map[usageKey]map[p2p.ID]bool)
+}
+
+// snapshotsRequested records a request for the list of local snapshots.
+func (u *snapshotUsage) snapshotsRequested() {
+	u.mtx.Lock()
+	defer u.mtx.Unlock()
+	u.snapshotsRequests++
+}
+
+// snapshotsServed records the snapshots advertised to peers.
+func (u *snapshotUsage) snapshotsServed(snapshots []*snapshot) {
+	u.mtx.Lock()
+	defer u.mtx.Unlock()
+	for _, s := range snapshots {
+		u.serve(usageKey{height: s.Height, format: s.Format})
+	}
+}
+
+// serve records a served snapshot. Once maxServedSnapshots are recorded, the previously served
+// snapshots are forgotten. The caller must hold the mutex lock.
+func (u *snapshotUsage) serve(key usageKey) {
+	if !u.served[key] && len(u.served) >= maxServedSnapshots {
+		u.served = make(map[usageKey]bool)
+	}
+	u.served[key] = true
+}
+
+// chunkRequested records a request of the peer for a chunk of the snapshot with the given height
+// and format, which was missing if the application did not have it. It returns false if the
+// request was not recorded, because we don't serve the snapshot.
+func (u *snapshotUsage) chunkRequested(peerID p2p.ID, height uint64, format uint32, missing bool) bool {
+	u.mtx.Lock()
+	defer u.mtx.Unlock()
+	key := usageKey{height: height, format: format}
+	if !missing {
+		u.serve(key)
+	}
+	if !u.served[key] {
+		return false
+	}
+	usage := u.snapshots[key]
+	if usage == nil {
+		usage = &SnapshotUsage{Height: height, Format: format}
+		u.snapshots[key] = usage
+		u.peers[key] = make(map[p2p.ID]bool)
+	}
+	usage.ChunkRequests++
+	if missing {
+		usage.MissingChunks++
+	}
+	if len(u.peers[key]) < maxUsagePeers {
+		u.peers[key][peerID] = true
+		usage.Peers = len(u.peers[key])
+	}
+	return true
+}
+
+// report returns the report of the requests tracked until end, and starts a new one.
+func (u *snapshotUsage) report(end time.Time) SnapshotUsageReport {
+	u.mtx.Lock()
+	defer u.mtx.Unlock()
+	report := SnapshotUsageReport{
+		Start:             u.start,
+		End:               end,
+		SnapshotsRequests: u.snapshotsRequests,
+		Snapshots:         make([]SnapshotUsage, 0, len(u.snapshots)),
+	}
+	for _, usage := range u.snapshots {
+		report.Snapshots = append(report.Snapshots, *usage)
+	}
+	sort.Slice(report.Snapshots, func(i, j int) bool {
+		a, b := report.Snapshots[i], report.Snapshots[j]
+		return a.Height > b.Height || (a.Height == b.Height && a.Format > b.Format)
+	})
+	u.reset(end)
+	return report
+}
+
+// SetSnapshotUsageHook sets the hook notified of the local snapshots requested by peers, every
+// snapshot_usage_interval. It must be called before the reactor is started.
+func (r *Reactor) SetSnapshotUsageHook(hook SnapshotUsageHook) {
+	r.usageHook = hook
+}
+
+// chunkRequested records the request of the peer for a chunk, in the snapshot usage and metrics.
+// The requests for snapshots we don't serve are labeled with unservedFormat.
+func (r *Reactor) chunkRequested(peerID p2p.ID, msg *ssproto.ChunkRequest, missing bool) {
+	format := unservedFormat
+	if r.usage.chunkRequested(peerID, msg.Height, msg.Format, missing) {
+		format = strconv.FormatUint(uint64(msg.Format), 10)
+	}
+	r.metrics.ChunkRequests.With("format", format, "missing", strconv.FormatBool(missing)).Add(1)
+}
+
+// reportSnapshotUsageRoutine reports the snapshot usage to the hook every interval, until the
+// reactor is stopped.
+func (r *Reactor) reportSnapshotUsageRoutine(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			r.usageHook.ReportSnapshotUsage(r.usage.report(now))
+		case <-r.Quit():
+			return
+		}
+	}
+}
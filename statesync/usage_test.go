@@ -0,0 +1,139 @@
+package statesync
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/cometbft/cometbft/config"
+	"github.com/cometbft/cometbft/p2p"
+	p2pmocks "github.com/cometbft/cometbft/p2p/mocks"
+	ssproto "github.com/cometbft/cometbft/proto/tendermint/statesync"
+	proxymocks "github.com/cometbft/cometbft/proxy/mocks"
+)
+
+// testUsageHook collects the snapshot usage reports.
+type testUsageHook struct {
+	mtx     sync.Mutex
+	reports []SnapshotUsageReport
+}
+
+func (h *testUsageHook) ReportSnapshotUsage(report SnapshotUsageReport) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	h.reports = append(h.reports, report)
+}
+
+func TestSnapshotUsage_report(t *testing.T) {
+	u := newSnapshotUsage()
+	start := u.start
+	u.snapshotsRequested()
+	u.snapshotsServed([]*snapshot{{Height: 1, Format: 2}})
+	assert.True(t, u.chunkRequested("a", 1, 1, false))
+	assert.True(t, u.chunkRequested("a", 1, 1, false))
+	assert.True(t, u.chunkRequested("b", 1, 1, true))
+	assert.True(t, u.chunkRequested("a", 2, 1, false))
+	assert.True(t, u.chunkRequested("a", 1, 2, true))
+	// Missing chunks of snapshots we don't serve are not recorded.
+	assert.False(t, u.chunkRequested("a", 3, 1, true))
+	assert.False(t, u.chunkRequested("a", 1, 9, true))
+
+	end := start.Add(time.Minute)
+	assert.Equal(t, SnapshotUsageReport{
+		Start:             start,
+		End:               end,
+		SnapshotsRequests: 1,
+		Snapshots: []SnapshotUsage{
+			{Height: 2, Format: 1, ChunkRequests: 1, Peers: 1},
+			{Height: 1, Format: 2, ChunkRequests: 1, MissingChunks: 1, Peers: 1},
+			{Height: 1, Format: 1, ChunkRequests: 3, MissingChunks: 1, Peers: 2},
+		},
+	}, u.report(end))
+
+	// The next report starts from scratch.
+	assert.Equal(t, SnapshotUsageReport{
+		Start:     end,
+		End:       end.Add(time.Minute),
+		Snapshots: []SnapshotUsage{},
+	}, u.report(end.Add(time.Minute)))
+}
+
+func TestSnapshotUsage_bounded(t *testing.T) {
+	u := newSnapshotUsage()
+	for height := uint64(1); height <= 2*maxServedSnapshots; height++ {
+		u.snapshotsServed([]*snapshot{{Height: height, Format: 1}})
+		assert.LessOrEqual(t, len(u.served), maxServedSnapshots)
+	}
+	assert.False(t, u.chunkRequested("a", 1, 1, true))
+	assert.True(t, u.chunkRequested("a", 2*maxServedSnapshots, 1, true))
+
+	for i := 0; i < 2*maxUsagePeers; i++ {
+		u.chunkRequested(p2p.ID(strconv.Itoa(i)), 2*maxServedSnapshots, 1, false)
+	}
+	report := u.report(time.Now())
+	require.Len(t, report.Snapshots, 1)
+	assert.EqualValues(t, 2*maxUsagePeers+1, report.Snapshots[0].ChunkRequests)
+	assert.Equal(t, maxUsagePeers, report.Snapshots[0].Peers)
+}
+
+func TestReactor_SnapshotUsageHook(t *testing.T) {
+	conn := &proxymocks.AppConnSnapshot{}
+	conn.On("ListSnapshots", mock.Anything, &abci.RequestListSnapshots{}).
+		Return(&abci.ResponseListSnapshots{}, nil)
+	conn.On("LoadSnapshotChunk", mock.Anything, &abci.RequestLoadSnapshotChunk{Height: 1, Format: 1, Chunk: 0}).
+		Return(&abci.ResponseLoadSnapshotChunk{Chunk: []byte{1}}, nil)
+	conn.On("LoadSnapshotChunk", mock.Anything, &abci.RequestLoadSnapshotChunk{Height: 1, Format: 1, Chunk: 1}).
+		Return(nil, errors.New("failed to load chunk"))
+
+	peer := &p2pmocks.Peer{}
+	peer.On("ID").Return(p2p.ID("id"))
+	peer.On("Send", mock.Anything).Return(true)
+
+	cfg := config.DefaultStateSyncConfig()
+	cfg.SnapshotUsageInterval = 10 * time.Millisecond
+	r, err := NewReactor(*cfg, conn, nil, NopMetrics())
+	require.NoError(t, err)
+	hook := &testUsageHook{}
+	r.SetSnapshotUsageHook(hook)
+
+	require.NoError(t, r.Start())
+	t.Cleanup(func() {
+		if err := r.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+	r.Receive(p2p.Envelope{ChannelID: SnapshotChannel, Src: peer, Message: &ssproto.SnapshotsRequest{}})
+	for index := uint32(0); index < 2; index++ {
+		r.Receive(p2p.Envelope{
+			ChannelID: ChunkChannel,
+			Src:       peer,
+			Message:   &ssproto.ChunkRequest{Height: 1, Format: 1, Index: index},
+		})
+	}
+
+	// The requests may span several reports.
+	var snapshotsRequests, chunkRequests, missingChunks uint64
+	require.Eventually(t, func() bool {
+		hook.mtx.Lock()
+		defer hook.mtx.Unlock()
+		snapshotsRequests, chunkRequests, missingChunks = 0, 0, 0
+		for _, report := range hook.reports {
+			snapshotsRequests += report.SnapshotsRequests
+			for _, usage := range report.Snapshots {
+				assert.EqualValues(t, 1, usage.Height)
+				assert.EqualValues(t, 1, usage.Format)
+				chunkRequests += usage.ChunkRequests
+				missingChunks += usage.MissingChunks
+			}
+		}
+		return snapshotsRequests == 1 && chunkRequests == 2
+	}, time.Second, 10*time.Millisecond)
+	assert.EqualValues(t, 1, missingChunks)
+}
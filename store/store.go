@@ -546,12 +546,23 @@ func (bs *BlockStore) saveBlockToBatch(
 		panic("BlockStore can only save a non-nil block")
 	}
 
+	if g, w := block.Height, bs.Height()+1; bs.Base() > 0 && g != w {
+		return fmt.Errorf("BlockStore can only save contiguous blocks. Wanted %v, got %v", w, g)
+	}
+	return bs.writeBlockToBatch(block, blockParts, seenCommit, batch)
+}
+
+// writeBlockToBatch writes the given block, blockParts, and seenCommit to the batch, wherever
+// the block is relative to the blocks of the store.
+func (bs *BlockStore) writeBlockToBatch(
+	block *types.Block,
+	blockParts *types.PartSet,
+	seenCommit *types.Commit,
+	batch dbm.Batch,
+) error {
 	height := block.Height
 	hash := block.Hash()
 
-	if g, w := height, bs.Height()+1; bs.Base() > 0 && g != w {
-		return fmt.Errorf("BlockStore can only save contiguous blocks. Wanted %v, got %v", w, g)
-	}
 	if !blockParts.IsComplete() {
 		return errors.New("BlockStore can only save complete block part sets")
 	}
@@ -604,6 +615,33 @@ func (bs *BlockStore) saveBlockToBatch(
 	return nil
 }
 
+// SaveBackfilledBlock persists the given block, blockParts, and seenCommit to the underlying db,
+// below the base of the store, e.g. to backfill the blocks below the height a node state synced
+// to. The block must be at the height below the base of the store, or the store must be empty.
+func (bs *BlockStore) SaveBackfilledBlock(block *types.Block, blockParts *types.PartSet, seenCommit *types.Commit) error {
+	if block == nil {
+		return errors.New("BlockStore can only save a non-nil block")
+	}
+	if g, w := block.Height, bs.Base()-1; bs.Base() > 0 && g != w {
+		return fmt.Errorf("BlockStore can only backfill the block below its base. Wanted %v, got %v", w, g)
+	}
+
+	batch := bs.db.NewBatch()
+	defer batch.Close()
+
+	if err := bs.writeBlockToBatch(block, blockParts, seenCommit, batch); err != nil {
+		return err
+	}
+
+	bs.mtx.Lock()
+	defer bs.mtx.Unlock()
+	bs.base = block.Height
+	if bs.height == 0 {
+		bs.height = block.Height
+	}
+	return bs.saveStateAndWriteDB(batch, "failed to save backfilled block")
+}
+
 func (bs *BlockStore) saveBlockPart(height int64, index int, part *types.Part, batch dbm.Batch, saveBlockPartsToBatch bool) {
 	pbp, err := part.ToProto()
 	if err != nil {
@@ -641,6 +641,56 @@ func TestPruneBlocks(t *testing.T) {
 	assert.Nil(t, bs.LoadBlock(1501))
 }
 
+func TestSaveBackfilledBlock(t *testing.T) {
+	state, bs, cleanup := makeStateAndBlockStore()
+	defer cleanup()
+
+	// backfill an empty store
+	block := makeUniqueBlock(10, state, new(types.Commit))
+	partSet, err := block.MakePartSet(types.BlockPartSizeBytes)
+	require.NoError(t, err)
+	require.NoError(t, bs.SaveBackfilledBlock(block, partSet, makeTestExtCommit(10, cmttime.Now()).ToCommit()))
+	assert.EqualValues(t, 10, bs.Base())
+	assert.EqualValues(t, 10, bs.Height())
+
+	// backfill below the base
+	for h := int64(9); h >= 8; h-- {
+		block := makeUniqueBlock(h, state, new(types.Commit))
+		partSet, err := block.MakePartSet(types.BlockPartSizeBytes)
+		require.NoError(t, err)
+		require.NoError(t, bs.SaveBackfilledBlock(block, partSet, makeTestExtCommit(h, cmttime.Now()).ToCommit()))
+	}
+	assert.EqualValues(t, 8, bs.Base())
+	assert.EqualValues(t, 10, bs.Height())
+	assert.EqualValues(t, 3, bs.Size())
+	for h := int64(8); h <= 10; h++ {
+		require.NotNil(t, bs.LoadBlock(h))
+		require.NotNil(t, bs.LoadSeenCommit(h))
+	}
+
+	// the base and height are persisted
+	bs = NewBlockStore(bs.db)
+	assert.EqualValues(t, 8, bs.Base())
+	assert.EqualValues(t, 10, bs.Height())
+
+	// blocks which are not below the base are rejected
+	for _, h := range []int64{6, 8, 11} {
+		block := makeUniqueBlock(h, state, new(types.Commit))
+		partSet, err := block.MakePartSet(types.BlockPartSizeBytes)
+		require.NoError(t, err)
+		require.Error(t, bs.SaveBackfilledBlock(block, partSet, makeTestExtCommit(h, cmttime.Now()).ToCommit()))
+	}
+	assert.EqualValues(t, 8, bs.Base())
+
+	// new blocks are still saved above the height
+	block = makeUniqueBlock(11, state, new(types.Commit))
+	partSet, err = block.MakePartSet(types.BlockPartSizeBytes)
+	require.NoError(t, err)
+	bs.SaveBlock(block, partSet, makeTestExtCommit(11, cmttime.Now()).ToCommit())
+	assert.EqualValues(t, 8, bs.Base())
+	assert.EqualValues(t, 11, bs.Height())
+}
+
 func TestLoadBlockMeta(t *testing.T) {
 	bs, db := newInMemoryBlockStore()
 	height := int64(10)
@@ -437,7 +437,10 @@ func (app *Application) PrepareProposal(
 		time.Sleep(app.cfg.PrepareProposalDelay)
 	}
 
-	return &abci.ResponsePrepareProposal{Txs: txs}, nil
+	// Declare the size of the square the txs and blobs are split into, which
+	// the nodes check against the block data.
+	data := cmttypes.Data{Txs: cmttypes.ToTxs(txs)}
+	return &abci.ResponsePrepareProposal{Txs: txs, SquareSize: data.MinSquareSize()}, nil
 }
 
 // ProcessProposal implements part of the Application interface.
@@ -580,7 +583,7 @@ func (app *Application) checkHeightAndExtensions(isPrepareProcessProposal bool,
 		currentHeight-- // at exactly voteExtHeight, PrepareProposal still has no extensions, see RFC100
 	}
 
-	return appHeight, voteExtHeight != 0 && currentHeight >= voteExtHeight
+	return appHeight, cmttypes.VoteExtensionsEnabled(currentHeight, voteExtHeight)
 }
 
 func (app *Application) storeValidator(valUpdate *abci.ValidatorUpdate) error {
@@ -624,8 +627,14 @@ func (app *Application) validatorUpdates(height uint64) (abci.ValidatorUpdates,
 	return valUpdates, nil
 }
 
-// parseTx parses a tx in 'key=value' format into a key and value.
+// parseTx parses a tx in 'key=value' format, or a blob tx wrapping one, into a
+// key and value.
 func parseTx(tx []byte) (string, string, error) {
+	// The blobs of a blob transaction are published as is; its inner
+	// transaction carries the key and value.
+	if blobTx, isBlob := cmttypes.UnmarshalBlobTx(tx); isBlob {
+		tx = blobTx.Tx
+	}
 	parts := bytes.Split(tx, []byte("="))
 	if len(parts) != 2 {
 		return "", "", fmt.Errorf("invalid tx format: %q", string(tx))
@@ -5,6 +5,7 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"strings"
 	"text/template"
 
 	e2e "github.com/cometbft/cometbft/test/e2e/pkg"
@@ -47,6 +48,23 @@ func (p Provider) StopTestnet(ctx context.Context) error {
 	return ExecCompose(ctx, p.Testnet.Dir, "down")
 }
 
+func (p Provider) NodeLogs(ctx context.Context, node *e2e.Node) ([]byte, error) {
+	return ExecComposeOutput(ctx, p.Testnet.Dir, "logs", "--no-color", "--no-log-prefix", node.Name)
+}
+
+func (p Provider) RunningNodes(ctx context.Context) ([]string, error) {
+	out, err := ExecComposeOutput(ctx, p.Testnet.Dir, "ps", "--services", "--filter", "status=running")
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, service := range strings.Fields(string(out)) {
+		// Upgraded nodes run as an alternate service.
+		names = append(names, strings.TrimSuffix(service, "_u"))
+	}
+	return names, nil
+}
+
 // dockerComposeBytes generates a Docker Compose config file for a testnet and returns the
 // file as bytes to be written out to disk.
 func dockerComposeBytes(testnet *e2e.Testnet) ([]byte, error) {
@@ -76,6 +94,10 @@ services:
     entrypoint: /usr/bin/entrypoint-builtin
 {{- end }}
     init: true
+{{- if .HasPerturbation "netem" }}
+    cap_add:
+    - NET_ADMIN
+{{- end }}
     ports:
     - 26656
     - {{ if .ProxyPort }}{{ .ProxyPort }}:{{ end }}26657
@@ -100,6 +122,10 @@ services:
     entrypoint: /usr/bin/entrypoint-builtin
 {{- end }}
     init: true
+{{- if .HasPerturbation "netem" }}
+    cap_add:
+    - NET_ADMIN
+{{- end }}
     ports:
     - 26656
     - {{ if .ProxyPort }}{{ .ProxyPort }}:{{ end }}26657
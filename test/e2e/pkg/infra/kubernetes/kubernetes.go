@@ -0,0 +1,224 @@
+package kubernetes
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	e2e "github.com/cometbft/cometbft/test/e2e/pkg"
+	"github.com/cometbft/cometbft/test/e2e/pkg/exec"
+	"github.com/cometbft/cometbft/test/e2e/pkg/infra"
+)
+
+var _ infra.Provider = (*Provider)(nil)
+
+// Provider implements an infrastructure provider that runs each node as a
+// single-replica StatefulSet in a Kubernetes cluster, driving the cluster
+// with kubectl and its current context.
+//
+// Each node is exposed by a Service whose cluster IP is the node's IP address
+// in the infrastructure data, so that the nodes reach each other as in the
+// other providers, and whose RPC node port is the node's port, so that the
+// runner reaches the node on its external IP address, one of the cluster's
+// node addresses. The files of each node are shipped as a ConfigMap, extracted
+// into the node's home directory when its pod is created.
+type Provider struct {
+	infra.ProviderData
+}
+
+// Setup generates the Kubernetes manifest of the testnet and writes it to
+// disk.
+func (p *Provider) Setup() error {
+	manifest, err := kubernetesManifestBytes(p.Testnet)
+	if err != nil {
+		return err
+	}
+	//nolint: gosec
+	// G306: Expect WriteFile permissions to be 0600 or less
+	return os.WriteFile(filepath.Join(p.Testnet.Dir, "kubernetes.yml"), manifest, 0o644)
+}
+
+// StartNodes creates the ConfigMap holding the files of each node and applies
+// the node's objects of the manifest. If no nodes are passed, the whole
+// network is started.
+func (p Provider) StartNodes(ctx context.Context, nodes ...*e2e.Node) error {
+	if len(nodes) == 0 {
+		nodes = p.Testnet.Nodes
+	}
+	for _, node := range nodes {
+		if err := p.createFiles(ctx, node); err != nil {
+			return fmt.Errorf("node %v: %w", node.Name, err)
+		}
+		err := p.kubectl(ctx, "apply", "-f", filepath.Join(p.Testnet.Dir, "kubernetes.yml"),
+			"-l", "e2e-testnet="+p.Testnet.Name+",e2e-node="+node.Name)
+		if err != nil {
+			return fmt.Errorf("node %v: %w", node.Name, err)
+		}
+	}
+	return nil
+}
+
+// StopTestnet deletes all the objects of the testnet from the cluster.
+func (p Provider) StopTestnet(ctx context.Context) error {
+	return p.kubectl(ctx, "delete", "statefulsets,services,configmaps",
+		"-l", "e2e-testnet="+p.Testnet.Name, "--ignore-not-found", "--wait")
+}
+
+// NodeLogs returns the logs of the node's pod.
+func (p Provider) NodeLogs(ctx context.Context, node *e2e.Node) ([]byte, error) {
+	return p.kubectlOutput(ctx, "logs", "statefulset/"+node.Name, "-c", "node")
+}
+
+// RunningNodes returns the names of the nodes whose pod is running.
+func (p Provider) RunningNodes(ctx context.Context) ([]string, error) {
+	out, err := p.kubectlOutput(ctx, "get", "pods",
+		"-l", "e2e-testnet="+p.Testnet.Name, "--field-selector", "status.phase=Running",
+		"-o", `jsonpath={range .items[*]}{.metadata.labels.e2e-node}{"\n"}{end}`)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(out)), nil
+}
+
+// createFiles (re)creates the ConfigMap holding an archive of the node's home
+// directory, which the node's pod extracts when it is created.
+func (p Provider) createFiles(ctx context.Context, node *e2e.Node) error {
+	archive := filepath.Join(p.Testnet.Dir, node.Name+".tar")
+	if err := exec.Command(ctx, "tar", "-cf", archive, "-C", filepath.Join(p.Testnet.Dir, node.Name), "."); err != nil {
+		return err
+	}
+	defer os.Remove(archive)
+
+	name := node.Name + "-files"
+	if err := p.kubectl(ctx, "delete", "configmap", name, "--ignore-not-found"); err != nil {
+		return err
+	}
+	if err := p.kubectl(ctx, "create", "configmap", name, "--from-file=node.tar="+archive); err != nil {
+		return err
+	}
+	return p.kubectl(ctx, "label", "configmap", name,
+		"e2e=true", "e2e-testnet="+p.Testnet.Name, "e2e-node="+node.Name)
+}
+
+// kubectl runs a kubectl command in the namespace of the infrastructure data.
+func (p Provider) kubectl(ctx context.Context, args ...string) error {
+	return exec.Command(ctx, p.kubectlArgs(args)...)
+}
+
+// kubectlOutput runs a kubectl command in the namespace of the infrastructure
+// data and returns the command's output.
+func (p Provider) kubectlOutput(ctx context.Context, args ...string) ([]byte, error) {
+	return exec.CommandOutput(ctx, p.kubectlArgs(args)...)
+}
+
+func (p Provider) kubectlArgs(args []string) []string {
+	cmd := []string{"kubectl"}
+	if p.InfrastructureData.Namespace != "" {
+		cmd = append(cmd, "-n", p.InfrastructureData.Namespace)
+	}
+	return append(cmd, args...)
+}
+
+// kubernetesManifestBytes generates the Kubernetes manifest of a testnet, with
+// a Service and a StatefulSet per node, and returns it as bytes to be written
+// out to disk.
+func kubernetesManifestBytes(testnet *e2e.Testnet) ([]byte, error) {
+	tmpl, err := template.New("kubernetes").Parse(`
+{{- range .Nodes }}
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: {{ .Name }}
+  labels:
+    e2e: "true"
+    e2e-testnet: {{ $.Name }}
+    e2e-node: {{ .Name }}
+spec:
+  type: NodePort
+  clusterIP: {{ .InternalIP }}
+  selector:
+    e2e-testnet: {{ $.Name }}
+    e2e-node: {{ .Name }}
+  ports:
+  - name: p2p
+    port: 26656
+  - name: rpc
+    port: 26657
+{{- if .ProxyPort }}
+    nodePort: {{ .ProxyPort }}
+{{- end }}
+  - name: prometheus
+    port: 26660
+  - name: pprof
+    port: 6060
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: {{ .Name }}
+  labels:
+    e2e: "true"
+    e2e-testnet: {{ $.Name }}
+    e2e-node: {{ .Name }}
+spec:
+  serviceName: {{ .Name }}
+  replicas: 1
+  selector:
+    matchLabels:
+      e2e-testnet: {{ $.Name }}
+      e2e-node: {{ .Name }}
+  template:
+    metadata:
+      labels:
+        e2e: "true"
+        e2e-testnet: {{ $.Name }}
+        e2e-node: {{ .Name }}
+    spec:
+      initContainers:
+      - name: files
+        image: {{ .Version }}
+        command: ["tar", "-xf", "/files/node.tar", "-C", "/cometbft"]
+        volumeMounts:
+        - name: files
+          mountPath: /files
+        - name: home
+          mountPath: /cometbft
+      containers:
+      - name: node
+        image: {{ .Version }}
+{{- if or (eq .ABCIProtocol "builtin") (eq .ABCIProtocol "builtin_connsync") }}
+        command: ["/usr/bin/entrypoint-builtin"]
+{{- end }}
+        ports:
+        - containerPort: 26656
+        - containerPort: 26657
+        - containerPort: 26660
+        - containerPort: 6060
+        volumeMounts:
+        - name: home
+          mountPath: /cometbft
+        - name: home
+          mountPath: /tendermint
+      volumes:
+      - name: files
+        configMap:
+          name: {{ .Name }}-files
+      - name: home
+        emptyDir: {}
+{{- end }}
+`)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, testnet)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
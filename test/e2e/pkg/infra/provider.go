@@ -22,6 +22,12 @@ type Provider interface {
 	// Stops the whole network
 	StopTestnet(context.Context) error
 
+	// Returns the logs of the node passed as parameter
+	NodeLogs(context.Context, *e2e.Node) ([]byte, error)
+
+	// Returns the names of the nodes that are currently running
+	RunningNodes(context.Context) ([]string, error)
+
 	// Returns the the provider's infrastructure data
 	GetInfrastructureData() *e2e.InfrastructureData
 }
@@ -0,0 +1,182 @@
+package ssh
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	e2e "github.com/cometbft/cometbft/test/e2e/pkg"
+	"github.com/cometbft/cometbft/test/e2e/pkg/exec"
+	"github.com/cometbft/cometbft/test/e2e/pkg/infra"
+)
+
+var _ infra.Provider = (*Provider)(nil)
+
+// Provider implements an infrastructure provider that runs each node as a
+// Docker container on the remote host given by its instance in the
+// infrastructure data, driving the hosts over SSH.
+//
+// The hosts must accept non-interactive SSH connections from the runner as
+// the infrastructure data's SSH user, and be able to run the node images.
+// Nodes are published on the same ports as with the docker provider, so the
+// instance ports must be free on the hosts.
+type Provider struct {
+	infra.ProviderData
+}
+
+// Setup implements infra.Provider. There is nothing to generate, as the node
+// files are copied to the hosts when the nodes are started.
+func (p *Provider) Setup() error {
+	return nil
+}
+
+// StartNodes copies the files of each node to its host and starts its
+// container there. If no nodes are passed, the whole network is started.
+func (p Provider) StartNodes(ctx context.Context, nodes ...*e2e.Node) error {
+	if len(nodes) == 0 {
+		nodes = p.Testnet.Nodes
+	}
+	for _, node := range nodes {
+		host, err := p.host(node)
+		if err != nil {
+			return err
+		}
+		if err := Exec(ctx, host, "mkdir", "-p", p.Testnet.Name); err != nil {
+			return fmt.Errorf("node %v: %w", node.Name, err)
+		}
+		err = exec.Command(ctx, append(sshOptions("scp"), "-r", "-q",
+			filepath.Join(p.Testnet.Dir, node.Name), scpHost(host)+":"+p.Testnet.Name+"/")...)
+		if err != nil {
+			return fmt.Errorf("node %v: %w", node.Name, err)
+		}
+		if err := Exec(ctx, host, p.dockerRunArgs(node)...); err != nil {
+			return fmt.Errorf("node %v: %w", node.Name, err)
+		}
+	}
+	return nil
+}
+
+// StopTestnet removes the containers and the files of all the nodes from
+// their hosts. Nodes that were not started are skipped, so that it can clean
+// up after a previous run before starting the testnet.
+func (p Provider) StopTestnet(ctx context.Context) error {
+	for _, node := range p.Testnet.Nodes {
+		host, err := p.host(node)
+		if err != nil {
+			return err
+		}
+		err = Exec(ctx, host, "docker", "ps", "-aq", "--filter", "name=^"+node.Name+"$",
+			"|", "xargs", "-r", "docker", "rm", "-f")
+		if err != nil {
+			return fmt.Errorf("node %v: %w", node.Name, err)
+		}
+		if err := Exec(ctx, host, "rm", "-rf", p.Testnet.Name+"/"+node.Name); err != nil {
+			return fmt.Errorf("node %v: %w", node.Name, err)
+		}
+	}
+	return nil
+}
+
+// NodeLogs returns the logs of the node's container.
+func (p Provider) NodeLogs(ctx context.Context, node *e2e.Node) ([]byte, error) {
+	host, err := p.host(node)
+	if err != nil {
+		return nil, err
+	}
+	return ExecOutput(ctx, host, "docker", "logs", node.Name)
+}
+
+// RunningNodes returns the names of the nodes whose container is running on
+// their host.
+func (p Provider) RunningNodes(ctx context.Context) ([]string, error) {
+	var names []string
+	for _, node := range p.Testnet.Nodes {
+		host, err := p.host(node)
+		if err != nil {
+			return nil, err
+		}
+		out, err := ExecOutput(ctx, host, "docker", "ps", "-q",
+			"--filter", "name=^"+node.Name+"$", "--filter", "status=running")
+		if err != nil {
+			return nil, fmt.Errorf("node %v: %w", node.Name, err)
+		}
+		if len(strings.TrimSpace(string(out))) > 0 {
+			names = append(names, node.Name)
+		}
+	}
+	return names, nil
+}
+
+// host returns the SSH destination of the host running the node.
+func (p Provider) host(node *e2e.Node) (string, error) {
+	instance, ok := p.InfrastructureData.Instances[node.Name]
+	if !ok {
+		return "", fmt.Errorf("information for node %q missing from infrastructure data", node.Name)
+	}
+	ip := instance.ExtIPAddress
+	if len(ip) == 0 {
+		ip = instance.IPAddress
+	}
+	if p.InfrastructureData.SSHUser == "" {
+		return ip.String(), nil
+	}
+	return p.InfrastructureData.SSHUser + "@" + ip.String(), nil
+}
+
+// dockerRunArgs returns the command starting the node's container on its
+// host, mirroring the services of the docker provider's compose file.
+func (p Provider) dockerRunArgs(node *e2e.Node) []string {
+	// The command is run by the remote shell, which expands $HOME.
+	dir := fmt.Sprintf("$HOME/%v/%v", p.Testnet.Name, node.Name)
+	args := []string{
+		"docker", "run", "-d", "--init",
+		"--name", node.Name,
+		"--label", "e2e=true",
+		"-p", "26656:26656",
+		"-p", fmt.Sprintf("%d:26657", node.ProxyPort),
+	}
+	if node.PrometheusProxyPort > 0 {
+		args = append(args, "-p", fmt.Sprintf("%d:26660", node.PrometheusProxyPort))
+	}
+	if node.ABCIProtocol == e2e.ProtocolBuiltin || node.ABCIProtocol == e2e.ProtocolBuiltinConnSync {
+		args = append(args, "--entrypoint", "/usr/bin/entrypoint-builtin")
+	}
+	return append(args,
+		"-v", dir+":/cometbft",
+		"-v", dir+":/tendermint",
+		node.Version,
+	)
+}
+
+// sshOptions returns the given SSH command with the options used to connect
+// to the hosts without prompting.
+func sshOptions(cmd string) []string {
+	return []string{cmd, "-o", "BatchMode=yes", "-o", "StrictHostKeyChecking=accept-new"}
+}
+
+// scpHost returns the host in the form expected by scp, which requires IPv6
+// addresses to be bracketed.
+func scpHost(host string) string {
+	user, ip, ok := strings.Cut(host, "@")
+	if !ok {
+		user, ip = "", host
+	}
+	if strings.Contains(ip, ":") {
+		ip = "[" + ip + "]"
+	}
+	if user == "" {
+		return ip
+	}
+	return user + "@" + ip
+}
+
+// Exec runs a command on a remote host.
+func Exec(ctx context.Context, host string, args ...string) error {
+	return exec.Command(ctx, append(append(sshOptions("ssh"), host), args...)...)
+}
+
+// ExecOutput runs a command on a remote host and returns the command's output.
+func ExecOutput(ctx context.Context, host string, args ...string) ([]byte, error) {
+	return exec.CommandOutput(ctx, append(append(sshOptions("ssh"), host), args...)...)
+}
@@ -1,14 +1,18 @@
 package e2e
 
 import (
+	"encoding/json"
 	"fmt"
 	"net"
+	"os"
 	"sort"
 )
 
 const (
 	dockerIPv4CIDR = "10.186.73.0/24"
 	dockerIPv6CIDR = "fd80:b10c::/48"
+
+	globalIPv4CIDR = "0.0.0.0/0"
 )
 
 // InfrastructureData contains the relevant information for a set of existing
@@ -16,8 +20,8 @@ const (
 type InfrastructureData struct {
 	Path string
 
-	// Provider is the name of infrastructure provider backing the testnet.
-	// Currently, only 'docker' is supported.
+	// Provider is the name of infrastructure provider backing the testnet,
+	// either 'docker', 'ssh' or 'kubernetes'.
 	Provider string `json:"provider"`
 
 	// Instances is a map of all of the machine instances on which to run
@@ -30,6 +34,15 @@ type InfrastructureData struct {
 	// IP addresses are expected to be within.
 	Network string `json:"network"`
 
+	// SSHUser is the user to log in as on the instances, for providers that
+	// reach them over SSH. If empty, the SSH client's default is used.
+	SSHUser string `json:"ssh_user,omitempty"`
+
+	// Namespace is the Kubernetes namespace to run the testnet in, for the
+	// kubernetes provider. If empty, the namespace of kubectl's current
+	// context is used.
+	Namespace string `json:"namespace,omitempty"`
+
 	// TracePushConfig is the URL of the server to push trace data to.
 	TracePushConfig string `json:"trace_push_config,omitempty"`
 
@@ -93,3 +106,22 @@ func NewDockerInfrastructureData(m Manifest) (InfrastructureData, error) {
 	}
 	return ifd, nil
 }
+
+// InfrastructureDataFromFile loads the infrastructure data describing a set of
+// existing machine instances from the JSON file at path. If the file does not
+// specify a network, any IPv4 address is accepted.
+func InfrastructureDataFromFile(path string) (InfrastructureData, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return InfrastructureData{}, err
+	}
+	ifd := InfrastructureData{}
+	if err := json.Unmarshal(bz, &ifd); err != nil {
+		return InfrastructureData{}, fmt.Errorf("invalid infrastructure data %q: %w", path, err)
+	}
+	if ifd.Network == "" {
+		ifd.Network = globalIPv4CIDR
+	}
+	ifd.Path = path
+	return ifd, nil
+}
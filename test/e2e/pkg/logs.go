@@ -0,0 +1,99 @@
+package e2e
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// LogRule is a pattern that the logs of a node must not contain or, if
+// Required is set, must contain. Patterns are matched against each line as
+// is, so they apply to both plain and JSON logs.
+type LogRule struct {
+	Name     string
+	Pattern  *regexp.Regexp
+	Required bool
+}
+
+// ForbiddenLogRules match panics, consensus failures and data races, which no
+// node should ever log.
+var ForbiddenLogRules = []LogRule{
+	{Name: "panic", Pattern: regexp.MustCompile(`^panic: `)},
+	{Name: "consensus failure", Pattern: regexp.MustCompile(`CONSENSUS FAILURE`)},
+	{Name: "data race", Pattern: regexp.MustCompile(`^WARNING: DATA RACE`)},
+}
+
+// LogMatch is a log line matching a rule, with its 1-based line number.
+type LogMatch struct {
+	Rule string
+	Line int
+	Text string
+}
+
+// LogReport is the result of checking logs against a set of rules.
+type LogReport struct {
+	// Forbidden holds the first line matching each forbidden rule.
+	Forbidden []LogMatch
+	// Found holds the first line matching each required rule.
+	Found []LogMatch
+	// Missing holds the names of the required rules no line matched.
+	Missing []string
+}
+
+// OK returns whether no forbidden rule and all the required rules matched.
+func (r LogReport) OK() bool {
+	return len(r.Forbidden) == 0 && len(r.Missing) == 0
+}
+
+// String describes the report, one rule per line.
+func (r LogReport) String() string {
+	var sb strings.Builder
+	for _, m := range r.Forbidden {
+		fmt.Fprintf(&sb, "forbidden %v at line %d: %s\n", m.Rule, m.Line, m.Text)
+	}
+	for _, name := range r.Missing {
+		fmt.Fprintf(&sb, "required %v not found\n", name)
+	}
+	for _, m := range r.Found {
+		fmt.Fprintf(&sb, "required %v found at line %d\n", m.Rule, m.Line)
+	}
+	return sb.String()
+}
+
+// CheckLogs scans logs for the given rules, reporting the first line matching
+// each of them.
+func CheckLogs(logs io.Reader, rules []LogRule) (LogReport, error) {
+	scanner := bufio.NewScanner(logs)
+	// Lines with stack traces or large messages may exceed the default limit.
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+
+	matched := make([]*LogMatch, len(rules))
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Text()
+		for i, rule := range rules {
+			if matched[i] == nil && rule.Pattern.MatchString(line) {
+				matched[i] = &LogMatch{Rule: rule.Name, Line: lineNum, Text: line}
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return LogReport{}, err
+	}
+
+	var report LogReport
+	for i, rule := range rules {
+		switch {
+		case rule.Required && matched[i] == nil:
+			report.Missing = append(report.Missing, rule.Name)
+		case rule.Required:
+			report.Found = append(report.Found, *matched[i])
+		case matched[i] != nil:
+			report.Forbidden = append(report.Forbidden, *matched[i])
+		}
+	}
+	return report, nil
+}
@@ -56,6 +56,18 @@ type Manifest struct {
 	// testnet via the RPC endpoint of a random node. Default is 0
 	Evidence int `toml:"evidence"`
 
+	// EvidenceTypes lists the kinds of evidence to inject, cycling through
+	// them in order: "duplicate-vote", "lunatic" or "amnesia" (the last two
+	// being light client attacks). Defaults to a mix of duplicate vote and
+	// lunatic evidence.
+	EvidenceTypes []string `toml:"evidence_types"`
+
+	// EvidenceValidators lists the validators whose keys are used to forge
+	// the evidence. Lunatic evidence is only valid if they hold at least 1/3
+	// of the voting power, and amnesia evidence if they hold more than 2/3.
+	// Defaults to all validators.
+	EvidenceValidators []string `toml:"evidence_validators"`
+
 	// ABCIProtocol specifies the protocol used to communicate with the ABCI
 	// application: "unix", "tcp", "grpc", "builtin" or "builtin_connsync".
 	//
@@ -80,11 +92,38 @@ type Manifest struct {
 	// Currently only uncoordinated upgrade is supported
 	UpgradeVersion string `toml:"upgrade_version"`
 
+	// RollingUpgrade makes the nodes with an upgrade perturbation upgrade one
+	// at a time: after each upgrade, the runner waits for the network to
+	// produce a few more blocks and for all nodes, including the upgraded one,
+	// to catch up before moving on to the next node.
+	RollingUpgrade bool `toml:"rolling_upgrade"`
+
 	LoadTxSizeBytes   int `toml:"load_tx_size_bytes"`
 	LoadTxBatchSize   int `toml:"load_tx_batch_size"`
 	LoadTxConnections int `toml:"load_tx_connections"`
 	LoadMaxTxs        int `toml:"load_max_txs"`
 
+	// LoadBlobNamespaces is the number of namespaces the load generator
+	// spreads blobs across. If set, each load transaction is wrapped into a
+	// blob transaction carrying LoadBlobsPerTx blobs. Defaults to 0 (plain
+	// transactions only).
+	LoadBlobNamespaces int `toml:"load_blob_namespaces"`
+
+	// LoadBlobNamespaceDistribution sets how blobs are spread across the
+	// namespaces: "uniform" (the default), or "zipf" for a few namespaces
+	// receiving most of the blobs.
+	LoadBlobNamespaceDistribution string `toml:"load_blob_namespace_distribution"`
+
+	// LoadBlobsPerTx is the number of blobs in each blob transaction.
+	// Defaults to 1.
+	LoadBlobsPerTx int `toml:"load_blobs_per_tx"`
+
+	// LoadBlobSizeMin and LoadBlobSizeMax bound the size of each blob in
+	// bytes, which is drawn uniformly between them. They default to
+	// load_tx_size_bytes.
+	LoadBlobSizeMin int `toml:"load_blob_size_min"`
+	LoadBlobSizeMax int `toml:"load_blob_size_max"`
+
 	// LogLevel specifies the log level to be set on all nodes.
 	LogLevel string `toml:"log_level"`
 
@@ -95,6 +134,12 @@ type Manifest struct {
 	// Defaults to false (disabled).
 	Prometheus bool `toml:"prometheus"`
 
+	// MetricLimits maps Prometheus metric names to the highest value they may
+	// reach on any node, e.g. cometbft_consensus_rounds = 3. The metrics are
+	// scraped throughout the run, and the test stage fails if any of them
+	// went over its limit. Requires Prometheus to be enabled.
+	MetricLimits map[string]float64 `toml:"metric_limits"`
+
 	// BlockMaxBytes specifies the maximum size in bytes of a block. This
 	// value will be written to the genesis file of all nodes.
 	BlockMaxBytes int64 `toml:"block_max_bytes"`
@@ -189,8 +234,19 @@ type ManifestNode struct {
 	// kill:       kills the node with SIGKILL then restarts it
 	// pause:      temporarily pauses (freezes) the node
 	// restart:    restarts the node, shutting it down with SIGTERM
+	// netem:      temporarily degrades the node's network as set in Netem
+	//             and NetemLinks
 	Perturb []string `toml:"perturb"`
 
+	// Netem sets the network conditions emulated on all the traffic sent by
+	// the node during a netem perturbation.
+	Netem ManifestNetem `toml:"netem"`
+
+	// NetemLinks sets the network conditions emulated on the traffic sent by
+	// the node to the given peers during a netem perturbation, keyed by peer
+	// name. They replace the node-wide Netem settings for those peers.
+	NetemLinks map[string]ManifestNetem `toml:"netem_links"`
+
 	// SendNoLoad determines if the e2e test should send load to this node.
 	// It defaults to false so unless the configured, the node will
 	// receive load.
@@ -218,3 +274,20 @@ func LoadManifest(file string) (Manifest, error) {
 	}
 	return manifest, nil
 }
+
+// ManifestNetem sets network conditions emulated with netem. Unset fields
+// leave the corresponding condition unaffected.
+type ManifestNetem struct {
+	// Delay is the latency added to each packet, e.g. "100ms".
+	Delay string `toml:"delay"`
+
+	// Jitter is the random variation of the delay, e.g. "20ms". It requires
+	// Delay to be set.
+	Jitter string `toml:"jitter"`
+
+	// Loss is the percentage of packets dropped, between 0 and 100.
+	Loss float64 `toml:"loss"`
+
+	// Rate caps the bandwidth, in tc units, e.g. "1mbit".
+	Rate string `toml:"rate"`
+}
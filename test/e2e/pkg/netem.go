@@ -0,0 +1,74 @@
+package e2e
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// maxNetemLinks is the maximum number of links of a node with their own
+// netem settings, bounded by the number of bands of the tc prio qdisc.
+const maxNetemLinks = 15
+
+// Netem describes network conditions emulated with the Linux netem queueing
+// discipline.
+type Netem struct {
+	Delay  time.Duration
+	Jitter time.Duration
+	Loss   float64 // percentage of packets dropped
+	Rate   string  // bandwidth cap, in tc units
+}
+
+// newNetem parses netem settings from a manifest.
+func newNetem(m ManifestNetem) (Netem, error) {
+	netem := Netem{Loss: m.Loss, Rate: m.Rate}
+	var err error
+	if m.Delay != "" {
+		if netem.Delay, err = time.ParseDuration(m.Delay); err != nil {
+			return Netem{}, fmt.Errorf("invalid netem delay %q: %w", m.Delay, err)
+		}
+	}
+	if m.Jitter != "" {
+		if netem.Jitter, err = time.ParseDuration(m.Jitter); err != nil {
+			return Netem{}, fmt.Errorf("invalid netem jitter %q: %w", m.Jitter, err)
+		}
+	}
+	return netem, nil
+}
+
+// IsZero returns true if the settings leave the network unaffected.
+func (n Netem) IsZero() bool {
+	return n == Netem{}
+}
+
+// Validate validates the netem settings.
+func (n Netem) Validate() error {
+	if n.Delay < 0 || n.Jitter < 0 {
+		return errors.New("netem delay and jitter must not be negative")
+	}
+	if n.Jitter > 0 && n.Delay == 0 {
+		return errors.New("netem jitter requires a delay")
+	}
+	if n.Loss < 0 || n.Loss > 100 {
+		return fmt.Errorf("netem loss %v must be a percentage between 0 and 100", n.Loss)
+	}
+	return nil
+}
+
+// Args returns the netem qdisc arguments for tc.
+func (n Netem) Args() []string {
+	args := []string{"netem"}
+	if n.Delay > 0 {
+		args = append(args, "delay", fmt.Sprintf("%dus", n.Delay.Microseconds()))
+		if n.Jitter > 0 {
+			args = append(args, fmt.Sprintf("%dus", n.Jitter.Microseconds()))
+		}
+	}
+	if n.Loss > 0 {
+		args = append(args, "loss", fmt.Sprintf("%v%%", n.Loss))
+	}
+	if n.Rate != "" {
+		args = append(args, "rate", n.Rate)
+	}
+	return args
+}
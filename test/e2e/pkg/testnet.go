@@ -33,6 +33,10 @@ const (
 	defaultConnections = 1
 	defaultTxSizeBytes = 1024
 
+	// maxLoadBlobNamespaces is the number of distinct namespace IDs the load
+	// generator can derive.
+	maxLoadBlobNamespaces = 1000000
+
 	localVersion = "cometbft/e2e-node:local-version"
 )
 
@@ -40,6 +44,8 @@ type (
 	Mode         string
 	Protocol     string
 	Perturbation string
+	EvidenceType string
+	Distribution string
 )
 
 const (
@@ -60,6 +66,14 @@ const (
 	PerturbationPause      Perturbation = "pause"
 	PerturbationRestart    Perturbation = "restart"
 	PerturbationUpgrade    Perturbation = "upgrade"
+	PerturbationNetem      Perturbation = "netem"
+
+	EvidenceDuplicateVote EvidenceType = "duplicate-vote"
+	EvidenceLunatic       EvidenceType = "lunatic"
+	EvidenceAmnesia       EvidenceType = "amnesia"
+
+	DistributionUniform Distribution = "uniform"
+	DistributionZipf    Distribution = "zipf"
 
 	EvidenceAgeHeight int64         = 14
 	EvidenceAgeTime   time.Duration = 1500 * time.Millisecond
@@ -78,10 +92,17 @@ type Testnet struct {
 	Nodes                                                []*Node
 	KeyType                                              string
 	Evidence                                             int
+	EvidenceTypes                                        []EvidenceType
+	EvidenceValidators                                   []*Node
 	LoadTxSizeBytes                                      int
 	LoadTxBatchSize                                      int
 	LoadTxConnections                                    int
 	LoadMaxTxs                                           int
+	LoadBlobNamespaces                                   int
+	LoadBlobNamespaceDistribution                        Distribution
+	LoadBlobsPerTx                                       int
+	LoadBlobSizeMin                                      int
+	LoadBlobSizeMax                                      int
 	ABCIProtocol                                         string
 	PrepareProposalDelay                                 time.Duration
 	ProcessProposalDelay                                 time.Duration
@@ -89,9 +110,11 @@ type Testnet struct {
 	VoteExtensionDelay                                   time.Duration
 	FinalizeBlockDelay                                   time.Duration
 	UpgradeVersion                                       string
+	RollingUpgrade                                       bool
 	LogLevel                                             string
 	LogFormat                                            string
 	Prometheus                                           bool
+	MetricLimits                                         map[string]float64
 	BlockMaxBytes                                        int64
 	VoteExtensionsEnableHeight                           int64
 	VoteExtensionsUpdateHeight                           int64
@@ -125,6 +148,8 @@ type Node struct {
 	Seeds               []*Node
 	PersistentPeers     []*Node
 	Perturbations       []Perturbation
+	Netem               Netem
+	NetemLinks          map[*Node]Netem
 	SendNoLoad          bool
 	Prometheus          bool
 	PrometheusProxyPort uint32
@@ -164,33 +189,40 @@ func NewTestnetFromManifest(manifest Manifest, file string, ifd InfrastructureDa
 	}
 
 	testnet := &Testnet{
-		Name:                       filepath.Base(dir),
-		File:                       file,
-		Dir:                        dir,
-		IP:                         ipNet,
-		InitialHeight:              1,
-		InitialState:               manifest.InitialState,
-		Validators:                 map[*Node]int64{},
-		ValidatorUpdates:           map[int64]map[*Node]int64{},
-		Nodes:                      []*Node{},
-		Evidence:                   manifest.Evidence,
-		LoadTxSizeBytes:            manifest.LoadTxSizeBytes,
-		LoadTxBatchSize:            manifest.LoadTxBatchSize,
-		LoadTxConnections:          manifest.LoadTxConnections,
-		LoadMaxTxs:                 manifest.LoadMaxTxs,
-		ABCIProtocol:               manifest.ABCIProtocol,
-		PrepareProposalDelay:       manifest.PrepareProposalDelay,
-		ProcessProposalDelay:       manifest.ProcessProposalDelay,
-		CheckTxDelay:               manifest.CheckTxDelay,
-		VoteExtensionDelay:         manifest.VoteExtensionDelay,
-		FinalizeBlockDelay:         manifest.FinalizeBlockDelay,
-		UpgradeVersion:             manifest.UpgradeVersion,
-		LogLevel:                   manifest.LogLevel,
-		LogFormat:                  manifest.LogFormat,
-		Prometheus:                 manifest.Prometheus,
-		BlockMaxBytes:              manifest.BlockMaxBytes,
-		VoteExtensionsEnableHeight: manifest.VoteExtensionsEnableHeight,
-		VoteExtensionsUpdateHeight: manifest.VoteExtensionsUpdateHeight,
+		Name:                          filepath.Base(dir),
+		File:                          file,
+		Dir:                           dir,
+		IP:                            ipNet,
+		InitialHeight:                 1,
+		InitialState:                  manifest.InitialState,
+		Validators:                    map[*Node]int64{},
+		ValidatorUpdates:              map[int64]map[*Node]int64{},
+		Nodes:                         []*Node{},
+		Evidence:                      manifest.Evidence,
+		LoadTxSizeBytes:               manifest.LoadTxSizeBytes,
+		LoadTxBatchSize:               manifest.LoadTxBatchSize,
+		LoadTxConnections:             manifest.LoadTxConnections,
+		LoadMaxTxs:                    manifest.LoadMaxTxs,
+		LoadBlobNamespaces:            manifest.LoadBlobNamespaces,
+		LoadBlobNamespaceDistribution: Distribution(manifest.LoadBlobNamespaceDistribution),
+		LoadBlobsPerTx:                manifest.LoadBlobsPerTx,
+		LoadBlobSizeMin:               manifest.LoadBlobSizeMin,
+		LoadBlobSizeMax:               manifest.LoadBlobSizeMax,
+		ABCIProtocol:                  manifest.ABCIProtocol,
+		PrepareProposalDelay:          manifest.PrepareProposalDelay,
+		ProcessProposalDelay:          manifest.ProcessProposalDelay,
+		CheckTxDelay:                  manifest.CheckTxDelay,
+		VoteExtensionDelay:            manifest.VoteExtensionDelay,
+		FinalizeBlockDelay:            manifest.FinalizeBlockDelay,
+		UpgradeVersion:                manifest.UpgradeVersion,
+		RollingUpgrade:                manifest.RollingUpgrade,
+		LogLevel:                      manifest.LogLevel,
+		LogFormat:                     manifest.LogFormat,
+		Prometheus:                    manifest.Prometheus,
+		MetricLimits:                  manifest.MetricLimits,
+		BlockMaxBytes:                 manifest.BlockMaxBytes,
+		VoteExtensionsEnableHeight:    manifest.VoteExtensionsEnableHeight,
+		VoteExtensionsUpdateHeight:    manifest.VoteExtensionsUpdateHeight,
 		ExperimentalMaxGossipConnectionsToPersistentPeers:    manifest.ExperimentalMaxGossipConnectionsToPersistentPeers,
 		ExperimentalMaxGossipConnectionsToNonPersistentPeers: manifest.ExperimentalMaxGossipConnectionsToNonPersistentPeers,
 
@@ -218,6 +250,18 @@ func NewTestnetFromManifest(manifest Manifest, file string, ifd InfrastructureDa
 	if testnet.LoadTxSizeBytes == 0 {
 		testnet.LoadTxSizeBytes = defaultTxSizeBytes
 	}
+	if testnet.LoadBlobNamespaceDistribution == "" {
+		testnet.LoadBlobNamespaceDistribution = DistributionUniform
+	}
+	if testnet.LoadBlobsPerTx == 0 {
+		testnet.LoadBlobsPerTx = 1
+	}
+	if testnet.LoadBlobSizeMin == 0 {
+		testnet.LoadBlobSizeMin = testnet.LoadTxSizeBytes
+	}
+	if testnet.LoadBlobSizeMax == 0 {
+		testnet.LoadBlobSizeMax = testnet.LoadBlobSizeMin
+	}
 
 	for _, name := range sortNodeNames(manifest) {
 		nodeManifest := manifest.Nodes[name]
@@ -290,6 +334,10 @@ func NewTestnetFromManifest(manifest Manifest, file string, ifd InfrastructureDa
 		for _, p := range nodeManifest.Perturb {
 			node.Perturbations = append(node.Perturbations, Perturbation(p))
 		}
+		node.Netem, err = newNetem(nodeManifest.Netem)
+		if err != nil {
+			return nil, fmt.Errorf("node %q: %w", name, err)
+		}
 		if node.MaxInboundConnections < 0 {
 			return nil, errors.New("MaxInboundConnections must not be negative")
 		}
@@ -319,6 +367,20 @@ func NewTestnetFromManifest(manifest Manifest, file string, ifd InfrastructureDa
 			}
 			node.PersistentPeers = append(node.PersistentPeers, peer)
 		}
+		for peerName, link := range nodeManifest.NetemLinks {
+			peer := testnet.LookupNode(peerName)
+			if peer == nil {
+				return nil, fmt.Errorf("unknown netem link peer %q for node %q", peerName, node.Name)
+			}
+			netem, err := newNetem(link)
+			if err != nil {
+				return nil, fmt.Errorf("node %q link to %q: %w", node.Name, peerName, err)
+			}
+			if node.NetemLinks == nil {
+				node.NetemLinks = map[*Node]Netem{}
+			}
+			node.NetemLinks[peer] = netem
+		}
 
 		// If there are no seeds or persistent peers specified, default to persistent
 		// connections to all other nodes.
@@ -366,6 +428,17 @@ func NewTestnetFromManifest(manifest Manifest, file string, ifd InfrastructureDa
 		testnet.ValidatorUpdates[int64(height)] = valUpdate
 	}
 
+	for _, evType := range manifest.EvidenceTypes {
+		testnet.EvidenceTypes = append(testnet.EvidenceTypes, EvidenceType(evType))
+	}
+	for _, name := range manifest.EvidenceValidators {
+		node := testnet.LookupNode(name)
+		if node == nil {
+			return nil, fmt.Errorf("unknown evidence validator %q", name)
+		}
+		testnet.EvidenceValidators = append(testnet.EvidenceValidators, node)
+	}
+
 	return testnet, testnet.Validate()
 }
 
@@ -414,6 +487,38 @@ func (t Testnet) Validate() error {
 			)
 		}
 	}
+	for _, evType := range t.EvidenceTypes {
+		switch evType {
+		case EvidenceDuplicateVote, EvidenceLunatic, EvidenceAmnesia:
+		default:
+			return fmt.Errorf("invalid evidence type %q", evType)
+		}
+	}
+	if t.LoadBlobNamespaces < 0 || t.LoadBlobNamespaces > maxLoadBlobNamespaces {
+		return fmt.Errorf("load_blob_namespaces must be between 0 and %d", maxLoadBlobNamespaces)
+	}
+	switch t.LoadBlobNamespaceDistribution {
+	case DistributionUniform, DistributionZipf:
+	default:
+		return fmt.Errorf("invalid load_blob_namespace_distribution %q", t.LoadBlobNamespaceDistribution)
+	}
+	if t.LoadBlobsPerTx < 0 {
+		return errors.New("load_blobs_per_tx must not be negative")
+	}
+	if t.LoadBlobSizeMin <= 0 || t.LoadBlobSizeMax < t.LoadBlobSizeMin {
+		return fmt.Errorf("invalid load blob size range [%d, %d]", t.LoadBlobSizeMin, t.LoadBlobSizeMax)
+	}
+	if t.RollingUpgrade && !t.hasUpgrades() {
+		return errors.New("rolling upgrade requires nodes with the upgrade perturbation")
+	}
+	if len(t.MetricLimits) > 0 && !t.Prometheus {
+		return errors.New("metric limits require Prometheus to be enabled")
+	}
+	for _, node := range t.EvidenceValidators {
+		if _, ok := t.Validators[node]; !ok {
+			return fmt.Errorf("evidence validator %q is not a genesis validator", node.Name)
+		}
+	}
 	for _, node := range t.Nodes {
 		if err := node.Validate(t); err != nil {
 			return fmt.Errorf("invalid node %q: %w", node.Name, err)
@@ -505,11 +610,26 @@ func (n Node) Validate(testnet Testnet) error {
 				return fmt.Errorf("'upgrade' perturbation can appear at most once per node")
 			}
 			upgradeFound = true
+		case PerturbationNetem:
+			if n.Netem.IsZero() && len(n.NetemLinks) == 0 {
+				return errors.New("'netem' perturbation requires netem settings")
+			}
 		case PerturbationDisconnect, PerturbationKill, PerturbationPause, PerturbationRestart:
 		default:
 			return fmt.Errorf("invalid perturbation %q", perturbation)
 		}
 	}
+	if err := n.Netem.Validate(); err != nil {
+		return err
+	}
+	if len(n.NetemLinks) > maxNetemLinks {
+		return fmt.Errorf("at most %d netem links are supported, got %d", maxNetemLinks, len(n.NetemLinks))
+	}
+	for peer, netem := range n.NetemLinks {
+		if err := netem.Validate(); err != nil {
+			return fmt.Errorf("link to %q: %w", peer.Name, err)
+		}
+	}
 
 	return nil
 }
@@ -552,6 +672,27 @@ func (t Testnet) IPv6() bool {
 	return t.IP.IP.To4() == nil
 }
 
+// HasPerturbation returns whether the node has the given perturbation.
+func (n Node) HasPerturbation(perturbation Perturbation) bool {
+	for _, p := range n.Perturbations {
+		if p == perturbation {
+			return true
+		}
+	}
+	return false
+}
+
+// hasUpgrades returns whether any node of the network has the upgrade
+// perturbation.
+func (t Testnet) hasUpgrades() bool {
+	for _, node := range t.Nodes {
+		if node.HasPerturbation(PerturbationUpgrade) {
+			return true
+		}
+	}
+	return false
+}
+
 // HasPerturbations returns whether the network has any perturbations.
 func (t Testnet) HasPerturbations() bool {
 	for _, node := range t.Nodes {
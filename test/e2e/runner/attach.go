@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	e2e "github.com/cometbft/cometbft/test/e2e/pkg"
+	"github.com/cometbft/cometbft/test/e2e/pkg/infra"
+)
+
+// attachStages are the stages that can be run against an attached testnet.
+var attachStages = map[string]bool{
+	"load":      true,
+	"perturb":   true,
+	"wait":      true,
+	"test":      true,
+	"benchmark": true,
+}
+
+// Attach discovers the nodes of an already running testnet and waits for
+// them to respond, without setting anything up. It fails if the testnet has
+// not been set up or none of its nodes are running.
+func Attach(ctx context.Context, testnet *e2e.Testnet, p infra.Provider) error {
+	if _, err := os.Stat(testnet.Dir); err != nil {
+		return fmt.Errorf("testnet %v has not been set up: %w", testnet.Name, err)
+	}
+	running, err := p.RunningNodes(ctx)
+	if err != nil {
+		return err
+	}
+	nodes, missing := splitRunningNodes(testnet, running)
+	if len(nodes) == 0 {
+		return fmt.Errorf("no nodes of testnet %v are running", testnet.Name)
+	}
+	if len(missing) > 0 {
+		logger.Info("Some testnet nodes are not running", "nodes", strings.Join(missing, ","))
+	}
+
+	for _, node := range nodes {
+		status, err := waitForNode(ctx, node, 0, 20*time.Second)
+		if err != nil {
+			return err
+		}
+		logger.Info("Attached to node", "node", node.Name, "height", status.SyncInfo.LatestBlockHeight)
+	}
+	return nil
+}
+
+// splitRunningNodes returns the testnet nodes that are running, along with
+// the names of those that are not.
+func splitRunningNodes(testnet *e2e.Testnet, running []string) ([]*e2e.Node, []string) {
+	isRunning := make(map[string]bool, len(running))
+	for _, name := range running {
+		isRunning[name] = true
+	}
+	var nodes []*e2e.Node
+	var missing []string
+	for _, node := range testnet.Nodes {
+		if isRunning[node.Name] {
+			nodes = append(nodes, node)
+		} else {
+			missing = append(missing, node.Name)
+		}
+	}
+	return nodes, missing
+}
+
+// validateAttachStages checks that the given stages can be run against an
+// attached testnet.
+func validateAttachStages(stages []string) error {
+	if len(stages) == 0 {
+		return errors.New("no stages given")
+	}
+	loads := 0
+	for _, stage := range stages {
+		if !attachStages[stage] {
+			return fmt.Errorf("stage %q can't be run against an attached testnet", stage)
+		}
+		if stage == "load" {
+			loads++
+		}
+	}
+	if loads > 1 {
+		return errors.New("stage \"load\" can be given at most once")
+	}
+	return nil
+}
+
+// RunAttached runs the given stages in order against an attached testnet.
+// Load is generated from its position until all the following stages are
+// done, or until canceled if it is the last stage.
+func RunAttached(
+	ctx context.Context,
+	testnet *e2e.Testnet,
+	p infra.Provider,
+	stages []string,
+	stallTimeout time.Duration,
+	benchmarkOutput string,
+) error {
+	loadCtx, loadCancel := context.WithCancel(ctx)
+	defer loadCancel()
+	var chLoadResult chan error
+
+	for i, stage := range stages {
+		var err error
+		switch stage {
+		case "load":
+			if i == len(stages)-1 {
+				return Load(ctx, testnet)
+			}
+			chLoadResult = make(chan error, 1)
+			go func() {
+				chLoadResult <- Load(loadCtx, testnet)
+			}()
+		case "perturb":
+			err = Perturb(ctx, testnet)
+		case "wait":
+			err = Wait(ctx, testnet, 5, stallTimeout)
+		case "test":
+			err = Test(ctx, testnet, p, nil)
+		case "benchmark":
+			err = Benchmark(ctx, testnet, 100, benchmarkOutput)
+		default:
+			err = fmt.Errorf("stage %q can't be run against an attached testnet", stage)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if chLoadResult != nil {
+		loadCancel()
+		return <-chLoadResult
+	}
+	return nil
+}
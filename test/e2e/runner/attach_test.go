@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	e2e "github.com/cometbft/cometbft/test/e2e/pkg"
+)
+
+func TestAttachNotRunning(t *testing.T) {
+	testnet := &e2e.Testnet{
+		Name:  "ci",
+		Dir:   t.TempDir(),
+		Nodes: []*e2e.Node{{Name: "validator01"}, {Name: "validator02"}},
+	}
+	err := Attach(context.Background(), testnet, &mockProvider{})
+	require.EqualError(t, err, "no nodes of testnet ci are running")
+
+	testnet.Dir = t.TempDir() + "/missing"
+	err = Attach(context.Background(), testnet, &mockProvider{started: []string{"validator01"}})
+	require.ErrorContains(t, err, "testnet ci has not been set up")
+}
+
+func TestSplitRunningNodes(t *testing.T) {
+	testnet := &e2e.Testnet{
+		Nodes: []*e2e.Node{{Name: "full01"}, {Name: "validator01"}, {Name: "validator02"}},
+	}
+	nodes, missing := splitRunningNodes(testnet, []string{"validator02", "full01", "prometheus"})
+	require.Equal(t, []*e2e.Node{testnet.Nodes[0], testnet.Nodes[2]}, nodes)
+	require.Equal(t, []string{"validator01"}, missing)
+}
+
+func TestValidateAttachStages(t *testing.T) {
+	require.NoError(t, validateAttachStages([]string{"load"}))
+	require.NoError(t, validateAttachStages([]string{"load", "perturb", "wait", "test"}))
+	require.NoError(t, validateAttachStages([]string{"benchmark"}))
+
+	require.Error(t, validateAttachStages(nil))
+	require.EqualError(t, validateAttachStages([]string{"wait", "setup"}),
+		`stage "setup" can't be run against an attached testnet`)
+	require.Error(t, validateAttachStages([]string{"load", "wait", "load"}))
+}
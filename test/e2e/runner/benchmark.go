@@ -20,8 +20,10 @@ import (
 // 4. Min block interval (fastest block)
 //
 // Metrics are based of the `benchmarkLength`, the amount of consecutive blocks
-// sampled from in the testnet
-func Benchmark(ctx context.Context, testnet *e2e.Testnet, benchmarkLength int64) error {
+// sampled from in the testnet. If output is set, a report including the
+// sampled blocks and the state of each node is also written to it, as JSON or
+// CSV depending on its extension.
+func Benchmark(ctx context.Context, testnet *e2e.Testnet, benchmarkLength int64, output string) error {
 	block, _, err := waitForHeight(ctx, testnet, 0)
 	if err != nil {
 		return err
@@ -57,6 +59,14 @@ func Benchmark(ctx context.Context, testnet *e2e.Testnet, benchmarkLength int64)
 
 	// print and return
 	logger.Info(testnetStats.OutputJSON(testnet))
+	if output == "" {
+		return nil
+	}
+	report := newBenchmarkReport(testnet, testnetStats, blocks, collectNodeReports(ctx, testnet))
+	if err := writeBenchmarkReport(output, report); err != nil {
+		return fmt.Errorf("writing benchmark report: %w", err)
+	}
+	logger.Info("Wrote benchmark report", "file", output)
 	return nil
 }
 
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	e2e "github.com/cometbft/cometbft/test/e2e/pkg"
+	"github.com/cometbft/cometbft/types"
+)
+
+const (
+	// Process metrics exported by the nodes' Prometheus endpoints.
+	cpuSecondsMetric     = "process_cpu_seconds_total"
+	residentMemoryMetric = "process_resident_memory_bytes"
+)
+
+// benchmarkReport is the structured result of a benchmark, written out for
+// tracking regressions across commits.
+type benchmarkReport struct {
+	Case        string  `json:"case"`
+	Size        int     `json:"size"`
+	StartHeight int64   `json:"start_height"`
+	EndHeight   int64   `json:"end_height"`
+	Duration    float64 `json:"dur"`
+	Txs         int64   `json:"txns"`
+	Throughput  float64 `json:"tx_per_sec"`
+	Mean        float64 `json:"mean"`
+	StdDev      float64 `json:"stddev"`
+	Max         float64 `json:"max"`
+	Min         float64 `json:"min"`
+
+	Blocks []blockReport `json:"blocks"`
+	Nodes  []nodeReport  `json:"nodes"`
+}
+
+// blockReport describes a sampled block, with the interval since the
+// previous one in seconds.
+type blockReport struct {
+	Height   int64   `json:"height"`
+	Interval float64 `json:"interval"`
+	Txs      int     `json:"txns"`
+}
+
+// nodeReport describes the state of a node at the end of the benchmark. The
+// resource usage is only known for nodes with Prometheus enabled.
+type nodeReport struct {
+	Name         string   `json:"name"`
+	MempoolTxs   int      `json:"mempool_txns"`
+	MempoolBytes int64    `json:"mempool_bytes"`
+	CPUSeconds   *float64 `json:"cpu_seconds,omitempty"`
+	MemoryBytes  *float64 `json:"memory_bytes,omitempty"`
+}
+
+// newBenchmarkReport builds the report of a benchmark over the given blocks.
+func newBenchmarkReport(
+	testnet *e2e.Testnet,
+	stats testnetStats,
+	blocks []*types.BlockMeta,
+	nodes []nodeReport,
+) benchmarkReport {
+	report := benchmarkReport{
+		Case:        filepath.Base(testnet.File),
+		Size:        len(testnet.Nodes),
+		StartHeight: stats.startHeight,
+		EndHeight:   stats.endHeight,
+		Duration:    stats.totalTime.Seconds(),
+		Txs:         stats.numtxns,
+		Mean:        stats.mean.Seconds(),
+		StdDev:      stats.std,
+		Max:         stats.max.Seconds(),
+		Min:         stats.min.Seconds(),
+		Blocks:      make([]blockReport, len(blocks)),
+		Nodes:       nodes,
+	}
+	for i, block := range blocks {
+		report.Blocks[i] = blockReport{Height: block.Header.Height, Txs: block.NumTxs}
+		if i > 0 {
+			report.Blocks[i].Interval = block.Header.Time.Sub(blocks[i-1].Header.Time).Seconds()
+		}
+	}
+	if span := blocks[len(blocks)-1].Header.Time.Sub(blocks[0].Header.Time); span > 0 {
+		report.Throughput = float64(stats.numtxns) / span.Seconds()
+	}
+	return report
+}
+
+// collectNodeReports queries the mempool of each node, along with its
+// resource usage if it has Prometheus enabled. Nodes that cannot be reached
+// are skipped.
+func collectNodeReports(ctx context.Context, testnet *e2e.Testnet) []nodeReport {
+	var reports []nodeReport
+	for _, node := range testnet.Nodes {
+		if node.Stateless() {
+			continue
+		}
+		client, err := node.Client()
+		if err != nil {
+			continue
+		}
+		mempool, err := client.NumUnconfirmedTxs(ctx)
+		if err != nil {
+			logger.Error("Failed to query node mempool", "node", node.Name, "err", err)
+			continue
+		}
+		report := nodeReport{
+			Name:         node.Name,
+			MempoolTxs:   mempool.Total,
+			MempoolBytes: mempool.TotalBytes,
+		}
+		if node.PrometheusProxyPort > 0 {
+			families, err := scrapeMetrics(ctx, node)
+			if err != nil {
+				logger.Error("Failed to scrape node metrics", "node", node.Name, "err", err)
+			} else {
+				if mf, ok := families[cpuSecondsMetric]; ok && len(mf.Metric) > 0 {
+					v := mf.Metric[0].GetCounter().GetValue()
+					report.CPUSeconds = &v
+				}
+				if mf, ok := families[residentMemoryMetric]; ok && len(mf.Metric) > 0 {
+					v := mf.Metric[0].GetGauge().GetValue()
+					report.MemoryBytes = &v
+				}
+			}
+		}
+		reports = append(reports, report)
+	}
+	return reports
+}
+
+// writeBenchmarkReport writes the report to the file at path, as JSON or CSV
+// depending on its extension.
+func writeBenchmarkReport(path string, report benchmarkReport) error {
+	var bz []byte
+	switch ext := filepath.Ext(path); ext {
+	case ".json":
+		var err error
+		bz, err = json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+	case ".csv":
+		bz = report.csv()
+	default:
+		return fmt.Errorf("unsupported benchmark report format %q, expected .json or .csv", ext)
+	}
+	return os.WriteFile(path, bz, 0o644) //nolint:gosec
+}
+
+// csv returns the report in CSV format, with one metric per row, labelled
+// with the block height or node name it applies to, if any.
+func (r benchmarkReport) csv() []byte {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	f := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+	i := func(v int64) string { return strconv.FormatInt(v, 10) }
+
+	rows := [][]string{
+		{"metric", "label", "value"},
+		{"case", "", r.Case},
+		{"size", "", strconv.Itoa(r.Size)},
+		{"start_height", "", i(r.StartHeight)},
+		{"end_height", "", i(r.EndHeight)},
+		{"dur", "", f(r.Duration)},
+		{"txns", "", i(r.Txs)},
+		{"tx_per_sec", "", f(r.Throughput)},
+		{"mean", "", f(r.Mean)},
+		{"stddev", "", f(r.StdDev)},
+		{"max", "", f(r.Max)},
+		{"min", "", f(r.Min)},
+	}
+	for _, b := range r.Blocks {
+		height := i(b.Height)
+		rows = append(rows,
+			[]string{"block_interval", height, f(b.Interval)},
+			[]string{"block_txns", height, strconv.Itoa(b.Txs)},
+		)
+	}
+	for _, n := range r.Nodes {
+		rows = append(rows,
+			[]string{"mempool_txns", n.Name, strconv.Itoa(n.MempoolTxs)},
+			[]string{"mempool_bytes", n.Name, i(n.MempoolBytes)},
+		)
+		if n.CPUSeconds != nil {
+			rows = append(rows, []string{"cpu_seconds", n.Name, f(*n.CPUSeconds)})
+		}
+		if n.MemoryBytes != nil {
+			rows = append(rows, []string{"memory_bytes", n.Name, f(*n.MemoryBytes)})
+		}
+	}
+	_ = w.WriteAll(rows) // writing to a buffer can't fail
+	return buf.Bytes()
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	e2e "github.com/cometbft/cometbft/test/e2e/pkg"
+	"github.com/cometbft/cometbft/types"
+)
+
+func testBenchmarkReport() benchmarkReport {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	blocks := []*types.BlockMeta{
+		{Header: types.Header{Height: 10, Time: start}, NumTxs: 2},
+		{Header: types.Header{Height: 11, Time: start.Add(time.Second)}, NumTxs: 4},
+		{Header: types.Header{Height: 12, Time: start.Add(4 * time.Second)}, NumTxs: 6},
+	}
+	stats := extractTestnetStats(splitIntoBlockIntervals(blocks))
+	stats.populateTxns(blocks)
+	stats.totalTime = 5 * time.Second
+	stats.startHeight = 10
+	stats.endHeight = 12
+
+	memory := 1024.0
+	testnet := &e2e.Testnet{File: "networks/ci.toml", Nodes: []*e2e.Node{{}, {}}}
+	return newBenchmarkReport(testnet, stats, blocks, []nodeReport{
+		{Name: "validator01", MempoolTxs: 3, MempoolBytes: 300, MemoryBytes: &memory},
+	})
+}
+
+func TestBenchmarkReport(t *testing.T) {
+	report := testBenchmarkReport()
+	require.Equal(t, "ci.toml", report.Case)
+	require.EqualValues(t, 12, report.Txs)
+	require.Equal(t, 3.0, report.Throughput)
+	require.Equal(t, 2.0, report.Mean)
+	require.Equal(t, []blockReport{
+		{Height: 10, Txs: 2},
+		{Height: 11, Interval: 1, Txs: 4},
+		{Height: 12, Interval: 3, Txs: 6},
+	}, report.Blocks)
+
+	dir := t.TempDir()
+
+	jsonFile := filepath.Join(dir, "report.json")
+	require.NoError(t, writeBenchmarkReport(jsonFile, report))
+	bz, err := os.ReadFile(jsonFile)
+	require.NoError(t, err)
+	var decoded benchmarkReport
+	require.NoError(t, json.Unmarshal(bz, &decoded))
+	require.Equal(t, report, decoded)
+
+	csvFile := filepath.Join(dir, "report.csv")
+	require.NoError(t, writeBenchmarkReport(csvFile, report))
+	bz, err = os.ReadFile(csvFile)
+	require.NoError(t, err)
+	require.Equal(t, `metric,label,value
+case,,ci.toml
+size,,2
+start_height,,10
+end_height,,12
+dur,,5
+txns,,12
+tx_per_sec,,3
+mean,,2
+stddev,,1
+max,,3
+min,,1
+block_interval,10,0
+block_txns,10,2
+block_interval,11,1
+block_txns,11,4
+block_interval,12,3
+block_txns,12,6
+mempool_txns,validator01,3
+mempool_bytes,validator01,300
+memory_bytes,validator01,1024
+`, string(bz))
+
+	require.Error(t, writeBenchmarkReport(filepath.Join(dir, "report.txt"), report))
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/cometbft/cometbft/privval"
 	cmtproto "github.com/cometbft/cometbft/proto/tendermint/types"
 	cmtversion "github.com/cometbft/cometbft/proto/tendermint/version"
+	rpctypes "github.com/cometbft/cometbft/rpc/core/types"
 	e2e "github.com/cometbft/cometbft/test/e2e/pkg"
 	"github.com/cometbft/cometbft/types"
 	"github.com/cometbft/cometbft/version"
@@ -28,7 +29,8 @@ const lightClientEvidenceRatio = 4
 // InjectEvidence takes a running testnet and generates an amount of valid/invalid
 // evidence and broadcasts it to a random node through the rpc endpoint `/broadcast_evidence`.
 // Evidence is random and can be a mixture of LightClientAttackEvidence and
-// DuplicateVoteEvidence.
+// DuplicateVoteEvidence, of the types listed in the testnet's EvidenceTypes and
+// forged with the keys of its EvidenceValidators, if given.
 func InjectEvidence(ctx context.Context, r *rand.Rand, testnet *e2e.Testnet, amount int) error {
 	// select a random node
 	var targetNode *e2e.Node
@@ -74,11 +76,15 @@ func InjectEvidence(ctx context.Context, r *rand.Rand, testnet *e2e.Testnet, amo
 		return err
 	}
 
-	// get the private keys of all the validators in the network
+	// get the private keys of the validators forging the evidence
 	privVals, err := getPrivateValidatorKeys(testnet)
 	if err != nil {
 		return err
 	}
+	signerVals := valSet
+	if len(testnet.EvidenceValidators) > 0 {
+		signerVals = filterValidatorSet(valSet, privVals)
+	}
 
 	// wait for the node to reach the height above the forged height so that
 	// it is able to validate the evidence
@@ -87,20 +93,36 @@ func InjectEvidence(ctx context.Context, r *rand.Rand, testnet *e2e.Testnet, amo
 		return err
 	}
 
-	var ev types.Evidence
+	var (
+		ev       types.Evidence
+		trusted  *types.SignedHeader
+		lunatics int
+	)
 	for i := 0; i < amount; i++ {
 		validEv := true
-		if i%lightClientEvidenceRatio == 0 {
-			validEv = i%(lightClientEvidenceRatio*2) != 0 // Alternate valid and invalid evidence
+		switch evidenceTypeAt(testnet.EvidenceTypes, i) {
+		case e2e.EvidenceLunatic:
+			validEv = lunatics%2 == 1 // Alternate valid and invalid evidence
+			lunatics++
 			ev, err = generateLightClientAttackEvidence(
-				ctx, privVals, evidenceHeight, valSet, testnet.Name, blockRes.Block.Time, validEv,
+				ctx, privVals, evidenceHeight, valSet, signerVals, testnet.Name, blockRes.Block.Time, validEv,
 			)
-		} else {
+		case e2e.EvidenceAmnesia:
+			if trusted == nil {
+				var commitRes *rpctypes.ResultCommit
+				commitRes, err = client.Commit(ctx, &evidenceHeight)
+				if err != nil {
+					return err
+				}
+				trusted = &commitRes.SignedHeader
+			}
+			ev, err = generateAmnesiaEvidence(privVals, trusted, valSet, signerVals)
+		default:
 			var dve *types.DuplicateVoteEvidence
 			dve, err = generateDuplicateVoteEvidence(
-				privVals, evidenceHeight, valSet, testnet.Name, blockRes.Block.Time,
+				r, privVals, evidenceHeight, valSet, testnet.Name, blockRes.Block.Time,
 			)
-			if dve.VoteA.Height < testnet.VoteExtensionsEnableHeight {
+			if !types.VoteExtensionsEnabled(dve.VoteA.Height, testnet.VoteExtensionsEnableHeight) {
 				dve.VoteA.Extension = nil
 				dve.VoteA.ExtensionSignature = nil
 				dve.VoteB.Extension = nil
@@ -139,10 +161,29 @@ func InjectEvidence(ctx context.Context, r *rand.Rand, testnet *e2e.Testnet, amo
 	return nil
 }
 
+// evidenceTypeAt returns the type of the i-th evidence to inject, cycling
+// through evTypes. If none are given, 1 in lightClientEvidenceRatio is lunatic
+// evidence and the rest is duplicate vote evidence.
+func evidenceTypeAt(evTypes []e2e.EvidenceType, i int) e2e.EvidenceType {
+	if len(evTypes) > 0 {
+		return evTypes[i%len(evTypes)]
+	}
+	if i%lightClientEvidenceRatio == 0 {
+		return e2e.EvidenceLunatic
+	}
+	return e2e.EvidenceDuplicateVote
+}
+
+// getPrivateValidatorKeys returns the keys of the testnet's evidence
+// validators or, if none are given, of all its validator nodes.
 func getPrivateValidatorKeys(testnet *e2e.Testnet) ([]types.MockPV, error) {
 	privVals := []types.MockPV{}
 
-	for _, node := range testnet.Nodes {
+	nodes := testnet.EvidenceValidators
+	if len(nodes) == 0 {
+		nodes = testnet.Nodes
+	}
+	for _, node := range nodes {
 		if node.Mode == e2e.ModeValidator {
 			privKeyPath := filepath.Join(testnet.Dir, node.Name, PrivvalKeyFile)
 			privKey, err := readPrivKey(privKeyPath)
@@ -158,13 +199,28 @@ func getPrivateValidatorKeys(testnet *e2e.Testnet) ([]types.MockPV, error) {
 	return privVals, nil
 }
 
-// creates evidence of a lunatic attack. The height provided is the common height.
-// The forged height happens 2 blocks later.
+// filterValidatorSet returns the validators of vals that have a key in privVals.
+func filterValidatorSet(vals *types.ValidatorSet, privVals []types.MockPV) *types.ValidatorSet {
+	var filtered []*types.Validator
+	for _, val := range vals.Copy().Validators {
+		for _, pv := range privVals {
+			if bytes.Equal(pv.PrivKey.PubKey().Address(), val.Address) {
+				filtered = append(filtered, val)
+				break
+			}
+		}
+	}
+	return types.NewValidatorSet(filtered)
+}
+
+// creates evidence of a lunatic attack, signed by the signers. The height
+// provided is the common height. The forged height happens 2 blocks later.
 func generateLightClientAttackEvidence(
 	ctx context.Context,
 	privVals []types.MockPV,
 	height int64,
 	vals *types.ValidatorSet,
+	signers *types.ValidatorSet,
 	chainID string,
 	evTime time.Time,
 	validEvidence bool,
@@ -175,9 +231,14 @@ func generateLightClientAttackEvidence(
 	header := makeHeaderRandom(chainID, forgedHeight)
 	header.Time = forgedTime
 
+	if validEvidence && signers.TotalVotingPower()*3 <= vals.TotalVotingPower() {
+		return nil, fmt.Errorf("lunatic evidence needs signers with more than 1/3 of the voting power, got %d/%d",
+			signers.TotalVotingPower(), vals.TotalVotingPower())
+	}
+
 	// add a new bogus validator and remove an existing one to
 	// vary the validator set slightly
-	pv, conflictingVals, err := mutateValidatorSet(ctx, privVals, vals, !validEvidence)
+	pv, conflictingVals, err := mutateValidatorSet(ctx, privVals, signers, !validEvidence)
 	if err != nil {
 		return nil, err
 	}
@@ -215,16 +276,59 @@ func generateLightClientAttackEvidence(
 	return ev, nil
 }
 
+// generateAmnesiaEvidence creates evidence of an amnesia attack: a block
+// conflicting with the trusted one but correctly derived from the same state,
+// committed by the signers in a later round. Since the attackers can't be told
+// apart from honest validators, the evidence lists no byzantine validators.
+func generateAmnesiaEvidence(
+	privVals []types.MockPV,
+	trusted *types.SignedHeader,
+	vals *types.ValidatorSet,
+	signers *types.ValidatorSet,
+) (*types.LightClientAttackEvidence, error) {
+	if signers.TotalVotingPower()*3 <= vals.TotalVotingPower()*2 {
+		return nil, fmt.Errorf("amnesia evidence needs signers with more than 2/3 of the voting power, got %d/%d",
+			signers.TotalVotingPower(), vals.TotalVotingPower())
+	}
+
+	header := *trusted.Header
+	header.DataHash = crypto.CRandBytes(tmhash.Size)
+
+	pvs := make([]types.PrivValidator, len(privVals))
+	for i, pv := range privVals {
+		pvs[i] = pv
+	}
+	blockID := makeBlockID(header.Hash(), 1000, []byte("partshash"))
+	commit, err := test.MakeCommit(blockID, header.Height, trusted.Commit.Round+1, vals, pvs, header.ChainID, header.Time)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.LightClientAttackEvidence{
+		ConflictingBlock: &types.LightBlock{
+			SignedHeader: &types.SignedHeader{
+				Header: &header,
+				Commit: commit,
+			},
+			ValidatorSet: vals,
+		},
+		CommonHeight:     header.Height,
+		TotalVotingPower: vals.TotalVotingPower(),
+		Timestamp:        header.Time,
+	}, nil
+}
+
 // generateDuplicateVoteEvidence picks a random validator from the val set and
 // returns duplicate vote evidence against the validator
 func generateDuplicateVoteEvidence(
+	r *rand.Rand,
 	privVals []types.MockPV,
 	height int64,
 	vals *types.ValidatorSet,
 	chainID string,
 	time time.Time,
 ) (*types.DuplicateVoteEvidence, error) {
-	privVal, valIdx, err := getRandomValidatorIndex(privVals, vals)
+	privVal, valIdx, err := getRandomValidatorIndex(r, privVals, vals)
 	if err != nil {
 		return nil, err
 	}
@@ -246,8 +350,8 @@ func generateDuplicateVoteEvidence(
 
 // getRandomValidatorIndex picks a random validator from a slice of mock PrivVals that's
 // also part of the validator set, returning the PrivVal and its index in the validator set
-func getRandomValidatorIndex(privVals []types.MockPV, vals *types.ValidatorSet) (types.MockPV, int32, error) {
-	for _, idx := range rand.Perm(len(privVals)) {
+func getRandomValidatorIndex(r *rand.Rand, privVals []types.MockPV, vals *types.ValidatorSet) (types.MockPV, int32, error) {
+	for _, idx := range r.Perm(len(privVals)) {
 		pv := privVals[idx]
 		valIdx, _ := vals.GetByAddress(pv.PrivKey.PubKey().Address())
 		if valIdx >= 0 {
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cometbft/cometbft/evidence"
+	"github.com/cometbft/cometbft/internal/test"
+	e2e "github.com/cometbft/cometbft/test/e2e/pkg"
+	"github.com/cometbft/cometbft/types"
+)
+
+func TestEvidenceTypeAt(t *testing.T) {
+	var defaults []e2e.EvidenceType
+	for i := 0; i < 5; i++ {
+		defaults = append(defaults, evidenceTypeAt(nil, i))
+	}
+	require.Equal(t, []e2e.EvidenceType{
+		e2e.EvidenceLunatic, e2e.EvidenceDuplicateVote, e2e.EvidenceDuplicateVote,
+		e2e.EvidenceDuplicateVote, e2e.EvidenceLunatic,
+	}, defaults)
+
+	evTypes := []e2e.EvidenceType{e2e.EvidenceAmnesia, e2e.EvidenceDuplicateVote}
+	require.Equal(t, e2e.EvidenceAmnesia, evidenceTypeAt(evTypes, 0))
+	require.Equal(t, e2e.EvidenceDuplicateVote, evidenceTypeAt(evTypes, 1))
+	require.Equal(t, e2e.EvidenceAmnesia, evidenceTypeAt(evTypes, 2))
+}
+
+func TestGenerateAmnesiaEvidence(t *testing.T) {
+	const chainID = "ci"
+	vals, pvs := types.RandValidatorSet(4, 10)
+	mockPVs := make([]types.MockPV, len(pvs))
+	for i, pv := range pvs {
+		mockPVs[i] = pv.(types.MockPV)
+	}
+
+	evTime := time.Now()
+	header := makeHeaderRandom(chainID, 10)
+	header.Time = evTime
+	header.ValidatorsHash = vals.Hash()
+	blockID := makeBlockID(header.Hash(), 1000, []byte("partshash"))
+	commit, err := test.MakeCommit(blockID, header.Height, 0, vals, pvs, chainID, evTime)
+	require.NoError(t, err)
+	trusted := &types.SignedHeader{Header: header, Commit: commit}
+
+	// Three of the four validators hold more than 2/3 of the voting power.
+	signers := filterValidatorSet(vals, mockPVs[:3])
+	ev, err := generateAmnesiaEvidence(mockPVs[:3], trusted, vals, signers)
+	require.NoError(t, err)
+	require.NoError(t, ev.ValidateBasic())
+	require.Empty(t, ev.ByzantineValidators)
+	require.NoError(t, evidence.VerifyLightClientAttack(ev, trusted, trusted, vals, evTime, time.Hour))
+
+	signers = filterValidatorSet(vals, mockPVs[:2])
+	_, err = generateAmnesiaEvidence(mockPVs[:2], trusted, vals, signers)
+	require.Error(t, err)
+}
@@ -31,8 +31,13 @@ func Load(ctx context.Context, testnet *e2e.Testnet) error {
 	started := time.Now()
 	u := [16]byte(uuid.New()) // generate run ID on startup
 
+	blobs, err := newBlobGenerator(testnet, randomSeed)
+	if err != nil {
+		return err
+	}
+
 	txCh := make(chan types.Tx)
-	go loadGenerate(ctx, txCh, testnet, u[:])
+	go loadGenerate(ctx, txCh, testnet, blobs, u[:])
 
 	for _, n := range testnet.Nodes {
 		if n.SendNoLoad {
@@ -70,8 +75,9 @@ func Load(ctx context.Context, testnet *e2e.Testnet) error {
 	}
 }
 
-// loadGenerate generates jobs until the context is canceled
-func loadGenerate(ctx context.Context, txCh chan<- types.Tx, testnet *e2e.Testnet, id []byte) {
+// loadGenerate generates jobs until the context is canceled. If blobs is not
+// nil, the transactions are wrapped into blob transactions.
+func loadGenerate(ctx context.Context, txCh chan<- types.Tx, testnet *e2e.Testnet, blobs *blobGenerator, id []byte) {
 	t := time.NewTimer(0)
 	defer t.Stop()
 	for {
@@ -88,7 +94,7 @@ func loadGenerate(ctx context.Context, txCh chan<- types.Tx, testnet *e2e.Testne
 		// the next batch is set to be sent out, then the context is canceled so that
 		// the current batch is halted, allowing the next batch to begin.
 		tctx, cf := context.WithTimeout(ctx, time.Second)
-		createTxBatch(tctx, txCh, testnet, id)
+		createTxBatch(tctx, txCh, testnet, blobs, id)
 		cf()
 	}
 }
@@ -96,7 +102,7 @@ func loadGenerate(ctx context.Context, txCh chan<- types.Tx, testnet *e2e.Testne
 // createTxBatch creates new transactions and sends them into the txCh. createTxBatch
 // returns when either a full batch has been sent to the txCh or the context
 // is canceled.
-func createTxBatch(ctx context.Context, txCh chan<- types.Tx, testnet *e2e.Testnet, id []byte) {
+func createTxBatch(ctx context.Context, txCh chan<- types.Tx, testnet *e2e.Testnet, blobs *blobGenerator, id []byte) {
 	wg := &sync.WaitGroup{}
 	genCh := make(chan struct{})
 	for i := 0; i < workerPoolSize; i++ {
@@ -113,6 +119,12 @@ func createTxBatch(ctx context.Context, txCh chan<- types.Tx, testnet *e2e.Testn
 				if err != nil {
 					panic(fmt.Sprintf("Failed to generate tx: %v", err))
 				}
+				if blobs != nil {
+					tx, err = blobs.wrap(tx)
+					if err != nil {
+						panic(fmt.Sprintf("Failed to generate blob tx: %v", err))
+					}
+				}
 
 				select {
 				case txCh <- tx:
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+
+	"github.com/celestiaorg/go-square/v2/share"
+
+	cmtproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	e2e "github.com/cometbft/cometbft/test/e2e/pkg"
+	"github.com/cometbft/cometbft/types"
+)
+
+// blobZipfExponent skews the zipf namespace distribution. The higher it is,
+// the larger the share of blobs sent to the first namespaces.
+const blobZipfExponent = 1.1
+
+// blobGenerator wraps load transactions into blob transactions, with blobs of
+// random sizes spread across the namespaces of the testnet's load settings.
+// It is safe for concurrent use.
+type blobGenerator struct {
+	namespaces []share.Namespace
+	perTx      int
+	minSize    int
+	maxSize    int
+
+	mtx  sync.Mutex
+	rand *rand.Rand
+	zipf *rand.Zipf // nil for a uniform distribution
+}
+
+// newBlobGenerator returns a blob generator for the testnet, or nil if the
+// testnet doesn't load blobs.
+func newBlobGenerator(testnet *e2e.Testnet, seed int64) (*blobGenerator, error) {
+	if testnet.LoadBlobNamespaces == 0 {
+		return nil, nil
+	}
+	g := &blobGenerator{
+		namespaces: make([]share.Namespace, testnet.LoadBlobNamespaces),
+		perTx:      testnet.LoadBlobsPerTx,
+		minSize:    testnet.LoadBlobSizeMin,
+		maxSize:    testnet.LoadBlobSizeMax,
+		rand:       rand.New(rand.NewSource(seed)), //nolint:gosec
+	}
+	for i := range g.namespaces {
+		ns, err := share.NewV0Namespace([]byte(fmt.Sprintf("e2e-%06d", i)))
+		if err != nil {
+			return nil, err
+		}
+		g.namespaces[i] = ns
+	}
+	if testnet.LoadBlobNamespaceDistribution == e2e.DistributionZipf && len(g.namespaces) > 1 {
+		g.zipf = rand.NewZipf(g.rand, blobZipfExponent, 1, uint64(len(g.namespaces)-1))
+	}
+	return g, nil
+}
+
+// wrap returns a blob transaction carrying tx along with the generator's
+// number of blobs.
+func (g *blobGenerator) wrap(tx []byte) (types.Tx, error) {
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+
+	blobs := make([]*cmtproto.Blob, g.perTx)
+	for i := range blobs {
+		ns := g.namespaces[g.namespaceIndex()]
+		data := make([]byte, g.minSize+g.rand.Intn(g.maxSize-g.minSize+1))
+		g.rand.Read(data)
+		blobs[i] = &cmtproto.Blob{
+			NamespaceId:      ns.ID(),
+			Data:             data,
+			ShareVersion:     uint32(share.ShareVersionZero),
+			NamespaceVersion: uint32(ns.Version()),
+		}
+	}
+	return types.MarshalBlobTx(tx, blobs...)
+}
+
+func (g *blobGenerator) namespaceIndex() int {
+	if g.zipf != nil {
+		return int(g.zipf.Uint64())
+	}
+	return g.rand.Intn(len(g.namespaces))
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/celestiaorg/go-square/v2/share"
+	"github.com/stretchr/testify/require"
+
+	e2e "github.com/cometbft/cometbft/test/e2e/pkg"
+	"github.com/cometbft/cometbft/types"
+)
+
+func TestBlobGenerator(t *testing.T) {
+	g, err := newBlobGenerator(&e2e.Testnet{}, 1)
+	require.NoError(t, err)
+	require.Nil(t, g)
+
+	for _, dist := range []e2e.Distribution{e2e.DistributionUniform, e2e.DistributionZipf} {
+		t.Run(string(dist), func(t *testing.T) {
+			testnet := &e2e.Testnet{
+				LoadBlobNamespaces:            4,
+				LoadBlobNamespaceDistribution: dist,
+				LoadBlobsPerTx:                2,
+				LoadBlobSizeMin:               100,
+				LoadBlobSizeMax:               2000,
+			}
+			g, err := newBlobGenerator(testnet, 1)
+			require.NoError(t, err)
+
+			namespaces := map[string]int{}
+			data := types.Data{}
+			for i := 0; i < 100; i++ {
+				tx, err := g.wrap([]byte("key=value"))
+				require.NoError(t, err)
+				data.Txs = append(data.Txs, tx)
+
+				blobTx, isBlob := types.UnmarshalBlobTx(tx)
+				require.True(t, isBlob)
+				require.Equal(t, []byte("key=value"), blobTx.Tx)
+				require.Len(t, blobTx.Blobs, 2)
+				for _, blob := range blobTx.Blobs {
+					require.GreaterOrEqual(t, len(blob.Data), 100)
+					require.LessOrEqual(t, len(blob.Data), 2000)
+					ns, err := share.NewNamespace(uint8(blob.NamespaceVersion), blob.NamespaceId)
+					require.NoError(t, err)
+					require.NoError(t, ns.ValidateForBlob())
+					namespaces[string(blob.NamespaceId)]++
+				}
+			}
+			require.Len(t, namespaces, 4)
+			require.Greater(t, data.MinSquareSize(), uint64(1))
+		})
+	}
+}
@@ -2,10 +2,13 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/rand"
 	"os"
+	"path/filepath"
 	"strconv"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -13,6 +16,8 @@ import (
 	e2e "github.com/cometbft/cometbft/test/e2e/pkg"
 	"github.com/cometbft/cometbft/test/e2e/pkg/infra"
 	"github.com/cometbft/cometbft/test/e2e/pkg/infra/docker"
+	"github.com/cometbft/cometbft/test/e2e/pkg/infra/kubernetes"
+	"github.com/cometbft/cometbft/test/e2e/pkg/infra/ssh"
 )
 
 const randomSeed = 2308084734268
@@ -25,10 +30,14 @@ func main() {
 
 // CLI is the Cobra-based command-line interface.
 type CLI struct {
-	root     *cobra.Command
-	testnet  *e2e.Testnet
-	preserve bool
-	infp     infra.Provider
+	root         *cobra.Command
+	testnet      *e2e.Testnet
+	preserve     bool
+	startTimeout time.Duration
+	stallTimeout time.Duration
+	infp         infra.Provider
+	seed         int64
+	manifest     []byte
 }
 
 // NewCLI sets up the CLI.
@@ -44,120 +53,87 @@ func NewCLI() *CLI {
 			if err != nil {
 				return err
 			}
-			m, err := e2e.LoadManifest(file)
+			cli.manifest, err = os.ReadFile(file)
 			if err != nil {
 				return err
 			}
-
-			inft, err := cmd.Flags().GetString("infrastructure-type")
+			m, err := e2e.LoadManifest(file)
 			if err != nil {
 				return err
 			}
+			return cli.loadTestnet(cmd, file, m)
+		},
+		RunE: cli.runTestnet,
+	}
 
-			var ifd e2e.InfrastructureData
-			switch inft {
-			case "docker":
-				var err error
-				ifd, err = e2e.NewDockerInfrastructureData(m)
-				if err != nil {
-					return err
-				}
-			default:
-				return fmt.Errorf("unknown infrastructure type '%s'", inft)
-			}
+	cli.root.PersistentFlags().StringP("file", "f", "", "Testnet TOML manifest")
+	_ = cli.root.MarkPersistentFlagRequired("file")
 
-			testnet, err := e2e.LoadTestnet(file, ifd)
-			if err != nil {
-				return fmt.Errorf("loading testnet: %s", err)
-			}
+	cli.root.PersistentFlags().StringP("infrastructure-type", "", "docker", "Backing infrastructure used to run the testnet. One of 'docker', 'ssh' or 'kubernetes'")
 
-			cli.testnet = testnet
-			switch inft {
-			case "docker":
-				cli.infp = &docker.Provider{
-					ProviderData: infra.ProviderData{
-						Testnet:            testnet,
-						InfrastructureData: ifd,
-					},
-				}
-			default:
-				return fmt.Errorf("bad infrastructure type: %s", inft)
-			}
-			return nil
-		},
-		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := Cleanup(cli.testnet); err != nil {
-				return err
-			}
-			if err := Setup(cli.testnet, cli.infp); err != nil {
-				return err
-			}
+	cli.root.PersistentFlags().StringP("infrastructure-data", "", "", "Path to the JSON file describing the instances of the 'ssh' or 'kubernetes' infrastructure")
 
-			r := rand.New(rand.NewSource(randomSeed)) //nolint: gosec
+	cli.root.PersistentFlags().Int64Var(&cli.seed, "seed", randomSeed,
+		"Seed of the random choices made when injecting evidence, recorded in the testnet directory for replays")
 
-			chLoadResult := make(chan error)
-			ctx, loadCancel := context.WithCancel(context.Background())
-			defer loadCancel()
-			go func() {
-				err := Load(ctx, cli.testnet)
-				if err != nil {
-					logger.Error(fmt.Sprintf("Transaction load failed: %v", err.Error()))
-				}
-				chLoadResult <- err
-			}()
+	cli.root.PersistentFlags().DurationVar(&cli.startTimeout, "start-timeout", DefaultStartTimeout,
+		"How long to wait for each initial node to come up when starting the testnet")
+	cli.root.PersistentFlags().DurationVar(&cli.stallTimeout, "stall-timeout", DefaultStallTimeout,
+		"How long the testnet may go without producing a block while waiting for it")
 
-			if err := Start(cmd.Context(), cli.testnet, cli.infp); err != nil {
-				return err
-			}
+	cli.root.Flags().BoolVarP(&cli.preserve, "preserve", "p", false,
+		"Preserves the running of the test net after tests are completed")
 
-			if err := Wait(cmd.Context(), cli.testnet, 5); err != nil { // allow some txs to go through
+	cli.root.AddCommand(&cobra.Command{
+		Use:   "validate",
+		Short: "Validates the testnet manifest without setting anything up",
+		// The manifest is loaded by the command itself, so that all the
+		// problems in it are reported rather than only the first one.
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			file, err := cmd.Flags().GetString("file")
+			if err != nil {
 				return err
 			}
-
-			if cli.testnet.HasPerturbations() {
-				if err := Perturb(cmd.Context(), cli.testnet); err != nil {
-					return err
-				}
-				if err := Wait(cmd.Context(), cli.testnet, 5); err != nil { // allow some txs to go through
-					return err
-				}
+			problems := ValidateManifest(file)
+			for _, problem := range problems {
+				logger.Error("Invalid manifest", "file", file, "err", problem)
 			}
-
-			if cli.testnet.Evidence > 0 {
-				if err := InjectEvidence(ctx, r, cli.testnet, cli.testnet.Evidence); err != nil {
-					return err
-				}
-				if err := Wait(cmd.Context(), cli.testnet, 5); err != nil { // ensure chain progress
-					return err
-				}
+			if len(problems) > 0 {
+				return fmt.Errorf("manifest %v has %d problem(s)", file, len(problems))
 			}
+			logger.Info("Manifest is valid", "file", file)
+			return nil
+		},
+	})
 
-			loadCancel()
-			if err := <-chLoadResult; err != nil {
+	cli.root.AddCommand(&cobra.Command{
+		Use:   "replay",
+		Short: "Reruns the last run of the testnet with the same seed and manifest",
+		Long: `Reruns the testnet with the seed and the snapshot of the manifest that the
+last run recorded in the testnet directory, so that it goes through the same
+perturbation and evidence schedule, e.g. to reproduce a failure.`,
+		// The manifest is loaded from the snapshot instead of the given file.
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			file, err := cmd.Flags().GetString("file")
+			if err != nil {
 				return err
 			}
-			if err := Wait(cmd.Context(), cli.testnet, 5); err != nil { // wait for network to settle before tests
+			dir := testnetDir(file)
+			cli.seed, cli.manifest, err = loadReplay(dir)
+			if err != nil {
 				return err
 			}
-			if err := Test(cli.testnet, cli.infp.GetInfrastructureData()); err != nil {
+			m, err := e2e.LoadManifest(filepath.Join(dir, replayManifestFile))
+			if err != nil {
 				return err
 			}
-			if !cli.preserve {
-				if err := Cleanup(cli.testnet); err != nil {
-					return err
-				}
-			}
-			return nil
+			return cli.loadTestnet(cmd, file, m)
 		},
-	}
-
-	cli.root.PersistentFlags().StringP("file", "f", "", "Testnet TOML manifest")
-	_ = cli.root.MarkPersistentFlagRequired("file")
-
-	cli.root.PersistentFlags().StringP("infrastructure-type", "", "docker", "Backing infrastructure used to run the testnet. Only 'docker' is supported")
-
-	cli.root.Flags().BoolVarP(&cli.preserve, "preserve", "p", false,
-		"Preserves the running of the test net after tests are completed")
+		RunE: cli.runTestnet,
+	})
 
 	cli.root.AddCommand(&cobra.Command{
 		Use:   "setup",
@@ -178,7 +154,7 @@ func NewCLI() *CLI {
 			if err != nil {
 				return err
 			}
-			return Start(cmd.Context(), cli.testnet, cli.infp)
+			return Start(cmd.Context(), cli.testnet, cli.infp, cli.startTimeout)
 		},
 	})
 
@@ -194,7 +170,7 @@ func NewCLI() *CLI {
 		Use:   "wait",
 		Short: "Waits for a few blocks to be produced and all nodes to catch up",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return Wait(cmd.Context(), cli.testnet, 5)
+			return Wait(cmd.Context(), cli.testnet, 5, cli.stallTimeout)
 		},
 	})
 
@@ -231,7 +207,7 @@ func NewCLI() *CLI {
 
 			return InjectEvidence(
 				cmd.Context(),
-				rand.New(rand.NewSource(randomSeed)), //nolint: gosec
+				rand.New(rand.NewSource(cli.seed)), //nolint: gosec
 				cli.testnet,
 				amount,
 			)
@@ -240,17 +216,70 @@ func NewCLI() *CLI {
 
 	cli.root.AddCommand(&cobra.Command{
 		Use:   "test",
-		Short: "Runs test cases against a running testnet",
+		Short: "Runs test cases against a running testnet and checks the node logs for failures",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return Test(cmd.Context(), cli.testnet, cli.infp, nil)
+		},
+	})
+
+	attachCmd := &cobra.Command{
+		Use:   "attach [stage...]",
+		Short: "Runs stages against an already running testnet",
+		Long: `Discovers the running nodes of a testnet that was set up and started
+earlier, and runs the given stages in order against it, without setting it up
+or cleaning it up. The stages can be any of load, perturb, wait, test and
+benchmark. Load is generated from its position until all the following stages
+are done, or until canceled if it is the last stage.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateAttachStages(args); err != nil {
+				return err
+			}
+			output, err := cmd.Flags().GetString("output")
+			if err != nil {
+				return err
+			}
+			if err := Attach(cmd.Context(), cli.testnet, cli.infp); err != nil {
+				return err
+			}
+			return RunAttached(cmd.Context(), cli.testnet, cli.infp, args, cli.stallTimeout, output)
+		},
+	}
+	attachCmd.Flags().String("output", "",
+		"Writes a report of the benchmark stage to the given .json or .csv file")
+	cli.root.AddCommand(attachCmd)
+
+	snapshotCmd := &cobra.Command{
+		Use:   "snapshot <archive>",
+		Args:  cobra.ExactArgs(1),
+		Short: "Stops the testnet and archives the directories of its nodes",
+		Long: `Waits for the running testnet to reach the given height, if any, then stops
+it and archives the configuration and data of each node to a gzipped tarball,
+which the restore command can seed a new run of the testnet from.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return Test(cli.testnet, cli.infp.GetInfrastructureData())
+			height, err := cmd.Flags().GetInt64("height")
+			if err != nil {
+				return err
+			}
+			return Snapshot(cmd.Context(), cli.testnet, cli.infp, height, args[0])
+		},
+	}
+	snapshotCmd.Flags().Int64("height", 0, "Height to wait for before taking the snapshot")
+	cli.root.AddCommand(snapshotCmd)
+
+	cli.root.AddCommand(&cobra.Command{
+		Use:   "restore <archive>",
+		Args:  cobra.ExactArgs(1),
+		Short: "Sets up the testnet from an archive written by the snapshot command",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return Restore(cli.testnet, cli.infp, args[0])
 		},
 	})
 
 	cli.root.AddCommand(&cobra.Command{
 		Use:   "cleanup",
-		Short: "Removes the testnet directory",
+		Short: "Stops the testnet and removes the testnet directory",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return Cleanup(cli.testnet)
+			return cli.cleanup(cmd.Context())
 		},
 	})
 
@@ -270,7 +299,7 @@ func NewCLI() *CLI {
 		},
 	})
 
-	cli.root.AddCommand(&cobra.Command{
+	benchmarkCmd := &cobra.Command{
 		Use:   "benchmark",
 		Short: "Benchmarks testnet",
 		Long: `Benchmarks the following metrics:
@@ -283,7 +312,7 @@ over a 100 block sampling period.
 Does not run any perturbations.
 		`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := Cleanup(cli.testnet); err != nil {
+			if err := cli.cleanup(cmd.Context()); err != nil {
 				return err
 			}
 			if err := Setup(cli.testnet, cli.infp); err != nil {
@@ -301,16 +330,21 @@ Does not run any perturbations.
 				chLoadResult <- err
 			}()
 
-			if err := Start(cmd.Context(), cli.testnet, cli.infp); err != nil {
+			if err := Start(cmd.Context(), cli.testnet, cli.infp, cli.startTimeout); err != nil {
+				return err
+			}
+
+			if err := Wait(cmd.Context(), cli.testnet, 5, cli.stallTimeout); err != nil { // allow some txs to go through
 				return err
 			}
 
-			if err := Wait(cmd.Context(), cli.testnet, 5); err != nil { // allow some txs to go through
+			output, err := cmd.Flags().GetString("output")
+			if err != nil {
 				return err
 			}
 
 			// we benchmark performance over the next 100 blocks
-			if err := Benchmark(cmd.Context(), cli.testnet, 100); err != nil {
+			if err := Benchmark(cmd.Context(), cli.testnet, 100, output); err != nil {
 				return err
 			}
 
@@ -319,13 +353,193 @@ Does not run any perturbations.
 				return err
 			}
 
-			return Cleanup(cli.testnet)
+			return cli.cleanup(cmd.Context())
+		},
+	}
+	benchmarkCmd.Flags().String("output", "",
+		"Writes a report of the benchmark to the given .json or .csv file")
+	cli.root.AddCommand(benchmarkCmd)
+
+	cli.root.AddCommand(&cobra.Command{
+		Use:   "statesync-benchmark",
+		Short: "Benchmarks state sync",
+		Long: `Starts the testnet without its state sync nodes, lets it produce snapshots
+and then starts each state sync node, measuring the time it takes to catch up
+along with the number of snapshot chunks and bytes it applied.
+
+State sync nodes must have Prometheus enabled. Does not run any perturbations.
+		`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := cli.cleanup(cmd.Context()); err != nil {
+				return err
+			}
+			if err := Setup(cli.testnet, cli.infp); err != nil {
+				return err
+			}
+
+			if err := StateSyncBenchmark(cmd.Context(), cli.testnet, cli.infp, cli.startTimeout); err != nil {
+				return err
+			}
+
+			return cli.cleanup(cmd.Context())
 		},
 	})
 
 	return cli
 }
 
+// loadTestnet loads the testnet from the manifest m read from file, along with
+// the provider of the infrastructure given on the command line.
+func (cli *CLI) loadTestnet(cmd *cobra.Command, file string, m e2e.Manifest) error {
+	inft, err := cmd.Flags().GetString("infrastructure-type")
+	if err != nil {
+		return err
+	}
+
+	var ifd e2e.InfrastructureData
+	switch inft {
+	case "docker":
+		var err error
+		ifd, err = e2e.NewDockerInfrastructureData(m)
+		if err != nil {
+			return err
+		}
+	case "ssh", "kubernetes":
+		p, err := cmd.Flags().GetString("infrastructure-data")
+		if err != nil {
+			return err
+		}
+		if p == "" {
+			return fmt.Errorf("'--infrastructure-data' must be set for infrastructure type '%s'", inft)
+		}
+		ifd, err = e2e.InfrastructureDataFromFile(p)
+		if err != nil {
+			return fmt.Errorf("loading infrastructure data: %w", err)
+		}
+	default:
+		return fmt.Errorf("unknown infrastructure type '%s'", inft)
+	}
+
+	testnet, err := e2e.NewTestnetFromManifest(m, file, ifd)
+	if err != nil {
+		return fmt.Errorf("loading testnet: %s", err)
+	}
+
+	// Perturbations act on the local Docker containers.
+	if inft != "docker" && testnet.HasPerturbations() {
+		return errors.New("perturbations are only supported with infrastructure type 'docker'")
+	}
+
+	cli.testnet = testnet
+	pd := infra.ProviderData{
+		Testnet:            testnet,
+		InfrastructureData: ifd,
+	}
+	switch inft {
+	case "docker":
+		cli.infp = &docker.Provider{ProviderData: pd}
+	case "ssh":
+		cli.infp = &ssh.Provider{ProviderData: pd}
+	case "kubernetes":
+		cli.infp = &kubernetes.Provider{ProviderData: pd}
+	default:
+		return fmt.Errorf("bad infrastructure type: %s", inft)
+	}
+	return nil
+}
+
+// cleanup stops the testnet on its infrastructure and removes the testnet
+// directory. Nodes running on the local Docker are removed by Cleanup, while
+// the nodes on other infrastructures must be stopped by their provider.
+func (cli *CLI) cleanup(ctx context.Context) error {
+	if _, ok := cli.infp.(*docker.Provider); !ok {
+		logger.Info("Stopping testnet")
+		if err := cli.infp.StopTestnet(ctx); err != nil {
+			return err
+		}
+	}
+	return Cleanup(cli.testnet)
+}
+
+// runTestnet sets up and starts the testnet, runs its perturbations, evidence
+// and tests, and cleans it up unless it is to be preserved.
+func (cli *CLI) runTestnet(cmd *cobra.Command, _ []string) error {
+	if err := cli.cleanup(cmd.Context()); err != nil {
+		return err
+	}
+	if err := Setup(cli.testnet, cli.infp); err != nil {
+		return err
+	}
+	if err := recordReplay(cli.testnet.Dir, cli.seed, cli.manifest); err != nil {
+		return err
+	}
+
+	logger.Info("Running testnet", "seed", cli.seed)
+	r := rand.New(rand.NewSource(cli.seed)) //nolint: gosec
+
+	chLoadResult := make(chan error)
+	ctx, loadCancel := context.WithCancel(context.Background())
+	defer loadCancel()
+	go func() {
+		err := Load(ctx, cli.testnet)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Transaction load failed: %v", err.Error()))
+		}
+		chLoadResult <- err
+	}()
+
+	if err := Start(cmd.Context(), cli.testnet, cli.infp, cli.startTimeout); err != nil {
+		return err
+	}
+
+	var metrics *metricsCollector
+	if len(cli.testnet.MetricLimits) > 0 {
+		metrics = newMetricsCollector(cli.testnet)
+		metricsCtx, metricsCancel := context.WithCancel(cmd.Context())
+		defer metricsCancel()
+		go metrics.Run(metricsCtx, metricsScrapeInterval)
+	}
+
+	if err := Wait(cmd.Context(), cli.testnet, 5, cli.stallTimeout); err != nil { // allow some txs to go through
+		return err
+	}
+
+	if cli.testnet.HasPerturbations() {
+		if err := Perturb(cmd.Context(), cli.testnet); err != nil {
+			return err
+		}
+		if err := Wait(cmd.Context(), cli.testnet, 5, cli.stallTimeout); err != nil { // allow some txs to go through
+			return err
+		}
+	}
+
+	if cli.testnet.Evidence > 0 {
+		if err := InjectEvidence(ctx, r, cli.testnet, cli.testnet.Evidence); err != nil {
+			return err
+		}
+		if err := Wait(cmd.Context(), cli.testnet, 5, cli.stallTimeout); err != nil { // ensure chain progress
+			return err
+		}
+	}
+
+	loadCancel()
+	if err := <-chLoadResult; err != nil {
+		return err
+	}
+	if err := Wait(cmd.Context(), cli.testnet, 5, cli.stallTimeout); err != nil { // wait for network to settle before tests
+		return err
+	}
+	if err := Test(cmd.Context(), cli.testnet, cli.infp, metrics); err != nil {
+		return err
+	}
+	if !cli.preserve {
+		if err := cli.cleanup(cmd.Context()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Run runs the CLI.
 func (cli *CLI) Run() {
 	if err := cli.root.Execute(); err != nil {
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+
+	e2e "github.com/cometbft/cometbft/test/e2e/pkg"
+)
+
+// metricsScrapeInterval is how often the metrics of the nodes are scraped
+// while the testnet runs.
+const metricsScrapeInterval = 5 * time.Second
+
+// metricsCollector scrapes the Prometheus metrics of the nodes of a testnet,
+// keeping the peak value of each metric with a limit in the testnet, so that
+// the limits can be checked once the run is over.
+type metricsCollector struct {
+	testnet *e2e.Testnet
+	scrape  func(context.Context, *e2e.Node) (map[string]*dto.MetricFamily, error)
+
+	mtx   sync.Mutex
+	peaks map[*e2e.Node]map[string]float64
+}
+
+func newMetricsCollector(testnet *e2e.Testnet) *metricsCollector {
+	return &metricsCollector{
+		testnet: testnet,
+		scrape:  scrapeMetrics,
+		peaks:   make(map[*e2e.Node]map[string]float64),
+	}
+}
+
+// Run scrapes the nodes every interval until ctx is canceled.
+func (c *metricsCollector) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		c.Scrape(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Scrape scrapes the nodes with Prometheus enabled once. Nodes that can't be
+// scraped, e.g. because they are down or not started yet, are skipped.
+func (c *metricsCollector) Scrape(ctx context.Context) {
+	for _, node := range c.testnet.Nodes {
+		if !node.Prometheus || node.Mode == e2e.ModeLight {
+			continue
+		}
+		families, err := c.scrape(ctx, node)
+		if err != nil {
+			logger.Debug("Failed to scrape metrics", "node", node.Name, "err", err)
+			continue
+		}
+		c.observe(node, families)
+	}
+}
+
+func (c *metricsCollector) observe(node *e2e.Node, families map[string]*dto.MetricFamily) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	for name := range c.testnet.MetricLimits {
+		mf, ok := families[name]
+		if !ok {
+			continue
+		}
+		for _, m := range mf.Metric {
+			v := metricValue(m)
+			if c.peaks[node] == nil {
+				c.peaks[node] = make(map[string]float64)
+			}
+			if peak, ok := c.peaks[node][name]; !ok || v > peak {
+				c.peaks[node][name] = v
+			}
+		}
+	}
+}
+
+// Check returns an error listing the metrics that went over their limit on
+// any node.
+func (c *metricsCollector) Check() error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	names := make([]string, 0, len(c.testnet.MetricLimits))
+	for name := range c.testnet.MetricLimits {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var violations []string
+	for _, node := range c.testnet.Nodes {
+		for _, name := range names {
+			peak, ok := c.peaks[node][name]
+			if limit := c.testnet.MetricLimits[name]; ok && peak > limit {
+				violations = append(violations,
+					fmt.Sprintf("%v reached %v on %v (limit %v)", name, peak, node.Name, limit))
+			}
+		}
+	}
+	if len(violations) > 0 {
+		return fmt.Errorf("metrics over their limits: %v", strings.Join(violations, "; "))
+	}
+	return nil
+}
+
+// metricValue returns the value of a gauge, counter or untyped metric.
+func metricValue(m *dto.Metric) float64 {
+	switch {
+	case m.Gauge != nil:
+		return m.GetGauge().GetValue()
+	case m.Counter != nil:
+		return m.GetCounter().GetValue()
+	default:
+		return m.GetUntyped().GetValue()
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"github.com/stretchr/testify/require"
+
+	e2e "github.com/cometbft/cometbft/test/e2e/pkg"
+)
+
+func TestMetricsCollector(t *testing.T) {
+	testnet := &e2e.Testnet{
+		MetricLimits: map[string]float64{
+			"cometbft_consensus_rounds": 2,
+			"cometbft_mempool_size":     100,
+		},
+	}
+	testnet.Nodes = []*e2e.Node{
+		{Name: "validator01", Prometheus: true},
+		{Name: "validator02", Prometheus: true},
+		{Name: "full01"},
+	}
+
+	// Each scrape returns the next exposition of the node, and fails once
+	// they are exhausted.
+	scrapes := map[string][]string{
+		"validator01": {
+			"cometbft_consensus_rounds 3\ncometbft_mempool_size 10\n",
+			"cometbft_consensus_rounds 0\ncometbft_mempool_size 20\n",
+		},
+		"validator02": {
+			`cometbft_mempool_size{chain_id="ci"} 50` + "\n",
+			`cometbft_mempool_size{chain_id="ci"} 150` + "\n",
+		},
+	}
+	c := newMetricsCollector(testnet)
+	c.scrape = func(_ context.Context, node *e2e.Node) (map[string]*dto.MetricFamily, error) {
+		require.NotEqual(t, "full01", node.Name, "scraped a node without Prometheus")
+		if len(scrapes[node.Name]) == 0 {
+			return nil, errors.New("connection refused")
+		}
+		text := scrapes[node.Name][0]
+		scrapes[node.Name] = scrapes[node.Name][1:]
+		var parser expfmt.TextParser
+		return parser.TextToMetricFamilies(strings.NewReader(text))
+	}
+
+	c.Scrape(context.Background())
+	require.EqualError(t, c.Check(), "metrics over their limits: "+
+		"cometbft_consensus_rounds reached 3 on validator01 (limit 2)")
+
+	c.Scrape(context.Background())
+	c.Scrape(context.Background())
+	require.EqualError(t, c.Check(), "metrics over their limits: "+
+		"cometbft_consensus_rounds reached 3 on validator01 (limit 2); "+
+		"cometbft_mempool_size reached 150 on validator02 (limit 100)")
+}
+
+func TestMetricsCollectorWithinLimits(t *testing.T) {
+	testnet := &e2e.Testnet{
+		MetricLimits: map[string]float64{"cometbft_consensus_rounds": 2},
+		Nodes:        []*e2e.Node{{Name: "validator01", Prometheus: true}},
+	}
+	c := newMetricsCollector(testnet)
+	c.scrape = func(context.Context, *e2e.Node) (map[string]*dto.MetricFamily, error) {
+		var parser expfmt.TextParser
+		return parser.TextToMetricFamilies(strings.NewReader("cometbft_consensus_rounds 2\n"))
+	}
+	c.Scrape(context.Background())
+	require.NoError(t, c.Check())
+}
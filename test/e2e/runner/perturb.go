@@ -3,6 +3,8 @@ package main
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strconv"
 	"time"
 
 	"github.com/cometbft/cometbft/libs/log"
@@ -11,20 +13,46 @@ import (
 	"github.com/cometbft/cometbft/test/e2e/pkg/infra/docker"
 )
 
+// netemDevice is the network interface of a node container.
+const netemDevice = "eth0"
+
 // Perturbs a running testnet.
 func Perturb(ctx context.Context, testnet *e2e.Testnet) error {
 	for _, node := range testnet.Nodes {
 		for _, perturbation := range node.Perturbations {
-			_, err := PerturbNode(ctx, node, perturbation)
+			status, err := PerturbNode(ctx, node, perturbation)
 			if err != nil {
 				return err
 			}
+			if perturbation == e2e.PerturbationUpgrade && testnet.RollingUpgrade {
+				if err := waitForUpgrade(ctx, testnet, node, status.SyncInfo.LatestBlockHeight); err != nil {
+					return err
+				}
+			}
 			time.Sleep(3 * time.Second) // give network some time to recover between each
 		}
 	}
 	return nil
 }
 
+// rollingUpgradeBlocks is the number of blocks that must be produced after
+// each upgrade of a rolling upgrade before the next node is upgraded.
+const rollingUpgradeBlocks = 3
+
+// waitForUpgrade waits for the network to produce rollingUpgradeBlocks blocks
+// past the height at which the node recovered from its upgrade, and for all
+// nodes to catch up with them.
+func waitForUpgrade(ctx context.Context, testnet *e2e.Testnet, node *e2e.Node, height int64) error {
+	height += rollingUpgradeBlocks
+	logger.Info("perturb node", "msg",
+		log.NewLazySprintf("Waiting for the network to reach height %v after upgrading node %v...",
+			height, node.Name))
+	if _, err := waitForAllNodes(ctx, testnet, height, waitingTime(len(testnet.Nodes), rollingUpgradeBlocks)); err != nil {
+		return fmt.Errorf("network did not progress after upgrading node %v: %w", node.Name, err)
+	}
+	return nil
+}
+
 // PerturbNode perturbs a node with a given perturbation, returning its status
 // after recovering.
 func PerturbNode(ctx context.Context, node *e2e.Node, perturbation e2e.Perturbation) (*rpctypes.ResultStatus, error) {
@@ -79,6 +107,18 @@ func PerturbNode(ctx context.Context, node *e2e.Node, perturbation e2e.Perturbat
 			return nil, err
 		}
 
+	case e2e.PerturbationNetem:
+		logger.Info("perturb node", "msg", log.NewLazySprintf("Degrading network of node %v...", node.Name))
+		for _, args := range netemCommands(node, netemDevice) {
+			if err := docker.Exec(context.Background(), append([]string{"exec", name}, args...)...); err != nil {
+				return nil, err
+			}
+		}
+		time.Sleep(10 * time.Second)
+		if err := docker.Exec(context.Background(), "exec", name, "tc", "qdisc", "del", "dev", netemDevice, "root"); err != nil {
+			return nil, err
+		}
+
 	case e2e.PerturbationUpgrade:
 		oldV := node.Version
 		newV := node.Testnet.UpgradeVersion
@@ -117,3 +157,46 @@ func PerturbNode(ctx context.Context, node *e2e.Node, perturbation e2e.Perturbat
 		log.NewLazySprintf("Node %v recovered at height %v", node.Name, status.SyncInfo.LatestBlockHeight))
 	return status, nil
 }
+
+// netemCommands returns the tc commands emulating the node's netem settings on
+// the given device. Traffic to peers with their own link settings is steered
+// to a band of a prio qdisc with those settings, while other traffic goes
+// through the first band, with the node-wide settings.
+func netemCommands(node *e2e.Node, dev string) [][]string {
+	if len(node.NetemLinks) == 0 {
+		return [][]string{append([]string{"tc", "qdisc", "add", "dev", dev, "root"}, node.Netem.Args()...)}
+	}
+
+	peers := make([]*e2e.Node, 0, len(node.NetemLinks))
+	for peer := range node.NetemLinks {
+		peers = append(peers, peer)
+	}
+	sort.Slice(peers, func(i, j int) bool { return peers[i].Name < peers[j].Name })
+
+	// All traffic defaults to the first band, whatever its TOS.
+	root := []string{"tc", "qdisc", "add", "dev", dev, "root", "handle", "1:", "prio",
+		"bands", strconv.Itoa(len(peers) + 1), "priomap"}
+	for i := 0; i < 16; i++ {
+		root = append(root, "0")
+	}
+	cmds := [][]string{root}
+	if !node.Netem.IsZero() {
+		cmds = append(cmds, append([]string{"tc", "qdisc", "add", "dev", dev, "parent", "1:1", "handle", "10:"},
+			node.Netem.Args()...))
+	}
+	for i, peer := range peers {
+		band := i + 2
+		cmds = append(cmds, append([]string{"tc", "qdisc", "add", "dev", dev,
+			"parent", fmt.Sprintf("1:%d", band), "handle", fmt.Sprintf("%d0:", band)},
+			node.NetemLinks[peer].Args()...))
+
+		protocol, match, prefix := "ip", "ip", 32
+		if peer.InternalIP.To4() == nil {
+			protocol, match, prefix = "ipv6", "ip6", 128
+		}
+		cmds = append(cmds, []string{"tc", "filter", "add", "dev", dev, "parent", "1:", "protocol", protocol,
+			"prio", "1", "u32", "match", match, "dst", fmt.Sprintf("%v/%d", peer.InternalIP, prefix),
+			"flowid", fmt.Sprintf("1:%d", band)})
+	}
+	return cmds
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	e2e "github.com/cometbft/cometbft/test/e2e/pkg"
+)
+
+func TestNetemCommands(t *testing.T) {
+	node := &e2e.Node{
+		Name:  "validator01",
+		Netem: e2e.Netem{Delay: 100 * time.Millisecond, Jitter: 20 * time.Millisecond, Loss: 1.5},
+	}
+	require.Equal(t, [][]string{
+		{"tc", "qdisc", "add", "dev", "eth0", "root", "netem", "delay", "100000us", "20000us", "loss", "1.5%"},
+	}, netemCommands(node, "eth0"))
+
+	peer2 := &e2e.Node{Name: "validator02", InternalIP: net.ParseIP("10.186.73.3")}
+	peer3 := &e2e.Node{Name: "validator03", InternalIP: net.ParseIP("fd80:b10c::4")}
+	node.NetemLinks = map[*e2e.Node]e2e.Netem{
+		peer3: {Loss: 10},
+		peer2: {Delay: time.Second, Rate: "1mbit"},
+	}
+	require.Equal(t, [][]string{
+		{
+			"tc", "qdisc", "add", "dev", "eth0", "root", "handle", "1:", "prio", "bands", "3",
+			"priomap", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0", "0",
+		},
+		{"tc", "qdisc", "add", "dev", "eth0", "parent", "1:1", "handle", "10:",
+			"netem", "delay", "100000us", "20000us", "loss", "1.5%"},
+		{"tc", "qdisc", "add", "dev", "eth0", "parent", "1:2", "handle", "20:",
+			"netem", "delay", "1000000us", "rate", "1mbit"},
+		{"tc", "filter", "add", "dev", "eth0", "parent", "1:", "protocol", "ip", "prio", "1",
+			"u32", "match", "ip", "dst", "10.186.73.3/32", "flowid", "1:2"},
+		{"tc", "qdisc", "add", "dev", "eth0", "parent", "1:3", "handle", "30:", "netem", "loss", "10%"},
+		{"tc", "filter", "add", "dev", "eth0", "parent", "1:", "protocol", "ipv6", "prio", "1",
+			"u32", "match", "ip6", "dst", "fd80:b10c::4/128", "flowid", "1:3"},
+	}, netemCommands(node, "eth0"))
+}
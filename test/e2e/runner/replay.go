@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// replayFile records the seed of a run in the testnet directory.
+	replayFile = "replay.json"
+	// replayManifestFile is the snapshot of the manifest of a run, kept in
+	// the testnet directory next to replayFile.
+	replayManifestFile = "manifest.toml"
+)
+
+// replayRecord is the content of replayFile.
+type replayRecord struct {
+	Seed int64 `json:"seed"`
+}
+
+// testnetDir returns the testnet directory of the manifest file.
+func testnetDir(file string) string {
+	return strings.TrimSuffix(file, filepath.Ext(file))
+}
+
+// recordReplay writes the seed and a snapshot of the manifest of a run to the
+// testnet directory dir, so that the run can be replayed.
+func recordReplay(dir string, seed int64, manifest []byte) error {
+	bz, err := json.MarshalIndent(replayRecord{Seed: seed}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, replayFile), bz, 0o644); err != nil { //nolint:gosec
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, replayManifestFile), manifest, 0o644) //nolint:gosec
+}
+
+// loadReplay reads the seed and the manifest snapshot recorded in the testnet
+// directory dir.
+func loadReplay(dir string) (int64, []byte, error) {
+	bz, err := os.ReadFile(filepath.Join(dir, replayFile))
+	if err != nil {
+		return 0, nil, fmt.Errorf("no run to replay in %v: %w", dir, err)
+	}
+	var record replayRecord
+	if err := json.Unmarshal(bz, &record); err != nil {
+		return 0, nil, fmt.Errorf("invalid replay record %v: %w", replayFile, err)
+	}
+	manifest, err := os.ReadFile(filepath.Join(dir, replayManifestFile))
+	if err != nil {
+		return 0, nil, err
+	}
+	return record.Seed, manifest, nil
+}
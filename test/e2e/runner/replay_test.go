@@ -0,0 +1,29 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplayRecord(t *testing.T) {
+	dir := t.TempDir()
+	_, _, err := loadReplay(dir)
+	require.ErrorContains(t, err, "no run to replay")
+
+	manifest, err := os.ReadFile("../networks/ci.toml")
+	require.NoError(t, err)
+	require.NoError(t, recordReplay(dir, 42, manifest))
+
+	seed, snapshot, err := loadReplay(dir)
+	require.NoError(t, err)
+	require.EqualValues(t, 42, seed)
+	require.Equal(t, manifest, snapshot)
+	require.FileExists(t, filepath.Join(dir, replayManifestFile))
+}
+
+func TestTestnetDir(t *testing.T) {
+	require.Equal(t, "networks/ci", testnetDir("networks/ci.toml"))
+}
@@ -12,6 +12,13 @@ import (
 	"github.com/cometbft/cometbft/types"
 )
 
+const (
+	// nodePollInterval and nodePollMaxInterval bound the interval between
+	// status polls when waiting for a node.
+	nodePollInterval    = 100 * time.Millisecond
+	nodePollMaxInterval = 2 * time.Second
+)
+
 // waitForHeight waits for the network to reach a certain height (or above),
 // returning the highest height seen. Errors if the network is not making
 // progress at all.
@@ -76,16 +83,37 @@ func waitForHeight(ctx context.Context, testnet *e2e.Testnet, height int64) (*ty
 	}
 }
 
-// waitForNode waits for a node to become available and catch up to the given block height.
+// waitForNode waits for a node to become available and catch up to the given
+// block height, failing if it makes no progress for the given timeout.
 func waitForNode(ctx context.Context, node *e2e.Node, height int64, timeout time.Duration) (*rpctypes.ResultStatus, error) {
 	client, err := node.Client()
 	if err != nil {
 		return nil, err
 	}
+	return waitForNodeStatus(ctx, node.Name, client, height, timeout)
+}
 
+// nodeStatusClient is the part of the RPC client used to wait for a node.
+type nodeStatusClient interface {
+	Status(ctx context.Context) (*rpctypes.ResultStatus, error)
+}
+
+// waitForNodeStatus polls the status of the named node until it reaches the
+// given height, doubling the polling interval up to nodePollMaxInterval
+// while it makes no progress. It fails if the node makes no progress for the
+// given timeout, reporting the last error returned by the node, if any.
+func waitForNodeStatus(
+	ctx context.Context,
+	name string,
+	client nodeStatusClient,
+	height int64,
+	timeout time.Duration,
+) (*rpctypes.ResultStatus, error) {
 	timer := time.NewTimer(0)
 	defer timer.Stop()
+	interval := nodePollInterval
 	var curHeight int64
+	var lastErr error
 	lastChanged := time.Now()
 	for {
 		select {
@@ -93,18 +121,31 @@ func waitForNode(ctx context.Context, node *e2e.Node, height int64, timeout time
 			return nil, ctx.Err()
 		case <-timer.C:
 			status, err := client.Status(ctx)
-			switch {
-			case time.Since(lastChanged) > timeout:
-				return nil, fmt.Errorf("timed out waiting for %v to reach height %v", node.Name, height)
-			case err != nil:
-			case status.SyncInfo.LatestBlockHeight >= height && (height == 0 || !status.SyncInfo.CatchingUp):
-				return status, nil
-			case curHeight < status.SyncInfo.LatestBlockHeight:
-				curHeight = status.SyncInfo.LatestBlockHeight
-				lastChanged = time.Now()
+			lastErr = err
+			if err == nil {
+				if status.SyncInfo.LatestBlockHeight >= height && (height == 0 || !status.SyncInfo.CatchingUp) {
+					return status, nil
+				}
+				if curHeight < status.SyncInfo.LatestBlockHeight {
+					curHeight = status.SyncInfo.LatestBlockHeight
+					lastChanged = time.Now()
+					interval = nodePollInterval
+				}
 			}
 
-			timer.Reset(300 * time.Millisecond)
+			if time.Since(lastChanged) > timeout {
+				if lastErr != nil {
+					return nil, fmt.Errorf("timed out waiting for %v to reach height %v: %w", name, height, lastErr)
+				}
+				return nil, fmt.Errorf("timed out waiting for %v to reach height %v, stuck at height %v",
+					name, height, curHeight)
+			}
+
+			timer.Reset(interval)
+			interval *= 2
+			if interval > nodePollMaxInterval {
+				interval = nodePollMaxInterval
+			}
 		}
 	}
 }
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cometbft/cometbft/libs/log"
+	e2e "github.com/cometbft/cometbft/test/e2e/pkg"
+	"github.com/cometbft/cometbft/test/e2e/pkg/infra"
+)
+
+// snapshotInfoFile is the entry of a snapshot archive describing it.
+const snapshotInfoFile = "snapshot.json"
+
+// snapshotInfo describes a snapshot archive.
+type snapshotInfo struct {
+	Testnet string   `json:"testnet"`
+	Height  int64    `json:"height"`
+	Nodes   []string `json:"nodes"`
+}
+
+// Snapshot waits for the testnet to reach the given height, if any, then
+// stops it and archives the directory of each node, with its configuration
+// and data, to a gzipped tarball at path. The testnet is left stopped.
+func Snapshot(ctx context.Context, testnet *e2e.Testnet, p infra.Provider, height int64, path string) error {
+	block, _, err := waitForHeight(ctx, testnet, height)
+	if err != nil {
+		return err
+	}
+	logger.Info("snapshot", "msg", log.NewLazySprintf("Stopping testnet at height %v", block.Height))
+	if err := p.StopTestnet(ctx); err != nil {
+		return err
+	}
+
+	info := snapshotInfo{Testnet: testnet.Name, Height: block.Height}
+	for _, node := range testnet.Nodes {
+		info.Nodes = append(info.Nodes, node.Name)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := writeSnapshot(f, testnet.Dir, info); err != nil {
+		return fmt.Errorf("failed to write snapshot %v: %w", path, err)
+	}
+	logger.Info("snapshot", "msg", log.NewLazySprintf("Wrote snapshot of height %v to %v", info.Height, path))
+	return f.Close()
+}
+
+// Restore sets up the testnet anew, then replaces the directories of its
+// nodes with the ones archived at path by Snapshot, so that starting the
+// testnet resumes the chain from the snapshot height.
+func Restore(testnet *e2e.Testnet, p infra.Provider, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := Cleanup(testnet); err != nil {
+		return err
+	}
+	if err := Setup(testnet, p); err != nil {
+		return err
+	}
+	info, err := readSnapshot(f, testnet)
+	if err != nil {
+		return fmt.Errorf("failed to restore snapshot %v: %w", path, err)
+	}
+	logger.Info("restore", "msg", log.NewLazySprintf("Restored testnet %v at height %v", info.Testnet, info.Height))
+	return nil
+}
+
+// writeSnapshot writes a gzipped tarball with the info, followed by the
+// directories of its nodes under dir.
+func writeSnapshot(w io.Writer, dir string, info snapshotInfo) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+
+	bz, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := tw.WriteHeader(&tar.Header{
+		Name: snapshotInfoFile,
+		Mode: 0o644,
+		Size: int64(len(bz)),
+	}); err != nil {
+		return err
+	}
+	if _, err := tw.Write(bz); err != nil {
+		return err
+	}
+
+	for _, name := range info.Nodes {
+		err := filepath.Walk(filepath.Join(dir, name), func(path string, fi os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			// Sockets, e.g. of the ABCI application, are recreated on start.
+			if !fi.IsDir() && !fi.Mode().IsRegular() {
+				return nil
+			}
+			rel, err := filepath.Rel(dir, path)
+			if err != nil {
+				return err
+			}
+			hdr, err := tar.FileInfoHeader(fi, "")
+			if err != nil {
+				return err
+			}
+			hdr.Name = filepath.ToSlash(rel)
+			if err := tw.WriteHeader(hdr); err != nil {
+				return err
+			}
+			if fi.IsDir() {
+				return nil
+			}
+			file, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			_, err = io.Copy(tw, file)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+// readSnapshot extracts a snapshot written by writeSnapshot into the testnet
+// directory, replacing the directories of the nodes it contains. The snapshot
+// must be of a testnet with the same name, since it is the chain ID.
+func readSnapshot(r io.Reader, testnet *e2e.Testnet) (snapshotInfo, error) {
+	var info snapshotInfo
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return info, err
+	}
+	tr := tar.NewReader(gr)
+
+	hdr, err := tr.Next()
+	if err != nil {
+		return info, err
+	}
+	if hdr.Name != snapshotInfoFile {
+		return info, fmt.Errorf("expected %v first, got %v", snapshotInfoFile, hdr.Name)
+	}
+	if err := json.NewDecoder(tr).Decode(&info); err != nil {
+		return info, err
+	}
+	if info.Testnet != testnet.Name {
+		return info, fmt.Errorf("snapshot is of testnet %q, not %q", info.Testnet, testnet.Name)
+	}
+	for _, name := range info.Nodes {
+		if testnet.LookupNode(name) == nil {
+			return info, fmt.Errorf("snapshot has unknown node %q", name)
+		}
+		if err := os.RemoveAll(filepath.Join(testnet.Dir, name)); err != nil {
+			return info, err
+		}
+	}
+
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return info, nil
+		}
+		if err != nil {
+			return info, err
+		}
+		name := filepath.FromSlash(hdr.Name)
+		if !filepath.IsLocal(name) || !isSnapshotNodePath(name, info.Nodes) {
+			return info, fmt.Errorf("unexpected snapshot entry %q", hdr.Name)
+		}
+		path := filepath.Join(testnet.Dir, name)
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(path, os.FileMode(hdr.Mode).Perm()); err != nil {
+				return info, err
+			}
+		case tar.TypeReg:
+			if err := extractSnapshotFile(tr, path, os.FileMode(hdr.Mode).Perm()); err != nil {
+				return info, err
+			}
+		default:
+			return info, fmt.Errorf("unexpected snapshot entry type %v for %q", hdr.Typeflag, hdr.Name)
+		}
+	}
+}
+
+// isSnapshotNodePath returns whether the relative path is within the
+// directory of one of the nodes.
+func isSnapshotNodePath(path string, nodes []string) bool {
+	for _, node := range nodes {
+		if path == node || strings.HasPrefix(path, node+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+func extractSnapshotFile(r io.Reader, path string, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil { //nolint:gosec // the snapshot was written by the runner
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	e2e "github.com/cometbft/cometbft/test/e2e/pkg"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	src := t.TempDir()
+	files := map[string]string{
+		"validator01/config/genesis.json":               `{"chain_id":"ci"}`,
+		"validator01/data/blockstore.db/000001.log":     "blocks",
+		"validator02/data/priv_validator_state.json":    `{"height":"42"}`,
+		"validator02/config/node_key.json":              "key",
+		"prometheus/should-not-be-archived/or-restored": "x",
+	}
+	for name, content := range files {
+		path := filepath.Join(src, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+
+	var buf bytes.Buffer
+	info := snapshotInfo{Testnet: "ci", Height: 42, Nodes: []string{"validator01", "validator02"}}
+	require.NoError(t, writeSnapshot(&buf, src, info))
+
+	testnet := &e2e.Testnet{Name: "ci", Dir: t.TempDir()}
+	testnet.Nodes = []*e2e.Node{{Name: "validator01"}, {Name: "validator02"}}
+	// Files written by setup are replaced by the snapshot.
+	stale := filepath.Join(testnet.Dir, "validator01", "data", "stale")
+	require.NoError(t, os.MkdirAll(filepath.Dir(stale), 0o755))
+	require.NoError(t, os.WriteFile(stale, nil, 0o600))
+
+	restored, err := readSnapshot(bytes.NewReader(buf.Bytes()), testnet)
+	require.NoError(t, err)
+	require.Equal(t, info, restored)
+	for name, content := range files {
+		bz, err := os.ReadFile(filepath.Join(testnet.Dir, name))
+		if filepath.Dir(name) == "prometheus/should-not-be-archived" {
+			require.True(t, os.IsNotExist(err))
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, content, string(bz))
+	}
+	require.NoFileExists(t, stale)
+
+	testnet.Name = "other"
+	_, err = readSnapshot(bytes.NewReader(buf.Bytes()), testnet)
+	require.EqualError(t, err, `snapshot is of testnet "ci", not "other"`)
+}
+
+func TestReadSnapshotRejectsEscapingEntries(t *testing.T) {
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	for _, entry := range [][2]string{
+		{snapshotInfoFile, `{"testnet":"ci","height":1,"nodes":["validator01"]}`},
+		{"validator01/../../escaped", "x"},
+	} {
+		name, content := entry[0], entry[1]
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content))}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+
+	testnet := &e2e.Testnet{Name: "ci", Dir: t.TempDir(), Nodes: []*e2e.Node{{Name: "validator01"}}}
+	_, err := readSnapshot(&buf, testnet)
+	require.EqualError(t, err, `unexpected snapshot entry "validator01/../../escaped"`)
+}
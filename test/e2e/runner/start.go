@@ -11,7 +11,14 @@ import (
 	"github.com/cometbft/cometbft/test/e2e/pkg/infra"
 )
 
-func Start(ctx context.Context, testnet *e2e.Testnet, p infra.Provider) error {
+// DefaultStartTimeout is the default time Start waits for each initial node
+// to come up.
+const DefaultStartTimeout = 15 * time.Second
+
+// Start starts the testnet, first the initial nodes, waiting up to
+// startTimeout for each of them to come up, then the catch up nodes once the
+// network reaches their start height.
+func Start(ctx context.Context, testnet *e2e.Testnet, p infra.Provider, startTimeout time.Duration) error {
 	if len(testnet.Nodes) == 0 {
 		return fmt.Errorf("no nodes in testnet")
 	}
@@ -47,32 +54,10 @@ func Start(ctx context.Context, testnet *e2e.Testnet, p infra.Provider) error {
 		nodesAtZero = append(nodesAtZero, nodeQueue[0])
 		nodeQueue = nodeQueue[1:]
 	}
-	err := p.StartNodes(context.Background(), nodesAtZero...)
+	err := startNodes(ctx, p, nodesAtZero, nodeClient, startTimeout)
 	if err != nil {
 		return err
 	}
-	for _, node := range nodesAtZero {
-		if _, err := waitForNode(ctx, node, 0, 15*time.Second); err != nil {
-			return err
-		}
-		if node.PrometheusProxyPort > 0 {
-			logger.Info("start", "msg",
-				log.NewLazySprintf("Node %v up on http://%s:%v; with Prometheus on http://%s:%v/metrics",
-					node.Name,
-					node.ExternalIP,
-					node.ProxyPort,
-					node.ExternalIP,
-					node.PrometheusProxyPort,
-				),
-			)
-		} else {
-			logger.Info("start", "msg", log.NewLazySprintf("Node %v up on http://%s:%v",
-				node.Name,
-				node.ExternalIP,
-				node.ProxyPort,
-			))
-		}
-	}
 
 	networkHeight := testnet.InitialHeight
 
@@ -133,3 +118,49 @@ func Start(ctx context.Context, testnet *e2e.Testnet, p infra.Provider) error {
 
 	return nil
 }
+
+// startNodes starts the given nodes and waits for each of them to come up,
+// giving up on a node once it has failed to respond for timeout.
+func startNodes(
+	ctx context.Context,
+	p infra.Provider,
+	nodes []*e2e.Node,
+	clientFor func(*e2e.Node) (nodeStatusClient, error),
+	timeout time.Duration,
+) error {
+	if err := p.StartNodes(context.Background(), nodes...); err != nil {
+		return err
+	}
+	for _, node := range nodes {
+		client, err := clientFor(node)
+		if err != nil {
+			return fmt.Errorf("node %v: %w", node.Name, err)
+		}
+		if _, err := waitForNodeStatus(ctx, node.Name, client, 0, timeout); err != nil {
+			return err
+		}
+		if node.PrometheusProxyPort > 0 {
+			logger.Info("start", "msg",
+				log.NewLazySprintf("Node %v up on http://%s:%v; with Prometheus on http://%s:%v/metrics",
+					node.Name,
+					node.ExternalIP,
+					node.ProxyPort,
+					node.ExternalIP,
+					node.PrometheusProxyPort,
+				),
+			)
+		} else {
+			logger.Info("start", "msg", log.NewLazySprintf("Node %v up on http://%s:%v",
+				node.Name,
+				node.ExternalIP,
+				node.ProxyPort,
+			))
+		}
+	}
+	return nil
+}
+
+// nodeClient returns an RPC client for the node.
+func nodeClient(node *e2e.Node) (nodeStatusClient, error) {
+	return node.Client()
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	rpctypes "github.com/cometbft/cometbft/rpc/core/types"
+	e2e "github.com/cometbft/cometbft/test/e2e/pkg"
+	"github.com/cometbft/cometbft/test/e2e/pkg/infra"
+)
+
+// mockProvider is an infra.Provider recording the nodes it starts.
+type mockProvider struct {
+	infra.Provider
+	started []string
+}
+
+func (p *mockProvider) RunningNodes(context.Context) ([]string, error) {
+	return p.started, nil
+}
+
+func (p *mockProvider) StartNodes(_ context.Context, nodes ...*e2e.Node) error {
+	for _, node := range nodes {
+		p.started = append(p.started, node.Name)
+	}
+	return nil
+}
+
+// mockNodeStatus is a nodeStatusClient failing until it has been polled
+// failures times, or forever if failures is negative.
+type mockNodeStatus struct {
+	failures int
+	polls    int
+}
+
+func (m *mockNodeStatus) Status(context.Context) (*rpctypes.ResultStatus, error) {
+	m.polls++
+	if m.failures < 0 || m.polls <= m.failures {
+		return nil, errors.New("connection refused")
+	}
+	return &rpctypes.ResultStatus{}, nil
+}
+
+func TestStartNodes(t *testing.T) {
+	clients := map[string]*mockNodeStatus{
+		"validator01": {},
+		"validator02": {failures: 4},
+	}
+	clientFor := func(node *e2e.Node) (nodeStatusClient, error) {
+		return clients[node.Name], nil
+	}
+	nodes := []*e2e.Node{{Name: "validator01"}, {Name: "validator02"}}
+
+	p := &mockProvider{}
+	err := startNodes(context.Background(), p, nodes, clientFor, 5*time.Second)
+	require.NoError(t, err)
+	require.Equal(t, []string{"validator01", "validator02"}, p.started)
+	require.Equal(t, 1, clients["validator01"].polls)
+	require.Equal(t, 5, clients["validator02"].polls)
+}
+
+func TestStartNodesTimeout(t *testing.T) {
+	clients := map[string]*mockNodeStatus{
+		"validator01": {},
+		"validator02": {failures: -1},
+	}
+	clientFor := func(node *e2e.Node) (nodeStatusClient, error) {
+		return clients[node.Name], nil
+	}
+	nodes := []*e2e.Node{{Name: "validator01"}, {Name: "validator02"}}
+
+	err := startNodes(context.Background(), &mockProvider{}, nodes, clientFor, 300*time.Millisecond)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "validator02")
+	require.Contains(t, err.Error(), "connection refused")
+	require.NotContains(t, err.Error(), "validator01")
+	require.Greater(t, clients["validator02"].polls, 1)
+}
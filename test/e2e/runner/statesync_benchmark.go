@@ -0,0 +1,226 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+
+	e2e "github.com/cometbft/cometbft/test/e2e/pkg"
+	"github.com/cometbft/cometbft/test/e2e/pkg/infra"
+)
+
+const (
+	// stateSyncChunksMetric and stateSyncChunkBytesMetric are the names of the
+	// state sync reactor metrics scraped from the syncing node.
+	stateSyncChunksMetric     = "cometbft_statesync_chunks_applied"
+	stateSyncChunkBytesMetric = "cometbft_statesync_chunk_bytes_applied"
+
+	stateSyncPollInterval = 300 * time.Millisecond
+	stateSyncTimeout      = 3 * time.Minute
+)
+
+// stateSyncProgress is an observation of a state syncing node.
+type stateSyncProgress struct {
+	time       time.Time
+	height     int64
+	catchingUp bool
+	chunks     int64
+	chunkBytes int64
+}
+
+// synced returns true once the node has restored a snapshot and caught up.
+func (p stateSyncProgress) synced() bool {
+	return p.height > 0 && !p.catchingUp
+}
+
+// stateSyncProber observes the progress of a state syncing node.
+type stateSyncProber interface {
+	Probe(ctx context.Context) (stateSyncProgress, error)
+}
+
+// stateSyncReport is the result of a state sync benchmark for a single node.
+type stateSyncReport struct {
+	node       string
+	duration   time.Duration
+	height     int64
+	chunks     int64
+	chunkBytes int64
+}
+
+func (r stateSyncReport) OutputJSON(net *e2e.Testnet) string {
+	jsn, err := json.Marshal(map[string]interface{}{
+		"case":        filepath.Base(net.File),
+		"node":        r.node,
+		"height":      r.height,
+		"dur":         r.duration.Seconds(),
+		"chunks":      r.chunks,
+		"chunk_bytes": r.chunkBytes,
+	})
+	if err != nil {
+		return ""
+	}
+	return string(jsn)
+}
+
+// measureStateSync polls the prober until the node has caught up and returns
+// the time it took since start, along with the number of chunks and bytes
+// applied. It fails if the node makes no progress for the given timeout.
+func measureStateSync(
+	ctx context.Context,
+	node string,
+	start time.Time,
+	prober stateSyncProber,
+	interval, timeout time.Duration,
+) (stateSyncReport, error) {
+	report := stateSyncReport{node: node}
+	var last stateSyncProgress
+	lastChanged := time.Now()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		progress, err := prober.Probe(ctx)
+		if err == nil {
+			if progress.synced() {
+				report.duration = progress.time.Sub(start)
+				report.height = progress.height
+				report.chunks = progress.chunks
+				report.chunkBytes = progress.chunkBytes
+				return report, nil
+			}
+			if progress.height != last.height || progress.chunks != last.chunks {
+				last = progress
+				lastChanged = time.Now()
+			}
+		}
+		if time.Since(lastChanged) > timeout {
+			return report, fmt.Errorf("timed out waiting for %v to state sync", node)
+		}
+
+		select {
+		case <-ctx.Done():
+			return report, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// nodeStateSyncProber probes a testnet node through its RPC status and its
+// Prometheus metrics.
+type nodeStateSyncProber struct {
+	node *e2e.Node
+}
+
+// Probe implements stateSyncProber.
+func (p nodeStateSyncProber) Probe(ctx context.Context) (stateSyncProgress, error) {
+	client, err := p.node.Client()
+	if err != nil {
+		return stateSyncProgress{}, err
+	}
+	status, err := client.Status(ctx)
+	if err != nil {
+		return stateSyncProgress{}, err
+	}
+	progress := stateSyncProgress{
+		time:       time.Now(),
+		height:     status.SyncInfo.LatestBlockHeight,
+		catchingUp: status.SyncInfo.CatchingUp,
+	}
+
+	families, err := scrapeMetrics(ctx, p.node)
+	if err != nil {
+		return stateSyncProgress{}, err
+	}
+	if mf, ok := families[stateSyncChunksMetric]; ok && len(mf.Metric) > 0 {
+		progress.chunks = int64(mf.Metric[0].GetCounter().GetValue())
+	}
+	if mf, ok := families[stateSyncChunkBytesMetric]; ok && len(mf.Metric) > 0 {
+		progress.chunkBytes = int64(mf.Metric[0].GetCounter().GetValue())
+	}
+	return progress, nil
+}
+
+// scrapeMetrics fetches the Prometheus metrics of a node, which must have
+// Prometheus enabled.
+func scrapeMetrics(ctx context.Context, node *e2e.Node) (map[string]*dto.MetricFamily, error) {
+	url := fmt.Sprintf("http://%s:%d/metrics", node.ExternalIP, node.PrometheusProxyPort)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var parser expfmt.TextParser
+	return parser.TextToMetricFamilies(resp.Body)
+}
+
+// StateSyncBenchmark starts the testnet without its state sync nodes and, once
+// the network has reached the height at which they are configured to start,
+// starts each of them in turn and measures how long it takes it to state sync
+// and catch up, along with the number of chunks and bytes it applied.
+//
+// The state sync nodes must have Prometheus enabled.
+func StateSyncBenchmark(ctx context.Context, testnet *e2e.Testnet, p infra.Provider, startTimeout time.Duration) error {
+	var syncNodes, otherNodes []*e2e.Node
+	for _, node := range testnet.Nodes {
+		if node.StateSync && node.StartAt > 0 {
+			syncNodes = append(syncNodes, node)
+		} else {
+			otherNodes = append(otherNodes, node)
+		}
+	}
+	if len(syncNodes) == 0 {
+		return errors.New("no state sync nodes in testnet")
+	}
+	for _, node := range syncNodes {
+		if node.PrometheusProxyPort == 0 {
+			return fmt.Errorf("state sync node %v must have Prometheus enabled", node.Name)
+		}
+	}
+
+	// Start the rest of the network, leaving the state sync nodes out.
+	nodes := testnet.Nodes
+	testnet.Nodes = otherNodes
+	err := Start(ctx, testnet, p, startTimeout)
+	testnet.Nodes = nodes
+	if err != nil {
+		return err
+	}
+
+	for _, node := range syncNodes {
+		// Let the network produce snapshots up to the node's start height.
+		logger.Info("Waiting for network to advance before starting state sync node",
+			"node", node.Name, "height", node.StartAt)
+		block, blockID, err := waitForHeight(ctx, testnet, node.StartAt)
+		if err != nil {
+			return err
+		}
+		if err := UpdateConfigStateSync(node, block.Height, blockID.Hash.Bytes()); err != nil {
+			return err
+		}
+
+		logger.Info("Starting state sync node", "node", node.Name, "height", block.Height)
+		startAt := time.Now()
+		if err := p.StartNodes(ctx, node); err != nil {
+			return err
+		}
+		report, err := measureStateSync(ctx, node.Name, startAt, nodeStateSyncProber{node: node},
+			stateSyncPollInterval, stateSyncTimeout)
+		if err != nil {
+			return err
+		}
+		logger.Info(report.OutputJSON(testnet))
+	}
+	return nil
+}
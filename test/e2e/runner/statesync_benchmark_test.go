@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// mockStateSync is a stateSyncProber replaying a sequence of progress events.
+type mockStateSync struct {
+	events []stateSyncProgress
+	errs   []error
+	probes int
+}
+
+func (m *mockStateSync) Probe(context.Context) (stateSyncProgress, error) {
+	i := m.probes
+	if i >= len(m.events) {
+		i = len(m.events) - 1
+	}
+	m.probes++
+	if i < len(m.errs) && m.errs[i] != nil {
+		return stateSyncProgress{}, m.errs[i]
+	}
+	return m.events[i], nil
+}
+
+func TestMeasureStateSync(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	sync := &mockStateSync{
+		events: []stateSyncProgress{
+			{}, // node not up yet
+			{time: start.Add(time.Second), catchingUp: true},
+			{time: start.Add(2 * time.Second), catchingUp: true, chunks: 2, chunkBytes: 2048},
+			{time: start.Add(4 * time.Second), height: 100, catchingUp: true, chunks: 4, chunkBytes: 4000},
+			{time: start.Add(7 * time.Second), height: 105, chunks: 4, chunkBytes: 4000},
+			{time: start.Add(9 * time.Second), height: 106, chunks: 4, chunkBytes: 4000},
+		},
+		errs: []error{errors.New("connection refused")},
+	}
+
+	report, err := measureStateSync(context.Background(), "full01", start, sync, time.Millisecond, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, 5, sync.probes)
+	require.Equal(t, stateSyncReport{
+		node:       "full01",
+		duration:   7 * time.Second,
+		height:     105,
+		chunks:     4,
+		chunkBytes: 4000,
+	}, report)
+}
+
+func TestMeasureStateSyncTimeout(t *testing.T) {
+	start := time.Now()
+	sync := &mockStateSync{
+		events: []stateSyncProgress{{time: start, catchingUp: true, chunks: 1}},
+	}
+
+	_, err := measureStateSync(context.Background(), "full01", start, sync, time.Millisecond, 50*time.Millisecond)
+	require.Error(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = measureStateSync(ctx, "full01", start, sync, time.Millisecond, time.Minute)
+	require.ErrorIs(t, err, context.Canceled)
+}
@@ -1,17 +1,25 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"io"
 	"os"
 
 	e2e "github.com/cometbft/cometbft/test/e2e/pkg"
 	"github.com/cometbft/cometbft/test/e2e/pkg/exec"
+	"github.com/cometbft/cometbft/test/e2e/pkg/infra"
 )
 
-// Test runs test cases under tests/
-func Test(testnet *e2e.Testnet, ifd *e2e.InfrastructureData) error {
+// Test runs test cases under tests/, then checks that no node has logged a
+// panic, a consensus failure or a data race, and that no metric went over its
+// limit. The metrics are those gathered by the collector throughout the run
+// or, if it is nil, scraped once now.
+func Test(ctx context.Context, testnet *e2e.Testnet, p infra.Provider, metrics *metricsCollector) error {
 	logger.Info("Running tests in ./tests/...")
 
+	ifd := p.GetInfrastructureData()
 	err := os.Setenv("E2E_MANIFEST", testnet.File)
 	if err != nil {
 		return err
@@ -27,5 +35,61 @@ func Test(testnet *e2e.Testnet, ifd *e2e.InfrastructureData) error {
 		return err
 	}
 
-	return exec.CommandVerbose(context.Background(), "go", "test", "-count", "1", "./tests/...")
+	err = exec.CommandVerbose(ctx, "go", "test", "-count", "1", "./tests/...")
+	if err != nil {
+		return err
+	}
+
+	if err := CheckLogs(ctx, testnet, p); err != nil {
+		return err
+	}
+
+	if len(testnet.MetricLimits) == 0 {
+		return nil
+	}
+	logger.Info("Checking node metrics against their limits")
+	if metrics == nil {
+		metrics = newMetricsCollector(testnet)
+	}
+	metrics.Scrape(ctx)
+	return metrics.Check()
+}
+
+// CheckLogs scans the logs of all the nodes of the testnet, and returns an
+// error naming the node and the offending line if any of them panicked,
+// halted on a consensus failure or hit a data race. This catches crashes that
+// don't stop the rest of the network from producing blocks.
+func CheckLogs(ctx context.Context, testnet *e2e.Testnet, p infra.Provider) error {
+	logger.Info("Checking node logs for panics, consensus failures and data races")
+	for _, node := range testnet.Nodes {
+		logs, err := p.NodeLogs(ctx, node)
+		if err != nil {
+			return fmt.Errorf("failed to get logs of node %v: %w", node.Name, err)
+		}
+		lineNum, line, err := scanLogs(bytes.NewReader(logs))
+		if err != nil {
+			return fmt.Errorf("failed to scan logs of node %v: %w", node.Name, err)
+		}
+		if lineNum > 0 {
+			return fmt.Errorf("node %v failed at line %d of its logs: %s", node.Name, lineNum, line)
+		}
+	}
+	return nil
+}
+
+// scanLogs returns the first line of the logs that matches a forbidden log
+// rule, i.e. reports a panic, a consensus failure or a data race, along with
+// its 1-based line number. The line number is 0 if no such line is found.
+func scanLogs(logs io.Reader) (int, string, error) {
+	report, err := e2e.CheckLogs(logs, e2e.ForbiddenLogRules)
+	if err != nil {
+		return 0, "", err
+	}
+	var first e2e.LogMatch
+	for _, m := range report.Forbidden {
+		if first.Line == 0 || m.Line < first.Line {
+			first = m
+		}
+	}
+	return first.Line, first.Text, nil
 }
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	e2e "github.com/cometbft/cometbft/test/e2e/pkg"
+	"github.com/cometbft/cometbft/test/e2e/pkg/infra"
+)
+
+const healthyLogs = `I[2024-01-01|00:00:00.000] Started node                                 module=main nodeInfo="..."
+I[2024-01-01|00:00:01.000] finalized block                              module=state height=1 num_txs_res=0
+E[2024-01-01|00:00:02.000] Stopping peer for error                      module=p2p err="recovered from panic in peer"
+I[2024-01-01|00:00:03.000] committed state                              module=state height=2`
+
+func TestScanLogs(t *testing.T) {
+	testCases := []struct {
+		name     string
+		logs     string
+		lineNum  int
+		contains string
+	}{
+		{"healthy", healthyLogs, 0, ""},
+		{"empty", "", 0, ""},
+		{
+			"panic",
+			healthyLogs + "\npanic: runtime error: index out of range [3] with length 3\n\ngoroutine 1 [running]:\n",
+			5,
+			"index out of range",
+		},
+		{
+			"consensus failure",
+			healthyLogs + "\nE[2024-01-01|00:00:04.000] CONSENSUS FAILURE!!!                         module=consensus err=\"boom\"\n",
+			5,
+			"CONSENSUS FAILURE",
+		},
+		{
+			"data race",
+			healthyLogs + "\n==================\nWARNING: DATA RACE\nWrite at 0x00c000 by goroutine 7:\n",
+			6,
+			"DATA RACE",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			lineNum, line, err := scanLogs(strings.NewReader(tc.logs))
+			require.NoError(t, err)
+			require.Equal(t, tc.lineNum, lineNum)
+			require.Contains(t, line, tc.contains)
+		})
+	}
+}
+
+func TestCheckLogsRules(t *testing.T) {
+	rules := append(append([]e2e.LogRule{}, e2e.ForbiddenLogRules...),
+		e2e.LogRule{Name: "committed state", Pattern: regexp.MustCompile(`committed state`), Required: true},
+		e2e.LogRule{Name: "state sync", Pattern: regexp.MustCompile(`module=statesync`), Required: true},
+	)
+	report, err := e2e.CheckLogs(strings.NewReader(healthyLogs), rules)
+	require.NoError(t, err)
+	require.False(t, report.OK())
+	require.Empty(t, report.Forbidden)
+	require.Equal(t, []e2e.LogMatch{{Rule: "committed state", Line: 4, Text: strings.Split(healthyLogs, "\n")[3]}},
+		report.Found)
+	require.Equal(t, []string{"state sync"}, report.Missing)
+	require.Equal(t, "required state sync not found\nrequired committed state found at line 4\n", report.String())
+}
+
+// mockLogsProvider is an infra.Provider serving fixed logs for each node.
+type mockLogsProvider struct {
+	infra.ProviderData
+	logs map[string]string
+}
+
+func (p mockLogsProvider) Setup() error                                   { return nil }
+func (p mockLogsProvider) StartNodes(context.Context, ...*e2e.Node) error { return nil }
+func (p mockLogsProvider) StopTestnet(context.Context) error              { return nil }
+func (p mockLogsProvider) RunningNodes(context.Context) ([]string, error) { return nil, nil }
+func (p mockLogsProvider) NodeLogs(_ context.Context, n *e2e.Node) ([]byte, error) {
+	return []byte(p.logs[n.Name]), nil
+}
+
+func TestCheckLogs(t *testing.T) {
+	testnet := &e2e.Testnet{Nodes: []*e2e.Node{{Name: "validator01"}, {Name: "validator02"}}}
+	p := mockLogsProvider{logs: map[string]string{
+		"validator01": healthyLogs,
+		"validator02": healthyLogs,
+	}}
+	require.NoError(t, CheckLogs(context.Background(), testnet, p))
+
+	p.logs["validator02"] = healthyLogs + "\npanic: boom"
+	err := CheckLogs(context.Background(), testnet, p)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "validator02")
+	require.Contains(t, err.Error(), "line 5")
+	require.Contains(t, err.Error(), "panic: boom")
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	e2e "github.com/cometbft/cometbft/test/e2e/pkg"
+)
+
+// ValidateManifest loads the testnet manifest in file and checks it without
+// setting anything up, returning all the problems found.
+func ValidateManifest(file string) []error {
+	manifest, err := e2e.LoadManifest(file)
+	if err != nil {
+		return []error{err}
+	}
+	if problems := manifestProblems(manifest); len(problems) > 0 {
+		return problems
+	}
+
+	// Building the testnet runs the remaining checks, but stops at the first
+	// problem it finds.
+	ifd, err := e2e.NewDockerInfrastructureData(manifest)
+	if err != nil {
+		return []error{err}
+	}
+	if _, err := e2e.NewTestnetFromManifest(manifest, file, ifd); err != nil {
+		return []error{err}
+	}
+	return nil
+}
+
+// manifestProblems runs semantic checks on the manifest, returning all the
+// problems found.
+func manifestProblems(m e2e.Manifest) []error {
+	var problems []error
+	if len(m.Nodes) == 0 {
+		problems = append(problems, errors.New("manifest has no nodes"))
+	}
+
+	names := make([]string, 0, len(m.Nodes))
+	for name := range m.Nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// Node names are used as host names, so they must be unique regardless
+	// of case.
+	seen := make(map[string]string, len(names))
+	for _, name := range names {
+		key := strings.ToLower(name)
+		if other, ok := seen[key]; ok {
+			problems = append(problems, fmt.Errorf("duplicate node name %q (also %q)", name, other))
+			continue
+		}
+		seen[key] = name
+	}
+
+	numValidators := 0
+	for _, name := range names {
+		node := m.Nodes[name]
+		if m.Validators == nil && (node.Mode == "" || node.Mode == string(e2e.ModeValidator)) {
+			numValidators++
+		}
+		for _, p := range node.Perturb {
+			switch e2e.Perturbation(p) {
+			case e2e.PerturbationDisconnect, e2e.PerturbationKill, e2e.PerturbationPause,
+				e2e.PerturbationRestart, e2e.PerturbationUpgrade, e2e.PerturbationNetem:
+			default:
+				problems = append(problems, fmt.Errorf("node %q has invalid perturbation %q", name, p))
+			}
+		}
+	}
+
+	// If the genesis validators are given explicitly, they must be nodes in
+	// the manifest.
+	if m.Validators != nil {
+		validators := make([]string, 0, len(*m.Validators))
+		for name := range *m.Validators {
+			validators = append(validators, name)
+		}
+		sort.Strings(validators)
+		for _, name := range validators {
+			if _, ok := m.Nodes[name]; !ok {
+				problems = append(problems, fmt.Errorf("unknown validator %q", name))
+				continue
+			}
+			if (*m.Validators)[name] > 0 {
+				numValidators++
+			}
+		}
+	}
+	if numValidators == 0 {
+		problems = append(problems, errors.New("manifest has no validators"))
+	}
+
+	if m.Evidence < 0 {
+		problems = append(problems, fmt.Errorf("evidence count %d must not be negative", m.Evidence))
+	}
+	for _, evType := range m.EvidenceTypes {
+		switch e2e.EvidenceType(evType) {
+		case e2e.EvidenceDuplicateVote, e2e.EvidenceLunatic, e2e.EvidenceAmnesia:
+		default:
+			problems = append(problems, fmt.Errorf("invalid evidence type %q", evType))
+		}
+	}
+	for _, name := range m.EvidenceValidators {
+		if _, ok := m.Nodes[name]; !ok {
+			problems = append(problems, fmt.Errorf("unknown evidence validator %q", name))
+		}
+	}
+	return problems
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeManifest(t *testing.T, manifest string) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "testnet.toml")
+	require.NoError(t, os.WriteFile(file, []byte(manifest), 0o600))
+	return file
+}
+
+func TestValidateManifest(t *testing.T) {
+	require.Empty(t, ValidateManifest("../networks/ci.toml"))
+
+	problems := ValidateManifest(writeManifest(t, `
+evidence = -1
+evidence_types = ["lunatic", "equivocation"]
+evidence_validators = ["validator02"]
+
+[node.validator01]
+perturb = ["explode"]
+[node.Validator01]
+mode = "full"
+[node.full01]
+mode = "full"
+`))
+	msgs := make([]string, 0, len(problems))
+	for _, problem := range problems {
+		msgs = append(msgs, problem.Error())
+	}
+	require.Equal(t, []string{
+		`duplicate node name "validator01" (also "Validator01")`,
+		`node "validator01" has invalid perturbation "explode"`,
+		"evidence count -1 must not be negative",
+		`invalid evidence type "equivocation"`,
+		`unknown evidence validator "validator02"`,
+	}, msgs)
+}
+
+func TestValidateManifestNoValidators(t *testing.T) {
+	problems := ValidateManifest(writeManifest(t, `
+validators = {full01 = 0, full02 = 100}
+
+[node.full01]
+mode = "full"
+`))
+	require.Len(t, problems, 2)
+	require.EqualError(t, problems[0], `unknown validator "full02"`)
+	require.EqualError(t, problems[1], "manifest has no validators")
+}
+
+func TestValidateManifestDuplicateKey(t *testing.T) {
+	problems := ValidateManifest(writeManifest(t, `
+[node.validator01]
+[node.validator01]
+`))
+	require.Len(t, problems, 1)
+	require.ErrorContains(t, problems[0], "validator01")
+}
+
+func TestValidateManifestRollingUpgrade(t *testing.T) {
+	problems := ValidateManifest(writeManifest(t, `
+rolling_upgrade = true
+
+[node.validator01]
+[node.validator02]
+`))
+	require.Len(t, problems, 1)
+	require.EqualError(t, problems[0], "rolling upgrade requires nodes with the upgrade perturbation")
+
+	require.Empty(t, ValidateManifest(writeManifest(t, `
+rolling_upgrade = true
+upgrade_version = "cometbft/e2e-node:local-version"
+
+[node.validator01]
+version = "cometbft/e2e-node:v0.38.0"
+perturb = ["upgrade"]
+[node.validator02]
+`)))
+}
@@ -2,26 +2,47 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/cometbft/cometbft/libs/log"
 	e2e "github.com/cometbft/cometbft/test/e2e/pkg"
 )
 
+const (
+	// DefaultStallTimeout is the default time Wait allows the network to go
+	// without producing a block before giving up.
+	DefaultStallTimeout = 30 * time.Second
+
+	stallPollInterval = time.Second
+)
+
 // Wait waits for a number of blocks to be produced, and for all nodes to catch
-// up with it.
-func Wait(ctx context.Context, testnet *e2e.Testnet, blocks int64) error {
+// up with it. It fails if no block is produced for stallTimeout.
+func Wait(ctx context.Context, testnet *e2e.Testnet, blocks int64, stallTimeout time.Duration) error {
 	block, _, err := waitForHeight(ctx, testnet, 0)
 	if err != nil {
 		return err
 	}
-	return WaitUntil(ctx, testnet, block.Height+blocks)
+	return WaitUntil(ctx, testnet, block.Height+blocks, stallTimeout)
 }
 
-// WaitUntil waits until a given height has been reached.
-func WaitUntil(ctx context.Context, testnet *e2e.Testnet, height int64) error {
+// WaitUntil waits until a given height has been reached. It fails if no block
+// is produced for stallTimeout.
+func WaitUntil(ctx context.Context, testnet *e2e.Testnet, height int64, stallTimeout time.Duration) error {
 	logger.Info("wait until", "msg", log.NewLazySprintf("Waiting for all nodes to reach height %v...", height))
-	_, err := waitForAllNodes(ctx, testnet, height, waitingTime(len(testnet.Nodes), height))
+	var validators []string
+	for node := range testnet.Validators {
+		validators = append(validators, node.Name)
+	}
+	sort.Strings(validators)
+	err := waitForProduction(ctx, newTestnetHeights(testnet), height, validators, stallPollInterval, stallTimeout)
+	if err != nil {
+		return err
+	}
+	_, err = waitForAllNodes(ctx, testnet, height, waitingTime(len(testnet.Nodes), height))
 	if err != nil {
 		return err
 	}
@@ -33,3 +54,104 @@ func WaitUntil(ctx context.Context, testnet *e2e.Testnet, height int64) error {
 func waitingTime(nodes int, height int64) time.Duration {
 	return time.Duration(20+(int64(nodes)*height)) * time.Second
 }
+
+// heightProber reports the latest block height of each node it can reach.
+type heightProber interface {
+	Heights(ctx context.Context) map[string]int64
+}
+
+// waitForProduction polls the node heights until the highest of them reaches
+// the given height. It fails if the highest height does not advance for
+// stallTimeout, reporting which of the given validators are behind it.
+func waitForProduction(
+	ctx context.Context,
+	prober heightProber,
+	height int64,
+	validators []string,
+	interval, stallTimeout time.Duration,
+) error {
+	var maxHeight int64
+	lastIncrease := time.Now()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		heights := prober.Heights(ctx)
+		for _, h := range heights {
+			if h > maxHeight {
+				maxHeight = h
+				lastIncrease = time.Now()
+			}
+		}
+		if maxHeight >= height {
+			return nil
+		}
+		if time.Since(lastIncrease) > stallTimeout {
+			return stallError(maxHeight, validators, heights)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// stallError describes a network whose block production stalled at height,
+// listing the validators that are behind it or could not be reached.
+func stallError(height int64, validators []string, heights map[string]int64) error {
+	var behind []string
+	for _, name := range validators {
+		h, ok := heights[name]
+		switch {
+		case !ok:
+			behind = append(behind, fmt.Sprintf("%v (unreachable)", name))
+		case h < height:
+			behind = append(behind, fmt.Sprintf("%v (height %v)", name, h))
+		}
+	}
+	if len(behind) == 0 {
+		return fmt.Errorf("production stalled at height %v", height)
+	}
+	return fmt.Errorf("production stalled at height %v; validators behind: %v",
+		height, strings.Join(behind, ", "))
+}
+
+// testnetHeights is a heightProber querying the status of the testnet nodes.
+type testnetHeights struct {
+	testnet *e2e.Testnet
+	clients map[string]nodeStatusClient
+}
+
+func newTestnetHeights(testnet *e2e.Testnet) *testnetHeights {
+	return &testnetHeights{testnet: testnet, clients: map[string]nodeStatusClient{}}
+}
+
+// Heights implements heightProber.
+func (p *testnetHeights) Heights(ctx context.Context) map[string]int64 {
+	heights := make(map[string]int64, len(p.testnet.Nodes))
+	for _, node := range p.testnet.Nodes {
+		if node.Stateless() {
+			continue
+		}
+		client, ok := p.clients[node.Name]
+		if !ok {
+			var err error
+			client, err = node.Client()
+			if err != nil {
+				continue
+			}
+			p.clients[node.Name] = client
+		}
+
+		subctx, cancel := context.WithTimeout(ctx, time.Second)
+		status, err := client.Status(subctx)
+		cancel()
+		if err != nil {
+			continue
+		}
+		heights[node.Name] = status.SyncInfo.LatestBlockHeight
+	}
+	return heights
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// mockHeights is a heightProber replaying a sequence of node heights, and
+// repeating the last one once the sequence is exhausted.
+type mockHeights struct {
+	heights []map[string]int64
+	probes  int
+}
+
+func (m *mockHeights) Heights(context.Context) map[string]int64 {
+	i := m.probes
+	if i >= len(m.heights) {
+		i = len(m.heights) - 1
+	}
+	m.probes++
+	return m.heights[i]
+}
+
+func TestWaitForProduction(t *testing.T) {
+	prober := &mockHeights{heights: []map[string]int64{
+		{},
+		{"validator01": 1, "validator02": 1},
+		{"validator01": 2, "validator02": 1},
+		{"validator01": 2, "validator02": 2},
+		{"validator01": 3, "validator02": 2},
+		{"validator01": 4, "validator02": 4},
+	}}
+
+	err := waitForProduction(context.Background(), prober, 4, []string{"validator01", "validator02"},
+		time.Millisecond, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, 6, prober.probes)
+}
+
+func TestWaitForProductionStalled(t *testing.T) {
+	prober := &mockHeights{heights: []map[string]int64{
+		{"validator01": 4, "validator02": 3},
+		{"validator01": 5, "validator02": 3, "full01": 5},
+	}}
+	validators := []string{"validator01", "validator02", "validator03"}
+
+	start := time.Now()
+	stallTimeout := 50 * time.Millisecond
+	err := waitForProduction(context.Background(), prober, 10, validators, time.Millisecond, stallTimeout)
+	require.EqualError(t, err,
+		"production stalled at height 5; validators behind: validator02 (height 3), validator03 (unreachable)")
+	require.GreaterOrEqual(t, time.Since(start), stallTimeout)
+	require.Greater(t, prober.probes, 2)
+}
@@ -3,14 +3,25 @@ package e2e_test
 import (
 	"bytes"
 	"context"
+	"errors"
+	"io"
 	"testing"
 
+	"github.com/cosmos/gogoproto/proto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/cometbft/cometbft/crypto/tmhash"
+	cmtbytes "github.com/cometbft/cometbft/libs/bytes"
+	cmtproto "github.com/cometbft/cometbft/proto/tendermint/types"
 	e2e "github.com/cometbft/cometbft/test/e2e/pkg"
+	"github.com/cometbft/cometbft/types"
 )
 
+// blockPartsSamples is the maximum number of heights per node for which the
+// block hash is re-derived from the block parts.
+const blockPartsSamples = 10
+
 // Tests that block headers are identical across nodes where present.
 func TestBlock_Header(t *testing.T) {
 	blocks := fetchBlockChain(t)
@@ -96,6 +107,142 @@ func TestBlock_Range(t *testing.T) {
 	})
 }
 
+// Tests that, for a sample of heights, the block hash re-derived from the
+// block's parts matches the one reported by the node.
+func TestBlock_PartsHash(t *testing.T) {
+	testNode(t, func(t *testing.T, node e2e.Node) {
+		if node.Mode == e2e.ModeSeed {
+			return
+		}
+
+		client, err := node.Client()
+		require.NoError(t, err)
+		status, err := client.Status(ctx)
+		require.NoError(t, err)
+
+		first := status.SyncInfo.EarliestBlockHeight
+		last := status.SyncInfo.LatestBlockHeight
+		if node.RetainBlocks > 0 {
+			first++ // avoid race conditions with block pruning
+		}
+		if first > last {
+			return
+		}
+
+		step := (last-first)/blockPartsSamples + 1
+		for h := first; h <= last; h += step {
+			resp, err := client.Block(ctx, &h)
+			require.NoError(t, err)
+
+			parts, err := resp.Block.MakePartSet(types.BlockPartSizeBytes)
+			require.NoError(t, err)
+			require.Equal(t, resp.BlockID.PartSetHeader, parts.Header(),
+				"part set header mismatch for height %d", h)
+
+			hash, err := blockHashFromParts(parts.Header(), partsOf(parts))
+			require.NoError(t, err, "failed to re-derive block at height %d", h)
+			require.Equal(t, resp.BlockID.Hash, hash,
+				"re-derived block hash mismatch for height %d", h)
+		}
+	})
+}
+
+// blockHashFromParts reassembles a block from the given parts, verifying them
+// against the part set header, and returns the hash of the decoded block.
+func blockHashFromParts(header types.PartSetHeader, parts []*types.Part) (cmtbytes.HexBytes, error) {
+	ps := types.NewPartSetFromHeader(header)
+	for _, part := range parts {
+		if _, err := ps.AddPart(part); err != nil {
+			return nil, err
+		}
+	}
+	if !ps.IsComplete() {
+		return nil, errors.New("incomplete part set")
+	}
+
+	bz, err := io.ReadAll(ps.GetReader())
+	if err != nil {
+		return nil, err
+	}
+	pbb := new(cmtproto.Block)
+	if err := proto.Unmarshal(bz, pbb); err != nil {
+		return nil, err
+	}
+	block, err := types.BlockFromProto(pbb)
+	if err != nil {
+		return nil, err
+	}
+	return block.Hash(), nil
+}
+
+// partsOf returns the parts of a complete part set.
+func partsOf(ps *types.PartSet) []*types.Part {
+	parts := make([]*types.Part, ps.Total())
+	for i := range parts {
+		parts[i] = ps.GetPart(i)
+	}
+	return parts
+}
+
+func TestBlockHashFromParts(t *testing.T) {
+	block := types.MakeBlock(1, types.Data{Txs: types.Txs{types.Tx("foo=bar")}}, &types.Commit{}, nil)
+	block.ChainID = "test-chain"
+	block.ProposerAddress = make([]byte, 20)
+	block.ValidatorsHash = tmhash.Sum([]byte("validators"))
+	block.NextValidatorsHash = block.ValidatorsHash
+	expected := block.Hash()
+	require.NotNil(t, expected)
+
+	// Use small parts so that the block is split into several of them.
+	ps, err := block.MakePartSet(64)
+	require.NoError(t, err)
+	require.Greater(t, ps.Total(), uint32(1))
+
+	hash, err := blockHashFromParts(ps.Header(), partsOf(ps))
+	require.NoError(t, err)
+	require.Equal(t, expected, hash)
+
+	t.Run("tampered part", func(t *testing.T) {
+		parts := partsOf(ps)
+		tampered := &types.Part{
+			Index: parts[0].Index,
+			Bytes: append(cmtbytes.HexBytes{}, parts[0].Bytes...),
+			Proof: parts[0].Proof,
+		}
+		tampered.Bytes[0] ^= 0xff
+		parts[0] = tampered
+
+		_, err := blockHashFromParts(ps.Header(), parts)
+		require.Error(t, err)
+	})
+
+	t.Run("missing part", func(t *testing.T) {
+		parts := partsOf(ps)
+		_, err := blockHashFromParts(ps.Header(), parts[:len(parts)-1])
+		require.Error(t, err)
+	})
+
+	t.Run("parts of another block", func(t *testing.T) {
+		other := types.MakeBlock(1, types.Data{Txs: types.Txs{types.Tx("foo=baz")}}, &types.Commit{}, nil)
+		other.ChainID = block.ChainID
+		other.ProposerAddress = block.ProposerAddress
+		other.ValidatorsHash = block.ValidatorsHash
+		other.NextValidatorsHash = block.NextValidatorsHash
+		ops, err := other.MakePartSet(64)
+		require.NoError(t, err)
+
+		// The parts are consistent with their own header, but re-derive to a
+		// different hash.
+		hash, err := blockHashFromParts(ops.Header(), partsOf(ops))
+		require.NoError(t, err)
+		require.NotEqual(t, expected, hash)
+
+		// They don't verify against the original header.
+		_, err = blockHashFromParts(ps.Header(), partsOf(ops))
+		require.Error(t, err)
+	})
+}
+
 func TestBlock_SignedData(t *testing.T) {
 	t.Helper()
 	testNode(t, func(t *testing.T, node e2e.Node) {
@@ -1,6 +1,7 @@
 package e2e_test
 
 import (
+	"bytes"
 	"context"
 	"os"
 	"path/filepath"
@@ -12,6 +13,8 @@ import (
 	rpchttp "github.com/cometbft/cometbft/rpc/client/http"
 	rpctypes "github.com/cometbft/cometbft/rpc/core/types"
 	e2e "github.com/cometbft/cometbft/test/e2e/pkg"
+	"github.com/cometbft/cometbft/test/e2e/pkg/infra"
+	"github.com/cometbft/cometbft/test/e2e/pkg/infra/docker"
 	"github.com/cometbft/cometbft/types"
 )
 
@@ -90,6 +93,32 @@ func loadTestnet(t *testing.T) e2e.Testnet {
 	return *testnet
 }
 
+// checkNodeLogs checks the logs of a node against the forbidden log rules and
+// the given ones. The report is attached to the test, which fails if a
+// forbidden rule matched or a required one didn't.
+func checkNodeLogs(t *testing.T, node e2e.Node, rules ...e2e.LogRule) e2e.LogReport {
+	t.Helper()
+
+	testnet := loadTestnet(t)
+	p := docker.Provider{ProviderData: infra.ProviderData{Testnet: &testnet}}
+	logs, err := p.NodeLogs(ctx, &node)
+	require.NoError(t, err)
+
+	rules = append(append([]e2e.LogRule{}, e2e.ForbiddenLogRules...), rules...)
+	report, err := e2e.CheckLogs(bytes.NewReader(logs), rules)
+	require.NoError(t, err)
+	if s := report.String(); s != "" {
+		t.Logf("log report of node %v:\n%s", node.Name, s)
+	}
+	for _, m := range report.Forbidden {
+		t.Errorf("node %v logged a %v at line %d: %s", node.Name, m.Rule, m.Line, m.Text)
+	}
+	for _, name := range report.Missing {
+		t.Errorf("node %v never logged %v", node.Name, name)
+	}
+	return report
+}
+
 // fetchBlockChain fetches a complete, up-to-date block history from
 // the freshest testnet archive node.
 func fetchBlockChain(t *testing.T) []*types.Block {
@@ -0,0 +1,24 @@
+package e2e_test
+
+import (
+	"regexp"
+	"testing"
+
+	e2e "github.com/cometbft/cometbft/test/e2e/pkg"
+)
+
+// Tests that no node logged a panic, a consensus failure or a data race, and
+// that nodes logging at the default level committed blocks.
+func TestNode_Logs(t *testing.T) {
+	testNode(t, func(t *testing.T, node e2e.Node) {
+		var rules []e2e.LogRule
+		if node.Testnet.LogLevel == "" {
+			rules = append(rules, e2e.LogRule{
+				Name:     "committed state",
+				Pattern:  regexp.MustCompile(`committed state`),
+				Required: true,
+			})
+		}
+		checkNodeLogs(t, node, rules...)
+	})
+}
@@ -4,9 +4,16 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"iter"
+	"math"
+	"sort"
 	"strings"
+	"sync/atomic"
 	"time"
 
+	square "github.com/celestiaorg/go-square/v2"
+	"github.com/celestiaorg/go-square/v2/share"
+	"github.com/celestiaorg/nmt"
 	gogotypes "github.com/cosmos/gogoproto/types"
 
 	"github.com/cometbft/cometbft/crypto"
@@ -14,6 +21,7 @@ import (
 	"github.com/cometbft/cometbft/crypto/tmhash"
 	"github.com/cometbft/cometbft/libs/bits"
 	cmtbytes "github.com/cometbft/cometbft/libs/bytes"
+	"github.com/cometbft/cometbft/libs/consts"
 	cmtmath "github.com/cometbft/cometbft/libs/math"
 	cmtsync "github.com/cometbft/cometbft/libs/sync"
 	cmtproto "github.com/cometbft/cometbft/proto/tendermint/types"
@@ -51,6 +59,9 @@ type Block struct {
 	// cachedHashes is used purely for passing the hashes of the tx alongside
 	// the block. This are not included in any encoding of this struct.
 	cachedHashes [][]byte
+
+	// byteSize caches the size of the encoded block, see ByteSize.
+	byteSize int
 }
 
 // ValidateBasic performs basic validation that doesn't involve state data.
@@ -70,26 +81,22 @@ func (b *Block) ValidateBasic() error {
 
 	// Validate the last commit and its hash.
 	if b.LastCommit == nil {
-		return errors.New("nil LastCommit")
+		return ErrNilLastCommit
 	}
 	if err := b.LastCommit.ValidateBasic(); err != nil {
-		return fmt.Errorf("wrong LastCommit: %v", err)
+		return ErrInvalidField{Field: "LastCommit", Reason: err}
 	}
 
 	if !bytes.Equal(b.LastCommitHash, b.LastCommit.Hash()) {
-		return fmt.Errorf("wrong Header.LastCommitHash. Expected %v, got %v",
-			b.LastCommit.Hash(),
-			b.LastCommitHash,
-		)
+		return ErrWrongLastCommitHash{Expected: b.LastCommit.Hash(), Actual: b.LastCommitHash}
 	}
 
+	if err := b.Data.ValidateBasic(); err != nil {
+		return ErrInvalidField{Field: "Data", Reason: err}
+	}
 	// NOTE: b.Data.Txs may be nil, but b.Data.Hash() still works fine.
 	if !bytes.Equal(b.DataHash, b.Data.Hash()) {
-		return fmt.Errorf(
-			"wrong Header.DataHash. Expected %v, got %v",
-			b.Data.Hash(),
-			b.DataHash,
-		)
+		return ErrWrongDataHash{Expected: b.Data.Hash(), Actual: b.DataHash}
 	}
 
 	// NOTE: b.Evidence.Evidence may be nil, but we're just looping.
@@ -100,15 +107,39 @@ func (b *Block) ValidateBasic() error {
 	}
 
 	if !bytes.Equal(b.EvidenceHash, b.Evidence.Hash()) {
-		return fmt.Errorf("wrong Header.EvidenceHash. Expected %v, got %v",
-			b.EvidenceHash,
-			b.Evidence.Hash(),
-		)
+		return ErrWrongEvidenceHash{Expected: b.Evidence.Hash(), Actual: b.EvidenceHash}
 	}
 
 	return nil
 }
 
+// VerifyLastCommit checks that the block's LastCommit matches its
+// LastCommitHash and that it is a valid commit for the previous block, signed
+// by more than two thirds of the voting power of vals, the validator set at
+// the previous height. It must not be called on the initial block, which has
+// no last commit to verify.
+func (b *Block) VerifyLastCommit(vals *ValidatorSet) error {
+	if b == nil {
+		return errors.New("nil block")
+	}
+
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	if b.LastCommit == nil {
+		return errors.New("nil LastCommit")
+	}
+	if !bytes.Equal(b.LastCommitHash, b.LastCommit.Hash()) {
+		return fmt.Errorf("wrong Header.LastCommitHash. Expected %v, got %v",
+			b.LastCommit.Hash(),
+			b.LastCommitHash,
+		)
+	}
+
+	// LastCommit.Signatures length is checked in VerifyCommit.
+	return vals.VerifyCommit(b.ChainID, b.LastBlockID, b.Height-1, b.LastCommit)
+}
+
 // fillHeader fills in any remaining header fields that are a function of the block data
 func (b *Block) fillHeader() {
 	if b.LastCommitHash == nil {
@@ -167,6 +198,7 @@ func (b *Block) MakePartSet(partSize uint32) (*PartSet, error) {
 		return nil, err
 	}
 	ops.TxPos = pos
+	b.byteSize = len(bz)
 	return ops, nil
 }
 
@@ -192,6 +224,21 @@ func (b *Block) Size() int {
 	return pbb.Size()
 }
 
+// ByteSize returns the size of the block in bytes, like Size, but only
+// encodes the block the first time, unless MakePartSet already did. The block
+// must not be modified afterwards.
+func (b *Block) ByteSize() int {
+	if b == nil {
+		return 0
+	}
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	if b.byteSize == 0 {
+		b.byteSize = b.Size()
+	}
+	return b.byteSize
+}
+
 // String returns a string representation of the block
 //
 // See StringIndented.
@@ -266,6 +313,78 @@ func (b *Block) SetCachedHashes(hashes [][]byte) {
 	b.cachedHashes = hashes
 }
 
+// TxHashes returns the hashes of the txs of the block, as computed by
+// Tx.Hash. Cached hashes are reused when there is one per tx, otherwise the
+// hashes are computed and cached, so that they are computed once per block.
+func (b *Block) TxHashes() [][]byte {
+	if len(b.cachedHashes) == len(b.Txs) {
+		return b.cachedHashes
+	}
+	hashes := make([][]byte, len(b.Txs))
+	for i, tx := range b.Txs {
+		hashes[i] = tx.Hash()
+	}
+	b.cachedHashes = hashes
+	return hashes
+}
+
+// BlockIndexMeta is a compact summary of a block for indexing pipelines.
+type BlockIndexMeta struct {
+	Height       int64     `json:"height"`
+	Time         time.Time `json:"time"`
+	NumTxs       int       `json:"num_txs"`
+	TotalTxBytes int64     `json:"total_tx_bytes"`
+	NumEvidence  int       `json:"num_evidence"`
+	Proposer     Address   `json:"proposer"`
+	NumBlobs     int       `json:"num_blobs"`
+	// Namespaces of the blobs in the block (version followed by ID), without
+	// duplicates and sorted in ascending order.
+	Namespaces [][]byte `json:"namespaces"`
+}
+
+// IndexMetadata returns the BlockIndexMeta of the block. Blobs are both those
+// published in the block data and those found by unwrapping its BlobTxs.
+func (b *Block) IndexMetadata() BlockIndexMeta {
+	meta := BlockIndexMeta{
+		Height:      b.Height,
+		Time:        b.Time,
+		NumTxs:      len(b.Txs),
+		NumEvidence: len(b.Evidence.Evidence),
+		Proposer:    b.ProposerAddress,
+	}
+
+	namespaces := make(map[string]struct{})
+	for _, tx := range b.Txs {
+		meta.TotalTxBytes += int64(len(tx))
+
+		blobTx, isBlob := UnmarshalBlobTx(tx)
+		if !isBlob {
+			continue
+		}
+		meta.NumBlobs += len(blobTx.Blobs)
+		for _, blob := range blobTx.Blobs {
+			ns := append([]byte{byte(blob.NamespaceVersion)}, blob.NamespaceId...)
+			namespaces[string(ns)] = struct{}{}
+		}
+	}
+
+	meta.NumBlobs += len(b.Data.Blobs)
+	for _, blob := range b.Data.Blobs {
+		ns := append([]byte{blob.NamespaceVersion}, blob.NamespaceID...)
+		namespaces[string(ns)] = struct{}{}
+	}
+
+	meta.Namespaces = make([][]byte, 0, len(namespaces))
+	for ns := range namespaces {
+		meta.Namespaces = append(meta.Namespaces, []byte(ns))
+	}
+	sort.Slice(meta.Namespaces, func(i, j int) bool {
+		return bytes.Compare(meta.Namespaces[i], meta.Namespaces[j]) < 0
+	})
+
+	return meta
+}
+
 // FromProto sets a protobuf Block to the given pointer.
 // It returns an error if the block is invalid.
 func BlockFromProto(bp *cmtproto.Block) (*Block, error) {
@@ -399,66 +518,107 @@ func (h *Header) Populate(
 	h.ProposerAddress = proposerAddress
 }
 
+// AppHashValidator checks the AppHash of a header, whose format is otherwise
+// up to the application.
+type AppHashValidator func(appHash []byte) error
+
+var appHashValidator atomic.Pointer[AppHashValidator]
+
+// SetAppHashValidator sets the validator Header.ValidateBasic checks the
+// AppHash with, so that headers with an AppHash the application could never
+// have produced are rejected early. It is meant to be called once, before the
+// node starts. A nil validator accepts any AppHash, which is the default.
+func SetAppHashValidator(v AppHashValidator) {
+	if v == nil {
+		appHashValidator.Store(nil)
+		return
+	}
+	appHashValidator.Store(&v)
+}
+
+// AppHashLength returns an AppHashValidator that requires the AppHash to be
+// exactly n bytes long.
+func AppHashLength(n int) AppHashValidator {
+	return func(appHash []byte) error {
+		if len(appHash) != n {
+			return fmt.Errorf("expected size to be %d bytes, got %d bytes", n, len(appHash))
+		}
+		return nil
+	}
+}
+
 // ValidateBasic performs stateless validation on a Header returning an error
 // if any validation fails.
 //
 // NOTE: Timestamp validation is subtle and handled elsewhere.
 func (h Header) ValidateBasic() error {
 	if h.Version.Block != version.BlockProtocol {
-		return fmt.Errorf("block protocol is incorrect: got: %d, want: %d ", h.Version.Block, version.BlockProtocol)
+		return ErrWrongBlockProtocol{Expected: version.BlockProtocol, Actual: h.Version.Block}
 	}
 	if len(h.ChainID) > MaxChainIDLen {
-		return fmt.Errorf("chainID is too long; got: %d, max: %d", len(h.ChainID), MaxChainIDLen)
+		return ErrChainIDTooLong{Length: len(h.ChainID), Max: MaxChainIDLen}
 	}
 
-	if h.Height < 0 {
-		return errors.New("negative Height")
-	} else if h.Height == 0 {
-		return errors.New("zero Height")
+	if h.Height <= 0 {
+		return ErrInvalidHeight{Height: h.Height}
 	}
 
 	if err := h.LastBlockID.ValidateBasic(); err != nil {
-		return fmt.Errorf("wrong LastBlockID: %w", err)
+		return ErrInvalidField{Field: "LastBlockID", Reason: err}
 	}
 
 	if err := ValidateHash(h.LastCommitHash); err != nil {
-		return fmt.Errorf("wrong LastCommitHash: %v", err)
+		return ErrInvalidField{Field: "LastCommitHash", Reason: err}
 	}
 
 	if err := ValidateHash(h.DataHash); err != nil {
-		return fmt.Errorf("wrong DataHash: %v", err)
+		return ErrInvalidField{Field: "DataHash", Reason: err}
 	}
 
 	if err := ValidateHash(h.EvidenceHash); err != nil {
-		return fmt.Errorf("wrong EvidenceHash: %v", err)
+		return ErrInvalidField{Field: "EvidenceHash", Reason: err}
 	}
 
 	if len(h.ProposerAddress) != crypto.AddressSize {
-		return fmt.Errorf(
-			"invalid ProposerAddress length; got: %d, expected: %d",
-			len(h.ProposerAddress), crypto.AddressSize,
-		)
+		return ErrInvalidProposerAddress{Length: len(h.ProposerAddress)}
 	}
 
 	// Basic validation of hashes related to application data.
 	// Will validate fully against state in state#ValidateBlock.
 	if err := ValidateHash(h.ValidatorsHash); err != nil {
-		return fmt.Errorf("wrong ValidatorsHash: %v", err)
+		return ErrInvalidField{Field: "ValidatorsHash", Reason: err}
 	}
 	if err := ValidateHash(h.NextValidatorsHash); err != nil {
-		return fmt.Errorf("wrong NextValidatorsHash: %v", err)
+		return ErrInvalidField{Field: "NextValidatorsHash", Reason: err}
 	}
 	if err := ValidateHash(h.ConsensusHash); err != nil {
-		return fmt.Errorf("wrong ConsensusHash: %v", err)
+		return ErrInvalidField{Field: "ConsensusHash", Reason: err}
+	}
+	// NOTE: AppHash is arbitrary length, unless the application restricts it.
+	if v := appHashValidator.Load(); v != nil {
+		if err := (*v)(h.AppHash); err != nil {
+			return ErrInvalidField{Field: "AppHash", Reason: err}
+		}
 	}
-	// NOTE: AppHash is arbitrary length
 	if err := ValidateHash(h.LastResultsHash); err != nil {
-		return fmt.Errorf("wrong LastResultsHash: %v", err)
+		return ErrInvalidField{Field: "LastResultsHash", Reason: err}
 	}
 
 	return nil
 }
 
+// ValidateBasicWithChainID performs the same validation as ValidateBasic and
+// additionally checks that the header belongs to the expected chain.
+func (h Header) ValidateBasicWithChainID(expected string) error {
+	if err := h.ValidateBasic(); err != nil {
+		return err
+	}
+	if h.ChainID != expected {
+		return fmt.Errorf("wrong ChainID; got: %q, expected: %q", h.ChainID, expected)
+	}
+	return nil
+}
+
 // Hash returns the hash of the header.
 // It computes a Merkle tree from the header fields
 // ordered as they appear in the Header.
@@ -932,23 +1092,23 @@ func (commit *Commit) Size() int {
 // Does not actually check the cryptographic signatures.
 func (commit *Commit) ValidateBasic() error {
 	if commit.Height < 0 {
-		return errors.New("negative Height")
+		return ErrInvalidHeight{Height: commit.Height}
 	}
 	if commit.Round < 0 {
-		return errors.New("negative Round")
+		return ErrInvalidRound{Round: commit.Round}
 	}
 
 	if commit.Height >= 1 {
 		if commit.BlockID.IsZero() {
-			return errors.New("commit cannot be for nil block")
+			return ErrCommitForNilBlock
 		}
 
 		if len(commit.Signatures) == 0 {
-			return errors.New("no signatures in commit")
+			return ErrNoCommitSignatures
 		}
 		for i, commitSig := range commit.Signatures {
 			if err := commitSig.ValidateBasic(); err != nil {
-				return fmt.Errorf("wrong CommitSig #%d: %v", i, err)
+				return ErrInvalidCommitSig{Index: i, Reason: err}
 			}
 		}
 	}
@@ -1184,6 +1344,21 @@ func (ec *ExtendedCommit) GetExtendedVote(valIndex int32) *Vote {
 	}
 }
 
+// ExtensionsByValidator returns the vote extensions in the extended commit,
+// keyed by the hex-encoded address of the validator that signed them. Only
+// validators that voted for the block are included, since absent and nil
+// votes carry no extension.
+func (ec *ExtendedCommit) ExtensionsByValidator() map[string][]byte {
+	exts := make(map[string][]byte, len(ec.ExtendedSignatures))
+	for _, ecs := range ec.ExtendedSignatures {
+		if ecs.BlockIDFlag != BlockIDFlagCommit {
+			continue
+		}
+		exts[ecs.ValidatorAddress.String()] = ecs.Extension
+	}
+	return exts
+}
+
 // Type returns the vote type of the extended commit, which is always
 // VoteTypePrecommit
 // Implements VoteSetReader.
@@ -1324,6 +1499,9 @@ type Data struct {
 	// proofs that some element was included in the block
 	SquareSize uint64 `json:"square_size"`
 
+	// Blobs that are published in the block alongside the txs.
+	Blobs []Blob `json:"blobs"`
+
 	// Volatile
 	hash cmtbytes.HexBytes
 }
@@ -1336,17 +1514,193 @@ func NewData(txs Txs, squareSize uint64, hash cmtbytes.HexBytes) Data {
 	}
 }
 
-// Hash returns the hash of the data
+// DataHasher computes the hash of the data of a block, which the DataHash of
+// its header commits to. It returns nil if the data is invalid.
+type DataHasher func(data *Data) []byte
+
+var dataHasher atomic.Pointer[DataHasher]
+
+// SetDataHasher sets the function Data.Hash computes the hash of the data
+// with, e.g. so that the DataHash commits to the erasure-coded square of the
+// data. Since it changes the hash of every block, it must be called once,
+// before the node is created, e.g. with node.WithDataHasher. A nil hasher
+// restores DefaultDataHash.
+func SetDataHasher(h DataHasher) {
+	if h == nil {
+		dataHasher.Store(nil)
+		return
+	}
+	dataHasher.Store(&h)
+}
+
+// Hash returns the hash of the data, as computed by the DataHasher set with
+// SetDataHasher, DefaultDataHash by default. The hash is cached, unless it
+// can't be computed, in which case Hash returns nil and ValidateBasic returns
+// the error.
 func (data *Data) Hash() cmtbytes.HexBytes {
 	if data == nil {
 		return (Txs{}).Hash()
 	}
 	if data.hash == nil {
-		data.hash = data.Txs.Hash() // NOTE: leaves of merkle tree are TxIDs
+		hash, err := data.computeHash()
+		if err != nil {
+			return nil
+		}
+		data.hash = hash
 	}
 	return data.hash
 }
 
+// computeHash computes the hash of the data, ignoring the cached one.
+func (data *Data) computeHash() ([]byte, error) {
+	if h := dataHasher.Load(); h != nil {
+		hash := (*h)(data)
+		if hash == nil {
+			return nil, errors.New("data hasher rejected the data")
+		}
+		return hash, nil
+	}
+	return DefaultDataHash(data)
+}
+
+// DefaultDataHash returns the Merkle root of the txs of the data. If the data
+// contains blobs, the hash commits to both the txs and the shares the blobs
+// are split into, as the root of a Merkle tree with the txs hash and the
+// blobs root (see BlobsRoot) as leaves. It returns an error if the blobs
+// can't be split into shares or aren't ordered by namespace.
+func DefaultDataHash(data *Data) ([]byte, error) {
+	txsHash := data.Txs.Hash() // NOTE: leaves of merkle tree are TxIDs
+	if len(data.Blobs) == 0 {
+		return txsHash, nil
+	}
+	blobsHash, err := blobsHash(data.Blobs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit to the blobs: %w", err)
+	}
+	return merkle.HashFromByteSlices([][]byte{txsHash, blobsHash}), nil
+}
+
+// BlobsRoot returns the root of the namespaced Merkle tree over the shares of
+// the given blobs, as committed to by the hash of a Data that contains them.
+// The blobs are validated and sorted by namespace first, without modifying
+// the given slice. The shares must fit in a square of the given size, which
+// must be a power of two.
+func BlobsRoot(blobs []Blob, squareSize uint64) (cmtbytes.HexBytes, error) {
+	if squareSize == 0 || squareSize&(squareSize-1) != 0 {
+		return nil, fmt.Errorf("square size %d is not a power of two", squareSize)
+	}
+
+	type namespacedBlob struct {
+		ns   share.Namespace
+		blob Blob
+	}
+	nsBlobs := make([]namespacedBlob, len(blobs))
+	for i, b := range blobs {
+		ns, err := share.NewNamespace(b.NamespaceVersion, b.NamespaceID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid namespace of blob #%d: %w", i, err)
+		}
+		if err := ns.ValidateForBlob(); err != nil {
+			return nil, fmt.Errorf("invalid namespace of blob #%d: %w", i, err)
+		}
+		if len(b.Data) == 0 {
+			return nil, fmt.Errorf("blob #%d is empty", i)
+		}
+		nsBlobs[i] = namespacedBlob{ns: ns, blob: b}
+	}
+	sort.SliceStable(nsBlobs, func(i, j int) bool {
+		return nsBlobs[i].ns.IsLessThan(nsBlobs[j].ns)
+	})
+	sorted := make([]Blob, len(nsBlobs))
+	for i, nb := range nsBlobs {
+		sorted[i] = nb.blob
+	}
+
+	shares, err := blobShares(sorted)
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(shares)) > squareSize*squareSize {
+		return nil, fmt.Errorf("blobs take %d shares, which don't fit in a square of size %d",
+			len(shares), squareSize)
+	}
+	return nmtRoot(shares)
+}
+
+// blobsHash returns the root of the namespaced Merkle tree whose leaves are
+// the shares the given blobs are split into, in order. The blobs must be
+// ordered by namespace.
+func blobsHash(blobs []Blob) ([]byte, error) {
+	shares, err := blobShares(blobs)
+	if err != nil {
+		return nil, err
+	}
+	return nmtRoot(shares)
+}
+
+// blobShares splits the given blobs into sparse shares, in order.
+func blobShares(blobs []Blob) ([]share.Share, error) {
+	splitter := share.NewSparseShareSplitter()
+	for i, b := range blobs {
+		ns, err := share.NewNamespace(b.NamespaceVersion, b.NamespaceID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid namespace of blob #%d: %w", i, err)
+		}
+		blob, err := share.NewBlob(ns, b.Data, b.ShareVersion, nil)
+		if err != nil {
+			return nil, fmt.Errorf("invalid blob #%d: %w", i, err)
+		}
+		if err := splitter.Write(blob); err != nil {
+			return nil, fmt.Errorf("failed to split blob #%d into shares: %w", i, err)
+		}
+	}
+	return splitter.Export(), nil
+}
+
+// nmtRoot returns the root of the namespaced Merkle tree whose leaves are the
+// given shares, prefixed with their namespace.
+func nmtRoot(shares []share.Share) ([]byte, error) {
+	tree := nmt.New(consts.NewBaseHashFunc(), nmt.NamespaceIDSize(share.NamespaceSize), nmt.IgnoreMaxNamespace(true))
+	for i, sh := range shares {
+		ns := sh.Namespace()
+		if err := tree.Push(append(ns.Bytes(), sh.ToBytes()...)); err != nil {
+			return nil, fmt.Errorf("failed to add share #%d to the tree: %w", i, err)
+		}
+	}
+	return tree.Root()
+}
+
+// ValidateBasic checks that the declared square size can hold the data, that
+// each blob is valid, that the blobs are ordered by namespace, and that the
+// hash of the data can be computed, unless it is already known.
+func (data *Data) ValidateBasic() error {
+	if err := data.ValidateSquareSize(false); err != nil {
+		return err
+	}
+	var prev share.Namespace
+	for i, b := range data.Blobs {
+		if err := b.ValidateBasic(); err != nil {
+			return fmt.Errorf("invalid blob #%d: %w", i, err)
+		}
+		ns, err := share.NewNamespace(b.NamespaceVersion, b.NamespaceID)
+		if err != nil {
+			return fmt.Errorf("invalid namespace of blob #%d: %w", i, err)
+		}
+		if i > 0 && ns.IsLessThan(prev) {
+			return fmt.Errorf("blob #%d is not ordered by namespace", i)
+		}
+		prev = ns
+	}
+	if data.hash == nil {
+		hash, err := data.computeHash()
+		if err != nil {
+			return err
+		}
+		data.hash = hash
+	}
+	return nil
+}
+
 // StringIndented returns an indented string representation of the transactions.
 func (data *Data) StringIndented(indent string) string {
 	if data == nil {
@@ -1379,6 +1733,14 @@ func (data *Data) ToProto() cmtproto.Data {
 		tp.Txs = txBzs
 	}
 
+	if len(data.Blobs) > 0 {
+		blobs := make([]*cmtproto.Blob, len(data.Blobs))
+		for i, b := range data.Blobs {
+			blobs[i] = b.ToProto()
+		}
+		tp.Blobs = blobs
+	}
+
 	tp.SquareSize = data.SquareSize
 	tp.Hash = data.hash
 
@@ -1403,12 +1765,170 @@ func DataFromProto(dp *cmtproto.Data) (Data, error) {
 		data.Txs = Txs{}
 	}
 
+	if len(dp.Blobs) > 0 {
+		blobs := make([]Blob, len(dp.Blobs))
+		for i, b := range dp.Blobs {
+			blob, err := BlobFromProto(b)
+			if err != nil {
+				return Data{}, fmt.Errorf("blob #%d: %w", i, err)
+			}
+			blobs[i] = blob
+		}
+		data.Blobs = blobs
+	}
+
 	data.hash = dp.Hash
 	data.SquareSize = dp.SquareSize
 
 	return *data, nil
 }
 
+// MinSquareSize returns the size of the smallest square that can hold the
+// shares the txs and blobs of the data are split into. It is a lower bound:
+// the padding between blobs and the share indexes added to the PFB txs are not
+// accounted for.
+func (data *Data) MinSquareSize() uint64 {
+	var (
+		txCounter   = share.NewCompactShareCounter()
+		pfbCounter  = share.NewCompactShareCounter()
+		blobsShares int
+	)
+	for _, tx := range data.Txs {
+		blobTx, isBlob := UnmarshalBlobTx(tx)
+		if !isBlob {
+			txCounter.Add(len(tx))
+			continue
+		}
+		pfbCounter.Add(len(blobTx.Tx))
+		for _, b := range blobTx.Blobs {
+			blobsShares += share.SparseSharesNeeded(uint32(len(b.Data)))
+		}
+	}
+	for _, b := range data.Blobs {
+		blobsShares += share.SparseSharesNeeded(uint32(len(b.Data)))
+	}
+	shares := txCounter.Size() + pfbCounter.Size() + blobsShares
+	return uint64(square.Size(shares))
+}
+
+// ValidateSquareSize returns an error if the declared SquareSize can't hold
+// the txs and blobs of the data. A zero SquareSize is treated as unknown and
+// accepted, unless strict is set, in which case the size must also be a
+// non-zero power of two.
+func (data *Data) ValidateSquareSize(strict bool) error {
+	if data.SquareSize == 0 {
+		if strict {
+			return errors.New("square size is not set")
+		}
+		return nil
+	}
+	if strict && data.SquareSize&(data.SquareSize-1) != 0 {
+		return fmt.Errorf("square size %d is not a power of two", data.SquareSize)
+	}
+	if minSize := data.MinSquareSize(); data.SquareSize < minSize {
+		return fmt.Errorf("square size %d is too small to hold the block data, which needs at least %d",
+			data.SquareSize, minSize)
+	}
+	return nil
+}
+
+// PositionedTx is a tx of a block along with its index in the block and its
+// position in the encoded block.
+type PositionedTx struct {
+	Index    int
+	Tx       Tx
+	Position TxPosition
+}
+
+// Iter yields the txs of the data in order, along with their positions in the
+// encoded block, as returned by MarshalBlockWithTxPositions and kept in the
+// TxPos of the block's part set. Txs without a position, e.g. because
+// positions is nil, are yielded with a zero position.
+func (data *Data) Iter(positions []TxPosition) iter.Seq[PositionedTx] {
+	return func(yield func(PositionedTx) bool) {
+		for i, tx := range data.Txs {
+			ptx := PositionedTx{Index: i, Tx: tx}
+			if i < len(positions) {
+				ptx.Position = positions[i]
+			}
+			if !yield(ptx) {
+				return
+			}
+		}
+	}
+}
+
+// NamespaceIndex returns the indexes of the txs carrying blobs of each
+// namespace, keyed by the namespace bytes (version followed by ID), in
+// increasing order. Txs that aren't blob txs are left out. The index is
+// derived from the txs, so it isn't part of the data hash, but the txs it
+// points to can be proven with TxInclusionProof.
+func (data *Data) NamespaceIndex() map[string][]int {
+	index := make(map[string][]int)
+	for i, tx := range data.Txs {
+		blobTx, isBlob := UnmarshalBlobTx(tx)
+		if !isBlob {
+			continue
+		}
+		seen := make(map[string]bool, len(blobTx.Blobs))
+		for _, b := range blobTx.Blobs {
+			ns := string(append([]byte{uint8(b.NamespaceVersion)}, b.NamespaceId...))
+			if seen[ns] {
+				continue
+			}
+			seen[ns] = true
+			index[ns] = append(index[ns], i)
+		}
+	}
+	return index
+}
+
+// TxInclusionProof returns a Merkle proof that the i-th tx is part of the
+// data, which can be validated against its hash. It returns an error if the
+// index is out of range, or if the hash of the data wasn't computed by
+// DefaultDataHash, e.g. because it was set by the application.
+func (data *Data) TxInclusionProof(i int) (DataTxProof, error) {
+	if i < 0 || i >= len(data.Txs) {
+		return DataTxProof{}, fmt.Errorf("tx index %d out of range [0, %d)", i, len(data.Txs))
+	}
+	proof := DataTxProof{TxProof: data.Txs.Proof(i)}
+	if len(data.Blobs) > 0 {
+		blobsHash, err := blobsHash(data.Blobs)
+		if err != nil {
+			return DataTxProof{}, err
+		}
+		proof.BlobsHash = blobsHash
+	}
+	if !bytes.Equal(proof.root(), data.Hash()) {
+		return DataTxProof{}, fmt.Errorf("data hash %X was not computed from the txs and blobs", data.Hash())
+	}
+	return proof, nil
+}
+
+// DataTxProof proves that a tx is part of the data of a block. When the data
+// has blobs, the txs hash the TxProof is rooted at is one of the two leaves
+// of the data hash, the other being BlobsHash.
+type DataTxProof struct {
+	TxProof
+	BlobsHash cmtbytes.HexBytes `json:"blobs_hash,omitempty"`
+}
+
+// Validate verifies the proof against the hash of the data. It returns nil if
+// the proof leads to dataHash and is internally consistent.
+func (p DataTxProof) Validate(dataHash []byte) error {
+	if !bytes.Equal(p.root(), dataHash) {
+		return errors.New("proof matches different data hash")
+	}
+	return p.TxProof.Validate(p.RootHash)
+}
+
+func (p DataTxProof) root() []byte {
+	if p.BlobsHash == nil {
+		return p.RootHash
+	}
+	return merkle.HashFromByteSlices([][]byte{p.RootHash, p.BlobsHash})
+}
+
 //-----------------------------------------------------------------------------
 
 type Blob struct {
@@ -1434,6 +1954,93 @@ func (b Blob) Namespace() []byte {
 	return append([]byte{b.NamespaceVersion}, b.NamespaceID...)
 }
 
+// ValidateBasic performs stateless validation of the blob: its namespace must
+// be a valid blob namespace, its share version supported and its data neither
+// empty nor larger than MaxBlobSizeBytes.
+func (b Blob) ValidateBasic() error {
+	ns, err := share.NewNamespace(b.NamespaceVersion, b.NamespaceID)
+	if err != nil {
+		return fmt.Errorf("invalid namespace: %w", err)
+	}
+	if err := ns.ValidateForBlob(); err != nil {
+		return fmt.Errorf("invalid namespace: %w", err)
+	}
+	// Blobs don't carry a signer, which later share versions require.
+	if b.ShareVersion != share.ShareVersionZero {
+		return fmt.Errorf("unsupported share version %d", b.ShareVersion)
+	}
+	if len(b.Data) == 0 {
+		return errors.New("empty data")
+	}
+	if len(b.Data) > MaxBlobSizeBytes {
+		return fmt.Errorf("data is too big: %d bytes, max %d", len(b.Data), MaxBlobSizeBytes)
+	}
+	return nil
+}
+
+// ToProto converts the blob to protobuf.
+func (b Blob) ToProto() *cmtproto.Blob {
+	return &cmtproto.Blob{
+		NamespaceId:      b.NamespaceID,
+		Data:             b.Data,
+		ShareVersion:     uint32(b.ShareVersion),
+		NamespaceVersion: uint32(b.NamespaceVersion),
+	}
+}
+
+// BlobFromProto converts a protobuf blob to the native type. It only checks
+// that the versions fit, see ValidateBasic for the full validation.
+func BlobFromProto(pb *cmtproto.Blob) (Blob, error) {
+	if pb == nil {
+		return Blob{}, errors.New("nil blob")
+	}
+	if pb.ShareVersion > math.MaxUint8 || pb.NamespaceVersion > math.MaxUint8 {
+		return Blob{}, fmt.Errorf("invalid version: share version %d, namespace version %d",
+			pb.ShareVersion, pb.NamespaceVersion)
+	}
+	return Blob{
+		NamespaceVersion: uint8(pb.NamespaceVersion),
+		NamespaceID:      pb.NamespaceId,
+		Data:             pb.Data,
+		ShareVersion:     uint8(pb.ShareVersion),
+	}, nil
+}
+
+// MarshalProto returns the canonical protobuf encoding of the blob. The blob
+// must be valid.
+func (b Blob) MarshalProto() ([]byte, error) {
+	if err := b.ValidateBasic(); err != nil {
+		return nil, err
+	}
+	return b.ToProto().Marshal()
+}
+
+// UnmarshalProto decodes a blob encoded with MarshalProto. It rejects invalid
+// blobs, as well as encodings that aren't canonical, so that a blob has a
+// single encoding.
+func (b *Blob) UnmarshalProto(bz []byte) error {
+	pb := new(cmtproto.Blob)
+	if err := pb.Unmarshal(bz); err != nil {
+		return err
+	}
+	blob, err := BlobFromProto(pb)
+	if err != nil {
+		return err
+	}
+	if err := blob.ValidateBasic(); err != nil {
+		return err
+	}
+	canonical, err := pb.Marshal()
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(canonical, bz) {
+		return errors.New("non-canonical blob encoding")
+	}
+	*b = blob
+	return nil
+}
+
 // -----------------------------------------------------------------------------
 
 // EvidenceData contains any evidence of malicious wrong-doing by validators
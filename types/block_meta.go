@@ -14,15 +14,19 @@ type BlockMeta struct {
 	BlockSize int     `json:"block_size"`
 	Header    Header  `json:"header"`
 	NumTxs    int     `json:"num_txs"`
+	// SquareSize is the size of the data square of the block, so that it can
+	// be learned without loading the block.
+	SquareSize uint64 `json:"square_size"`
 }
 
 // NewBlockMeta returns a new BlockMeta.
 func NewBlockMeta(block *Block, blockParts *PartSet) *BlockMeta {
 	return &BlockMeta{
-		BlockID:   BlockID{block.Hash(), blockParts.Header()},
-		BlockSize: block.Size(),
-		Header:    block.Header,
-		NumTxs:    len(block.Data.Txs), //nolint:staticcheck
+		BlockID:    BlockID{block.Hash(), blockParts.Header()},
+		BlockSize:  block.ByteSize(),
+		Header:     block.Header,
+		NumTxs:     len(block.Data.Txs), //nolint:staticcheck
+		SquareSize: block.Data.SquareSize,
 	}
 }
 
@@ -32,10 +36,11 @@ func (bm *BlockMeta) ToProto() *cmtproto.BlockMeta {
 	}
 
 	pb := &cmtproto.BlockMeta{
-		BlockID:   bm.BlockID.ToProto(),
-		BlockSize: int64(bm.BlockSize),
-		Header:    *bm.Header.ToProto(),
-		NumTxs:    int64(bm.NumTxs),
+		BlockID:    bm.BlockID.ToProto(),
+		BlockSize:  int64(bm.BlockSize),
+		Header:     *bm.Header.ToProto(),
+		NumTxs:     int64(bm.NumTxs),
+		SquareSize: bm.SquareSize,
 	}
 	return pb
 }
@@ -69,6 +74,7 @@ func BlockMetaFromTrustedProto(pb *cmtproto.BlockMeta) (*BlockMeta, error) {
 	bm.BlockSize = int(pb.BlockSize)
 	bm.Header = h
 	bm.NumTxs = int(pb.NumTxs)
+	bm.SquareSize = pb.SquareSize
 
 	return bm, nil
 }
@@ -14,10 +14,11 @@ func TestBlockMeta_ToProto(t *testing.T) {
 	bi := BlockID{Hash: h.Hash(), PartSetHeader: PartSetHeader{Total: 123, Hash: cmtrand.Bytes(tmhash.Size)}}
 
 	bm := &BlockMeta{
-		BlockID:   bi,
-		BlockSize: 200,
-		Header:    h,
-		NumTxs:    0,
+		BlockID:    bi,
+		BlockSize:  200,
+		Header:     h,
+		NumTxs:     0,
+		SquareSize: 4,
 	}
 
 	tests := []struct {
@@ -93,3 +94,10 @@ func TestBlockMeta_ValidateBasic(t *testing.T) {
 		})
 	}
 }
+
+func TestNewBlockMetaSquareSize(t *testing.T) {
+	block := MakeBlock(1, Data{Txs: Txs{Tx("tx")}, SquareSize: 1}, new(Commit), nil)
+	parts, err := block.MakePartSet(BlockPartSizeBytes)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, NewBlockMeta(block, parts).SquareSize)
+}
@@ -7,12 +7,14 @@ import (
 	stdbytes "bytes"
 	"crypto/rand"
 	"encoding/hex"
+	"io"
 	"math"
 	"os"
 	"reflect"
 	"testing"
 	"time"
 
+	"github.com/celestiaorg/go-square/v2/share"
 	gogotypes "github.com/cosmos/gogoproto/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -53,6 +55,68 @@ func TestBlockAddEvidence(t *testing.T) {
 	require.NotNil(t, block.EvidenceHash)
 }
 
+func TestBlockIndexMetadata(t *testing.T) {
+	lastID := makeBlockIDRandom()
+	h := int64(3)
+
+	voteSet, _, vals := randVoteSet(h-1, 1, cmtproto.PrecommitType, 10, 1, false)
+	extCommit, err := MakeExtCommit(lastID, h-1, 1, voteSet, vals, time.Now(), false)
+	require.NoError(t, err)
+
+	ev, err := NewMockDuplicateVoteEvidenceWithValidator(h, time.Now(), vals[0], "block-test-chain")
+	require.NoError(t, err)
+
+	nsOne := stdbytes.Repeat([]byte{1}, share.NamespaceIDSize)
+	nsTwo := stdbytes.Repeat([]byte{2}, share.NamespaceIDSize)
+	blobTx1, err := MarshalBlobTx([]byte("pfb1"),
+		&cmtproto.Blob{NamespaceId: nsTwo, Data: []byte("blob1")},
+		&cmtproto.Blob{NamespaceId: nsOne, Data: []byte("blob2")},
+	)
+	require.NoError(t, err)
+	blobTx2, err := MarshalBlobTx([]byte("pfb2"),
+		&cmtproto.Blob{NamespaceId: nsOne, Data: []byte("blob3")},
+	)
+	require.NoError(t, err)
+
+	txs := []Tx{Tx("foo"), blobTx1, Tx("bar"), blobTx2}
+	block := MakeBlock(h, Data{Txs: txs}, extCommit.ToCommit(), []Evidence{ev})
+	pubKey, err := vals[0].GetPubKey()
+	require.NoError(t, err)
+	block.ProposerAddress = pubKey.Address()
+
+	meta := block.IndexMetadata()
+	assert.Equal(t, h, meta.Height)
+	assert.Equal(t, block.Time, meta.Time)
+	assert.Equal(t, 4, meta.NumTxs)
+	assert.Equal(t, int64(len("foo")+len(blobTx1)+len("bar")+len(blobTx2)), meta.TotalTxBytes)
+	assert.Equal(t, 1, meta.NumEvidence)
+	assert.Equal(t, block.ProposerAddress, meta.Proposer)
+	assert.Equal(t, 3, meta.NumBlobs)
+	assert.Equal(t, [][]byte{
+		append([]byte{0}, nsOne...),
+		append([]byte{0}, nsTwo...),
+	}, meta.Namespaces)
+
+	// A block without blobs has no namespaces.
+	meta = MakeBlock(h, Data{Txs: []Tx{Tx("foo")}}, extCommit.ToCommit(), nil).IndexMetadata()
+	assert.Zero(t, meta.NumBlobs)
+	assert.Empty(t, meta.Namespaces)
+	assert.Zero(t, meta.NumEvidence)
+
+	// Blobs published in the block data are indexed too.
+	nsThree := stdbytes.Repeat([]byte{3}, share.NamespaceIDSize)
+	data := Data{
+		Txs:   []Tx{blobTx2},
+		Blobs: []Blob{{NamespaceID: nsOne, Data: []byte("blob4")}, {NamespaceID: nsThree, Data: []byte("blob5")}},
+	}
+	meta = MakeBlock(h, data, extCommit.ToCommit(), nil).IndexMetadata()
+	assert.Equal(t, 3, meta.NumBlobs)
+	assert.Equal(t, [][]byte{
+		append([]byte{0}, nsOne...),
+		append([]byte{0}, nsThree...),
+	}, meta.Namespaces)
+}
+
 func TestBlockValidateBasic(t *testing.T) {
 	require.Error(t, (*Block)(nil).ValidateBasic())
 
@@ -88,6 +152,23 @@ func TestBlockValidateBasic(t *testing.T) {
 		{"Incorrect block protocol version", func(blk *Block) {
 			blk.Version.Block = 1
 		}, true},
+		{"Blobs", func(blk *Block) {
+			blk.Data.Blobs = testBlobs(t)
+			blk.Data.hash = nil
+			blk.DataHash = blk.Data.Hash()
+		}, false},
+		{"Blobs not ordered by namespace with empty DataHash", func(blk *Block) {
+			blobs := testBlobs(t)
+			blk.Data.Blobs = []Blob{blobs[1], blobs[0]}
+			blk.Data.hash = nil
+			blk.DataHash = nil
+		}, true},
+		{"Blob with reserved namespace", func(blk *Block) {
+			blk.Data.Blobs = testBlobs(t)
+			blk.Data.Blobs[0].NamespaceID = share.TxNamespace.ID()
+			blk.Data.hash = nil
+			blk.DataHash = blk.Data.Hash()
+		}, true},
 	}
 	for i, tc := range testCases {
 		tc := tc
@@ -102,6 +183,89 @@ func TestBlockValidateBasic(t *testing.T) {
 	}
 }
 
+func TestBlockValidateBasicErrors(t *testing.T) {
+	lastID := makeBlockIDRandom()
+	h := int64(3)
+
+	voteSet, valSet, vals := randVoteSet(h-1, 1, cmtproto.PrecommitType, 10, 1, false)
+	extCommit, err := MakeExtCommit(lastID, h-1, 1, voteSet, vals, time.Now(), false)
+	require.NoError(t, err)
+	commit := extCommit.ToCommit()
+
+	testCases := []struct {
+		testName      string
+		malleateBlock func(*Block)
+		target        any
+	}{
+		{"Incorrect block protocol version", func(blk *Block) { blk.Version.Block = 1 }, &ErrWrongBlockProtocol{}},
+		{"Negative Height", func(blk *Block) { blk.Height = -1 }, &ErrInvalidHeight{}},
+		{"Short DataHash", func(blk *Block) { blk.DataHash = []byte{1} }, &ErrInvalidField{}},
+		{"Short ProposerAddress", func(blk *Block) { blk.ProposerAddress = []byte{1} }, &ErrInvalidProposerAddress{}},
+		{"Tampered LastCommitHash", func(blk *Block) { blk.LastCommitHash = tmhash.Sum([]byte("x")) }, &ErrWrongLastCommitHash{}},
+		{"Tampered DataHash", func(blk *Block) { blk.DataHash = tmhash.Sum([]byte("x")) }, &ErrWrongDataHash{}},
+		{"Tampered EvidenceHash", func(blk *Block) { blk.EvidenceHash = tmhash.Sum([]byte("x")) }, &ErrWrongEvidenceHash{}},
+		{"Invalid CommitSig", func(blk *Block) {
+			blk.LastCommit.Signatures[0].Signature = nil
+		}, &ErrInvalidCommitSig{}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.testName, func(t *testing.T) {
+			lastCommit := *commit
+			lastCommit.Signatures = append([]CommitSig(nil), commit.Signatures...)
+			block := MakeBlock(h, Data{Txs: []Tx{Tx("foo")}}, &lastCommit, nil)
+			block.ProposerAddress = valSet.GetProposer().Address
+			tc.malleateBlock(block)
+			err := block.ValidateBasic()
+			require.Error(t, err)
+			require.ErrorAs(t, err, tc.target)
+		})
+	}
+
+	block := MakeBlock(h, Data{}, nil, nil)
+	block.ProposerAddress = valSet.GetProposer().Address
+	require.ErrorIs(t, block.ValidateBasic(), ErrNilLastCommit)
+}
+
+func TestBlockVerifyLastCommit(t *testing.T) {
+	require.Error(t, (*Block)(nil).VerifyLastCommit(nil))
+
+	lastID := makeBlockIDRandom()
+	h := int64(3)
+
+	voteSet, valSet, vals := randVoteSet(h-1, 1, cmtproto.PrecommitType, 10, 1, false)
+	extCommit, err := MakeExtCommit(lastID, h-1, 1, voteSet, vals, time.Now(), false)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		testName      string
+		malleateBlock func(*Block)
+		expErr        bool
+	}{
+		{"Valid commit", func(blk *Block) {}, false},
+		{"Nil LastCommit", func(blk *Block) { blk.LastCommit = nil }, true},
+		{"Wrong LastCommitHash", func(blk *Block) { blk.LastCommitHash = []byte("something else") }, true},
+		{"Wrong LastBlockID", func(blk *Block) { blk.LastBlockID = makeBlockIDRandom() }, true},
+		{"Below quorum", func(blk *Block) {
+			for i := 0; i < 4; i++ {
+				blk.LastCommit.Signatures[i] = NewCommitSigAbsent()
+			}
+			blk.LastCommit.hash = nil
+			blk.LastCommitHash = blk.LastCommit.Hash()
+		}, true},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.testName, func(t *testing.T) {
+			block := MakeBlock(h, Data{}, extCommit.ToCommit(), nil)
+			block.ChainID = voteSet.ChainID()
+			block.LastBlockID = lastID
+			tc.malleateBlock(block)
+			err := block.VerifyLastCommit(valSet)
+			assert.Equal(t, tc.expErr, err != nil, err)
+		})
+	}
+}
+
 func TestBlockHash(t *testing.T) {
 	assert.Nil(t, (*Block)(nil).Hash())
 	assert.Nil(t, MakeBlock(int64(3), Data{Txs: []Tx{Tx("Hello World")}}, nil, nil).Hash())
@@ -118,6 +282,67 @@ func TestBlockMakePartSet(t *testing.T) {
 	assert.EqualValues(t, 1, partSet.Total())
 }
 
+func TestBlockByteSize(t *testing.T) {
+	require.Zero(t, (*Block)(nil).ByteSize())
+
+	block := MakeBlock(int64(3), Data{Txs: makeTxs(5, 100)}, nil, nil)
+	require.Equal(t, block.Size(), block.ByteSize())
+
+	// The size of the encoding made for the part set is reused.
+	block = MakeBlock(int64(3), Data{Txs: makeTxs(5, 100)}, nil, nil)
+	partSet, err := block.MakePartSet(BlockPartSizeBytes)
+	require.NoError(t, err)
+	require.EqualValues(t, partSet.ByteSize(), block.ByteSize())
+	require.Equal(t, block.Size(), block.ByteSize())
+}
+
+func TestDataIter(t *testing.T) {
+	block := MakeBlock(int64(3), Data{Txs: makeTxs(5, 100)}, nil, nil)
+	partSet, err := block.MakePartSet(BlockPartSizeBytes)
+	require.NoError(t, err)
+	bz, err := io.ReadAll(partSet.GetReader())
+	require.NoError(t, err)
+
+	var i int
+	for ptx := range block.Data.Iter(partSet.TxPos) {
+		require.Equal(t, i, ptx.Index)
+		require.Equal(t, block.Txs[i], ptx.Tx)
+		// Positions span the whole field, including its tag and length.
+		require.True(t, stdbytes.HasSuffix(bz[ptx.Position.Start:ptx.Position.End], ptx.Tx))
+		i++
+	}
+	require.Equal(t, len(block.Txs), i)
+
+	// Without positions, the txs are still yielded.
+	i = 0
+	for ptx := range block.Data.Iter(nil) {
+		require.Equal(t, block.Txs[i], ptx.Tx)
+		require.Zero(t, ptx.Position)
+		if i++; i == 2 {
+			break
+		}
+	}
+	require.Equal(t, 2, i)
+}
+
+func TestBlockTxHashes(t *testing.T) {
+	txs := makeTxs(3, 10)
+	block := MakeBlock(1, Data{Txs: txs}, nil, nil)
+	hashes := block.TxHashes()
+	require.Len(t, hashes, len(txs))
+	for i, tx := range txs {
+		require.Equal(t, tx.Hash(), hashes[i])
+	}
+	require.Equal(t, hashes, block.CachedHashes())
+
+	// Cached hashes are reused, unless they don't match the txs.
+	cached := [][]byte{{1}, {2}, {3}}
+	block.SetCachedHashes(cached)
+	require.Equal(t, cached, block.TxHashes())
+	block.SetCachedHashes(cached[:1])
+	require.Equal(t, hashes, block.TxHashes())
+}
+
 func TestBlockMakePartSetWithEvidence(t *testing.T) {
 	ops, err := (*Block)(nil).MakePartSet(2)
 	assert.Error(t, err)
@@ -590,6 +815,35 @@ func toVoteSet(ec *ExtendedCommit, chainID string, vals *ValidatorSet) *VoteSet
 	return voteSet
 }
 
+func TestExtendedCommitExtensionsByValidator(t *testing.T) {
+	addr1 := crypto.AddressHash([]byte("validator1"))
+	addr2 := crypto.AddressHash([]byte("validator2"))
+	addr3 := crypto.AddressHash([]byte("validator3"))
+	ec := &ExtendedCommit{
+		Height: 1,
+		ExtendedSignatures: []ExtendedCommitSig{
+			{
+				CommitSig: CommitSig{BlockIDFlag: BlockIDFlagCommit, ValidatorAddress: addr1},
+				Extension: []byte("ext1"),
+			},
+			NewExtendedCommitSigAbsent(),
+			{
+				CommitSig: CommitSig{BlockIDFlag: BlockIDFlagNil, ValidatorAddress: addr2},
+			},
+			{
+				CommitSig: CommitSig{BlockIDFlag: BlockIDFlagCommit, ValidatorAddress: addr3},
+				Extension: []byte("ext3"),
+			},
+		},
+	}
+
+	exts := ec.ExtensionsByValidator()
+	require.Len(t, exts, 2)
+	assert.Equal(t, []byte("ext1"), exts[addr1.String()])
+	assert.Equal(t, []byte("ext3"), exts[addr3.String()])
+	assert.NotContains(t, exts, addr2.String())
+}
+
 // TestExtendedCommitToVoteSet tests that the vote set produced from an extended commit
 // contains the same vote information as the extended commit. The test ensures
 // that the ToVoteSet method behaves as expected, whether vote extensions
@@ -800,6 +1054,7 @@ func TestBlockProtoBuf(t *testing.T) {
 func TestDataProtoBuf(t *testing.T) {
 	data := &Data{Txs: Txs{Tx([]byte{1}), Tx([]byte{2}), Tx([]byte{3})}}
 	data2 := &Data{Txs: Txs{}}
+	data3 := &Data{Txs: Txs{Tx([]byte{1})}, Blobs: testBlobs(t)}
 	testCases := []struct {
 		msg     string
 		data1   *Data
@@ -807,6 +1062,7 @@ func TestDataProtoBuf(t *testing.T) {
 	}{
 		{"success", data, true},
 		{"success data2", data2, true},
+		{"success data with blobs", data3, true},
 	}
 	for _, tc := range testCases {
 		protoData := tc.data1.ToProto()
@@ -820,6 +1076,312 @@ func TestDataProtoBuf(t *testing.T) {
 	}
 }
 
+func TestDataFromProtoInvalidBlob(t *testing.T) {
+	data := &Data{Txs: Txs{Tx([]byte{1})}, Blobs: testBlobs(t)}
+	protoData := data.ToProto()
+	protoData.Blobs[0].ShareVersion = math.MaxUint8 + 1
+	_, err := DataFromProto(&protoData)
+	require.Error(t, err)
+}
+
+func TestDataHashWithBlobs(t *testing.T) {
+	txs := Txs{Tx([]byte{1}), Tx([]byte{2})}
+	withoutBlobs := &Data{Txs: txs}
+	withBlobs := &Data{Txs: txs, Blobs: testBlobs(t)}
+
+	// Data without blobs keeps hashing to the hash of its txs.
+	require.EqualValues(t, txs.Hash(), withoutBlobs.Hash())
+	require.NotNil(t, withBlobs.Hash())
+	require.NotEqual(t, withoutBlobs.Hash(), withBlobs.Hash())
+
+	// The hash survives a proto round trip.
+	protoData := (&Data{Txs: txs, Blobs: testBlobs(t)}).ToProto()
+	d, err := DataFromProto(&protoData)
+	require.NoError(t, err)
+	require.Equal(t, withBlobs.Hash(), d.Hash())
+
+	// Changing the content of a blob changes the hash.
+	changed := &Data{Txs: txs, Blobs: testBlobs(t)}
+	changed.Blobs[1].Data = []byte("other blob")
+	require.NotEqual(t, withBlobs.Hash(), changed.Hash())
+
+	// Blobs that aren't ordered by namespace are rejected.
+	blobs := testBlobs(t)
+	reordered := &Data{Txs: txs, Blobs: []Blob{blobs[1], blobs[0]}}
+	require.Error(t, reordered.ValidateBasic())
+
+	// So are blobs that can't be split into shares.
+	invalid := &Data{Txs: txs, Blobs: testBlobs(t)}
+	invalid.Blobs[0].NamespaceID = []byte{1}
+	require.Error(t, invalid.ValidateBasic())
+
+	require.NoError(t, withBlobs.ValidateBasic())
+}
+
+func TestDefaultDataHashInvalidBlobs(t *testing.T) {
+	blobs := testBlobs(t)
+	_, err := DefaultDataHash(&Data{Blobs: []Blob{blobs[1], blobs[0]}})
+	require.Error(t, err)
+
+	invalid := testBlobs(t)
+	invalid[0].NamespaceID = []byte{1}
+	_, err = DefaultDataHash(&Data{Blobs: invalid})
+	require.Error(t, err)
+
+	hash, err := DefaultDataHash(&Data{Blobs: testBlobs(t)})
+	require.NoError(t, err)
+	require.NotEmpty(t, hash)
+}
+
+func TestSetDataHasher(t *testing.T) {
+	txs := Txs{Tx("foo"), Tx("bar")}
+	root := []byte("square root")
+
+	SetDataHasher(func(data *Data) []byte {
+		require.Equal(t, txs, data.Txs)
+		return root
+	})
+	defer SetDataHasher(nil)
+	require.EqualValues(t, root, (&Data{Txs: txs}).Hash())
+	block := MakeBlock(1, Data{Txs: txs}, new(Commit), nil)
+	require.EqualValues(t, root, block.DataHash)
+
+	// Proofs against the default hash can't be built anymore.
+	_, err := (&Data{Txs: txs}).TxInclusionProof(0)
+	require.Error(t, err)
+
+	// Data the hasher rejects is invalid.
+	SetDataHasher(func(*Data) []byte { return nil })
+	require.Error(t, (&Data{Txs: txs}).ValidateBasic())
+
+	SetDataHasher(nil)
+	require.EqualValues(t, txs.Hash(), (&Data{Txs: txs}).Hash())
+}
+
+func TestDataValidateBasicSquareSize(t *testing.T) {
+	// 10 txs of 1000 bytes take 21 compact shares, so they need at least an
+	// 8x8 square.
+	txs := make(Txs, 10)
+	for i := range txs {
+		txs[i] = cmtrand.Bytes(1000)
+	}
+	data := &Data{Txs: txs, Blobs: testBlobs(t)}
+	require.EqualValues(t, 8, data.MinSquareSize())
+
+	testCases := []struct {
+		msg        string
+		squareSize uint64
+		expPass    bool
+	}{
+		{"unknown square size", 0, true},
+		{"too small square size", 4, false},
+		{"minimal square size", 8, true},
+		{"larger square size", 64, true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.msg, func(t *testing.T) {
+			data.SquareSize = tc.squareSize
+			// The square size is only checked on validation, not on decode.
+			protoData := data.ToProto()
+			d, err := DataFromProto(&protoData)
+			require.NoError(t, err)
+			require.Equal(t, tc.squareSize, d.SquareSize)
+			if tc.expPass {
+				require.NoError(t, d.ValidateBasic())
+			} else {
+				require.Error(t, d.ValidateBasic())
+			}
+		})
+	}
+}
+
+func TestDataValidateSquareSizeStrict(t *testing.T) {
+	data := &Data{Txs: Txs{Tx("tx")}}
+	require.EqualValues(t, 1, data.MinSquareSize())
+
+	data.SquareSize = 0
+	require.NoError(t, data.ValidateSquareSize(false))
+	require.Error(t, data.ValidateSquareSize(true))
+
+	data.SquareSize = 6
+	require.NoError(t, data.ValidateSquareSize(false))
+	require.Error(t, data.ValidateSquareSize(true))
+
+	data.SquareSize = 4
+	require.NoError(t, data.ValidateSquareSize(true))
+}
+
+func TestDataMinSquareSizeBlobTx(t *testing.T) {
+	ns := share.MustNewV0Namespace(stdbytes.Repeat([]byte{1}, share.NamespaceVersionZeroIDSize))
+	blob := &cmtproto.Blob{
+		NamespaceId: ns.ID(),
+		Data:        stdbytes.Repeat([]byte{1}, 20*share.ContinuationSparseShareContentSize),
+	}
+	blobTx, err := MarshalBlobTx([]byte("pfb"), blob)
+	require.NoError(t, err)
+
+	// The blob of the blob tx is laid out in sparse shares of its own, so the
+	// tx needs a larger square than its raw size alone would suggest.
+	data := &Data{Txs: Txs{blobTx}, SquareSize: 4}
+	require.EqualValues(t, 8, data.MinSquareSize())
+	require.Error(t, data.ValidateSquareSize(false))
+}
+
+func TestBlobsRoot(t *testing.T) {
+	blobs := testBlobs(t)
+	unsorted := []Blob{blobs[1], blobs[0]}
+
+	root, err := BlobsRoot(unsorted, 4)
+	require.NoError(t, err)
+	// The given blobs are left untouched.
+	require.Equal(t, blobs[1], unsorted[0])
+
+	// The root matches the one the data hash of a block with the same blobs
+	// commits to.
+	txs := Txs{Tx("tx")}
+	block := MakeBlock(1, Data{Txs: txs, Blobs: blobs}, new(Commit), nil)
+	require.Equal(t, merkle.HashFromByteSlices([][]byte{txs.Hash(), root}), block.DataHash.Bytes())
+
+	// The order of the given blobs doesn't matter.
+	sortedRoot, err := BlobsRoot(blobs, 4)
+	require.NoError(t, err)
+	require.Equal(t, root, sortedRoot)
+
+	// The blobs take 4 shares, which don't fit in a 1x1 square.
+	_, err = BlobsRoot(blobs, 1)
+	require.Error(t, err)
+	_, err = BlobsRoot(blobs, 3)
+	require.Error(t, err)
+
+	// Blobs can't use reserved namespaces.
+	invalid := testBlobs(t)
+	invalid[0].NamespaceID = share.TxNamespace.ID()
+	_, err = BlobsRoot(invalid, 4)
+	require.Error(t, err)
+
+	// Nor be empty.
+	invalid = testBlobs(t)
+	invalid[1].Data = nil
+	_, err = BlobsRoot(invalid, 4)
+	require.Error(t, err)
+}
+
+func TestDataTxInclusionProof(t *testing.T) {
+	txs := makeTxs(5, 10)
+	for _, data := range []*Data{
+		{Txs: txs},
+		{Txs: txs, Blobs: testBlobs(t)},
+	} {
+		for i, tx := range data.Txs {
+			proof, err := data.TxInclusionProof(i)
+			require.NoError(t, err)
+			require.EqualValues(t, tx, proof.Data)
+			require.NoError(t, proof.Validate(data.Hash()))
+
+			// The proof doesn't hold for other data.
+			other := &Data{Txs: txs[:4], Blobs: data.Blobs}
+			require.Error(t, proof.Validate(other.Hash()))
+		}
+		_, err := data.TxInclusionProof(len(txs))
+		require.Error(t, err)
+		_, err = data.TxInclusionProof(-1)
+		require.Error(t, err)
+	}
+
+	// A proof can't be built when the hash doesn't come from the txs.
+	data := NewData(txs, 0, []byte("data root set by the application"))
+	_, err := data.TxInclusionProof(0)
+	require.Error(t, err)
+}
+
+func TestDataNamespaceIndex(t *testing.T) {
+	blobs := testBlobs(t)
+	blob := func(b Blob) *cmtproto.Blob {
+		return &cmtproto.Blob{
+			NamespaceVersion: uint32(b.NamespaceVersion),
+			NamespaceId:      b.NamespaceID,
+			Data:             b.Data,
+		}
+	}
+	tx1, err := MarshalBlobTx([]byte("pfb1"), blob(blobs[0]), blob(blobs[0]))
+	require.NoError(t, err)
+	tx3, err := MarshalBlobTx([]byte("pfb3"), blob(blobs[0]), blob(blobs[1]))
+	require.NoError(t, err)
+
+	data := &Data{Txs: Txs{Tx("tx0"), tx1, Tx("tx2"), tx3}}
+	ns1 := string(append([]byte{blobs[0].NamespaceVersion}, blobs[0].NamespaceID...))
+	ns2 := string(append([]byte{blobs[1].NamespaceVersion}, blobs[1].NamespaceID...))
+	require.Equal(t, map[string][]int{ns1: {1, 3}, ns2: {3}}, data.NamespaceIndex())
+
+	// The indexed txs can be proven against the data hash.
+	proof, err := data.TxInclusionProof(data.NamespaceIndex()[ns2][0])
+	require.NoError(t, err)
+	require.EqualValues(t, tx3, proof.Data)
+	require.NoError(t, proof.Validate(data.Hash()))
+}
+
+func TestBlobValidateBasic(t *testing.T) {
+	testCases := []struct {
+		name     string
+		malleate func(*Blob)
+		expErr   bool
+	}{
+		{"valid", func(*Blob) {}, false},
+		{"unknown namespace version", func(b *Blob) { b.NamespaceVersion = 1 }, true},
+		{"short namespace ID", func(b *Blob) { b.NamespaceID = b.NamespaceID[1:] }, true},
+		{"reserved namespace", func(b *Blob) { b.NamespaceID = share.TxNamespace.ID() }, true},
+		{"unsupported share version", func(b *Blob) { b.ShareVersion = share.ShareVersionOne }, true},
+		{"empty data", func(b *Blob) { b.Data = nil }, true},
+		{"data too big", func(b *Blob) { b.Data = make([]byte, MaxBlobSizeBytes+1) }, true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			blob := testBlobs(t)[0]
+			tc.malleate(&blob)
+			err := blob.ValidateBasic()
+			if tc.expErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestBlobMarshalProto(t *testing.T) {
+	for _, blob := range testBlobs(t) {
+		bz, err := blob.MarshalProto()
+		require.NoError(t, err)
+
+		var decoded Blob
+		require.NoError(t, decoded.UnmarshalProto(bz))
+		require.Equal(t, blob, decoded)
+
+		// Trailing fields make another encoding of the same blob.
+		require.Error(t, decoded.UnmarshalProto(append(bz, 0x28, 0x00)))
+		require.Error(t, decoded.UnmarshalProto(bz[:len(bz)-1]))
+	}
+
+	invalid := testBlobs(t)[0]
+	invalid.Data = nil
+	_, err := invalid.MarshalProto()
+	require.Error(t, err)
+	bz, err := invalid.ToProto().Marshal()
+	require.NoError(t, err)
+	var decoded Blob
+	require.Error(t, decoded.UnmarshalProto(bz))
+}
+
+func testBlobs(t *testing.T) []Blob {
+	t.Helper()
+	ns1 := share.MustNewV0Namespace(stdbytes.Repeat([]byte{1}, share.NamespaceVersionZeroIDSize))
+	ns2 := share.MustNewV0Namespace(stdbytes.Repeat([]byte{2}, share.NamespaceVersionZeroIDSize))
+	return []Blob{
+		{NamespaceVersion: ns1.Version(), NamespaceID: ns1.ID(), Data: []byte("blob one")},
+		{NamespaceVersion: ns2.Version(), NamespaceID: ns2.ID(), Data: stdbytes.Repeat([]byte{2}, 1000)},
+	}
+}
+
 // TestEvidenceDataProtoBuf ensures parity in converting to and from proto.
 func TestEvidenceDataProtoBuf(t *testing.T) {
 	const chainID = "mychain"
@@ -912,6 +1474,32 @@ func TestHeaderProto(t *testing.T) {
 	}
 }
 
+func TestHeaderValidateBasicWithChainID(t *testing.T) {
+	h := makeRandHeader()
+	require.NoError(t, h.ValidateBasicWithChainID(h.ChainID))
+	require.Error(t, h.ValidateBasicWithChainID("other-chain"))
+
+	// The basic validation is still performed.
+	h.Height = -1
+	require.Error(t, h.ValidateBasicWithChainID(h.ChainID))
+}
+
+func TestHeaderValidateBasicAppHashValidator(t *testing.T) {
+	h := makeRandHeader()
+	h.AppHash = []byte("short app hash")
+	require.NoError(t, h.ValidateBasic())
+
+	SetAppHashValidator(AppHashLength(32))
+	defer SetAppHashValidator(nil)
+	require.Error(t, h.ValidateBasic())
+	h.AppHash = stdbytes.Repeat([]byte{1}, 32)
+	require.NoError(t, h.ValidateBasic())
+
+	SetAppHashValidator(nil)
+	h.AppHash = nil
+	require.NoError(t, h.ValidateBasic())
+}
+
 func TestBlockIDProtoBuf(t *testing.T) {
 	blockID := makeBlockID([]byte("hash"), 2, []byte("part_set_hash"))
 	testCases := []struct {
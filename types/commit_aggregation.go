@@ -0,0 +1,190 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cometbft/cometbft/crypto"
+	"github.com/cometbft/cometbft/libs/bits"
+	cmtproto "github.com/cometbft/cometbft/proto/tendermint/types"
+)
+
+// SignatureAggregator aggregates the signatures of several keys into a single
+// signature, as signature schemes such as BLS allow.
+type SignatureAggregator interface {
+	// Aggregate returns the aggregate of the given signatures.
+	Aggregate(sigs [][]byte) ([]byte, error)
+	// VerifyAggregate returns whether sig is the aggregate of the signatures
+	// of msgs[i] by pubKeys[i], for every i.
+	VerifyAggregate(pubKeys []crypto.PubKey, msgs [][]byte, sig []byte) bool
+}
+
+// AggregatedCommit is a commit carrying a single aggregated signature of the
+// validators that voted for the block, along with a bitmap of them, instead of
+// a signature per validator. Only the votes for the block are kept: absent and
+// nil votes aren't part of an aggregated commit.
+//
+// NOTE: aggregated commits aren't part of blocks yet. They are only allowed
+// from the height set by ABCIParams.CommitAggregationEnableHeight, and require
+// a key type that supports aggregation, which the validators must all use.
+type AggregatedCommit struct {
+	Height  int64   `json:"height"`
+	Round   int32   `json:"round"`
+	BlockID BlockID `json:"block_id"`
+	// Signers marks the validators whose signatures were aggregated, by their
+	// index in the validator set.
+	Signers *bits.BitArray `json:"signers"`
+	// Timestamps holds the timestamp of the vote of each signer, in order,
+	// since each validator signs its own.
+	Timestamps []time.Time `json:"timestamps"`
+	Signature  []byte      `json:"signature"`
+}
+
+// Aggregate returns an aggregated commit of the signatures of the votes for
+// the block of the commit.
+func (commit *Commit) Aggregate(agg SignatureAggregator) (*AggregatedCommit, error) {
+	ac := &AggregatedCommit{
+		Height:  commit.Height,
+		Round:   commit.Round,
+		BlockID: commit.BlockID,
+		Signers: bits.NewBitArray(len(commit.Signatures)),
+	}
+	var sigs [][]byte
+	for i, cs := range commit.Signatures {
+		if cs.BlockIDFlag != BlockIDFlagCommit {
+			continue
+		}
+		ac.Signers.SetIndex(i, true)
+		ac.Timestamps = append(ac.Timestamps, cs.Timestamp)
+		sigs = append(sigs, cs.Signature)
+	}
+	if len(sigs) == 0 {
+		return nil, errors.New("no signatures for the block to aggregate")
+	}
+	sig, err := agg.Aggregate(sigs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate signatures: %w", err)
+	}
+	ac.Signature = sig
+	return ac, nil
+}
+
+// ValidateBasic performs basic validation that doesn't involve state data.
+// Does not actually check the aggregated signature.
+func (ac *AggregatedCommit) ValidateBasic() error {
+	if ac.Height <= 0 {
+		return ErrInvalidHeight{Height: ac.Height}
+	}
+	if ac.Round < 0 {
+		return ErrInvalidRound{Round: ac.Round}
+	}
+	if ac.BlockID.IsZero() {
+		return ErrCommitForNilBlock
+	}
+	if ac.Signers == nil {
+		return errors.New("nil signers")
+	}
+	if n := len(ac.Signers.GetTrueIndices()); n != len(ac.Timestamps) {
+		return fmt.Errorf("got %d timestamps for %d signers", len(ac.Timestamps), n)
+	}
+	if len(ac.Signature) == 0 {
+		return errors.New("signature is missing")
+	}
+	return nil
+}
+
+// Verify verifies that +2/3 of the voting power of vals, the validator set at
+// the height of the commit, signed the block, and that the aggregated
+// signature is valid.
+func (ac *AggregatedCommit) Verify(chainID string, vals *ValidatorSet, agg SignatureAggregator) error {
+	if vals == nil {
+		return errors.New("nil validator set")
+	}
+	if err := ac.ValidateBasic(); err != nil {
+		return err
+	}
+	if ac.Signers.Size() != vals.Size() {
+		return NewErrInvalidCommitSignatures(vals.Size(), ac.Signers.Size())
+	}
+
+	// Rebuild the commit the signatures were made for, to get the sign bytes
+	// of each vote.
+	commit := &Commit{
+		Height:     ac.Height,
+		Round:      ac.Round,
+		BlockID:    ac.BlockID,
+		Signatures: make([]CommitSig, vals.Size()),
+	}
+	var (
+		pubKeys []crypto.PubKey
+		msgs    [][]byte
+		tallied int64
+	)
+	for i, idx := range ac.Signers.GetTrueIndices() {
+		val := vals.Validators[idx]
+		commit.Signatures[idx] = CommitSig{
+			BlockIDFlag:      BlockIDFlagCommit,
+			ValidatorAddress: val.Address,
+			Timestamp:        ac.Timestamps[i],
+		}
+		pubKeys = append(pubKeys, val.PubKey)
+		msgs = append(msgs, commit.VoteSignBytes(chainID, int32(idx)))
+		tallied += val.VotingPower
+	}
+
+	if needed := vals.TotalVotingPower() * 2 / 3; tallied <= needed {
+		return ErrNotEnoughVotingPowerSigned{Got: tallied, Needed: needed}
+	}
+	if !agg.VerifyAggregate(pubKeys, msgs, ac.Signature) {
+		return errors.New("wrong aggregated signature")
+	}
+	return nil
+}
+
+// ToProto converts AggregatedCommit to protobuf.
+func (ac *AggregatedCommit) ToProto() *cmtproto.AggregatedCommit {
+	if ac == nil {
+		return nil
+	}
+	return &cmtproto.AggregatedCommit{
+		Height:     ac.Height,
+		Round:      ac.Round,
+		BlockID:    ac.BlockID.ToProto(),
+		Signers:    ac.Signers.ToProto(),
+		Timestamps: ac.Timestamps,
+		Signature:  ac.Signature,
+	}
+}
+
+// AggregatedCommitFromProto creates an AggregatedCommit from its protobuf
+// representation. It returns an error if the commit is invalid.
+func AggregatedCommitFromProto(pac *cmtproto.AggregatedCommit) (*AggregatedCommit, error) {
+	if pac == nil {
+		return nil, errors.New("nil AggregatedCommit")
+	}
+
+	bi, err := BlockIDFromProto(&pac.BlockID)
+	if err != nil {
+		return nil, err
+	}
+	if pac.Signers == nil {
+		return nil, errors.New("nil signers")
+	}
+	if pac.Signers.Bits <= 0 || int64(len(pac.Signers.Elems)) != (pac.Signers.Bits+63)/64 {
+		return nil, fmt.Errorf("invalid signers: %d elems for %d bits",
+			len(pac.Signers.Elems), pac.Signers.Bits)
+	}
+	signers := new(bits.BitArray)
+	signers.FromProto(pac.Signers)
+
+	ac := &AggregatedCommit{
+		Height:     pac.Height,
+		Round:      pac.Round,
+		BlockID:    *bi,
+		Signers:    signers,
+		Timestamps: pac.Timestamps,
+		Signature:  pac.Signature,
+	}
+	return ac, ac.ValidateBasic()
+}
@@ -0,0 +1,91 @@
+package types
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cometbft/cometbft/crypto"
+	cmtproto "github.com/cometbft/cometbft/proto/tendermint/types"
+)
+
+// concatAggregator "aggregates" signatures by concatenating them, so that
+// aggregated commits can be tested with ed25519 keys.
+type concatAggregator struct{}
+
+func (concatAggregator) Aggregate(sigs [][]byte) ([]byte, error) {
+	var agg []byte
+	for _, sig := range sigs {
+		if len(sig) != MaxSignatureSize {
+			return nil, errors.New("unexpected signature size")
+		}
+		agg = append(agg, sig...)
+	}
+	return agg, nil
+}
+
+func (concatAggregator) VerifyAggregate(pubKeys []crypto.PubKey, msgs [][]byte, sig []byte) bool {
+	if len(sig) != len(pubKeys)*MaxSignatureSize {
+		return false
+	}
+	for i, pubKey := range pubKeys {
+		if !pubKey.VerifySignature(msgs[i], sig[i*MaxSignatureSize:(i+1)*MaxSignatureSize]) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestAggregatedCommit(t *testing.T) {
+	const chainID = "test_chain_id"
+	h := int64(3)
+	blockID := makeBlockIDRandom()
+
+	voteSet, valSet, vals := randVoteSet(h, 0, cmtproto.PrecommitType, 4, 10, false)
+	extCommit, err := MakeExtCommit(blockID, h, 0, voteSet, vals, time.Now(), false)
+	require.NoError(t, err)
+	commit := extCommit.ToCommit()
+	// Absent votes are left out.
+	commit.Signatures[2] = NewCommitSigAbsent()
+
+	ac, err := commit.Aggregate(concatAggregator{})
+	require.NoError(t, err)
+	require.Equal(t, []int{0, 1, 3}, ac.Signers.GetTrueIndices())
+	require.Len(t, ac.Timestamps, 3)
+	require.NoError(t, ac.Verify(chainID, valSet, concatAggregator{}))
+	require.Error(t, ac.Verify("other_chain", valSet, concatAggregator{}))
+
+	// The commit survives a protobuf round trip.
+	bz, err := ac.ToProto().Marshal()
+	require.NoError(t, err)
+	var pac cmtproto.AggregatedCommit
+	require.NoError(t, pac.Unmarshal(bz))
+	decoded, err := AggregatedCommitFromProto(&pac)
+	require.NoError(t, err)
+	require.NoError(t, decoded.Verify(chainID, valSet, concatAggregator{}))
+	require.Equal(t, ac.Signers.GetTrueIndices(), decoded.Signers.GetTrueIndices())
+
+	// Signers whose elements don't match their size are rejected.
+	pac.Signers.Bits = 100
+	_, err = AggregatedCommitFromProto(&pac)
+	require.Error(t, err)
+
+	// The signers must hold more than 2/3 of the voting power.
+	commit.Signatures[3] = NewCommitSigAbsent()
+	ac, err = commit.Aggregate(concatAggregator{})
+	require.NoError(t, err)
+	var errPower ErrNotEnoughVotingPowerSigned
+	require.ErrorAs(t, ac.Verify(chainID, valSet, concatAggregator{}), &errPower)
+
+	// The signers must match the timestamps.
+	ac.Timestamps = ac.Timestamps[1:]
+	require.Error(t, ac.ValidateBasic())
+
+	for i := range commit.Signatures {
+		commit.Signatures[i] = NewCommitSigAbsent()
+	}
+	_, err = commit.Aggregate(concatAggregator{})
+	require.Error(t, err)
+}
@@ -1,6 +1,12 @@
 package types
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cometbft/cometbft/crypto"
+	cmtbytes "github.com/cometbft/cometbft/libs/bytes"
+)
 
 type (
 	// ErrInvalidCommitHeight is returned when we encounter a commit with an
@@ -39,3 +45,129 @@ func NewErrInvalidCommitSignatures(expected, actual int) ErrInvalidCommitSignatu
 func (e ErrInvalidCommitSignatures) Error() string {
 	return fmt.Sprintf("Invalid commit -- wrong set size: %v vs %v", e.Expected, e.Actual)
 }
+
+// Sentinel errors returned by the basic validation of blocks and commits.
+var (
+	ErrNilLastCommit      = errors.New("nil LastCommit")
+	ErrCommitForNilBlock  = errors.New("commit cannot be for nil block")
+	ErrNoCommitSignatures = errors.New("no signatures in commit")
+)
+
+type (
+	// ErrWrongBlockProtocol is returned when a header has an unexpected block
+	// protocol version.
+	ErrWrongBlockProtocol struct {
+		Expected uint64
+		Actual   uint64
+	}
+
+	// ErrChainIDTooLong is returned when a header has a chain ID longer than
+	// MaxChainIDLen.
+	ErrChainIDTooLong struct {
+		Length int
+		Max    int
+	}
+
+	// ErrInvalidHeight is returned when a header or commit has a negative or,
+	// for headers, zero height.
+	ErrInvalidHeight struct {
+		Height int64
+	}
+
+	// ErrInvalidRound is returned when a commit has a negative round.
+	ErrInvalidRound struct {
+		Round int32
+	}
+
+	// ErrInvalidProposerAddress is returned when a header has a proposer
+	// address of the wrong size.
+	ErrInvalidProposerAddress struct {
+		Length int
+	}
+
+	// ErrInvalidField is returned when a field of a header or block, such as
+	// one of the hashes of a header, is malformed.
+	ErrInvalidField struct {
+		Field  string
+		Reason error
+	}
+
+	// ErrInvalidCommitSig is returned when a signature of a commit is
+	// malformed.
+	ErrInvalidCommitSig struct {
+		Index  int
+		Reason error
+	}
+
+	// ErrWrongLastCommitHash is returned when the LastCommitHash of a header
+	// doesn't match the last commit of its block.
+	ErrWrongLastCommitHash struct {
+		Expected cmtbytes.HexBytes
+		Actual   cmtbytes.HexBytes
+	}
+
+	// ErrWrongDataHash is returned when the DataHash of a header doesn't match
+	// the data of its block.
+	ErrWrongDataHash struct {
+		Expected cmtbytes.HexBytes
+		Actual   cmtbytes.HexBytes
+	}
+
+	// ErrWrongEvidenceHash is returned when the EvidenceHash of a header
+	// doesn't match the evidence of its block.
+	ErrWrongEvidenceHash struct {
+		Expected cmtbytes.HexBytes
+		Actual   cmtbytes.HexBytes
+	}
+)
+
+func (e ErrWrongBlockProtocol) Error() string {
+	return fmt.Sprintf("block protocol is incorrect: got: %d, want: %d ", e.Actual, e.Expected)
+}
+
+func (e ErrChainIDTooLong) Error() string {
+	return fmt.Sprintf("chainID is too long; got: %d, max: %d", e.Length, e.Max)
+}
+
+func (e ErrInvalidHeight) Error() string {
+	if e.Height == 0 {
+		return "zero Height"
+	}
+	return "negative Height"
+}
+
+func (e ErrInvalidRound) Error() string {
+	return "negative Round"
+}
+
+func (e ErrInvalidProposerAddress) Error() string {
+	return fmt.Sprintf("invalid ProposerAddress length; got: %d, expected: %d", e.Length, crypto.AddressSize)
+}
+
+func (e ErrInvalidField) Error() string {
+	return fmt.Sprintf("wrong %s: %v", e.Field, e.Reason)
+}
+
+func (e ErrInvalidField) Unwrap() error {
+	return e.Reason
+}
+
+func (e ErrInvalidCommitSig) Error() string {
+	return fmt.Sprintf("wrong CommitSig #%d: %v", e.Index, e.Reason)
+}
+
+func (e ErrInvalidCommitSig) Unwrap() error {
+	return e.Reason
+}
+
+func (e ErrWrongLastCommitHash) Error() string {
+	return fmt.Sprintf("wrong Header.LastCommitHash. Expected %v, got %v", e.Expected, e.Actual)
+}
+
+func (e ErrWrongDataHash) Error() string {
+	return fmt.Sprintf("wrong Header.DataHash. Expected %v, got %v", e.Expected, e.Actual)
+}
+
+func (e ErrWrongEvidenceHash) Error() string {
+	return fmt.Sprintf("wrong Header.EvidenceHash. Expected %v, got %v", e.Expected, e.Actual)
+}
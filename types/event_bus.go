@@ -183,12 +183,37 @@ func (b *EventBus) PublishEventTx(data EventDataTx) error {
 
 	// add predefined compositeKeys
 	events[EventTypeKey] = append(events[EventTypeKey], EventTx)
-	events[TxHashKey] = append(events[TxHashKey], fmt.Sprintf("%X", Tx(data.Tx).Hash()))
+	events[TxHashKey] = append(events[TxHashKey], fmt.Sprintf("%X", data.TxHash()))
 	events[TxHeightKey] = append(events[TxHeightKey], fmt.Sprintf("%d", data.Height))
 
 	return b.pubsub.PublishWithEvents(ctx, data, events)
 }
 
+// PublishEventTxEvicted publishes the eviction of a transaction from the
+// mempool, with the TxHashKey of the transaction.
+func (b *EventBus) PublishEventTxEvicted(data EventDataTxEvicted) error {
+	events := map[string][]string{
+		EventTypeKey: {EventTxEvicted},
+		TxHashKey:    {fmt.Sprintf("%X", data.Tx.Hash())},
+	}
+	return b.pubsub.PublishWithEvents(context.Background(), data, events)
+}
+
+// PublishEventPeerRejected publishes the rejection of a peer, with the
+// PeerRejectReasonKey of the rejection.
+func (b *EventBus) PublishEventPeerRejected(data EventDataPeerRejected) error {
+	events := map[string][]string{
+		EventTypeKey:        {EventPeerRejected},
+		PeerRejectReasonKey: {data.Reason},
+	}
+	return b.pubsub.PublishWithEvents(context.Background(), data, events)
+}
+
+// PublishEventStateSyncStatus publishes the progress of a state sync.
+func (b *EventBus) PublishEventStateSyncStatus(data EventDataStateSyncStatus) error {
+	return b.Publish(EventStateSyncStatus, data)
+}
+
 func (b *EventBus) PublishEventNewRoundStep(data EventDataRoundState) error {
 	return b.Publish(EventNewRoundStep, data)
 }
@@ -277,6 +302,18 @@ func (NopEventBus) PublishEventTx(EventDataTx) error {
 	return nil
 }
 
+func (NopEventBus) PublishEventTxEvicted(EventDataTxEvicted) error {
+	return nil
+}
+
+func (NopEventBus) PublishEventPeerRejected(EventDataPeerRejected) error {
+	return nil
+}
+
+func (NopEventBus) PublishEventStateSyncStatus(EventDataStateSyncStatus) error {
+	return nil
+}
+
 func (NopEventBus) PublishEventNewRoundStep(EventDataRoundState) error {
 	return nil
 }
@@ -49,7 +49,7 @@ func TestEventBusPublishEventTx(t *testing.T) {
 		close(done)
 	}()
 
-	err = eventBus.PublishEventTx(EventDataTx{abci.TxResult{
+	err = eventBus.PublishEventTx(EventDataTx{TxResult: abci.TxResult{
 		Height: 1,
 		Index:  0,
 		Tx:     tx,
@@ -64,6 +64,69 @@ func TestEventBusPublishEventTx(t *testing.T) {
 	}
 }
 
+func TestEventBusPublishEventTxEvicted(t *testing.T) {
+	eventBus := NewEventBus()
+	err := eventBus.Start()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if err := eventBus.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	tx := Tx("foo")
+	sub, err := eventBus.Subscribe(context.Background(), "test", EventQueryTxEvictedFor(tx))
+	require.NoError(t, err)
+
+	// The eviction of another transaction doesn't match the query.
+	err = eventBus.PublishEventTxEvicted(EventDataTxEvicted{Tx: Tx("bar"), Reason: TxEvictedExpired})
+	require.NoError(t, err)
+	err = eventBus.PublishEventTxEvicted(EventDataTxEvicted{Tx: tx, Reason: TxEvictedReplaced})
+	require.NoError(t, err)
+
+	select {
+	case msg := <-sub.Out():
+		edt := msg.Data().(EventDataTxEvicted)
+		assert.EqualValues(t, tx, edt.Tx)
+		assert.Equal(t, TxEvictedReplaced, edt.Reason)
+	case <-time.After(1 * time.Second):
+		t.Fatal("did not receive an evicted transaction after 1 sec.")
+	}
+}
+
+func TestEventBusPublishEventPeerRejected(t *testing.T) {
+	eventBus := NewEventBus()
+	err := eventBus.Start()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if err := eventBus.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	query := cmtquery.MustCompile(fmt.Sprintf("%s='%s' AND %s='chain_id_mismatch'",
+		EventTypeKey, EventPeerRejected, PeerRejectReasonKey))
+	sub, err := eventBus.Subscribe(context.Background(), "test", query)
+	require.NoError(t, err)
+
+	// A rejection for another reason doesn't match the query.
+	err = eventBus.PublishEventPeerRejected(EventDataPeerRejected{PeerID: "a", Reason: "duplicate"})
+	require.NoError(t, err)
+	err = eventBus.PublishEventPeerRejected(EventDataPeerRejected{
+		PeerID: "b", Reason: "chain_id_mismatch", ChainID: "chain-a", PeerChainID: "chain-b",
+	})
+	require.NoError(t, err)
+
+	select {
+	case msg := <-sub.Out():
+		edt := msg.Data().(EventDataPeerRejected)
+		assert.Equal(t, "b", edt.PeerID)
+		assert.Equal(t, "chain-b", edt.PeerChainID)
+	case <-time.After(1 * time.Second):
+		t.Fatal("did not receive a rejected peer after 1 sec.")
+	}
+}
+
 func TestEventBusPublishEventNewBlock(t *testing.T) {
 	eventBus := NewEventBus()
 	err := eventBus.Start()
@@ -203,7 +266,7 @@ func TestEventBusPublishEventTxDuplicateKeys(t *testing.T) {
 			}
 		}()
 
-		err = eventBus.PublishEventTx(EventDataTx{abci.TxResult{
+		err = eventBus.PublishEventTx(EventDataTx{TxResult: abci.TxResult{
 			Height: 1,
 			Index:  0,
 			Tx:     tx,
@@ -569,7 +632,7 @@ func TestEventBusPublishEventIndexWrapper(t *testing.T) {
 		close(done)
 	}()
 
-	err = eventBus.PublishEventTx(EventDataTx{abci.TxResult{
+	err = eventBus.PublishEventTx(EventDataTx{TxResult: abci.TxResult{
 		Height: 1,
 		Index:  0,
 		Tx:     tx,
@@ -2,8 +2,10 @@ package types
 
 import (
 	"fmt"
+	"time"
 
 	abci "github.com/cometbft/cometbft/abci/types"
+	cmtbytes "github.com/cometbft/cometbft/libs/bytes"
 	cmtjson "github.com/cometbft/cometbft/libs/json"
 	cmtpubsub "github.com/cometbft/cometbft/libs/pubsub"
 	cmtquery "github.com/cometbft/cometbft/libs/pubsub/query"
@@ -24,6 +26,18 @@ const (
 	EventTx                  = "Tx"
 	EventValidatorSetUpdates = "ValidatorSetUpdates"
 
+	// Mempool events, triggered when a transaction leaves the mempool without
+	// being committed.
+	EventTxEvicted = "TxEvicted"
+
+	// P2P events, triggered when a peer is rejected, so that operators can
+	// diagnose misconfigurations such as chain ID mismatches remotely.
+	EventPeerRejected = "PeerRejected"
+
+	// State sync events, triggered as a state sync makes progress, so that
+	// operators can monitor snapshot restores.
+	EventStateSyncStatus = "StateSyncStatus"
+
 	// Internal consensus events.
 	// These are used for testing the consensus state machine.
 	// They can also be used to build real-time consensus visualizers.
@@ -59,6 +73,9 @@ func init() {
 	cmtjson.RegisterType(EventDataCompleteProposal{}, "tendermint/event/CompleteProposal")
 	cmtjson.RegisterType(EventDataVote{}, "tendermint/event/Vote")
 	cmtjson.RegisterType(EventDataValidatorSetUpdates{}, "tendermint/event/ValidatorSetUpdates")
+	cmtjson.RegisterType(EventDataTxEvicted{}, "tendermint/event/TxEvicted")
+	cmtjson.RegisterType(EventDataPeerRejected{}, "tendermint/event/PeerRejected")
+	cmtjson.RegisterType(EventDataStateSyncStatus{}, "tendermint/event/StateSyncStatus")
 	cmtjson.RegisterType(EventDataString(""), "tendermint/event/ProposalString")
 }
 
@@ -98,6 +115,85 @@ type EventDataNewEvidence struct {
 // All txs fire EventDataTx
 type EventDataTx struct {
 	abci.TxResult
+
+	// Hash of the tx, if already known. It is computed from the tx otherwise,
+	// see TxHash.
+	Hash []byte `json:"-"`
+}
+
+// TxHash returns the hash of the tx, reusing the one the event was published
+// with if any.
+func (data EventDataTx) TxHash() []byte {
+	if data.Hash != nil {
+		return data.Hash
+	}
+	return Tx(data.Tx).Hash()
+}
+
+// Reasons for which a transaction is evicted from the mempool.
+const (
+	// TxEvictedMempoolFull means the mempool, or the lane of the transaction,
+	// was full, and the transaction had too low a priority to stay.
+	TxEvictedMempoolFull = "mempool_full"
+	// TxEvictedExpired means the transaction was not committed within the
+	// configured TTL.
+	TxEvictedExpired = "expired"
+	// TxEvictedRecheckFailed means the transaction became invalid after a
+	// block and was rejected when rechecked.
+	TxEvictedRecheckFailed = "recheck_failed"
+	// TxEvictedReplaced means the transaction was replaced by one with a
+	// higher priority of the same sender, or of the same account and nonce.
+	TxEvictedReplaced = "replaced"
+)
+
+// EventDataTxEvicted is fired when a transaction is evicted from the mempool,
+// so that its submitter can tell why it will not be committed.
+type EventDataTxEvicted struct {
+	Tx     Tx     `json:"tx"`
+	Reason string `json:"reason"`
+}
+
+// EventDataPeerRejected is fired when a peer is rejected while connecting to
+// it or accepting its connection. Fields that are unknown at the stage the
+// peer was rejected are empty.
+type EventDataPeerRejected struct {
+	PeerID   string `json:"peer_id"`
+	Reason   string `json:"reason"`
+	Outbound bool   `json:"outbound"`
+	Error    string `json:"error"`
+
+	LocalAddr      string `json:"local_addr"`
+	RemoteAddr     string `json:"remote_addr"`
+	HandshakeStage string `json:"handshake_stage"`
+	TraceID        string `json:"trace_id"`
+	ChainID        string `json:"chain_id"`
+	PeerChainID    string `json:"peer_chain_id"`
+}
+
+// EventDataStateSyncStatus is fired when a state sync starts restoring a
+// snapshot or completes, and at most once per second as it discovers
+// snapshots and fetches chunks. Throughput and ETA are estimated from the
+// chunks fetched since the restore started.
+type EventDataStateSyncStatus struct {
+	Syncing   bool                `json:"syncing"`
+	Snapshots []StateSyncSnapshot `json:"snapshots"` // discovered, best first
+	Snapshot  *StateSyncSnapshot  `json:"snapshot"`  // being restored, if any
+
+	ChunksFetched uint32        `json:"chunks_fetched"`
+	ChunksTotal   uint32        `json:"chunks_total"`
+	BytesFetched  int64         `json:"bytes_fetched"`
+	Throughput    float64       `json:"throughput"` // in bytes per second
+	ETA           time.Duration `json:"eta"`
+	Error         string        `json:"error"` // why the state sync failed, if it did
+}
+
+// StateSyncSnapshot is a snapshot discovered by a state sync.
+type StateSyncSnapshot struct {
+	Height uint64            `json:"height"`
+	Format uint32            `json:"format"`
+	Chunks uint32            `json:"chunks"`
+	Hash   cmtbytes.HexBytes `json:"hash"`
+	Peers  int               `json:"peers"`
 }
 
 // NOTE: This goes into the replay WAL
@@ -154,6 +250,11 @@ const (
 
 	// BlockHeightKey is a reserved key used for indexing FinalizeBlock events.
 	BlockHeightKey = "block.height"
+
+	// PeerRejectReasonKey is a reserved key, used to specify why a peer was
+	// rejected.
+	// see EventBus#PublishEventPeerRejected
+	PeerRejectReasonKey = "peer.reject_reason"
 )
 
 var (
@@ -166,11 +267,14 @@ var (
 	EventQueryNewEvidence         = QueryForEvent(EventNewEvidence)
 	EventQueryNewRound            = QueryForEvent(EventNewRound)
 	EventQueryNewRoundStep        = QueryForEvent(EventNewRoundStep)
+	EventQueryPeerRejected        = QueryForEvent(EventPeerRejected)
 	EventQueryPolka               = QueryForEvent(EventPolka)
 	EventQueryRelock              = QueryForEvent(EventRelock)
+	EventQueryStateSyncStatus     = QueryForEvent(EventStateSyncStatus)
 	EventQueryTimeoutPropose      = QueryForEvent(EventTimeoutPropose)
 	EventQueryTimeoutWait         = QueryForEvent(EventTimeoutWait)
 	EventQueryTx                  = QueryForEvent(EventTx)
+	EventQueryTxEvicted           = QueryForEvent(EventTxEvicted)
 	EventQueryUnlock              = QueryForEvent(EventUnlock)
 	EventQueryValidatorSetUpdates = QueryForEvent(EventValidatorSetUpdates)
 	EventQueryValidBlock          = QueryForEvent(EventValidBlock)
@@ -181,6 +285,12 @@ func EventQueryTxFor(tx Tx) cmtpubsub.Query {
 	return cmtquery.MustCompile(fmt.Sprintf("%s='%s' AND %s='%X'", EventTypeKey, EventTx, TxHashKey, tx.Hash()))
 }
 
+// EventQueryTxEvictedFor returns a query matching the eviction of tx from the
+// mempool.
+func EventQueryTxEvictedFor(tx Tx) cmtpubsub.Query {
+	return cmtquery.MustCompile(fmt.Sprintf("%s='%s' AND %s='%X'", EventTypeKey, EventTxEvicted, TxHashKey, tx.Hash()))
+}
+
 func QueryForEvent(eventType string) cmtpubsub.Query {
 	return cmtquery.MustCompile(fmt.Sprintf("%s='%s'", EventTypeKey, eventType))
 }
@@ -578,6 +578,23 @@ func (err *ErrEvidenceOverflow) Error() string {
 	return fmt.Sprintf("Too much evidence: Max %d, got %d", err.Max, err.Got)
 }
 
+// ErrTooManyEvidence is for when the number of evidence items exceeds the max
+// count.
+type ErrTooManyEvidence struct {
+	Max int64
+	Got int64
+}
+
+// NewErrTooManyEvidence returns a new ErrTooManyEvidence where got > max.
+func NewErrTooManyEvidence(max, got int64) *ErrTooManyEvidence {
+	return &ErrTooManyEvidence{max, got}
+}
+
+// Error returns a string representation of the error.
+func (err *ErrTooManyEvidence) Error() string {
+	return fmt.Sprintf("Too many evidence items: Max %d, got %d", err.Max, err.Got)
+}
+
 //-------------------------------------------- MOCKING --------------------------------------
 
 // unstable - use only for testing
@@ -23,6 +23,10 @@ const (
 	// MaxBlockPartsCount is the maximum number of block parts.
 	MaxBlockPartsCount = (MaxBlockSizeBytes / BlockPartSizeBytes) + 1
 
+	// MaxBlobSizeBytes is the maximum size of the data of a blob, which must
+	// fit in a block.
+	MaxBlobSizeBytes = MaxBlockSizeBytes
+
 	ABCIPubKeyTypeEd25519   = ed25519.KeyType
 	ABCIPubKeyTypeSecp256k1 = secp256k1.KeyType
 )
@@ -54,6 +58,7 @@ type EvidenceParams struct {
 	MaxAgeNumBlocks int64         `json:"max_age_num_blocks"` // only accept new evidence more recent than this
 	MaxAgeDuration  time.Duration `json:"max_age_duration"`
 	MaxBytes        int64         `json:"max_bytes"`
+	MaxCount        int64         `json:"max_count"` // 0 means no limit other than MaxBytes
 }
 
 // ValidatorParams restrict the public key types validators can use.
@@ -69,7 +74,8 @@ type VersionParams struct {
 // ABCIParams configure ABCI functionality specific to the Application Blockchain
 // Interface.
 type ABCIParams struct {
-	VoteExtensionsEnableHeight int64 `json:"vote_extensions_enable_height"`
+	VoteExtensionsEnableHeight    int64 `json:"vote_extensions_enable_height"`
+	CommitAggregationEnableHeight int64 `json:"commit_aggregation_enable_height"`
 }
 
 // VoteExtensionsEnabled returns true if vote extensions are enabled at height h
@@ -78,10 +84,27 @@ func (a ABCIParams) VoteExtensionsEnabled(h int64) bool {
 	if h < 1 {
 		panic(fmt.Errorf("cannot check if vote extensions enabled for height %d (< 1)", h))
 	}
-	if a.VoteExtensionsEnableHeight == 0 {
+	return VoteExtensionsEnabled(h, a.VoteExtensionsEnableHeight)
+}
+
+// CommitAggregationEnabled returns true if commits may be aggregated at height
+// h and false otherwise.
+func (a ABCIParams) CommitAggregationEnabled(h int64) bool {
+	if h < 1 {
+		panic(fmt.Errorf("cannot check if commit aggregation enabled for height %d (< 1)", h))
+	}
+	return a.CommitAggregationEnableHeight > 0 && h >= a.CommitAggregationEnableHeight
+}
+
+// VoteExtensionsEnabled returns true if vote extensions are enabled at the
+// given height when they are enabled from enableHeight onward. An enableHeight
+// of 0 (or less) means vote extensions are disabled. Vote extensions are never
+// enabled at a height of 0 (or less), as there are no votes at such heights.
+func VoteExtensionsEnabled(height, enableHeight int64) bool {
+	if height < 1 || enableHeight < 1 {
 		return false
 	}
-	return a.VoteExtensionsEnableHeight <= h
+	return height >= enableHeight
 }
 
 // DefaultConsensusParams returns a default ConsensusParams.
@@ -187,10 +210,20 @@ func (params ConsensusParams) ValidateBasic() error {
 			params.Evidence.MaxBytes)
 	}
 
+	if params.Evidence.MaxCount < 0 {
+		return fmt.Errorf("evidence.MaxCount must be non negative. Got: %d",
+			params.Evidence.MaxCount)
+	}
+
 	if params.ABCI.VoteExtensionsEnableHeight < 0 {
 		return fmt.Errorf("ABCI.VoteExtensionsEnableHeight cannot be negative. Got: %d", params.ABCI.VoteExtensionsEnableHeight)
 	}
 
+	if params.ABCI.CommitAggregationEnableHeight < 0 {
+		return fmt.Errorf("ABCI.CommitAggregationEnableHeight cannot be negative. Got: %d",
+			params.ABCI.CommitAggregationEnableHeight)
+	}
+
 	if len(params.Validator.PubKeyTypes) == 0 {
 		return errors.New("len(Validator.PubKeyTypes) must be greater than 0")
 	}
@@ -207,7 +240,9 @@ func (params ConsensusParams) ValidateBasic() error {
 	return nil
 }
 
-// ValidateUpdate validates the updated VoteExtensionsEnableHeight.
+// ValidateUpdate validates the updated VoteExtensionsEnableHeight and
+// CommitAggregationEnableHeight. The latter follows the same rules as the
+// former, described below.
 // | r | params...EnableHeight | updated...EnableHeight | result (nil == pass)
 // |  1 | *                    | (nil)                  | nil
 // |  2 | *                    | < 0                    | VoteExtensionsEnableHeight must be positive
@@ -224,6 +259,10 @@ func (params ConsensusParams) ValidateUpdate(updated *cmtproto.ConsensusParams,
 	if updated == nil || updated.Abci == nil {
 		return nil
 	}
+	if err := validateCommitAggregationUpdate(params.ABCI.CommitAggregationEnableHeight,
+		updated.Abci.CommitAggregationEnableHeight, h); err != nil {
+		return err
+	}
 	// 2
 	if updated.Abci.VoteExtensionsEnableHeight < 0 {
 		return errors.New("VoteExtensionsEnableHeight must be positive")
@@ -267,6 +306,26 @@ func (params ConsensusParams) ValidateUpdate(updated *cmtproto.ConsensusParams,
 	return nil
 }
 
+// validateCommitAggregationUpdate validates the update of the
+// CommitAggregationEnableHeight from current to updated at height h: it can
+// only be set to 0 or a future height, and can't be changed once reached.
+func validateCommitAggregationUpdate(current, updated, h int64) error {
+	switch {
+	case updated < 0:
+		return errors.New("CommitAggregationEnableHeight must be positive")
+	case updated == current:
+		return nil
+	case current > 0 && current <= h:
+		return fmt.Errorf("commit aggregation cannot be modified once enabled, "+
+			"enable height: %d, current height %d", current, h)
+	case updated > 0 && updated <= h:
+		return fmt.Errorf("commit aggregation cannot be updated to a past or current height, "+
+			"enable height: %d, current height %d", updated, h)
+	default:
+		return nil
+	}
+}
+
 // Hash returns a hash of a subset of the parameters to store in the block header.
 // Only the Block.MaxBytes and Block.MaxGas are included in the hash.
 // This allows the ConsensusParams to evolve more without breaking the block
@@ -309,6 +368,7 @@ func (params ConsensusParams) Update(params2 *cmtproto.ConsensusParams) Consensu
 		res.Evidence.MaxAgeNumBlocks = params2.Evidence.MaxAgeNumBlocks
 		res.Evidence.MaxAgeDuration = params2.Evidence.MaxAgeDuration
 		res.Evidence.MaxBytes = params2.Evidence.MaxBytes
+		res.Evidence.MaxCount = params2.Evidence.MaxCount
 	}
 	if params2.Validator != nil {
 		// Copy params2.Validator.PubkeyTypes, and set result's value to the copy.
@@ -320,6 +380,7 @@ func (params ConsensusParams) Update(params2 *cmtproto.ConsensusParams) Consensu
 	}
 	if params2.Abci != nil {
 		res.ABCI.VoteExtensionsEnableHeight = params2.Abci.GetVoteExtensionsEnableHeight()
+		res.ABCI.CommitAggregationEnableHeight = params2.Abci.GetCommitAggregationEnableHeight()
 	}
 	return res
 }
@@ -334,6 +395,7 @@ func (params *ConsensusParams) ToProto() cmtproto.ConsensusParams {
 			MaxAgeNumBlocks: params.Evidence.MaxAgeNumBlocks,
 			MaxAgeDuration:  params.Evidence.MaxAgeDuration,
 			MaxBytes:        params.Evidence.MaxBytes,
+			MaxCount:        params.Evidence.MaxCount,
 		},
 		Validator: &cmtproto.ValidatorParams{
 			PubKeyTypes: params.Validator.PubKeyTypes,
@@ -342,7 +404,8 @@ func (params *ConsensusParams) ToProto() cmtproto.ConsensusParams {
 			App: params.Version.App,
 		},
 		Abci: &cmtproto.ABCIParams{
-			VoteExtensionsEnableHeight: params.ABCI.VoteExtensionsEnableHeight,
+			VoteExtensionsEnableHeight:    params.ABCI.VoteExtensionsEnableHeight,
+			CommitAggregationEnableHeight: params.ABCI.CommitAggregationEnableHeight,
 		},
 	}
 }
@@ -357,6 +420,7 @@ func ConsensusParamsFromProto(pbParams cmtproto.ConsensusParams) ConsensusParams
 			MaxAgeNumBlocks: pbParams.Evidence.MaxAgeNumBlocks,
 			MaxAgeDuration:  pbParams.Evidence.MaxAgeDuration,
 			MaxBytes:        pbParams.Evidence.MaxBytes,
+			MaxCount:        pbParams.Evidence.MaxCount,
 		},
 		Validator: ValidatorParams{
 			PubKeyTypes: pbParams.Validator.PubKeyTypes,
@@ -367,6 +431,7 @@ func ConsensusParamsFromProto(pbParams cmtproto.ConsensusParams) ConsensusParams
 	}
 	if pbParams.Abci != nil {
 		c.ABCI.VoteExtensionsEnableHeight = pbParams.Abci.GetVoteExtensionsEnableHeight()
+		c.ABCI.CommitAggregationEnableHeight = pbParams.Abci.GetCommitAggregationEnableHeight()
 	}
 	return c
 }
@@ -77,6 +77,25 @@ func makeParams(
 	}
 }
 
+func TestConsensusParamsEvidenceMaxCount(t *testing.T) {
+	params := makeParams(1000, 0, 2, 1, valEd25519, 0)
+	params.Evidence.MaxCount = -1
+	assert.Error(t, params.ValidateBasic())
+	params.Evidence.MaxCount = 5
+	assert.NoError(t, params.ValidateBasic())
+
+	updated := params.Update(&cmtproto.ConsensusParams{
+		Evidence: &cmtproto.EvidenceParams{
+			MaxAgeNumBlocks: 2,
+			MaxAgeDuration:  time.Duration(2),
+			MaxBytes:        1,
+			MaxCount:        10,
+		},
+	})
+	assert.Equal(t, int64(10), updated.Evidence.MaxCount)
+	assert.Equal(t, updated, ConsensusParamsFromProto(updated.ToProto()))
+}
+
 func TestConsensusParamsHash(t *testing.T) {
 	params := []ConsensusParams{
 		makeParams(4, 2, 3, 1, valEd25519, 0),
@@ -228,6 +247,46 @@ func TestConsensusParamsUpdate_VoteExtensionsEnableHeight(t *testing.T) {
 	}
 }
 
+func TestConsensusParamsUpdate_CommitAggregationEnableHeight(t *testing.T) {
+	testCases := []struct {
+		name        string
+		current     int64
+		from        int64
+		to          int64
+		expectedErr bool
+	}{
+		{"no change", 3, 100, 100, false},
+		{"no change once enabled", 300, 100, 100, false},
+		{"set for the first time", 3, 0, 5, false},
+		{"set to the current height", 5, 0, 5, true},
+		{"set to a past height", 6, 0, 5, true},
+		{"reset to 0 before enabled", 4, 5, 0, false},
+		{"reset to 0 once enabled", 5, 5, 0, true},
+		{"modify before enabled", 9, 10, 15, false},
+		{"modify once enabled", 10, 10, 15, true},
+		{"negative", 3, 0, -5, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			initialParams := makeParams(1, 0, 2, 0, valEd25519, 0)
+			initialParams.ABCI.CommitAggregationEnableHeight = tc.from
+			update := &cmtproto.ConsensusParams{
+				Abci: &cmtproto.ABCIParams{CommitAggregationEnableHeight: tc.to},
+			}
+			if tc.expectedErr {
+				require.Error(t, initialParams.ValidateUpdate(update, tc.current))
+			} else {
+				require.NoError(t, initialParams.ValidateUpdate(update, tc.current))
+				updated := initialParams.Update(update)
+				require.Equal(t, tc.to, updated.ABCI.CommitAggregationEnableHeight)
+				require.Equal(t, tc.to > 0 && tc.current+1 >= tc.to,
+					updated.ABCI.CommitAggregationEnabled(tc.current+1))
+			}
+		})
+	}
+}
+
 func TestProto(t *testing.T) {
 	params := []ConsensusParams{
 		makeParams(4, 2, 3, 1, valEd25519, 1),
@@ -239,6 +298,7 @@ func TestProto(t *testing.T) {
 		makeParams(7, 8, 9, 1, valEd25519, 1),
 		makeParams(4, 6, 5, 1, valEd25519, 1),
 	}
+	params[0].ABCI.CommitAggregationEnableHeight = 10
 
 	for i := range params {
 		pbParams := params[i].ToProto()
@@ -249,3 +309,32 @@ func TestProto(t *testing.T) {
 
 	}
 }
+
+func TestVoteExtensionsEnabled(t *testing.T) {
+	testCases := []struct {
+		name         string
+		height       int64
+		enableHeight int64
+		expected     bool
+	}{
+		{"disabled", 10, 0, false},
+		{"disabled at height 1", 1, 0, false},
+		{"negative enable height", 10, -1, false},
+		{"before enable height", 9, 10, false},
+		{"at enable height", 10, 10, true},
+		{"after enable height", 11, 10, true},
+		{"enabled from genesis", 1, 1, true},
+		{"zero height", 0, 1, false},
+		{"negative height", -1, 1, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, VoteExtensionsEnabled(tc.height, tc.enableHeight))
+			if tc.height > 0 {
+				// The method on ABCIParams agrees with the function.
+				params := ABCIParams{VoteExtensionsEnableHeight: tc.enableHeight}
+				require.Equal(t, tc.expected, params.VoteExtensionsEnabled(tc.height))
+			}
+		})
+	}
+}
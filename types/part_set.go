@@ -320,9 +320,10 @@ func Encode(ops *PartSet, partSize uint32) (*PartSet, int, error) {
 	return eps, lastLen, nil
 }
 
-// IsReadyForDecoding returns true if the PartSet has every single part, not just
-// ready to be decoded.
-// TODO: this here only requires 2/3rd. We need all the data now because we have no erasure encoding.
+// IsReadyForDecoding returns true if the PartSet has every single part. On its
+// own, a PartSet can't be decoded from fewer parts: decoding from any half of
+// the original and parity parts built by Encode is done by the
+// CombinedPartSet of the propagation reactor.
 func (ps *PartSet) IsReadyForDecoding() bool {
 	return ps.IsComplete()
 }
@@ -508,16 +509,41 @@ func (ps *PartSet) AddPart(part *Part) (bool, error) {
 		return false, fmt.Errorf("nil part")
 	}
 
-	// The proof should be compatible with the number of parts.
-	if part.Proof.Total != int64(ps.total) {
-		return false, fmt.Errorf(ErrPartSetInvalidProofTotal.Error()+":%v %v", part.Proof.Total, ps.total)
+	if err := verifyPartProof(ps.total, ps.Hash(), part); err != nil {
+		return false, err
+	}
+
+	return ps.AddPartWithoutProof(part)
+}
+
+// VerifyPart checks that the part is valid and that its Merkle proof proves
+// its inclusion in the part set with the given header. It allows a single
+// part to be verified without assembling the whole part set.
+func VerifyPart(psh PartSetHeader, part *Part) error {
+	if part == nil {
+		return errors.New("nil part")
 	}
+	if err := part.ValidateBasic(); err != nil {
+		return err
+	}
+	if part.Index >= psh.Total {
+		return ErrPartSetUnexpectedIndex
+	}
+	return verifyPartProof(psh.Total, psh.Hash, part)
+}
 
-	if err := part.Proof.Verify(ps.Hash(), part.Bytes); err != nil {
-		return false, fmt.Errorf("%w:%w", ErrPartSetInvalidProofHash, err)
+// verifyPartProof checks the Merkle proof of the part against the root hash
+// of a part set with the given number of parts.
+func verifyPartProof(total uint32, hash []byte, part *Part) error {
+	// The proof should be compatible with the number of parts.
+	if part.Proof.Total != int64(total) {
+		return fmt.Errorf("%w:%v %v", ErrPartSetInvalidProofTotal, part.Proof.Total, total)
 	}
 
-	return ps.AddPartWithoutProof(part)
+	if err := part.Proof.Verify(hash, part.Bytes); err != nil {
+		return fmt.Errorf("%w:%w", ErrPartSetInvalidProofHash, err)
+	}
+	return nil
 }
 
 func (ps *PartSet) AddPartWithoutProof(part *Part) (bool, error) {
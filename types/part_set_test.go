@@ -186,6 +186,33 @@ func TestWrongProof(t *testing.T) {
 	}
 }
 
+func TestVerifyPart(t *testing.T) {
+	data := cmtrand.Bytes(int(BlockPartSizeBytes) * 3)
+	partSet, err := NewPartSetFromData(data, BlockPartSizeBytes)
+	require.NoError(t, err)
+	header := partSet.Header()
+
+	for i := 0; i < int(partSet.Total()); i++ {
+		require.NoError(t, VerifyPart(header, partSet.GetPart(i)))
+	}
+
+	// A part from another part set doesn't verify.
+	otherSet, err := NewPartSetFromData(cmtrand.Bytes(int(BlockPartSizeBytes)*3), BlockPartSizeBytes)
+	require.NoError(t, err)
+	require.ErrorIs(t, VerifyPart(header, otherSet.GetPart(0)), ErrPartSetInvalidProofHash)
+
+	// Neither does a part with a tampered proof.
+	part := partSet.GetPart(1)
+	part.Proof.Aunts[0][0] += byte(0x01)
+	require.ErrorIs(t, VerifyPart(header, part), ErrPartSetInvalidProofHash)
+
+	// Nor a part whose proof is for a different number of parts.
+	part = partSet.GetPart(2)
+	require.ErrorIs(t, VerifyPart(PartSetHeader{Total: 4, Hash: header.Hash}, part), ErrPartSetInvalidProofTotal)
+
+	require.Error(t, VerifyPart(header, nil))
+}
+
 func TestPartSetHeaderValidateBasic(t *testing.T) {
 	testCases := []struct {
 		testName              string
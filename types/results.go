@@ -3,6 +3,7 @@ package types
 import (
 	abci "github.com/cometbft/cometbft/abci/types"
 	"github.com/cometbft/cometbft/crypto/merkle"
+	cmtbytes "github.com/cometbft/cometbft/libs/bytes"
 )
 
 // ABCIResults wraps the deliver tx results to return a proof.
@@ -18,6 +19,14 @@ func NewResults(responses []*abci.ExecTxResult) ABCIResults {
 	return res
 }
 
+// ComputeLastResultsHash returns the LastResultsHash expected in the header of
+// the block following the one that produced the given results. Only the
+// deterministic fields of each result are hashed (see
+// deterministicExecTxResult).
+func ComputeLastResultsHash(results []*abci.ExecTxResult) cmtbytes.HexBytes {
+	return NewResults(results).Hash()
+}
+
 // Hash returns a merkle hash of all results.
 func (a ABCIResults) Hash() []byte {
 	return merkle.HashFromByteSlices(a.toByteSlices())
@@ -52,3 +52,33 @@ func TestABCIResults(t *testing.T) {
 		assert.NoError(t, valid, "%d", i)
 	}
 }
+
+func TestComputeLastResultsHash(t *testing.T) {
+	results := []*abci.ExecTxResult{
+		{Code: 0, Data: []byte("one"), GasWanted: 10, GasUsed: 5},
+		{Code: 14, Data: []byte("foo"), GasWanted: 20, GasUsed: 20},
+	}
+	const golden = "FC971715AB38974CE7EA3F2EB8F649306E10479A4C717C8D88DC9D09B79F98FD"
+	assert.Equal(t, golden, ComputeLastResultsHash(results).String())
+
+	// Non-deterministic fields are not hashed.
+	results[0].Log = "log"
+	results[0].Info = "info"
+	results[0].Events = []abci.Event{{Type: "event"}}
+	assert.Equal(t, golden, ComputeLastResultsHash(results).String())
+
+	// Changing the code or the gas changes the hash.
+	results[1].Code = 0
+	assert.NotEqual(t, golden, ComputeLastResultsHash(results).String())
+	results[1].Code = 14
+	results[1].GasUsed = 19
+	assert.NotEqual(t, golden, ComputeLastResultsHash(results).String())
+	results[1].GasUsed = 20
+	results[1].GasWanted = 21
+	assert.NotEqual(t, golden, ComputeLastResultsHash(results).String())
+
+	// No results hash to the hash of an empty tree.
+	assert.Equal(t,
+		"E3B0C44298FC1C149AFBF4C8996FB92427AE41E4649B934CA495991B7852B855",
+		ComputeLastResultsHash(nil).String())
+}
@@ -84,6 +84,16 @@ func CachedTxFromTxs(txs Txs) []*CachedTx {
 	return cachedTxs
 }
 
+// CachedTxFromTxsWithHashes creates a slice of CachedTx from a slice of Tx
+// and the hashes of the txs, e.g. as returned by Block.TxHashes.
+func CachedTxFromTxsWithHashes(txs Txs, hashes [][]byte) []*CachedTx {
+	cachedTxs := make([]*CachedTx, len(txs))
+	for i, tx := range txs {
+		cachedTxs[i] = NewCachedTx(tx, hashes[i])
+	}
+	return cachedTxs
+}
+
 func CachedTxToSliceOfBytes(cachedTxs []*CachedTx) [][]byte {
 	txBzs := make([][]byte, len(cachedTxs))
 	for i := 0; i < len(cachedTxs); i++ {
@@ -52,6 +52,38 @@ func VerifyCommit(chainID string, vals *ValidatorSet, blockID BlockID,
 		ignore, count, true, true)
 }
 
+// VerifyAll verifies the signatures of all the validators that signed the
+// commit, whether for the block or for nil, batching them when the keys of
+// the validators support it. Unlike VerifyCommit, it doesn't require +2/3 of
+// the voting power to have signed. vals must be the validator set that signed
+// the commit.
+func (commit *Commit) VerifyAll(chainID string, vals *ValidatorSet) error {
+	if commit == nil {
+		return errors.New("nil commit")
+	}
+	if err := verifyBasicValsAndCommit(vals, commit, commit.Height, commit.BlockID); err != nil {
+		return err
+	}
+
+	ignore := func(c CommitSig) bool { return c.BlockIDFlag == BlockIDFlagAbsent }
+	count := func(c CommitSig) bool { return true }
+
+	// A commit without any signature has nothing to verify.
+	signed := false
+	for _, c := range commit.Signatures {
+		signed = signed || !ignore(c)
+	}
+	if !signed {
+		return nil
+	}
+
+	// No voting power is needed, so that only the signatures are checked.
+	if shouldBatchVerify(vals, commit) {
+		return verifyCommitBatch(chainID, vals, commit, -1, ignore, count, true, true)
+	}
+	return verifyCommitSingle(chainID, vals, commit, -1, ignore, count, true, true)
+}
+
 // LIGHT CLIENT VERIFICATION METHODS
 
 // VerifyCommitLight verifies +2/3 of the set had signed the given commit.
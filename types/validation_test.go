@@ -182,6 +182,45 @@ func TestValidatorSet_VerifyCommit_CheckAllSignatures(t *testing.T) {
 	}
 }
 
+func TestCommit_VerifyAll(t *testing.T) {
+	var (
+		chainID = "test_chain_id"
+		h       = int64(3)
+		blockID = makeBlockIDRandom()
+	)
+
+	for _, size := range []int{1, 4} { // single and batch verification
+		voteSet, valSet, vals := randVoteSet(h, 0, cmtproto.PrecommitType, size, 10, false)
+		extCommit, err := MakeExtCommit(blockID, h, 0, voteSet, vals, time.Now(), false)
+		require.NoError(t, err)
+		commit := extCommit.ToCommit()
+		require.NoError(t, commit.VerifyAll(chainID, valSet))
+		require.Error(t, commit.VerifyAll("CentaurusA", valSet))
+
+		// Absent signatures are skipped, and no voting power is needed.
+		commit.Signatures[0] = NewCommitSigAbsent()
+		require.NoError(t, commit.VerifyAll(chainID, valSet))
+
+		_, otherSet, _ := randVoteSet(h, 0, cmtproto.PrecommitType, size+1, 10, false)
+		require.Error(t, commit.VerifyAll(chainID, otherSet))
+	}
+
+	// Every signature is checked, even once +2/3 is reached.
+	voteSet, valSet, vals := randVoteSet(h, 0, cmtproto.PrecommitType, 4, 10, false)
+	extCommit, err := MakeExtCommit(blockID, h, 0, voteSet, vals, time.Now(), false)
+	require.NoError(t, err)
+	commit := extCommit.ToCommit()
+	vote := voteSet.GetByIndex(3)
+	v := vote.ToProto()
+	require.NoError(t, vals[3].SignVote("CentaurusA", v))
+	vote.Signature = v.Signature
+	commit.Signatures[3] = vote.CommitSig()
+	err = commit.VerifyAll(chainID, valSet)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "wrong signature (#3)")
+	}
+}
+
 func TestValidatorSet_VerifyCommitLight_ReturnsAsSoonAsMajOfVotingPowerSignedIffNotAllSigs(t *testing.T) {
 	var (
 		chainID = "test_chain_id"
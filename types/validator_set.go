@@ -693,6 +693,46 @@ func (vals *ValidatorSet) UpdateWithChangeSet(changes []*Validator) error {
 	return vals.updateWithChangeSet(changes, true)
 }
 
+// PowerShift returns the fraction of voting power that changed hands between
+// vals and other, as a value between 0 (same voting power distribution) and 1
+// (no validator in common). It is computed as the larger of the voting power
+// gained and the voting power lost by the validators present in either set,
+// divided by the larger of the total voting powers of both sets. For example,
+// moving 1/3 of the power of a set to new validators, or removing validators
+// holding 1/3 of it, yields 1/3.
+//
+// A shift above 1/3 between two consecutive heights means that a light client
+// trusting the old set can no longer rely on it to verify the new one.
+func (vals *ValidatorSet) PowerShift(other *ValidatorSet) float64 {
+	var total int64
+	changes := make(map[string]int64)
+	if !vals.IsNilOrEmpty() {
+		total = vals.TotalVotingPower()
+		for _, val := range vals.Validators {
+			changes[string(val.Address)] -= val.VotingPower
+		}
+	}
+	if !other.IsNilOrEmpty() {
+		total = max(total, other.TotalVotingPower())
+		for _, val := range other.Validators {
+			changes[string(val.Address)] += val.VotingPower
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+
+	var gained, lost int64
+	for _, change := range changes {
+		if change > 0 {
+			gained += change
+		} else {
+			lost -= change
+		}
+	}
+	return float64(max(gained, lost)) / float64(total)
+}
+
 // VerifyCommit verifies +2/3 of the set had signed the given commit and all
 // other signatures are valid
 func (vals *ValidatorSet) VerifyCommit(chainID string, blockID BlockID,
@@ -733,6 +733,71 @@ func TestSafeSubClip(t *testing.T) {
 
 //-------------------------------------------------------------------
 
+func TestValidatorSetPowerShift(t *testing.T) {
+	vals := NewValidatorSet([]*Validator{
+		newValidator([]byte("a"), 200),
+		newValidator([]byte("b"), 100),
+		newValidator([]byte("c"), 100),
+		newValidator([]byte("d"), 200),
+	})
+
+	testCases := []struct {
+		name     string
+		other    *ValidatorSet
+		expected float64
+	}{
+		{"same set", vals.Copy(), 0},
+		{"no validator in common", NewValidatorSet([]*Validator{
+			newValidator([]byte("e"), 600),
+		}), 1},
+		{"1/3 of the power moved to a new validator", NewValidatorSet([]*Validator{
+			newValidator([]byte("a"), 200),
+			newValidator([]byte("d"), 200),
+			newValidator([]byte("e"), 200),
+		}), 1.0 / 3},
+		{"power moved between existing validators", NewValidatorSet([]*Validator{
+			newValidator([]byte("a"), 100),
+			newValidator([]byte("b"), 100),
+			newValidator([]byte("c"), 100),
+			newValidator([]byte("d"), 300),
+		}), 1.0 / 6},
+		{"full replacement with less power", NewValidatorSet([]*Validator{
+			newValidator([]byte("e"), 100),
+			newValidator([]byte("f"), 200),
+		}), 1},
+		{"pure removal", NewValidatorSet([]*Validator{
+			newValidator([]byte("a"), 200),
+			newValidator([]byte("b"), 100),
+			newValidator([]byte("d"), 200),
+		}), 1.0 / 6},
+		{"removal of 1/3 of the power", NewValidatorSet([]*Validator{
+			newValidator([]byte("a"), 200),
+			newValidator([]byte("d"), 200),
+		}), 1.0 / 3},
+		{"validator added", NewValidatorSet([]*Validator{
+			newValidator([]byte("a"), 200),
+			newValidator([]byte("b"), 100),
+			newValidator([]byte("c"), 100),
+			newValidator([]byte("d"), 200),
+			newValidator([]byte("e"), 100),
+		}), 1.0 / 7},
+		{"empty set", NewValidatorSet(nil), 1},
+		{"nil set", nil, 1},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			assert.InDelta(t, tc.expected, vals.PowerShift(tc.other), 1e-9)
+			if tc.other != nil {
+				assert.InDelta(t, tc.expected, tc.other.PowerShift(vals), 1e-9)
+			}
+		})
+	}
+
+	var empty *ValidatorSet
+	assert.Zero(t, empty.PowerShift(nil))
+}
+
 func TestEmptySet(t *testing.T) {
 	var valList []*Validator
 	valSet := NewValidatorSet(valList)
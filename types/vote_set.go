@@ -663,7 +663,7 @@ func (voteSet *VoteSet) MakeExtendedCommit(ap ABCIParams) *ExtendedCommit {
 		BlockID:            *voteSet.maj23,
 		ExtendedSignatures: sigs,
 	}
-	if err := ec.EnsureExtensions(ap.VoteExtensionsEnabled(ec.Height)); err != nil {
+	if err := ec.EnsureExtensions(VoteExtensionsEnabled(ec.Height, ap.VoteExtensionsEnableHeight)); err != nil {
 		panic(fmt.Errorf("problem with vote extension data when making extended commit of height %d; %w",
 			ec.Height, err))
 	}